@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is the maximum length of the Event message.
+const MaxMessageLength = 39000
+
+// SetOriginRevision sets the source artifact origin revision in Metadata.
+func (in *Event) SetOriginRevision(revision string) {
+	if in.Metadata == nil {
+		in.Metadata = make(map[string]string)
+	}
+	in.Metadata[MetaOriginRevisionKey] = revision
+}
+
+// GetOriginRevision returns the source artifact origin revision from Metadata.
+func (in *Event) GetOriginRevision() (string, bool) {
+	r, ok := in.Metadata[MetaOriginRevisionKey]
+	return r, ok
+}
+
+// Fingerprint returns a stable hash in the format `sha256:<hex>` of the
+// involved object UID, the reason, the message and the metadata of the event.
+// It can be used by receivers to deduplicate events, as it does not depend
+// on the timestamp or the reporting instance.
+func (in *Event) Fingerprint() string {
+	h := sha256.New()
+	writeField(h, string(in.InvolvedObject.UID))
+	writeField(h, in.Reason)
+	writeField(h, in.Message)
+
+	keys := make([]string, 0, len(in.Metadata))
+	for k := range in.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeField(h, k)
+		writeField(h, in.Metadata[k])
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
+
+// writeField writes the length-prefixed value to the hash, so that
+// distinct sequences of fields never produce the same input.
+func writeField(h hash.Hash, v string) {
+	var l [8]byte
+	binary.BigEndian.PutUint64(l[:], uint64(len(v)))
+	h.Write(l[:])
+	h.Write([]byte(v))
+}
+
+// Validate returns an error if the required fields of the event are not set
+// or if the severity or message are invalid. It should be called before
+// serializing the event, as receivers reject invalid payloads.
+func (in *Event) Validate() error {
+	var errs []error
+	if in.InvolvedObject.Kind == "" {
+		errs = append(errs, errors.New("involvedObject.kind is required"))
+	}
+	if in.InvolvedObject.Name == "" {
+		errs = append(errs, errors.New("involvedObject.name is required"))
+	}
+	if in.InvolvedObject.Namespace == "" {
+		errs = append(errs, errors.New("involvedObject.namespace is required"))
+	}
+	switch in.Severity {
+	case EventSeverityTrace, EventSeverityInfo, EventSeverityError:
+	case "":
+		errs = append(errs, errors.New("severity is required"))
+	default:
+		errs = append(errs, fmt.Errorf("severity '%s' must be one of %s, %s or %s",
+			in.Severity, EventSeverityTrace, EventSeverityInfo, EventSeverityError))
+	}
+	if in.Timestamp.IsZero() {
+		errs = append(errs, errors.New("timestamp is required"))
+	}
+	if in.Message == "" {
+		errs = append(errs, errors.New("message is required"))
+	} else if n := utf8.RuneCountInString(in.Message); n > MaxMessageLength {
+		errs = append(errs, fmt.Errorf("message length %d exceeds the maximum of %d characters", n, MaxMessageLength))
+	}
+	if in.Reason == "" {
+		errs = append(errs, errors.New("reason is required"))
+	}
+	if in.ReportingController == "" {
+		errs = append(errs, errors.New("reportingController is required"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid event: %w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testEvent() *Event {
+	e := &Event{
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "kustomize.toolkit.fluxcd.io/v1",
+			Kind:       "Kustomization",
+			Namespace:  "flux-system",
+			Name:       "apps",
+			UID:        "8b0c1f2e-3d4a-4b5c-9d6e-7f8091a2b3c4",
+		},
+		Severity:            EventSeverityInfo,
+		Timestamp:           metav1.NewTime(time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)),
+		Message:             "Deployment/apps/web configured",
+		Reason:              "ReconciliationSucceeded",
+		Metadata:            map[string]string{MetaRevisionKey: "main@sha1:6f4b3d1a9c2e7a10"},
+		ReportingController: "kustomize-controller",
+		ReportingInstance:   "kustomize-controller-7d9c5b8f4-x2k9p",
+	}
+	e.SetOriginRevision("main@sha1:6f4b3d1a9c2e7a10")
+	return e
+}
+
+func testMinimalEvent() *Event {
+	return &Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "GitRepository",
+			Namespace: "flux-system",
+			Name:      "podinfo",
+		},
+		Severity:            EventSeverityError,
+		Timestamp:           metav1.NewTime(time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)),
+		Message:             "authentication required",
+		Reason:              "GitOperationFailed",
+		ReportingController: "source-controller",
+	}
+}
+
+func TestEvent_GoldenJSON(t *testing.T) {
+	tests := []struct {
+		golden string
+		event  *Event
+	}{
+		{golden: "event.json", event: testEvent()},
+		{golden: "event_minimal.json", event: testMinimalEvent()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			want, err := os.ReadFile(filepath.Join("testdata", tt.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.event.Validate(); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+
+			got, err := json.Marshal(tt.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, want); err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != compact.String() {
+				t.Errorf("marshalled event does not match %s:\ngot:  %s\nwant: %s", tt.golden, got, compact.String())
+			}
+
+			var decoded Event
+			if err := json.Unmarshal(want, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !decoded.Timestamp.Equal(&tt.event.Timestamp) {
+				t.Errorf("unmarshalled %s timestamp does not match: got %s, want %s", tt.golden, decoded.Timestamp, tt.event.Timestamp)
+			}
+			// The timestamp is decoded in the local time zone.
+			decoded.Timestamp = tt.event.Timestamp
+			if !reflect.DeepEqual(&decoded, tt.event) {
+				t.Errorf("unmarshalled %s does not match:\ngot:  %+v\nwant: %+v", tt.golden, decoded, *tt.event)
+			}
+			if decoded.Fingerprint() != tt.event.Fingerprint() {
+				t.Errorf("fingerprint of unmarshalled %s does not match", tt.golden)
+			}
+		})
+	}
+}
+
+func TestEvent_OriginRevision(t *testing.T) {
+	e := &Event{}
+	if _, ok := e.GetOriginRevision(); ok {
+		t.Error("expected no origin revision")
+	}
+
+	e.SetOriginRevision("v1.0.0@sha256:0123")
+	if r, ok := e.GetOriginRevision(); !ok || r != "v1.0.0@sha256:0123" {
+		t.Errorf("expected origin revision, got %q", r)
+	}
+	if r, ok := e.GetRevision(); !ok || r != "v1.0.0@sha256:0123" {
+		t.Errorf("expected GetRevision to fall back to the origin revision, got %q", r)
+	}
+}
+
+func TestEvent_Fingerprint(t *testing.T) {
+	e := testEvent()
+	fp := e.Fingerprint()
+
+	// The fingerprint is stable across versions.
+	const want = "sha256:e51fb830da7db9d5b12c8266436bb6caf14a254423c168b52588bd495b189af0"
+	if fp != want {
+		t.Errorf("expected fingerprint %s, got %s", want, fp)
+	}
+
+	// Fields that vary between emissions of the same event are ignored.
+	same := testEvent()
+	same.Timestamp = metav1.NewTime(same.Timestamp.Add(time.Hour))
+	same.ReportingInstance = "kustomize-controller-7d9c5b8f4-abcde"
+	same.Severity = EventSeverityTrace
+	if got := same.Fingerprint(); got != fp {
+		t.Errorf("expected fingerprint %s, got %s", fp, got)
+	}
+
+	for name, mutate := range map[string]func(e *Event){
+		"uid":            func(e *Event) { e.InvolvedObject.UID = "other" },
+		"reason":         func(e *Event) { e.Reason = "ReconciliationFailed" },
+		"message":        func(e *Event) { e.Message += "." },
+		"metadata value": func(e *Event) { e.Metadata[MetaRevisionKey] = "main@sha1:9c2e7a10" },
+		"metadata key":   func(e *Event) { e.Metadata[MetaTokenKey] = "" },
+		"field boundary": func(e *Event) {
+			e.Reason = "ReconciliationSucceededDeployment/apps/web"
+			e.Message = " configured"
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			changed := testEvent()
+			mutate(changed)
+			if changed.Fingerprint() == fp {
+				t.Errorf("expected fingerprint to change")
+			}
+		})
+	}
+}
+
+func TestEvent_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(e *Event)
+		wantErr []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(e *Event) {},
+		},
+		{
+			name: "missing involved object",
+			mutate: func(e *Event) {
+				e.InvolvedObject = corev1.ObjectReference{}
+			},
+			wantErr: []string{
+				"involvedObject.kind is required",
+				"involvedObject.name is required",
+				"involvedObject.namespace is required",
+			},
+		},
+		{
+			name:    "missing severity",
+			mutate:  func(e *Event) { e.Severity = "" },
+			wantErr: []string{"severity is required"},
+		},
+		{
+			name:    "invalid severity",
+			mutate:  func(e *Event) { e.Severity = "warning" },
+			wantErr: []string{"severity 'warning' must be one of trace, info or error"},
+		},
+		{
+			name:    "missing timestamp",
+			mutate:  func(e *Event) { e.Timestamp = metav1.Time{} },
+			wantErr: []string{"timestamp is required"},
+		},
+		{
+			name:    "missing message",
+			mutate:  func(e *Event) { e.Message = "" },
+			wantErr: []string{"message is required"},
+		},
+		{
+			name:    "message too long",
+			mutate:  func(e *Event) { e.Message = strings.Repeat("a", MaxMessageLength+1) },
+			wantErr: []string{"message length 39001 exceeds the maximum of 39000 characters"},
+		},
+		{
+			name:   "message at the maximum length in characters",
+			mutate: func(e *Event) { e.Message = strings.Repeat("é", MaxMessageLength) },
+		},
+		{
+			name: "missing reason and reporting controller",
+			mutate: func(e *Event) {
+				e.Reason = ""
+				e.ReportingController = ""
+			},
+			wantErr: []string{"reason is required", "reportingController is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := testEvent()
+			tt.mutate(e)
+
+			err := e.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.HasPrefix(err.Error(), "invalid event: ") {
+				t.Errorf("expected error to start with 'invalid event: ', got %q", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got %q", want, err)
+				}
+			}
+		})
+	}
+}
@@ -17,9 +17,29 @@ limitations under the License.
 package meta
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// digestRegexp matches the '<algorithm>:<checksum>' digests, as validated
+// by the pattern of the Artifact.Digest field.
+var digestRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9=_-]+$`)
+
+// digestLengths are the lengths of the hex-encoded checksums of the
+// registered digest algorithms.
+var digestLengths = map[string]int{
+	"sha1":   40,
+	"sha256": 64,
+	"sha384": 96,
+	"sha512": 128,
+	"blake3": 64,
+}
+
 // Artifact represents the output of a Source reconciliation.
 type Artifact struct {
 	// Digest is the digest of the file in the form of '<algorithm>:<checksum>'.
@@ -58,13 +78,78 @@ type Artifact struct {
 	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
+// ArtifactRef is a reference to a specific Artifact of a Source, e.g. the
+// Artifact last applied by a consumer of the Source.
+type ArtifactRef struct {
+	// Revision is the Revision of the Artifact.
+	// +required
+	Revision string `json:"revision"`
+
+	// Digest is the Digest of the Artifact.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// Ref returns the reference to the Artifact.
+func (in *Artifact) Ref() ArtifactRef {
+	return ArtifactRef{Revision: in.Revision, Digest: in.Digest}
+}
+
+// Matches returns if the given Artifact has the Revision and, if set, the
+// Digest of the reference.
+func (in ArtifactRef) Matches(artifact *Artifact) bool {
+	if !artifact.HasRevision(in.Revision) {
+		return false
+	}
+	return in.Digest == "" || artifact.HasDigest(in.Digest)
+}
+
+// Validate returns an error if the Digest of the Artifact is not in the form
+// of '<algorithm>:<checksum>', with a checksum of the expected length for
+// the registered algorithms, or if the URL is not an absolute HTTP address.
+func (in *Artifact) Validate() error {
+	if err := validateDigest(in.Digest); err != nil {
+		return fmt.Errorf("invalid artifact digest '%s': %w", in.Digest, err)
+	}
+	u, err := url.Parse(in.URL)
+	if err != nil {
+		return fmt.Errorf("invalid artifact URL '%s': %w", in.URL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid artifact URL '%s': must be an absolute HTTP address", in.URL)
+	}
+	return nil
+}
+
+// validateDigest returns an error if the digest is not in the form of
+// '<algorithm>:<checksum>'.
+func validateDigest(digest string) error {
+	if !digestRegexp.MatchString(digest) {
+		return fmt.Errorf("must be in the form of '<algorithm>:<checksum>'")
+	}
+	algorithm, checksum, _ := strings.Cut(digest, ":")
+	length, ok := digestLengths[algorithm]
+	if !ok {
+		return nil
+	}
+	if len(checksum) != length {
+		return fmt.Errorf("%s checksum must be %d characters long", algorithm, length)
+	}
+	if strings.Trim(checksum, "0123456789abcdef") != "" {
+		return fmt.Errorf("%s checksum must be lowercase hex-encoded", algorithm)
+	}
+	return nil
+}
+
 // HasRevision returns if the given revision matches the current Revision of
-// the Artifact.
+// the Artifact. The revisions in the legacy formats of the Source APIs, e.g.
+// 'main/<sha1>' or '<sha256>', match the ones in the current formats, e.g.
+// 'main@sha1:<sha1>' or 'sha256:<sha256>'.
 func (in *Artifact) HasRevision(revision string) bool {
 	if in == nil {
 		return false
 	}
-	return in.Revision == revision
+	return in.Revision == revision || transformLegacyRevision(in.Revision) == transformLegacyRevision(revision)
 }
 
 // HasDigest returns if the given digest matches the current Digest of the
@@ -75,3 +160,66 @@ func (in *Artifact) HasDigest(digest string) bool {
 	}
 	return in.Digest == digest
 }
+
+// transformLegacyRevision returns the revision in the current format of the
+// Source APIs, '[<named pointer>@]<algorithm>:<checksum>', if it is in a
+// legacy format, i.e.:
+//
+//   - '<named pointer>/<checksum>', e.g. 'main/<sha1>' or 'latest/<sha256>'
+//   - 'HEAD/<sha1>'
+//   - '<checksum>', e.g. '<sha256>' for the Bucket API
+//
+// The algorithm of the checksum is inferred from its length. Other
+// revisions, e.g. a Helm chart version, are returned as is.
+func transformLegacyRevision(revision string) string {
+	if revision == "" || strings.Contains(revision, ":") {
+		return revision
+	}
+
+	pointer, checksum := "", revision
+	if i := strings.LastIndex(revision, "/"); i != -1 {
+		pointer, checksum = revision[:i], revision[i+1:]
+	}
+	var algorithm string
+	switch len(checksum) {
+	case 40:
+		algorithm = "sha1"
+	case 64:
+		algorithm = "sha256"
+	}
+	switch {
+	case algorithm == "" || strings.Trim(checksum, "0123456789abcdef") != "":
+		return revision
+	case pointer == "" || pointer == "HEAD":
+		return algorithm + ":" + checksum
+	default:
+		return pointer + "@" + algorithm + ":" + checksum
+	}
+}
+
+// ConvertArtifact returns the Artifact of the given Artifact of a Source
+// API, e.g. the Artifact of the v1beta2 GitRepository API, or a pointer to
+// it. The fields are mapped by their JSON names, and the legacy Checksum
+// field is converted to the Digest in the 'sha256:<checksum>' form when the
+// Digest is not set. It returns nil if the given Artifact is nil.
+func ConvertArtifact(artifact any) (*Artifact, error) {
+	data, err := json.Marshal(artifact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert artifact: %w", err)
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+
+	var out struct {
+		Artifact
+		Checksum string `json:"checksum,omitempty"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to convert artifact: %w", err)
+	}
+	if out.Digest == "" && out.Checksum != "" {
+		out.Digest = "sha256:" + out.Checksum
+	}
+	return &out.Artifact, nil
+}
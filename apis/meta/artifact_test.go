@@ -0,0 +1,241 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+)
+
+const (
+	testSHA1   = "5394cb7f48332b2de7c17dd8b8384bbc84b7e738"
+	testSHA256 = "c8b2a4a2d4a8f6bde0a2b4a4f8e7a0c5b5c2a3d6e4f5a6b7c8d9e0f1a2b3c4d5"
+)
+
+func TestArtifact_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		digest string
+		url    string
+		err    string
+	}{
+		{
+			name:   "valid sha256 digest",
+			digest: "sha256:" + testSHA256,
+		},
+		{
+			name:   "valid sha512 digest",
+			digest: "sha512:" + strings.Repeat("ab", 64),
+		},
+		{
+			name:   "unregistered algorithm",
+			digest: "xxh3+64:ABC_123=",
+		},
+		{
+			name:   "empty digest",
+			digest: "",
+			err:    "invalid artifact digest '': must be in the form of '<algorithm>:<checksum>'",
+		},
+		{
+			name:   "missing algorithm",
+			digest: testSHA256,
+			err:    "must be in the form of '<algorithm>:<checksum>'",
+		},
+		{
+			name:   "empty checksum",
+			digest: "sha256:",
+			err:    "must be in the form of '<algorithm>:<checksum>'",
+		},
+		{
+			name:   "uppercase algorithm",
+			digest: "SHA256:" + testSHA256,
+			err:    "must be in the form of '<algorithm>:<checksum>'",
+		},
+		{
+			name:   "trailing separator in algorithm",
+			digest: "sha256+:" + testSHA256,
+			err:    "must be in the form of '<algorithm>:<checksum>'",
+		},
+		{
+			name:   "short sha256 checksum",
+			digest: "sha256:" + testSHA256[:63],
+			err:    "sha256 checksum must be 64 characters long",
+		},
+		{
+			name:   "uppercase sha256 checksum",
+			digest: "sha256:" + strings.ToUpper(testSHA256),
+			err:    "sha256 checksum must be lowercase hex-encoded",
+		},
+		{
+			name:   "non-hex sha1 checksum",
+			digest: "sha1:" + strings.Repeat("z", 40),
+			err:    "sha1 checksum must be lowercase hex-encoded",
+		},
+		{
+			name:   "relative URL",
+			digest: "sha256:" + testSHA256,
+			url:    "/gitrepository/default/podinfo/latest.tar.gz",
+			err:    "must be an absolute HTTP address",
+		},
+		{
+			name:   "URL without host",
+			digest: "sha256:" + testSHA256,
+			url:    "http:///latest.tar.gz",
+			err:    "must be an absolute HTTP address",
+		},
+		{
+			name:   "URL with another scheme",
+			digest: "sha256:" + testSHA256,
+			url:    "file:///data/latest.tar.gz",
+			err:    "must be an absolute HTTP address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			artifact := &meta.Artifact{
+				Digest: tt.digest,
+				URL:    "http://source-controller.flux-system.svc/gitrepository/default/podinfo/latest.tar.gz",
+			}
+			if tt.url != "" {
+				artifact.URL = tt.url
+			}
+			err := artifact.Validate()
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestArtifact_HasRevision(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		revision string
+		want     bool
+	}{
+		{name: "same revision", current: "main@sha1:" + testSHA1, revision: "main@sha1:" + testSHA1, want: true},
+		{name: "legacy Git branch", current: "main@sha1:" + testSHA1, revision: "main/" + testSHA1, want: true},
+		{name: "legacy Git branch with slash", current: "feature/x@sha1:" + testSHA1, revision: "feature/x/" + testSHA1, want: true},
+		{name: "legacy Git HEAD", current: "sha1:" + testSHA1, revision: "HEAD/" + testSHA1, want: true},
+		{name: "legacy OCI tag", current: "latest@sha256:" + testSHA256, revision: "latest/" + testSHA256, want: true},
+		{name: "legacy Bucket checksum", current: "sha256:" + testSHA256, revision: testSHA256, want: true},
+		{name: "legacy current revision", current: "main/" + testSHA1, revision: "main@sha1:" + testSHA1, want: true},
+		{name: "Helm chart version", current: "6.0.0", revision: "6.0.0", want: true},
+		{name: "other branch", current: "main@sha1:" + testSHA1, revision: "dev/" + testSHA1},
+		{name: "other commit", current: "main@sha1:" + testSHA1, revision: "main/" + strings.Repeat("a", 40)},
+		{name: "other algorithm", current: "main@sha256:" + testSHA1, revision: "main/" + testSHA1},
+		{name: "uppercase legacy checksum", current: "sha1:" + testSHA1, revision: strings.ToUpper(testSHA1)},
+		{name: "empty revision", current: "main@sha1:" + testSHA1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			artifact := &meta.Artifact{Revision: tt.current}
+			g.Expect(artifact.HasRevision(tt.revision)).To(Equal(tt.want))
+		})
+	}
+
+	var artifact *meta.Artifact
+	NewWithT(t).Expect(artifact.HasRevision("")).To(BeFalse())
+}
+
+func TestArtifactRef_Matches(t *testing.T) {
+	g := NewWithT(t)
+
+	artifact := &meta.Artifact{Revision: "main@sha1:" + testSHA1, Digest: "sha256:" + testSHA256}
+	g.Expect(artifact.Ref()).To(Equal(meta.ArtifactRef{Revision: artifact.Revision, Digest: artifact.Digest}))
+	g.Expect(artifact.Ref().Matches(artifact)).To(BeTrue())
+	g.Expect(meta.ArtifactRef{Revision: "main/" + testSHA1}.Matches(artifact)).To(BeTrue())
+	g.Expect(meta.ArtifactRef{Revision: artifact.Revision, Digest: "sha256:" + strings.Repeat("0", 64)}.Matches(artifact)).To(BeFalse())
+	g.Expect(artifact.Ref().Matches(nil)).To(BeFalse())
+}
+
+func TestConvertArtifact(t *testing.T) {
+	// legacyArtifact mirrors the Artifact of the v1beta2 source APIs.
+	type legacyArtifact struct {
+		Path           string            `json:"path"`
+		URL            string            `json:"url"`
+		Revision       string            `json:"revision"`
+		Checksum       string            `json:"checksum,omitempty"`
+		Digest         string            `json:"digest,omitempty"`
+		LastUpdateTime metav1.Time       `json:"lastUpdateTime"`
+		Size           *int64            `json:"size,omitempty"`
+		Metadata       map[string]string `json:"metadata,omitempty"`
+	}
+
+	size := int64(1024)
+	// The times are decoded in the local time zone.
+	now := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC).Local())
+	tests := []struct {
+		name string
+		in   any
+		want *meta.Artifact
+	}{
+		{
+			name: "legacy checksum",
+			in: &legacyArtifact{
+				Path:           "gitrepository/default/podinfo/latest.tar.gz",
+				URL:            "http://source-controller/gitrepository/default/podinfo/latest.tar.gz",
+				Revision:       "main/" + testSHA1,
+				Checksum:       testSHA256,
+				LastUpdateTime: now,
+				Size:           &size,
+				Metadata:       map[string]string{"org.opencontainers.image.source": "podinfo"},
+			},
+			want: &meta.Artifact{
+				Path:           "gitrepository/default/podinfo/latest.tar.gz",
+				URL:            "http://source-controller/gitrepository/default/podinfo/latest.tar.gz",
+				Revision:       "main/" + testSHA1,
+				Digest:         "sha256:" + testSHA256,
+				LastUpdateTime: now,
+				Size:           &size,
+				Metadata:       map[string]string{"org.opencontainers.image.source": "podinfo"},
+			},
+		},
+		{
+			name: "digest takes precedence over checksum",
+			in:   legacyArtifact{Checksum: testSHA256, Digest: "sha512:" + strings.Repeat("ab", 64), LastUpdateTime: now},
+			want: &meta.Artifact{Digest: "sha512:" + strings.Repeat("ab", 64), LastUpdateTime: now},
+		},
+		{
+			name: "nil artifact",
+			in:   (*legacyArtifact)(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := meta.ConvertArtifact(tt.in)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
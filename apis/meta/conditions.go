@@ -149,6 +149,10 @@ const (
 	// the reconciliation failed.
 	ReconciliationFailedReason string = "ReconciliationFailed"
 
+	// ReconciliationTimeoutReason represents the fact that
+	// the reconciliation did not complete within the configured timeout.
+	ReconciliationTimeoutReason string = "ReconciliationTimeout"
+
 	// InvalidCELExpressionReason represents the fact that a CEL expression
 	// in the configuration is invalid.
 	InvalidCELExpressionReason string = "InvalidCELExpression"
@@ -131,6 +131,11 @@ const (
 	// name of the Kubernetes ServiceAccount in the same namespace that should be used
 	// for authentication.
 	KubeConfigKeyServiceAccountName = "serviceAccountName"
+	// KubeConfigKeyClientCertSecretName is the key in the ConfigMap that contains the
+	// name of the Secret in the same namespace holding the PEM-encoded client certificate
+	// and key for authenticating with the Kubernetes API server, in the `tls.crt` and
+	// `tls.key` keys.
+	KubeConfigKeyClientCertSecretName = "clientCertSecretName"
 )
 
 // KubeConfigReference contains enough information build a kubeconfig
@@ -165,6 +170,12 @@ type KubeConfigReference struct {
 	//    ServiceAccount in the same namespace that should be used
 	//    for authentication. If not specified, the controller
 	//    ServiceAccount will be used.
+	// - `clientCertSecretName`: the optional name of a Secret in the same
+	//    namespace with the PEM-encoded client certificate and key in the
+	//    `tls.crt` and `tls.key` keys, for authenticating with mTLS instead
+	//    of a ServiceAccount token. Supported only for the `generic`
+	//    provider. Mutually exclusive with `serviceAccountName`, unless
+	//    both are allowed by the controller.
 	//
 	// Mutually exclusive with SecretRef.
 	//
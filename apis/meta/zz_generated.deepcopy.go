@@ -50,6 +50,21 @@ func (in *Artifact) DeepCopy() *Artifact {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactRef) DeepCopyInto(out *ArtifactRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactRef.
+func (in *ArtifactRef) DeepCopy() *ArtifactRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DependencyReference) DeepCopyInto(out *DependencyReference) {
 	*out = *in
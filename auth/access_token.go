@@ -30,16 +30,28 @@ import (
 )
 
 // GetAccessToken returns an access token for accessing resources in the given cloud provider.
-func GetAccessToken(ctx context.Context, provider Provider, opts ...Option) (Token, error) {
+// The request is recorded with the callback of WithAuditCallback, if set.
+func GetAccessToken(ctx context.Context, provider Provider, opts ...Option) (token Token, err error) {
 
 	var o Options
 	o.Apply(opts...)
 
+	event := AuditEvent{
+		Provider:                provider.GetName(),
+		ServiceAccountName:      o.ServiceAccountName,
+		ServiceAccountNamespace: o.ServiceAccountNamespace,
+		Audiences:               o.Audiences,
+	}
+	if !o.ShouldGetServiceAccountToken() {
+		event.ServiceAccountName, event.ServiceAccountNamespace = "", ""
+	}
+	defer func() { o.audit(event, err) }()
+
 	// Initialize access token fetcher for controller.
 	newAccessToken := func() (Token, error) {
 		token, err := provider.NewControllerToken(ctx, opts...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create provider access token for the controller: %w", err)
+			return nil, fmt.Errorf("failed to create provider access token for the controller: %w", ClassifyError(err))
 		}
 		return token, nil
 	}
@@ -49,6 +61,13 @@ func GetAccessToken(ctx context.Context, provider Provider, opts ...Option) (Tok
 	var providerIdentity string
 	var audiences []string
 	if o.ShouldGetServiceAccountToken() {
+		// Check the feature gate for object-level workload identity before
+		// looking up the cache, so that the tokens issued for a call which
+		// was allowed are not returned to a call which is not.
+		if !isObjectLevelWorkloadIdentityAllowed(&o, o.ServiceAccountNamespace) {
+			return nil, NewInvalidConfigurationError(&ErrObjectLevelWIDisabled{Namespace: o.ServiceAccountNamespace})
+		}
+
 		// Fetch service account details.
 		var err error
 		saRef := client.ObjectKey{
@@ -60,14 +79,11 @@ func GetAccessToken(ctx context.Context, provider Provider, opts ...Option) (Tok
 		if err != nil {
 			return nil, err
 		}
+		event.ServiceAccountName = serviceAccount.Name
+		event.Audiences = audiences
 
 		// Update the function to create an access token using the service account.
 		newAccessToken = func() (Token, error) {
-			// Check the feature gate for object-level workload identity.
-			if !IsObjectLevelWorkloadIdentityEnabled() {
-				return nil, ErrObjectLevelWorkloadIdentityNotEnabled
-			}
-
 			// Issue Kubernetes OIDC token for the service account.
 			tokenReq := &authnv1.TokenRequest{
 				Spec: authnv1.TokenRequestSpec{
@@ -76,7 +92,7 @@ func GetAccessToken(ctx context.Context, provider Provider, opts ...Option) (Tok
 			}
 			if err := o.Client.SubResource("token").Create(ctx, serviceAccount, tokenReq); err != nil {
 				return nil, fmt.Errorf("failed to create kubernetes token for service account '%s/%s': %w",
-					serviceAccount.Namespace, serviceAccount.Name, err)
+					serviceAccount.Namespace, serviceAccount.Name, ClassifyError(err))
 			}
 			oidcToken := tokenReq.Status.Token
 
@@ -84,7 +100,7 @@ func GetAccessToken(ctx context.Context, provider Provider, opts ...Option) (Tok
 			token, err := provider.NewTokenForServiceAccount(ctx, oidcToken, *serviceAccount, opts...)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create provider access token for service account '%s/%s': %w",
-					serviceAccount.Namespace, serviceAccount.Name, err)
+					serviceAccount.Namespace, serviceAccount.Name, ClassifyError(err))
 			}
 
 			return token, nil
@@ -97,7 +113,7 @@ func GetAccessToken(ctx context.Context, provider Provider, opts ...Option) (Tok
 	}
 
 	// Build cache key.
-	cacheKey := buildAccessTokenCacheKey(provider, audiences, providerIdentity, serviceAccount, opts...)
+	cacheKey := buildAccessTokenCacheKey(provider.GetName(), audiences, providerIdentity, serviceAccount, opts...)
 
 	// Build involved object details.
 	kind := o.InvolvedObject.Kind
@@ -106,14 +122,15 @@ func GetAccessToken(ctx context.Context, provider Provider, opts ...Option) (Tok
 	operation := o.InvolvedObject.Operation
 
 	// Get token from cache.
-	token, _, err := o.Cache.GetOrSet(ctx, cacheKey, func(ctx context.Context) (cache.Token, error) {
+	cached, hit, err := o.Cache.GetOrSet(ctx, cacheKey, func(ctx context.Context) (cache.Token, error) {
 		return newAccessToken()
-	}, cache.WithInvolvedObject(kind, name, namespace, operation))
+	}, cache.WithInvolvedObject(kind, name, namespace, operation), cacheMetadata(provider, serviceAccount))
 	if err != nil {
 		return nil, err
 	}
+	event.CacheHit = hit
 
-	return token, nil
+	return cached, nil
 }
 
 func getServiceAccountAndProviderInfo(ctx context.Context, provider Provider, client client.Client,
@@ -137,10 +154,10 @@ func getServiceAccountAndProviderInfo(ctx context.Context, provider Provider, cl
 	if err := client.Get(ctx, key, &serviceAccount); err != nil {
 		if errors.IsNotFound(err) && setDefaultSA {
 			return nil, nil, "", fmt.Errorf("failed to get service account '%s': %w",
-				key, ErrDefaultServiceAccountNotFound)
+				key, NewInvalidConfigurationError(ErrDefaultServiceAccountNotFound))
 		}
 		return nil, nil, "", fmt.Errorf("failed to get service account '%s': %w",
-			key, err)
+			key, ClassifyError(err))
 	}
 
 	// Get provider audience.
@@ -149,7 +166,7 @@ func getServiceAccountAndProviderInfo(ctx context.Context, provider Provider, cl
 		var err error
 		audiences, err = provider.GetAudiences(ctx, serviceAccount)
 		if err != nil {
-			return nil, nil, "", fmt.Errorf("failed to get provider audience: %w", err)
+			return nil, nil, "", fmt.Errorf("failed to get provider audience: %w", ClassifyError(err))
 		}
 	}
 
@@ -157,13 +174,13 @@ func getServiceAccountAndProviderInfo(ctx context.Context, provider Provider, cl
 	providerIdentity, err := provider.GetIdentity(serviceAccount)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("failed to get provider identity from service account '%s/%s' annotations: %w",
-			key.Namespace, key.Name, err)
+			key.Namespace, key.Name, NewInvalidConfigurationError(err))
 	}
 
 	return &serviceAccount, audiences, providerIdentity, nil
 }
 
-func buildAccessTokenCacheKey(provider Provider, audiences []string, providerIdentity string,
+func buildAccessTokenCacheKey(provider string, audiences []string, providerIdentity string,
 	serviceAccount *corev1.ServiceAccount, opts ...Option) string {
 
 	var o Options
@@ -171,7 +188,7 @@ func buildAccessTokenCacheKey(provider Provider, audiences []string, providerIde
 
 	var parts []string
 
-	parts = append(parts, fmt.Sprintf("provider=%s", provider.GetName()))
+	parts = append(parts, fmt.Sprintf("provider=%s", provider))
 
 	if len(audiences) == 0 {
 		audiences = o.Audiences
@@ -198,6 +215,10 @@ func buildAccessTokenCacheKey(provider Provider, audiences []string, providerIde
 		parts = append(parts, fmt.Sprintf("stsEndpoint=%s", o.STSEndpoint))
 	}
 
+	if o.AWSRegion != "" {
+		parts = append(parts, fmt.Sprintf("awsRegion=%s", o.AWSRegion))
+	}
+
 	if o.ProxyURL != nil {
 		parts = append(parts, fmt.Sprintf("proxyURL=%s", o.ProxyURL))
 	}
@@ -206,5 +227,13 @@ func buildAccessTokenCacheKey(provider Provider, audiences []string, providerIde
 		parts = append(parts, fmt.Sprintf("caData=%s", o.CAData))
 	}
 
+	if o.OIDCTokenFile != "" {
+		parts = append(parts, fmt.Sprintf("oidcTokenFile=%s", o.OIDCTokenFile))
+	}
+
+	if o.AzureCloud != nil {
+		parts = append(parts, fmt.Sprintf("azureAuthorityHost=%s", o.AzureCloud.ActiveDirectoryAuthorityHost))
+	}
+
 	return buildCacheKey(parts...)
 }
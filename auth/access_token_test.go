@@ -18,6 +18,7 @@ package auth_test
 
 import (
 	"context"
+	"errors"
 	"net/url"
 	"testing"
 	"time"
@@ -338,6 +339,44 @@ func TestGetAccessToken(t *testing.T) {
 			disableObjectLevel: true,
 			expectedErr:        "ObjectLevelWorkloadIdentity feature gate is not enabled",
 		},
+		{
+			name: "disabled object level workload identity with allowed namespace",
+			provider: &mockProvider{
+				returnName:           "mock-provider",
+				returnAccessToken:    &mockToken{token: "mock-access-token"},
+				paramAudiences:       []string{"audience1", "audience2"},
+				paramServiceAccount:  *defaultServiceAccount,
+				paramOIDCTokenClient: oidcClient,
+			},
+			opts: []auth.Option{
+				auth.WithClient(kubeClient),
+				auth.WithServiceAccountName(saRef.Name),
+				auth.WithServiceAccountNamespace(saRef.Namespace),
+				auth.WithAudiences("audience1", "audience2"),
+				auth.WithScopes("scope1", "scope2"),
+				auth.WithSTSRegion("us-east-1"),
+				auth.WithSTSEndpoint("https://sts.some-cloud.io"),
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.io:8080"}),
+				auth.WithCAData("ca-data"),
+				auth.WithAllowedServiceAccountNamespaces([]string{"tenant", saRef.Namespace}),
+			},
+			disableObjectLevel: true,
+			expectedToken:      &mockToken{token: "mock-access-token"},
+		},
+		{
+			name: "disabled object level workload identity with other allowed namespaces",
+			provider: &mockProvider{
+				returnName: "mock-provider",
+			},
+			opts: []auth.Option{
+				auth.WithClient(kubeClient),
+				auth.WithServiceAccountName(saRef.Name),
+				auth.WithServiceAccountNamespace(saRef.Namespace),
+				auth.WithAllowedServiceAccountNamespaces([]string{"tenant"}),
+			},
+			disableObjectLevel: true,
+			expectedErr:        "ObjectLevelWorkloadIdentity feature gate is not enabled and the namespace 'default' is not allowed",
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
@@ -359,6 +398,12 @@ func TestGetAccessToken(t *testing.T) {
 			if tt.expectedErr != "" {
 				g.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)))
 				g.Expect(token).To(BeNil())
+				if tt.disableObjectLevel {
+					var disabledErr *auth.ErrObjectLevelWIDisabled
+					g.Expect(errors.As(err, &disabledErr)).To(BeTrue())
+					g.Expect(err).To(MatchError(auth.ErrObjectLevelWorkloadIdentityNotEnabled))
+					g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				}
 			} else {
 				g.Expect(err).NotTo(HaveOccurred())
 				g.Expect(token).To(Equal(tt.expectedToken))
@@ -367,6 +412,28 @@ func TestGetAccessToken(t *testing.T) {
 	}
 }
 
+func TestGetAccessToken_ObjectLevelWorkloadIdentityDisabled(t *testing.T) {
+	g := NewWithT(t)
+
+	tokenCache, err := cache.NewTokenCache(1)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The gate is checked before the service account is looked up and before
+	// the cache, so neither the client nor the provider are used.
+	_, err = auth.GetAccessToken(context.Background(), &mockProvider{t: t}, []auth.Option{
+		auth.WithServiceAccountName("default"),
+		auth.WithServiceAccountNamespace("tenant-a"),
+		auth.WithAllowedServiceAccountNamespaces([]string{"tenant-b"}),
+		auth.WithCache(*tokenCache, cache.InvolvedObject{Kind: "test", Name: "test", Namespace: "tenant-a"}),
+	}...)
+
+	var disabledErr *auth.ErrObjectLevelWIDisabled
+	g.Expect(errors.As(err, &disabledErr)).To(BeTrue())
+	g.Expect(disabledErr.Namespace).To(Equal("tenant-a"))
+	g.Expect(err).To(MatchError(auth.ErrObjectLevelWorkloadIdentityNotEnabled))
+	g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+}
+
 // TestGetAccessToken_ControllerLevelAudienceCacheKey is a regression test for a
 // token cache collision: controller-level (no service account) access tokens are
 // also audience-scoped (e.g. SOPS Vault/OpenBao decryption and kubeconfig
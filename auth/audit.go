@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrorClass is the classification of the error of a failed token request
+// recorded in an AuditEvent.
+type ErrorClass string
+
+const (
+	// ErrorClassInvalidConfiguration is the class of the errors classified
+	// as ErrInvalidConfiguration.
+	ErrorClassInvalidConfiguration ErrorClass = "InvalidConfiguration"
+	// ErrorClassPermissionDenied is the class of the errors classified as
+	// ErrPermissionDenied.
+	ErrorClassPermissionDenied ErrorClass = "PermissionDenied"
+	// ErrorClassTransient is the class of the errors classified as
+	// ErrTransient.
+	ErrorClassTransient ErrorClass = "Transient"
+	// ErrorClassUnknown is the class of the errors which are not classified.
+	ErrorClassUnknown ErrorClass = "Unknown"
+)
+
+// CallerRef references the object a token is requested for, e.g. the
+// OCIRepository pulling the artifacts with the token.
+type CallerRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// String returns the reference in the form '<kind>/<namespace>/<name>', or
+// '<kind>/<name>' for cluster-scoped objects.
+func (r CallerRef) String() string {
+	if r.Namespace == "" {
+		return r.Kind + "/" + r.Name
+	}
+	return r.Kind + "/" + r.Namespace + "/" + r.Name
+}
+
+// AuditEvent records a request of an access token to GetAccessToken. It
+// never holds the token, nor the message of the error, which may contain
+// sensitive data returned by the provider.
+type AuditEvent struct {
+	// Time is the time the request completed at.
+	Time time.Time
+	// Provider is the name of the provider the token is requested from.
+	Provider string
+	// ServiceAccountName and ServiceAccountNamespace identify the service
+	// account the token is issued for, or are empty for the tokens of the
+	// controller.
+	ServiceAccountName      string
+	ServiceAccountNamespace string
+	// Audiences are the audiences of the token.
+	Audiences []string
+	// Caller is the object the token is requested for, see WithCallerRef.
+	Caller CallerRef
+	// CacheHit is true if the token was served from the cache instead of
+	// being issued by the provider.
+	CacheHit bool
+	// Success is true if the token was returned.
+	Success bool
+	// ErrorClass is the class of the error the request failed with, or
+	// empty on success.
+	ErrorClass ErrorClass
+}
+
+// WithAuditCallback sets the callback invoked with the AuditEvent of each
+// request of an access token, successful or failed, including the tokens
+// served from the cache. The access tokens requested for the artifact
+// registry, Git and REST config credentials are audited too. The callback is
+// called synchronously, so it must not block.
+func WithAuditCallback(callback func(AuditEvent)) Option {
+	return func(o *Options) {
+		o.AuditCallback = callback
+	}
+}
+
+// WithCallerRef sets the object the tokens are requested for, recorded as
+// the caller of the AuditEvents. The kind is read from the type metadata of
+// the object, falling back to the name of its Go type. Defaults to the
+// involved object of WithCache.
+func WithCallerRef(obj client.Object) Option {
+	return func(o *Options) {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		if kind == "" {
+			kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+		}
+		o.CallerRef = &CallerRef{
+			Kind:      kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+		}
+	}
+}
+
+// NewAuditLogger returns an audit callback logging the AuditEvents with the
+// given logger, with the keys provider, serviceAccount, audiences, caller,
+// cacheHit, success and errorClass.
+func NewAuditLogger(log logr.Logger) func(AuditEvent) {
+	return func(e AuditEvent) {
+		var serviceAccount string
+		if e.ServiceAccountNamespace != "" {
+			serviceAccount = e.ServiceAccountNamespace + "/" + e.ServiceAccountName
+		}
+		msg := "access token issued"
+		if !e.Success {
+			msg = "access token request failed"
+		}
+		log.Info(msg,
+			"provider", e.Provider,
+			"serviceAccount", serviceAccount,
+			"audiences", e.Audiences,
+			"caller", e.Caller.String(),
+			"cacheHit", e.CacheHit,
+			"success", e.Success,
+			"errorClass", string(e.ErrorClass))
+	}
+}
+
+// audit invokes the audit callback of the options, if any, with the event
+// of a token request completed with the given error.
+func (o *Options) audit(event AuditEvent, err error) {
+	if o.AuditCallback == nil {
+		return
+	}
+	event.Time = time.Now()
+	if o.CallerRef != nil {
+		event.Caller = *o.CallerRef
+	} else {
+		event.Caller = CallerRef{
+			Kind:      o.InvolvedObject.Kind,
+			Namespace: o.InvolvedObject.Namespace,
+			Name:      o.InvolvedObject.Name,
+		}
+	}
+	event.Success = err == nil
+	event.ErrorClass = errorClass(err)
+	o.AuditCallback(event)
+}
+
+// errorClass returns the class of the given error, or an empty class if nil.
+func errorClass(err error) ErrorClass {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrInvalidConfiguration):
+		return ErrorClassInvalidConfiguration
+	case errors.Is(err, ErrPermissionDenied):
+		return ErrorClassPermissionDenied
+	case errors.Is(err, ErrTransient):
+		return ErrorClassTransient
+	default:
+		return ErrorClassUnknown
+	}
+}
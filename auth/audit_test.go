@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/auth"
+	"github.com/fluxcd/pkg/cache"
+)
+
+func TestGetAccessToken_AuditCallback(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	tokenCache, err := cache.NewTokenCache(1)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	var events []auth.AuditEvent
+	provider := &mockProvider{
+		t:                     t,
+		returnName:            "mock-provider",
+		returnControllerToken: &mockToken{token: "secret-token"},
+	}
+	opts := []auth.Option{
+		auth.WithAudiences("audience1", "audience2"),
+		auth.WithScopes("scope1", "scope2"),
+		auth.WithSTSRegion("us-east-1"),
+		auth.WithSTSEndpoint("https://sts.some-cloud.io"),
+		auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.io:8080"}),
+		auth.WithCAData("ca-data"),
+		auth.WithCache(*tokenCache, cache.InvolvedObject{Kind: "OCIRepository", Name: "podinfo", Namespace: "apps"}),
+		auth.WithCallerRef(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "caller", Namespace: "tenant"}}),
+		auth.WithAuditCallback(func(e auth.AuditEvent) { events = append(events, e) }),
+	}
+
+	// The first request issues the token, the second is served from the cache.
+	for range 2 {
+		_, err = auth.GetAccessToken(ctx, provider, opts...)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	g.Expect(events).To(HaveLen(2))
+	for i, e := range events {
+		g.Expect(e.Time).NotTo(BeZero())
+		g.Expect(e.Provider).To(Equal("mock-provider"))
+		g.Expect(e.ServiceAccountName).To(BeEmpty())
+		g.Expect(e.ServiceAccountNamespace).To(BeEmpty())
+		g.Expect(e.Audiences).To(Equal([]string{"audience1", "audience2"}))
+		g.Expect(e.Caller).To(Equal(auth.CallerRef{Kind: "ConfigMap", Namespace: "tenant", Name: "caller"}))
+		g.Expect(e.CacheHit).To(Equal(i == 1))
+		g.Expect(e.Success).To(BeTrue())
+		g.Expect(e.ErrorClass).To(BeEmpty())
+		g.Expect(fmt.Sprintf("%+v", e)).NotTo(ContainSubstring("secret-token"))
+	}
+}
+
+func TestGetAccessToken_AuditCallbackFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	tokenCache, err := cache.NewTokenCache(1)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	var events []auth.AuditEvent
+	_, err = auth.GetAccessToken(context.Background(), &mockProvider{t: t, returnName: "mock-provider"}, []auth.Option{
+		auth.WithServiceAccountName("default"),
+		auth.WithServiceAccountNamespace("tenant-a"),
+		auth.WithAllowedServiceAccountNamespaces([]string{"tenant-b"}),
+		auth.WithCache(*tokenCache, cache.InvolvedObject{Kind: "OCIRepository", Name: "podinfo", Namespace: "tenant-a"}),
+		auth.WithAuditCallback(func(e auth.AuditEvent) { events = append(events, e) }),
+	}...)
+	g.Expect(err).To(HaveOccurred())
+
+	// The caller defaults to the involved object of the cache.
+	g.Expect(events).To(HaveLen(1))
+	g.Expect(events[0].ServiceAccountName).To(Equal("default"))
+	g.Expect(events[0].ServiceAccountNamespace).To(Equal("tenant-a"))
+	g.Expect(events[0].Caller).To(Equal(auth.CallerRef{Kind: "OCIRepository", Namespace: "tenant-a", Name: "podinfo"}))
+	g.Expect(events[0].CacheHit).To(BeFalse())
+	g.Expect(events[0].Success).To(BeFalse())
+	g.Expect(events[0].ErrorClass).To(Equal(auth.ErrorClassInvalidConfiguration))
+}
+
+func TestNewAuditLogger(t *testing.T) {
+	g := NewWithT(t)
+
+	var lines []string
+	log := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+
+	callback := auth.NewAuditLogger(log)
+	callback(auth.AuditEvent{
+		Provider:                "aws",
+		ServiceAccountName:      "default",
+		ServiceAccountNamespace: "apps",
+		Audiences:               []string{"sts.amazonaws.com"},
+		Caller:                  auth.CallerRef{Kind: "OCIRepository", Namespace: "apps", Name: "podinfo"},
+		CacheHit:                true,
+		Success:                 true,
+	})
+	callback(auth.AuditEvent{
+		Provider:   "gcp",
+		Caller:     auth.CallerRef{Kind: "Bucket", Name: "cluster-scoped"},
+		ErrorClass: auth.ErrorClassPermissionDenied,
+	})
+
+	g.Expect(lines).To(Equal([]string{
+		`"level"=0 "msg"="access token issued" "provider"="aws" "serviceAccount"="apps/default" ` +
+			`"audiences"=["sts.amazonaws.com"] "caller"="OCIRepository/apps/podinfo" "cacheHit"=true ` +
+			`"success"=true "errorClass"=""`,
+		`"level"=0 "msg"="access token request failed" "provider"="gcp" "serviceAccount"="" ` +
+			`"audiences"=[] "caller"="Bucket/cluster-scoped" "cacheHit"=false ` +
+			`"success"=false "errorClass"="PermissionDenied"`,
+	}))
+}
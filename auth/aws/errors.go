@@ -0,0 +1,53 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/fluxcd/pkg/auth"
+)
+
+// classifyError classifies the errors returned by the AWS APIs according
+// to the error code returned by the service or, when missing, according
+// to the HTTP status code of the response.
+func classifyError(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "AccessDenied", "AccessDeniedException", "InvalidIdentityToken",
+			"ExpiredTokenException", "UnrecognizedClientException":
+			return auth.NewPermissionDeniedError(err)
+		case "MalformedPolicyDocument", "PackedPolicyTooLarge", "ValidationError",
+			"InvalidParameterException", "ResourceNotFoundException":
+			return auth.NewInvalidConfigurationError(err)
+		case "Throttling", "ThrottlingException", "RequestLimitExceeded",
+			"IDPCommunicationError", "ServiceUnavailable", "ServerException":
+			return auth.NewTransientError(err)
+		}
+	}
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		return auth.NewErrorFromHTTPStatus(respErr.HTTPStatusCode(), err)
+	}
+
+	return auth.ClassifyError(err)
+}
@@ -61,6 +61,7 @@ type mockImplementation struct {
 	returnEndpoint     string
 	returnCAData       string
 	returnPresignedURL string
+	returnErr          error
 }
 
 type mockHTTPPresigner struct {
@@ -112,6 +113,9 @@ func (m *mockImplementation) AssumeRoleWithWebIdentity(ctx context.Context, para
 	proxyURL, err := options.HTTPClient.(*http.Client).Transport.(*http.Transport).Proxy(nil)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(proxyURL).To(Equal(m.argProxyURL))
+	if m.returnErr != nil {
+		return nil, m.returnErr
+	}
 	return &sts.AssumeRoleWithWebIdentityOutput{
 		Credentials: &ststypes.Credentials{
 			AccessKeyId:     aws.String(m.returnCreds.AccessKeyID),
@@ -21,6 +21,8 @@ import (
 	"regexp"
 
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/fluxcd/pkg/auth"
 )
 
 const stsEndpointPattern = `^https://(.+\.)?sts(-fips)?(\.[^.]+)?(\.vpce)?\.amazonaws\.com$`
@@ -39,8 +41,8 @@ var stsEndpointRegex = regexp.MustCompile(stsEndpointPattern)
 //	https://vpce-002b7cc8966426bc6-njisq19r-us-east-1a.sts.us-east-1.vpce.amazonaws.com
 func ValidateSTSEndpoint(endpoint string) error {
 	if !stsEndpointRegex.MatchString(endpoint) {
-		return fmt.Errorf("invalid STS endpoint: '%s'. must match %s",
-			endpoint, stsEndpointPattern)
+		return auth.NewInvalidConfigurationError(fmt.Errorf("invalid STS endpoint: '%s'. must match %s",
+			endpoint, stsEndpointPattern))
 	}
 	return nil
 }
@@ -53,8 +55,8 @@ func getRoleARN(serviceAccount corev1.ServiceAccount) (string, error) {
 	const key = "eks.amazonaws.com/role-arn"
 	arn := serviceAccount.Annotations[key]
 	if !roleARNRegex.MatchString(arn) {
-		return "", fmt.Errorf("invalid %s annotation: '%s'. must match %s",
-			key, arn, roleARNPattern)
+		return "", auth.NewInvalidConfigurationError(fmt.Errorf("invalid %s annotation: '%s'. must match %s",
+			key, arn, roleARNPattern))
 	}
 	return arn, nil
 }
@@ -72,8 +74,8 @@ var clusterRegex = regexp.MustCompile(clusterPattern)
 func parseCluster(cluster string) (string, string, error) {
 	m := clusterRegex.FindStringSubmatch(cluster)
 	if len(m) != 3 {
-		return "", "", fmt.Errorf("invalid EKS cluster ARN: '%s'. must match %s",
-			cluster, clusterPattern)
+		return "", "", auth.NewInvalidConfigurationError(fmt.Errorf("invalid EKS cluster ARN: '%s'. must match %s",
+			cluster, clusterPattern))
 	}
 	region := m[1]
 	name := m[2]
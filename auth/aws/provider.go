@@ -64,6 +64,10 @@ const (
 // Provider implements the auth.Provider interface for AWS authentication.
 type Provider struct{ Implementation }
 
+func init() {
+	auth.MustRegister(Provider{})
+}
+
 // GetName implements auth.Provider.
 func (Provider) GetName() string {
 	return ProviderName
@@ -78,19 +82,15 @@ func (p Provider) NewControllerToken(ctx context.Context, opts ...auth.Option) (
 		config.WithHTTPClient(o.GetHTTPClient()),
 	}
 
-	stsRegion := o.STSRegion
-	if stsRegion == "" {
-		// EKS sets this environment variable automatically if the controller pod is
-		// properly configured with IRSA or EKS Pod Identity, so we can rely on it.
-		stsRegion = os.Getenv("AWS_REGION")
-		if stsRegion == "" {
-			return nil, errors.New("AWS_REGION environment variable is not set in the Flux controller. " +
-				"if you have properly configured IAM Roles for Service Accounts (IRSA) or EKS Pod Identity, " +
-				"please delete/replace the controller pod so the EKS admission controllers can inject this " +
-				"environment variable, or set it manually if the cluster is not EKS")
-		}
+	region, err := resolveRegion(ctx, &o,
+		"AWS_REGION environment variable is not set in the Flux controller. "+
+			"if you have properly configured IAM Roles for Service Accounts (IRSA) or EKS Pod Identity, "+
+			"please delete/replace the controller pod so the EKS admission controllers can inject this "+
+			"environment variable, or set it manually if the cluster is not EKS")
+	if err != nil {
+		return nil, err
 	}
-	confOpts = append(confOpts, config.WithRegion(stsRegion))
+	confOpts = append(confOpts, config.WithRegion(region.Name))
 
 	if e := o.STSEndpoint; e != "" {
 		if err := ValidateSTSEndpoint(e); err != nil {
@@ -99,13 +99,28 @@ func (p Provider) NewControllerToken(ctx context.Context, opts ...auth.Option) (
 		confOpts = append(confOpts, config.WithBaseEndpoint(e))
 	}
 
+	if o.OIDCTokenFile != "" {
+		oidcToken, err := o.ReadOIDCTokenFile()
+		if err != nil {
+			return nil, err
+		}
+		roleARN := os.Getenv("AWS_ROLE_ARN")
+		if !roleARNRegex.MatchString(roleARN) {
+			return nil, auth.NewInvalidConfigurationError(fmt.Errorf(
+				"invalid AWS_ROLE_ARN environment variable: '%s'. must match %s", roleARN, roleARNPattern))
+		}
+		roleSessionName := fmt.Sprintf("controller.%s.fluxcd.io", region.Name)
+		return p.assumeRoleWithWebIdentity(ctx, oidcToken, roleARN, roleSessionName, region, &o)
+	}
+
 	conf, err := p.impl().LoadDefaultConfig(ctx, confOpts...)
 	if err != nil {
-		return nil, err
+		return nil, auth.NewInvalidConfigurationError(err)
 	}
 	creds, err := conf.Credentials.Retrieve(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to retrieve the controller credentials in region %s: %w",
+			region, classifyError(err))
 	}
 
 	return newTokenFromAWSCredentials(&creds), nil
@@ -132,16 +147,18 @@ func (p Provider) NewTokenForServiceAccount(ctx context.Context, oidcToken strin
 	var o auth.Options
 	o.Apply(opts...)
 
-	stsRegion := o.STSRegion
-	if stsRegion == "" {
-		// In this case we can't rely on IRSA or EKS Pod Identity for the controller
-		// pod because this is object-level configuration, so we show a different
-		// error message.
-		// In this error message we assume an API that has a region field, e.g. the
-		// Bucket API. APIs that can extract the region from the ARN (e.g. KMS) will
-		// never reach this code path.
-		return nil, errors.New("an AWS region is required for authenticating with a service account. " +
+	// The region is usually configured in the object spec or parsed from the
+	// ARN of the resource, otherwise the region of the controller is used.
+	// This is object-level configuration, so we show a different error message
+	// than for the controller when the region is missing.
+	// In this error message we assume an API that has a region field, e.g. the
+	// Bucket API. APIs that can extract the region from the ARN (e.g. KMS) will
+	// never reach this code path.
+	region, err := resolveRegion(ctx, &o,
+		"an AWS region is required for authenticating with a service account. "+
 			"please configure one in the object spec")
+	if err != nil {
+		return nil, err
 	}
 
 	roleARN, err := getRoleARN(serviceAccount)
@@ -149,10 +166,19 @@ func (p Provider) NewTokenForServiceAccount(ctx context.Context, oidcToken strin
 		return nil, err
 	}
 
-	roleSessionName := getRoleSessionName(serviceAccount, stsRegion)
+	roleSessionName := getRoleSessionName(serviceAccount, region.Name)
+
+	return p.assumeRoleWithWebIdentity(ctx, oidcToken, roleARN, roleSessionName, region, &o)
+}
+
+// assumeRoleWithWebIdentity exchanges the given OIDC token for AWS
+// credentials of the given role through the regional endpoint of the STS
+// service.
+func (p Provider) assumeRoleWithWebIdentity(ctx context.Context, oidcToken, roleARN, roleSessionName string,
+	region Region, o *auth.Options) (auth.Token, error) {
 
 	stsOpts := sts.Options{
-		Region:     stsRegion,
+		Region:     region.Name,
 		HTTPClient: o.GetHTTPClient(),
 	}
 
@@ -170,7 +196,7 @@ func (p Provider) NewTokenForServiceAccount(ctx context.Context, oidcToken strin
 	}
 	resp, err := p.impl().AssumeRoleWithWebIdentity(ctx, req, stsOpts)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to assume role '%s' in region %s: %w", roleARN, region, classifyError(err))
 	}
 	if resp.Credentials == nil {
 		return nil, fmt.Errorf("credentials are nil")
@@ -203,6 +229,16 @@ func (p Provider) GetAccessTokenOptionsForArtifactRepository(artifactRepository
 	return []auth.Option{auth.WithSTSRegion(ecrRegion)}, nil
 }
 
+// GetAccessTokenOptionSetsForArtifactRepository implements auth.ArtifactRepositoryProvider.
+// A single access token with the region of the ECR registry is required.
+func (p Provider) GetAccessTokenOptionSetsForArtifactRepository(artifactRepository string, _ ...auth.Option) ([][]auth.Option, error) {
+	opts, err := p.GetAccessTokenOptionsForArtifactRepository(artifactRepository)
+	if err != nil {
+		return nil, err
+	}
+	return [][]auth.Option{opts}, nil
+}
+
 // This regex is sourced from the AWS ECR Credential Helper (https://github.com/awslabs/amazon-ecr-credential-helper).
 // It covers both public AWS partitions like amazonaws.com, China partitions like amazonaws.com.cn, and non-public partitions.
 const registryPattern = `([0-9+]*).dkr[.-]ecr(?:-fips)?\.([^/.]*)\.(amazonaws\.com[.cn]*|amazonaws\.eu|sc2s\.sgov\.gov|c2s\.ic\.gov|cloud\.adc-e\.uk|csp\.hci\.ic\.gov|on\.aws)`
@@ -226,8 +262,11 @@ func (Provider) ParseArtifactRepository(artifactRepository string) (string, erro
 
 	parts := registryRegex.FindAllStringSubmatch(registry, -1)
 	if len(parts) < 1 || len(parts[0]) < 3 {
-		return "", fmt.Errorf("invalid AWS registry: '%s'. must match %s",
-			registry, registryPattern)
+		return "", &auth.ErrUnsupportedRegistry{
+			Provider: ProviderName,
+			Registry: registry,
+			Err:      fmt.Errorf("invalid AWS registry: '%s'. must match %s", registry, registryPattern),
+		}
 	}
 
 	ecrRegion := parts[0][2]
@@ -263,7 +302,7 @@ func (p Provider) NewArtifactRegistryCredentials(ctx context.Context, registryIn
 
 	respAny, err := authTokenFunc(ctx, conf)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	// Parse the authorization token.
@@ -362,7 +401,7 @@ func (p Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 		}
 		clusterResource, err := p.impl().DescribeCluster(ctx, describeInput, eksOpts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to describe EKS cluster '%s': %w", cluster, err)
+			return nil, fmt.Errorf("failed to describe EKS cluster '%s': %w", cluster, classifyError(err))
 		}
 
 		// Update host and CA with cluster details.
@@ -416,16 +455,18 @@ func (p Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 // https://docs.aws.amazon.com/codecommit/latest/userguide/regions.html#regions-git
 func getRegionFromCodeCommitURL(gitURL *url.URL) (string, error) {
 	if gitURL == nil {
-		return "", fmt.Errorf("Git URL must be specified for AWS CodeCommit authentication")
+		return "", auth.NewInvalidConfigurationError(
+			errors.New("Git URL must be specified for AWS CodeCommit authentication"))
 	}
 	if !strings.EqualFold(gitURL.Scheme, "https") {
-		return "", fmt.Errorf("AWS CodeCommit authentication requires an HTTPS Git URL")
+		return "", auth.NewInvalidConfigurationError(
+			errors.New("AWS CodeCommit authentication requires an HTTPS Git URL"))
 	}
 	urlSplit := strings.Split(gitURL.Hostname(), ".")
 	if len(urlSplit) < 4 ||
 		!(strings.HasPrefix(gitURL.Hostname(), "git-codecommit.") || strings.HasPrefix(gitURL.Hostname(), "git-codecommit-fips.")) ||
 		!(strings.HasSuffix(gitURL.Hostname(), ".amazonaws.com") || strings.HasSuffix(gitURL.Hostname(), ".amazonaws.com.cn")) {
-		return "", fmt.Errorf("invalid AWS CodeCommit Git URL: %s", gitURL.Host)
+		return "", auth.NewInvalidConfigurationError(fmt.Errorf("invalid AWS CodeCommit Git URL: %s", gitURL.Host))
 	}
 	return urlSplit[1], nil
 }
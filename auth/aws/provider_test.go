@@ -18,13 +18,22 @@ package aws_test
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/go-containerregistry/pkg/authn"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -75,6 +84,10 @@ func TestProvider_NewControllerToken(t *testing.T) {
 
 			if !tt.skipSTSRegion {
 				t.Setenv("AWS_REGION", "us-east-1")
+			} else {
+				t.Setenv("AWS_REGION", "")
+				t.Setenv("AWS_DEFAULT_REGION", "")
+				t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
 			}
 
 			opts := []auth.Option{
@@ -102,6 +115,90 @@ func TestProvider_NewControllerToken(t *testing.T) {
 	}
 }
 
+func TestProvider_NewControllerToken_OIDCTokenFile(t *testing.T) {
+	newTokenFile := func(t *testing.T, exp time.Time) (string, string) {
+		t.Helper()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"exp": exp.Unix(),
+		}).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "token")
+		if err := os.WriteFile(path, []byte(token), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path, token
+	}
+
+	for _, tt := range []struct {
+		name    string
+		exp     time.Time
+		roleARN string
+		err     string
+	}{
+		{
+			name:    "valid",
+			exp:     time.Now().Add(time.Hour),
+			roleARN: "arn:aws:iam::1234567890:role/some-role",
+		},
+		{
+			name:    "expired token",
+			exp:     time.Now().Add(-time.Hour),
+			roleARN: "arn:aws:iam::1234567890:role/some-role",
+			err:     "expired at",
+		},
+		{
+			name: "missing role ARN",
+			exp:  time.Now().Add(time.Hour),
+			err:  "invalid AWS_ROLE_ARN environment variable: ''",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Setenv("AWS_REGION", "us-east-1")
+			t.Setenv("AWS_ROLE_ARN", tt.roleARN)
+
+			path, oidcToken := newTokenFile(t, tt.exp)
+
+			impl := &mockImplementation{
+				t:                  t,
+				argRoleARN:         tt.roleARN,
+				argRoleSessionName: "controller.us-east-1.fluxcd.io",
+				argOIDCToken:       oidcToken,
+				argRegion:          "us-east-1",
+				argProxyURL:        &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				argSTSEndpoint:     "https://sts.amazonaws.com",
+				returnCreds:        awssdk.Credentials{AccessKeyID: "access-key-id"},
+			}
+
+			opts := []auth.Option{
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+				auth.WithSTSEndpoint("https://sts.amazonaws.com"),
+				auth.WithOIDCTokenFile(path),
+			}
+
+			provider := aws.Provider{Implementation: impl}
+			token, err := provider.NewControllerToken(t.Context(), opts...)
+
+			if tt.err == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(token).To(Equal(&aws.Credentials{Credentials: types.Credentials{
+					AccessKeyId:     awssdk.String("access-key-id"),
+					SecretAccessKey: awssdk.String(""),
+					SessionToken:    awssdk.String(""),
+					Expiration:      awssdk.Time(time.Time{}),
+				}}))
+			} else {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				g.Expect(token).To(BeNil())
+			}
+		})
+	}
+}
+
 func TestProvider_NewTokenForServiceAccount(t *testing.T) {
 	for _, tt := range []struct {
 		name               string
@@ -172,6 +269,10 @@ func TestProvider_NewTokenForServiceAccount(t *testing.T) {
 
 			if !tt.skipSTSRegion {
 				opts = append(opts, auth.WithSTSRegion("us-east-1"))
+			} else {
+				t.Setenv("AWS_REGION", "")
+				t.Setenv("AWS_DEFAULT_REGION", "")
+				t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
 			}
 
 			provider := aws.Provider{Implementation: impl}
@@ -194,6 +295,94 @@ func TestProvider_NewTokenForServiceAccount(t *testing.T) {
 	}
 }
 
+func TestProvider_NewTokenForServiceAccount_ErrorClassification(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		roleARN   string
+		stsErr    error
+		wantErr   error
+		retryable bool
+	}{
+		{
+			name:    "invalid role ARN",
+			roleARN: "foobar",
+			wantErr: auth.ErrInvalidConfiguration,
+		},
+		{
+			name:    "access denied",
+			stsErr:  &smithy.GenericAPIError{Code: "AccessDenied", Message: "Not authorized to perform sts:AssumeRoleWithWebIdentity"},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name:    "invalid identity token",
+			stsErr:  &smithy.GenericAPIError{Code: "InvalidIdentityToken", Message: "No OpenIDConnect provider found"},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name:      "throttling",
+			stsErr:    &smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"},
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name: "server error",
+			stsErr: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}},
+				Err:      errors.New("service unavailable"),
+			},
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:      "network error",
+			stsErr:    &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			roleARN := tt.roleARN
+			if roleARN == "" {
+				roleARN = "arn:aws:iam::1234567890:role/some-role"
+			}
+
+			impl := &mockImplementation{
+				t:                  t,
+				argRegion:          "us-east-1",
+				argRoleARN:         roleARN,
+				argRoleSessionName: "test-sa.test-ns.us-east-1.fluxcd.io",
+				argOIDCToken:       "oidc-token",
+				argSTSEndpoint:     "https://sts.amazonaws.com",
+				argProxyURL:        &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				returnErr:          tt.stsErr,
+			}
+
+			serviceAccount := corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-sa",
+					Namespace:   "test-ns",
+					Annotations: map[string]string{"eks.amazonaws.com/role-arn": roleARN},
+				},
+			}
+
+			provider := aws.Provider{Implementation: impl}
+			_, err := provider.NewTokenForServiceAccount(context.Background(), "oidc-token", serviceAccount,
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+				auth.WithSTSRegion("us-east-1"),
+				auth.WithSTSEndpoint("https://sts.amazonaws.com"))
+
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err).To(MatchError(tt.wantErr))
+			if tt.stsErr != nil {
+				g.Expect(err).To(MatchError(tt.stsErr))
+			}
+			g.Expect(auth.IsRetryable(err)).To(Equal(tt.retryable))
+		})
+	}
+}
+
 func TestProvider_GetAudiences(t *testing.T) {
 	g := NewWithT(t)
 	aud, err := aws.Provider{}.GetAudiences(context.Background(), corev1.ServiceAccount{})
@@ -290,6 +479,76 @@ func TestProvider_GetAccessTokenOptionsForArtifactRepository(t *testing.T) {
 	g.Expect(o.STSRegion).To(Equal("us-east-1"))
 }
 
+func TestProvider_GetAccessTokenOptionSetsForArtifactRepository(t *testing.T) {
+	for _, tt := range []struct {
+		name               string
+		artifactRepository string
+		expectedRegion     string
+		unsupported        bool
+		malformed          bool
+	}{
+		{
+			name:               "private ECR repository",
+			artifactRepository: "012345678901.dkr.ecr.us-east-1.amazonaws.com/foo:v1",
+			expectedRegion:     "us-east-1",
+		},
+		{
+			name:               "FIPS ECR registry",
+			artifactRepository: "012345678901.dkr-ecr-fips.us-gov-west-1.amazonaws.com",
+			expectedRegion:     "us-gov-west-1",
+		},
+		{
+			name:               "China partition",
+			artifactRepository: "012345678901.dkr.ecr.cn-north-1.amazonaws.com.cn/foo",
+			expectedRegion:     "cn-north-1",
+		},
+		{
+			name:               "public ECR",
+			artifactRepository: "public.ecr.aws/foo/bar",
+			expectedRegion:     "us-east-1",
+		},
+		{
+			name:               "ACR registry",
+			artifactRepository: "myregistry.azurecr.io/foo",
+			unsupported:        true,
+		},
+		{
+			name:               "GAR registry",
+			artifactRepository: "us-central1-docker.pkg.dev/project/repo",
+			unsupported:        true,
+		},
+		{
+			name:               "malformed repository",
+			artifactRepository: "012345678901.dkr.ecr.us-east-1.amazonaws.com/Foo",
+			malformed:          true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts, err := aws.Provider{}.GetAccessTokenOptionSetsForArtifactRepository(tt.artifactRepository)
+
+			var unsupportedErr *auth.ErrUnsupportedRegistry
+			switch {
+			case tt.unsupported:
+				g.Expect(errors.As(err, &unsupportedErr)).To(BeTrue())
+				g.Expect(unsupportedErr.Provider).To(Equal(aws.ProviderName))
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+			case tt.malformed:
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				g.Expect(errors.As(err, &unsupportedErr)).To(BeFalse())
+			default:
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(opts).To(HaveLen(1))
+
+				var o auth.Options
+				o.Apply(opts[0]...)
+				g.Expect(o.STSRegion).To(Equal(tt.expectedRegion))
+			}
+		})
+	}
+}
+
 func TestProvider_ParseArtifactRepository(t *testing.T) {
 	tests := []struct {
 		artifactRepository string
@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+
+	"github.com/fluxcd/pkg/auth"
+)
+
+// RegionSource is the source a Region was resolved from.
+type RegionSource string
+
+const (
+	// RegionSourceOption is the source of the region set with auth.WithAWSRegion.
+	RegionSourceOption RegionSource = "auth.WithAWSRegion option"
+	// RegionSourceResource is the source of the region set with auth.WithSTSRegion,
+	// i.e. the region configured in the object spec or parsed from the EKS
+	// cluster ARN, the ECR host or the CodeCommit URL.
+	RegionSourceResource RegionSource = "auth.WithSTSRegion option"
+	// RegionSourceEnvAWSRegion is the source of the region set with the
+	// AWS_REGION environment variable.
+	RegionSourceEnvAWSRegion RegionSource = "AWS_REGION environment variable"
+	// RegionSourceEnvAWSDefaultRegion is the source of the region set with the
+	// AWS_DEFAULT_REGION environment variable.
+	RegionSourceEnvAWSDefaultRegion RegionSource = "AWS_DEFAULT_REGION environment variable"
+	// RegionSourceIMDS is the source of the region retrieved from the EC2
+	// instance metadata service.
+	RegionSourceIMDS RegionSource = "EC2 instance metadata"
+)
+
+// imdsTimeout bounds the lookup of the region from the EC2 instance metadata
+// service, including the retries of the client, so that the controllers
+// running outside EC2 fail fast.
+const imdsTimeout = 5 * time.Second
+
+// Region is an AWS region along with the source it was resolved from,
+// recorded for debugging.
+type Region struct {
+	Name   string
+	Source RegionSource
+}
+
+// String returns the name of the region and its source.
+func (r Region) String() string {
+	return fmt.Sprintf("'%s' (from %s)", r.Name, r.Source)
+}
+
+// ResolveRegion returns the region of the AWS STS service for the given
+// options. The region is resolved from, in order:
+//
+//  1. The auth.WithAWSRegion option.
+//  2. The auth.WithSTSRegion option, which the provider sets to the region of
+//     the EKS cluster ARN, the ECR host or the CodeCommit URL.
+//  3. The AWS_REGION and AWS_DEFAULT_REGION environment variables.
+//  4. The EC2 instance metadata service (IMDSv2, falling back to IMDSv1 when
+//     the token request is dropped by the hop limit of the instance), unless
+//     the AWS_EC2_METADATA_DISABLED environment variable is set to true. The
+//     AWS_EC2_METADATA_SERVICE_ENDPOINT environment variable overrides the
+//     endpoint of the service.
+//
+// The global STS endpoint is disabled in many organizations, so the
+// aws-global pseudo-region is rejected and the regional endpoint of the
+// resolved region is always used.
+func ResolveRegion(ctx context.Context, opts ...auth.Option) (Region, error) {
+	var o auth.Options
+	o.Apply(opts...)
+	return resolveRegion(ctx, &o, "an AWS region is required")
+}
+
+// resolveRegion implements ResolveRegion. It returns an invalid configuration
+// error with the given message if the region is not found, along with the
+// error of the EC2 instance metadata service, if any.
+func resolveRegion(ctx context.Context, o *auth.Options, notFoundMsg string) (Region, error) {
+	region := lookupRegion(o)
+	if region.Name == "" {
+		name, err := getIMDSRegion(ctx)
+		switch {
+		case err != nil:
+			return Region{}, auth.NewInvalidConfigurationError(fmt.Errorf("%s. %w", notFoundMsg, err))
+		case name == "":
+			return Region{}, auth.NewInvalidConfigurationError(errors.New(notFoundMsg))
+		}
+		region = Region{Name: name, Source: RegionSourceIMDS}
+	}
+	if region.Name == "aws-global" {
+		return Region{}, auth.NewInvalidConfigurationError(fmt.Errorf(
+			"invalid AWS region %s: the global STS endpoint is not supported, "+
+				"please configure a regional one", region))
+	}
+	return region, nil
+}
+
+// lookupRegion returns the region of the options or the environment, if any.
+func lookupRegion(o *auth.Options) Region {
+	switch {
+	case o.AWSRegion != "":
+		return Region{Name: o.AWSRegion, Source: RegionSourceOption}
+	case o.STSRegion != "":
+		return Region{Name: o.STSRegion, Source: RegionSourceResource}
+	}
+	// EKS sets AWS_REGION automatically if the controller pod is properly
+	// configured with IRSA or EKS Pod Identity, so we can rely on it.
+	if name := os.Getenv("AWS_REGION"); name != "" {
+		return Region{Name: name, Source: RegionSourceEnvAWSRegion}
+	}
+	if name := os.Getenv("AWS_DEFAULT_REGION"); name != "" {
+		return Region{Name: name, Source: RegionSourceEnvAWSDefaultRegion}
+	}
+	return Region{}
+}
+
+// getIMDSRegion returns the region of the EC2 instance the controller runs
+// on, or an empty string if the instance metadata service is disabled.
+// The client of the SDK is used as is: it goes around the proxy of the
+// options, and its short timeouts make the IMDSv2 token request fall back
+// to IMDSv1 on the pods whose instance has a hop limit of 1.
+func getIMDSRegion(ctx context.Context) (string, error) {
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		return "", nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, imdsTimeout)
+	defer cancel()
+	resp, err := imds.New(imds.Options{}).GetRegion(ctx, &imds.GetRegionInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the region from the EC2 instance metadata: %w", err)
+	}
+	return resp.Region, nil
+}
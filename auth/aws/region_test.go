@@ -0,0 +1,284 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/auth"
+	"github.com/fluxcd/pkg/auth/aws"
+)
+
+// fakeIMDS is a fake EC2 instance metadata service serving the region of
+// the instance.
+type fakeIMDS struct {
+	region string
+	// dropTokenRequests simulates a hop limit of 1, the response of the
+	// IMDSv2 token request never reaching the pod.
+	dropTokenRequests bool
+	// requireToken rejects the IMDSv1 requests.
+	requireToken bool
+
+	tokenRequests  atomic.Int32
+	regionRequests atomic.Int32
+}
+
+func (f *fakeIMDS) start(t *testing.T) {
+	t.Helper()
+	const token = "imds-token"
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		f.tokenRequests.Add(1)
+		if r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if f.dropTokenRequests {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Header().Set("X-aws-ec2-metadata-token-ttl-seconds", "300")
+		_, _ = w.Write([]byte(token))
+	})
+	mux.HandleFunc("GET /latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+		f.regionRequests.Add(1)
+		if tok := r.Header.Get("X-aws-ec2-metadata-token"); tok != token && (tok != "" || f.requireToken) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"region": f.region})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL)
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+}
+
+// unsetRegionEnv unsets the environment variables of the region.
+func unsetRegionEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+}
+
+func TestResolveRegion(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		opts             []auth.Option
+		awsRegion        string
+		awsDefaultRegion string
+		imds             *fakeIMDS
+		want             aws.Region
+		err              string
+	}{
+		{
+			name:             "explicit override",
+			opts:             []auth.Option{auth.WithAWSRegion("eu-west-1"), auth.WithSTSRegion("us-east-1")},
+			awsRegion:        "us-west-2",
+			awsDefaultRegion: "us-west-1",
+			imds:             &fakeIMDS{region: "ap-south-1"},
+			want:             aws.Region{Name: "eu-west-1", Source: aws.RegionSourceOption},
+		},
+		{
+			name:             "region of the resource",
+			opts:             []auth.Option{auth.WithSTSRegion("us-east-1")},
+			awsRegion:        "us-west-2",
+			awsDefaultRegion: "us-west-1",
+			imds:             &fakeIMDS{region: "ap-south-1"},
+			want:             aws.Region{Name: "us-east-1", Source: aws.RegionSourceResource},
+		},
+		{
+			name:             "AWS_REGION environment variable",
+			awsRegion:        "us-west-2",
+			awsDefaultRegion: "us-west-1",
+			imds:             &fakeIMDS{region: "ap-south-1"},
+			want:             aws.Region{Name: "us-west-2", Source: aws.RegionSourceEnvAWSRegion},
+		},
+		{
+			name:             "AWS_DEFAULT_REGION environment variable",
+			awsDefaultRegion: "us-west-1",
+			imds:             &fakeIMDS{region: "ap-south-1"},
+			want:             aws.Region{Name: "us-west-1", Source: aws.RegionSourceEnvAWSDefaultRegion},
+		},
+		{
+			name: "IMDSv2",
+			imds: &fakeIMDS{region: "ap-south-1", requireToken: true},
+			want: aws.Region{Name: "ap-south-1", Source: aws.RegionSourceIMDS},
+		},
+		{
+			name: "IMDSv1 fallback on hop limit",
+			imds: &fakeIMDS{region: "ap-south-1", dropTokenRequests: true},
+			want: aws.Region{Name: "ap-south-1", Source: aws.RegionSourceIMDS},
+		},
+		{
+			name: "IMDS failure",
+			imds: &fakeIMDS{region: "ap-south-1", dropTokenRequests: true, requireToken: true},
+			err:  "an AWS region is required. failed to get the region from the EC2 instance metadata",
+		},
+		{
+			name: "IMDS disabled",
+			err:  "an AWS region is required",
+		},
+		{
+			name: "global endpoint",
+			opts: []auth.Option{auth.WithAWSRegion("aws-global")},
+			err:  "invalid AWS region 'aws-global' (from auth.WithAWSRegion option): the global STS endpoint is not supported",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Setenv("AWS_REGION", tt.awsRegion)
+			t.Setenv("AWS_DEFAULT_REGION", tt.awsDefaultRegion)
+			if tt.imds != nil {
+				tt.imds.start(t)
+			} else {
+				t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+			}
+
+			region, err := aws.ResolveRegion(t.Context(), tt.opts...)
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(HavePrefix(tt.err))
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(region).To(Equal(tt.want))
+
+			// The instance metadata is queried only as the last resort.
+			if tt.want.Source != aws.RegionSourceIMDS {
+				g.Expect(tt.imds.tokenRequests.Load()).To(BeZero())
+				g.Expect(tt.imds.regionRequests.Load()).To(BeZero())
+			} else {
+				g.Expect(tt.imds.tokenRequests.Load()).NotTo(BeZero())
+				g.Expect(tt.imds.regionRequests.Load()).NotTo(BeZero())
+			}
+		})
+	}
+}
+
+func TestProvider_NewTokenForServiceAccount_Region(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opts   []auth.Option
+		region string
+	}{
+		{
+			name:   "explicit override",
+			opts:   []auth.Option{auth.WithAWSRegion("eu-west-1"), auth.WithSTSRegion("us-east-1")},
+			region: "eu-west-1",
+		},
+		{
+			name:   "region of the object",
+			opts:   []auth.Option{auth.WithSTSRegion("us-east-1")},
+			region: "us-east-1",
+		},
+		{
+			name:   "region of the instance",
+			region: "ap-south-1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			unsetRegionEnv(t)
+			(&fakeIMDS{region: "ap-south-1"}).start(t)
+
+			roleARN := "arn:aws:iam::1234567890:role/some-role"
+			impl := &mockImplementation{
+				t:                  t,
+				argRegion:          tt.region,
+				argRoleARN:         roleARN,
+				argRoleSessionName: "test-sa.test-ns." + tt.region + ".fluxcd.io",
+				argOIDCToken:       "oidc-token",
+				argSTSEndpoint:     "https://sts." + tt.region + ".amazonaws.com",
+				argProxyURL:        &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				returnCreds:        awssdk.Credentials{AccessKeyID: "access-key-id"},
+			}
+
+			serviceAccount := corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-sa",
+					Namespace:   "test-ns",
+					Annotations: map[string]string{"eks.amazonaws.com/role-arn": roleARN},
+				},
+			}
+
+			opts := append([]auth.Option{
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+				auth.WithSTSEndpoint("https://sts." + tt.region + ".amazonaws.com"),
+			}, tt.opts...)
+
+			provider := aws.Provider{Implementation: impl}
+			_, err := provider.NewTokenForServiceAccount(t.Context(), "oidc-token", serviceAccount, opts...)
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestProvider_NewTokenForServiceAccount_RegionInError(t *testing.T) {
+	g := NewWithT(t)
+
+	unsetRegionEnv(t)
+	(&fakeIMDS{region: "ap-south-1"}).start(t)
+
+	roleARN := "arn:aws:iam::1234567890:role/some-role"
+	impl := &mockImplementation{
+		t:                  t,
+		argRegion:          "ap-south-1",
+		argRoleARN:         roleARN,
+		argRoleSessionName: "test-sa.test-ns.ap-south-1.fluxcd.io",
+		argOIDCToken:       "oidc-token",
+		argSTSEndpoint:     "https://sts.ap-south-1.amazonaws.com",
+		argProxyURL:        &url.URL{Scheme: "http", Host: "proxy.example.com"},
+		returnErr:          &smithy.GenericAPIError{Code: "AccessDenied", Message: "Not authorized"},
+	}
+
+	serviceAccount := corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-sa",
+			Namespace:   "test-ns",
+			Annotations: map[string]string{"eks.amazonaws.com/role-arn": roleARN},
+		},
+	}
+
+	// The source of the region is recorded in the errors of the STS service.
+	provider := aws.Provider{Implementation: impl}
+	_, err := provider.NewTokenForServiceAccount(t.Context(), "oidc-token", serviceAccount,
+		auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+		auth.WithSTSEndpoint("https://sts.ap-south-1.amazonaws.com"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err).To(MatchError(auth.ErrPermissionDenied))
+	g.Expect(err.Error()).To(ContainSubstring("in region 'ap-south-1' (from EC2 instance metadata)"))
+}
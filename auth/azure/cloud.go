@@ -0,0 +1,152 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+
+	"github.com/fluxcd/pkg/auth"
+)
+
+const (
+	// envVarAzureEnvironment is the environment variable name used to specify
+	// the name of the Azure cloud, e.g. AzureChinaCloud.
+	envVarAzureEnvironment = "AZURE_ENVIRONMENT"
+
+	// envVarAzureAuthorityHost is the environment variable name used by the
+	// Azure SDK and the workload identity webhook to specify the authority host.
+	envVarAzureAuthorityHost = "AZURE_AUTHORITY_HOST"
+)
+
+// cloudNames maps the Azure cloud names, as used by the Azure CLI and the
+// Azure SDKs, to their configuration.
+var cloudNames = map[string]cloud.Configuration{
+	"azurecloud":             cloud.AzurePublic,
+	"azurepubliccloud":       cloud.AzurePublic,
+	"azurechinacloud":        cloud.AzureChina,
+	"azureusgovernment":      cloud.AzureGovernment,
+	"azureusgovernmentcloud": cloud.AzureGovernment,
+}
+
+// ParseCloudName returns the configuration of the Azure cloud with the given
+// name, e.g. AzurePublicCloud, AzureChinaCloud or AzureUSGovernmentCloud. The
+// name is case-insensitive. Controllers can use it for validating the cloud
+// name given in a flag at startup, before passing it to auth.WithAzureCloud.
+func ParseCloudName(name string) (cloud.Configuration, error) {
+	conf, ok := cloudNames[strings.ToLower(name)]
+	if !ok {
+		return cloud.Configuration{}, auth.NewInvalidConfigurationError(fmt.Errorf(
+			"invalid Azure cloud name '%s'. must be one of AzurePublicCloud, AzureChinaCloud, AzureUSGovernmentCloud",
+			name))
+	}
+	return conf, nil
+}
+
+// getCloudConfig returns the Azure cloud configuration set with auth.WithAzureCloud,
+// or the one named in the AZURE_ENVIRONMENT environment variable. It returns nil
+// if none is configured, in which case the Azure SDK defaults apply, i.e. the public
+// cloud, or the authority host set in the AZURE_AUTHORITY_HOST environment variable.
+func getCloudConfig(o *auth.Options) (*cloud.Configuration, error) {
+	if o.AzureCloud != nil {
+		if err := validateCloudConfig(o.AzureCloud); err != nil {
+			return nil, err
+		}
+		return o.AzureCloud, nil
+	}
+	if name, ok := os.LookupEnv(envVarAzureEnvironment); ok {
+		conf, err := ParseCloudName(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s environment variable: %w", envVarAzureEnvironment, err)
+		}
+		return &conf, nil
+	}
+	return nil, nil
+}
+
+func validateCloudConfig(conf *cloud.Configuration) error {
+	host := conf.ActiveDirectoryAuthorityHost
+	if host == "" {
+		return auth.NewInvalidConfigurationError(
+			errors.New("invalid Azure cloud configuration: the authority host is required"))
+	}
+	u, err := url.Parse(host)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return auth.NewInvalidConfigurationError(
+			fmt.Errorf("invalid Azure cloud configuration: the authority host '%s' must be an HTTPS URL", host))
+	}
+	return nil
+}
+
+// clientOptions returns the options of the Azure SDK clients for the given
+// auth options, i.e. the HTTP client and the configured cloud.
+func clientOptions(o *auth.Options) (azcore.ClientOptions, error) {
+	conf, err := getCloudConfig(o)
+	if err != nil {
+		return azcore.ClientOptions{}, err
+	}
+	clientOpts := azcore.ClientOptions{
+		Transport: o.GetHTTPClient(),
+	}
+	if conf != nil {
+		clientOpts.Cloud = *conf
+	}
+	return clientOpts, nil
+}
+
+// getARMCloudConfig returns the cloud configuration for the Azure Resource
+// Manager calls. The configured cloud takes precedence over the environment
+// file, which takes precedence over the cloud of the authority host.
+func getARMCloudConfig(o *auth.Options) (*cloud.Configuration, error) {
+	if o.AzureCloud == nil && hasEnvironmentFile() {
+		return getCloudConfigFromEnvironment()
+	}
+	conf, err := getCloudConfig(o)
+	if err != nil || conf != nil {
+		return conf, err
+	}
+
+	switch authorityHost := os.Getenv(envVarAzureAuthorityHost); {
+	case strings.Contains(authorityHost, "chinacloudapi.cn"):
+		return &cloud.AzureChina, nil
+	case strings.Contains(authorityHost, "microsoftonline.us"):
+		return &cloud.AzureGovernment, nil
+	default:
+		return &cloud.AzurePublic, nil
+	}
+}
+
+// containerRegistrySuffix returns the DNS suffix of the container registries
+// of the given cloud, or an empty string for the clouds it is unknown for.
+func containerRegistrySuffix(conf *cloud.Configuration) string {
+	switch conf.ActiveDirectoryAuthorityHost {
+	case cloud.AzurePublic.ActiveDirectoryAuthorityHost:
+		return ".azurecr.io"
+	case cloud.AzureChina.ActiveDirectoryAuthorityHost:
+		return ".azurecr.cn"
+	case cloud.AzureGovernment.ActiveDirectoryAuthorityHost:
+		return ".azurecr.us"
+	default:
+		return ""
+	}
+}
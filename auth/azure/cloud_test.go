@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/auth"
+	"github.com/fluxcd/pkg/auth/azure"
+)
+
+// mockAuthority is an http.RoundTripper serving the OpenID configuration
+// and the token endpoint of any AAD authority host, recording the hosts
+// of the requests.
+type mockAuthority struct {
+	mu    sync.Mutex
+	hosts []string
+}
+
+func (m *mockAuthority) RoundTrip(req *http.Request) (*http.Response, error) {
+	m.mu.Lock()
+	m.hosts = append(m.hosts, req.URL.Host)
+	m.mu.Unlock()
+
+	tenant := strings.Split(strings.TrimPrefix(req.URL.Path, "/"), "/")[0]
+	base := fmt.Sprintf("https://%s/%s", req.URL.Host, tenant)
+	var body string
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/.well-known/openid-configuration"):
+		body = fmt.Sprintf(`{"token_endpoint":"%[1]s/oauth2/v2.0/token","authorization_endpoint":"%[1]s/oauth2/v2.0/authorize","issuer":"%[1]s/v2.0"}`, base)
+	case strings.HasSuffix(req.URL.Path, "/oauth2/v2.0/token"):
+		body = `{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`
+	default:
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// authorityImplementation creates real client assertion credentials
+// talking to a mockAuthority.
+type authorityImplementation struct {
+	mockImplementation
+	authority *mockAuthority
+}
+
+func (m *authorityImplementation) NewClientAssertionCredential(tenantID string, clientID string, getAssertion func(context.Context) (string, error), options *azidentity.ClientAssertionCredentialOptions) (azcore.TokenCredential, error) {
+	opts := *options
+	opts.Transport = &http.Client{Transport: m.authority}
+	opts.DisableInstanceDiscovery = true
+	return azidentity.NewClientAssertionCredential(tenantID, clientID, getAssertion, &opts)
+}
+
+func TestProvider_NewTokenForServiceAccount_Cloud(t *testing.T) {
+	serviceAccount := corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"azure.workload.identity/tenant-id": "tenant-id",
+				"azure.workload.identity/client-id": "client-id",
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name          string
+		opts          []auth.Option
+		environment   string
+		authorityHost string
+		expectedHost  string
+		err           string
+	}{
+		{
+			name:         "public cloud by default",
+			expectedHost: "login.microsoftonline.com",
+		},
+		{
+			name:         "China cloud from the options",
+			opts:         []auth.Option{auth.WithAzureCloud(cloud.AzureChina)},
+			environment:  "AzureUSGovernmentCloud",
+			expectedHost: "login.chinacloudapi.cn",
+		},
+		{
+			name:         "Government cloud from the environment",
+			environment:  "AzureUSGovernmentCloud",
+			expectedHost: "login.microsoftonline.us",
+		},
+		{
+			name:         "China cloud from the environment with the Azure CLI name",
+			environment:  "AzureChinaCloud",
+			expectedHost: "login.chinacloudapi.cn",
+		},
+		{
+			name: "custom cloud from the options",
+			opts: []auth.Option{auth.WithAzureCloud(cloud.Configuration{
+				ActiveDirectoryAuthorityHost: "https://login.sovereign.example/",
+			})},
+			expectedHost: "login.sovereign.example",
+		},
+		{
+			name:          "custom authority host from the environment",
+			authorityHost: "https://login.sovereign.example/",
+			expectedHost:  "login.sovereign.example",
+		},
+		{
+			name:        "invalid cloud name in the environment",
+			environment: "AzureGermanCloud",
+			err:         "invalid AZURE_ENVIRONMENT environment variable: invalid Azure cloud name 'AzureGermanCloud'",
+		},
+		{
+			name: "invalid authority host in the options",
+			opts: []auth.Option{auth.WithAzureCloud(cloud.Configuration{
+				ActiveDirectoryAuthorityHost: "http://login.sovereign.example/",
+			})},
+			err: "the authority host 'http://login.sovereign.example/' must be an HTTPS URL",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Setenv("AZURE_AUTHORITY_HOST", tt.authorityHost)
+			if tt.environment != "" {
+				t.Setenv("AZURE_ENVIRONMENT", tt.environment)
+			}
+
+			authority := &mockAuthority{}
+			provider := azure.Provider{Implementation: &authorityImplementation{authority: authority}}
+			token, err := provider.NewTokenForServiceAccount(context.Background(), "oidc-token", serviceAccount,
+				append(tt.opts, auth.WithScopes("https://containerregistry.azure.net/.default"))...)
+
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.err)))
+				g.Expect(authority.hosts).To(BeEmpty())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(token.(*azure.Token).Token).To(Equal("access-token"))
+			g.Expect(authority.hosts).NotTo(BeEmpty())
+			for _, host := range authority.hosts {
+				g.Expect(host).To(Equal(tt.expectedHost))
+			}
+		})
+	}
+}
+
+func TestParseCloudName(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		expected cloud.Configuration
+		err      bool
+	}{
+		{name: "AzurePublicCloud", expected: cloud.AzurePublic},
+		{name: "AzureCloud", expected: cloud.AzurePublic},
+		{name: "azurechinacloud", expected: cloud.AzureChina},
+		{name: "AzureUSGovernment", expected: cloud.AzureGovernment},
+		{name: "AzureUSGovernmentCloud", expected: cloud.AzureGovernment},
+		{name: "AzureGermanCloud", err: true},
+		{name: "", err: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			conf, err := azure.ParseCloudName(tt.name)
+			if tt.err {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(errors.Is(err, auth.ErrInvalidConfiguration)).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(conf.ActiveDirectoryAuthorityHost).To(Equal(tt.expected.ActiveDirectoryAuthorityHost))
+		})
+	}
+}
+
+func TestProvider_Cloud_Endpoints(t *testing.T) {
+	t.Run("ACR scope from the options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		custom := cloud.Configuration{
+			ActiveDirectoryAuthorityHost: "https://login.sovereign.example/",
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				cloud.ResourceManager: {Endpoint: "https://management.sovereign.example"},
+			},
+		}
+		opts, err := azure.Provider{}.GetAccessTokenOptionsForArtifactRepository("myregistry.azurecr.io")
+		g.Expect(err).NotTo(HaveOccurred())
+
+		var o auth.Options
+		o.Apply(append([]auth.Option{auth.WithAzureCloud(custom)}, opts...)...)
+		g.Expect(o.Scopes).To(Equal([]string{"https://management.sovereign.example/.default"}))
+	})
+
+	t.Run("ARM scope from the environment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		t.Setenv("AZURE_ENVIRONMENT", "AzureChinaCloud")
+		opts, err := azure.Provider{}.GetAccessTokenOptionsForCluster(
+			auth.WithClusterResource("/subscriptions/12345678-1234-1234-1234-123456789012/resourceGroups/test-rg/providers/Microsoft.ContainerService/managedClusters/test-cluster"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts).To(HaveLen(2))
+
+		var o auth.Options
+		o.Apply(opts[1]...)
+		g.Expect(o.Scopes).To(Equal([]string{cloud.AzureChina.Services[cloud.ResourceManager].Audience + "/.default"}))
+		g.Expect(o.Scopes[0]).To(ContainSubstring("chinacloudapi.cn"))
+	})
+
+	t.Run("invalid cloud name in the environment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		t.Setenv("AZURE_ENVIRONMENT", "AzureGermanCloud")
+		_, err := azure.Provider{}.GetAccessTokenOptionsForCluster(
+			auth.WithClusterResource("/subscriptions/12345678-1234-1234-1234-123456789012/resourceGroups/test-rg/providers/Microsoft.ContainerService/managedClusters/test-cluster"))
+		g.Expect(err).To(MatchError(ContainSubstring("invalid Azure cloud name 'AzureGermanCloud'")))
+
+		_, err = azure.Provider{}.GetAccessTokenOptionsForArtifactRepository("myregistry.azurecr.io")
+		g.Expect(err).To(MatchError(ContainSubstring("invalid Azure cloud name 'AzureGermanCloud'")))
+	})
+
+	t.Run("registry outside of the configured cloud", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := azure.Provider{}.NewArtifactRegistryCredentials(context.Background(), "myregistry.azurecr.io",
+			&azure.Token{}, auth.WithAzureCloud(cloud.AzureChina))
+		g.Expect(err).To(MatchError(
+			"invalid Azure registry: 'myregistry.azurecr.io'. must end with .azurecr.cn in the configured Azure cloud"))
+	})
+}
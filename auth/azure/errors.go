@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"errors"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+
+	"github.com/fluxcd/pkg/auth"
+)
+
+// nonRetriable is implemented by the azidentity errors which are not
+// retried by the Azure SDK, e.g. when a credential is not available in
+// the environment.
+type nonRetriable interface {
+	NonRetriable()
+}
+
+// classifyError classifies the errors returned by the Azure APIs according
+// to the HTTP status code of the response. Errors which are not retried by
+// the Azure SDK and not caused by the network are classified as invalid
+// configuration.
+func classifyError(err error) error {
+	var authErr *azidentity.AuthenticationFailedError
+	if errors.As(err, &authErr) && authErr.RawResponse != nil {
+		return auth.NewErrorFromHTTPStatus(authErr.RawResponse.StatusCode, err)
+	}
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		return auth.NewErrorFromHTTPStatus(respErr.StatusCode, err)
+	}
+
+	if classified := auth.ClassifyError(err); classified != err {
+		return classified
+	}
+
+	var nr nonRetriable
+	if errors.As(err, &nr) {
+		return auth.NewInvalidConfigurationError(err)
+	}
+	return err
+}
@@ -60,6 +60,7 @@ type mockImplementation struct {
 	firstCallMade   bool
 
 	returnToken       string
+	returnTokenErr    error
 	returnACRToken    string
 	returnCluster     armcontainerservice.ManagedCluster
 	returnKubeconfigs []*armcontainerservice.CredentialResult
@@ -71,6 +72,7 @@ type mockTokenCredential struct {
 	argScopes []string
 
 	returnToken string
+	returnErr   error
 }
 
 type mockAKSClient struct {
@@ -120,7 +122,7 @@ func (m *mockImplementation) newDefaultAzureCredential(options *azidentity.Defau
 		}
 	}
 
-	return &mockTokenCredential{t: m.t, argScopes: expectedScopes, returnToken: m.returnToken}, nil
+	return &mockTokenCredential{t: m.t, argScopes: expectedScopes, returnToken: m.returnToken, returnErr: m.returnTokenErr}, nil
 }
 
 func (m *mockImplementation) NewClientAssertionCredential(tenantID string, clientID string, getAssertion func(context.Context) (string, error), options *azidentity.ClientAssertionCredentialOptions) (azcore.TokenCredential, error) {
@@ -153,7 +155,7 @@ func (m *mockImplementation) NewClientAssertionCredential(tenantID string, clien
 		}
 	}
 
-	return &mockTokenCredential{t: m.t, argScopes: expectedScopes, returnToken: m.returnToken}, nil
+	return &mockTokenCredential{t: m.t, argScopes: expectedScopes, returnToken: m.returnToken, returnErr: m.returnTokenErr}, nil
 }
 
 func (m *mockImplementation) ExchangeAADAccessTokenForACRRefreshToken(ctx context.Context, client *azcontainerregistry.AuthenticationClient, grantType azcontainerregistry.PostContentSchemaGrantType, service string, options *azcontainerregistry.AuthenticationClientExchangeAADAccessTokenForACRRefreshTokenOptions) (azcontainerregistry.AuthenticationClientExchangeAADAccessTokenForACRRefreshTokenResponse, error) {
@@ -261,6 +263,9 @@ func (m *mockTokenCredential) GetToken(ctx context.Context, options policy.Token
 	m.t.Helper()
 	g := NewWithT(m.t)
 	g.Expect(options.Scopes).To(Equal(m.argScopes))
+	if m.returnErr != nil {
+		return azcore.AccessToken{}, m.returnErr
+	}
 	return azcore.AccessToken{
 		Token:     m.returnToken,
 		ExpiresOn: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), // Fixed expiry for testing
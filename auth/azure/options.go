@@ -18,12 +18,15 @@ package azure
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/fluxcd/pkg/auth"
 )
 
 func getIdentity(serviceAccount corev1.ServiceAccount) (string, error) {
@@ -43,7 +46,8 @@ func getTenantID(serviceAccount corev1.ServiceAccount) (string, error) {
 	if tenantID, ok := serviceAccount.Annotations[key]; ok {
 		return tenantID, nil
 	}
-	return "", fmt.Errorf("azure tenant ID is not set in the service account annotation %s", key)
+	return "", auth.NewInvalidConfigurationError(
+		fmt.Errorf("azure tenant ID is not set in the service account annotation %s", key))
 }
 
 func getClientID(serviceAccount corev1.ServiceAccount) (string, error) {
@@ -51,7 +55,8 @@ func getClientID(serviceAccount corev1.ServiceAccount) (string, error) {
 	if clientID, ok := serviceAccount.Annotations[key]; ok {
 		return clientID, nil
 	}
-	return "", fmt.Errorf("azure client ID is not set in the service account annotation %s", key)
+	return "", auth.NewInvalidConfigurationError(
+		fmt.Errorf("azure client ID is not set in the service account annotation %s", key))
 }
 
 const clusterPattern = `(?i)^/subscriptions/([^/]{36})/resourceGroups/([^/]{1,200})/providers/Microsoft\.ContainerService/managedClusters/([^/]{1,200})$`
@@ -61,8 +66,8 @@ var clusterRegex = regexp.MustCompile(clusterPattern)
 func parseCluster(cluster string) (string, string, string, error) {
 	m := clusterRegex.FindStringSubmatch(cluster)
 	if len(m) != 4 {
-		return "", "", "", fmt.Errorf("invalid AKS cluster ID: '%s'. must match %s",
-			cluster, clusterPattern)
+		return "", "", "", auth.NewInvalidConfigurationError(fmt.Errorf("invalid AKS cluster ID: '%s'. must match %s",
+			cluster, clusterPattern))
 	}
 	subscriptionID := m[1]
 	resourceGroup := m[2]
@@ -97,15 +102,16 @@ func hasEnvironmentFile() bool {
 func getEnvironmentConfig() (*Environment, error) {
 	envFilePath := os.Getenv(envVarAzureEnvironmentFilepath)
 	if len(envFilePath) == 0 {
-		return nil, fmt.Errorf("environment variable %s is not set", envVarAzureEnvironmentFilepath)
+		return nil, auth.NewInvalidConfigurationError(
+			fmt.Errorf("environment variable %s is not set", envVarAzureEnvironmentFilepath))
 	}
 	content, err := os.ReadFile(envFilePath)
 	if err != nil {
-		return nil, err
+		return nil, auth.NewInvalidConfigurationError(err)
 	}
 	env := &Environment{}
 	if err = json.Unmarshal(content, env); err != nil {
-		return nil, err
+		return nil, auth.NewInvalidConfigurationError(err)
 	}
 
 	return env, nil
@@ -127,7 +133,8 @@ func getCloudConfigFromEnvironment() (*cloud.Configuration, error) {
 			Audience: env.TokenAudience,
 		}
 	} else {
-		return nil, fmt.Errorf("resourceManagerEndpoint and tokenAudience must be set in the environment file")
+		return nil, auth.NewInvalidConfigurationError(
+			errors.New("resourceManagerEndpoint and tokenAudience must be set in the environment file"))
 	}
 
 	return &cloudConf, nil
@@ -141,7 +148,8 @@ func getContainerRegistryDNSSuffix() (string, error) {
 	}
 
 	if len(env.ContainerRegistryDNSSuffix) == 0 {
-		return "", fmt.Errorf("containerRegistryDNSSuffix must be set in the environment file")
+		return "", auth.NewInvalidConfigurationError(
+			errors.New("containerRegistryDNSSuffix must be set in the environment file"))
 	}
 
 	return env.ContainerRegistryDNSSuffix, nil
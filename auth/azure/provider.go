@@ -18,6 +18,7 @@ package azure
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -45,6 +46,10 @@ const ProviderName = "azure"
 // Provider implements the auth.Provider interface for Azure authentication.
 type Provider struct{ Implementation }
 
+func init() {
+	auth.MustRegister(Provider{})
+}
+
 // GetName implements auth.Provider.
 func (Provider) GetName() string {
 	return ProviderName
@@ -56,10 +61,16 @@ func (p Provider) NewControllerToken(ctx context.Context, opts ...auth.Option) (
 	var o auth.Options
 	o.Apply(opts...)
 
+	if o.OIDCTokenFile != "" {
+		return p.newControllerTokenFromOIDCTokenFile(ctx, &o)
+	}
+
+	clientOpts, err := clientOptions(&o)
+	if err != nil {
+		return nil, err
+	}
 	azOpts := azidentity.DefaultAzureCredentialOptions{
-		ClientOptions: azcore.ClientOptions{
-			Transport: o.GetHTTPClient(),
-		},
+		ClientOptions: clientOpts,
 	}
 
 	credFunc := p.impl().NewDefaultAzureCredentialWithoutShellOut
@@ -68,15 +79,60 @@ func (p Provider) NewControllerToken(ctx context.Context, opts ...auth.Option) (
 	}
 	cred, err := credFunc(&azOpts)
 	if err != nil {
-		return nil, err
+		return nil, auth.NewInvalidConfigurationError(err)
 	}
 	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
 		Scopes: o.Scopes,
 	})
 	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return &Token{token}, nil
+}
+
+// newControllerTokenFromOIDCTokenFile exchanges the OIDC token from the file
+// configured with auth.WithOIDCTokenFile for an Azure access token of the
+// application identified by the AZURE_TENANT_ID and AZURE_CLIENT_ID
+// environment variables.
+func (p Provider) newControllerTokenFromOIDCTokenFile(ctx context.Context, o *auth.Options) (auth.Token, error) {
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if tenantID == "" {
+		return nil, auth.NewInvalidConfigurationError(
+			errors.New("AZURE_TENANT_ID environment variable is required for authenticating with an OIDC token file"))
+	}
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	if clientID == "" {
+		return nil, auth.NewInvalidConfigurationError(
+			errors.New("AZURE_CLIENT_ID environment variable is required for authenticating with an OIDC token file"))
+	}
+
+	// Fail fast if the token file is unusable.
+	if _, err := o.ReadOIDCTokenFile(); err != nil {
 		return nil, err
 	}
 
+	clientOpts, err := clientOptions(o)
+	if err != nil {
+		return nil, err
+	}
+	azOpts := &azidentity.ClientAssertionCredentialOptions{
+		ClientOptions: clientOpts,
+	}
+
+	cred, err := p.impl().NewClientAssertionCredential(tenantID, clientID, func(context.Context) (string, error) {
+		return o.ReadOIDCTokenFile()
+	}, azOpts)
+	if err != nil {
+		return nil, auth.NewInvalidConfigurationError(err)
+	}
+	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: o.Scopes,
+	})
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
 	return &Token{token}, nil
 }
 
@@ -104,23 +160,25 @@ func (p Provider) NewTokenForServiceAccount(ctx context.Context, oidcToken strin
 	s := strings.Split(identity, "/")
 	tenantID, clientID := s[0], s[1]
 
+	clientOpts, err := clientOptions(&o)
+	if err != nil {
+		return nil, err
+	}
 	azOpts := &azidentity.ClientAssertionCredentialOptions{
-		ClientOptions: azcore.ClientOptions{
-			Transport: o.GetHTTPClient(),
-		},
+		ClientOptions: clientOpts,
 	}
 
 	cred, err := p.impl().NewClientAssertionCredential(tenantID, clientID, func(context.Context) (string, error) {
 		return oidcToken, nil
 	}, azOpts)
 	if err != nil {
-		return nil, err
+		return nil, auth.NewInvalidConfigurationError(err)
 	}
 	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
 		Scopes: o.Scopes,
 	})
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	return &Token{token}, nil
@@ -144,6 +202,12 @@ func (p Provider) GetAccessTokenOptionsForArtifactRepository(artifactRepository
 		if err != nil {
 			return nil, err
 		}
+	case os.Getenv(envVarAzureEnvironment) != "":
+		var err error
+		conf, err = getCloudConfig(&auth.Options{})
+		if err != nil {
+			return nil, err
+		}
 	case strings.HasSuffix(registry, ".azurecr.cn"):
 		conf = &cloud.AzureChina
 	case strings.HasSuffix(registry, ".azurecr.us"):
@@ -152,15 +216,45 @@ func (p Provider) GetAccessTokenOptionsForArtifactRepository(artifactRepository
 		conf = &cloud.AzurePublic
 	}
 
-	var acrScope string
-	if acrService, ok := conf.Services[azcontainerregistry.ServiceName]; ok {
-		acrScope = acrService.Audience + "/.default"
-	} else {
-		// Fallback for custom environments that don't define ACR service config.
-		acrScope = conf.Services[cloud.ResourceManager].Endpoint + "/.default"
+	// The scope is computed when the options are applied, so that the cloud
+	// configured with auth.WithAzureCloud takes precedence.
+	return []auth.Option{func(o *auth.Options) {
+		if o.AzureCloud != nil {
+			conf = o.AzureCloud
+		}
+		o.Scopes = []string{acrScope(conf)}
+	}}, nil
+}
+
+// GetAccessTokenOptionSetsForArtifactRepository implements auth.ArtifactRepositoryProvider.
+// A single access token with the scope of ACR is required.
+func (p Provider) GetAccessTokenOptionSetsForArtifactRepository(artifactRepository string, _ ...auth.Option) ([][]auth.Option, error) {
+	if _, err := p.ParseArtifactRepository(artifactRepository); err != nil {
+		// The registries of a custom environment cannot be told apart
+		// without a containerRegistryDNSSuffix, its scope applies to any
+		// registry.
+		if !hasEnvironmentFile() {
+			return nil, err
+		}
+		if _, suffixErr := getContainerRegistryDNSSuffix(); suffixErr == nil {
+			return nil, err
+		}
 	}
 
-	return []auth.Option{auth.WithScopes(acrScope)}, nil
+	opts, err := p.GetAccessTokenOptionsForArtifactRepository(artifactRepository)
+	if err != nil {
+		return nil, err
+	}
+	return [][]auth.Option{opts}, nil
+}
+
+// acrScope returns the scope of the access tokens for ACR in the given cloud.
+func acrScope(conf *cloud.Configuration) string {
+	if acrService, ok := conf.Services[azcontainerregistry.ServiceName]; ok {
+		return acrService.Audience + "/.default"
+	}
+	// Fallback for custom environments that don't define ACR service config.
+	return conf.Services[cloud.ResourceManager].Endpoint + "/.default"
 }
 
 // https://github.com/kubernetes/kubernetes/blob/v1.23.1/pkg/credentialprovider/azure/azure_credentials.go#L55
@@ -191,12 +285,18 @@ func (Provider) ParseArtifactRepository(artifactRepository string) (string, erro
 		if strings.HasSuffix(registry, registrySuffix) {
 			return registry, nil
 		}
-		return "", fmt.Errorf("invalid Azure registry: '%s'. must end with %s",
-			registry, registrySuffix)
+		return "", &auth.ErrUnsupportedRegistry{
+			Provider: ProviderName,
+			Registry: registry,
+			Err:      fmt.Errorf("invalid Azure registry: '%s'. must end with %s", registry, registrySuffix),
+		}
 	}
 
-	return "", fmt.Errorf("invalid Azure registry: '%s'. must match %s",
-		registry, registryPattern)
+	return "", &auth.ErrUnsupportedRegistry{
+		Provider: ProviderName,
+		Registry: registry,
+		Err:      fmt.Errorf("invalid Azure registry: '%s'. must match %s", registry, registryPattern),
+	}
 }
 
 // NewArtifactRegistryCredentials implements auth.Provider.
@@ -206,16 +306,30 @@ func (p Provider) NewArtifactRegistryCredentials(ctx context.Context, registry s
 	var o auth.Options
 	o.Apply(opts...)
 
+	// Make sure the registry belongs to the configured cloud, if any.
+	conf, err := getCloudConfig(&o)
+	if err != nil {
+		return nil, err
+	}
+	if conf != nil {
+		if suffix := containerRegistrySuffix(conf); suffix != "" && !strings.HasSuffix(registry, suffix) {
+			return nil, auth.NewInvalidConfigurationError(fmt.Errorf(
+				"invalid Azure registry: '%s'. must end with %s in the configured Azure cloud", registry, suffix))
+		}
+	}
+
 	// Create the ACR authentication client.
 	endpoint := fmt.Sprintf("https://%s", registry)
+	azOpts, err := clientOptions(&o)
+	if err != nil {
+		return nil, err
+	}
 	clientOpts := azcontainerregistry.AuthenticationClientOptions{
-		ClientOptions: azcore.ClientOptions{
-			Transport: o.GetHTTPClient(),
-		},
+		ClientOptions: azOpts,
 	}
 	client, err := azcontainerregistry.NewAuthenticationClient(endpoint, &clientOpts)
 	if err != nil {
-		return nil, err
+		return nil, auth.NewInvalidConfigurationError(err)
 	}
 
 	// Exchange the access token for an ACR token.
@@ -226,7 +340,7 @@ func (p Provider) NewArtifactRegistryCredentials(ctx context.Context, registry s
 	}
 	resp, err := p.impl().ExchangeAADAccessTokenForACRRefreshToken(ctx, client, grantType, service, tokenOpts)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 	token := *resp.RefreshToken
 
@@ -265,18 +379,9 @@ func (Provider) GetAccessTokenOptionsForCluster(opts ...auth.Option) ([][]auth.O
 
 	// Token needed for looking up details of the cluster resource.
 	if o.ClusterAddress == "" || o.CAData == "" {
-		conf := &cloud.AzurePublic
-		switch authorityHost := os.Getenv("AZURE_AUTHORITY_HOST"); {
-		case hasEnvironmentFile():
-			var err error
-			conf, err = getCloudConfigFromEnvironment()
-			if err != nil {
-				return nil, err
-			}
-		case strings.Contains(authorityHost, "chinacloudapi.cn"):
-			conf = &cloud.AzureChina
-		case strings.Contains(authorityHost, "microsoftonline.us"):
-			conf = &cloud.AzureGovernment
+		conf, err := getARMCloudConfig(&o)
+		if err != nil {
+			return nil, err
 		}
 		armScope := conf.Services[cloud.ResourceManager].Audience + "/.default"
 		armTokenOpts := []auth.Option{auth.WithScopes(armScope)}
@@ -310,29 +415,37 @@ func (p Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 			return nil, err
 		}
 
-		// Create client for describing the cluster resource.
+		// Create client for describing the cluster resource,
+		// with the Resource Manager endpoint of the cloud.
+		conf, err := getARMCloudConfig(&o)
+		if err != nil {
+			return nil, err
+		}
 		clientOpts := arm.ClientOptions{
 			ClientOptions: azcore.ClientOptions{
 				Transport: o.GetHTTPClient(),
+				Cloud:     *conf,
 			},
 		}
 		client, err := p.impl().NewManagedClustersClient(
 			subscriptionID, armToken.credential(), &clientOpts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create client for describing AKS cluster: %w", err)
+			return nil, auth.NewInvalidConfigurationError(
+				fmt.Errorf("failed to create client for describing AKS cluster: %w", err))
 		}
 
 		// Describe the cluster resource.
 		clusterResource, err := client.Get(ctx, resourceGroup, clusterName, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to describe AKS cluster: %w", err)
+			return nil, fmt.Errorf("failed to describe AKS cluster: %w", classifyError(err))
 		}
 
 		// We only support clusters with Microsoft Entra ID integration enabled.
 		if clusterResource.Properties.AADProfile == nil {
-			return nil, fmt.Errorf("AKS cluster %s does not have Microsoft Entra ID integration enabled. "+
-				"See docs for enabling: https://learn.microsoft.com/en-us/azure/aks/enable-authentication-microsoft-entra-id",
-				cluster)
+			return nil, auth.NewInvalidConfigurationError(fmt.Errorf(
+				"AKS cluster %s does not have Microsoft Entra ID integration enabled. "+
+					"See docs for enabling: https://learn.microsoft.com/en-us/azure/aks/enable-authentication-microsoft-entra-id",
+				cluster))
 		}
 
 		// Parse specified cluster address.
@@ -350,7 +463,7 @@ func (p Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 		// is specified.
 		resp, err := client.ListClusterUserCredentials(ctx, resourceGroup, clusterName, nil)
 		if err != nil {
-			return nil, err
+			return nil, classifyError(err)
 		}
 		var restConfig *rest.Config
 		var addresses []string
@@ -371,10 +484,12 @@ func (p Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 		}
 		if restConfig == nil {
 			if canonicalHost == "" {
-				return nil, fmt.Errorf("no kubeconfig found for AKS cluster %s", cluster)
+				return nil, auth.NewInvalidConfigurationError(
+					fmt.Errorf("no kubeconfig found for AKS cluster %s", cluster))
 			}
-			return nil, fmt.Errorf("no kubeconfig found for AKS cluster %s matching the specified address '%s'. cluster addresses: [%s]",
-				cluster, o.ClusterAddress, strings.Join(addresses, ", "))
+			return nil, auth.NewInvalidConfigurationError(fmt.Errorf(
+				"no kubeconfig found for AKS cluster %s matching the specified address '%s'. cluster addresses: [%s]",
+				cluster, o.ClusterAddress, strings.Join(addresses, ", ")))
 		}
 
 		// Update host and CA with cluster details.
@@ -20,13 +20,19 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -83,6 +89,78 @@ func TestProvider_NewControllerToken(t *testing.T) {
 	}
 }
 
+func TestProvider_NewControllerToken_OIDCTokenFile(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		exp     time.Time
+		skipEnv bool
+		err     string
+	}{
+		{
+			name: "valid",
+			exp:  time.Now().Add(time.Hour),
+		},
+		{
+			name: "expired token",
+			exp:  time.Now().Add(-time.Hour),
+			err:  "expired at",
+		},
+		{
+			name:    "missing tenant id",
+			exp:     time.Now().Add(time.Hour),
+			skipEnv: true,
+			err:     "AZURE_TENANT_ID environment variable is required for authenticating with an OIDC token file",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			if !tt.skipEnv {
+				t.Setenv("AZURE_TENANT_ID", "tenant-id")
+				t.Setenv("AZURE_CLIENT_ID", "client-id")
+			}
+
+			oidcToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"exp": tt.exp.Unix(),
+			}).SignedString([]byte("secret"))
+			g.Expect(err).NotTo(HaveOccurred())
+			path := filepath.Join(t.TempDir(), "token")
+			g.Expect(os.WriteFile(path, []byte(oidcToken), 0o600)).To(Succeed())
+
+			impl := &mockImplementation{
+				t:            t,
+				argTenantID:  "tenant-id",
+				argClientID:  "client-id",
+				argOIDCToken: oidcToken,
+				argProxyURL:  &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				argScopes:    []string{"scope1", "scope2"},
+				returnToken:  "access-token",
+			}
+
+			opts := []auth.Option{
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+				auth.WithScopes("scope1", "scope2"),
+				auth.WithOIDCTokenFile(path),
+			}
+
+			provider := azure.Provider{Implementation: impl}
+			token, err := provider.NewControllerToken(context.Background(), opts...)
+
+			if tt.err == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(token).To(Equal(&azure.Token{AccessToken: azcore.AccessToken{
+					Token:     "access-token",
+					ExpiresOn: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				}}))
+			} else {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				g.Expect(token).To(BeNil())
+			}
+		})
+	}
+}
+
 func TestProvider_NewTokenForServiceAccount(t *testing.T) {
 	for _, tt := range []struct {
 		name        string
@@ -153,6 +231,101 @@ func TestProvider_NewTokenForServiceAccount(t *testing.T) {
 	}
 }
 
+func TestProvider_NewTokenForServiceAccount_ErrorClassification(t *testing.T) {
+	newResponse := func(code int) *http.Response {
+		return &http.Response{StatusCode: code, Status: http.StatusText(code), Body: http.NoBody}
+	}
+
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		tokenErr    error
+		wantErr     error
+		retryable   bool
+	}{
+		{
+			name: "missing annotation",
+			annotations: map[string]string{
+				"azure.workload.identity/tenant-id": "tenant-id",
+			},
+			wantErr: auth.ErrInvalidConfiguration,
+		},
+		{
+			name:     "no matching federated identity",
+			tokenErr: &azidentity.AuthenticationFailedError{RawResponse: newResponse(http.StatusBadRequest)},
+			wantErr:  auth.ErrInvalidConfiguration,
+		},
+		{
+			name:     "unauthorized client",
+			tokenErr: &azidentity.AuthenticationFailedError{RawResponse: newResponse(http.StatusUnauthorized)},
+			wantErr:  auth.ErrPermissionDenied,
+		},
+		{
+			name:      "throttling",
+			tokenErr:  &azcore.ResponseError{StatusCode: http.StatusTooManyRequests, RawResponse: newResponse(http.StatusTooManyRequests)},
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:      "server error",
+			tokenErr:  &azidentity.AuthenticationFailedError{RawResponse: newResponse(http.StatusServiceUnavailable)},
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:      "network error",
+			tokenErr:  &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNRESET},
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:     "credential unavailable",
+			tokenErr: azidentity.NewCredentialUnavailableError("no credential configured"),
+			wantErr:  auth.ErrInvalidConfiguration,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			annotations := tt.annotations
+			if annotations == nil {
+				annotations = map[string]string{
+					"azure.workload.identity/tenant-id": "tenant-id",
+					"azure.workload.identity/client-id": "client-id",
+				}
+			}
+
+			impl := &mockImplementation{
+				t:              t,
+				argTenantID:    "tenant-id",
+				argClientID:    "client-id",
+				argOIDCToken:   "oidc-token",
+				argProxyURL:    &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				argScopes:      []string{"scope1"},
+				returnTokenErr: tt.tokenErr,
+			}
+
+			serviceAccount := corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+				},
+			}
+
+			provider := azure.Provider{Implementation: impl}
+			_, err := provider.NewTokenForServiceAccount(context.Background(), "oidc-token", serviceAccount,
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+				auth.WithScopes("scope1"))
+
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err).To(MatchError(tt.wantErr))
+			if tt.tokenErr != nil {
+				g.Expect(err).To(MatchError(tt.tokenErr))
+			}
+			g.Expect(auth.IsRetryable(err)).To(Equal(tt.retryable))
+		})
+	}
+}
+
 func TestProvider_GetAudiences(t *testing.T) {
 	g := NewWithT(t)
 	aud, err := azure.Provider{}.GetAudiences(context.Background(), corev1.ServiceAccount{})
@@ -374,6 +547,111 @@ func TestProvider_GetAccessTokenOptionsForArtifactRepository(t *testing.T) {
 	}
 }
 
+func TestProvider_GetAccessTokenOptionSetsForArtifactRepository(t *testing.T) {
+	for _, tt := range []struct {
+		name                       string
+		artifactRepository         string
+		readFromEnv                bool
+		containerRegistryDNSSuffix string
+		expectedScope              string
+		unsupported                bool
+		malformed                  bool
+	}{
+		{
+			name:               "Azure Public Cloud",
+			artifactRepository: "myregistry.azurecr.io",
+			expectedScope:      "https://containerregistry.azure.net/.default",
+		},
+		{
+			name:               "Azure China Cloud",
+			artifactRepository: "myregistry.azurecr.cn/foo/bar:v1",
+			expectedScope:      "https://containerregistry.azure.net/.default",
+		},
+		{
+			name:               "Azure Government Cloud",
+			artifactRepository: "myregistry.azurecr.us/foo",
+			expectedScope:      "https://containerregistry.azure.net/.default",
+		},
+		{
+			name:               "Custom environment file",
+			artifactRepository: "myregistry.private.io",
+			readFromEnv:        true,
+			expectedScope:      "https://management.core.azure.private/.default",
+		},
+		{
+			name:                       "registry within the environment file suffix",
+			artifactRepository:         "myregistry.private.io",
+			containerRegistryDNSSuffix: "private.io",
+			expectedScope:              "https://management.core.azure.private/.default",
+		},
+		{
+			name:                       "registry outside of the environment file suffix",
+			artifactRepository:         "myregistry.other.io",
+			containerRegistryDNSSuffix: "private.io",
+			unsupported:                true,
+		},
+		{
+			name:               "Invalid registry",
+			artifactRepository: "myregistry.invalid.io",
+			unsupported:        true,
+		},
+		{
+			name:               "ECR registry",
+			artifactRepository: "012345678901.dkr.ecr.us-east-1.amazonaws.com/foo",
+			unsupported:        true,
+		},
+		{
+			name:               "empty registry name",
+			artifactRepository: ".azurecr.io/repo",
+			unsupported:        true,
+		},
+		{
+			name:               "malformed repository",
+			artifactRepository: "myregistry.azurecr.io/foo:v1:v2",
+			malformed:          true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			if tt.readFromEnv || tt.containerRegistryDNSSuffix != "" {
+				envContent := fmt.Sprintf(`{"resourceManagerEndpoint": "%s", "tokenAudience": "%s", "extraField": "%s"}`, "https://management.core.azure.private", "https://management.core.azure.private", "random-extra-field-for-testing")
+				if tt.containerRegistryDNSSuffix != "" {
+					envContent = fmt.Sprintf(`{"resourceManagerEndpoint": "%s", "tokenAudience": "%s", "containerRegistryDNSSuffix": "%s"}`,
+						"https://management.core.azure.private", "https://management.core.azure.private", tt.containerRegistryDNSSuffix)
+				}
+				tempFileName, err := createTempAzureEnvFile(envContent)
+				g.Expect(err).NotTo(HaveOccurred())
+				defer os.Remove(tempFileName)
+
+				// Set the environment variable to point to the temp file
+				t.Setenv("AZURE_ENVIRONMENT_FILEPATH", tempFileName)
+			}
+
+			provider := azure.Provider{}
+			opts, err := provider.GetAccessTokenOptionSetsForArtifactRepository(tt.artifactRepository)
+
+			var unsupportedErr *auth.ErrUnsupportedRegistry
+			switch {
+			case tt.unsupported:
+				g.Expect(errors.As(err, &unsupportedErr)).To(BeTrue())
+				g.Expect(unsupportedErr.Provider).To(Equal(azure.ProviderName))
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+			case tt.malformed:
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				g.Expect(errors.As(err, &unsupportedErr)).To(BeFalse())
+			default:
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(opts).To(HaveLen(1))
+
+				var armOptions auth.Options
+				armOptions.Apply(opts[0]...)
+				g.Expect(armOptions.Scopes).To(Equal([]string{tt.expectedScope}))
+			}
+		})
+	}
+}
+
 func TestProvider_NewRESTConfig(t *testing.T) {
 	for _, tt := range []struct {
 		name           string
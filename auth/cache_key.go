@@ -18,12 +18,71 @@ package auth
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/fluxcd/pkg/cache"
 )
 
+// CacheKey returns the key under which GetAccessToken caches the access
+// tokens of the given provider, e.g. for pre-warming the cache or for
+// invalidating the token of a specific identity. The key is a stable SHA-256
+// hash, it never contains the raw value of the options.
+//
+// Only the options identifying the token participate in the key:
+//
+//   - WithAudiences, which must be the audiences the provider resolves for
+//     the service account when they are not set explicitly
+//   - WithScopes
+//   - WithSTSRegion, WithSTSEndpoint and WithAWSRegion
+//   - WithProxyURL
+//   - WithCAData
+//   - WithOIDCTokenFile
+//   - WithAzureCloud, by its authority host
+//   - WithServiceAccountName and WithServiceAccountNamespace, the name
+//     falling back to the default service account if configured
+//   - WithProviderIdentity, for service account tokens only
+//
+// All the other options, e.g. WithClient or WithCache, do not change the
+// key, and neither does the order of the options.
+func CacheKey(provider string, opts ...Option) (string, error) {
+	if provider == "" {
+		return "", errors.New("provider name is required")
+	}
+
+	var o Options
+	o.Apply(opts...)
+
+	var serviceAccount *corev1.ServiceAccount
+	if o.ShouldGetServiceAccountToken() {
+		serviceAccount = &corev1.ServiceAccount{}
+		serviceAccount.Name = o.ServiceAccountName
+		serviceAccount.Namespace = o.ServiceAccountNamespace
+		if serviceAccount.Name == "" {
+			serviceAccount.Name = getDefaultServiceAccount()
+		}
+	} else if o.ProviderIdentity != "" {
+		return "", errors.New("provider identity requires a service account namespace")
+	}
+
+	return buildAccessTokenCacheKey(provider, o.Audiences, o.ProviderIdentity, serviceAccount, opts...), nil
+}
+
 func buildCacheKey(parts ...string) string {
 	s := strings.Join(parts, "\n")
 	hash := sha256.Sum256([]byte(s))
 	return fmt.Sprintf("%x", hash)
 }
+
+// cacheMetadata returns the cache option recording the provider and the
+// service account of a cached token, for the token cache introspection.
+func cacheMetadata(provider Provider, serviceAccount *corev1.ServiceAccount) cache.Options {
+	var sa string
+	if serviceAccount != nil {
+		sa = serviceAccount.Namespace + "/" + serviceAccount.Name
+	}
+	return cache.WithTokenMetadata(provider.GetName(), sa)
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/auth"
+	"github.com/fluxcd/pkg/cache"
+)
+
+// identityOptions returns an option for every identity option of CacheKey.
+func identityOptions() []auth.Option {
+	return []auth.Option{
+		auth.WithAudiences("audience1", "audience2"),
+		auth.WithScopes("scope1", "scope2"),
+		auth.WithSTSRegion("us-east-1"),
+		auth.WithSTSEndpoint("https://sts.some-cloud.io"),
+		auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.io:8080"}),
+		auth.WithCAData("ca-data"),
+		auth.WithOIDCTokenFile("/var/run/secrets/oidc/token"),
+		auth.WithServiceAccountName("test-sa"),
+		auth.WithServiceAccountNamespace("test-ns"),
+		auth.WithProviderIdentity("test-identity"),
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	// The keys are pinned, a change in the way the keys are computed would
+	// invalidate all the cached tokens and break the external invalidation.
+	for _, tt := range []struct {
+		name     string
+		provider string
+		opts     []auth.Option
+		key      string
+		err      string
+	}{
+		{
+			name:     "controller token",
+			provider: "aws",
+			key:      "81f19deba9f1fc37fc5378888af763067ba938bffedfbdfb767ac5283e5a970b",
+		},
+		{
+			name:     "controller token with options",
+			provider: "aws",
+			opts:     identityOptions()[:7],
+			key:      "18225841248a390e77f38db4961c2ff1605f9abfb282808c58203d94896dc9c4",
+		},
+		{
+			name:     "service account token",
+			provider: "aws",
+			opts:     identityOptions(),
+			key:      "735a4ac124dcd27d7581e362aeb00088cd7e89c6bae7fb26186bba87d4d008f2",
+		},
+		{
+			name: "missing provider",
+			err:  "provider name is required",
+		},
+		{
+			name:     "provider identity without service account",
+			provider: "aws",
+			opts:     []auth.Option{auth.WithProviderIdentity("test-identity")},
+			err:      "provider identity requires a service account namespace",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			key, err := auth.CacheKey(tt.provider, tt.opts...)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(tt.err))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(key).To(Equal(tt.key))
+		})
+	}
+}
+
+func TestCacheKey_NonIdentityOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	tokenCache, err := cache.NewTokenCache(1)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	key, err := auth.CacheKey("aws", identityOptions()...)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Adding options which do not identify the token must not change the key.
+	opts := append(identityOptions(),
+		auth.WithClient(fake.NewClientBuilder().Build()),
+		auth.WithCache(*tokenCache, cache.InvolvedObject{Kind: "test", Name: "test", Namespace: "test"}),
+		auth.WithGitURL(url.URL{Scheme: "https", Host: "github.com", Path: "/org/repo"}),
+		auth.WithClusterResource("projects/p/locations/l/clusters/c"),
+		auth.WithClusterAddress("https://cluster.io"),
+		auth.WithAllowShellOut(),
+		auth.WithAllowedServiceAccountNamespaces([]string{"test-ns"}),
+	)
+	keyWithOpts, err := auth.CacheKey("aws", opts...)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(keyWithOpts).To(Equal(key))
+
+	// Every identity option changes the key.
+	for i := range identityOptions() {
+		opts := identityOptions()
+		opts = append(opts[:i], opts[i+1:]...)
+		otherKey, err := auth.CacheKey("aws", opts...)
+		if err != nil {
+			continue
+		}
+		g.Expect(otherKey).NotTo(Equal(key), "option %d does not change the key", i)
+	}
+}
+
+func TestCacheKey_AzureCloud(t *testing.T) {
+	g := NewWithT(t)
+
+	key, err := auth.CacheKey("azure", identityOptions()...)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The tokens of different clouds are cached separately.
+	chinaKey, err := auth.CacheKey("azure", append(identityOptions(), auth.WithAzureCloud(cloud.AzureChina))...)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(chinaKey).NotTo(Equal(key))
+
+	governmentKey, err := auth.CacheKey("azure", append(identityOptions(), auth.WithAzureCloud(cloud.AzureGovernment))...)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(governmentKey).NotTo(Equal(chinaKey))
+}
+
+func TestCacheKey_AWSRegion(t *testing.T) {
+	g := NewWithT(t)
+
+	key, err := auth.CacheKey("aws", identityOptions()...)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The region override takes precedence over the STS region, so the
+	// tokens of different overrides are cached separately.
+	overrideKey, err := auth.CacheKey("aws", append(identityOptions(), auth.WithAWSRegion("eu-west-1"))...)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(overrideKey).NotTo(Equal(key))
+}
+
+func TestCacheKey_NoSecretMaterial(t *testing.T) {
+	g := NewWithT(t)
+
+	const secret = "-----BEGIN CERTIFICATE-----secret-ca-data"
+	key, err := auth.CacheKey("aws", auth.WithCAData(secret),
+		auth.WithProxyURL(url.URL{Scheme: "http", User: url.UserPassword("user", "password"), Host: "proxy.io"}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(key).To(MatchRegexp(`^[a-f0-9]{64}$`))
+	g.Expect(key).NotTo(ContainSubstring("secret"))
+	g.Expect(key).NotTo(ContainSubstring("password"))
+}
+
+func TestCacheKey_PreWarm(t *testing.T) {
+	ctx := context.Background()
+
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sa",
+			Namespace: "test-ns",
+		},
+	}
+	kubeClient := fake.NewClientBuilder().WithObjects(serviceAccount).Build()
+	g := NewWithT(t)
+	g.Expect(kubeClient.Get(ctx, client.ObjectKeyFromObject(serviceAccount), serviceAccount)).To(Succeed())
+
+	for _, tt := range []struct {
+		name     string
+		opts     []auth.Option
+		keyOpts  []auth.Option
+		provider *mockProvider
+	}{
+		{
+			name: "controller token",
+			opts: []auth.Option{
+				auth.WithAudiences("audience"),
+				auth.WithSTSRegion("us-east-1"),
+			},
+			provider: &mockProvider{returnName: "mock-provider"},
+		},
+		{
+			name: "service account token",
+			opts: []auth.Option{
+				auth.WithClient(kubeClient),
+				auth.WithServiceAccountName(serviceAccount.Name),
+				auth.WithServiceAccountNamespace(serviceAccount.Namespace),
+				auth.WithAllowedServiceAccountNamespaces([]string{serviceAccount.Namespace}),
+			},
+			// The audiences and identity resolved by the provider.
+			keyOpts: []auth.Option{
+				auth.WithAudiences("mock-audience"),
+				auth.WithProviderIdentity("mock-identity"),
+			},
+			provider: &mockProvider{
+				returnName:          "mock-provider",
+				returnIdentity:      "mock-identity",
+				paramServiceAccount: *serviceAccount,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tt.provider.t = t
+
+			tokenCache, err := cache.NewTokenCache(1)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// Pre-warm the cache with the key computed by CacheKey.
+			key, err := auth.CacheKey(tt.provider.GetName(), append(tt.opts, tt.keyOpts...)...)
+			g.Expect(err).NotTo(HaveOccurred())
+			cached := &mockToken{token: "pre-warmed"}
+			_, _, err = tokenCache.GetOrSet(ctx, key, func(context.Context) (cache.Token, error) {
+				return cached, nil
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			opts := append(tt.opts, auth.WithCache(*tokenCache, cache.InvolvedObject{
+				Kind:      "test",
+				Name:      "test",
+				Namespace: "test",
+			}))
+			token, err := auth.GetAccessToken(ctx, tt.provider, opts...)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(token).To(BeIdenticalTo(cached))
+		})
+	}
+}
+
+func FuzzCacheKey_OptionOrder(f *testing.F) {
+	f.Add(uint64(0), "aws")
+	f.Add(uint64(42), "azure")
+	f.Add(uint64(1<<63), "gcp")
+
+	f.Fuzz(func(t *testing.T, seed uint64, provider string) {
+		if provider == "" {
+			t.Skip()
+		}
+
+		opts := identityOptions()
+		key, err := auth.CacheKey(provider, opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rng := rand.New(rand.NewPCG(seed, seed))
+		rng.Shuffle(len(opts), func(i, j int) { opts[i], opts[j] = opts[j], opts[i] })
+		shuffled, err := auth.CacheKey(provider, opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shuffled != key {
+			t.Fatalf("key changed with the order of the options: %s != %s", shuffled, key)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// These errors classify the failures of acquiring credentials. They are
+// never returned directly, the returned errors wrap one of them together
+// with the underlying error, so they must be checked with errors.Is.
+var (
+	// ErrInvalidConfiguration classifies failures caused by an invalid or
+	// missing configuration, e.g. an invalid role ARN, a missing service
+	// account annotation or a missing environment variable. Retrying the
+	// operation without changing the configuration will fail again.
+	ErrInvalidConfiguration = errors.New("invalid configuration")
+
+	// ErrPermissionDenied classifies failures caused by the identity not
+	// being allowed to perform the operation, e.g. a missing trust
+	// relationship or role binding. Retrying the operation without changing
+	// the permissions will fail again.
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrTransient classifies failures which may succeed on retry, e.g.
+	// throttling, server errors or network errors.
+	ErrTransient = errors.New("transient failure")
+)
+
+// classifiedError wraps an error with one of the classification errors
+// while keeping the message of the underlying error.
+type classifiedError struct {
+	class error
+	err   error
+}
+
+// Error implements error.
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the classification and the underlying error.
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+// NewInvalidConfigurationError classifies the given error as ErrInvalidConfiguration.
+// Errors which are already classified are returned unchanged.
+func NewInvalidConfigurationError(err error) error {
+	return classify(ErrInvalidConfiguration, err)
+}
+
+// NewPermissionDeniedError classifies the given error as ErrPermissionDenied.
+// Errors which are already classified are returned unchanged.
+func NewPermissionDeniedError(err error) error {
+	return classify(ErrPermissionDenied, err)
+}
+
+// NewTransientError classifies the given error as ErrTransient.
+// Errors which are already classified are returned unchanged.
+func NewTransientError(err error) error {
+	return classify(ErrTransient, err)
+}
+
+// NewErrorFromHTTPStatus classifies the given error according to the HTTP
+// status code of the failed request: 401 and 403 as ErrPermissionDenied,
+// other 4xx except 408 and 429 as ErrInvalidConfiguration, and 408, 429
+// and 5xx as ErrTransient. Errors which are already classified, or with
+// another status code, are returned unchanged.
+func NewErrorFromHTTPStatus(statusCode int, err error) error {
+	switch {
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return NewPermissionDeniedError(err)
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooManyRequests,
+		statusCode >= http.StatusInternalServerError && statusCode < 600:
+		return NewTransientError(err)
+	case statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError:
+		return NewInvalidConfigurationError(err)
+	default:
+		return err
+	}
+}
+
+// ClassifyError classifies the given error by looking for a Kubernetes API
+// status or a network failure in its chain. Errors which are already
+// classified, or which can't be classified, are returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil || isClassified(err) {
+		return err
+	}
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		switch {
+		case apierrors.IsNotFound(err):
+			return NewInvalidConfigurationError(err)
+		case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsTooManyRequests(err),
+			apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err):
+			return NewTransientError(err)
+		}
+		return NewErrorFromHTTPStatus(int(status.Status().Code), err)
+	}
+
+	if isCertificateError(err) {
+		return NewInvalidConfigurationError(err)
+	}
+	if isNetworkError(err) {
+		return NewTransientError(err)
+	}
+	return err
+}
+
+// IsRetryable returns true if the operation that failed with the given error
+// may succeed on retry. Errors classified as ErrTransient and errors which
+// are not classified are considered retryable, while errors classified as
+// ErrInvalidConfiguration or ErrPermissionDenied are not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrTransient) {
+		return true
+	}
+	return !errors.Is(err, ErrInvalidConfiguration) && !errors.Is(err, ErrPermissionDenied)
+}
+
+func classify(class, err error) error {
+	if err == nil || isClassified(err) {
+		return err
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+func isClassified(err error) bool {
+	return errors.Is(err, ErrInvalidConfiguration) ||
+		errors.Is(err, ErrPermissionDenied) ||
+		errors.Is(err, ErrTransient)
+}
+
+// isCertificateError returns true if the error was caused by the verification
+// of a TLS certificate, which usually requires a CA to be configured.
+func isCertificateError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &certErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
+
+// isNetworkError returns true if the error was caused by the network,
+// e.g. a timeout, a refused or reset connection or an unexpected EOF.
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/fluxcd/pkg/auth"
+)
+
+func TestClassifyError(t *testing.T) {
+	gr := schema.GroupResource{Resource: "serviceaccounts"}
+
+	for _, tt := range []struct {
+		name      string
+		err       error
+		wantErr   error
+		retryable bool
+	}{
+		{
+			name:      "nil",
+			err:       nil,
+			retryable: false,
+		},
+		{
+			name:      "unclassified",
+			err:       errors.New("something went wrong"),
+			retryable: true,
+		},
+		{
+			name:    "forbidden",
+			err:     apierrors.NewForbidden(gr, "default", errors.New("cannot create resource")),
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name:    "unauthorized",
+			err:     apierrors.NewUnauthorized("invalid bearer token"),
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name:    "not found",
+			err:     apierrors.NewNotFound(gr, "default"),
+			wantErr: auth.ErrInvalidConfiguration,
+		},
+		{
+			name:    "bad request",
+			err:     apierrors.NewBadRequest("invalid audience"),
+			wantErr: auth.ErrInvalidConfiguration,
+		},
+		{
+			name:      "internal error",
+			err:       apierrors.NewInternalError(errors.New("etcdserver: leader changed")),
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:      "too many requests",
+			err:       apierrors.NewTooManyRequests("rate limited", 1),
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:      "server timeout",
+			err:       apierrors.NewServerTimeout(gr, "create", 1),
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:      "connection refused",
+			err:       fmt.Errorf("failed to call API: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}),
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:      "deadline exceeded",
+			err:       fmt.Errorf("failed to call API: %w", context.DeadlineExceeded),
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:    "unknown certificate authority",
+			err:     fmt.Errorf("failed to call API: %w", x509.UnknownAuthorityError{}),
+			wantErr: auth.ErrInvalidConfiguration,
+		},
+		{
+			name:    "already classified",
+			err:     auth.NewPermissionDeniedError(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNRESET}),
+			wantErr: auth.ErrPermissionDenied,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := auth.ClassifyError(tt.err)
+			if tt.err == nil {
+				g.Expect(err).To(BeNil())
+			} else {
+				g.Expect(err).To(MatchError(tt.err))
+				g.Expect(err.Error()).To(Equal(tt.err.Error()))
+			}
+			if tt.wantErr != nil {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			}
+			g.Expect(auth.IsRetryable(err)).To(Equal(tt.retryable))
+		})
+	}
+}
+
+func TestNewErrorFromHTTPStatus(t *testing.T) {
+	for _, tt := range []struct {
+		code    int
+		wantErr error
+	}{
+		{code: http.StatusBadRequest, wantErr: auth.ErrInvalidConfiguration},
+		{code: http.StatusUnauthorized, wantErr: auth.ErrPermissionDenied},
+		{code: http.StatusForbidden, wantErr: auth.ErrPermissionDenied},
+		{code: http.StatusNotFound, wantErr: auth.ErrInvalidConfiguration},
+		{code: http.StatusRequestTimeout, wantErr: auth.ErrTransient},
+		{code: http.StatusTooManyRequests, wantErr: auth.ErrTransient},
+		{code: http.StatusInternalServerError, wantErr: auth.ErrTransient},
+		{code: http.StatusBadGateway, wantErr: auth.ErrTransient},
+		{code: http.StatusOK},
+		{code: 0},
+	} {
+		t.Run(fmt.Sprint(tt.code), func(t *testing.T) {
+			g := NewWithT(t)
+
+			underlying := errors.New("request failed")
+			err := auth.NewErrorFromHTTPStatus(tt.code, underlying)
+			g.Expect(err).To(MatchError(underlying))
+			if tt.wantErr == nil {
+				g.Expect(err).To(Equal(underlying))
+			} else {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			}
+		})
+	}
+}
+
+func TestIsRetryable_Wrapped(t *testing.T) {
+	g := NewWithT(t)
+
+	err := fmt.Errorf("failed to get access token: %w",
+		auth.NewInvalidConfigurationError(errors.New("invalid role ARN")))
+	g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+	g.Expect(err.Error()).To(Equal("failed to get access token: invalid role ARN"))
+	g.Expect(auth.IsRetryable(err)).To(BeFalse())
+}
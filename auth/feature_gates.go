@@ -18,6 +18,7 @@ package auth
 
 import (
 	"fmt"
+	"slices"
 )
 
 // FeatureGateObjectLevelWorkloadIdentity is a feature gate that enables the use of
@@ -29,6 +30,33 @@ const FeatureGateObjectLevelWorkloadIdentity = "ObjectLevelWorkloadIdentity"
 var ErrObjectLevelWorkloadIdentityNotEnabled = fmt.Errorf(
 	"%s feature gate is not enabled", FeatureGateObjectLevelWorkloadIdentity)
 
+// ErrObjectLevelWIDisabled is returned when a token is requested for a
+// service account while object-level workload identity is disabled and
+// the namespace of the service account is not allowed. It matches
+// ErrObjectLevelWorkloadIdentityNotEnabled with errors.Is.
+type ErrObjectLevelWIDisabled struct {
+	// Namespace is the namespace of the service account.
+	Namespace string
+}
+
+// Error implements error.
+func (e *ErrObjectLevelWIDisabled) Error() string {
+	return fmt.Sprintf("%s and the namespace '%s' is not allowed to use object-level workload identity",
+		ErrObjectLevelWorkloadIdentityNotEnabled, e.Namespace)
+}
+
+// Is returns true for ErrObjectLevelWorkloadIdentityNotEnabled.
+func (e *ErrObjectLevelWIDisabled) Is(target error) bool {
+	return target == ErrObjectLevelWorkloadIdentityNotEnabled
+}
+
+// isObjectLevelWorkloadIdentityAllowed returns true if object-level workload
+// identity is enabled, or if the given namespace is allowed by the options.
+func isObjectLevelWorkloadIdentityAllowed(o *Options, namespace string) bool {
+	return IsObjectLevelWorkloadIdentityEnabled() ||
+		slices.Contains(o.AllowedServiceAccountNamespaces, namespace)
+}
+
 // SetFeatureGates sets the default values for the feature gates.
 func SetFeatureGates(features map[string]bool) {
 	// opt-in from Flux v2.6.
@@ -39,16 +67,24 @@ func SetFeatureGates(features map[string]bool) {
 // identity for authentication.
 var enableObjectLevelWorkloadIdentity bool
 
+// SetObjectLevelWorkloadIdentityEnabled enables or disables the use of
+// object-level workload identity for authentication. When disabled, only
+// the service accounts in the namespaces allowed with the
+// WithAllowedServiceAccountNamespaces option can be used.
+func SetObjectLevelWorkloadIdentityEnabled(enabled bool) {
+	enableObjectLevelWorkloadIdentity = enabled
+}
+
 // EnableObjectLevelWorkloadIdentity enables the use of object-level workload
 // identity for authentication.
 func EnableObjectLevelWorkloadIdentity() {
-	enableObjectLevelWorkloadIdentity = true
+	SetObjectLevelWorkloadIdentityEnabled(true)
 }
 
 // DisableObjectLevelWorkloadIdentity disables the use of object-level workload
 // identity for authentication.
 func DisableObjectLevelWorkloadIdentity() {
-	enableObjectLevelWorkloadIdentity = false
+	SetObjectLevelWorkloadIdentityEnabled(false)
 }
 
 // IsObjectLevelWorkloadIdentityEnabled returns true if the object-level
@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"errors"
+
+	"cloud.google.com/go/compute/metadata"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+
+	auth "github.com/fluxcd/pkg/auth"
+)
+
+// classifyError classifies the errors returned by the GCP APIs and by the
+// metadata service according to the HTTP status code of the response.
+func classifyError(err error) error {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) && retrieveErr.Response != nil {
+		return auth.NewErrorFromHTTPStatus(retrieveErr.Response.StatusCode, err)
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return auth.NewErrorFromHTTPStatus(apiErr.Code, err)
+	}
+
+	var metadataErr *metadata.Error
+	if errors.As(err, &metadataErr) {
+		return auth.NewErrorFromHTTPStatus(metadataErr.Code, err)
+	}
+
+	// The metadata key is not defined, e.g. because the cluster is not GKE.
+	var notDefinedErr metadata.NotDefinedError
+	if errors.As(err, &notDefinedErr) {
+		return auth.NewInvalidConfigurationError(err)
+	}
+
+	return auth.ClassifyError(err)
+}
@@ -18,10 +18,13 @@ package gcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
 	"cloud.google.com/go/compute/metadata"
+
+	auth "github.com/fluxcd/pkg/auth"
 )
 
 type gkeMetadataLoader struct {
@@ -96,26 +99,29 @@ func (g *gkeMetadataLoader) load(ctx context.Context) error {
 
 	projectID, err := client.GetWithContext(ctx, "project/project-id")
 	if err != nil {
-		return fmt.Errorf("failed to get GKE cluster project ID from the metadata service: %w", err)
+		return fmt.Errorf("failed to get GKE cluster project ID from the metadata service: %w", classifyError(err))
 	}
 	if projectID == "" {
-		return fmt.Errorf("failed to get GKE cluster project ID from the metadata service: empty value")
+		return auth.NewInvalidConfigurationError(
+			errors.New("failed to get GKE cluster project ID from the metadata service: empty value"))
 	}
 
 	location, err := client.GetWithContext(ctx, "instance/attributes/cluster-location")
 	if err != nil {
-		return fmt.Errorf("failed to get GKE cluster location from the metadata service: %w", err)
+		return fmt.Errorf("failed to get GKE cluster location from the metadata service: %w", classifyError(err))
 	}
 	if location == "" {
-		return fmt.Errorf("failed to get GKE cluster location from the metadata service: empty value")
+		return auth.NewInvalidConfigurationError(
+			errors.New("failed to get GKE cluster location from the metadata service: empty value"))
 	}
 
 	name, err := client.GetWithContext(ctx, "instance/attributes/cluster-name")
 	if err != nil {
-		return fmt.Errorf("failed to get GKE cluster name from the metadata service: %w", err)
+		return fmt.Errorf("failed to get GKE cluster name from the metadata service: %w", classifyError(err))
 	}
 	if name == "" {
-		return fmt.Errorf("failed to get GKE cluster name from the metadata service: empty value")
+		return auth.NewInvalidConfigurationError(
+			errors.New("failed to get GKE cluster name from the metadata service: empty value"))
 	}
 
 	g.projectID = projectID
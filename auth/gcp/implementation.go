@@ -23,6 +23,7 @@ import (
 	"golang.org/x/oauth2/google"
 	"golang.org/x/oauth2/google/externalaccount"
 	"google.golang.org/api/container/v1"
+	gkehub "google.golang.org/api/gkehub/v1"
 )
 
 // Implementation provides the required methods of the GCP libraries.
@@ -32,6 +33,13 @@ type Implementation interface {
 	GetCluster(ctx context.Context, cluster string, client *container.Service) (*container.Cluster, error)
 }
 
+// MembershipImplementation is an optional interface of the Implementation
+// for describing fleet memberships. The GCP libraries are used for the
+// implementations not providing it.
+type MembershipImplementation interface {
+	GetMembership(ctx context.Context, membership string, client *gkehub.Service) (*gkehub.Membership, error)
+}
+
 type implementation struct{}
 
 func (implementation) DefaultTokenSource(ctx context.Context, scope ...string) (oauth2.TokenSource, error) {
@@ -45,3 +53,7 @@ func (implementation) NewTokenSource(ctx context.Context, conf externalaccount.C
 func (implementation) GetCluster(ctx context.Context, cluster string, client *container.Service) (*container.Cluster, error) {
 	return client.Projects.Locations.Clusters.Get(cluster).Context(ctx).Do()
 }
+
+func (implementation) GetMembership(ctx context.Context, membership string, client *gkehub.Service) (*gkehub.Membership, error) {
+	return client.Projects.Locations.Memberships.Get(membership).Context(ctx).Do()
+}
@@ -29,19 +29,35 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google/externalaccount"
 	"google.golang.org/api/container/v1"
+	gkehub "google.golang.org/api/gkehub/v1"
+
+	"github.com/fluxcd/pkg/auth/gcp"
 )
 
+var _ gcp.MembershipImplementation = &mockImplementation{}
+
 type mockImplementation struct {
 	t *testing.T
 
-	expectGKEAPICall bool
+	expectGKEAPICall    bool
+	expectGKEHubAPICall bool
+
+	argConfig     externalaccount.Config
+	argProxyURL   *url.URL
+	argCluster    string
+	argMembership string
+	argScopes     []string
+
+	returnToken      *oauth2.Token
+	returnTokenErr   error
+	returnCluster    *container.Cluster
+	returnMembership *gkehub.Membership
+}
 
-	argConfig   externalaccount.Config
-	argProxyURL *url.URL
-	argCluster  string
+type errTokenSource struct{ err error }
 
-	returnToken   *oauth2.Token
-	returnCluster *container.Cluster
+func (e errTokenSource) Token() (*oauth2.Token, error) {
+	return nil, e.err
 }
 
 func (m *mockImplementation) DefaultTokenSource(ctx context.Context, scope ...string) (oauth2.TokenSource, error) {
@@ -56,10 +72,14 @@ func (m *mockImplementation) DefaultTokenSource(ctx context.Context, scope ...st
 	proxyURL, err := ctx.Value(oauth2.HTTPClient).(*http.Client).Transport.(*http.Transport).Proxy(nil)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(proxyURL).To(Equal(m.argProxyURL))
-	g.Expect(scope).To(Equal([]string{
-		"https://www.googleapis.com/auth/cloud-platform",
-		"https://www.googleapis.com/auth/userinfo.email",
-	}))
+	expectedScopes := m.argScopes
+	if expectedScopes == nil {
+		expectedScopes = []string{
+			"https://www.googleapis.com/auth/cloud-platform",
+			"https://www.googleapis.com/auth/userinfo.email",
+		}
+	}
+	g.Expect(scope).To(Equal(expectedScopes))
 	return oauth2.StaticTokenSource(m.returnToken), nil
 }
 
@@ -76,6 +96,9 @@ func (m *mockImplementation) NewTokenSource(ctx context.Context, conf externalac
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(proxyURL).To(Equal(m.argProxyURL))
 	g.Expect(conf).To(Equal(m.argConfig))
+	if m.returnTokenErr != nil {
+		return errTokenSource{m.returnTokenErr}, nil
+	}
 	return oauth2.StaticTokenSource(m.returnToken), nil
 }
 
@@ -87,6 +110,26 @@ func (m *mockImplementation) GetCluster(ctx context.Context, cluster string, cli
 	g.Expect(cluster).To(Equal(m.argCluster))
 	g.Expect(client).NotTo(BeNil())
 	g.Expect(client.BasePath).To(Equal("https://container.googleapis.com/"))
+	m.expectAPIHTTPClient(g, client)
+	return m.returnCluster, nil
+}
+
+func (m *mockImplementation) GetMembership(ctx context.Context, membership string, client *gkehub.Service) (*gkehub.Membership, error) {
+	m.t.Helper()
+	g := NewWithT(m.t)
+	g.Expect(m.expectGKEHubAPICall).To(BeTrue())
+	g.Expect(ctx).NotTo(BeNil())
+	g.Expect(membership).To(Equal(m.argMembership))
+	g.Expect(client).NotTo(BeNil())
+	g.Expect(client.BasePath).To(Equal("https://gkehub.googleapis.com/"))
+	m.expectAPIHTTPClient(g, client)
+	return m.returnMembership, nil
+}
+
+// expectAPIHTTPClient asserts that the HTTP client of the given API service
+// uses the returned token and the proxy.
+func (m *mockImplementation) expectAPIHTTPClient(g *WithT, client any) {
+	m.t.Helper()
 	httpClientField := reflect.ValueOf(client).Elem().FieldByName("client")
 	httpClientValue := reflect.NewAt(httpClientField.Type(), unsafe.Pointer(httpClientField.UnsafeAddr())).Elem().Interface().(*http.Client)
 	g.Expect(httpClientValue).NotTo(BeNil())
@@ -108,5 +151,4 @@ func (m *mockImplementation) GetCluster(ctx context.Context, cluster string, cli
 	proxyURL, err := parameterRoundTripperValue.(*http.Transport).Proxy(nil)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(proxyURL).To(Equal(m.argProxyURL))
-	return m.returnCluster, nil
 }
@@ -18,9 +18,13 @@ package gcp
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+
+	auth "github.com/fluxcd/pkg/auth"
 )
 
 const serviceAccountEmailPattern = `^[a-zA-Z0-9-]{1,100}@[a-zA-Z0-9-]{1,100}\.iam\.gserviceaccount\.com$`
@@ -34,8 +38,8 @@ func getServiceAccountEmail(serviceAccount corev1.ServiceAccount) (string, error
 		return "", nil
 	}
 	if !serviceAccountEmailRegex.MatchString(email) {
-		return "", fmt.Errorf("invalid %s annotation: '%s'. must match %s",
-			key, email, serviceAccountEmailPattern)
+		return "", auth.NewInvalidConfigurationError(fmt.Errorf("invalid %s annotation: '%s'. must match %s",
+			key, email, serviceAccountEmailPattern))
 	}
 	return email, nil
 }
@@ -51,8 +55,8 @@ func getWorkloadIdentityProviderAudience(serviceAccount corev1.ServiceAccount) (
 		return "", nil
 	}
 	if !workloadIdentityProviderRegex.MatchString(wip) {
-		return "", fmt.Errorf("invalid %s annotation: '%s'. must match %s",
-			key, wip, workloadIdentityProviderPattern)
+		return "", auth.NewInvalidConfigurationError(fmt.Errorf("invalid %s annotation: '%s'. must match %s",
+			key, wip, workloadIdentityProviderPattern))
 	}
 	return fmt.Sprintf("//iam.googleapis.com/%s", wip), nil
 }
@@ -63,8 +67,67 @@ var clusterRegex = regexp.MustCompile(clusterPattern)
 
 func parseCluster(cluster string) error {
 	if !clusterRegex.MatchString(cluster) {
-		return fmt.Errorf("invalid GKE cluster ID: '%s'. must match %s",
-			cluster, clusterPattern)
+		return auth.NewInvalidConfigurationError(fmt.Errorf("invalid GKE cluster ID: '%s'. must match %s",
+			cluster, clusterPattern))
+	}
+	return nil
+}
+
+const membershipPattern = `^projects/([^/]{1,200})/locations/([^/]{1,200})/memberships/([^/]{1,200})$`
+
+var membershipRegex = regexp.MustCompile(membershipPattern)
+
+// isMembership returns true if the cluster resource is the name of a fleet membership.
+func isMembership(cluster string) bool {
+	return membershipRegex.MatchString(cluster)
+}
+
+// connectGatewayHost is the host of the global Connect Gateway endpoint.
+const connectGatewayHost = "connectgateway.googleapis.com"
+
+// isConnectGatewayAddress returns true if the cluster address is a Connect Gateway URL.
+func isConnectGatewayAddress(address string) bool {
+	if address == "" {
+		return false
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	return host == connectGatewayHost || strings.HasSuffix(host, "-"+connectGatewayHost)
+}
+
+// connectGatewayURL returns the Connect Gateway URL of the given fleet membership.
+// Memberships of GKE clusters are addressed with the gkeMemberships collection,
+// and regional memberships with the regional endpoint of the gateway.
+func connectGatewayURL(membership string, gke bool) (string, error) {
+	m := membershipRegex.FindStringSubmatch(membership)
+	if m == nil {
+		return "", auth.NewInvalidConfigurationError(fmt.Errorf("invalid fleet membership ID: '%s'. must match %s",
+			membership, membershipPattern))
+	}
+	project, location, name := m[1], m[2], m[3]
+
+	host := connectGatewayHost
+	if location != "global" {
+		host = fmt.Sprintf("%s-%s", location, connectGatewayHost)
+	}
+	collection := "memberships"
+	if gke {
+		collection = "gkeMemberships"
+	}
+	return fmt.Sprintf("https://%s/v1/projects/%s/locations/%s/%s/%s", host, project, location, collection, name), nil
+}
+
+// parseScope validates that the scope is an https URL, as all the OAuth 2.0
+// scopes of Google APIs are, e.g. https://www.googleapis.com/auth/cloud-platform.
+func parseScope(scope string) error {
+	u, err := url.Parse(scope)
+	if err != nil || u.Scheme != "https" || u.Host == "" || strings.Trim(u.Path, "/") == "" ||
+		u.RawQuery != "" || u.Fragment != "" {
+		return auth.NewInvalidConfigurationError(fmt.Errorf("invalid GCP scope: '%s'. must be an https URL, e.g. %s",
+			scope, cloudPlatformScope))
 	}
 	return nil
 }
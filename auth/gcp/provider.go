@@ -19,14 +19,17 @@ package gcp
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google/externalaccount"
 	"google.golang.org/api/container/v1"
+	gkehub "google.golang.org/api/gkehub/v1"
 	"google.golang.org/api/option"
 	htransport "google.golang.org/api/transport/http"
 	corev1 "k8s.io/api/core/v1"
@@ -37,14 +40,52 @@ import (
 // ProviderName is the name of the GCP authentication provider.
 const ProviderName = "gcp"
 
+// cloudPlatformScope is the scope granting access to all the Google Cloud APIs.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// scopes are the default scopes of the access tokens, used for accessing
+// GKE clusters and the other Google Cloud APIs.
 var scopes = []string{
-	"https://www.googleapis.com/auth/cloud-platform",
+	cloudPlatformScope,
 	"https://www.googleapis.com/auth/userinfo.email",
 }
 
+// connectGatewayScopes are the scopes of the access tokens used for
+// accessing clusters through the Connect Gateway.
+var connectGatewayScopes = []string{
+	cloudPlatformScope,
+}
+
+// artifactRepositoryScopes are the scopes of the access tokens used for
+// pulling and pushing artifacts from GCR and Artifact Registry. For least
+// privilege, they grant access to the registries only. Pull-only clients
+// can narrow them further with auth.WithScopes and the
+// https://www.googleapis.com/auth/devstorage.read_only scope.
+var artifactRepositoryScopes = []string{
+	"https://www.googleapis.com/auth/devstorage.read_write",
+}
+
+// getScopes returns the scopes configured with auth.WithScopes after
+// validating them, or the default scopes.
+func getScopes(o *auth.Options) ([]string, error) {
+	if len(o.Scopes) == 0 {
+		return scopes, nil
+	}
+	for _, scope := range o.Scopes {
+		if err := parseScope(scope); err != nil {
+			return nil, err
+		}
+	}
+	return o.Scopes, nil
+}
+
 // Provider implements the auth.Provider interface for GCP authentication.
 type Provider struct{ Implementation }
 
+func init() {
+	auth.MustRegister(Provider{})
+}
+
 // GetName implements auth.Provider.
 func (Provider) GetName() string {
 	return ProviderName
@@ -55,17 +96,77 @@ func (p Provider) NewControllerToken(ctx context.Context, opts ...auth.Option) (
 	var o auth.Options
 	o.Apply(opts...)
 
+	if o.OIDCTokenFile != "" {
+		return p.newControllerTokenFromOIDCTokenFile(ctx, &o)
+	}
+
+	scopes, err := getScopes(&o)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx = context.WithValue(ctx, oauth2.HTTPClient, o.GetHTTPClient())
 
 	src, err := p.impl().DefaultTokenSource(ctx, scopes...)
 	if err != nil {
-		return nil, err
+		return nil, auth.NewInvalidConfigurationError(err)
 	}
 	token, err := src.Token()
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return &Token{*token}, nil
+}
+
+// newControllerTokenFromOIDCTokenFile exchanges the OIDC token from the file
+// configured with auth.WithOIDCTokenFile for a GCP access token through the
+// STS service. The audience must be the workload identity provider, e.g.
+// //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>,
+// and is configured with auth.WithAudiences.
+func (p Provider) newControllerTokenFromOIDCTokenFile(ctx context.Context, o *auth.Options) (auth.Token, error) {
+	if len(o.Audiences) == 0 {
+		return nil, auth.NewInvalidConfigurationError(
+			errors.New("the workload identity provider audience is required for authenticating with an OIDC token file"))
+	}
+	audience := o.Audiences[0]
+	if !workloadIdentityProviderRegex.MatchString(strings.TrimPrefix(audience, "//iam.googleapis.com/")) {
+		return nil, auth.NewInvalidConfigurationError(fmt.Errorf(
+			"invalid workload identity provider audience: '%s'. must match //iam.googleapis.com/%s",
+			audience, workloadIdentityProviderPattern))
+	}
+
+	scopes, err := getScopes(o)
 	if err != nil {
 		return nil, err
 	}
 
+	oidcToken, err := o.ReadOIDCTokenFile()
+	if err != nil {
+		return nil, err
+	}
+
+	conf := externalaccount.Config{
+		UniverseDomain:       "googleapis.com",
+		Audience:             audience,
+		SubjectTokenType:     "urn:ietf:params:oauth:token-type:jwt",
+		TokenURL:             "https://sts.googleapis.com/v1/token",
+		TokenInfoURL:         "https://sts.googleapis.com/v1/introspect",
+		SubjectTokenSupplier: StaticTokenSupplier(oidcToken),
+		Scopes:               scopes,
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, o.GetHTTPClient())
+
+	src, err := p.impl().NewTokenSource(ctx, conf)
+	if err != nil {
+		return nil, auth.NewInvalidConfigurationError(err)
+	}
+	token, err := src.Token()
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
 	return &Token{*token}, nil
 }
 
@@ -113,6 +214,11 @@ func (p Provider) NewTokenForServiceAccount(ctx context.Context, oidcToken strin
 		return nil, err
 	}
 
+	scopes, err := getScopes(&o)
+	if err != nil {
+		return nil, err
+	}
+
 	// Assume we are in GKE. In this case, retrieve the audience from the metadata.
 	if audience == "" {
 		audience, err = gkeMetadata.getAudience(ctx)
@@ -147,11 +253,11 @@ func (p Provider) NewTokenForServiceAccount(ctx context.Context, oidcToken strin
 
 	src, err := p.impl().NewTokenSource(ctx, conf)
 	if err != nil {
-		return nil, err
+		return nil, auth.NewInvalidConfigurationError(err)
 	}
 	token, err := src.Token()
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	return &Token{*token}, nil
@@ -159,8 +265,27 @@ func (p Provider) NewTokenForServiceAccount(ctx context.Context, oidcToken strin
 
 // GetAccessTokenOptionsForArtifactRepository implements auth.Provider.
 func (Provider) GetAccessTokenOptionsForArtifactRepository(string) ([]auth.Option, error) {
-	// GCP does not require any special options to retrieve access tokens.
-	return nil, nil
+	// GCP access tokens are not scoped to the location of the registry,
+	// they only require the registry scopes, unless the scopes are
+	// configured with auth.WithScopes.
+	return []auth.Option{func(o *auth.Options) {
+		if len(o.Scopes) == 0 {
+			o.Scopes = artifactRepositoryScopes
+		}
+	}}, nil
+}
+
+// GetAccessTokenOptionSetsForArtifactRepository implements auth.ArtifactRepositoryProvider.
+// A single access token with the registry scopes is required.
+func (p Provider) GetAccessTokenOptionSetsForArtifactRepository(artifactRepository string, _ ...auth.Option) ([][]auth.Option, error) {
+	if _, err := p.ParseArtifactRepository(artifactRepository); err != nil {
+		return nil, err
+	}
+	opts, err := p.GetAccessTokenOptionsForArtifactRepository(artifactRepository)
+	if err != nil {
+		return nil, err
+	}
+	return [][]auth.Option{opts}, nil
 }
 
 // The docker.s3nsregistry.fr host is the S3NS sovereign cloud artifact
@@ -178,8 +303,11 @@ func (Provider) ParseArtifactRepository(artifactRepository string) (string, erro
 	}
 
 	if !registryRegex.MatchString(registry) {
-		return "", fmt.Errorf("invalid GCP registry: '%s'. must match %s",
-			registry, registryPattern)
+		return "", &auth.ErrUnsupportedRegistry{
+			Provider: ProviderName,
+			Registry: registry,
+			Err:      fmt.Errorf("invalid GCP registry: '%s'. must match %s", registry, registryPattern),
+		}
 	}
 
 	// The artifact repository is irrelevant for issuing GCP registry credentials,
@@ -204,7 +332,15 @@ func (Provider) NewArtifactRegistryCredentials(_ context.Context, _ string,
 
 // GetAccessTokenOptionsForCluster implements auth.Provider.
 func (Provider) GetAccessTokenOptionsForCluster(opts ...auth.Option) ([][]auth.Option, error) {
-	// A single token is needed. No options.
+	var o auth.Options
+	o.Apply(opts...)
+
+	// A single token is needed. Clusters accessed through the
+	// Connect Gateway need the Connect Gateway scopes, unless the
+	// scopes are configured with auth.WithScopes.
+	if len(o.Scopes) == 0 && (isMembership(o.ClusterResource) || isConnectGatewayAddress(o.ClusterAddress)) {
+		return [][]auth.Option{{auth.WithScopes(connectGatewayScopes...)}}, nil
+	}
 	return [][]auth.Option{{}}, nil
 }
 
@@ -217,6 +353,11 @@ func (p Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 	var o auth.Options
 	o.Apply(opts...)
 
+	// Fleet memberships are accessed through the Connect Gateway.
+	if isMembership(o.ClusterResource) || isConnectGatewayAddress(o.ClusterAddress) {
+		return p.newConnectGatewayRESTConfig(ctx, token, &o)
+	}
+
 	// Describe the cluster resource to get missing CA or endpoint.
 	host := o.ClusterAddress
 	caData := []byte(o.CAData)
@@ -227,23 +368,21 @@ func (p Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 		}
 
 		// Create client for describing the cluster resource.
-		baseTransport := http.DefaultTransport.(*http.Transport).Clone()
-		if p := o.ProxyURL; p != nil {
-			baseTransport.Proxy = http.ProxyURL(p)
-		}
-		transport, err := htransport.NewTransport(ctx, baseTransport, option.WithTokenSource(token.source()))
+		httpClient, err := newAPIHTTPClient(ctx, token, &o)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create google http transport for describing GKE cluster: %w", err)
+			return nil, auth.NewInvalidConfigurationError(
+				fmt.Errorf("failed to create google http transport for describing GKE cluster: %w", err))
 		}
-		client, err := container.NewService(ctx, option.WithHTTPClient(&http.Client{Transport: transport}))
+		client, err := container.NewService(ctx, option.WithHTTPClient(httpClient))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create client for describing GKE cluster: %w", err)
+			return nil, auth.NewInvalidConfigurationError(
+				fmt.Errorf("failed to create client for describing GKE cluster: %w", err))
 		}
 
 		// Describe the cluster resource.
 		clusterResource, err := p.impl().GetCluster(ctx, cluster, client)
 		if err != nil {
-			return nil, fmt.Errorf("failed to describe GKE cluster '%s': %w", cluster, err)
+			return nil, fmt.Errorf("failed to describe GKE cluster '%s': %w", cluster, classifyError(err))
 		}
 
 		// Update host and CA with cluster details.
@@ -267,9 +406,80 @@ func (p Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 	}, nil
 }
 
+// newConnectGatewayRESTConfig returns the REST config for accessing a fleet
+// membership through the Connect Gateway. If the address is not specified,
+// the membership is described to resolve the gateway URL. No CA is configured
+// unless specified, as the gateway serves a certificate trusted by the system.
+func (p Provider) newConnectGatewayRESTConfig(ctx context.Context, token *Token,
+	o *auth.Options) (*auth.RESTConfig, error) {
+
+	host := o.ClusterAddress
+	if host == "" {
+		membership := o.ClusterResource
+
+		// Create client for describing the membership resource.
+		httpClient, err := newAPIHTTPClient(ctx, token, o)
+		if err != nil {
+			return nil, auth.NewInvalidConfigurationError(
+				fmt.Errorf("failed to create google http transport for describing fleet membership: %w", err))
+		}
+		client, err := gkehub.NewService(ctx, option.WithHTTPClient(httpClient))
+		if err != nil {
+			return nil, auth.NewInvalidConfigurationError(
+				fmt.Errorf("failed to create client for describing fleet membership: %w", err))
+		}
+
+		// Describe the membership resource.
+		membershipResource, err := p.membershipImpl().GetMembership(ctx, membership, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe fleet membership '%s': %w", membership, classifyError(err))
+		}
+
+		// Resolve the gateway URL.
+		gke := membershipResource.Endpoint != nil && membershipResource.Endpoint.GkeCluster != nil
+		host, err = connectGatewayURL(membership, gke)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Build and return the REST config.
+	var caData []byte
+	if o.CAData != "" {
+		caData = []byte(o.CAData)
+	}
+	return &auth.RESTConfig{
+		Host:        host,
+		BearerToken: token.AccessToken,
+		CAData:      caData,
+		ExpiresAt:   token.Expiry,
+	}, nil
+}
+
+// newAPIHTTPClient returns an HTTP client authenticated with the given token
+// for calling the Google Cloud APIs.
+func newAPIHTTPClient(ctx context.Context, token *Token, o *auth.Options) (*http.Client, error) {
+	baseTransport := http.DefaultTransport.(*http.Transport).Clone()
+	if p := o.ProxyURL; p != nil {
+		baseTransport.Proxy = http.ProxyURL(p)
+	}
+	transport, err := htransport.NewTransport(ctx, baseTransport, option.WithTokenSource(token.source()))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
 func (p Provider) impl() Implementation {
 	if p.Implementation == nil {
 		return implementation{}
 	}
 	return p.Implementation
 }
+
+func (p Provider) membershipImpl() MembershipImplementation {
+	if m, ok := p.impl().(MembershipImplementation); ok {
+		return m
+	}
+	return implementation{}
+}
@@ -18,15 +18,24 @@ package gcp_test
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/go-containerregistry/pkg/authn"
 	. "github.com/onsi/gomega"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google/externalaccount"
 	"google.golang.org/api/container/v1"
+	gkehub "google.golang.org/api/gkehub/v1"
+	"google.golang.org/api/googleapi"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -53,6 +62,87 @@ func TestProvider_NewControllerToken(t *testing.T) {
 	g.Expect(token).To(Equal(&gcp.Token{oauth2.Token{AccessToken: "access-token"}}))
 }
 
+func TestProvider_NewControllerToken_OIDCTokenFile(t *testing.T) {
+	const audience = "//iam.googleapis.com/projects/1234567890/locations/global/workloadIdentityPools/pool/providers/provider"
+
+	for _, tt := range []struct {
+		name      string
+		exp       time.Time
+		audiences []string
+		err       string
+	}{
+		{
+			name:      "valid",
+			exp:       time.Now().Add(time.Hour),
+			audiences: []string{audience},
+		},
+		{
+			name:      "expired token",
+			exp:       time.Now().Add(-time.Hour),
+			audiences: []string{audience},
+			err:       "expired at",
+		},
+		{
+			name: "missing audience",
+			exp:  time.Now().Add(time.Hour),
+			err:  "the workload identity provider audience is required for authenticating with an OIDC token file",
+		},
+		{
+			name:      "invalid audience",
+			exp:       time.Now().Add(time.Hour),
+			audiences: []string{"foo"},
+			err:       "invalid workload identity provider audience: 'foo'",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			oidcToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"exp": tt.exp.Unix(),
+			}).SignedString([]byte("secret"))
+			g.Expect(err).NotTo(HaveOccurred())
+			path := filepath.Join(t.TempDir(), "token")
+			g.Expect(os.WriteFile(path, []byte(oidcToken), 0o600)).To(Succeed())
+
+			impl := &mockImplementation{
+				t:           t,
+				argProxyURL: &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				argConfig: externalaccount.Config{
+					Audience:         audience,
+					SubjectTokenType: "urn:ietf:params:oauth:token-type:jwt",
+					TokenURL:         "https://sts.googleapis.com/v1/token",
+					TokenInfoURL:     "https://sts.googleapis.com/v1/introspect",
+					Scopes: []string{
+						"https://www.googleapis.com/auth/cloud-platform",
+						"https://www.googleapis.com/auth/userinfo.email",
+					},
+					SubjectTokenSupplier: gcp.StaticTokenSupplier(oidcToken),
+					UniverseDomain:       "googleapis.com",
+				},
+				returnToken: &oauth2.Token{AccessToken: "access-token"},
+			}
+
+			opts := []auth.Option{
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+				auth.WithAudiences(tt.audiences...),
+				auth.WithOIDCTokenFile(path),
+			}
+
+			provider := gcp.Provider{Implementation: impl}
+			token, err := provider.NewControllerToken(context.Background(), opts...)
+
+			if tt.err == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(token).To(Equal(&gcp.Token{oauth2.Token{AccessToken: "access-token"}}))
+			} else {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				g.Expect(token).To(BeNil())
+			}
+		})
+	}
+}
+
 func TestProvider_NewTokenForServiceAccount(t *testing.T) {
 	startGKEMetadataServer(t)
 
@@ -185,6 +275,102 @@ func TestProvider_NewTokenForServiceAccount(t *testing.T) {
 	}
 }
 
+func TestProvider_NewTokenForServiceAccount_ErrorClassification(t *testing.T) {
+	const wip = "projects/1234567890/locations/global/workloadIdentityPools/test-pool/providers/test-provider"
+
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		tokenErr    error
+		wantErr     error
+		retryable   bool
+	}{
+		{
+			name: "invalid sa email",
+			annotations: map[string]string{
+				"iam.gke.io/gcp-service-account": "foobar",
+			},
+			wantErr: auth.ErrInvalidConfiguration,
+		},
+		{
+			name: "invalid grant",
+			tokenErr: &oauth2.RetrieveError{
+				Response:  &http.Response{StatusCode: http.StatusBadRequest},
+				ErrorCode: "invalid_grant",
+			},
+			wantErr: auth.ErrInvalidConfiguration,
+		},
+		{
+			name: "impersonation denied",
+			tokenErr: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "Permission 'iam.serviceAccounts.getAccessToken' denied",
+			},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name: "server error",
+			tokenErr: &oauth2.RetrieveError{
+				Response: &http.Response{StatusCode: http.StatusInternalServerError},
+			},
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+		{
+			name:      "network error",
+			tokenErr:  &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			wantErr:   auth.ErrTransient,
+			retryable: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			annotations := tt.annotations
+			if annotations == nil {
+				annotations = map[string]string{"gcp.auth.fluxcd.io/workload-identity-provider": wip}
+			}
+
+			impl := &mockImplementation{
+				t: t,
+				argConfig: externalaccount.Config{
+					Audience:         "//iam.googleapis.com/" + wip,
+					SubjectTokenType: "urn:ietf:params:oauth:token-type:jwt",
+					TokenURL:         "https://sts.googleapis.com/v1/token",
+					TokenInfoURL:     "https://sts.googleapis.com/v1/introspect",
+					Scopes: []string{
+						"https://www.googleapis.com/auth/cloud-platform",
+						"https://www.googleapis.com/auth/userinfo.email",
+					},
+					SubjectTokenSupplier: gcp.StaticTokenSupplier("oidc-token"),
+					UniverseDomain:       "googleapis.com",
+				},
+				argProxyURL:    &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				returnTokenErr: tt.tokenErr,
+			}
+
+			serviceAccount := corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-sa",
+					Namespace:   "test-ns",
+					Annotations: annotations,
+				},
+			}
+
+			provider := gcp.Provider{Implementation: impl}
+			_, err := provider.NewTokenForServiceAccount(context.Background(), "oidc-token", serviceAccount,
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}))
+
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err).To(MatchError(tt.wantErr))
+			if tt.tokenErr != nil {
+				g.Expect(err).To(MatchError(tt.tokenErr))
+			}
+			g.Expect(auth.IsRetryable(err)).To(Equal(tt.retryable))
+		})
+	}
+}
+
 func TestProvider_GetAudience(t *testing.T) {
 	startGKEMetadataServer(t)
 
@@ -264,6 +450,7 @@ func TestProvider_NewArtifactRegistryCredentials(t *testing.T) {
 		Implementation: &mockImplementation{
 			t:           t,
 			argProxyURL: &url.URL{Scheme: "http", Host: "proxy.example.com"},
+			argScopes:   []string{"https://www.googleapis.com/auth/devstorage.read_write"},
 			returnToken: &oauth2.Token{
 				AccessToken: "access-token",
 				Expiry:      exp,
@@ -351,6 +538,107 @@ func TestProvider_ParseArtifactRegistry(t *testing.T) {
 	}
 }
 
+func TestProvider_GetAccessTokenOptionsForArtifactRepository(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opts   []auth.Option
+		scopes []string
+	}{
+		{
+			name:   "registry scopes by default",
+			scopes: []string{"https://www.googleapis.com/auth/devstorage.read_write"},
+		},
+		{
+			name:   "scopes configured",
+			opts:   []auth.Option{auth.WithScopes("https://www.googleapis.com/auth/devstorage.read_only")},
+			scopes: []string{"https://www.googleapis.com/auth/devstorage.read_only"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts, err := gcp.Provider{}.GetAccessTokenOptionsForArtifactRepository("europe-west1-docker.pkg.dev/project/repo")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var o auth.Options
+			o.Apply(append(tt.opts, opts...)...)
+			g.Expect(o.Scopes).To(Equal(tt.scopes))
+		})
+	}
+}
+
+func TestProvider_GetAccessTokenOptionSetsForArtifactRepository(t *testing.T) {
+	for _, tt := range []struct {
+		name               string
+		artifactRepository string
+		opts               []auth.Option
+		scopes             []string
+		unsupported        bool
+		malformed          bool
+	}{
+		{
+			name:               "GAR repository",
+			artifactRepository: "europe-west1-docker.pkg.dev/project/repo/image:v1",
+		},
+		{
+			name:               "scopes configured",
+			artifactRepository: "europe-west1-docker.pkg.dev/project/repo/image:v1",
+			opts:               []auth.Option{auth.WithScopes("https://www.googleapis.com/auth/devstorage.read_only")},
+			scopes:             []string{"https://www.googleapis.com/auth/devstorage.read_only"},
+		},
+		{
+			name:               "regional GCR registry",
+			artifactRepository: "eu.gcr.io/project/image",
+		},
+		{
+			name:               "S3NS registry",
+			artifactRepository: "docker.s3nsregistry.fr/s3ns/repo",
+		},
+		{
+			name:               "ECR registry",
+			artifactRepository: "012345678901.dkr.ecr.us-east-1.amazonaws.com/foo",
+			unsupported:        true,
+		},
+		{
+			name:               "GAR host without location",
+			artifactRepository: "-docker.pkg.dev/project/repo",
+			unsupported:        true,
+		},
+		{
+			name:               "malformed repository",
+			artifactRepository: "europe-west1-docker.pkg.dev/project/repo@sha256:foo",
+			malformed:          true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts, err := gcp.Provider{}.GetAccessTokenOptionSetsForArtifactRepository(tt.artifactRepository, tt.opts...)
+
+			var unsupportedErr *auth.ErrUnsupportedRegistry
+			switch {
+			case tt.unsupported:
+				g.Expect(errors.As(err, &unsupportedErr)).To(BeTrue())
+				g.Expect(unsupportedErr.Provider).To(Equal(gcp.ProviderName))
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+			case tt.malformed:
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				g.Expect(errors.As(err, &unsupportedErr)).To(BeFalse())
+			default:
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(opts).To(HaveLen(1))
+				o := auth.Options{}
+				o.Apply(append(tt.opts, opts[0]...)...)
+				scopes := tt.scopes
+				if scopes == nil {
+					scopes = []string{"https://www.googleapis.com/auth/devstorage.read_write"}
+				}
+				g.Expect(o.Scopes).To(Equal(scopes))
+			}
+		})
+	}
+}
+
 func TestProvider_NewRESTConfig(t *testing.T) {
 	for _, tt := range []struct {
 		name           string
@@ -463,6 +751,104 @@ func TestProvider_NewRESTConfig(t *testing.T) {
 	}
 }
 
+func TestProvider_NewRESTConfig_ConnectGateway(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		cluster        string
+		clusterAddress string
+		caData         string
+		membership     *gkehub.Membership
+		host           string
+	}{
+		{
+			name:    "GKE cluster membership",
+			cluster: "projects/test-project/locations/global/memberships/test-cluster",
+			membership: &gkehub.Membership{
+				Endpoint: &gkehub.MembershipEndpoint{
+					GkeCluster: &gkehub.GkeCluster{
+						ResourceLink: "//container.googleapis.com/projects/test-project/locations/us-central1/clusters/test-cluster",
+					},
+				},
+			},
+			host: "https://connectgateway.googleapis.com/v1/projects/test-project/locations/global/gkeMemberships/test-cluster",
+		},
+		{
+			name:       "attached cluster membership",
+			cluster:    "projects/test-project/locations/global/memberships/test-cluster",
+			membership: &gkehub.Membership{},
+			host:       "https://connectgateway.googleapis.com/v1/projects/test-project/locations/global/memberships/test-cluster",
+		},
+		{
+			name:    "regional membership",
+			cluster: "projects/test-project/locations/us-central1/memberships/test-cluster",
+			membership: &gkehub.Membership{
+				Endpoint: &gkehub.MembershipEndpoint{GkeCluster: &gkehub.GkeCluster{}},
+			},
+			host: "https://us-central1-connectgateway.googleapis.com/v1/projects/test-project/locations/us-central1/gkeMemberships/test-cluster",
+		},
+		{
+			name:           "membership with gateway address",
+			cluster:        "projects/test-project/locations/global/memberships/test-cluster",
+			clusterAddress: "https://connectgateway.googleapis.com/v1/projects/123456789/locations/global/gkeMemberships/test-cluster",
+			host:           "https://connectgateway.googleapis.com/v1/projects/123456789/locations/global/gkeMemberships/test-cluster",
+		},
+		{
+			name:           "gateway address only",
+			clusterAddress: "https://us-central1-connectgateway.googleapis.com/v1/projects/123456789/locations/us-central1/memberships/test-cluster",
+			host:           "https://us-central1-connectgateway.googleapis.com/v1/projects/123456789/locations/us-central1/memberships/test-cluster",
+		},
+		{
+			name:           "gateway address with CA",
+			clusterAddress: "https://connectgateway.googleapis.com/v1/projects/123456789/locations/global/memberships/test-cluster",
+			caData:         "-----BEGIN CERTIFICATE-----",
+			host:           "https://connectgateway.googleapis.com/v1/projects/123456789/locations/global/memberships/test-cluster",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tokenExpiry := time.Now().Add(1 * time.Hour)
+			impl := &mockImplementation{
+				t:                   t,
+				expectGKEHubAPICall: tt.clusterAddress == "",
+				argMembership:       tt.cluster,
+				argProxyURL:         &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				argScopes:           []string{"https://www.googleapis.com/auth/cloud-platform"},
+				returnToken: &oauth2.Token{
+					AccessToken: "access-token",
+					Expiry:      tokenExpiry,
+				},
+				returnMembership: tt.membership,
+			}
+
+			opts := []auth.Option{
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+			}
+			if tt.cluster != "" {
+				opts = append(opts, auth.WithClusterResource(tt.cluster))
+			}
+			if tt.clusterAddress != "" {
+				opts = append(opts, auth.WithClusterAddress(tt.clusterAddress))
+			}
+			if tt.caData != "" {
+				opts = append(opts, auth.WithCAData(tt.caData))
+			}
+
+			provider := gcp.Provider{Implementation: impl}
+			restConfig, err := auth.GetRESTConfig(context.Background(), provider, opts...)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(restConfig.Host).To(Equal(tt.host))
+			g.Expect(restConfig.BearerToken).To(Equal("access-token"))
+			if tt.caData != "" {
+				g.Expect(restConfig.CAData).To(Equal([]byte(tt.caData)))
+			} else {
+				g.Expect(restConfig.CAData).To(BeNil())
+			}
+			g.Expect(restConfig.ExpiresAt).To(Equal(tokenExpiry))
+		})
+	}
+}
+
 func TestProvider_GetAccessTokenOptionsForCluster(t *testing.T) {
 	g := NewWithT(t)
 
@@ -480,4 +866,122 @@ func TestProvider_GetAccessTokenOptionsForCluster(t *testing.T) {
 		g.Expect(opts).To(HaveLen(1))
 		g.Expect(opts[0]).To(HaveLen(0)) // Empty slice - no options needed for GCP
 	})
+
+	t.Run("with membership resource", func(t *testing.T) {
+		opts, err := gcp.Provider{}.GetAccessTokenOptionsForCluster(
+			auth.WithClusterResource("projects/test-project/locations/global/memberships/test-cluster"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts).To(HaveLen(1))
+		var o auth.Options
+		o.Apply(opts[0]...)
+		g.Expect(o.Scopes).To(Equal([]string{"https://www.googleapis.com/auth/cloud-platform"}))
+	})
+
+	t.Run("with membership resource and scopes", func(t *testing.T) {
+		opts, err := gcp.Provider{}.GetAccessTokenOptionsForCluster(
+			auth.WithClusterResource("projects/test-project/locations/global/memberships/test-cluster"),
+			auth.WithScopes("https://www.googleapis.com/auth/cloud-platform.read-only"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts).To(Equal([][]auth.Option{{}}))
+	})
+
+	t.Run("with gateway address", func(t *testing.T) {
+		opts, err := gcp.Provider{}.GetAccessTokenOptionsForCluster(
+			auth.WithClusterAddress("https://connectgateway.googleapis.com/v1/projects/123456789/locations/global/memberships/test-cluster"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(opts).To(HaveLen(1))
+		var o auth.Options
+		o.Apply(opts[0]...)
+		g.Expect(o.Scopes).To(Equal([]string{"https://www.googleapis.com/auth/cloud-platform"}))
+	})
+}
+
+func TestProvider_Scopes(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opts   []auth.Option
+		get    func(ctx context.Context, provider gcp.Provider, opts ...auth.Option) error
+		scopes []string
+		err    string
+	}{
+		{
+			name:   "artifact repository",
+			get:    getArtifactRegistryCredentials,
+			scopes: []string{"https://www.googleapis.com/auth/devstorage.read_write"},
+		},
+		{
+			name:   "artifact repository with scopes",
+			opts:   []auth.Option{auth.WithScopes("https://www.googleapis.com/auth/devstorage.read_only")},
+			get:    getArtifactRegistryCredentials,
+			scopes: []string{"https://www.googleapis.com/auth/devstorage.read_only"},
+		},
+		{
+			name: "cluster",
+			get:  getRESTConfig,
+			scopes: []string{
+				"https://www.googleapis.com/auth/cloud-platform",
+				"https://www.googleapis.com/auth/userinfo.email",
+			},
+		},
+		{
+			name:   "cluster with scopes",
+			opts:   []auth.Option{auth.WithScopes("https://www.googleapis.com/auth/cloud-platform")},
+			get:    getRESTConfig,
+			scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+		},
+		{
+			name: "invalid scope",
+			opts: []auth.Option{auth.WithScopes("devstorage.read_only")},
+			get:  getArtifactRegistryCredentials,
+			err:  "invalid GCP scope: 'devstorage.read_only'. must be an https URL",
+		},
+		{
+			name: "scope with query",
+			opts: []auth.Option{auth.WithScopes("https://www.googleapis.com/auth/cloud-platform?foo=bar")},
+			get:  getRESTConfig,
+			err:  "invalid GCP scope: 'https://www.googleapis.com/auth/cloud-platform?foo=bar'. must be an https URL",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			impl := &mockImplementation{
+				t:                t,
+				expectGKEAPICall: true,
+				argCluster:       "projects/project-id/locations/us-central1/clusters/cluster-name",
+				argProxyURL:      &url.URL{Scheme: "http", Host: "proxy.example.com"},
+				argScopes:        tt.scopes,
+				returnToken:      &oauth2.Token{AccessToken: "access-token", Expiry: time.Now().Add(time.Hour)},
+				returnCluster: &container.Cluster{
+					Endpoint: "https://203.0.113.10",
+					MasterAuth: &container.MasterAuth{
+						ClusterCaCertificate: "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t",
+					},
+				},
+			}
+
+			opts := append([]auth.Option{
+				auth.WithProxyURL(url.URL{Scheme: "http", Host: "proxy.example.com"}),
+			}, tt.opts...)
+			err := tt.get(context.Background(), gcp.Provider{Implementation: impl}, opts...)
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func getArtifactRegistryCredentials(ctx context.Context, provider gcp.Provider, opts ...auth.Option) error {
+	_, err := auth.GetArtifactRegistryCredentials(ctx, provider, "us-central1-docker.pkg.dev/project/repo", opts...)
+	return err
+}
+
+func getRESTConfig(ctx context.Context, provider gcp.Provider, opts ...auth.Option) error {
+	opts = append(opts, auth.WithClusterResource("projects/project-id/locations/us-central1/clusters/cluster-name"))
+	_, err := auth.GetRESTConfig(ctx, provider, opts...)
+	return err
 }
@@ -18,8 +18,11 @@ package generic
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -38,6 +41,10 @@ const ProviderName = "generic"
 // Provider implements the auth.Provider interface for generic authentication.
 type Provider struct{ Implementation }
 
+func init() {
+	auth.MustRegister(Provider{})
+}
+
 // GetName implements auth.RESTConfigProvider.
 func (p Provider) GetName() string {
 	return ProviderName
@@ -49,8 +56,24 @@ func (p Provider) NewControllerToken(ctx context.Context, opts ...auth.Option) (
 	var o auth.Options
 	o.Apply(opts...)
 
+	// Outside Kubernetes the OIDC token issued by the platform is used as is.
+	if o.OIDCTokenFile != "" {
+		token, err := o.ReadOIDCTokenFile()
+		if err != nil {
+			return nil, err
+		}
+		exp, err := getExpirationFromToken(token)
+		if err != nil {
+			return nil, err
+		}
+		return &Token{
+			Token:     token,
+			ExpiresAt: *exp,
+		}, nil
+	}
+
 	if o.Client == nil {
-		return nil, errors.New("client is required to create a controller token")
+		return nil, auth.NewInvalidConfigurationError(errors.New("client is required to create a controller token"))
 	}
 
 	// Like all providers, this one should fetch controller-level credentials
@@ -60,21 +83,24 @@ func (p Provider) NewControllerToken(ctx context.Context, opts ...auth.Option) (
 	const tokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
 	b, err := p.impl().ReadFile(tokenFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read service account token file %s: %w", tokenFile, err)
+		return nil, auth.NewInvalidConfigurationError(
+			fmt.Errorf("failed to read service account token file %s: %w", tokenFile, err))
 	}
 
 	// Get controller service account from token subject.
 	tok, _, err := jwt.NewParser().ParseUnverified(string(b), jwt.MapClaims{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse service account token: %w", err)
+		return nil, auth.NewInvalidConfigurationError(fmt.Errorf("failed to parse service account token: %w", err))
 	}
 	sub, err := tok.Claims.GetSubject()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get subject from service account token: %w", err)
+		return nil, auth.NewInvalidConfigurationError(
+			fmt.Errorf("failed to get subject from service account token: %w", err))
 	}
 	parts := strings.Split(sub, ":")
 	if len(parts) != 4 {
-		return nil, fmt.Errorf("invalid subject format in service account token: %s", sub)
+		return nil, auth.NewInvalidConfigurationError(
+			fmt.Errorf("invalid subject format in service account token: %s", sub))
 	}
 	serviceAccount := corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
@@ -91,7 +117,7 @@ func (p Provider) NewControllerToken(ctx context.Context, opts ...auth.Option) (
 	}
 	if err := o.Client.SubResource("token").Create(ctx, &serviceAccount, tokenReq); err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes token for controller service account '%s': %w",
-			client.ObjectKeyFromObject(&serviceAccount), err)
+			client.ObjectKeyFromObject(&serviceAccount), auth.ClassifyError(err))
 	}
 	token := tokenReq.Status.Token
 
@@ -132,16 +158,32 @@ func (Provider) NewTokenForServiceAccount(ctx context.Context, oidcToken string,
 	}, nil
 }
 
+// GetAccessTokenOptionSetsForArtifactRepository implements auth.ArtifactRepositoryProvider.
+// The generic provider does not issue registry credentials, it returns
+// empty options for any artifact repository.
+func (Provider) GetAccessTokenOptionSetsForArtifactRepository(string, ...auth.Option) ([][]auth.Option, error) {
+	return nil, nil
+}
+
 // GetAccessTokenOptionsForCluster implements auth.RESTConfigProvider.
 func (Provider) GetAccessTokenOptionsForCluster(opts ...auth.Option) ([][]auth.Option, error) {
 
 	var o auth.Options
 	o.Apply(opts...)
 
-	audiences := o.Audiences
-	if len(audiences) == 0 {
-		// Use cluster address as the default audience.
-		audiences = []string{o.ClusterAddress}
+	// The client certificate replaces the service account token, unless
+	// both are explicitly allowed.
+	if o.ClientCertData != "" && !o.AllowClientCertificateWithToken {
+		if o.ServiceAccountName != "" {
+			return nil, auth.NewInvalidConfigurationError(
+				errors.New("the client certificate and the service account token are mutually exclusive"))
+		}
+		return nil, nil
+	}
+
+	audiences, err := getClusterAudiences(o)
+	if err != nil {
+		return nil, err
 	}
 
 	return [][]auth.Option{{auth.WithAudiences(audiences...)}}, nil
@@ -151,7 +193,10 @@ func (Provider) GetAccessTokenOptionsForCluster(opts ...auth.Option) ([][]auth.O
 func (Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 	opts ...auth.Option) (*auth.RESTConfig, error) {
 
-	token := accessTokens[0].(*Token)
+	var token *Token
+	if len(accessTokens) > 0 {
+		token = accessTokens[0].(*Token)
+	}
 
 	var o auth.Options
 	o.Apply(opts...)
@@ -159,7 +204,7 @@ func (Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 	// Parse the cluster address.
 	host := o.ClusterAddress
 	if host == "" {
-		return nil, errors.New("cluster address is required to create a REST config")
+		return nil, auth.NewInvalidConfigurationError(errors.New("cluster address is required to create a REST config"))
 	}
 	var err error
 	host, err = auth.ParseClusterAddress(host)
@@ -167,18 +212,64 @@ func (Provider) NewRESTConfig(ctx context.Context, accessTokens []auth.Token,
 		return nil, fmt.Errorf("failed to parse cluster address %s: %w", o.ClusterAddress, err)
 	}
 
+	if token == nil && o.ClientCertData == "" {
+		return nil, auth.NewInvalidConfigurationError(
+			errors.New("an access token or a client certificate is required to create a REST config"))
+	}
+
+	conf := &auth.RESTConfig{Host: host}
+
 	// Get CA if provided.
-	var caData []byte
 	if o.CAData != "" {
-		caData = []byte(o.CAData)
+		conf.CAData = []byte(o.CAData)
 	}
 
-	return &auth.RESTConfig{
-		Host:        host,
-		CAData:      caData,
-		BearerToken: token.Token,
-		ExpiresAt:   token.ExpiresAt,
-	}, nil
+	if token != nil {
+		// Validate the audiences of the token against the ones expected by the
+		// cluster, to fail with a descriptive error instead of an opaque 401.
+		if err := validateTokenAudiences(token.Token, o); err != nil {
+			return nil, err
+		}
+		conf.BearerToken = token.Token
+		conf.ExpiresAt = token.ExpiresAt
+	}
+
+	if o.ClientCertData != "" {
+		notAfter, err := validateClientCertificate(o)
+		if err != nil {
+			return nil, err
+		}
+		conf.CertData = []byte(o.ClientCertData)
+		conf.KeyData = []byte(o.ClientKeyData)
+
+		// The REST config expires with the first of its credentials.
+		if conf.ExpiresAt.IsZero() || notAfter.Before(conf.ExpiresAt) {
+			conf.ExpiresAt = notAfter
+		}
+	}
+
+	return conf, nil
+}
+
+// validateClientCertificate returns an error if the client certificate and
+// key of the options are not a valid pair or if the certificate has expired,
+// otherwise the expiration time of the certificate.
+func validateClientCertificate(o auth.Options) (time.Time, error) {
+	if o.ClientKeyData == "" {
+		return time.Time{}, auth.NewInvalidConfigurationError(
+			errors.New("the client key is required along with the client certificate"))
+	}
+	pair, err := tls.X509KeyPair([]byte(o.ClientCertData), []byte(o.ClientKeyData))
+	if err != nil {
+		return time.Time{}, auth.NewInvalidConfigurationError(
+			fmt.Errorf("invalid client certificate and key pair: %w", err))
+	}
+	if notAfter := pair.Leaf.NotAfter; time.Now().After(notAfter) {
+		return time.Time{}, auth.NewInvalidConfigurationError(
+			fmt.Errorf("the client certificate '%s' expired at %s",
+				pair.Leaf.Subject.CommonName, notAfter.Format(time.RFC3339)))
+	}
+	return pair.Leaf.NotAfter, nil
 }
 
 func (p Provider) impl() Implementation {
@@ -188,14 +279,84 @@ func (p Provider) impl() Implementation {
 	return p.Implementation
 }
 
+// getClusterAudiences returns the audiences configured in the options,
+// defaulting to the normalized cluster address. The cluster address as
+// configured is also included when it differs from the normalized one,
+// so that tokens are accepted by API servers expecting either form.
+func getClusterAudiences(o auth.Options) ([]string, error) {
+	if len(o.Audiences) > 0 {
+		return o.Audiences, nil
+	}
+
+	// Use cluster address as the default audience.
+	aud, err := normalizeAudience(o.ClusterAddress)
+	if err != nil {
+		return nil, err
+	}
+	if aud != o.ClusterAddress {
+		return []string{aud, o.ClusterAddress}, nil
+	}
+	return []string{aud}, nil
+}
+
+// normalizeAudience returns the canonical form of a cluster address used
+// as audience, i.e. with the https scheme, a lowercase host and no trailing
+// slash. Unlike auth.ParseClusterAddress, the default port is not added, as
+// the audience must match exactly the one expected by the API server.
+func normalizeAudience(address string) (string, error) {
+	if address == "" {
+		return "", auth.NewInvalidConfigurationError(
+			errors.New("cluster address is required to create a REST config"))
+	}
+	if !strings.Contains(address, "://") {
+		address = fmt.Sprintf("https://%s", address)
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", auth.NewInvalidConfigurationError(
+			fmt.Errorf("failed to parse cluster address '%s' as audience: %w", address, err))
+	}
+	if u.Scheme != "https" {
+		return "", auth.NewInvalidConfigurationError(
+			fmt.Errorf("the cluster address '%s' must use https scheme to be used as audience", address))
+	}
+	return fmt.Sprintf("https://%s%s", strings.ToLower(u.Host), strings.TrimRight(u.Path, "/")), nil
+}
+
+// validateTokenAudiences returns an error if none of the audiences of
+// the token matches the audiences expected by the cluster.
+func validateTokenAudiences(token string, o auth.Options) error {
+	expected, err := getClusterAudiences(o)
+	if err != nil {
+		return err
+	}
+	tok, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return auth.NewInvalidConfigurationError(fmt.Errorf("failed to parse access token: %w", err))
+	}
+	actual, err := tok.Claims.GetAudience()
+	if err != nil {
+		return auth.NewInvalidConfigurationError(fmt.Errorf("failed to get audiences from access token: %w", err))
+	}
+	for _, aud := range actual {
+		if slices.Contains(expected, aud) {
+			return nil
+		}
+	}
+	return auth.NewInvalidConfigurationError(
+		fmt.Errorf("the access token audiences [%s] do not match the audiences expected by the cluster [%s]",
+			strings.Join(actual, ", "), strings.Join(expected, ", ")))
+}
+
 func getExpirationFromToken(token string) (*time.Time, error) {
 	tok, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse service account token: %w", err)
+		return nil, auth.NewInvalidConfigurationError(fmt.Errorf("failed to parse service account token: %w", err))
 	}
 	exp, err := tok.Claims.GetExpirationTime()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get expiration time from service account token: %w", err)
+		return nil, auth.NewInvalidConfigurationError(
+			fmt.Errorf("failed to get expiration time from service account token: %w", err))
 	}
 	return &exp.Time, nil
 }
@@ -18,15 +18,39 @@ package generic_test
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/golang-jwt/jwt/v5"
 	. "github.com/onsi/gomega"
+	authnv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/auth"
@@ -43,6 +67,41 @@ func TestProvider_NewControllerToken(t *testing.T) {
 		g.Expect(token).To(BeNil())
 	})
 
+	t.Run("with OIDC token file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		exp := time.Now().Add(time.Hour).Truncate(time.Second)
+		oidcToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"exp": exp.Unix(),
+		}).SignedString([]byte("secret"))
+		g.Expect(err).NotTo(HaveOccurred())
+		path := filepath.Join(t.TempDir(), "token")
+		g.Expect(os.WriteFile(path, []byte(oidcToken), 0o600)).To(Succeed())
+
+		token, err := generic.Provider{}.NewControllerToken(context.Background(), auth.WithOIDCTokenFile(path))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(token).To(Equal(&generic.Token{
+			Token:     oidcToken,
+			ExpiresAt: exp,
+		}))
+	})
+
+	t.Run("with expired OIDC token file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		oidcToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"exp": time.Now().Add(-time.Hour).Unix(),
+		}).SignedString([]byte("secret"))
+		g.Expect(err).NotTo(HaveOccurred())
+		path := filepath.Join(t.TempDir(), "token")
+		g.Expect(os.WriteFile(path, []byte(oidcToken), 0o600)).To(Succeed())
+
+		token, err := generic.Provider{}.NewControllerToken(context.Background(), auth.WithOIDCTokenFile(path))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("expired at"))
+		g.Expect(token).To(BeNil())
+	})
+
 	t.Run("with audiences", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -94,6 +153,89 @@ func TestProvider_NewControllerToken(t *testing.T) {
 	})
 }
 
+func TestProvider_NewControllerToken_ErrorClassification(t *testing.T) {
+	saToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "system:serviceaccount:flux-system:controller",
+	}).SignedString([]byte("secret"))
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	gr := schema.GroupResource{Resource: "serviceaccounts"}
+
+	for _, tt := range []struct {
+		name       string
+		saToken    string
+		requestErr error
+		wantErr    error
+		retryable  bool
+	}{
+		{
+			name:    "invalid service account token",
+			saToken: "foobar",
+			wantErr: auth.ErrInvalidConfiguration,
+		},
+		{
+			name:       "forbidden",
+			requestErr: apierrors.NewForbidden(gr, "controller", errors.New("cannot create resource")),
+			wantErr:    auth.ErrPermissionDenied,
+		},
+		{
+			name:       "service account not found",
+			requestErr: apierrors.NewNotFound(gr, "controller"),
+			wantErr:    auth.ErrInvalidConfiguration,
+		},
+		{
+			name:       "internal error",
+			requestErr: apierrors.NewInternalError(errors.New("etcdserver: request timed out")),
+			wantErr:    auth.ErrTransient,
+			retryable:  true,
+		},
+		{
+			name:       "service unavailable",
+			requestErr: apierrors.NewServiceUnavailable("the server is currently unable to handle the request"),
+			wantErr:    auth.ErrTransient,
+			retryable:  true,
+		},
+		{
+			name:       "too many requests",
+			requestErr: apierrors.NewTooManyRequests("rate limited", 1),
+			wantErr:    auth.ErrTransient,
+			retryable:  true,
+		},
+		{
+			name:       "network error",
+			requestErr: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			wantErr:    auth.ErrTransient,
+			retryable:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			b := saToken
+			if tt.saToken != "" {
+				b = tt.saToken
+			}
+			m := &mockImplementation{t: t, b: []byte(b)}
+
+			c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceCreate: func(context.Context, client.Client, string, client.Object,
+					client.Object, ...client.SubResourceCreateOption) error {
+					return tt.requestErr
+				},
+			}).Build()
+
+			token, err := generic.Provider{m}.NewControllerToken(context.Background(), auth.WithClient(c))
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(token).To(BeNil())
+			g.Expect(err).To(MatchError(tt.wantErr))
+			if tt.requestErr != nil {
+				g.Expect(err).To(MatchError(tt.requestErr))
+			}
+			g.Expect(auth.IsRetryable(err)).To(Equal(tt.retryable))
+		})
+	}
+}
+
 func TestProvider_NewTokenForServiceAccount(t *testing.T) {
 	g := NewWithT(t)
 
@@ -324,7 +466,168 @@ func TestProvider_NewRESTConfig_EndToEnd(t *testing.T) {
 	g.Expect(err.Error()).To(ContainSubstring(`forbidden: User "system:serviceaccount:default:tenant" cannot get resource "namespaces"`))
 }
 
+func TestProvider_NewRESTConfig_AudienceValidation(t *testing.T) {
+	saToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "system:serviceaccount:flux-system:controller",
+	}).SignedString([]byte("secret"))
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	for _, tt := range []struct {
+		name           string
+		clusterAddress string
+		audiences      []string
+		tokenAudiences []string
+		err            string
+	}{
+		{
+			name:           "default audience matches",
+			clusterAddress: "https://Example.com/",
+			tokenAudiences: []string{"https://example.com"},
+		},
+		{
+			name:           "one of the audiences matches",
+			clusterAddress: "https://example.com",
+			audiences:      []string{"audience1", "audience2"},
+			tokenAudiences: []string{"audience0", "audience2"},
+		},
+		{
+			name:           "default audience mismatch",
+			clusterAddress: "https://example.com",
+			tokenAudiences: []string{"https://example.com:443"},
+			err:            "the access token audiences [https://example.com:443] do not match the audiences expected by the cluster [https://example.com]",
+		},
+		{
+			name:           "default audience mismatch with trailing slash",
+			clusterAddress: "https://example.com/",
+			tokenAudiences: []string{"https://example.com:443"},
+			err:            "the access token audiences [https://example.com:443] do not match the audiences expected by the cluster [https://example.com, https://example.com/]",
+		},
+		{
+			name:           "audiences mismatch",
+			clusterAddress: "https://example.com",
+			audiences:      []string{"audience1", "audience2"},
+			tokenAudiences: []string{"https://example.com"},
+			err:            "the access token audiences [https://example.com] do not match the audiences expected by the cluster [audience1, audience2]",
+		},
+		{
+			name:           "token without audiences",
+			clusterAddress: "https://example.com",
+			err:            "the access token audiences [] do not match the audiences expected by the cluster [https://example.com]",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// The API server mock mints the token with the configured
+			// audiences, regardless of the requested ones.
+			claims := jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()}
+			if len(tt.tokenAudiences) > 0 {
+				claims["aud"] = tt.tokenAudiences
+			}
+			accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+			g.Expect(err).NotTo(HaveOccurred())
+			c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceCreate: func(_ context.Context, _ client.Client, _ string, _ client.Object,
+					subResource client.Object, _ ...client.SubResourceCreateOption) error {
+					subResource.(*authnv1.TokenRequest).Status.Token = accessToken
+					return nil
+				},
+			}).Build()
+
+			opts := []auth.Option{
+				auth.WithClient(c),
+				auth.WithClusterAddress(tt.clusterAddress),
+			}
+			if len(tt.audiences) > 0 {
+				opts = append(opts, auth.WithAudiences(tt.audiences...))
+			}
+
+			m := &mockImplementation{t: t, b: []byte(saToken)}
+			conf, err := auth.GetRESTConfig(context.Background(), generic.Provider{m}, opts...)
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(Equal(tt.err))
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				g.Expect(conf).To(BeNil())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(conf.BearerToken).To(Equal(accessToken))
+		})
+	}
+}
+
+func TestProvider_GetAccessTokenOptionSetsForArtifactRepository(t *testing.T) {
+	for _, artifactRepository := range []string{
+		"ghcr.io/fluxcd/source-controller",
+		"012345678901.dkr.ecr.us-east-1.amazonaws.com/foo",
+		"registry.example.com:5000",
+	} {
+		t.Run(artifactRepository, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts, err := generic.Provider{}.GetAccessTokenOptionSetsForArtifactRepository(artifactRepository)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(opts).To(BeEmpty())
+		})
+	}
+}
+
 func TestProvider_GetAccessTokenOptionsForCluster(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		clusterAddress string
+		audiences      []string
+		err            string
+	}{
+		{
+			name:           "trailing slash",
+			clusterAddress: "https://example.com/",
+			audiences:      []string{"https://example.com", "https://example.com/"},
+		},
+		{
+			name:           "uppercase host",
+			clusterAddress: "https://EXAMPLE.com:6443",
+			audiences:      []string{"https://example.com:6443", "https://EXAMPLE.com:6443"},
+		},
+		{
+			name:           "with path",
+			clusterAddress: "https://example.com/k8s/clusters/c-1/",
+			audiences:      []string{"https://example.com/k8s/clusters/c-1", "https://example.com/k8s/clusters/c-1/"},
+		},
+		{
+			name:           "without scheme",
+			clusterAddress: "example.com",
+			audiences:      []string{"https://example.com", "example.com"},
+		},
+		{
+			name:           "http scheme",
+			clusterAddress: "http://example.com",
+			err:            "the cluster address 'http://example.com' must use https scheme to be used as audience",
+		},
+		{
+			name: "without address",
+			err:  "cluster address is required to create a REST config",
+		},
+	} {
+		t.Run("normalizes default audience with "+tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			opts, err := generic.Provider{}.GetAccessTokenOptionsForCluster(
+				auth.WithClusterAddress(tt.clusterAddress))
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(Equal(tt.err))
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(opts).To(HaveLen(1))
+			var o auth.Options
+			o.Apply(opts[0]...)
+			g.Expect(o.Audiences).To(Equal(tt.audiences))
+		})
+	}
+
 	t.Run("without audiences", func(t *testing.T) {
 		g := NewWithT(t)
 		opts, err := generic.Provider{}.GetAccessTokenOptionsForCluster(
@@ -350,3 +653,255 @@ func TestProvider_GetAccessTokenOptionsForCluster(t *testing.T) {
 		g.Expect(o.Audiences).To(ConsistOf("audience1", "audience2"))
 	})
 }
+
+// newClientCertificate returns a PEM-encoded client certificate signed by
+// the given CA and expiring at notAfter, along with its PEM-encoded key.
+func newClientCertificate(t *testing.T, ca *x509.Certificate, caKey crypto.Signer,
+	commonName string, notAfter time.Time) (string, string) {
+	t.Helper()
+	g := NewWithT(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-2 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	g.Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+// newClientCA returns a self-signed CA for the client certificates.
+func newClientCA(t *testing.T) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+	g := NewWithT(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	g.Expect(err).NotTo(HaveOccurred())
+	ca, err := x509.ParseCertificate(der)
+	g.Expect(err).NotTo(HaveOccurred())
+	return ca, key
+}
+
+func TestProvider_NewRESTConfig_ClientCertificate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	ca, caKey := newClientCA(t)
+	notAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+	certData, keyData := newClientCertificate(t, ca, caKey, "flux", notAfter)
+
+	// Start an API server mock requiring client certificates signed by the CA.
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	const (
+		namespace  = "default"
+		cmName     = "kubeconfig"
+		secretName = "client-cert"
+	)
+	kubeconfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cmName,
+			Namespace: namespace,
+		},
+		Data: map[string]string{
+			meta.KubeConfigKeyProvider:             generic.ProviderName,
+			meta.KubeConfigKeyAddress:              server.URL,
+			meta.KubeConfigKeyCACert:               serverCA,
+			meta.KubeConfigKeyClientCertSecretName: secretName,
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(certData),
+			corev1.TLSPrivateKeyKey: []byte(keyData),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(kubeconfig, secret).Build()
+	ref := meta.KubeConfigReference{ConfigMapRef: &meta.LocalObjectReference{Name: cmName}}
+
+	t.Run("authenticates with the client certificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		conf, err := utils.GetRESTConfig(ctx, ref, namespace, c)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(conf.TLSClientConfig.CertData).To(Equal([]byte(certData)))
+		g.Expect(conf.TLSClientConfig.KeyData).To(Equal([]byte(keyData)))
+
+		httpClient, err := rest.HTTPClientFor(conf)
+		g.Expect(err).NotTo(HaveOccurred())
+		resp, err := httpClient.Get(server.URL)
+		g.Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(string(body)).To(Equal("flux"))
+	})
+
+	t.Run("expires with the client certificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		conf, err := auth.GetRESTConfig(ctx, generic.Provider{},
+			auth.WithClusterAddress(server.URL),
+			auth.WithClientCertificate(certData, keyData))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(conf.BearerToken).To(BeEmpty())
+		g.Expect(conf.ExpiresAt).To(BeTemporally("==", notAfter))
+	})
+
+	t.Run("is rejected by the server without the client certificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		conf := &rest.Config{
+			Host:            server.URL,
+			TLSClientConfig: rest.TLSClientConfig{CAData: []byte(serverCA)},
+		}
+		httpClient, err := rest.HTTPClientFor(conf)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, err = httpClient.Get(server.URL)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails without the keys in the secret", func(t *testing.T) {
+		g := NewWithT(t)
+
+		secret := secret.DeepCopy()
+		delete(secret.Data, corev1.TLSPrivateKeyKey)
+		c := fake.NewClientBuilder().WithObjects(kubeconfig, secret).Build()
+		_, err := utils.GetRESTConfig(ctx, ref, namespace, c)
+		g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+		g.Expect(err.Error()).To(ContainSubstring("must contain the 'tls.crt' and 'tls.key' keys"))
+	})
+}
+
+func TestProvider_NewRESTConfig_ClientCertificateValidation(t *testing.T) {
+	ca, caKey := newClientCA(t)
+	certData, keyData := newClientCertificate(t, ca, caKey, "flux", time.Now().Add(time.Hour))
+	expiredCertData, expiredKeyData := newClientCertificate(t, ca, caKey, "expired", time.Now().Add(-time.Hour))
+
+	for _, tt := range []struct {
+		name     string
+		certData string
+		keyData  string
+		err      string
+	}{
+		{
+			name:     "missing key",
+			certData: certData,
+			err:      "the client key is required along with the client certificate",
+		},
+		{
+			name:     "mismatched key",
+			certData: certData,
+			keyData:  expiredKeyData,
+			err:      "invalid client certificate and key pair: tls: private key does not match public key",
+		},
+		{
+			name:     "expired certificate",
+			certData: expiredCertData,
+			keyData:  expiredKeyData,
+			err:      "the client certificate 'expired' expired at",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			conf, err := generic.Provider{}.NewRESTConfig(context.Background(), nil,
+				auth.WithClusterAddress("https://example.com"),
+				auth.WithClientCertificate(tt.certData, tt.keyData))
+			g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+			g.Expect(err.Error()).To(HavePrefix(tt.err))
+			g.Expect(conf).To(BeNil())
+		})
+	}
+
+	t.Run("requires an access token or a client certificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := generic.Provider{}.NewRESTConfig(context.Background(), nil,
+			auth.WithClusterAddress("https://example.com"))
+		g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+		g.Expect(err.Error()).To(Equal("an access token or a client certificate is required to create a REST config"))
+	})
+
+	t.Run("rejects the service account token with the client certificate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := generic.Provider{}.GetAccessTokenOptionsForCluster(
+			auth.WithClusterAddress("https://example.com"),
+			auth.WithServiceAccountName("tenant"),
+			auth.WithClientCertificate(certData, keyData))
+		g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+		g.Expect(err.Error()).To(Equal("the client certificate and the service account token are mutually exclusive"))
+	})
+
+	t.Run("uses both the token and the client certificate when allowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		opts := []auth.Option{
+			auth.WithClusterAddress("https://example.com"),
+			auth.WithServiceAccountName("tenant"),
+			auth.WithClientCertificate(certData, keyData),
+			auth.WithAllowClientCertificateWithToken(),
+		}
+		atOpts, err := generic.Provider{}.GetAccessTokenOptionsForCluster(opts...)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(atOpts).To(HaveLen(1))
+
+		// The token expires after the certificate.
+		tokenExpiresAt := time.Now().Add(2 * time.Hour)
+		accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"aud": []string{"https://example.com"},
+			"exp": tokenExpiresAt.Unix(),
+		}).SignedString([]byte("secret"))
+		g.Expect(err).NotTo(HaveOccurred())
+		conf, err := generic.Provider{}.NewRESTConfig(context.Background(),
+			[]auth.Token{&generic.Token{Token: accessToken, ExpiresAt: tokenExpiresAt}}, opts...)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(conf.BearerToken).To(Equal(accessToken))
+		g.Expect(conf.CertData).To(Equal([]byte(certData)))
+		g.Expect(conf.KeyData).To(Equal([]byte(keyData)))
+		g.Expect(conf.ExpiresAt).To(BeTemporally("<", tokenExpiresAt))
+		g.Expect(time.Until(conf.ExpiresAt)).To(BeNumerically("~", time.Hour, 10*time.Second))
+	})
+}
@@ -80,7 +80,8 @@ func GetGitCredentials(ctx context.Context, provider GitCredentialsProvider,
 
 	gitURL := o.GitURL
 	if gitURL == nil {
-		return nil, errors.New("a Git repository URL is required for issuing Git credentials")
+		return nil, NewInvalidConfigurationError(
+			errors.New("a Git repository URL is required for issuing Git credentials"))
 	}
 
 	gitInput, err := provider.ParseGitRepository(gitURL)
@@ -104,7 +105,7 @@ func GetGitCredentials(ctx context.Context, provider GitCredentialsProvider,
 	newGitCredentials := func() (*GitCredentials, error) {
 		creds, err := provider.NewGitCredentials(ctx, gitInput, accessToken, opts...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create Git credentials: %w", err)
+			return nil, fmt.Errorf("failed to create Git credentials: %w", ClassifyError(err))
 		}
 		return creds, nil
 	}
@@ -130,7 +131,7 @@ func GetGitCredentials(ctx context.Context, provider GitCredentialsProvider,
 			return nil, err
 		}
 	}
-	accessTokenCacheKey := buildAccessTokenCacheKey(provider, audiences,
+	accessTokenCacheKey := buildAccessTokenCacheKey(provider.GetName(), audiences,
 		providerIdentity, serviceAccount, accessTokenOpts...)
 	cacheKey := buildCacheKey(
 		fmt.Sprintf("accessTokenCacheKey=%s", accessTokenCacheKey),
@@ -145,7 +146,7 @@ func GetGitCredentials(ctx context.Context, provider GitCredentialsProvider,
 	// Get credentials from cache.
 	creds, _, err := o.Cache.GetOrSet(ctx, cacheKey, func(ctx context.Context) (cache.Token, error) {
 		return newGitCredentials()
-	}, cache.WithInvolvedObject(kind, name, namespace, operation))
+	}, cache.WithInvolvedObject(kind, name, namespace, operation), cacheMetadata(provider, serviceAccount))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ReadOIDCTokenFile reads the OIDC token from the file configured with
+// WithOIDCTokenFile and validates that it is not expired. The file is
+// read on every call so that tokens rotated on disk are picked up.
+func (o *Options) ReadOIDCTokenFile() (string, error) {
+	if o.OIDCTokenFile == "" {
+		return "", NewInvalidConfigurationError(fmt.Errorf("OIDC token file is not configured"))
+	}
+
+	b, err := os.ReadFile(o.OIDCTokenFile)
+	if err != nil {
+		// The file may not have been (re)written yet by the token issuer.
+		return "", NewTransientError(fmt.Errorf("failed to read OIDC token file %s: %w", o.OIDCTokenFile, err))
+	}
+	token := strings.TrimSpace(string(b))
+
+	tok, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return "", NewInvalidConfigurationError(
+			fmt.Errorf("failed to parse OIDC token from file %s: %w", o.OIDCTokenFile, err))
+	}
+	exp, err := tok.Claims.GetExpirationTime()
+	if err != nil {
+		return "", NewInvalidConfigurationError(
+			fmt.Errorf("failed to get expiration time from OIDC token in file %s: %w", o.OIDCTokenFile, err))
+	}
+	if exp == nil {
+		return "", NewInvalidConfigurationError(
+			fmt.Errorf("OIDC token in file %s has no expiration time", o.OIDCTokenFile))
+	}
+	if !time.Now().Before(exp.Time) {
+		// The token is read on every call, so a rotated token is picked up on retry.
+		return "", NewTransientError(fmt.Errorf("OIDC token in file %s expired at %s",
+			o.OIDCTokenFile, exp.Time.UTC().Format(time.RFC3339)))
+	}
+
+	return token, nil
+}
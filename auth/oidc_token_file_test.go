@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/auth"
+)
+
+func TestOptions_ReadOIDCTokenFile(t *testing.T) {
+	newToken := func(t *testing.T, claims jwt.MapClaims) string {
+		t.Helper()
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	validToken := newToken(t, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()})
+
+	for _, tt := range []struct {
+		name     string
+		contents string
+		noFile   bool
+		expected string
+		err      string
+	}{
+		{
+			name:     "valid token",
+			contents: validToken,
+			expected: validToken,
+		},
+		{
+			name:     "valid token with trailing newline",
+			contents: validToken + "\n",
+			expected: validToken,
+		},
+		{
+			name:     "expired token",
+			contents: newToken(t, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}),
+			err:      "expired at",
+		},
+		{
+			name:     "token without expiration",
+			contents: newToken(t, jwt.MapClaims{"sub": "foo"}),
+			err:      "has no expiration time",
+		},
+		{
+			name:     "invalid token",
+			contents: "not-a-jwt",
+			err:      "failed to parse OIDC token",
+		},
+		{
+			name:   "missing file",
+			noFile: true,
+			err:    "failed to read OIDC token file",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			path := filepath.Join(t.TempDir(), "token")
+			if !tt.noFile {
+				g.Expect(os.WriteFile(path, []byte(tt.contents), 0o600)).To(Succeed())
+			}
+
+			var o auth.Options
+			o.Apply(auth.WithOIDCTokenFile(path))
+
+			token, err := o.ReadOIDCTokenFile()
+			if tt.err == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(token).To(Equal(tt.expected))
+			} else {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				g.Expect(token).To(BeEmpty())
+			}
+		})
+	}
+
+	t.Run("token is re-read on every call", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := filepath.Join(t.TempDir(), "token")
+		g.Expect(os.WriteFile(path, []byte(validToken), 0o600)).To(Succeed())
+
+		var o auth.Options
+		o.Apply(auth.WithOIDCTokenFile(path))
+
+		token, err := o.ReadOIDCTokenFile()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(token).To(Equal(validToken))
+
+		rotatedToken := newToken(t, jwt.MapClaims{"exp": time.Now().Add(2 * time.Hour).Unix()})
+		g.Expect(os.WriteFile(path, []byte(rotatedToken), 0o600)).To(Succeed())
+
+		token, err = o.ReadOIDCTokenFile()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(token).To(Equal(rotatedToken))
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		g := NewWithT(t)
+		var o auth.Options
+		_, err := o.ReadOIDCTokenFile()
+		g.Expect(err).To(MatchError("OIDC token file is not configured"))
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/fluxcd/pkg/cache"
@@ -32,21 +33,31 @@ type Option func(*Options)
 // Options contains options for configuring the behavior of the provider methods.
 // Not all providers/methods support all options.
 type Options struct {
-	Client                  client.Client
-	Cache                   *cache.TokenCache
-	ServiceAccountName      string
-	ServiceAccountNamespace string
-	InvolvedObject          cache.InvolvedObject
-	Audiences               []string
-	Scopes                  []string
-	STSRegion               string
-	STSEndpoint             string
-	ProxyURL                *url.URL
-	GitURL                  *url.URL
-	CAData                  string
-	ClusterResource         string
-	ClusterAddress          string
-	AllowShellOut           bool
+	Client                          client.Client
+	Cache                           *cache.TokenCache
+	ServiceAccountName              string
+	ServiceAccountNamespace         string
+	InvolvedObject                  cache.InvolvedObject
+	Audiences                       []string
+	Scopes                          []string
+	STSRegion                       string
+	STSEndpoint                     string
+	AWSRegion                       string
+	ProxyURL                        *url.URL
+	GitURL                          *url.URL
+	CAData                          string
+	ClusterResource                 string
+	ClusterAddress                  string
+	ClientCertData                  string
+	ClientKeyData                   string
+	AllowClientCertificateWithToken bool
+	AllowShellOut                   bool
+	OIDCTokenFile                   string
+	AllowedServiceAccountNamespaces []string
+	ProviderIdentity                string
+	AzureCloud                      *cloud.Configuration
+	AuditCallback                   func(AuditEvent)
+	CallerRef                       *CallerRef
 }
 
 // ShouldGetServiceAccountToken returns true if ServiceAccount token should be retrieved.
@@ -116,6 +127,15 @@ func WithSTSEndpoint(stsEndpoint string) Option {
 	}
 }
 
+// WithAWSRegion sets the region of the AWS STS service, taking precedence
+// over the region of WithSTSRegion, the environment and the EC2 instance
+// metadata. The regional endpoint of the STS service is always used.
+func WithAWSRegion(region string) Option {
+	return func(o *Options) {
+		o.AWSRegion = region
+	}
+}
+
 // WithProxyURL sets a *url.URL for an HTTP/S proxy for acquiring the token.
 func WithProxyURL(proxyURL url.URL) Option {
 	return func(o *Options) {
@@ -159,6 +179,26 @@ func WithClusterAddress(clusterAddress string) Option {
 	}
 }
 
+// WithClientCertificate sets the PEM-encoded client certificate and key
+// for authenticating with the Kubernetes API server of a REST config over
+// mTLS. Supported only by the generic provider.
+func WithClientCertificate(certData, keyData string) Option {
+	return func(o *Options) {
+		o.ClientCertData = certData
+		o.ClientKeyData = keyData
+	}
+}
+
+// WithAllowClientCertificateWithToken allows a REST config to authenticate
+// with both a client certificate and a service account token. By default,
+// the client certificate replaces the token, and configuring a service
+// account along with a client certificate is rejected.
+func WithAllowClientCertificateWithToken() Option {
+	return func(o *Options) {
+		o.AllowClientCertificateWithToken = true
+	}
+}
+
 // WithAllowShellOut allows the provider to shell out to binary tools
 // for acquiring controller tokens. MUST be used only by the Flux CLI,
 // i.e. in the github.com/fluxcd/flux2 Git repository.
@@ -168,6 +208,49 @@ func WithAllowShellOut() Option {
 	}
 }
 
+// WithOIDCTokenFile sets the path to a file containing an OIDC token (JWT)
+// issued by the platform where the controller runs. When set, providers use
+// this token as the subject token for their STS exchange in NewControllerToken
+// instead of the default controller credentials. The file is read on every
+// token mint so that rotation by the platform is honored. This is meant for
+// controllers running outside Kubernetes, e.g. in CI or on VMs.
+func WithOIDCTokenFile(path string) Option {
+	return func(o *Options) {
+		o.OIDCTokenFile = path
+	}
+}
+
+// WithAllowedServiceAccountNamespaces allows the use of object-level workload
+// identity for the service accounts in the given namespaces even when the
+// ObjectLevelWorkloadIdentity feature gate is disabled, e.g. for cluster
+// admins to opt in specific tenants while enforcing controller-level
+// identities for all the others.
+func WithAllowedServiceAccountNamespaces(namespaces []string) Option {
+	return func(o *Options) {
+		o.AllowedServiceAccountNamespaces = namespaces
+	}
+}
+
+// WithProviderIdentity sets the identity impersonated by the service account,
+// as returned by Provider.GetIdentity, for computing the cache key of a
+// service account token with CacheKey. It is ignored by the providers, which
+// always read the identity from the service account annotations.
+func WithProviderIdentity(identity string) Option {
+	return func(o *Options) {
+		o.ProviderIdentity = identity
+	}
+}
+
+// WithAzureCloud sets the Azure cloud configuration, e.g. cloud.AzureChina or
+// cloud.AzureGovernment, controlling the authority host and the service endpoints
+// used by the azure provider. It takes precedence over the AZURE_ENVIRONMENT
+// environment variable.
+func WithAzureCloud(conf cloud.Configuration) Option {
+	return func(o *Options) {
+		o.AzureCloud = &conf
+	}
+}
+
 // Apply applies the given slice of Option(s) to the Options struct.
 func (o *Options) Apply(opts ...Option) {
 	for _, opt := range opts {
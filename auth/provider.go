@@ -18,10 +18,20 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]Provider)
+)
+
 // Provider contains the logic to retrieve security credentials
 // for accessing resources in a cloud provider.
 type Provider interface {
@@ -52,3 +62,62 @@ type Provider interface {
 	NewTokenForServiceAccount(ctx context.Context, oidcToken string,
 		serviceAccount corev1.ServiceAccount, opts ...Option) (Token, error)
 }
+
+// Register makes the given provider available by its name with GetProvider.
+// It is meant to be called from the init function of the provider package,
+// the providers of this module registering themselves that way, which makes
+// out-of-tree providers possible. Register returns an error if the name of
+// the provider is empty or if a provider is already registered with the
+// same name.
+func Register(p Provider) error {
+	if p == nil {
+		return errors.New("provider is nil")
+	}
+	name := p.GetName()
+	if name == "" {
+		return errors.New("provider name is empty")
+	}
+
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if _, dup := providers[name]; dup {
+		return fmt.Errorf("provider '%s' is already registered", name)
+	}
+	providers[name] = p
+	return nil
+}
+
+// MustRegister is like Register but panics if the provider cannot be
+// registered. It is meant for the init functions of the built-in
+// providers, for which a failed registration is a programming error.
+func MustRegister(p Provider) {
+	if err := Register(p); err != nil {
+		panic(fmt.Sprintf("auth: %v", err))
+	}
+}
+
+// GetProvider returns the provider registered with the given name, or an
+// error listing the names of the registered providers if there is none.
+func GetProvider(name string) (Provider, error) {
+	providersMu.RLock()
+	p, ok := providers[name]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("provider '%s' not implemented, registered providers: %s",
+			name, strings.Join(ProviderNames(), ", "))
+	}
+	return p, nil
+}
+
+// ProviderNames returns the sorted names of the registered providers, e.g.
+// for validating the provider names given in controller flags.
+func ProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -21,6 +21,8 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -239,3 +241,54 @@ func (m *mockProvider) checkOptions(opts ...auth.Option) {
 	g.Expect(o.CAData).To(Equal("ca-data"))
 	g.Expect(o.AllowShellOut).To(Equal(m.paramAllowShellOut))
 }
+
+func TestRegister(t *testing.T) {
+	g := NewWithT(t)
+
+	p := &mockProvider{returnName: "test-register"}
+	g.Expect(auth.Register(p)).To(Succeed())
+
+	registered, err := auth.GetProvider("test-register")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(registered).To(BeIdenticalTo(p))
+	g.Expect(auth.ProviderNames()).To(ContainElement("test-register"))
+
+	g.Expect(auth.Register(&mockProvider{returnName: "test-register"})).
+		To(MatchError("provider 'test-register' is already registered"))
+	g.Expect(auth.Register(&mockProvider{})).
+		To(MatchError("provider name is empty"))
+	g.Expect(auth.Register(nil)).
+		To(MatchError("provider is nil"))
+
+	registered, err = auth.GetProvider("test-register")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(registered).To(BeIdenticalTo(p))
+}
+
+func TestGetProvider_Unknown(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(auth.Register(&mockProvider{returnName: "test-unknown-b"})).To(Succeed())
+	g.Expect(auth.Register(&mockProvider{returnName: "test-unknown-a"})).To(Succeed())
+
+	names := auth.ProviderNames()
+	g.Expect(slices.IsSorted(names)).To(BeTrue())
+	g.Expect(names).To(ContainElements("test-unknown-a", "test-unknown-b"))
+
+	p, err := auth.GetProvider("unknown")
+	g.Expect(err).To(MatchError("provider 'unknown' not implemented, registered providers: " +
+		strings.Join(names, ", ")))
+	g.Expect(err.Error()).To(ContainSubstring("test-unknown-a, test-unknown-b"))
+	g.Expect(p).To(BeNil())
+}
+
+func TestMustRegister(t *testing.T) {
+	g := NewWithT(t)
+
+	p := &mockProvider{returnName: "test-must-register"}
+	g.Expect(func() { auth.MustRegister(p) }).NotTo(Panic())
+	g.Expect(auth.GetProvider("test-must-register")).To(BeIdenticalTo(p))
+
+	g.Expect(func() { auth.MustRegister(p) }).
+		To(PanicWith("auth: provider 'test-must-register' is already registered"))
+}
@@ -18,6 +18,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -44,6 +45,8 @@ type ArtifactRegistryCredentialsProvider interface {
 	// it's a valid repository for the provider. As a result, it returns
 	// the input required for the provider to issue registry credentials.
 	// This input is included in the cache key for the issued credentials.
+	// It returns an *ErrUnsupportedRegistry error if the registry is not
+	// a registry of the provider.
 	ParseArtifactRepository(artifactRepository string) (string, error)
 
 	// NewArtifactRegistryCredentials takes the registry input extracted by
@@ -66,14 +69,91 @@ func (a *ArtifactRegistryCredentials) GetDuration() time.Duration {
 	return time.Until(a.ExpiresAt)
 }
 
+// ArtifactRepositoryProvider is an optional interface of the providers
+// that derive the access token options for an artifact repository from
+// its registry host, e.g. the region of an ECR registry or the scope of
+// an ACR registry. The providers not implementing it are skipped by
+// ProviderForArtifactRepository.
+type ArtifactRepositoryProvider interface {
+	Provider
+
+	// GetAccessTokenOptionSetsForArtifactRepository returns the options
+	// that must be passed to the provider to retrieve access tokens for an
+	// artifact repository. More than one access token may be required
+	// depending on the provider, with different options. Hence the return
+	// type is a slice of []Option. It returns an *ErrUnsupportedRegistry
+	// error if the registry is not a registry of the provider, so that a
+	// chain of providers can be consulted.
+	GetAccessTokenOptionSetsForArtifactRepository(artifactRepository string, opts ...Option) ([][]Option, error)
+}
+
+// ErrUnsupportedRegistry is returned when the registry of an artifact
+// repository is not a registry of the provider, e.g. an ACR registry for
+// the AWS provider, so that a chain of providers can be consulted until
+// one of them supports the registry. It is classified as
+// ErrInvalidConfiguration.
+type ErrUnsupportedRegistry struct {
+	// Provider is the name of the provider.
+	Provider string
+	// Registry is the registry host of the artifact repository.
+	Registry string
+	// Err describes the registry hosts supported by the provider.
+	Err error
+}
+
+// Error implements error.
+func (e *ErrUnsupportedRegistry) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error describing the supported registry hosts.
+func (e *ErrUnsupportedRegistry) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true for ErrInvalidConfiguration.
+func (e *ErrUnsupportedRegistry) Is(target error) bool {
+	return target == ErrInvalidConfiguration
+}
+
+// ProviderForArtifactRepository consults the given
+// providers in order and returns the first one supporting the registry of
+// the artifact repository, together with its access token options. The
+// providers not implementing ArtifactRepositoryProvider or returning an
+// *ErrUnsupportedRegistry error are skipped, and the last of these errors
+// is returned if none supports the registry.
+func ProviderForArtifactRepository(artifactRepository string,
+	providers []Provider, opts ...Option) (Provider, [][]Option, error) {
+
+	err := NewInvalidConfigurationError(fmt.Errorf("no provider to consult for artifact repository '%s'",
+		artifactRepository))
+	for _, provider := range providers {
+		repoProvider, ok := provider.(ArtifactRepositoryProvider)
+		if !ok {
+			continue
+		}
+		var accessTokenOpts [][]Option
+		accessTokenOpts, err = repoProvider.GetAccessTokenOptionSetsForArtifactRepository(artifactRepository, opts...)
+		var unsupportedErr *ErrUnsupportedRegistry
+		if errors.As(err, &unsupportedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return provider, accessTokenOpts, nil
+	}
+	return nil, nil, err
+}
+
 // GetRegistryFromArtifactRepository returns the registry from the artifact repository.
 func GetRegistryFromArtifactRepository(artifactRepository string) (string, error) {
 	registry := strings.TrimSuffix(artifactRepository, "/")
 	if strings.ContainsRune(registry, '/') {
 		ref, err := name.ParseReference(registry)
 		if err != nil {
-			return "", fmt.Errorf("failed to parse artifact repository '%s': %w",
-				artifactRepository, err)
+			return "", NewInvalidConfigurationError(fmt.Errorf("failed to parse artifact repository '%s': %w",
+				artifactRepository, err))
 		}
 		return ref.Context().RegistryStr(), nil
 	}
@@ -106,7 +186,7 @@ func GetArtifactRegistryCredentials(ctx context.Context, provider ArtifactRegist
 	newArtifactRegistryCredentials := func() (*ArtifactRegistryCredentials, error) {
 		creds, err := provider.NewArtifactRegistryCredentials(ctx, registryInput, accessToken, opts...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create artifact registry credentials: %w", err)
+			return nil, fmt.Errorf("failed to create artifact registry credentials: %w", ClassifyError(err))
 		}
 		return creds, nil
 	}
@@ -135,7 +215,7 @@ func GetArtifactRegistryCredentials(ctx context.Context, provider ArtifactRegist
 			return nil, err
 		}
 	}
-	accessTokenCacheKey := buildAccessTokenCacheKey(provider, audiences,
+	accessTokenCacheKey := buildAccessTokenCacheKey(provider.GetName(), audiences,
 		providerIdentity, serviceAccount, accessTokenOpts...)
 	cacheKey := buildCacheKey(
 		fmt.Sprintf("accessTokenCacheKey=%s", accessTokenCacheKey),
@@ -150,7 +230,7 @@ func GetArtifactRegistryCredentials(ctx context.Context, provider ArtifactRegist
 	// Get credentials from cache.
 	creds, _, err := o.Cache.GetOrSet(ctx, cacheKey, func(ctx context.Context) (cache.Token, error) {
 		return newArtifactRegistryCredentials()
-	}, cache.WithInvolvedObject(kind, name, namespace, operation))
+	}, cache.WithInvolvedObject(kind, name, namespace, operation), cacheMetadata(provider, serviceAccount))
 	if err != nil {
 		return nil, err
 	}
@@ -18,7 +18,9 @@ package auth_test
 
 import (
 	"context"
+	"errors"
 	"net/url"
+	"slices"
 	"testing"
 	"time"
 
@@ -29,6 +31,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/fluxcd/pkg/auth"
+	"github.com/fluxcd/pkg/auth/aws"
+	"github.com/fluxcd/pkg/auth/azure"
+	"github.com/fluxcd/pkg/auth/gcp"
+	"github.com/fluxcd/pkg/auth/generic"
 	"github.com/fluxcd/pkg/cache"
 )
 
@@ -95,6 +101,85 @@ func TestGetRegistryFromArtifactRepository(t *testing.T) {
 	}
 }
 
+func TestProviderForArtifactRepository(t *testing.T) {
+	providers := []auth.Provider{aws.Provider{}, azure.Provider{}, gcp.Provider{}}
+
+	for _, tt := range []struct {
+		name               string
+		artifactRepository string
+		providers          []auth.Provider
+		expectedProvider   string
+		expectedErr        string
+		unsupported        bool
+	}{
+		{
+			name:               "ECR registry",
+			artifactRepository: "012345678901.dkr.ecr.us-east-1.amazonaws.com/foo",
+			providers:          providers,
+			expectedProvider:   aws.ProviderName,
+		},
+		{
+			name:               "ACR registry",
+			artifactRepository: "myregistry.azurecr.io/foo",
+			providers:          providers,
+			expectedProvider:   azure.ProviderName,
+		},
+		{
+			name:               "GAR registry",
+			artifactRepository: "us-central1-docker.pkg.dev/project/repo",
+			providers:          providers,
+			expectedProvider:   gcp.ProviderName,
+		},
+		{
+			name:               "unsupported registry",
+			artifactRepository: "ghcr.io/fluxcd/source-controller",
+			providers:          providers,
+			expectedErr:        "invalid GCP registry: 'ghcr.io'",
+			unsupported:        true,
+		},
+		{
+			name:               "generic provider as fallback",
+			artifactRepository: "ghcr.io/fluxcd/source-controller",
+			providers:          append(slices.Clone(providers), generic.Provider{}),
+			expectedProvider:   generic.ProviderName,
+		},
+		{
+			name:               "malformed repository stops the chain",
+			artifactRepository: "myregistry.azurecr.io/Foo",
+			providers:          append(slices.Clone(providers), generic.Provider{}),
+			expectedErr:        "failed to parse artifact repository 'myregistry.azurecr.io/Foo'",
+		},
+		{
+			name:               "providers without artifact repository support are skipped",
+			artifactRepository: "ghcr.io/fluxcd/source-controller",
+			providers:          []auth.Provider{struct{ auth.Provider }{generic.Provider{}}},
+			expectedErr:        "no provider to consult for artifact repository",
+		},
+		{
+			name:               "no providers",
+			artifactRepository: "ghcr.io/fluxcd/source-controller",
+			expectedErr:        "no provider to consult for artifact repository",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, _, err := auth.ProviderForArtifactRepository(tt.artifactRepository, tt.providers)
+
+			if tt.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)))
+				g.Expect(err).To(MatchError(auth.ErrInvalidConfiguration))
+				var unsupportedErr *auth.ErrUnsupportedRegistry
+				g.Expect(errors.As(err, &unsupportedErr)).To(Equal(tt.unsupported))
+				g.Expect(provider).To(BeNil())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(provider.GetName()).To(Equal(tt.expectedProvider))
+		})
+	}
+}
+
 func TestGetArtifactRegistryCredentials(t *testing.T) {
 	g := NewWithT(t)
 
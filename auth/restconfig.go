@@ -58,6 +58,8 @@ type RESTConfig struct {
 	Host        string
 	BearerToken string
 	CAData      []byte
+	CertData    []byte
+	KeyData     []byte
 	ExpiresAt   time.Time
 }
 
@@ -70,17 +72,19 @@ func (r *RESTConfig) GetDuration() time.Duration {
 // the canonical form https://<lowercase(host)>:<port>.
 func ParseClusterAddress(address string) (string, error) {
 	if address == "" {
-		return "", errors.New("empty address")
+		return "", NewInvalidConfigurationError(errors.New("empty address"))
 	}
 	if !strings.HasPrefix(address, "http") {
 		address = fmt.Sprintf("https://%s", address)
 	}
 	u, err := url.Parse(address)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse Kubernetes API server address '%s': %w", address, err)
+		return "", NewInvalidConfigurationError(
+			fmt.Errorf("failed to parse Kubernetes API server address '%s': %w", address, err))
 	}
 	if u.Scheme != "https" {
-		return "", fmt.Errorf("the Kubernetes API server address '%s' must use https scheme", address)
+		return "", NewInvalidConfigurationError(
+			fmt.Errorf("the Kubernetes API server address '%s' must use https scheme", address))
 	}
 	host := u.Host
 	if u.Port() == "" {
@@ -117,7 +121,7 @@ func GetRESTConfig(ctx context.Context, provider RESTConfigProvider, opts ...Opt
 	newRESTConfig := func() (*RESTConfig, error) {
 		conf, err := provider.NewRESTConfig(ctx, accessTokens, opts...)
 		if err != nil {
-			return nil, err
+			return nil, ClassifyError(err)
 		}
 		return conf, nil
 	}
@@ -131,7 +135,7 @@ func GetRESTConfig(ctx context.Context, provider RESTConfigProvider, opts ...Opt
 	var serviceAccount *corev1.ServiceAccount
 	var providerIdentity string
 	var audiences []string
-	if o.ShouldGetServiceAccountToken() {
+	if len(accessTokenOpts) > 0 && o.ShouldGetServiceAccountToken() {
 		var err error
 		saRef := client.ObjectKey{
 			Name:      o.ServiceAccountName,
@@ -145,7 +149,7 @@ func GetRESTConfig(ctx context.Context, provider RESTConfigProvider, opts ...Opt
 	}
 	var cacheKeyParts []string
 	for i, atOpts := range accessTokenOpts {
-		key := buildAccessTokenCacheKey(provider, audiences, providerIdentity, serviceAccount, atOpts...)
+		key := buildAccessTokenCacheKey(provider.GetName(), audiences, providerIdentity, serviceAccount, atOpts...)
 		cacheKeyParts = append(cacheKeyParts, fmt.Sprintf("accessToken%dCacheKey=%s", i, key))
 	}
 	if c := o.ClusterResource; c != "" {
@@ -154,6 +158,9 @@ func GetRESTConfig(ctx context.Context, provider RESTConfigProvider, opts ...Opt
 	if a := o.ClusterAddress; a != "" {
 		cacheKeyParts = append(cacheKeyParts, fmt.Sprintf("address=%s", a))
 	}
+	if c := o.ClientCertData; c != "" {
+		cacheKeyParts = append(cacheKeyParts, fmt.Sprintf("clientCert=%s", c))
+	}
 	cacheKey := buildCacheKey(cacheKeyParts...)
 
 	// Build involved object details.
@@ -161,7 +168,8 @@ func GetRESTConfig(ctx context.Context, provider RESTConfigProvider, opts ...Opt
 		o.InvolvedObject.Kind,
 		o.InvolvedObject.Name,
 		o.InvolvedObject.Namespace,
-		o.InvolvedObject.Operation)}
+		o.InvolvedObject.Operation),
+		cacheMetadata(provider, serviceAccount)}
 
 	// Get restconfig from cache.
 	token, _, err := o.Cache.GetOrSet(ctx, cacheKey, func(ctx context.Context) (cache.Token, error) {
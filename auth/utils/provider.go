@@ -19,28 +19,21 @@ package utils
 import (
 	"fmt"
 
-	"github.com/fluxcd/pkg/auth/aws"
-	"github.com/fluxcd/pkg/auth/azure"
-	"github.com/fluxcd/pkg/auth/gcp"
-	"github.com/fluxcd/pkg/auth/generic"
+	"github.com/fluxcd/pkg/auth"
+	_ "github.com/fluxcd/pkg/auth/aws"
+	_ "github.com/fluxcd/pkg/auth/azure"
+	_ "github.com/fluxcd/pkg/auth/gcp"
+	_ "github.com/fluxcd/pkg/auth/generic"
 )
 
-// ProviderByName looks up the implemented providers by name and type.
+// ProviderByName looks up the registered providers by name and type.
+// Importing this package registers the providers implemented in this module.
 func ProviderByName[T any](name string) (T, error) {
-	var p any
 	var zero T
 
-	switch name {
-	case aws.ProviderName:
-		p = aws.Provider{}
-	case azure.ProviderName:
-		p = azure.Provider{}
-	case gcp.ProviderName:
-		p = gcp.Provider{}
-	case generic.ProviderName:
-		p = generic.Provider{}
-	default:
-		return zero, fmt.Errorf("provider '%s' not implemented", name)
+	p, err := auth.GetProvider(name)
+	if err != nil {
+		return zero, err
 	}
 
 	provider, ok := p.(T)
@@ -197,7 +197,7 @@ func TestProviderByName(t *testing.T) {
 			g := NewWithT(t)
 			p, err := authutils.ProviderByName[iface]("unknown")
 			g.Expect(err).To(HaveOccurred())
-			g.Expect(err.Error()).To(Equal("provider 'unknown' not implemented"))
+			g.Expect(err.Error()).To(HavePrefix("provider 'unknown' not implemented, registered providers: "))
 			g.Expect(p).To(BeNil())
 		})
 	})
@@ -117,6 +117,22 @@ func GetRESTConfig(ctx context.Context,
 		}
 		opts = append(opts, auth.WithAudiences(audiences...))
 	}
+	if name, ok := cm.Data[meta.KubeConfigKeyClientCertSecretName]; ok {
+		secretKey := client.ObjectKey{
+			Name:      name,
+			Namespace: namespace,
+		}
+		var secret corev1.Secret
+		if err := ctrlClient.Get(ctx, secretKey, &secret); err != nil {
+			return nil, fmt.Errorf("failed to get client certificate secret %s: %w", secretKey.String(), err)
+		}
+		certData, keyData := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+		if len(certData) == 0 || len(keyData) == 0 {
+			return nil, auth.NewInvalidConfigurationError(fmt.Errorf("client certificate secret %s must contain the '%s' and '%s' keys",
+				secretKey.String(), corev1.TLSCertKey, corev1.TLSPrivateKeyKey))
+		}
+		opts = append(opts, auth.WithClientCertificate(string(certData), string(keyData)))
+	}
 
 	conf, err := auth.GetRESTConfig(ctx, provider, opts...)
 	if err != nil {
@@ -126,8 +142,12 @@ func GetRESTConfig(ctx context.Context,
 	// Build wrapped *rest.Config that will call
 	// auth.GetRESTConfig for every HTTP request.
 	restConfig := &rest.Config{
-		Host:            conf.Host,
-		TLSClientConfig: rest.TLSClientConfig{CAData: conf.CAData},
+		Host: conf.Host,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData:   conf.CAData,
+			CertData: conf.CertData,
+			KeyData:  conf.KeyData,
+		},
 	}
 	restConfig.Wrap(func(base http.RoundTripper) http.RoundTripper {
 		return &restConfigRoundTripper{
@@ -142,7 +162,9 @@ func GetRESTConfig(ctx context.Context,
 
 // restConfigRoundTripper is an http.RoundTripper that wraps the base
 // RoundTripper and retrieves a bearer token for the remote cluster
-// using auth.GetRESTConfig before each HTTP request.
+// using auth.GetRESTConfig before each HTTP request. Without a bearer
+// token, e.g. when authenticating with a client certificate, the request
+// is sent as is.
 type restConfigRoundTripper struct {
 	base     http.RoundTripper
 	provider auth.RESTConfigProvider
@@ -155,6 +177,8 @@ func (r *restConfigRoundTripper) RoundTrip(req *http.Request) (*http.Response, e
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+details.BearerToken)
+	if details.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+details.BearerToken)
+	}
 	return r.base.RoundTrip(req)
 }
@@ -282,6 +282,16 @@ func (c *LRU[T]) ListKeys() ([]string, error) {
 	return keys, nil
 }
 
+// forEach calls fn for each item in the cache, from the least to the most
+// recently used, while holding the read lock of the cache.
+func (c *LRU[T]) forEach(fn func(key string, value T)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for n := c.head.next; n != c.tail; n = n.next {
+		fn(n.key, n.value)
+	}
+}
+
 // Resize resizes the cache and returns the number of items removed.
 func (c *LRU[T]) Resize(size int) (int, error) {
 	if size <= 0 {
@@ -52,6 +52,9 @@ type storeOptions struct {
 	debugKey            string
 	debugValueFunc      func(any) any
 	eventNamespaceLabel string
+	tokenProvider       string
+	tokenServiceAccount string
+	tokenCollector      prometheus.Registerer
 }
 
 func (o *storeOptions) apply(opts ...Options) error {
@@ -118,3 +121,25 @@ func WithEventNamespaceLabel(label string) Options {
 		return nil
 	}
 }
+
+// WithTokenMetadata sets the provider and the service account of the token
+// stored with TokenCache.GetOrSet, reported in the TokenCache snapshot and
+// metrics. The service account is in the form '<namespace>/<name>', and is
+// empty for the tokens of the controller.
+func WithTokenMetadata(provider, serviceAccount string) Options {
+	return func(o *storeOptions) error {
+		o.tokenProvider = provider
+		o.tokenServiceAccount = serviceAccount
+		return nil
+	}
+}
+
+// WithTokenCacheCollector registers a TokenCacheCollector for the
+// TokenCache in the given Prometheus registerer, with the metrics
+// prefix set with WithMetricsPrefix.
+func WithTokenCacheCollector(r prometheus.Registerer) Options {
+	return func(o *storeOptions) error {
+		o.tokenCollector = r
+		return nil
+	}
+}
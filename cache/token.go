@@ -18,6 +18,10 @@ package cache
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -63,9 +67,30 @@ type TokenFlags struct {
 }
 
 type tokenItem struct {
-	token Token
-	mono  time.Time
-	unix  time.Time
+	token          Token
+	mono           time.Time
+	unix           time.Time
+	issuedAt       time.Time
+	provider       string
+	serviceAccount string
+	hits           atomic.Int64
+}
+
+// EntryInfo describes an entry of the TokenCache, without the token itself.
+type EntryInfo struct {
+	// Provider is the provider of the token, if known.
+	Provider string `json:"provider,omitempty"`
+	// ServiceAccount is the service account of the token in the
+	// form '<namespace>/<name>', empty for the tokens of the controller.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// KeyHash is the SHA-256 hash of the cache key of the entry.
+	KeyHash string `json:"keyHash"`
+	// IssuedAt is the time at which the token was stored in the cache.
+	IssuedAt time.Time `json:"issuedAt"`
+	// ExpiresAt is the time at which the cache considers the token expired.
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Hits is the number of times the token was retrieved from the cache.
+	Hits int64 `json:"hits"`
 }
 
 // NewTokenCache returns a new TokenCache with the given capacity.
@@ -82,7 +107,13 @@ func NewTokenCache(capacity int, opts ...Options) (*TokenCache, error) {
 		return nil, err
 	}
 
-	return &TokenCache{cache, o.maxDuration}, nil
+	tc := &TokenCache{cache, o.maxDuration}
+	if o.tokenCollector != nil {
+		if err := o.tokenCollector.Register(NewTokenCacheCollector(tc, o.metricsPrefix)); err != nil {
+			return nil, fmt.Errorf("failed to register token cache collector: %w", err)
+		}
+	}
+	return tc, nil
 }
 
 // GetOrSet returns the token for the given key if present and not expired, or
@@ -99,12 +130,17 @@ func (c *TokenCache) GetOrSet(ctx context.Context,
 		return !token.expired()
 	}
 
+	var o storeOptions
+	o.apply(opts...)
 	fetch := func(ctx context.Context) (*tokenItem, error) {
 		token, err := newToken(ctx)
 		if err != nil {
 			return nil, err
 		}
-		return c.newItem(token), nil
+		item := c.newItem(token)
+		item.provider = o.tokenProvider
+		item.serviceAccount = o.tokenServiceAccount
+		return item, nil
 	}
 
 	opts = append(opts, func(so *storeOptions) error {
@@ -121,9 +157,43 @@ func (c *TokenCache) GetOrSet(ctx context.Context,
 	if err != nil {
 		return nil, false, err
 	}
+	if ok {
+		item.hits.Add(1)
+	}
 	return item.token, ok, nil
 }
 
+// Snapshot returns the information of the entries of the cache, sorted by
+// provider, service account and key hash, e.g. to be served by a debug
+// endpoint. The tokens and the cache keys are never included.
+func (c *TokenCache) Snapshot() []EntryInfo {
+	if c == nil {
+		return nil
+	}
+	var entries []EntryInfo
+	c.cache.forEach(func(key string, item *tokenItem) {
+		entries = append(entries, EntryInfo{
+			Provider:       item.provider,
+			ServiceAccount: item.serviceAccount,
+			KeyHash:        fmt.Sprintf("%x", sha256.Sum256([]byte(key))),
+			IssuedAt:       item.issuedAt,
+			ExpiresAt:      item.mono,
+			Hits:           item.hits.Load(),
+		})
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.ServiceAccount != b.ServiceAccount {
+			return a.ServiceAccount < b.ServiceAccount
+		}
+		return a.KeyHash < b.KeyHash
+	})
+	return entries
+}
+
 // DeleteEventsForObject deletes all cache events (cache_miss and cache_hit) for
 // the associated object being deleted, given its kind, name and namespace.
 func (c *TokenCache) DeleteEventsForObject(kind, name, namespace, operation string) {
@@ -146,13 +216,15 @@ func (c *TokenCache) newItem(token Token) *tokenItem {
 		d = m
 	}
 
-	mono := time.Now().Add(d)
+	now := time.Now()
+	mono := now.Add(d)
 	unix := time.Unix(mono.Unix(), 0)
 
 	return &tokenItem{
-		token: token,
-		mono:  mono,
-		unix:  unix,
+		token:    token,
+		mono:     mono,
+		unix:     unix,
+		issuedAt: now,
 	}
 }
 
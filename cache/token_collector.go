@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// tokenCacheBuckets are the buckets in seconds of the age and TTL
+// histograms of the TokenCacheCollector.
+var tokenCacheBuckets = []float64{60, 300, 600, 1800, 3600, 21600, 43200}
+
+// TokenCacheCollector is a prometheus.Collector reporting the entries of a
+// live TokenCache per provider and service account, with the distribution
+// of their age and TTL. The tokens and the cache keys are never reported.
+type TokenCacheCollector struct {
+	cache *TokenCache
+
+	entries               *prometheus.Desc
+	serviceAccountEntries *prometheus.Desc
+	age                   *prometheus.Desc
+	ttl                   *prometheus.Desc
+}
+
+// NewTokenCacheCollector returns a TokenCacheCollector for the given
+// TokenCache, with the metric names prefixed by the given prefix.
+func NewTokenCacheCollector(c *TokenCache, prefix string) *TokenCacheCollector {
+	return &TokenCacheCollector{
+		cache: c,
+		entries: prometheus.NewDesc(
+			fmt.Sprintf("%stoken_cache_entries", prefix),
+			"Number of tokens in the cache per provider.",
+			[]string{"provider"}, nil,
+		),
+		serviceAccountEntries: prometheus.NewDesc(
+			fmt.Sprintf("%stoken_cache_service_account_entries", prefix),
+			"Number of tokens in the cache per provider and service account.",
+			[]string{"provider", "service_account"}, nil,
+		),
+		age: prometheus.NewDesc(
+			fmt.Sprintf("%stoken_cache_entry_age_seconds", prefix),
+			"Age of the tokens in the cache per provider.",
+			[]string{"provider"}, nil,
+		),
+		ttl: prometheus.NewDesc(
+			fmt.Sprintf("%stoken_cache_entry_ttl_seconds", prefix),
+			"Time until the expiration of the tokens in the cache per provider.",
+			[]string{"provider"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *TokenCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entries
+	ch <- c.serviceAccountEntries
+	ch <- c.age
+	ch <- c.ttl
+}
+
+// Collect implements prometheus.Collector.
+func (c *TokenCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	type histogram struct {
+		count   uint64
+		sum     float64
+		buckets map[float64]uint64
+	}
+	newHistogram := func() *histogram {
+		return &histogram{buckets: make(map[float64]uint64, len(tokenCacheBuckets))}
+	}
+	observe := func(h *histogram, v float64) {
+		h.count++
+		h.sum += v
+		for _, b := range tokenCacheBuckets {
+			if v <= b {
+				h.buckets[b]++
+			}
+		}
+	}
+
+	type serviceAccountKey struct{ provider, serviceAccount string }
+	entries := map[string]float64{}
+	serviceAccounts := map[serviceAccountKey]float64{}
+	ages := map[string]*histogram{}
+	ttls := map[string]*histogram{}
+
+	now := time.Now()
+	for _, e := range c.cache.Snapshot() {
+		entries[e.Provider]++
+		if e.ServiceAccount != "" {
+			serviceAccounts[serviceAccountKey{e.Provider, e.ServiceAccount}]++
+		}
+		if ages[e.Provider] == nil {
+			ages[e.Provider] = newHistogram()
+			ttls[e.Provider] = newHistogram()
+		}
+		observe(ages[e.Provider], now.Sub(e.IssuedAt).Seconds())
+		observe(ttls[e.Provider], max(e.ExpiresAt.Sub(now).Seconds(), 0))
+	}
+
+	for provider, n := range entries {
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, n, provider)
+	}
+	for k, n := range serviceAccounts {
+		ch <- prometheus.MustNewConstMetric(c.serviceAccountEntries, prometheus.GaugeValue, n, k.provider, k.serviceAccount)
+	}
+	for provider, h := range ages {
+		ch <- prometheus.MustNewConstHistogram(c.age, h.count, h.sum, h.buckets, provider)
+	}
+	for provider, h := range ttls {
+		ch <- prometheus.MustNewConstHistogram(c.ttl, h.count, h.sum, h.buckets, provider)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/fluxcd/pkg/cache"
+)
+
+type secretToken struct {
+	value    string
+	duration time.Duration
+}
+
+func (t *secretToken) GetDuration() time.Duration {
+	return t.duration
+}
+
+func newTestTokenCache(t *testing.T) (*cache.TokenCache, *prometheus.Registry) {
+	t.Helper()
+	g := NewWithT(t)
+
+	reg := prometheus.NewPedanticRegistry()
+	tc, err := cache.NewTokenCache(10,
+		cache.WithMetricsPrefix("gotk_"),
+		cache.WithTokenCacheCollector(reg))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	entries := []struct {
+		key            string
+		provider       string
+		serviceAccount string
+		duration       time.Duration
+	}{
+		{key: "aws-controller-key", provider: "aws", duration: time.Hour},
+		{key: "aws-tenant-key", provider: "aws", serviceAccount: "tenant/puller", duration: 10 * time.Minute},
+		{key: "gcp-tenant-key", provider: "gcp", serviceAccount: "tenant/puller", duration: time.Hour},
+	}
+	for _, e := range entries {
+		_, _, err := tc.GetOrSet(context.Background(), e.key, func(context.Context) (cache.Token, error) {
+			return &secretToken{value: "secret-" + e.key, duration: e.duration}, nil
+		}, cache.WithTokenMetadata(e.provider, e.serviceAccount))
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	_, retrieved, err := tc.GetOrSet(context.Background(), "aws-tenant-key", func(context.Context) (cache.Token, error) {
+		return nil, fmt.Errorf("unexpected fetch")
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(retrieved).To(BeTrue())
+
+	return tc, reg
+}
+
+func TestTokenCache_Snapshot(t *testing.T) {
+	g := NewWithT(t)
+
+	tc, _ := newTestTokenCache(t)
+	hash := func(key string) string {
+		return fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
+	}
+
+	snapshot := tc.Snapshot()
+	g.Expect(snapshot).To(HaveLen(3))
+	for i, want := range []cache.EntryInfo{
+		{Provider: "aws", KeyHash: hash("aws-controller-key")},
+		{Provider: "aws", ServiceAccount: "tenant/puller", KeyHash: hash("aws-tenant-key"), Hits: 1},
+		{Provider: "gcp", ServiceAccount: "tenant/puller", KeyHash: hash("gcp-tenant-key")},
+	} {
+		e := snapshot[i]
+		g.Expect(e.IssuedAt).To(BeTemporally("~", time.Now(), time.Minute))
+		g.Expect(e.ExpiresAt).To(BeTemporally(">", e.IssuedAt))
+		e.IssuedAt, e.ExpiresAt = time.Time{}, time.Time{}
+		g.Expect(e).To(Equal(want))
+	}
+	g.Expect(snapshot[1].ExpiresAt.Sub(snapshot[1].IssuedAt)).To(Equal(8 * time.Minute))
+
+	// Neither the tokens nor the cache keys are in the snapshot.
+	b, err := json.Marshal(snapshot)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(b)).NotTo(ContainSubstring("secret"))
+	g.Expect(string(b)).NotTo(ContainSubstring("-key"))
+
+	var nilCache *cache.TokenCache
+	g.Expect(nilCache.Snapshot()).To(BeNil())
+}
+
+func TestTokenCacheCollector(t *testing.T) {
+	g := NewWithT(t)
+
+	_, reg := newTestTokenCache(t)
+
+	expected := `
+		# HELP gotk_token_cache_entries Number of tokens in the cache per provider.
+		# TYPE gotk_token_cache_entries gauge
+		gotk_token_cache_entries{provider="aws"} 2
+		gotk_token_cache_entries{provider="gcp"} 1
+		# HELP gotk_token_cache_service_account_entries Number of tokens in the cache per provider and service account.
+		# TYPE gotk_token_cache_service_account_entries gauge
+		gotk_token_cache_service_account_entries{provider="aws",service_account="tenant/puller"} 1
+		gotk_token_cache_service_account_entries{provider="gcp",service_account="tenant/puller"} 1
+`
+	g.Expect(testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gotk_token_cache_entries", "gotk_token_cache_service_account_entries")).To(Succeed())
+
+	mfs, err := reg.Gather()
+	g.Expect(err).NotTo(HaveOccurred())
+	buckets := map[string]map[float64]uint64{}
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			if h := m.GetHistogram(); h != nil {
+				key := mf.GetName() + "/" + m.GetLabel()[0].GetValue()
+				buckets[key] = map[float64]uint64{}
+				for _, b := range h.GetBucket() {
+					buckets[key][b.GetUpperBound()] = b.GetCumulativeCount()
+				}
+				g.Expect(h.GetSampleCount()).To(BeNumerically(">", 0))
+			}
+		}
+	}
+	g.Expect(buckets).To(HaveLen(4))
+	g.Expect(buckets["gotk_token_cache_entry_age_seconds/aws"][60]).To(BeEquivalentTo(2))
+	g.Expect(buckets["gotk_token_cache_entry_ttl_seconds/aws"][300]).To(BeEquivalentTo(0))
+	g.Expect(buckets["gotk_token_cache_entry_ttl_seconds/aws"][600]).To(BeEquivalentTo(1))
+	g.Expect(buckets["gotk_token_cache_entry_ttl_seconds/aws"][3600]).To(BeEquivalentTo(2))
+	g.Expect(buckets["gotk_token_cache_entry_ttl_seconds/gcp"][1800]).To(BeEquivalentTo(0))
+	g.Expect(buckets["gotk_token_cache_entry_ttl_seconds/gcp"][3600]).To(BeEquivalentTo(1))
+
+	problems, err := testutil.GatherAndLint(reg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(problems).To(BeEmpty())
+
+	// Neither the tokens nor the cache keys are in the metrics.
+	var out strings.Builder
+	for _, mf := range mfs {
+		out.WriteString(mf.String())
+	}
+	g.Expect(out.String()).NotTo(ContainSubstring("secret"))
+	g.Expect(out.String()).NotTo(ContainSubstring("-key"))
+
+	// The collector can only be registered once.
+	_, err = cache.NewTokenCache(1, cache.WithMetricsPrefix("gotk_"), cache.WithTokenCacheCollector(reg))
+	g.Expect(err).To(MatchError(ContainSubstring("failed to register token cache collector")))
+}
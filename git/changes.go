@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"strings"
+
+	"github.com/fluxcd/pkg/sourceignore/gitignore"
+)
+
+// OnlyIgnoredChanges returns true if all the given paths, relative to the
+// root of the repository and e.g. returned by a ChangedPaths diff, are
+// ignored by the matcher. A path is ignored if the matcher ignores the file
+// or any of its parent directories, like when the files of a repository are
+// filtered with sourceignore patterns. It returns true for an empty list of
+// paths, and false for a nil matcher.
+func OnlyIgnoredChanges(paths []string, matcher gitignore.Matcher) bool {
+	if matcher == nil {
+		return false
+	}
+	for _, p := range paths {
+		if !isIgnored(strings.Split(p, "/"), matcher) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIgnored returns true if the matcher ignores the file or any of its
+// parent directories.
+func isIgnored(path []string, matcher gitignore.Matcher) bool {
+	for i := 1; i < len(path); i++ {
+		if matcher.Match(path[:i], true) {
+			return true
+		}
+	}
+	return matcher.Match(path, false)
+}
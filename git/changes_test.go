@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/sourceignore"
+	"github.com/fluxcd/pkg/sourceignore/gitignore"
+)
+
+func TestOnlyIgnoredChanges(t *testing.T) {
+	matcher := sourceignore.NewMatcher(sourceignore.ReadPatterns(strings.NewReader(`docs/
+*.md
+!CHANGELOG.md
+!docs/api/
+`), nil))
+
+	tests := []struct {
+		name    string
+		paths   []string
+		matcher gitignore.Matcher
+		want    bool
+	}{
+		{
+			name:    "ignored files",
+			paths:   []string{"README.md", "app/README.md"},
+			matcher: matcher,
+			want:    true,
+		},
+		{
+			name:    "files in ignored directory",
+			paths:   []string{"docs/guide.md", "docs/images/logo.svg"},
+			matcher: matcher,
+			want:    true,
+		},
+		{
+			name:    "negated file",
+			paths:   []string{"README.md", "CHANGELOG.md"},
+			matcher: matcher,
+			want:    false,
+		},
+		{
+			name:    "negated directory in ignored directory",
+			paths:   []string{"docs/api/spec.yaml"},
+			matcher: matcher,
+			want:    true,
+		},
+		{
+			name:    "relevant file",
+			paths:   []string{"docs/guide.md", "app/deploy.yaml"},
+			matcher: matcher,
+			want:    false,
+		},
+		{
+			name:    "no paths",
+			matcher: matcher,
+			want:    true,
+		},
+		{
+			name:  "nil matcher",
+			paths: []string{"README.md"},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(OnlyIgnoredChanges(tt.paths, tt.matcher)).To(Equal(tt.want))
+		})
+	}
+}
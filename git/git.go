@@ -17,8 +17,11 @@ limitations under the License.
 package git
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -88,6 +91,34 @@ type Commit struct {
 	Message string
 	// ReferencingTag is the tag that points to this commit.
 	ReferencingTag *Tag
+	// Submodules holds the revisions of the submodules checked out with
+	// this commit, i.e. the commits pinned by its gitlinks. It is only set
+	// if requested with the clone config.
+	Submodules []SubmoduleRevision
+	// Warnings holds the files of the checkout whose mode, i.e. the
+	// executable bit or the symlink, could not be applied to the worktree,
+	// for example on Windows. It is only set if requested with the clone
+	// config.
+	Warnings []string
+}
+
+// CommitList is a list of commits of the history of a repository.
+type CommitList struct {
+	// Commits holds the commits, most recent first.
+	Commits []*Commit
+	// Truncated is true if the history of the repository is shallow and
+	// older commits exist in the remote repository.
+	Truncated bool
+}
+
+// SubmoduleRevision is the revision of a submodule checked out
+// with its superproject.
+type SubmoduleRevision struct {
+	// Path is the path of the submodule relative to the root of the
+	// superproject, e.g. 'vendor/lib' or 'lib/nested' for a nested submodule.
+	Path string
+	// Hash is the hash of the commit checked out in the submodule.
+	Hash Hash
 }
 
 // String returns a string representation of the Commit, composed
@@ -101,6 +132,32 @@ func (c *Commit) String() string {
 	return c.Hash.Digest()
 }
 
+// StringWithSubmodules returns the string representation of the Commit,
+// followed by the number of submodules and a SHA-256 digest of their paths
+// and hashes, if any. For example:
+// 'main@sha1:a0c14dc8580a23f79bc654faa79c4f62b46c2c22(+2 submodules: sha256:2f6c...)'.
+// The digest does not depend on the order of the submodules, and changes
+// whenever a submodule is checked out at a different commit. As the
+// submodules are checked out at the commits pinned by the superproject,
+// this only happens with a new superproject commit: the branch tracked by
+// a submodule advancing alone does not change the revision.
+func (c *Commit) StringWithSubmodules() string {
+	if len(c.Submodules) == 0 {
+		return c.String()
+	}
+
+	submodules := make([]SubmoduleRevision, len(c.Submodules))
+	copy(submodules, c.Submodules)
+	sort.Slice(submodules, func(i, j int) bool {
+		return submodules[i].Path < submodules[j].Path
+	})
+	h := sha256.New()
+	for _, s := range submodules {
+		fmt.Fprintf(h, "%s %s\n", s.Path, s.Hash)
+	}
+	return fmt.Sprintf("%s(+%d submodules: sha256:%x)", c.String(), len(submodules), h.Sum(nil))
+}
+
 // AbsoluteReference returns a string representation of the Commit, composed
 // out of the Reference element (if not empty) and Hash.
 // For example: 'refs/tags/tag-1@sha1:a0c14dc8580a23f79bc654faa79c4f62b46c2c22'
@@ -229,6 +286,22 @@ func (t *Tag) String() string {
 	return fmt.Sprintf("%s@%s", t.Name, t.Hash.String())
 }
 
+// Reference represents a Git reference advertised by a remote.
+type Reference struct {
+	// Name is the full name of the reference, for example:
+	// 'refs/heads/main' or 'refs/tags/v1.0.0'.
+	Name string
+	// Hash is the hash the reference points to. For annotated tags,
+	// this is the hash of the commit the tag points to.
+	Hash Hash
+}
+
+// String returns a string representation of the Reference in the format
+// of <name@digest>, for eg: "refs/heads/main@sha1:a0c14dc8580a23f79bc654faa79c4f62b46c2c22".
+func (r *Reference) String() string {
+	return fmt.Sprintf("%s@%s", r.Name, r.Hash.Digest())
+}
+
 // ErrRepositoryNotFound indicates that the repository (or the ref in
 // question) does not exist at the given URL.
 type ErrRepositoryNotFound struct {
@@ -240,6 +313,115 @@ func (e ErrRepositoryNotFound) Error() string {
 	return fmt.Sprintf("%s: git repository: '%s'", e.Message, e.URL)
 }
 
+// ErrShallowSinceNotSupported indicates that the Git server at the given
+// URL does not support the deepen-since capability required to clone with
+// a shallow-since time. Callers can fall back to a clone without it.
+type ErrShallowSinceNotSupported struct {
+	URL string
+}
+
+func (e ErrShallowSinceNotSupported) Error() string {
+	return fmt.Sprintf("git server does not support shallow clones by time (deepen-since): git repository: '%s'", e.URL)
+}
+
+// ErrRepositoryTooLarge indicates that the clone of the repository at the
+// given URL was aborted for exceeding the configured maximum number of
+// objects or size. Objects and Size report the progress of the clone when
+// it was aborted.
+type ErrRepositoryTooLarge struct {
+	URL        string
+	MaxObjects int
+	MaxSize    int64
+	Objects    int
+	Size       int64
+}
+
+func (e ErrRepositoryTooLarge) Error() string {
+	var limits []string
+	if e.MaxObjects > 0 {
+		limits = append(limits, fmt.Sprintf("%d objects", e.MaxObjects))
+	}
+	if e.MaxSize > 0 {
+		limits = append(limits, fmt.Sprintf("%d bytes", e.MaxSize))
+	}
+	return fmt.Sprintf("repository exceeds the clone limit of %s, aborted after receiving %d objects and %d bytes: git repository: '%s'",
+		strings.Join(limits, " and "), e.Objects, e.Size, e.URL)
+}
+
+const (
+	// PrivateKeyFormatPEM is the format of the traditional PEM private keys,
+	// e.g. the PKCS#1 RSA keys, encrypted with the Proc-Type and DEK-Info
+	// headers.
+	PrivateKeyFormatPEM = "PEM"
+	// PrivateKeyFormatPKCS8 is the format of the PKCS#8 private keys,
+	// encrypted with PBES2.
+	PrivateKeyFormatPKCS8 = "PKCS#8"
+	// PrivateKeyFormatOpenSSH is the format of the OpenSSH private keys,
+	// encrypted with a KDF and a cipher.
+	PrivateKeyFormatOpenSSH = "OpenSSH"
+)
+
+// ErrPrivateKeyPassphrase indicates that the SSH private key is passphrase
+// protected, and that the passphrase is missing or incorrect.
+type ErrPrivateKeyPassphrase struct {
+	Format  string
+	Missing bool
+}
+
+func (e ErrPrivateKeyPassphrase) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("the %s SSH private key is passphrase protected, but no passphrase was provided", e.Format)
+	}
+	return fmt.Sprintf("the passphrase of the %s SSH private key is incorrect", e.Format)
+}
+
+// Unwrap returns x509.IncorrectPasswordError when the passphrase is
+// incorrect.
+func (e ErrPrivateKeyPassphrase) Unwrap() error {
+	if e.Missing {
+		return nil
+	}
+	return x509.IncorrectPasswordError
+}
+
+// ErrPrivateKeyUnsupported indicates that the SSH private key is encrypted
+// with a cipher or a key derivation function which is not supported. Cipher
+// is the name of the unsupported cipher or key derivation function.
+type ErrPrivateKeyUnsupported struct {
+	Format string
+	Cipher string
+}
+
+func (e ErrPrivateKeyUnsupported) Error() string {
+	return fmt.Sprintf("the %s SSH private key is encrypted with the unsupported cipher '%s'", e.Format, e.Cipher)
+}
+
+// ErrUnsafeSymlink indicates that a symlink of the checked out tree has an
+// absolute target or a target outside of the worktree. Path is the path of
+// the symlink relative to the root of the worktree.
+type ErrUnsafeSymlink struct {
+	Path   string
+	Target string
+}
+
+func (e ErrUnsafeSymlink) Error() string {
+	return fmt.Sprintf("the symlink '%s' points to '%s', which is absolute or outside of the worktree", e.Path, e.Target)
+}
+
+// ErrCherryPickConflict indicates that a commit could not be cherry-picked
+// onto a branch, as the files it changes were changed differently on the
+// branch. Files are the paths of the conflicting files.
+type ErrCherryPickConflict struct {
+	Commit string
+	Branch string
+	Files  []string
+}
+
+func (e ErrCherryPickConflict) Error() string {
+	return fmt.Sprintf("unable to cherry-pick commit '%s' onto branch '%s': conflicting changes in '%s'",
+		e.Commit, e.Branch, strings.Join(e.Files, "', '"))
+}
+
 var (
 	ErrNoGitRepository = errors.New("no git repository")
 	ErrNoStagedFiles   = errors.New("no staged files")
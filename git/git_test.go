@@ -154,6 +154,44 @@ func TestCommit_String(t *testing.T) {
 	}
 }
 
+func TestCommit_StringWithSubmodules(t *testing.T) {
+	g := NewWithT(t)
+
+	commit := &Commit{
+		Hash:      []byte("5394cb7f48332b2de7c17dd8b8384bbc84b7e738"),
+		Reference: "refs/heads/main",
+	}
+	g.Expect(commit.StringWithSubmodules()).To(Equal(commit.String()))
+
+	commit.Submodules = []SubmoduleRevision{
+		{Path: "vendor/lib", Hash: Hash("a0c14dc8580a23f79bc654faa79c4f62b46c2c22")},
+		{Path: "base", Hash: Hash("d4f4b3a1c5e2e8b7d3a2e1f0c9b8a7d6e5f4c3b2")},
+	}
+	rev := commit.StringWithSubmodules()
+	g.Expect(rev).To(MatchRegexp(`^main@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738\(\+2 submodules: sha256:[0-9a-f]{64}\)$`))
+
+	// The order of the submodules does not matter.
+	reordered := &Commit{
+		Hash:       commit.Hash,
+		Reference:  commit.Reference,
+		Submodules: []SubmoduleRevision{commit.Submodules[1], commit.Submodules[0]},
+	}
+	g.Expect(reordered.StringWithSubmodules()).To(Equal(rev))
+	g.Expect(commit.Submodules[0].Path).To(Equal("vendor/lib"))
+
+	// A submodule checked out at another commit changes the revision.
+	advanced := &Commit{
+		Hash:      commit.Hash,
+		Reference: commit.Reference,
+		Submodules: []SubmoduleRevision{
+			commit.Submodules[0],
+			{Path: "base", Hash: Hash("5394cb7f48332b2de7c17dd8b8384bbc84b7e738")},
+		},
+	}
+	g.Expect(advanced.StringWithSubmodules()).ToNot(Equal(rev))
+	g.Expect(TransformRevision(advanced.StringWithSubmodules())).To(Equal(commit.String()))
+}
+
 func TestCommit_AbsoluteReference(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -390,6 +428,16 @@ func TestTag_String(t *testing.T) {
 	}
 }
 
+func TestReference_String(t *testing.T) {
+	g := NewWithT(t)
+
+	ref := &Reference{
+		Name: "refs/heads/main",
+		Hash: Hash("5394cb7f48332b2de7c17dd8b8384bbc84b7e738"),
+	}
+	g.Expect(ref.String()).To(Equal("refs/heads/main@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738"))
+}
+
 func TestIsSigned(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -4,6 +4,8 @@ go 1.26.0
 
 replace (
 	github.com/fluxcd/pkg/gittestserver => ../gittestserver
+	github.com/fluxcd/pkg/lockedfile => ../lockedfile
+	github.com/fluxcd/pkg/sourceignore => ../sourceignore
 	github.com/fluxcd/pkg/ssh => ../ssh
 	github.com/fluxcd/pkg/version => ../version
 )
@@ -16,6 +18,8 @@ require (
 	github.com/elazarl/goproxy v1.8.0
 	github.com/fluxcd/gitkit v0.6.0
 	github.com/fluxcd/pkg/gittestserver v0.29.0
+	github.com/fluxcd/pkg/lockedfile v0.8.0
+	github.com/fluxcd/pkg/sourceignore v0.18.0
 	github.com/fluxcd/pkg/ssh v0.25.0
 	github.com/fluxcd/pkg/version v0.16.0
 	github.com/go-git/go-billy/v5 v5.9.0
@@ -0,0 +1,416 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+	"github.com/fluxcd/pkg/lockedfile"
+)
+
+// cacheScheme is the URL scheme used to clone from a repository cache
+// entry with the in-process go-git server, without requiring a git binary.
+const cacheScheme = "gogit-cache"
+
+var cacheEntries = &cacheEntryLoader{storers: make(map[string]storer.Storer)}
+
+func init() {
+	// The in-process go-git server serving the registered cache entries is
+	// installed at program initialization, before any remote operation, as
+	// the protocols of go-git are read without synchronization.
+	client.InstallProtocol(cacheScheme, server.NewClient(cacheEntries))
+}
+
+// cacheEntryLoader is a server.Loader serving the cache entries which are
+// registered for the duration of a clone under a random token, so that no
+// other repository on disk can be loaded through the cache scheme.
+type cacheEntryLoader struct {
+	mu      sync.Mutex
+	storers map[string]storer.Storer
+}
+
+// Load implements server.Loader.
+func (l *cacheEntryLoader) Load(ep *transport.Endpoint) (storer.Storer, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.storers[ep.Host]
+	if !ok {
+		return nil, transport.ErrRepositoryNotFound
+	}
+	return s, nil
+}
+
+func (l *cacheEntryLoader) register(s storer.Storer) (string, func(), error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	token := hex.EncodeToString(b)
+
+	l.mu.Lock()
+	l.storers[token] = s
+	l.mu.Unlock()
+	return fmt.Sprintf("%s://%s", cacheScheme, token), func() {
+		l.mu.Lock()
+		delete(l.storers, token)
+		l.mu.Unlock()
+	}, nil
+}
+
+// repositoryCache is a directory of bare repositories keyed by the hash of
+// their normalized URL.
+type repositoryCache struct {
+	dir        string
+	maxEntries int
+}
+
+// WithRepositoryCache configures the client to keep a bare copy of the cloned
+// repositories in the given directory, so that subsequent clones of the same
+// URL fetch only the new objects from the remote, and check out the worktree
+// from the cached copy. At most maxEntries repositories are kept, the least
+// recently used ones are evicted beyond that. The cache can be shared among
+// clients and processes, the access to each entry is serialized with a file
+// lock.
+//
+// A cached repository always holds the full history of the branches and
+// tags of the remote, shallow clones are therefore not shallow on the first
+// fetch. Clones recursing submodules are not cached. If fetching into a
+// cached repository or checking out from it fails, the entry is discarded
+// and the remote is cloned again from scratch.
+func WithRepositoryCache(dir string, maxEntries int) ClientOption {
+	return func(c *Client) error {
+		if dir == "" {
+			return errors.New("repository cache directory cannot be empty")
+		}
+		if maxEntries < 1 {
+			return fmt.Errorf("repository cache max entries must be at least 1, got %d", maxEntries)
+		}
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("invalid repository cache directory '%s': %w", dir, err)
+		}
+		if err := os.MkdirAll(absDir, 0o700); err != nil {
+			return fmt.Errorf("unable to create repository cache directory: %w", err)
+		}
+		c.cache = &repositoryCache{dir: absDir, maxEntries: maxEntries}
+		return nil
+	}
+}
+
+// entryPath returns the path of the cache entry for the given URL.
+func (c *repositoryCache) entryPath(u string) string {
+	sum := sha256.Sum256([]byte(normalizeCacheURL(u)))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// evict removes the least recently used entries beyond the maximum number of
+// entries. Eviction is best effort, failures are retried on the next call.
+func (c *repositoryCache) evict() {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type entry struct {
+		path    string
+		modTime time.Time
+	}
+	var entries []entry
+	for _, de := range dirEntries {
+		if !de.IsDir() {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{path: filepath.Join(c.dir, de.Name()), modTime: info.ModTime()})
+	}
+	if len(entries) <= c.maxEntries {
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.After(entries[j].modTime)
+	})
+	for _, e := range entries[c.maxEntries:] {
+		unlock, err := lockedfile.MutexAt(e.path + ".lock").Lock()
+		if err != nil {
+			continue
+		}
+		_ = os.RemoveAll(e.path)
+		unlock()
+	}
+}
+
+// normalizeCacheURL returns the URL without credentials, with a lower case
+// scheme and host, and without the trailing slash and .git suffix, so that
+// the different spellings of a repository URL share the same cache entry.
+func normalizeCacheURL(u string) string {
+	ru, err := url.Parse(u)
+	if err != nil || ru.Scheme == "" {
+		return strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
+	}
+	ru.User = nil
+	ru.Scheme = strings.ToLower(ru.Scheme)
+	ru.Host = strings.ToLower(ru.Host)
+	ru.Path = strings.TrimSuffix(strings.TrimSuffix(ru.Path, "/"), ".git")
+	ru.RawPath = ""
+	return ru.String()
+}
+
+// cloneWithCache updates the cache entry of the URL and clones from it.
+func (g *Client) cloneWithCache(ctx context.Context, url string, cfg repository.CloneConfig) (*git.Commit, error) {
+	entry := g.cache.entryPath(url)
+	unlock, err := lockedfile.MutexAt(entry + ".lock").Lock()
+	if err != nil {
+		return nil, fmt.Errorf("unable to lock repository cache entry: %w", err)
+	}
+
+	commit, err := g.cloneFromCacheEntry(ctx, url, entry, cfg)
+	var tooLargeErr git.ErrRepositoryTooLarge
+	if errors.As(err, &tooLargeErr) {
+		// Discard the partially fetched data, a fresh clone would exceed
+		// the limits as well.
+		_ = os.RemoveAll(entry)
+	} else if err != nil && !isPermanentCacheErr(err) {
+		// The entry may be corrupted or may not be updatable from the
+		// remote, start over from a fresh clone.
+		if rmErr := os.RemoveAll(entry); rmErr == nil {
+			if rmErr = g.resetStorage(); rmErr == nil {
+				commit, err = g.cloneFromCacheEntry(ctx, url, entry, cfg)
+			}
+		}
+	}
+	if err == nil {
+		now := time.Now()
+		_ = os.Chtimes(entry, now, now)
+	}
+	unlock()
+
+	g.cache.evict()
+	return commit, err
+}
+
+// cloneFromCacheEntry fetches the remote into the cache entry, creating it
+// if it does not exist, and clones the entry into the client storage.
+func (g *Client) cloneFromCacheEntry(ctx context.Context, url, entry string, cfg repository.CloneConfig) (*git.Commit, error) {
+	repo, err := extgogit.PlainOpen(entry)
+	if errors.Is(err, extgogit.ErrRepositoryNotExists) {
+		repo, err = extgogit.PlainInit(entry, true)
+		if err == nil {
+			_, err = repo.CreateRemote(&config.RemoteConfig{
+				Name: git.DefaultRemote,
+				URLs: []string{url},
+			})
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repository cache entry: %w", err)
+	}
+
+	if err := g.fetchCacheEntry(ctx, repo, url, cfg); err != nil {
+		return nil, err
+	}
+
+	cacheURL, unregister, err := cacheEntries.register(repo.Storer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to register repository cache entry: %w", err)
+	}
+	defer unregister()
+
+	// Clone from the cache entry with the in-process server, which requires
+	// neither credentials nor a proxy, and does not support shallow clones,
+	// including the ones by time.
+	local := *g
+	local.cache = nil
+	// Empty HTTP auth options result in no auth method.
+	local.authOpts = &git.AuthOptions{Transport: git.HTTP}
+	local.proxy = transport.ProxyOptions{}
+	cfg.ShallowClone = false
+	cfg.ShallowSince = time.Time{}
+	commit, err := local.clone(ctx, cacheURL, cfg)
+	if err != nil {
+		var notFoundErr git.ErrRepositoryNotFound
+		if errors.As(err, &notFoundErr) {
+			return nil, git.ErrRepositoryNotFound{
+				Message: strings.ReplaceAll(notFoundErr.Message, cacheURL, url),
+				URL:     url,
+			}
+		}
+		var tooLargeErr git.ErrRepositoryTooLarge
+		if errors.As(err, &tooLargeErr) {
+			g.storer = local.storer
+			tooLargeErr.URL = url
+			return nil, tooLargeErr
+		}
+		return nil, &cacheCloneError{err: err, cacheURL: cacheURL, url: url}
+	}
+	g.repository = local.repository
+	g.sparseCheckoutDirectories = local.sparseCheckoutDirectories
+
+	// Point the remote of the clone to the actual URL for subsequent
+	// operations, e.g. push.
+	if g.repository != nil {
+		if err := setRemoteURL(g.repository, url); err != nil {
+			return nil, err
+		}
+	}
+	return commit, nil
+}
+
+// fetchCacheEntry fetches the branches and tags of the remote into the cache
+// entry, and the reference to check out if it is neither a branch nor a tag.
+// The limits of the clone configuration apply to the fetched objects.
+func (g *Client) fetchCacheEntry(ctx context.Context, repo *extgogit.Repository, url string, cfg repository.CloneConfig) error {
+	authMethod, err := g.remoteAuth(ctx, g.authOpts)
+	if err != nil {
+		return fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+
+	if cfg.MaxObjects > 0 || cfg.MaxSize > 0 {
+		limits := &cloneLimits{maxObjects: cfg.MaxObjects, maxSize: cfg.MaxSize}
+		if repo, err = extgogit.Open(newLimitedStorer(repo.Storer, limits), nil); err != nil {
+			return fmt.Errorf("unable to open repository cache entry: %w", err)
+		}
+	}
+
+	refSpecs := []config.RefSpec{
+		"+refs/heads/*:refs/heads/*",
+		"+refs/tags/*:refs/tags/*",
+	}
+	if refName := plumbing.ReferenceName(strings.TrimSuffix(cfg.RefName, tagDereferenceSuffix)); refName != "" &&
+		!refName.IsBranch() && !refName.IsTag() {
+		refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("+%[1]s:%[1]s", refName)))
+	}
+
+	err = repo.FetchContext(ctx, &extgogit.FetchOptions{
+		RemoteName:   git.DefaultRemote,
+		RemoteURL:    url,
+		RefSpecs:     refSpecs,
+		Auth:         authMethod,
+		Progress:     nil,
+		Tags:         extgogit.NoTags,
+		Force:        true,
+		Prune:        true,
+		ClientCert:   clientCert(g.authOpts),
+		ClientKey:    clientKey(g.authOpts),
+		CABundle:     caBundle(g.authOpts),
+		ProxyOptions: g.proxyOptions(g.authOpts),
+	})
+	var tooLargeErr git.ErrRepositoryTooLarge
+	switch {
+	case err == nil, errors.Is(err, extgogit.NoErrAlreadyUpToDate), errors.Is(err, transport.ErrEmptyRemoteRepository):
+		return nil
+	case errors.As(err, &tooLargeErr):
+		tooLargeErr.URL = url
+		return tooLargeErr
+	case errors.Is(err, transport.ErrRepositoryNotFound):
+		return git.ErrRepositoryNotFound{
+			Message: fmt.Sprintf("unable to clone: %s", err),
+			URL:     url,
+		}
+	default:
+		return fmt.Errorf("unable to fetch '%s': %w", url, err)
+	}
+}
+
+// resetStorage discards the objects and files written by a failed clone
+// attempt to the memory or disk storage of the client. It returns an error
+// for other storages, which can't be reset.
+func (g *Client) resetStorage() error {
+	g.repository = nil
+	switch g.storer.(type) {
+	case *memory.Storage:
+		g.storer = memory.NewStorage()
+	case *filesystem.Storage:
+		// The Git directory is removed together with the worktree.
+		if g.worktreeFS.Root() != g.path {
+			return errors.New("unable to reset the client storage")
+		}
+	default:
+		return errors.New("unable to reset the client storage")
+	}
+
+	files, err := g.worktreeFS.ReadDir("/")
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := util.RemoveAll(g.worktreeFS, f.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cacheCloneError is an error returned when cloning from a cache entry,
+// with the URL of the cache entry replaced by the URL of the remote.
+type cacheCloneError struct {
+	err      error
+	cacheURL string
+	url      string
+}
+
+// Error implements error.
+func (e *cacheCloneError) Error() string {
+	return strings.ReplaceAll(e.err.Error(), e.cacheURL, e.url)
+}
+
+// Unwrap returns the underlying error.
+func (e *cacheCloneError) Unwrap() error {
+	return e.err
+}
+
+// isPermanentCacheErr returns true if the error is not caused by the cache
+// entry, and would therefore be returned by a fresh clone as well.
+func isPermanentCacheErr(err error) bool {
+	var notFoundErr git.ErrRepositoryNotFound
+	var noMatchingRefSpecErr extgogit.NoMatchingRefSpecError
+	return errors.As(err, &notFoundErr) ||
+		errors.As(err, &noMatchingRefSpecErr) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, transport.ErrAuthenticationRequired) ||
+		errors.Is(err, transport.ErrAuthorizationFailed) ||
+		errors.Is(err, transport.ErrInvalidAuthMethod)
+}
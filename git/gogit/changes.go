@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// ChangedPaths returns the sorted paths of the files which differ between
+// the trees of the given commits. The commits can be given as SHAs or as
+// revision strings, e.g. 'main@sha1:<SHA>', and must both be present in
+// the repository, which is usually not the case for shallow clones.
+//
+// As the trees of the commits are compared, the changes brought by all the
+// commits in between are reported, including the ones merged from other
+// branches. Renamed files are reported with both their old and new paths.
+func (g *Client) ChangedPaths(ctx context.Context, fromSHA, toSHA string) ([]string, error) {
+	if g.repository == nil {
+		return nil, git.ErrNoGitRepository
+	}
+
+	from, err := g.commitTree(fromSHA)
+	if err != nil {
+		return nil, err
+	}
+	to, err := g.commitTree(toSHA)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTreeWithOptions(ctx, from, to, &object.DiffTreeOptions{
+		DetectRenames: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to diff commits '%s' and '%s': %w", fromSHA, toSHA, err)
+	}
+
+	seen := make(map[string]struct{}, len(changes))
+	var paths []string
+	for _, c := range changes {
+		for _, name := range []string{c.From.Name, c.To.Name} {
+			if _, ok := seen[name]; ok || name == "" {
+				continue
+			}
+			seen[name] = struct{}{}
+			paths = append(paths, name)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// commitTree returns the tree of the given commit.
+func (g *Client) commitTree(rev string) (*object.Tree, error) {
+	hash := git.ExtractHashFromRevision(git.TransformRevision(rev))
+	if len(hash) == 0 {
+		return nil, fmt.Errorf("invalid commit '%s'", rev)
+	}
+	commit, err := g.repository.CommitObject(plumbing.NewHash(hash.String()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve commit '%s': %w", rev, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve tree of commit '%s': %w", rev, err)
+	}
+	return tree, nil
+}
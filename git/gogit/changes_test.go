@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/sourceignore"
+)
+
+func TestClient_ChangedPaths(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	wt, err := repo.Worktree()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	now := time.Now()
+	initial, err := commitFiles(repo, map[string]string{
+		"app/deploy.yaml": "replicas: 1",
+		"docs/guide.md":   "guide",
+		"docs/howto.md":   "a long enough how-to for the rename detection",
+		"README.md":       "readme",
+	}, now)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	docs, err := commitFiles(repo, map[string]string{
+		"docs/guide.md": "updated guide",
+		"README.md":     "updated readme",
+	}, now.Add(time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	app, err := commitFile(repo, "app/deploy.yaml", "replicas: 2", now.Add(2*time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = wt.Move("docs/howto.md", "app/howto.md")
+	g.Expect(err).ToNot(HaveOccurred())
+	rename, err := wt.Commit("Move how-to", &extgogit.CommitOptions{
+		Author: mockSignature(now.Add(3 * time.Minute)),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Branch off from the rename, and merge a change to the docs
+	// with a change to the app into the main branch.
+	g.Expect(createBranch(repo, "docs")).To(Succeed())
+	feature, err := commitFile(repo, "docs/feature.md", "feature", now.Add(4*time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(wt.Checkout(&extgogit.CheckoutOptions{Hash: rename, Force: true})).To(Succeed())
+	mainHead, err := commitFile(repo, "app/service.yaml", "port: 80", now.Add(5*time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = stageFile(wt, "docs/feature.md", "feature")
+	g.Expect(err).ToNot(HaveOccurred())
+	merge, err := wt.Commit("Merge docs", &extgogit.CommitOptions{
+		Author:  mockSignature(now.Add(6 * time.Minute)),
+		Parents: []plumbing.Hash{mainHead, feature},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ggc, err := NewClient(path, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	ggc.repository = repo
+
+	matcher := sourceignore.NewMatcher(sourceignore.ReadPatterns(strings.NewReader("docs/\n*.md\n"), nil))
+
+	tests := []struct {
+		name        string
+		from        string
+		to          string
+		want        []string
+		onlyIgnored bool
+		wantErr     string
+	}{
+		{
+			name:        "ignored changes only",
+			from:        initial.String(),
+			to:          docs.String(),
+			want:        []string{"README.md", "docs/guide.md"},
+			onlyIgnored: true,
+		},
+		{
+			name: "relevant changes",
+			from: docs.String(),
+			to:   app.String(),
+			want: []string{"app/deploy.yaml"},
+		},
+		{
+			name: "changes of several commits",
+			from: initial.String(),
+			to:   app.String(),
+			want: []string{"README.md", "app/deploy.yaml", "docs/guide.md"},
+		},
+		{
+			name:        "rename reports both paths",
+			from:        app.String(),
+			to:          rename.String(),
+			want:        []string{"app/howto.md", "docs/howto.md"},
+			onlyIgnored: true,
+		},
+		{
+			name:        "merge of ignored changes",
+			from:        mainHead.String(),
+			to:          merge.String(),
+			want:        []string{"docs/feature.md"},
+			onlyIgnored: true,
+		},
+		{
+			name: "merge of relevant changes",
+			from: feature.String(),
+			to:   merge.String(),
+			want: []string{"app/service.yaml"},
+		},
+		{
+			name:        "revision strings",
+			from:        "main@" + git.Hash(initial.String()).Digest(),
+			to:          "main@" + git.Hash(docs.String()).Digest(),
+			want:        []string{"README.md", "docs/guide.md"},
+			onlyIgnored: true,
+		},
+		{
+			name:        "no changes",
+			from:        app.String(),
+			to:          app.String(),
+			onlyIgnored: true,
+		},
+		{
+			name:    "unknown commit",
+			from:    "5394cb7f48332b2de7c17dd8b8384bbc84b7e738",
+			to:      app.String(),
+			wantErr: "unable to resolve commit '5394cb7f48332b2de7c17dd8b8384bbc84b7e738'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			paths, err := ggc.ChangedPaths(context.TODO(), tt.from, tt.to)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(paths).To(Equal(tt.want))
+			g.Expect(git.OnlyIgnoredChanges(paths, matcher)).To(Equal(tt.onlyIgnored))
+		})
+	}
+}
+
+func TestClient_ChangedPaths_NoRepository(t *testing.T) {
+	g := NewWithT(t)
+
+	ggc, err := NewClient(t.TempDir(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = ggc.ChangedPaths(context.TODO(), "a", "b")
+	g.Expect(err).To(MatchError(git.ErrNoGitRepository))
+}
+
+// stageFile writes and stages the given file without committing it.
+func stageFile(wt *extgogit.Worktree, path, content string) (plumbing.Hash, error) {
+	f, err := wt.Filesystem.Create(path)
+	if err != nil {
+		return plumbing.Hash{}, err
+	}
+	if _, err = f.Write([]byte(content)); err != nil {
+		f.Close()
+		return plumbing.Hash{}, err
+	}
+	if err = f.Close(); err != nil {
+		return plumbing.Hash{}, err
+	}
+	return wt.Add(path)
+}
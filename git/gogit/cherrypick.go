@@ -0,0 +1,287 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// cherryPickOnto fetches the destination branch of the refspec from origin
+// with the given options, and cherry-picks the commit of the source of the
+// refspec onto its head. It returns the refspec pushing the cherry-picked
+// commit to the destination, and the hash of the cherry-picked commit. The
+// refspec is returned as is if the destination does not exist at origin or
+// is in the history of the source, in which case nothing is cherry-picked.
+//
+// The changes of the commit to its first parent are applied to the tree of
+// the head of the destination branch, file by file: a change conflicts if
+// the file was changed differently on the branch, in which case a
+// git.ErrCherryPickConflict error listing the conflicting files is returned.
+func (g *Client) cherryPickOnto(ctx context.Context, refspec config.RefSpec, fetchOpts *extgogit.FetchOptions) (config.RefSpec, string, error) {
+	dst := refspec.Dst("")
+	if !dst.IsBranch() {
+		return "", "", fmt.Errorf("unable to cherry-pick onto '%s': not a branch", dst)
+	}
+	commit, err := g.refspecCommit(refspec)
+	if err != nil {
+		return "", "", err
+	}
+
+	tracking := plumbing.NewRemoteReferenceName(extgogit.DefaultRemoteName, dst.Short())
+	fetchOpts.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", dst, tracking))}
+	if shallows, err := g.repository.Storer.Shallow(); err == nil && len(shallows) > 0 {
+		fetchOpts.Depth = 1
+	}
+	err = g.repository.FetchContext(ctx, fetchOpts)
+	if errors.Is(err, extgogit.NoMatchingRefSpecError{}) {
+		return refspec, "", nil
+	}
+	if err != nil && !errors.Is(err, extgogit.NoErrAlreadyUpToDate) {
+		return "", "", fmt.Errorf("unable to fetch branch '%s': %w", dst.Short(), err)
+	}
+	ref, err := g.repository.Reference(tracking, true)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to resolve fetched branch '%s': %w", dst.Short(), err)
+	}
+	head, err := g.repository.CommitObject(ref.Hash())
+	if err != nil {
+		return "", "", fmt.Errorf("unable to resolve commit object for branch '%s': %w", dst.Short(), err)
+	}
+	// The branch can be fast-forwarded to the commit. In shallow clones, the
+	// history may not reach the head, in which case the commit is
+	// cherry-picked.
+	if head.Hash == commit.Hash {
+		return refspec, "", nil
+	}
+	if ok, err := head.IsAncestor(commit); err == nil && ok {
+		return refspec, "", nil
+	}
+
+	hash, err := g.cherryPick(commit, head, dst.Short())
+	if err != nil {
+		return "", "", err
+	}
+	return config.RefSpec(fmt.Sprintf("%s:%s", hash, dst)), hash.String(), nil
+}
+
+// refspecCommit returns the commit of the source of the refspec.
+func (g *Client) refspecCommit(refspec config.RefSpec) (*object.Commit, error) {
+	src := refspec.Src()
+	hash := plumbing.NewHash(src)
+	if !refspec.IsExactSHA1() {
+		ref, err := g.repository.Reference(plumbing.ReferenceName(src), true)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve reference '%s': %w", src, err)
+		}
+		hash = ref.Hash()
+	}
+	commit, err := g.repository.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve commit object for '%s': %w", src, err)
+	}
+	return commit, nil
+}
+
+// cherryPick creates a commit with the changes of the given commit applied
+// onto the head of the branch, and returns its hash. The author, committer
+// and message of the commit are kept, but not its signature.
+func (g *Client) cherryPick(commit, head *object.Commit, branch string) (plumbing.Hash, error) {
+	var base *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("unable to resolve parent of commit '%s': %w", commit.Hash, err)
+		}
+		if base, err = parent.Tree(); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("unable to resolve tree of commit '%s': %w", parent.Hash, err)
+		}
+	}
+	theirs, err := commit.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to resolve tree of commit '%s': %w", commit.Hash, err)
+	}
+	ours, err := head.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to resolve tree of commit '%s': %w", head.Hash, err)
+	}
+
+	entries, err := flattenTree(ours)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	changes, err := object.DiffTree(base, theirs)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to diff commit '%s': %w", commit.Hash, err)
+	}
+	var conflicts []string
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		from, to := changeEntry(change.From), changeEntry(change.To)
+		current, ok := entries[name]
+		var at *object.TreeEntry
+		if ok {
+			at = &current
+		}
+
+		switch {
+		case sameEntry(at, from):
+			if to == nil {
+				delete(entries, name)
+			} else {
+				entries[name] = *to
+			}
+		case sameEntry(at, to):
+		default:
+			conflicts = append(conflicts, name)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return plumbing.ZeroHash, git.ErrCherryPickConflict{
+			Commit: commit.Hash.String(),
+			Branch: branch,
+			Files:  conflicts,
+		}
+	}
+
+	tree, err := g.writeTree(entries)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	picked := &object.Commit{
+		Author:       commit.Author,
+		Committer:    commit.Committer,
+		Message:      commit.Message,
+		TreeHash:     tree,
+		ParentHashes: []plumbing.Hash{head.Hash},
+	}
+	obj := g.repository.Storer.NewEncodedObject()
+	if err := picked.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to encode cherry-picked commit: %w", err)
+	}
+	hash, err := g.repository.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to store cherry-picked commit: %w", err)
+	}
+	return hash, nil
+}
+
+// flattenTree returns the entries of the files of the tree, recursively,
+// by their path.
+func flattenTree(tree *object.Tree) (map[string]object.TreeEntry, error) {
+	entries := map[string]object.TreeEntry{}
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to walk tree '%s': %w", tree.Hash, err)
+		}
+		if entry.Mode == filemode.Dir {
+			continue
+		}
+		entries[name] = entry
+	}
+}
+
+// writeTree writes the tree objects holding the file entries by their path,
+// and returns the hash of the root tree.
+func (g *Client) writeTree(entries map[string]object.TreeEntry) (plumbing.Hash, error) {
+	tree := &object.Tree{}
+	dirs := map[string]map[string]object.TreeEntry{}
+	for name, entry := range entries {
+		dir, rest, nested := strings.Cut(name, "/")
+		if !nested {
+			entry.Name = name
+			tree.Entries = append(tree.Entries, entry)
+			continue
+		}
+		if dirs[dir] == nil {
+			dirs[dir] = map[string]object.TreeEntry{}
+		}
+		dirs[dir][rest] = entry
+	}
+	for dir, dirEntries := range dirs {
+		if _, ok := entries[dir]; ok {
+			return plumbing.ZeroHash, fmt.Errorf("unable to write tree: '%s' is both a file and a directory", dir)
+		}
+		hash, err := g.writeTree(dirEntries)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: hash})
+	}
+	// Git sorts the entries by name, with the names of the trees suffixed
+	// with a slash.
+	sort.Slice(tree.Entries, func(i, j int) bool {
+		return treeEntrySortName(tree.Entries[i]) < treeEntrySortName(tree.Entries[j])
+	})
+
+	obj := g.repository.Storer.NewEncodedObject()
+	if err := tree.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to encode tree: %w", err)
+	}
+	hash, err := g.repository.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to store tree: %w", err)
+	}
+	return hash, nil
+}
+
+func treeEntrySortName(entry object.TreeEntry) string {
+	if entry.Mode == filemode.Dir {
+		return entry.Name + "/"
+	}
+	return entry.Name
+}
+
+// changeEntry returns the tree entry of the side of a change, or nil if the
+// file does not exist on that side.
+func changeEntry(entry object.ChangeEntry) *object.TreeEntry {
+	if entry.Name == "" {
+		return nil
+	}
+	return &entry.TreeEntry
+}
+
+// sameEntry returns true if both entries are absent, or have the same
+// content and mode.
+func sameEntry(a, b *object.TreeEntry) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Hash == b.Hash && a.Mode == b.Mode
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestPushWithResult_modes(t *testing.T) {
+	g := NewWithT(t)
+
+	server, repoURL, err := setupGitServer(false)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+	defer server.StopHTTP()
+
+	// Each update clones the checkout branch, as an automation does.
+	checkout := func(g *WithT) (*Client, *extgogit.Repository) {
+		tmp := t.TempDir()
+		repo, err := extgogit.PlainClone(tmp, false, &extgogit.CloneOptions{
+			URL:          repoURL,
+			RemoteName:   git.DefaultRemote,
+			Tags:         extgogit.NoTags,
+			SingleBranch: true,
+			Depth:        1,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ggc, err := NewClient(tmp, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		ggc.repository = repo
+		return ggc, repo
+	}
+	pushBranch := func(g *WithT) *object.Commit {
+		repo, err := extgogit.PlainOpen(filepath.Join(server.Root(), "test.git"))
+		g.Expect(err).ToNot(HaveOccurred())
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName("auto"), true)
+		g.Expect(err).ToNot(HaveOccurred())
+		commit, err := repo.CommitObject(ref.Hash())
+		g.Expect(err).ToNot(HaveOccurred())
+		return commit
+	}
+	fileContents := func(g *WithT, commit *object.Commit) map[string]string {
+		files, err := commit.Files()
+		g.Expect(err).ToNot(HaveOccurred())
+		contents := map[string]string{}
+		err = files.ForEach(func(f *object.File) error {
+			contents[f.Name], err = f.Contents()
+			return err
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		return contents
+	}
+	refspec := "refs/heads/master:refs/heads/auto"
+
+	t.Run("cherry-pick creates the missing push branch", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ggc, repo := checkout(g)
+		cc, err := commitFile(repo, "app.yaml", "image: app:v1", time.Now())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := ggc.PushWithResult(context.TODO(), repository.PushConfig{
+			Refspecs: []string{refspec},
+			Mode:     repository.PushModeCherryPick,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(&repository.PushResult{
+			Mode:     repository.PushModeCherryPick,
+			Refspecs: []string{refspec},
+		}))
+		g.Expect(pushBranch(g).Hash).To(Equal(cc))
+	})
+
+	t.Run("cherry-pick keeps the history of the push branch", func(t *testing.T) {
+		g := NewWithT(t)
+
+		prior := pushBranch(g)
+		ggc, repo := checkout(g)
+		cc, err := commitFile(repo, "infra.yaml", "image: infra:v1", time.Now())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := ggc.PushWithResult(context.TODO(), repository.PushConfig{
+			Refspecs: []string{refspec},
+			Mode:     repository.PushModeCherryPick,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Mode).To(Equal(repository.PushModeCherryPick))
+		g.Expect(result.CherryPicked).ToNot(BeEmpty())
+		g.Expect(result.CherryPicked).ToNot(Equal(cc.String()))
+		g.Expect(result.Refspecs).To(Equal([]string{result.CherryPicked + ":refs/heads/auto"}))
+
+		head := pushBranch(g)
+		g.Expect(head.Hash.String()).To(Equal(result.CherryPicked))
+		g.Expect(head.ParentHashes).To(Equal([]plumbing.Hash{prior.Hash}))
+		g.Expect(head.Message).To(Equal("Adding: infra.yaml"))
+		g.Expect(fileContents(g, head)).To(Equal(map[string]string{
+			"foo.txt":    "test file\n",
+			"app.yaml":   "image: app:v1",
+			"infra.yaml": "image: infra:v1",
+		}))
+	})
+
+	t.Run("cherry-pick fails on conflicting changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		prior := pushBranch(g)
+		ggc, repo := checkout(g)
+		cc, err := commitFiles(repo, map[string]string{
+			"app.yaml":   "image: app:v2",
+			"infra.yaml": "image: infra:v1",
+			"other.yaml": "image: other:v1",
+		}, time.Now())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = ggc.PushWithResult(context.TODO(), repository.PushConfig{
+			Refspecs: []string{refspec},
+			Mode:     repository.PushModeCherryPick,
+		})
+		g.Expect(err).To(Equal(git.ErrCherryPickConflict{
+			Commit: cc.String(),
+			Branch: "auto",
+			Files:  []string{"app.yaml"},
+		}))
+		g.Expect(pushBranch(g).Hash).To(Equal(prior.Hash))
+	})
+
+	t.Run("reset discards the history of the push branch", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ggc, repo := checkout(g)
+		cc, err := commitFile(repo, "app.yaml", "image: app:v2", time.Now())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := ggc.PushWithResult(context.TODO(), repository.PushConfig{
+			Refspecs: []string{refspec},
+			Mode:     repository.PushModeReset,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(&repository.PushResult{
+			Mode:     repository.PushModeReset,
+			Refspecs: []string{refspec},
+		}))
+
+		head := pushBranch(g)
+		g.Expect(head.Hash).To(Equal(cc))
+		g.Expect(fileContents(g, head)).To(Equal(map[string]string{
+			"foo.txt":  "test file\n",
+			"app.yaml": "image: app:v2",
+		}))
+	})
+
+	t.Run("modes require a single refspec", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ggc, _ := checkout(g)
+		_, err := ggc.PushWithResult(context.TODO(), repository.PushConfig{
+			Refspecs: []string{refspec, "refs/heads/master:refs/heads/other"},
+			Mode:     repository.PushModeReset,
+		})
+		g.Expect(err).To(MatchError(ContainSubstring("requires a single refspec")))
+	})
+}
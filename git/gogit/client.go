@@ -79,6 +79,11 @@ type Client struct {
 	singleBranch              bool
 	proxy                     transport.ProxyOptions
 	sparseCheckoutDirectories []string
+	cache                     *repositoryCache
+	urlRewrites               []RewriteRule
+	connectionPool            ConnectionPoolOptions
+	disableSystemRoots        bool
+	traceHooks                *TraceHooks
 }
 
 var _ repository.Client = &Client{}
@@ -96,7 +101,8 @@ func NewClient(path string, authOpts *git.AuthOptions, clientOpts ...ClientOptio
 		path:     securePath,
 		authOpts: authOpts,
 		// Default to single branch as it is the most performant option.
-		singleBranch: true,
+		singleBranch:   true,
+		connectionPool: DefaultConnectionPoolOptions(),
 	}
 
 	if len(clientOpts) == 0 {
@@ -248,14 +254,94 @@ func (g *Client) Init(ctx context.Context, url, branch string) error {
 }
 
 func (g *Client) Clone(ctx context.Context, url string, cfg repository.CloneConfig) (*git.Commit, error) {
+	ctx = g.withTrace(ctx, TraceOperationClone)
 	if err := g.validateUrlAndAuthOptions(url); err != nil {
 		return nil, err
 	}
 
-	return g.clone(ctx, url, cfg)
+	if rewrittenURL, authOpts, err := g.rewriteRemote(url, g.authOpts); err != nil {
+		return nil, err
+	} else if rewrittenURL != url {
+		return g.cloneRewritten(ctx, url, rewrittenURL, authOpts, cfg)
+	}
+
+	var commit *git.Commit
+	var err error
+	if g.cache != nil && !cfg.RecurseSubmodules {
+		commit, err = g.cloneWithCache(ctx, url, cfg)
+	} else {
+		commit, err = g.clone(ctx, url, cfg)
+	}
+	if err != nil || commit == nil || !git.IsConcreteCommit(*commit) || g.repository == nil {
+		return commit, err
+	}
+
+	if cfg.PreserveFileModes || cfg.RejectUnsafeSymlinks {
+		if commit.Warnings, err = g.applyWorktreeModes(cfg.PreserveFileModes, cfg.RejectUnsafeSymlinks); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.RecurseSubmodules && cfg.SubmoduleRevisions {
+		if commit.Submodules, err = submoduleRevisions(g.repository, ""); err != nil {
+			return nil, err
+		}
+	}
+	return commit, nil
 }
 
 func (g *Client) clone(ctx context.Context, url string, cfg repository.CloneConfig) (*git.Commit, error) {
+	checkoutStrat := cfg.CheckoutStrategy
+	if !cfg.ShallowSince.IsZero() {
+		if checkoutStrat.Commit != "" || checkoutStrat.RefName != "" || checkoutStrat.Tag != "" || checkoutStrat.SemVer != "" {
+			return nil, errors.New("shallow clones by time are only supported with a branch checkout")
+		}
+		if cfg.RecurseSubmodules {
+			return nil, errors.New("shallow clones by time are not supported with submodules")
+		}
+	}
+	if cfg.MaxObjects < 0 || cfg.MaxSize < 0 {
+		return nil, errors.New("the clone max objects and max size cannot be negative")
+	}
+	if cfg.MaxObjects > 0 || cfg.MaxSize > 0 {
+		return g.cloneWithLimits(ctx, url, cfg)
+	}
+	return g.cloneTarget(ctx, url, cfg)
+}
+
+// cloneWithLimits clones with the client storage wrapped in a storer
+// enforcing the limits of the clone configuration. The partially cloned
+// data is discarded if a limit is exceeded.
+func (g *Client) cloneWithLimits(ctx context.Context, url string, cfg repository.CloneConfig) (*git.Commit, error) {
+	s := g.storer
+	g.storer = newLimitedStorer(s, &cloneLimits{maxObjects: cfg.MaxObjects, maxSize: cfg.MaxSize})
+	commit, err := g.cloneTarget(ctx, url, cfg)
+	g.storer = s
+
+	if err != nil {
+		var tooLargeErr git.ErrRepositoryTooLarge
+		if errors.As(err, &tooLargeErr) {
+			tooLargeErr.URL = url
+			if rmErr := g.resetStorage(); rmErr != nil {
+				return nil, fmt.Errorf("%w, unable to discard the cloned data: %w", tooLargeErr, rmErr)
+			}
+			return nil, tooLargeErr
+		}
+		return nil, err
+	}
+
+	// Open the repository with the client storage for the subsequent
+	// operations.
+	if g.repository != nil {
+		if g.repository, err = extgogit.Open(s, g.worktreeFS); err != nil {
+			return nil, fmt.Errorf("unable to open the cloned repository: %w", err)
+		}
+	}
+	return commit, nil
+}
+
+// cloneTarget clones the repository and checks out the target of the
+// checkout strategy of the clone configuration.
+func (g *Client) cloneTarget(ctx context.Context, url string, cfg repository.CloneConfig) (*git.Commit, error) {
 	checkoutStrat := cfg.CheckoutStrategy
 	switch {
 	case checkoutStrat.Commit != "":
@@ -277,15 +363,24 @@ func (g *Client) clone(ctx context.Context, url string, cfg repository.CloneConf
 
 // validateUrlAndAuthOptions performs validations on the input url and auth options.
 func (g *Client) validateUrlAndAuthOptions(u string) error {
+	return g.validateUrlWithAuthOptions(u, g.authOpts)
+}
+
+// validateUrlWithAuthOptions performs validations on the input url and the
+// given auth options.
+func (g *Client) validateUrlWithAuthOptions(u string, authOpts *git.AuthOptions) error {
 	ru, err := url.Parse(u)
 	if err != nil {
 		return fmt.Errorf("cannot parse url: %w", err)
 	}
+	if ru.Scheme == cacheScheme {
+		return fmt.Errorf("unsupported url scheme '%s'", ru.Scheme)
+	}
 
-	if g.authOpts != nil {
-		httpOrHttps := g.authOpts.Transport == git.HTTP || g.authOpts.Transport == git.HTTPS
-		hasUsernameOrPassword := g.authOpts.Username != "" || g.authOpts.Password != ""
-		hasBearerToken := g.authOpts.BearerToken != ""
+	if authOpts != nil {
+		httpOrHttps := authOpts.Transport == git.HTTP || authOpts.Transport == git.HTTPS
+		hasUsernameOrPassword := authOpts.Username != "" || authOpts.Password != ""
+		hasBearerToken := authOpts.BearerToken != ""
 
 		if httpOrHttps && hasBearerToken && hasUsernameOrPassword {
 			return errors.New("basic auth and bearer token cannot be set at the same time")
@@ -301,10 +396,10 @@ func (g *Client) validateUrlAndAuthOptions(u string) error {
 		return errors.New("URL cannot contain credentials when using HTTP")
 	}
 
-	if httpOrEmpty && g.authOpts != nil {
-		if g.authOpts.Username != "" || g.authOpts.Password != "" {
+	if httpOrEmpty && authOpts != nil {
+		if authOpts.Username != "" || authOpts.Password != "" {
 			return errors.New("basic auth cannot be sent over HTTP")
-		} else if g.authOpts.BearerToken != "" {
+		} else if authOpts.BearerToken != "" {
 			return errors.New("bearer token cannot be sent over HTTP")
 		}
 	}
@@ -386,14 +481,42 @@ func (g *Client) Commit(info git.Commit, commitOpts ...repository.CommitOption)
 	return commit.String(), nil
 }
 
+// Push performs a Git push to origin, as PushWithResult does.
 func (g *Client) Push(ctx context.Context, cfg repository.PushConfig) error {
+	_, err := g.PushWithResult(ctx, cfg)
+	return err
+}
+
+// PushWithResult performs a Git push to origin and returns its result. By
+// default, it pushes the reference pointed to by HEAD to its equivalent
+// destination at origin, but this is configurable via the Refspecs of the
+// PushConfig. With the repository.PushModeReset mode, the destination is
+// force pushed. With the repository.PushModeCherryPick mode, the destination
+// branch is fetched from origin and the commit of the source is
+// cherry-picked onto its head, unless the source already contains it.
+func (g *Client) PushWithResult(ctx context.Context, cfg repository.PushConfig) (*repository.PushResult, error) {
 	if g.repository == nil {
-		return git.ErrNoGitRepository
+		return nil, git.ErrNoGitRepository
 	}
+	ctx = g.withTrace(ctx, TraceOperationPush)
 
-	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	authOpts := g.authOpts
+	var remoteURL string
+	if len(g.urlRewrites) > 0 {
+		remote, err := g.repository.Remote(extgogit.DefaultRemoteName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get remote: %w", err)
+		}
+		if urls := remote.Config().URLs; len(urls) > 0 {
+			if remoteURL, authOpts, err = g.rewriteRemote(urls[0], authOpts); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	authMethod, err := g.remoteAuth(ctx, authOpts)
 	if err != nil {
-		return fmt.Errorf("failed to construct auth method with options: %w", err)
+		return nil, fmt.Errorf("failed to construct auth method with options: %w", err)
 	}
 
 	var refspecs []config.RefSpec
@@ -407,30 +530,74 @@ func (g *Client) Push(ctx context.Context, cfg repository.PushConfig) error {
 	if len(refspecs) == 0 {
 		head, err := g.repository.Head()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		headRefspec := config.RefSpec(fmt.Sprintf("%s:%[1]s", head.Name()))
 		refspecs = append(refspecs, headRefspec)
 	}
 
+	result := &repository.PushResult{Mode: cfg.Mode}
+	force := cfg.Force
+	switch cfg.Mode {
+	case "":
+	case repository.PushModeReset, repository.PushModeCherryPick:
+		if len(refspecs) != 1 {
+			return nil, fmt.Errorf("push mode '%s' requires a single refspec, got %d", cfg.Mode, len(refspecs))
+		}
+		if err := refspecs[0].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid refspec '%s': %w", refspecs[0], err)
+		}
+		if refspecs[0].IsWildcard() || refspecs[0].IsDelete() {
+			return nil, fmt.Errorf("push mode '%s' requires a refspec with a single source and destination, got '%s'",
+				cfg.Mode, refspecs[0])
+		}
+
+		force = cfg.Mode == repository.PushModeReset
+		if cfg.Mode == repository.PushModeCherryPick {
+			refspec, picked, err := g.cherryPickOnto(ctx, refspecs[0], &extgogit.FetchOptions{
+				RemoteName:   extgogit.DefaultRemoteName,
+				RemoteURL:    remoteURL,
+				Auth:         authMethod,
+				ClientCert:   clientCert(authOpts),
+				ClientKey:    clientKey(authOpts),
+				CABundle:     caBundle(authOpts),
+				ProxyOptions: g.proxyOptions(authOpts),
+			})
+			if err != nil {
+				return nil, err
+			}
+			refspecs[0] = refspec
+			result.CherryPicked = picked
+		}
+	default:
+		return nil, fmt.Errorf("unsupported push mode '%s'", cfg.Mode)
+	}
+
+	// The refspecs are recorded before the push, which prefixes them with a
+	// plus sign when forced.
+	for _, refspec := range refspecs {
+		result.Refspecs = append(result.Refspecs, refspec.String())
+	}
+
 	err = g.repository.PushContext(ctx, &extgogit.PushOptions{
 		RefSpecs:     refspecs,
-		Force:        cfg.Force,
+		Force:        force,
 		RemoteName:   extgogit.DefaultRemoteName,
+		RemoteURL:    remoteURL,
 		Auth:         authMethod,
 		Progress:     nil,
-		ClientCert:   clientCert(g.authOpts),
-		ClientKey:    clientKey(g.authOpts),
-		CABundle:     caBundle(g.authOpts),
-		ProxyOptions: g.proxy,
+		ClientCert:   clientCert(authOpts),
+		ClientKey:    clientKey(authOpts),
+		CABundle:     caBundle(authOpts),
+		ProxyOptions: g.proxyOptions(authOpts),
 		Options:      cfg.Options,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to push to remote: %w", err)
+		return nil, fmt.Errorf("failed to push to remote: %w", err)
 	}
 
-	return nil
+	return result, nil
 }
 
 // SwitchBranch switches the current branch to the given branch name.
@@ -18,8 +18,10 @@ package gogit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"sort"
 	"strings"
 	"time"
@@ -43,7 +45,7 @@ func (g *Client) cloneBranch(ctx context.Context, url, branch string, opts repos
 	if g.authOpts == nil {
 		return nil, fmt.Errorf("unable to checkout repo with an empty set of auth options")
 	}
-	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	authMethod, err := g.remoteAuth(ctx, g.authOpts)
 	if err != nil {
 		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
 	}
@@ -67,6 +69,10 @@ func (g *Client) cloneBranch(ctx context.Context, url, branch string, opts repos
 		}
 	}
 
+	if !opts.ShallowSince.IsZero() {
+		return g.cloneBranchSince(ctx, url, branch, authMethod, opts)
+	}
+
 	var depth int
 	if opts.ShallowClone {
 		depth = 1
@@ -85,10 +91,13 @@ func (g *Client) cloneBranch(ctx context.Context, url, branch string, opts repos
 		ClientCert:        clientCert(g.authOpts),
 		ClientKey:         clientKey(g.authOpts),
 		CABundle:          caBundle(g.authOpts),
-		ProxyOptions:      g.proxy,
+		ProxyOptions:      g.proxyOptions(g.authOpts),
 	}
 
 	repo, err := extgogit.CloneContext(ctx, g.storer, g.worktreeFS, cloneOpts)
+	if err == nil && !cloneOpts.NoCheckout {
+		g.trace(ctx).clonedWorktree()
+	}
 	if err != nil {
 		if err == transport.ErrRepositoryNotFound || isRemoteBranchNotFoundErr(err, ref.String()) {
 			return nil, git.ErrRepositoryNotFound{
@@ -116,9 +125,11 @@ func (g *Client) cloneBranch(ctx context.Context, url, branch string, opts repos
 		if err != nil {
 			return nil, fmt.Errorf("unable to open repo worktree: %w", err)
 		}
-		err = w.Checkout(&extgogit.CheckoutOptions{
-			Branch:                    ref,
-			SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+		err = g.trace(ctx).checkout(func() error {
+			return w.Checkout(&extgogit.CheckoutOptions{
+				Branch:                    ref,
+				SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+			})
 		})
 		if err != nil {
 			return nil, fmt.Errorf("unable to sparse checkout branch '%s': %w", branch, err)
@@ -143,7 +154,7 @@ func (g *Client) cloneTag(ctx context.Context, url, tag string, opts repository.
 		return nil, fmt.Errorf("unable to checkout repo with an empty set of auth options")
 	}
 
-	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	authMethod, err := g.remoteAuth(ctx, g.authOpts)
 	if err != nil {
 		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
 	}
@@ -186,10 +197,13 @@ func (g *Client) cloneTag(ctx context.Context, url, tag string, opts repository.
 		ClientCert:   clientCert(g.authOpts),
 		ClientKey:    clientKey(g.authOpts),
 		CABundle:     caBundle(g.authOpts),
-		ProxyOptions: g.proxy,
+		ProxyOptions: g.proxyOptions(g.authOpts),
 	}
 
 	repo, err := extgogit.CloneContext(ctx, g.storer, g.worktreeFS, cloneOpts)
+	if err == nil && !cloneOpts.NoCheckout {
+		g.trace(ctx).clonedWorktree()
+	}
 	if err != nil {
 		if err == transport.ErrEmptyRemoteRepository || err == transport.ErrRepositoryNotFound || isRemoteBranchNotFoundErr(err, ref.String()) {
 			return nil, git.ErrRepositoryNotFound{
@@ -205,9 +219,11 @@ func (g *Client) cloneTag(ctx context.Context, url, tag string, opts repository.
 		if err != nil {
 			return nil, fmt.Errorf("unable to open repo worktree: %w", err)
 		}
-		err = w.Checkout(&extgogit.CheckoutOptions{
-			Branch:                    ref,
-			SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+		err = g.trace(ctx).checkout(func() error {
+			return w.Checkout(&extgogit.CheckoutOptions{
+				Branch:                    ref,
+				SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+			})
 		})
 		if err != nil {
 			return nil, fmt.Errorf("unable to sparse checkout tag '%s': %w", tag, err)
@@ -239,7 +255,7 @@ func (g *Client) cloneTag(ctx context.Context, url, tag string, opts repository.
 }
 
 func (g *Client) cloneCommit(ctx context.Context, url, commit string, opts repository.CloneConfig) (*git.Commit, error) {
-	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	authMethod, err := g.remoteAuth(ctx, g.authOpts)
 	if err != nil {
 		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
 	}
@@ -262,7 +278,7 @@ func (g *Client) cloneCommit(ctx context.Context, url, commit string, opts repos
 		ClientCert:        clientCert(g.authOpts),
 		ClientKey:         clientKey(g.authOpts),
 		CABundle:          caBundle(g.authOpts),
-		ProxyOptions:      g.proxy,
+		ProxyOptions:      g.proxyOptions(g.authOpts),
 	}
 	if opts.Branch != "" {
 		cloneOpts.SingleBranch = g.singleBranch
@@ -270,6 +286,9 @@ func (g *Client) cloneCommit(ctx context.Context, url, commit string, opts repos
 	}
 
 	repo, err := extgogit.CloneContext(ctx, g.storer, g.worktreeFS, cloneOpts)
+	if err == nil && !cloneOpts.NoCheckout {
+		g.trace(ctx).clonedWorktree()
+	}
 	if err != nil {
 		if err == transport.ErrEmptyRemoteRepository || err == transport.ErrRepositoryNotFound ||
 			isRemoteBranchNotFoundErr(err, cloneOpts.ReferenceName.String()) {
@@ -289,10 +308,12 @@ func (g *Client) cloneCommit(ctx context.Context, url, commit string, opts repos
 	if err != nil {
 		return nil, fmt.Errorf("unable to resolve commit object for '%s': %w", commit, err)
 	}
-	err = w.Checkout(&extgogit.CheckoutOptions{
-		Hash:                      cc.Hash,
-		Force:                     true,
-		SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+	err = g.trace(ctx).checkout(func() error {
+		return w.Checkout(&extgogit.CheckoutOptions{
+			Hash:                      cc.Hash,
+			Force:                     true,
+			SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to checkout commit '%s': %w", commit, err)
@@ -313,7 +334,7 @@ func (g *Client) cloneSemVer(ctx context.Context, url, semverTag string, opts re
 		return nil, fmt.Errorf("semver parse error: %w", err)
 	}
 
-	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	authMethod, err := g.remoteAuth(ctx, g.authOpts)
 	if err != nil {
 		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
 	}
@@ -333,10 +354,13 @@ func (g *Client) cloneSemVer(ctx context.Context, url, semverTag string, opts re
 		ClientCert:        clientCert(g.authOpts),
 		ClientKey:         clientKey(g.authOpts),
 		CABundle:          caBundle(g.authOpts),
-		ProxyOptions:      g.proxy,
+		ProxyOptions:      g.proxyOptions(g.authOpts),
 	}
 
 	repo, err := extgogit.CloneContext(ctx, g.storer, g.worktreeFS, cloneOpts)
+	if err == nil && !cloneOpts.NoCheckout {
+		g.trace(ctx).clonedWorktree()
+	}
 	if err != nil {
 		if err == transport.ErrEmptyRemoteRepository || err == transport.ErrRepositoryNotFound {
 			return nil, git.ErrRepositoryNotFound{
@@ -414,9 +438,11 @@ func (g *Client) cloneSemVer(ctx context.Context, url, semverTag string, opts re
 	if err != nil {
 		return nil, fmt.Errorf("unable to find reference for tag '%s': %w", t, err)
 	}
-	err = w.Checkout(&extgogit.CheckoutOptions{
-		Branch:                    tagRef.Name(),
-		SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+	err = g.trace(ctx).checkout(func() error {
+		return w.Checkout(&extgogit.CheckoutOptions{
+			Branch:                    tagRef.Name(),
+			SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+		})
 	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to checkout tag '%s': %w", t, err)
@@ -442,10 +468,13 @@ func (g *Client) cloneSemVer(ctx context.Context, url, semverTag string, opts re
 }
 
 func (g *Client) cloneRefName(ctx context.Context, url string, refName string, cloneOpts repository.CloneConfig) (*git.Commit, error) {
+	if err := validateRefName(refName); err != nil {
+		return nil, err
+	}
 	if g.authOpts == nil {
 		return nil, fmt.Errorf("unable to checkout repo with an empty set of auth options")
 	}
-	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	authMethod, err := g.remoteAuth(ctx, g.authOpts)
 	if err != nil {
 		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
 	}
@@ -454,7 +483,7 @@ func (g *Client) cloneRefName(ctx context.Context, url string, refName string, c
 		return nil, err
 	}
 	if head == "" {
-		return nil, fmt.Errorf("unable to resolve ref '%s' to a specific commit", refName)
+		return nil, fmt.Errorf("unable to resolve ref '%s' to a specific commit: the ref is not advertised by the remote", refName)
 	}
 
 	hash := git.ExtractHashFromRevision(head)
@@ -488,7 +517,153 @@ func (g *Client) cloneRefName(ctx context.Context, url string, refName string, c
 		return commit, nil
 	}
 
-	return g.cloneCommit(ctx, url, hash.String(), cloneOpts)
+	return g.cloneRef(ctx, url, ref, authMethod, cloneOpts)
+}
+
+// validateRefName returns an error if the given name is not a fully-qualified
+// reference conforming to the Git reference format. The tag dereference
+// suffix is allowed for the tags.
+func validateRefName(refName string) error {
+	name := plumbing.ReferenceName(strings.TrimSuffix(refName, tagDereferenceSuffix))
+	if !strings.HasPrefix(name.String(), "refs/") {
+		return fmt.Errorf("invalid ref name '%s': the ref must be fully-qualified, e.g. 'refs/heads/main'", refName)
+	}
+	if name.String() != refName && !name.IsTag() {
+		return fmt.Errorf("invalid ref name '%s': the '%s' suffix is only valid for tags", refName, tagDereferenceSuffix)
+	}
+	if err := name.Validate(); err != nil {
+		return fmt.Errorf("invalid ref name '%s': %w", refName, err)
+	}
+	return nil
+}
+
+// cloneRef clones the given reference which is neither a branch nor a tag,
+// e.g. a Gerrit change, a GitLab merge request head or a notes ref. As a
+// clone only fetches the branches and the tags, the reference is fetched by
+// refspec into a new repository.
+func (g *Client) cloneRef(ctx context.Context, url string, ref plumbing.ReferenceName,
+	authMethod transport.AuthMethod, opts repository.CloneConfig) (*git.Commit, error) {
+	repo, err := extgogit.Init(g.storer, g.worktreeFS)
+	if err != nil {
+		return nil, fmt.Errorf("unable to init repository: %w", err)
+	}
+	refSpec := config.RefSpec(fmt.Sprintf("+%[1]s:%[1]s", ref))
+	if _, err = repo.CreateRemote(&config.RemoteConfig{
+		Name:  git.DefaultRemote,
+		URLs:  []string{url},
+		Fetch: []config.RefSpec{refSpec},
+	}); err != nil {
+		return nil, fmt.Errorf("unable to create remote: %w", err)
+	}
+
+	var depth int
+	if opts.ShallowClone {
+		depth = 1
+	}
+	err = repo.FetchContext(ctx, &extgogit.FetchOptions{
+		RemoteName:   git.DefaultRemote,
+		RefSpecs:     []config.RefSpec{refSpec},
+		Depth:        depth,
+		Auth:         authMethod,
+		Progress:     nil,
+		Tags:         extgogit.NoTags,
+		ClientCert:   clientCert(g.authOpts),
+		ClientKey:    clientKey(g.authOpts),
+		CABundle:     caBundle(g.authOpts),
+		ProxyOptions: g.proxyOptions(g.authOpts),
+	})
+	if err != nil && !errors.Is(err, extgogit.NoErrAlreadyUpToDate) {
+		if errors.Is(err, transport.ErrRepositoryNotFound) {
+			return nil, git.ErrRepositoryNotFound{
+				Message: fmt.Sprintf("unable to clone: %s", err),
+				URL:     url,
+			}
+		}
+		return nil, fmt.Errorf("unable to fetch ref '%s' from '%s', the remote may not permit fetching it: %w", ref, url, err)
+	}
+
+	fetched, err := repo.Reference(ref, true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve ref '%s' after fetching it: %w", ref, err)
+	}
+	cc, err := repo.CommitObject(fetched.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve commit object for ref '%s', the ref must point to a commit: %w", ref, err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repo worktree: %w", err)
+	}
+	err = g.trace(ctx).checkout(func() error {
+		return w.Checkout(&extgogit.CheckoutOptions{
+			Hash:                      cc.Hash,
+			Force:                     true,
+			SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to checkout ref '%s': %w", ref, err)
+	}
+	if opts.RecurseSubmodules {
+		subs, err := w.Submodules()
+		if err == nil {
+			err = subs.UpdateContext(ctx, &extgogit.SubmoduleUpdateOptions{
+				Init:              true,
+				RecurseSubmodules: recurseSubmodules(true),
+				Auth:              authMethod,
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to update submodules of ref '%s': %w", ref, err)
+		}
+	}
+
+	g.repository = repo
+	g.sparseCheckoutDirectories = opts.SparseCheckoutDirectories
+	return build.CommitWithRef(cc, nil, ref)
+}
+
+// submoduleRevisions returns the revisions of the submodules checked out in
+// the worktree of the repository, including the nested submodules, with their
+// paths prefixed with the given path.
+func submoduleRevisions(repo *extgogit.Repository, prefix string) ([]git.SubmoduleRevision, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repo worktree: %w", err)
+	}
+	submodules, err := w.Submodules()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list submodules: %w", err)
+	}
+
+	var revisions []git.SubmoduleRevision
+	for _, sub := range submodules {
+		subPath := path.Join(prefix, sub.Config().Path)
+		status, err := sub.Status()
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve status of submodule '%s': %w", subPath, err)
+		}
+		// Skip the submodules which are not checked out.
+		if status.Current.IsZero() {
+			continue
+		}
+		revisions = append(revisions, git.SubmoduleRevision{
+			Path: subPath,
+			Hash: git.Hash(status.Current.String()),
+		})
+
+		subRepo, err := sub.Repository()
+		if err != nil {
+			return nil, fmt.Errorf("unable to open submodule '%s': %w", subPath, err)
+		}
+		nested, err := submoduleRevisions(subRepo, subPath)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, nested...)
+	}
+	return revisions, nil
 }
 
 func recurseSubmodules(recurse bool) extgogit.SubmoduleRescursivity {
@@ -517,7 +692,7 @@ func (g *Client) getRemoteHEAD(ctx context.Context, url string, ref plumbing.Ref
 		ClientKey:     clientKey(g.authOpts),
 		CABundle:      caBundle(g.authOpts),
 		PeelingOption: extgogit.AppendPeeled,
-		ProxyOptions:  g.proxy,
+		ProxyOptions:  g.proxyOptions(g.authOpts),
 	}
 	refs, err := remote.ListContext(ctx, listOpts)
 	if err != nil {
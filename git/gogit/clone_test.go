@@ -18,8 +18,10 @@ package gogit
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	iofs "io/fs"
 	"maps"
 	"net"
@@ -31,6 +33,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -575,6 +578,21 @@ func TestClone_cloneRefName(t *testing.T) {
 	})
 	g.Expect(err).ToNot(HaveOccurred())
 
+	// push a commit which is on no branch of the remote to custom refs, in
+	// the format of Gerrit changes, GitLab merge requests and git notes.
+	err = createBranch(repo, "unmerged")
+	g.Expect(err).ToNot(HaveOccurred())
+	unmergedHash, err := commitFile(repo, "change.txt", "unmerged change", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	err = repo.Push(&extgogit.PushOptions{
+		RefSpecs: []config.RefSpec{
+			config.RefSpec("+refs/heads/unmerged:refs/changes/01/1/1"),
+			config.RefSpec("+refs/heads/unmerged:refs/merge-requests/1/head"),
+			config.RefSpec("+refs/heads/unmerged:refs/notes/commits"),
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
 	tests := []struct {
 		name                   string
 		refName                string
@@ -628,11 +646,52 @@ func TestClone_cloneRefName(t *testing.T) {
 			expectedCommit:         hash.String(),
 			expectedConcreteCommit: true,
 		},
+		{
+			name:                   "ref name pointing to a Gerrit change",
+			refName:                "refs/changes/01/1/1",
+			filesCreated:           map[string]string{"change.txt": "unmerged change"},
+			expectedCommit:         unmergedHash.String(),
+			expectedConcreteCommit: true,
+		},
+		{
+			name:                   "ref name pointing to a merge request",
+			refName:                "refs/merge-requests/1/head",
+			filesCreated:           map[string]string{"change.txt": "unmerged change"},
+			expectedCommit:         unmergedHash.String(),
+			expectedConcreteCommit: true,
+		},
+		{
+			name:                   "ref name pointing to notes",
+			refName:                "refs/notes/commits",
+			filesCreated:           map[string]string{"change.txt": "unmerged change"},
+			expectedCommit:         unmergedHash.String(),
+			expectedConcreteCommit: true,
+		},
 		{
 			name:        "non existing ref",
 			refName:     "refs/tags/v0.2.0",
 			expectedErr: "unable to resolve ref 'refs/tags/v0.2.0' to a specific commit",
 		},
+		{
+			name:        "non existing custom ref",
+			refName:     "refs/changes/01/1/2",
+			expectedErr: "the ref is not advertised by the remote",
+		},
+		{
+			name:        "ref name which is not fully-qualified",
+			refName:     "main",
+			expectedErr: "invalid ref name 'main': the ref must be fully-qualified",
+		},
+		{
+			name:        "ref name with an invalid format",
+			refName:     "refs/heads/foo..bar",
+			expectedErr: "invalid ref name 'refs/heads/foo..bar'",
+		},
+		{
+			name:        "ref name with dereference suffix pointing to a branch",
+			refName:     "refs/heads/master" + tagDereferenceSuffix,
+			expectedErr: "suffix is only valid for tags",
+		},
 	}
 
 	for _, tt := range tests {
@@ -759,6 +818,120 @@ func Test_cloneSubmodule(t *testing.T) {
 	g.Expect(c).To(Equal(len(expectedPaths)))
 }
 
+func Test_cloneSubmoduleRevisions(t *testing.T) {
+	g := NewWithT(t)
+
+	server, err := gittestserver.NewTempGitServer()
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+
+	err = server.StartHTTP()
+	g.Expect(err).ToNot(HaveOccurred())
+	defer server.StopHTTP()
+
+	baseRepoPath := "base.git"
+	err = server.InitRepo("../testdata/git/repo", git.DefaultBranch, baseRepoPath)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	icingRepoPath := "icing.git"
+	err = server.InitRepo("../testdata/git/repo2", git.DefaultBranch, icingRepoPath)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	gitCmd := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		g.Expect(err).ToNot(HaveOccurred(), string(out))
+	}
+
+	icingDir := t.TempDir()
+	gitCmd(icingDir, "clone", server.HTTPAddress()+"/"+icingRepoPath, ".")
+	gitCmd(icingDir, "submodule", "add", server.HTTPAddress()+"/"+baseRepoPath, "base")
+	gitCmd(icingDir, "commit", "-m", "add submodule")
+	gitCmd(icingDir, "push", "origin", git.DefaultBranch)
+
+	baseDir := t.TempDir()
+	baseRepo, err := extgogit.PlainClone(baseDir, false, &extgogit.CloneOptions{
+		URL:           server.HTTPAddress() + "/" + baseRepoPath,
+		ReferenceName: plumbing.NewBranchReferenceName(git.DefaultBranch),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	baseHead, err := baseRepo.Head()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	clone := func(submoduleRevisions bool) *git.Commit {
+		t.Helper()
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{
+			Transport: git.HTTP,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		cc, err := ggc.Clone(context.TODO(), server.HTTPAddress()+"/"+icingRepoPath, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{
+				Branch: git.DefaultBranch,
+			},
+			RecurseSubmodules:  true,
+			SubmoduleRevisions: submoduleRevisions,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		return cc
+	}
+
+	// Without the option, the submodule revisions are not reported.
+	cc := clone(false)
+	g.Expect(cc.Submodules).To(BeNil())
+	g.Expect(cc.StringWithSubmodules()).To(Equal(cc.String()))
+
+	cc = clone(true)
+	g.Expect(cc.Submodules).To(Equal([]git.SubmoduleRevision{
+		{Path: "base", Hash: git.Hash(baseHead.Hash().String())},
+	}))
+	rev := cc.StringWithSubmodules()
+	g.Expect(rev).To(HavePrefix(cc.String() + "(+1 submodules: sha256:"))
+
+	// Advance the submodule branch only: the submodule is checked out at
+	// the commit pinned by the gitlink of the superproject.
+	newBaseHead, err := commitFile(baseRepo, "foo.txt", "advanced", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	err = baseRepo.Push(&extgogit.PushOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	pinned := clone(true)
+	g.Expect(pinned.Submodules).To(Equal(cc.Submodules))
+	g.Expect(pinned.StringWithSubmodules()).To(Equal(rev))
+
+	// Update the gitlink in the superproject.
+	gitCmd(icingDir, "submodule", "update", "--remote", "base")
+	gitCmd(icingDir, "commit", "-am", "update submodule")
+	gitCmd(icingDir, "push", "origin", git.DefaultBranch)
+
+	advanced := clone(true)
+	g.Expect(advanced.Submodules).To(Equal([]git.SubmoduleRevision{
+		{Path: "base", Hash: git.Hash(newBaseHead.String())},
+	}))
+	g.Expect(advanced.String()).ToNot(Equal(cc.String()))
+	g.Expect(advanced.StringWithSubmodules()).ToNot(Equal(rev))
+	g.Expect(advanced.StringWithSubmodules()).To(HavePrefix(advanced.String() + "(+1 submodules: sha256:"))
+
+	// The last observed revision with submodules skips the clone.
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{
+		Transport: git.HTTP,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	cc, err = ggc.Clone(context.TODO(), server.HTTPAddress()+"/"+icingRepoPath, repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{
+			Branch: git.DefaultBranch,
+		},
+		RecurseSubmodules:  true,
+		SubmoduleRevisions: true,
+		LastObservedCommit: advanced.StringWithSubmodules(),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(git.IsConcreteCommit(*cc)).To(BeFalse())
+	g.Expect(cc.String()).To(Equal(advanced.String()))
+}
+
 func Test_sparseCheckout(t *testing.T) {
 	repo, repoPath, err := initRepo(t.TempDir())
 	if err != nil {
@@ -1574,6 +1747,176 @@ func TestCloneAndPush_WithProxy(t *testing.T) {
 	g.Expect(cc1.String()).To(Equal(cc2.Hash.String()))
 }
 
+func TestClone_WithAuthenticatingProxy(t *testing.T) {
+	certs := newTestCertificates(t)
+	repoAuth := basicAuthHeader("repo-user", "repo-password")
+	proxyAuth := basicAuthHeader("proxy-user", "proxy-password")
+
+	tests := []struct {
+		name        string
+		proxyUser   *url.Userinfo
+		policy      git.ProxyAuthPolicy
+		wantAuth    string
+		wantErr     string
+		wantConnect bool
+	}{
+		{
+			name:        "repository credentials forwarded through the tunnel",
+			proxyUser:   url.UserPassword("proxy-user", "proxy-password"),
+			wantAuth:    repoAuth,
+			wantConnect: true,
+		},
+		{
+			name:        "repository credentials explicitly forwarded",
+			proxyUser:   url.UserPassword("proxy-user", "proxy-password"),
+			policy:      git.ProxyAuthForward,
+			wantAuth:    repoAuth,
+			wantConnect: true,
+		},
+		{
+			name:        "repository credentials replaced by proxy credentials",
+			proxyUser:   url.UserPassword("proxy-user", "proxy-password"),
+			policy:      git.ProxyAuthReplace,
+			wantAuth:    "",
+			wantConnect: true,
+		},
+		{
+			name:      "invalid proxy credentials",
+			proxyUser: url.UserPassword("proxy-user", "wrong"),
+			wantErr:   "Proxy Authentication Required",
+		},
+		{
+			name:    "missing proxy credentials",
+			wantErr: "Proxy Authentication Required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server, err := gittestserver.NewTempGitServer()
+			g.Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(server.Root())
+
+			// Validate the Authorization header received through the tunnel,
+			// and that the proxy credentials never reach the remote.
+			var leaked atomic.Bool
+			server.AddHTTPMiddlewares(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("Proxy-Authorization") != "" || r.Header.Get("Authorization") == proxyAuth {
+						leaked.Store(true)
+					}
+					if r.Header.Get("Authorization") != tt.wantAuth {
+						w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					next.ServeHTTP(w, r)
+				})
+			})
+			g.Expect(server.StartHTTPS(certs.certPEM, certs.keyPEM, certs.caPEM, "localhost")).To(Succeed())
+			defer server.StopHTTP()
+
+			repoPath := "proxy.git"
+			g.Expect(server.InitRepo(testRepositoryPath, git.DefaultBranch, repoPath)).To(Succeed())
+			repoURL := server.HTTPAddress() + "/" + repoPath
+
+			var connects atomic.Int32
+			proxyURL, err := url.Parse(newConnectProxy(t, proxyAuth, &connects))
+			g.Expect(err).ToNot(HaveOccurred())
+			proxyURL.User = tt.proxyUser
+
+			authOpts := &git.AuthOptions{
+				Transport:       git.HTTPS,
+				Username:        "repo-user",
+				Password:        "repo-password",
+				CAFile:          certs.caPEM,
+				ProxyURL:        proxyURL.String(),
+				ProxyAuthPolicy: tt.policy,
+			}
+			g.Expect(authOpts.Validate()).To(Succeed())
+
+			// The proxy of the auth options takes precedence over the one of the client.
+			ggc, err := NewClient(t.TempDir(), authOpts, WithDiskStorage(),
+				WithProxy(transport.ProxyOptions{URL: "http://127.0.0.1:1"}))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = ggc.Clone(context.TODO(), repoURL, repository.CloneConfig{
+				CheckoutStrategy: repository.CheckoutStrategy{
+					Branch: git.DefaultBranch,
+				},
+			})
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				g.Expect(connects.Load()).To(BeZero())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(connects.Load()).To(BeNumerically(">", 0))
+			g.Expect(leaked.Load()).To(BeFalse())
+		})
+	}
+}
+
+// newConnectProxy starts an HTTP proxy which tunnels the CONNECT requests
+// presenting the given Proxy-Authorization header, and rejects all other
+// requests. It returns the URL of the proxy.
+func newConnectProxy(t *testing.T, proxyAuth string, connects *atomic.Int32) string {
+	t.Helper()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Proxy-Authorization") != proxyAuth {
+			w.Header().Set("Proxy-Authenticate", `Basic realm="proxy"`)
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+
+		dst, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			dst.Close()
+			return
+		}
+		connects.Add(1)
+		if _, err := conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+			conn.Close()
+			dst.Close()
+			return
+		}
+
+		done := make(chan struct{}, 2)
+		tunnel := func(to, from net.Conn) {
+			_, _ = io.Copy(to, from)
+			done <- struct{}{}
+		}
+		go tunnel(dst, conn)
+		go tunnel(conn, dst)
+		go func() {
+			<-done
+			conn.Close()
+			dst.Close()
+		}()
+	}))
+	t.Cleanup(proxy.Close)
+
+	return proxy.URL
+}
+
+// basicAuthHeader returns the value of the basic Authorization header
+// for the given credentials.
+func basicAuthHeader(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
 func Test_getRemoteHEAD(t *testing.T) {
 	g := NewWithT(t)
 	repo, path, err := initRepo(t.TempDir())
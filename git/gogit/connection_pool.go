@@ -0,0 +1,452 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"container/list"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	gohttp "net/http"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// sharedTransportCacheSize is the maximum number of HTTP transports shared
+// by the remote operations, i.e. of distinct connection pool options, TLS
+// and proxy configurations.
+const sharedTransportCacheSize = 64
+
+func init() {
+	// The go-git HTTP client copies its HTTP transport for each operation
+	// configuring the TLS or the proxy of the endpoint, which opens new
+	// connections, nor does it allow the TLS verification to be customized
+	// per operation. Hence the HTTP(S) protocols are replaced at program
+	// initialization, before any remote operation, with a transport
+	// delegating the sessions of the clients of this package to clients
+	// sharing their HTTP transport per configuration, including the pins
+	// of the server identity. The sessions are in turn wrapped to retry
+	// the requests rejected with stale credentials from a
+	// git.CredentialsCallback. The configuration is read from the
+	// pooledAuth of each operation: the sessions of other go-git users
+	// are served by the default go-git HTTP client.
+	shared := newSharedTransport()
+	for _, scheme := range []string{"https", "http"} {
+		client.InstallProtocol(scheme, &protocolTransport{
+			pooled: newCredentialsTransport(shared),
+			next:   http.DefaultClient,
+		})
+	}
+}
+
+// protocolTransport is a transport.Transport which delegates the sessions
+// with a pooledAuth to the pooled transport, and the others to the next one.
+type protocolTransport struct {
+	pooled transport.Transport
+	next   transport.Transport
+}
+
+// NewUploadPackSession implements transport.Transport.
+func (t *protocolTransport) NewUploadPackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.UploadPackSession, error) {
+	return t.transportFor(auth).NewUploadPackSession(ep, auth)
+}
+
+// NewReceivePackSession implements transport.Transport.
+func (t *protocolTransport) NewReceivePackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.ReceivePackSession, error) {
+	return t.transportFor(auth).NewReceivePackSession(ep, auth)
+}
+
+func (t *protocolTransport) transportFor(auth transport.AuthMethod) transport.Transport {
+	if _, ok := auth.(*pooledAuth); ok {
+		return t.pooled
+	}
+	return t.next
+}
+
+// ConnectionPoolOptions configures the pool of the connections of the
+// remote operations over HTTP(S). The connections are shared by all the
+// clients of the process with the same pool options, TLS and proxy
+// configuration, while the credentials are set on each request of an
+// operation.
+type ConnectionPoolOptions struct {
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept
+	// per host.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is the duration after which an idle connection is
+	// closed, zero means no limit.
+	IdleConnTimeout time.Duration
+
+	// DisableHTTP2 disables the negotiation of HTTP/2 with the servers
+	// supporting it.
+	DisableHTTP2 bool
+
+	// HTTP2ReadIdleTimeout is the duration after which a health check
+	// ping is sent on an HTTP/2 connection which received no frame, so that
+	// the broken connections are detected before being reused. Zero
+	// disables the health checks.
+	HTTP2ReadIdleTimeout time.Duration
+
+	// HTTP2PingTimeout is the duration after which an HTTP/2 connection is
+	// closed if the health check ping is not answered.
+	HTTP2PingTimeout time.Duration
+}
+
+// DefaultConnectionPoolOptions returns the default ConnectionPoolOptions,
+// suited to controllers reconciling many repositories of the same host.
+func DefaultConnectionPoolOptions() ConnectionPoolOptions {
+	return ConnectionPoolOptions{
+		MaxIdleConnsPerHost:  16,
+		IdleConnTimeout:      90 * time.Second,
+		HTTP2ReadIdleTimeout: 30 * time.Second,
+		HTTP2PingTimeout:     15 * time.Second,
+	}
+}
+
+// WithConnectionPool configures the pool of the HTTP(S) connections of the
+// remote operations of the client. The clients with the same options share
+// their connections.
+func WithConnectionPool(opts ConnectionPoolOptions) ClientOption {
+	return func(c *Client) error {
+		if opts.MaxIdleConnsPerHost < 0 || opts.IdleConnTimeout < 0 ||
+			opts.HTTP2ReadIdleTimeout < 0 || opts.HTTP2PingTimeout < 0 {
+			return errors.New("connection pool options must not be negative")
+		}
+		c.connectionPool = opts
+		return nil
+	}
+}
+
+// remoteAuth returns the transport.AuthMethod for the remote operations
+// of the client with the given git.AuthOptions. The auth method of the
+// HTTP(S) operations is wrapped with the connection pool options of the
+// client, and the one of the SSH operations traces the handshake of the
+// operation of the context.
+func (g *Client) remoteAuth(ctx context.Context, opts *git.AuthOptions) (transport.AuthMethod, error) {
+	auth, err := transportAuth(opts, g.useDefaultKnownHosts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil || (opts.Transport != git.HTTPS && opts.Transport != git.HTTP) {
+		if pk, ok := auth.(*CustomPublicKeys); ok {
+			pk.trace = g.trace(ctx)
+		}
+		return auth, nil
+	}
+	var httpAuth http.AuthMethod
+	if auth != nil {
+		var ok bool
+		if httpAuth, ok = auth.(http.AuthMethod); !ok {
+			return nil, transport.ErrInvalidAuthMethod
+		}
+	}
+	if _, ok := httpAuth.(*pinnedAuth); ok {
+		if err := checkPinnedTransport(); err != nil {
+			return nil, err
+		}
+	}
+	trust := defaultTrustMode(caBundle(opts))
+	if opts.Transport == git.HTTPS {
+		if trust, err = g.tlsTrustMode(opts); err != nil {
+			return nil, err
+		}
+	}
+	return &pooledAuth{auth: httpAuth, pool: g.connectionPool, trust: trust, traced: g.traceHooks != nil}, nil
+}
+
+// pooledAuth wraps the http.AuthMethod of an HTTP(S) operation with the
+// options of the pool its connections are taken from, the trust mode
+// of its TLS configuration, and whether its client has trace hooks.
+type pooledAuth struct {
+	auth   http.AuthMethod
+	pool   ConnectionPoolOptions
+	trust  trustMode
+	traced bool
+}
+
+// SetAuth implements http.AuthMethod.
+func (a *pooledAuth) SetAuth(r *gohttp.Request) {
+	if a.auth != nil {
+		a.auth.SetAuth(r)
+	}
+}
+
+// Name implements transport.AuthMethod.
+func (a *pooledAuth) Name() string {
+	if a.auth != nil {
+		return a.auth.Name()
+	}
+	return "http-pooled"
+}
+
+// String implements transport.AuthMethod.
+func (a *pooledAuth) String() string {
+	if a.auth != nil {
+		return a.auth.String()
+	}
+	return a.Name()
+}
+
+// unwrap returns the wrapped auth method as a transport.AuthMethod,
+// or an untyped nil when there is none.
+func (a *pooledAuth) unwrap() transport.AuthMethod {
+	if a.auth == nil {
+		return nil
+	}
+	return a.auth
+}
+
+// sharedTransportKey identifies the HTTP transports shared by the remote
+// operations: by their connection pool options, the pins of the server
+// identity, the trust mode, the tracing of their requests, and the TLS and
+// proxy configuration of their endpoint.
+type sharedTransportKey struct {
+	pool       ConnectionPoolOptions
+	pins       string
+	trust      trustMode
+	traced     bool
+	caBundle   string
+	clientCert string
+	clientKey  string
+	insecure   bool
+	proxyURL   string
+}
+
+// sharedTransport is a transport.Transport which delegates the sessions to
+// go-git HTTP clients shared per sharedTransportKey. The sessions without a
+// pooledAuth use the default connection pool options. The least recently
+// used clients are evicted above sharedTransportCacheSize, and their idle
+// connections closed.
+type sharedTransport struct {
+	mu      sync.Mutex
+	clients map[sharedTransportKey]*list.Element
+	lru     *list.List
+}
+
+// sharedClient is a go-git HTTP client and its HTTP transport.
+type sharedClient struct {
+	key       sharedTransportKey
+	client    transport.Transport
+	transport *gohttp.Transport
+}
+
+func newSharedTransport() *sharedTransport {
+	return &sharedTransport{
+		clients: make(map[sharedTransportKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// NewUploadPackSession implements transport.Transport.
+func (t *sharedTransport) NewUploadPackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.UploadPackSession, error) {
+	c, ep, auth, err := t.clientFor(ep, auth)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewUploadPackSession(ep, auth)
+}
+
+// NewReceivePackSession implements transport.Transport.
+func (t *sharedTransport) NewReceivePackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.ReceivePackSession, error) {
+	c, ep, auth, err := t.clientFor(ep, auth)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewReceivePackSession(ep, auth)
+}
+
+// clientFor returns the shared client, the endpoint and the unwrapped auth
+// method to use for the given endpoint and auth method. The TLS and proxy
+// configuration of the returned endpoint is unset, as it is configured on
+// the HTTP transport of the client instead of a per-session copy of it.
+func (t *sharedTransport) clientFor(ep *transport.Endpoint, auth transport.AuthMethod) (
+	transport.Transport, *transport.Endpoint, transport.AuthMethod, error) {
+	key := sharedTransportKey{
+		pool:       DefaultConnectionPoolOptions(),
+		trust:      defaultTrustMode(ep.CaBundle),
+		caBundle:   string(ep.CaBundle),
+		clientCert: string(ep.ClientCert),
+		clientKey:  string(ep.ClientKey),
+		insecure:   ep.InsecureSkipTLS,
+	}
+	if ep.Proxy.URL != "" {
+		proxyURL, err := ep.Proxy.FullURL()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		key.proxyURL = proxyURL.String()
+	}
+	if pa, ok := auth.(*pooledAuth); ok {
+		key.pool = pa.pool
+		key.trust = pa.trust
+		key.traced = pa.traced
+		auth = pa.unwrap()
+	}
+	pinned, ok := auth.(*pinnedAuth)
+	if ok {
+		key.pins = pinned.fingerprint
+		auth = pinned.unwrap()
+	}
+
+	sessionEP := *ep
+	sessionEP.CaBundle = nil
+	sessionEP.ClientCert = nil
+	sessionEP.ClientKey = nil
+	sessionEP.InsecureSkipTLS = false
+	sessionEP.Proxy = transport.ProxyOptions{}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.clients[key]; ok {
+		t.lru.MoveToFront(e)
+		return e.Value.(*sharedClient).client, &sessionEP, auth, nil
+	}
+
+	tr, err := newPooledHTTPTransport(key.pool)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := configureSharedTransport(tr, ep, pinned, key.trust); err != nil {
+		return nil, nil, nil, err
+	}
+	// The HTTP client has no cookie jar, so that no state of an operation
+	// is shared with the others.
+	var next gohttp.RoundTripper = &trustTransport{next: tr, mode: key.trust}
+	if key.traced {
+		next = &traceTransport{next: next}
+	}
+	c := &sharedClient{
+		key: key,
+		client: http.NewClient(&gohttp.Client{
+			Transport: &drainingTransport{next: next},
+		}),
+		transport: tr,
+	}
+	t.clients[key] = t.lru.PushFront(c)
+	for t.lru.Len() > sharedTransportCacheSize {
+		oldest := t.lru.Remove(t.lru.Back()).(*sharedClient)
+		delete(t.clients, oldest.key)
+		oldest.transport.CloseIdleConnections()
+	}
+	return c.client, &sessionEP, auth, nil
+}
+
+// configureSharedTransport configures the HTTP transport with the TLS and
+// proxy configuration of the endpoint, the root CAs of the trust mode, and
+// the pins of the server identity.
+func configureSharedTransport(tr *gohttp.Transport, ep *transport.Endpoint, pinned *pinnedAuth, trust trustMode) error {
+	if len(ep.ClientCert) > 0 && len(ep.ClientKey) > 0 {
+		keyPair, err := tls.X509KeyPair(ep.ClientCert, ep.ClientKey)
+		if err != nil {
+			return err
+		}
+		tr.TLSClientConfig.Certificates = []tls.Certificate{keyPair}
+	}
+	roots, err := rootCAs(trust, ep.CaBundle)
+	if err != nil {
+		return err
+	}
+	tr.TLSClientConfig.RootCAs = roots
+	if ep.InsecureSkipTLS {
+		tr.TLSClientConfig.InsecureSkipVerify = true
+	}
+	if ep.Proxy.URL != "" {
+		proxyURL, err := ep.Proxy.FullURL()
+		if err != nil {
+			return err
+		}
+		tr.Proxy = gohttp.ProxyURL(proxyURL)
+	}
+	if pinned != nil {
+		verify, err := verifyPinnedPeer(pinned.sans, pinned.spkiHashes)
+		if err != nil {
+			return err
+		}
+		tr.TLSClientConfig.VerifyPeerCertificate = verify
+	}
+	return nil
+}
+
+// newPooledHTTPTransport returns a clone of the default HTTP transport
+// configured with the given connection pool options.
+func newPooledHTTPTransport(opts ConnectionPoolOptions) (*gohttp.Transport, error) {
+	base, ok := gohttp.DefaultTransport.(*gohttp.Transport)
+	if !ok {
+		return nil, errors.New("default HTTP transport is not an *http.Transport")
+	}
+	tr := base.Clone()
+	if tr.TLSClientConfig == nil {
+		tr.TLSClientConfig = &tls.Config{}
+	}
+	tr.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	if tr.MaxIdleConns > 0 && tr.MaxIdleConns < opts.MaxIdleConnsPerHost {
+		tr.MaxIdleConns = opts.MaxIdleConnsPerHost
+	}
+	tr.IdleConnTimeout = opts.IdleConnTimeout
+
+	// HTTP/2 is negotiated over TLS despite the custom TLS configuration.
+	tr.Protocols = new(gohttp.Protocols)
+	tr.Protocols.SetHTTP1(true)
+	if !opts.DisableHTTP2 {
+		tr.Protocols.SetHTTP2(true)
+		tr.HTTP2 = &gohttp.HTTP2Config{
+			SendPingTimeout: opts.HTTP2ReadIdleTimeout,
+			PingTimeout:     opts.HTTP2PingTimeout,
+		}
+	}
+	return tr, nil
+}
+
+// maxDrainBytes is the maximum number of bytes read from the remainder of a
+// response body when it is closed.
+const maxDrainBytes = 64 << 10
+
+// drainingTransport is an http.RoundTripper draining the remainder of the
+// response bodies when they are closed, e.g. the trailing packets of an
+// upload-pack response which go-git does not read, as the connection of a
+// response closed before its end is not reused.
+type drainingTransport struct {
+	next gohttp.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *drainingTransport) RoundTrip(req *gohttp.Request) (*gohttp.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = &drainingBody{ReadCloser: resp.Body}
+	return resp, nil
+}
+
+// drainingBody is a response body draining its remainder when closed.
+type drainingBody struct {
+	io.ReadCloser
+}
+
+// Close implements io.Closer.
+func (b *drainingBody) Close() error {
+	_, _ = io.CopyN(io.Discard, b.ReadCloser, maxDrainBytes)
+	return b.ReadCloser.Close()
+}
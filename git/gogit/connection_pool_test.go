@@ -0,0 +1,246 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+	"github.com/fluxcd/pkg/gittestserver"
+)
+
+// connectionRecorder records the client connections and the Authorization
+// header of the requests of a git server.
+type connectionRecorder struct {
+	mu          sync.Mutex
+	connections map[string]struct{}
+	auth        []string
+}
+
+func (r *connectionRecorder) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.connections[req.RemoteAddr] = struct{}{}
+		r.auth = append(r.auth, req.Header.Get("Authorization"))
+		r.mu.Unlock()
+		next.ServeHTTP(w, req)
+	})
+}
+
+// reset returns the number of connections and the Authorization headers
+// recorded since the last reset.
+func (r *connectionRecorder) reset() (int, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, auth := len(r.connections), r.auth
+	r.connections = map[string]struct{}{}
+	r.auth = nil
+	return n, auth
+}
+
+func TestClient_ConnectionReuse(t *testing.T) {
+	certs := newTestCertificates(t)
+
+	server, err := gittestserver.NewTempGitServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(server.Root())
+	recorder := &connectionRecorder{connections: map[string]struct{}{}}
+	server.AddHTTPMiddlewares(recorder.middleware)
+	if err := server.StartHTTPS(certs.certPEM, certs.keyPEM, certs.caPEM, "localhost"); err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopHTTP()
+
+	repoPath := "test.git"
+	if err := server.InitRepo(testRepositoryPath, git.DefaultBranch, repoPath); err != nil {
+		t.Fatal(err)
+	}
+	repoURL := server.HTTPAddress() + "/" + repoPath
+
+	// The options differ from those of the other tests, so that the
+	// connections are not reused from them.
+	pool := DefaultConnectionPoolOptions()
+	pool.IdleConnTimeout = time.Minute
+
+	clone := func(t *testing.T, authOpts *git.AuthOptions, opts ...ClientOption) {
+		t.Helper()
+		g := NewWithT(t)
+		ggc, err := NewClient(t.TempDir(), authOpts, append(opts, WithDiskStorage())...)
+		g.Expect(err).ToNot(HaveOccurred())
+		defer ggc.Close()
+		_, err = ggc.Clone(context.TODO(), repoURL, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{
+				Branch: git.DefaultBranch,
+			},
+			ShallowClone: true,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	t.Run("reuses the connections across clones", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder.reset()
+
+		for range 3 {
+			clone(t, &git.AuthOptions{
+				Transport: git.HTTPS,
+				CAFile:    certs.caPEM,
+				Username:  "user",
+				Password:  "pass",
+			}, WithConnectionPool(pool))
+		}
+		connections, auth := recorder.reset()
+		g.Expect(connections).To(Equal(1))
+		g.Expect(auth).ToNot(BeEmpty())
+	})
+
+	t.Run("does not share the credentials of the connections", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clone(t, &git.AuthOptions{
+			Transport: git.HTTPS,
+			CAFile:    certs.caPEM,
+			Username:  "user",
+			Password:  "pass",
+		}, WithConnectionPool(pool))
+		clone(t, &git.AuthOptions{
+			Transport: git.HTTPS,
+			CAFile:    certs.caPEM,
+		}, WithConnectionPool(pool))
+		clone(t, &git.AuthOptions{
+			Transport:   git.HTTPS,
+			CAFile:      certs.caPEM,
+			BearerToken: "token",
+		}, WithConnectionPool(pool))
+
+		connections, auth := recorder.reset()
+		g.Expect(connections).To(Equal(1))
+		g.Expect(auth).To(ContainElement(HavePrefix("Basic ")))
+		g.Expect(auth).To(ContainElement(BeEmpty()))
+		g.Expect(auth).To(ContainElement("Bearer token"))
+
+		// The requests of each operation carry its own credentials only.
+		var order []string
+		for _, a := range auth {
+			if len(order) == 0 || order[len(order)-1] != a {
+				order = append(order, a)
+			}
+		}
+		g.Expect(order).To(HaveLen(3))
+	})
+
+	t.Run("does not share the connections across pool options", func(t *testing.T) {
+		g := NewWithT(t)
+		other := pool
+		other.MaxIdleConnsPerHost = 4
+
+		opts := &git.AuthOptions{
+			Transport: git.HTTPS,
+			CAFile:    certs.caPEM,
+		}
+		clone(t, opts, WithConnectionPool(pool))
+		clone(t, opts, WithConnectionPool(other))
+		connections, _ := recorder.reset()
+		g.Expect(connections).To(Equal(2))
+	})
+}
+
+func TestWithConnectionPool(t *testing.T) {
+	g := NewWithT(t)
+
+	ggc, err := NewClient(t.TempDir(), nil, WithMemoryStorage())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ggc.connectionPool).To(Equal(DefaultConnectionPoolOptions()))
+
+	opts := ConnectionPoolOptions{MaxIdleConnsPerHost: 2, DisableHTTP2: true}
+	ggc, err = NewClient(t.TempDir(), nil, WithMemoryStorage(), WithConnectionPool(opts))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ggc.connectionPool).To(Equal(opts))
+
+	_, err = NewClient(t.TempDir(), nil, WithMemoryStorage(),
+		WithConnectionPool(ConnectionPoolOptions{IdleConnTimeout: -time.Second}))
+	g.Expect(err).To(HaveOccurred())
+
+	tr, err := newPooledHTTPTransport(opts)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tr.MaxIdleConnsPerHost).To(Equal(2))
+	g.Expect(tr.Protocols.HTTP2()).To(BeFalse())
+
+	tr, err = newPooledHTTPTransport(DefaultConnectionPoolOptions())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tr.Protocols.HTTP2()).To(BeTrue())
+	g.Expect(tr.HTTP2.SendPingTimeout).To(Equal(30 * time.Second))
+}
+
+// recordingTransport is a transport.Transport recording the auth methods of
+// its sessions.
+type recordingTransport struct {
+	auth []transport.AuthMethod
+}
+
+func (t *recordingTransport) NewUploadPackSession(_ *transport.Endpoint, auth transport.AuthMethod) (transport.UploadPackSession, error) {
+	t.auth = append(t.auth, auth)
+	return nil, nil
+}
+
+func (t *recordingTransport) NewReceivePackSession(_ *transport.Endpoint, auth transport.AuthMethod) (transport.ReceivePackSession, error) {
+	t.auth = append(t.auth, auth)
+	return nil, nil
+}
+
+func TestProtocolTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	// The protocols are installed at program initialization on top of the
+	// ones of go-git.
+	for _, scheme := range []string{"https", "http"} {
+		installed, ok := client.Protocols[scheme].(*protocolTransport)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(installed.next).To(Equal(githttp.DefaultClient))
+	}
+
+	// Only the sessions of the clients use the pooled transport.
+	pooled, next := &recordingTransport{}, &recordingTransport{}
+	tr := &protocolTransport{pooled: pooled, next: next}
+	ep, err := transport.NewEndpoint("https://example.com/repo.git")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	pa := &pooledAuth{pool: DefaultConnectionPoolOptions()}
+	basic := &githttp.BasicAuth{Username: "user", Password: "password"}
+	_, err = tr.NewUploadPackSession(ep, pa)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = tr.NewReceivePackSession(ep, basic)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = tr.NewUploadPackSession(ep, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(pooled.auth).To(Equal([]transport.AuthMethod{pa}))
+	g.Expect(next.auth).To(Equal([]transport.AuthMethod{basic, nil}))
+}
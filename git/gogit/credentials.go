@@ -0,0 +1,223 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gohttp "net/http"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// callbackAuth is an http.AuthMethod which gets the credentials of each
+// request from a git.CredentialsCallback.
+type callbackAuth struct {
+	callback git.CredentialsCallback
+
+	mu sync.Mutex
+	// ctx is the context of the session operation in progress, as go-git
+	// sets the auth of the requests before binding them to the context.
+	ctx context.Context
+	// err is the error of the last callback, surfaced by the session in
+	// place of the authentication error of the remote.
+	err error
+}
+
+// SetAuth implements http.AuthMethod.
+func (a *callbackAuth) SetAuth(r *gohttp.Request) {
+	a.mu.Lock()
+	ctx := a.ctx
+	a.mu.Unlock()
+	if ctx == nil {
+		ctx = r.Context()
+	}
+
+	username, password, err := a.callback(ctx)
+
+	a.mu.Lock()
+	a.err = err
+	a.mu.Unlock()
+	if err != nil {
+		return
+	}
+	if username != "" || password != "" {
+		r.SetBasicAuth(username, password)
+	}
+}
+
+// Name implements transport.AuthMethod.
+func (a *callbackAuth) Name() string {
+	return "http-credentials-callback"
+}
+
+// String implements transport.AuthMethod.
+func (a *callbackAuth) String() string {
+	return a.Name()
+}
+
+// bind sets the context of the callbacks for the duration of an operation,
+// and returns a function restoring the previous one.
+func (a *callbackAuth) bind(ctx context.Context) func() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	prev := a.ctx
+	a.ctx = ctx
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.ctx = prev
+	}
+}
+
+// wrapErr returns the error of the last callback, if any, or the given error.
+func (a *callbackAuth) wrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return fmt.Errorf("credentials callback failed: %w", a.err)
+	}
+	return err
+}
+
+// callbackAuthOf returns the callbackAuth of the given auth method, which
+// may be wrapped in a pooledAuth and a pinnedAuth, or nil.
+func callbackAuthOf(auth transport.AuthMethod) *callbackAuth {
+	switch a := auth.(type) {
+	case *callbackAuth:
+		return a
+	case *pooledAuth:
+		return callbackAuthOf(a.unwrap())
+	case *pinnedAuth:
+		if ca, ok := a.auth.(*callbackAuth); ok {
+			return ca
+		}
+	}
+	return nil
+}
+
+// credentialsTransport is a transport.Transport which retries once the
+// operations of the sessions using a callbackAuth when the remote rejects
+// the credentials, so that the callback can return refreshed ones. The
+// receive-pack requests are not retried as their packfile is streamed,
+// but their credentials are still requested from the callback.
+type credentialsTransport struct {
+	next transport.Transport
+}
+
+func newCredentialsTransport(next transport.Transport) *credentialsTransport {
+	if next == nil {
+		next = http.DefaultClient
+	}
+	return &credentialsTransport{next: next}
+}
+
+// NewUploadPackSession implements transport.Transport.
+func (t *credentialsTransport) NewUploadPackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.UploadPackSession, error) {
+	s, err := t.next.NewUploadPackSession(ep, auth)
+	if err != nil {
+		return nil, err
+	}
+	if ca := callbackAuthOf(auth); ca != nil {
+		return &callbackUploadPackSession{UploadPackSession: s, auth: ca}, nil
+	}
+	return s, nil
+}
+
+// NewReceivePackSession implements transport.Transport.
+func (t *credentialsTransport) NewReceivePackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.ReceivePackSession, error) {
+	s, err := t.next.NewReceivePackSession(ep, auth)
+	if err != nil {
+		return nil, err
+	}
+	if ca := callbackAuthOf(auth); ca != nil {
+		return &callbackReceivePackSession{ReceivePackSession: s, auth: ca}, nil
+	}
+	return s, nil
+}
+
+// withRetry runs the given operation with the context bound to the auth,
+// and runs it once more if the remote rejected the credentials.
+func withRetry[T any](ctx context.Context, auth *callbackAuth, op func(context.Context) (T, error)) (T, error) {
+	restore := auth.bind(ctx)
+	defer restore()
+
+	res, err := op(ctx)
+	if errors.Is(err, transport.ErrAuthenticationRequired) {
+		res, err = op(ctx)
+	}
+	return res, auth.wrapErr(err)
+}
+
+// callbackUploadPackSession is a transport.UploadPackSession retrying the
+// advertised references and upload-pack requests rejected by the remote.
+type callbackUploadPackSession struct {
+	transport.UploadPackSession
+	auth *callbackAuth
+}
+
+// AdvertisedReferences implements transport.Session.
+func (s *callbackUploadPackSession) AdvertisedReferences() (*packp.AdvRefs, error) {
+	return s.AdvertisedReferencesContext(context.TODO())
+}
+
+// AdvertisedReferencesContext implements transport.Session.
+func (s *callbackUploadPackSession) AdvertisedReferencesContext(ctx context.Context) (*packp.AdvRefs, error) {
+	return withRetry(ctx, s.auth, s.UploadPackSession.AdvertisedReferencesContext)
+}
+
+// UploadPack implements transport.UploadPackSession.
+func (s *callbackUploadPackSession) UploadPack(ctx context.Context, req *packp.UploadPackRequest) (*packp.UploadPackResponse, error) {
+	return withRetry(ctx, s.auth, func(ctx context.Context) (*packp.UploadPackResponse, error) {
+		return s.UploadPackSession.UploadPack(ctx, req)
+	})
+}
+
+// callbackReceivePackSession is a transport.ReceivePackSession retrying the
+// advertised references requests rejected by the remote.
+type callbackReceivePackSession struct {
+	transport.ReceivePackSession
+	auth *callbackAuth
+}
+
+// AdvertisedReferences implements transport.Session.
+func (s *callbackReceivePackSession) AdvertisedReferences() (*packp.AdvRefs, error) {
+	return s.AdvertisedReferencesContext(context.TODO())
+}
+
+// AdvertisedReferencesContext implements transport.Session.
+func (s *callbackReceivePackSession) AdvertisedReferencesContext(ctx context.Context) (*packp.AdvRefs, error) {
+	return withRetry(ctx, s.auth, s.ReceivePackSession.AdvertisedReferencesContext)
+}
+
+// ReceivePack implements transport.ReceivePackSession.
+func (s *callbackReceivePackSession) ReceivePack(ctx context.Context, req *packp.ReferenceUpdateRequest) (*packp.ReportStatus, error) {
+	restore := s.auth.bind(ctx)
+	defer restore()
+
+	res, err := s.ReceivePackSession.ReceivePack(ctx, req)
+	return res, s.auth.wrapErr(err)
+}
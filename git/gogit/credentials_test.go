@@ -0,0 +1,191 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+	"github.com/fluxcd/pkg/gittestserver"
+)
+
+// tokenServer is a git HTTP server which accepts a single valid token,
+// which is rotated with each rotate call.
+type tokenServer struct {
+	mu       sync.Mutex
+	valid    string
+	rejected int
+}
+
+func (s *tokenServer) rotate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.valid = token
+}
+
+func (s *tokenServer) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		_, password, ok := r.BasicAuth()
+		valid := ok && password == s.valid
+		if !valid {
+			s.rejected++
+		}
+		s.mu.Unlock()
+		if !valid {
+			w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tokenSource is a git.CredentialsCallback returning the current token of
+// a tokenServer, or a stale one until it is refreshed.
+type tokenSource struct {
+	mu     sync.Mutex
+	server *tokenServer
+	token  string
+	err    error
+	calls  int
+	ctx    context.Context
+}
+
+func (s *tokenSource) credentials(ctx context.Context) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	s.ctx = ctx
+	if s.err != nil {
+		return "", "", s.err
+	}
+	token := s.token
+	// The next call gets the current token of the server.
+	s.server.mu.Lock()
+	s.token = s.server.valid
+	s.server.mu.Unlock()
+	return "x-access-token", token, nil
+}
+
+func TestClient_CredentialsCallback(t *testing.T) {
+	g := NewWithT(t)
+
+	server, err := gittestserver.NewTempGitServer()
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+	g.Expect(server.StartHTTP()).To(Succeed())
+	defer server.StopHTTP()
+
+	repoPath := "callback.git"
+	g.Expect(server.InitRepo(testRepositoryPath, git.DefaultBranch, repoPath)).To(Succeed())
+
+	gitURL, err := url.Parse(server.HTTPAddress())
+	g.Expect(err).ToNot(HaveOccurred())
+	tokens := &tokenServer{valid: "token-1"}
+	front := httptest.NewServer(tokens.handler(httputil.NewSingleHostReverseProxy(gitURL)))
+	defer front.Close()
+	repoURL := front.URL + "/" + repoPath
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.TODO(), ctxKey{}, "clone")
+
+	t.Run("refreshes the stale token on clone and push", func(t *testing.T) {
+		g := NewWithT(t)
+
+		source := &tokenSource{server: tokens, token: "stale"}
+		authOpts := &git.AuthOptions{
+			Transport:           git.HTTP,
+			CredentialsCallback: source.credentials,
+		}
+		g.Expect(authOpts.Validate()).To(Succeed())
+
+		ggc, err := NewClient(t.TempDir(), authOpts, WithDiskStorage())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = ggc.Clone(ctx, repoURL, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(source.calls).To(BeNumerically(">", 2))
+		g.Expect(source.ctx.Value(ctxKey{})).To(Equal("clone"))
+
+		// Expire the token in the middle of the operations.
+		tokens.rotate("token-2")
+		cc, err := commitFile(ggc.repository, "callback", "refreshed", time.Now())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(ggc.Push(context.TODO(), repository.PushConfig{})).To(Succeed())
+
+		tokens.rotate("token-3")
+		refs, err := ggc.ListRemote(context.TODO(), repoURL, authOpts, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(refs).To(ContainElement(HaveField("Hash", git.Hash(cc.String()))))
+		// The stale token of each operation was rejected once.
+		g.Expect(tokens.rejected).To(Equal(3))
+	})
+
+	t.Run("fails with the credentials rejected twice", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{
+			Transport: git.HTTP,
+			CredentialsCallback: func(context.Context) (string, string, error) {
+				calls++
+				return "x-access-token", "stale", nil
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = ggc.Clone(ctx, repoURL, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+		})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, transport.ErrAuthenticationRequired)).To(BeTrue())
+		g.Expect(calls).To(Equal(2))
+	})
+
+	t.Run("returns the error of the callback", func(t *testing.T) {
+		g := NewWithT(t)
+
+		source := &tokenSource{server: tokens, err: errors.New("token exchange failed")}
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{
+			Transport:           git.HTTP,
+			CredentialsCallback: source.credentials,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = ggc.Clone(ctx, repoURL, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+		})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("credentials callback failed: token exchange failed"))
+	})
+}
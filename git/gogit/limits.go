@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// packfileHeaderSize is the size of the header of a packfile, i.e. the
+// signature, the version and the number of objects.
+const packfileHeaderSize = 12
+
+// cloneLimits counts the objects and the bytes received while cloning, and
+// fails once the configured maximum number of objects or size is exceeded.
+type cloneLimits struct {
+	maxObjects int
+	maxSize    int64
+	objects    int
+	size       int64
+}
+
+// add counts the given objects and bytes, and returns a
+// git.ErrRepositoryTooLarge error if a limit is exceeded.
+func (l *cloneLimits) add(objects int, size int64) error {
+	l.objects += objects
+	l.size += size
+	if (l.maxObjects > 0 && l.objects > l.maxObjects) || (l.maxSize > 0 && l.size > l.maxSize) {
+		return git.ErrRepositoryTooLarge{
+			MaxObjects: l.maxObjects,
+			MaxSize:    l.maxSize,
+			Objects:    l.objects,
+			Size:       l.size,
+		}
+	}
+	return nil
+}
+
+// newLimitedStorer returns a storer enforcing the given limits on the
+// objects written to s.
+//
+// For the storers writing the received packfiles as is, e.g. the filesystem
+// storage, the objects are counted from the packfile header and the size is
+// the size of the packfiles. For the other storers, e.g. the memory storage,
+// the objects are counted as they are decoded and the size is their decoded
+// size.
+func newLimitedStorer(s storage.Storer, limits *cloneLimits) storage.Storer {
+	ls := &limitedStorer{Storer: s, limits: limits}
+	if _, ok := s.(storer.PackfileWriter); ok {
+		return &limitedPackfileStorer{limitedStorer: ls}
+	}
+	return ls
+}
+
+// limitedStorer is a storage.Storer enforcing the clone limits on the
+// objects written to the storage.
+type limitedStorer struct {
+	storage.Storer
+	limits *cloneLimits
+}
+
+// Init initializes the underlying storer if it is a storer.Initializer.
+func (s *limitedStorer) Init() error {
+	if i, ok := s.Storer.(storer.Initializer); ok {
+		return i.Init()
+	}
+	return nil
+}
+
+// SetEncodedObject counts the object and writes it to the underlying storer.
+func (s *limitedStorer) SetEncodedObject(obj plumbing.EncodedObject) (plumbing.Hash, error) {
+	if err := s.limits.add(1, obj.Size()); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return s.Storer.SetEncodedObject(obj)
+}
+
+// Module returns the storer of the given submodule, sharing the limits with
+// the parent repository.
+func (s *limitedStorer) Module(name string) (storage.Storer, error) {
+	m, err := s.Storer.Module(name)
+	if err != nil {
+		return nil, err
+	}
+	return newLimitedStorer(m, s.limits), nil
+}
+
+// limitedPackfileStorer is a limitedStorer for the storers implementing
+// storer.PackfileWriter.
+type limitedPackfileStorer struct {
+	*limitedStorer
+}
+
+// PackfileWriter returns a writer of the underlying storer enforcing the
+// limits on the written packfile.
+func (s *limitedPackfileStorer) PackfileWriter() (io.WriteCloser, error) {
+	w, err := s.Storer.(storer.PackfileWriter).PackfileWriter()
+	if err != nil {
+		return nil, err
+	}
+	return &limitedPackfileWriter{WriteCloser: w, limits: s.limits}, nil
+}
+
+// limitedPackfileWriter counts the bytes written to a packfile, and the
+// objects from the packfile header.
+type limitedPackfileWriter struct {
+	io.WriteCloser
+	limits *cloneLimits
+	header []byte
+}
+
+// Write counts the given bytes, and the objects once the header is written,
+// before writing them to the underlying writer.
+func (w *limitedPackfileWriter) Write(p []byte) (int, error) {
+	var objects int
+	if len(w.header) < packfileHeaderSize {
+		n := min(packfileHeaderSize-len(w.header), len(p))
+		w.header = append(w.header, p[:n]...)
+		if len(w.header) == packfileHeaderSize {
+			objects = int(binary.BigEndian.Uint32(w.header[8:]))
+		}
+	}
+	if err := w.limits.add(objects, int64(len(p))); err != nil {
+		return 0, err
+	}
+	return w.WriteCloser.Write(p)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestClone_WithLimits(t *testing.T) {
+	server := newCacheTestServer(t, "limits.git")
+	repoURL := server.HTTPAddress() + "/limits.git"
+
+	storages := []struct {
+		name string
+		opts []ClientOption
+	}{
+		{name: "disk storage", opts: []ClientOption{WithDiskStorage()}},
+		{name: "memory storage", opts: []ClientOption{WithMemoryStorage()}},
+	}
+	tests := []struct {
+		name       string
+		maxObjects int
+		maxSize    int64
+		wantErr    bool
+	}{
+		{name: "within the limits", maxObjects: 1000, maxSize: 10 << 20},
+		{name: "too many objects", maxObjects: 1, wantErr: true},
+		{name: "too large", maxSize: 16, wantErr: true},
+	}
+	for _, storage := range storages {
+		for _, tt := range tests {
+			t.Run(storage.name+"/"+tt.name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, storage.opts...)
+				g.Expect(err).ToNot(HaveOccurred())
+				cc, err := ggc.Clone(context.TODO(), repoURL, repository.CloneConfig{
+					CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+					MaxObjects:       tt.maxObjects,
+					MaxSize:          tt.maxSize,
+				})
+
+				if !tt.wantErr {
+					g.Expect(err).ToNot(HaveOccurred())
+					g.Expect(git.IsConcreteCommit(*cc)).To(BeTrue())
+					// The repository is usable after the clone.
+					_, err = commitFile(ggc.repository, "bar.txt", "new", cc.Author.When)
+					g.Expect(err).ToNot(HaveOccurred())
+					return
+				}
+
+				var tooLargeErr git.ErrRepositoryTooLarge
+				g.Expect(errors.As(err, &tooLargeErr)).To(BeTrue(), "unexpected error: %v", err)
+				g.Expect(tooLargeErr.URL).To(Equal(repoURL))
+				g.Expect(tooLargeErr.MaxObjects).To(Equal(tt.maxObjects))
+				g.Expect(tooLargeErr.MaxSize).To(Equal(tt.maxSize))
+				if tt.maxObjects > 0 {
+					g.Expect(tooLargeErr.Objects).To(BeNumerically(">", tt.maxObjects))
+				}
+				if tt.maxSize > 0 {
+					g.Expect(tooLargeErr.Size).To(BeNumerically(">", tt.maxSize))
+				}
+				g.Expect(err.Error()).To(ContainSubstring("repository exceeds the clone limit"))
+				g.Expect(ggc.repository).To(BeNil())
+
+				// The partially cloned data is discarded.
+				files, err := os.ReadDir(ggc.Path())
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(files).To(BeEmpty())
+				if s, ok := ggc.storer.(*memory.Storage); ok {
+					g.Expect(s.Objects).To(BeEmpty())
+				}
+			})
+		}
+	}
+}
+
+func TestClone_WithLimits_Negative(t *testing.T) {
+	g := NewWithT(t)
+
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.Clone(context.TODO(), "http://localhost/repo.git", repository.CloneConfig{
+		MaxSize: -1,
+	})
+	g.Expect(err).To(MatchError("the clone max objects and max size cannot be negative"))
+}
+
+func TestClone_WithRepositoryCache_Limits(t *testing.T) {
+	g := NewWithT(t)
+
+	server := newCacheTestServer(t, "cached.git")
+	repoURL := server.HTTPAddress() + "/cached.git"
+	cacheDir := t.TempDir()
+
+	clone := func(maxObjects int) (*Client, error) {
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP},
+			WithDiskStorage(), WithRepositoryCache(cacheDir, 5))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.Clone(context.TODO(), repoURL, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+			MaxObjects:       maxObjects,
+		})
+		return ggc, err
+	}
+
+	// The fetch into the cache entry is aborted, and the entry discarded.
+	ggc, err := clone(1)
+	var tooLargeErr git.ErrRepositoryTooLarge
+	g.Expect(errors.As(err, &tooLargeErr)).To(BeTrue(), "unexpected error: %v", err)
+	g.Expect(tooLargeErr.URL).To(Equal(repoURL))
+	entry := ggc.cache.entryPath(repoURL)
+	g.Expect(entry).ToNot(BeADirectory())
+	files, err := os.ReadDir(ggc.Path())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(files).To(BeEmpty())
+
+	ggc, err = clone(1000)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entry).To(BeADirectory())
+	g.Expect(filepath.Join(ggc.Path(), "foo.txt")).To(BeARegularFile())
+
+	// The clone from an up-to-date cache entry is limited as well.
+	_, err = clone(1)
+	g.Expect(errors.As(err, &tooLargeErr)).To(BeTrue(), "unexpected error: %v", err)
+	g.Expect(tooLargeErr.URL).To(Equal(repoURL))
+}
+
+func TestLimitedPackfileWriter(t *testing.T) {
+	g := NewWithT(t)
+
+	header := []byte{'P', 'A', 'C', 'K', 0, 0, 0, 2, 0, 0, 0, 3}
+	w := &limitedPackfileWriter{WriteCloser: nopWriteCloser{}, limits: &cloneLimits{maxObjects: 2}}
+
+	// The objects are counted once the header is complete.
+	_, err := w.Write(header[:5])
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = w.Write(header[5:])
+	g.Expect(err).To(Equal(git.ErrRepositoryTooLarge{MaxObjects: 2, Objects: 3, Size: 12}))
+
+	w = &limitedPackfileWriter{WriteCloser: nopWriteCloser{}, limits: &cloneLimits{maxSize: 16}}
+	_, err = w.Write(append(header, make([]byte, 4)...))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = w.Write([]byte{0})
+	g.Expect(err).To(Equal(git.ErrRepositoryTooLarge{MaxSize: 16, Objects: 3, Size: 17}))
+}
+
+func TestLimitedStorer(t *testing.T) {
+	g := NewWithT(t)
+
+	s := newLimitedStorer(memory.NewStorage(), &cloneLimits{maxObjects: 1})
+	g.Expect(s).ToNot(BeAssignableToTypeOf(&limitedPackfileStorer{}))
+
+	obj := s.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	_, err := s.SetEncodedObject(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = s.SetEncodedObject(obj)
+	g.Expect(err).To(Equal(git.ErrRepositoryTooLarge{MaxObjects: 1, Objects: 2}))
+}
+
+type nopWriteCloser struct{}
+
+func (nopWriteCloser) Write(p []byte) (int, error) { return len(p), nil }
+func (nopWriteCloser) Close() error                { return nil }
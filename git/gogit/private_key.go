@@ -0,0 +1,273 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+const (
+	pemTypeEncryptedPKCS8 = "ENCRYPTED PRIVATE KEY"
+	pemTypeOpenSSH        = "OPENSSH PRIVATE KEY"
+
+	// openSSHKeyMagic is the magic prefix of the OpenSSH private keys.
+	openSSHKeyMagic = "openssh-key-v1\x00"
+)
+
+var (
+	// openSSHCiphers are the OpenSSH private key ciphers supported by
+	// golang.org/x/crypto/ssh.
+	openSSHCiphers = []string{"none", "aes256-ctr", "aes256-cbc"}
+	// openSSHKDFs are the OpenSSH private key KDFs supported by
+	// golang.org/x/crypto/ssh.
+	openSSHKDFs = []string{"none", "bcrypt"}
+	// pemCiphers are the DEK-Info ciphers supported by x509.DecryptPEMBlock.
+	pemCiphers = []string{"DES-CBC", "DES-EDE3-CBC", "AES-128-CBC", "AES-192-CBC", "AES-256-CBC"}
+)
+
+// parsePrivateKey parses the given PEM encoded SSH private key, in the PEM
+// (e.g. PKCS#1), PKCS#8 or OpenSSH format, decrypting it with the passphrase
+// if it is encrypted. A missing or incorrect passphrase results in a
+// git.ErrPrivateKeyPassphrase error, and an unsupported encryption in a
+// git.ErrPrivateKeyUnsupported error.
+func parsePrivateKey(pemBytes []byte, passphrase string) (gossh.Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("ssh: no key found")
+	}
+
+	if block.Type == pemTypeEncryptedPKCS8 {
+		if passphrase == "" {
+			return nil, git.ErrPrivateKeyPassphrase{Format: git.PrivateKeyFormatPKCS8, Missing: true}
+		}
+		der, err := decryptPKCS8(block.Bytes, []byte(passphrase))
+		if err != nil {
+			return nil, err
+		}
+		key, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			// The decrypted data is random noise if the passphrase is incorrect.
+			return nil, git.ErrPrivateKeyPassphrase{Format: git.PrivateKeyFormatPKCS8}
+		}
+		return gossh.NewSignerFromKey(key)
+	}
+
+	signer, err := gossh.ParsePrivateKey(pemBytes)
+	var missingErr *gossh.PassphraseMissingError
+	if !errors.As(err, &missingErr) {
+		return signer, err
+	}
+
+	format := git.PrivateKeyFormatPEM
+	if block.Type == pemTypeOpenSSH {
+		format = git.PrivateKeyFormatOpenSSH
+	}
+	if err := checkPrivateKeyCipher(block); err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, git.ErrPrivateKeyPassphrase{Format: format, Missing: true}
+	}
+	signer, err = gossh.ParsePrivateKeyWithPassphrase(pemBytes, []byte(passphrase))
+	switch {
+	case errors.Is(err, x509.IncorrectPasswordError):
+		return nil, git.ErrPrivateKeyPassphrase{Format: format}
+	case err != nil && format == git.PrivateKeyFormatPEM:
+		// The PEM encryption does not always detect an incorrect passphrase,
+		// in which case the decrypted key is random noise.
+		return nil, git.ErrPrivateKeyPassphrase{Format: format}
+	}
+	return signer, err
+}
+
+// checkPrivateKeyCipher returns a git.ErrPrivateKeyUnsupported error if
+// the encrypted PEM or OpenSSH private key uses a cipher or a KDF which is
+// not supported.
+func checkPrivateKeyCipher(block *pem.Block) error {
+	if block.Type != pemTypeOpenSSH {
+		mode, _, _ := strings.Cut(block.Headers["DEK-Info"], ",")
+		if !slices.Contains(pemCiphers, mode) {
+			return git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatPEM, Cipher: mode}
+		}
+		return nil
+	}
+
+	if !bytes.HasPrefix(block.Bytes, []byte(openSSHKeyMagic)) {
+		// Let the parsing report the invalid key.
+		return nil
+	}
+	var header struct {
+		CipherName string
+		KdfName    string
+		KdfOpts    string
+		Rest       []byte `ssh:"rest"`
+	}
+	if err := gossh.Unmarshal(block.Bytes[len(openSSHKeyMagic):], &header); err != nil {
+		return nil
+	}
+	if !slices.Contains(openSSHCiphers, header.CipherName) {
+		return git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatOpenSSH, Cipher: header.CipherName}
+	}
+	if !slices.Contains(openSSHKDFs, header.KdfName) {
+		return git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatOpenSSH, Cipher: header.KdfName}
+	}
+	return nil
+}
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// pkcs8Cipher is a block cipher of the PBES2 encryption scheme.
+type pkcs8Cipher struct {
+	keySize   int
+	newCipher func(key []byte) (cipher.Block, error)
+}
+
+var pkcs8Ciphers = map[string]pkcs8Cipher{
+	oidAES128CBC.String():  {keySize: 16, newCipher: aes.NewCipher},
+	oidAES192CBC.String():  {keySize: 24, newCipher: aes.NewCipher},
+	oidAES256CBC.String():  {keySize: 32, newCipher: aes.NewCipher},
+	oidDESEDE3CBC.String(): {keySize: 24, newCipher: des.NewTripleDESCipher},
+}
+
+var pkcs8PRFs = map[string]func() hash.Hash{
+	oidHMACWithSHA1.String():   sha1.New,
+	oidHMACWithSHA256.String(): sha256.New,
+	oidHMACWithSHA384.String(): sha512.New384,
+	oidHMACWithSHA512.String(): sha512.New,
+}
+
+// encryptedPrivateKeyInfo is the PKCS#8 EncryptedPrivateKeyInfo structure.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkixAlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pkixAlgorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+// pbes2Params are the parameters of the PBES2 encryption scheme (RFC 8018).
+type pbes2Params struct {
+	KeyDerivationFunc pkixAlgorithmIdentifier
+	EncryptionScheme  pkixAlgorithmIdentifier
+}
+
+// pbkdf2Params are the parameters of the PBKDF2 key derivation function
+// (RFC 8018).
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                     `asn1:"optional"`
+	PRF            pkixAlgorithmIdentifier `asn1:"optional"`
+}
+
+// decryptPKCS8 decrypts the DER encoded PKCS#8 EncryptedPrivateKeyInfo with
+// the passphrase, and returns the DER encoded PKCS#8 PrivateKeyInfo. Only the
+// PBES2 scheme with PBKDF2 and the AES or 3DES CBC ciphers is supported,
+// as generated by OpenSSL.
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("ssh: failed to parse the PKCS#8 encrypted private key: %w", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatPKCS8, Cipher: info.Algorithm.Algorithm.String()}
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("ssh: failed to parse the PKCS#8 PBES2 parameters: %w", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatPKCS8, Cipher: params.KeyDerivationFunc.Algorithm.String()}
+	}
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, fmt.Errorf("ssh: failed to parse the PKCS#8 PBKDF2 parameters: %w", err)
+	}
+	prf := sha1.New
+	if len(kdfParams.PRF.Algorithm) > 0 {
+		var ok bool
+		if prf, ok = pkcs8PRFs[kdfParams.PRF.Algorithm.String()]; !ok {
+			return nil, git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatPKCS8, Cipher: kdfParams.PRF.Algorithm.String()}
+		}
+	}
+
+	c, ok := pkcs8Ciphers[params.EncryptionScheme.Algorithm.String()]
+	if !ok {
+		return nil, git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatPKCS8, Cipher: params.EncryptionScheme.Algorithm.String()}
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("ssh: failed to parse the PKCS#8 cipher parameters: %w", err)
+	}
+
+	key, err := pbkdf2.Key(prf, string(passphrase), kdfParams.Salt, kdfParams.IterationCount, c.keySize)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: failed to derive the PKCS#8 encryption key: %w", err)
+	}
+	block, err := c.newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(info.EncryptedData)%block.BlockSize() != 0 || len(info.EncryptedData) == 0 {
+		return nil, errors.New("ssh: invalid PKCS#8 encrypted private key length")
+	}
+	data := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data, info.EncryptedData)
+
+	// An invalid PKCS#7 padding is the result of an incorrect passphrase.
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > block.BlockSize() ||
+		!bytes.Equal(data[len(data)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, git.ErrPrivateKeyPassphrase{Format: git.PrivateKeyFormatPKCS8}
+	}
+	return data[:len(data)-padding], nil
+}
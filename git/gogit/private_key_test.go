@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// encryptPKCS8 encrypts the PKCS#8 private key with PBES2, PBKDF2 with
+// HMAC-SHA256 and AES-256-CBC, as 'openssl pkcs8 -topk8 -v2 aes256' does,
+// declaring the given OID as the encryption scheme.
+func encryptPKCS8(g *WithT, key crypto.PrivateKey, passphrase string, encryptionScheme asn1.ObjectIdentifier) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	salt := make([]byte, 16)
+	iv := make([]byte, aes.BlockSize)
+	_, err = rand.Read(salt)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = rand.Read(iv)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	derivedKey, err := pbkdf2.Key(sha256.New, passphrase, salt, 2048, 32)
+	g.Expect(err).ToNot(HaveOccurred())
+	block, err := aes.NewCipher(derivedKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	padding := aes.BlockSize - len(der)%aes.BlockSize
+	data := append(der, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+	marshal := func(v any) asn1.RawValue {
+		b, err := asn1.Marshal(v)
+		g.Expect(err).ToNot(HaveOccurred())
+		return asn1.RawValue{FullBytes: b}
+	}
+	info := encryptedPrivateKeyInfo{
+		Algorithm: pkixAlgorithmIdentifier{
+			Algorithm: oidPBES2,
+			Parameters: marshal(pbes2Params{
+				KeyDerivationFunc: pkixAlgorithmIdentifier{
+					Algorithm: oidPBKDF2,
+					Parameters: marshal(pbkdf2Params{
+						Salt:           salt,
+						IterationCount: 2048,
+						PRF:            pkixAlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+					}),
+				},
+				EncryptionScheme: pkixAlgorithmIdentifier{Algorithm: encryptionScheme, Parameters: marshal(iv)},
+			}),
+		},
+		EncryptedData: data,
+	}
+	infoDER, err := asn1.Marshal(info)
+	g.Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeEncryptedPKCS8, Bytes: infoDER})
+}
+
+// privateKeyFixtures returns the given key in each of the supported formats,
+// unencrypted and encrypted with the passphrase.
+func privateKeyFixtures(g *WithT, passphrase string) map[string]struct {
+	key       []byte
+	encrypted []byte
+	format    string
+} {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).ToNot(HaveOccurred())
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	pkcs1 := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}
+	encryptedPKCS1, err := x509.EncryptPEMBlock(rand.Reader, pkcs1.Type, pkcs1.Bytes, []byte(passphrase), x509.PEMCipherAES256)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	openssh, err := gossh.MarshalPrivateKey(edKey, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	encryptedOpenSSH, err := gossh.MarshalPrivateKeyWithPassphrase(edKey, "", []byte(passphrase))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return map[string]struct {
+		key       []byte
+		encrypted []byte
+		format    string
+	}{
+		"PKCS#1": {
+			key:       pem.EncodeToMemory(pkcs1),
+			encrypted: pem.EncodeToMemory(encryptedPKCS1),
+			format:    git.PrivateKeyFormatPEM,
+		},
+		"PKCS#8": {
+			key:       pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER}),
+			encrypted: encryptPKCS8(g, ecKey, passphrase, oidAES256CBC),
+			format:    git.PrivateKeyFormatPKCS8,
+		},
+		"OpenSSH": {
+			key:       pem.EncodeToMemory(openssh),
+			encrypted: pem.EncodeToMemory(encryptedOpenSSH),
+			format:    git.PrivateKeyFormatOpenSSH,
+		},
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	g := NewWithT(t)
+
+	const passphrase = "correct horse battery staple"
+	for name, fixture := range privateKeyFixtures(g, passphrase) {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			unencrypted, err := parsePrivateKey(fixture.key, "")
+			g.Expect(err).ToNot(HaveOccurred())
+			// A passphrase is ignored for an unencrypted key.
+			_, err = parsePrivateKey(fixture.key, passphrase)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			signer, err := parsePrivateKey(fixture.encrypted, passphrase)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(signer.PublicKey().Type()).To(Equal(unencrypted.PublicKey().Type()))
+
+			_, err = parsePrivateKey(fixture.encrypted, "")
+			g.Expect(err).To(Equal(git.ErrPrivateKeyPassphrase{Format: fixture.format, Missing: true}))
+			g.Expect(err.Error()).To(ContainSubstring("no passphrase was provided"))
+
+			_, err = parsePrivateKey(fixture.encrypted, "wrong")
+			g.Expect(err).To(Equal(git.ErrPrivateKeyPassphrase{Format: fixture.format}))
+			g.Expect(errors.Is(err, x509.IncorrectPasswordError)).To(BeTrue())
+		})
+	}
+}
+
+func TestParsePrivateKey_Unsupported(t *testing.T) {
+	g := NewWithT(t)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// An OpenSSH key encrypted with a cipher not supported by x/crypto/ssh,
+	// e.g. with 'ssh-keygen -Z aes128-ctr'.
+	block, err := gossh.MarshalPrivateKeyWithPassphrase(edKey, "", []byte("foo"))
+	g.Expect(err).ToNot(HaveOccurred())
+	var header struct {
+		CipherName string
+		Rest       []byte `ssh:"rest"`
+	}
+	g.Expect(gossh.Unmarshal(block.Bytes[len(openSSHKeyMagic):], &header)).To(Succeed())
+	header.CipherName = "aes128-ctr"
+	block.Bytes = append([]byte(openSSHKeyMagic), gossh.Marshal(header)...)
+	_, err = parsePrivateKey(pem.EncodeToMemory(block), "foo")
+	g.Expect(err).To(Equal(git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatOpenSSH, Cipher: "aes128-ctr"}))
+
+	// A legacy PEM key with an unknown DEK-Info cipher.
+	pemBlock := &pem.Block{
+		Type:    "RSA PRIVATE KEY",
+		Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "CAMELLIA-256-CBC,0B016973B2A761D31E6B388D0F327C35"},
+		Bytes:   make([]byte, 32),
+	}
+	_, err = parsePrivateKey(pem.EncodeToMemory(pemBlock), "foo")
+	g.Expect(err).To(Equal(git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatPEM, Cipher: "CAMELLIA-256-CBC"}))
+
+	// A PKCS#8 key encrypted with a cipher other than AES or 3DES CBC, e.g.
+	// AES-256-GCM.
+	oidAES256GCM := asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 46}
+	_, err = parsePrivateKey(encryptPKCS8(g, ecKey, "foo", oidAES256GCM), "foo")
+	g.Expect(err).To(Equal(git.ErrPrivateKeyUnsupported{Format: git.PrivateKeyFormatPKCS8, Cipher: oidAES256GCM.String()}))
+	g.Expect(err.Error()).To(Equal("the PKCS#8 SSH private key is encrypted with the unsupported cipher '2.16.840.1.101.3.4.1.46'"))
+}
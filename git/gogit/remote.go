@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// ListRemote lists the branches and tags advertised by the remote at the
+// given URL, without cloning the repository. If auth is nil, the auth
+// options of the client are used. The proxy options of the client apply.
+//
+// Only references under refs/heads and refs/tags are returned. When patterns
+// are provided, a reference is included if its full name (e.g.
+// 'refs/heads/feature/*') or its short name (e.g. 'feature/*') matches any
+// of the glob patterns, as defined by path.Match. For annotated tags, the
+// returned hash is the hash of the commit the tag points to.
+//
+// An empty remote repository results in an empty list.
+func (g *Client) ListRemote(ctx context.Context, url string, auth *git.AuthOptions, patterns []string) ([]git.Reference, error) {
+	ctx = g.withTrace(ctx, TraceOperationListRemote)
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid ref pattern '%s': %w", pattern, err)
+		}
+	}
+
+	if auth == nil {
+		auth = g.authOpts
+	}
+	if err := g.validateUrlWithAuthOptions(url, auth); err != nil {
+		return nil, err
+	}
+	url, auth, err := g.rewriteRemote(url, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	authMethod, err := g.remoteAuth(ctx, auth)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+
+	remote := extgogit.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemote,
+		URLs: []string{url},
+	})
+	refs, err := remote.ListContext(ctx, &extgogit.ListOptions{
+		Auth:          authMethod,
+		ClientCert:    clientCert(auth),
+		ClientKey:     clientKey(auth),
+		CABundle:      caBundle(auth),
+		PeelingOption: extgogit.AppendPeeled,
+		ProxyOptions:  g.proxyOptions(auth),
+	})
+	if err != nil {
+		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to list remote for '%s': %w", url, err)
+	}
+
+	return matchRefs(refs, patterns), nil
+}
+
+// BranchExists returns whether the given branch exists on the remote at the
+// given URL, without cloning the repository. If auth is nil, the auth options
+// of the client are used.
+func (g *Client) BranchExists(ctx context.Context, url, branch string, auth *git.AuthOptions) (bool, error) {
+	if branch == "" {
+		return false, errors.New("branch name cannot be empty")
+	}
+
+	ref := plumbing.NewBranchReferenceName(branch).String()
+	refs, err := g.ListRemote(ctx, url, auth, []string{ref})
+	if err != nil {
+		return false, err
+	}
+	for _, r := range refs {
+		if r.Name == ref {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchRefs returns the branches and tags of the given refs which match any
+// of the patterns, sorted by name. The hash of peeled tag references replaces
+// the hash of the annotated tag they belong to.
+func matchRefs(refs []*plumbing.Reference, patterns []string) []git.Reference {
+	peeled := make(map[string]string)
+	for _, ref := range refs {
+		if n, ok := strings.CutSuffix(ref.Name().String(), tagDereferenceSuffix); ok {
+			peeled[n] = ref.Hash().String()
+		}
+	}
+
+	var result []git.Reference
+	for _, ref := range refs {
+		name := ref.Name().String()
+		if ref.Type() != plumbing.HashReference || strings.HasSuffix(name, tagDereferenceSuffix) {
+			continue
+		}
+		if !ref.Name().IsBranch() && !ref.Name().IsTag() {
+			continue
+		}
+		if !matchesAnyPattern(ref.Name(), patterns) {
+			continue
+		}
+		hash := ref.Hash().String()
+		if h, ok := peeled[name]; ok {
+			hash = h
+		}
+		result = append(result, git.Reference{
+			Name: name,
+			Hash: git.Hash(hash),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// matchesAnyPattern returns true if there are no patterns, or if the full or
+// short name of the reference matches any of the patterns.
+func matchesAnyPattern(name plumbing.ReferenceName, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name.String()); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, name.Short()); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/gittestserver"
+	"github.com/fluxcd/pkg/ssh"
+)
+
+func TestClient_ListRemote(t *testing.T) {
+	g := NewWithT(t)
+
+	server, err := gittestserver.NewTempGitServer()
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+	g.Expect(server.StartHTTP()).To(Succeed())
+	defer server.StopHTTP()
+
+	repoPath := "test.git"
+	g.Expect(server.InitRepo(testRepositoryPath, git.DefaultBranch, repoPath)).To(Succeed())
+	repoURL := server.HTTPAddress() + "/" + repoPath
+
+	repo, err := extgogit.PlainClone(t.TempDir(), false, &extgogit.CloneOptions{
+		URL: repoURL,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	head, err := repo.Head()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(createBranch(repo, "feature/one")).To(Succeed())
+	featureHash, err := commitFile(repo, "one.txt", "one", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = tag(repo, featureHash, true, "v1.0.0", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = tag(repo, head.Hash(), false, "v0.1.0", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(repo.Push(&extgogit.PushOptions{
+		RefSpecs: []config.RefSpec{
+			"+refs/heads/feature/one:refs/heads/feature/one",
+			"+refs/tags/*:refs/tags/*",
+		},
+	})).To(Succeed())
+
+	// Push a custom reference, which should never be listed.
+	g.Expect(repo.Storer.SetReference(plumbing.NewHashReference("refs/pull/1/head", featureHash))).To(Succeed())
+	g.Expect(repo.Push(&extgogit.PushOptions{
+		RefSpecs: []config.RefSpec{"+refs/pull/1/head:refs/pull/1/head"},
+	})).To(Succeed())
+
+	master := git.Reference{Name: "refs/heads/" + git.DefaultBranch, Hash: git.Hash(head.Hash().String())}
+	feature := git.Reference{Name: "refs/heads/feature/one", Hash: git.Hash(featureHash.String())}
+	annotatedTag := git.Reference{Name: "refs/tags/v1.0.0", Hash: git.Hash(featureHash.String())}
+	lightweightTag := git.Reference{Name: "refs/tags/v0.1.0", Hash: git.Hash(head.Hash().String())}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []git.Reference
+		wantErr  string
+	}{
+		{
+			name: "all branches and tags",
+			want: []git.Reference{feature, master, lightweightTag, annotatedTag},
+		},
+		{
+			name:     "full branch name glob",
+			patterns: []string{"refs/heads/feature/*"},
+			want:     []git.Reference{feature},
+		},
+		{
+			name:     "short tag name glob",
+			patterns: []string{"v1.*"},
+			want:     []git.Reference{annotatedTag},
+		},
+		{
+			name:     "multiple patterns",
+			patterns: []string{git.DefaultBranch, "refs/tags/*"},
+			want:     []git.Reference{master, lightweightTag, annotatedTag},
+		},
+		{
+			name:     "custom references are not matched",
+			patterns: []string{"refs/pull/*/head"},
+			want:     nil,
+		},
+		{
+			name:     "invalid pattern",
+			patterns: []string{"[a-"},
+			wantErr:  "invalid ref pattern '[a-'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ggc, err := NewClient(t.TempDir(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			refs, err := ggc.ListRemote(context.TODO(), repoURL, &git.AuthOptions{Transport: git.HTTP}, tt.patterns)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(refs).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestClient_ListRemote_EmptyRepository(t *testing.T) {
+	g := NewWithT(t)
+
+	_, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ggc, err := NewClient(t.TempDir(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	refs, err := ggc.ListRemote(context.TODO(), path, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(refs).To(BeEmpty())
+}
+
+func TestClient_BranchExists(t *testing.T) {
+	g := NewWithT(t)
+	timeout := 5 * time.Second
+
+	server, err := gittestserver.NewTempGitServer()
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+
+	g.Expect(server.StartHTTP()).To(Succeed())
+	defer server.StopHTTP()
+
+	server.KeyDir(server.Root())
+	g.Expect(server.ListenSSH()).To(Succeed())
+	go func() {
+		server.StartSSH()
+	}()
+	defer server.StopSSH()
+
+	repoPath := "test.git"
+	g.Expect(server.InitRepo(testRepositoryPath, git.DefaultBranch, repoPath)).To(Succeed())
+
+	// Other tests may have configured the global SSH algorithms.
+	hostKeyAlgos, kexAlgos := git.HostKeyAlgos, git.KexAlgos
+	git.HostKeyAlgos, git.KexAlgos = nil, nil
+	defer func() {
+		git.HostKeyAlgos, git.KexAlgos = hostKeyAlgos, kexAlgos
+	}()
+
+	u, err := url.Parse(server.SSHAddress())
+	g.Expect(err).ToNot(HaveOccurred())
+	knownHosts, err := ssh.ScanHostKey(u.Host, timeout, nil, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	kp, err := ssh.GenerateKeyPair(ssh.ED25519)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	transports := []struct {
+		name     string
+		repoURL  string
+		authOpts *git.AuthOptions
+	}{
+		{
+			name:     "HTTP",
+			repoURL:  server.HTTPAddress() + "/" + repoPath,
+			authOpts: &git.AuthOptions{Transport: git.HTTP},
+		},
+		{
+			name:    "SSH",
+			repoURL: server.SSHAddress() + "/" + repoPath,
+			authOpts: &git.AuthOptions{
+				Transport:  git.SSH,
+				Identity:   kp.PrivateKey,
+				KnownHosts: knownHosts,
+			},
+		},
+	}
+
+	for _, tr := range transports {
+		t.Run(tr.name, func(t *testing.T) {
+			tests := []struct {
+				name    string
+				branch  string
+				want    bool
+				wantErr string
+			}{
+				{name: "existing branch", branch: git.DefaultBranch, want: true},
+				{name: "missing branch", branch: "does-not-exist", want: false},
+				{name: "tag name is not a branch", branch: "v0.1.0", want: false},
+				{name: "empty branch name", branch: "", wantErr: "branch name cannot be empty"},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					g := NewWithT(t)
+
+					ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+					defer cancel()
+
+					ggc, err := NewClient(t.TempDir(), tr.authOpts)
+					g.Expect(err).ToNot(HaveOccurred())
+
+					exists, err := ggc.BranchExists(ctx, tr.repoURL, tt.branch, nil)
+					if tt.wantErr != "" {
+						g.Expect(err).To(HaveOccurred())
+						g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+						return
+					}
+					g.Expect(err).ToNot(HaveOccurred())
+					g.Expect(exists).To(Equal(tt.want))
+				})
+			}
+		})
+	}
+}
+
+func Test_matchRefs(t *testing.T) {
+	g := NewWithT(t)
+
+	commitHash := "84d9be20ca15d29bebc629e5b6f29dab78cc69ba"
+	tagHash := "9000be6daa3323cb7009075259bb7bd62498d32f"
+	refs := []*plumbing.Reference{
+		plumbing.NewSymbolicReference(plumbing.HEAD, "refs/heads/main"),
+		plumbing.NewReferenceFromStrings("refs/heads/main", commitHash),
+		plumbing.NewReferenceFromStrings("refs/tags/v1.0.0", tagHash),
+		plumbing.NewReferenceFromStrings("refs/tags/v1.0.0"+tagDereferenceSuffix, commitHash),
+		plumbing.NewReferenceFromStrings("refs/pull/1/head", commitHash),
+	}
+
+	g.Expect(matchRefs(refs, nil)).To(Equal([]git.Reference{
+		{Name: "refs/heads/main", Hash: git.Hash(commitHash)},
+		{Name: "refs/tags/v1.0.0", Hash: git.Hash(commitHash)},
+	}))
+	g.Expect(matchRefs(refs, []string{"refs/tags/*"})).To(Equal([]git.Reference{
+		{Name: "refs/tags/v1.0.0", Hash: git.Hash(commitHash)},
+	}))
+	g.Expect(matchRefs(refs, []string{"feature/*"})).To(BeEmpty())
+}
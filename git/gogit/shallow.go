@@ -0,0 +1,274 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/internal/build"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+// cloneBranchSince clones the given branch with the history bounded to the
+// commits more recent than opts.ShallowSince. As go-git only supports
+// bounding the history by depth, the pack is requested from the upload-pack
+// session with the deepen-since capability, and the repository is set up
+// as a single branch clone would.
+func (g *Client) cloneBranchSince(ctx context.Context, url, branch string, authMethod transport.AuthMethod,
+	opts repository.CloneConfig) (*git.Commit, error) {
+	ref := plumbing.NewBranchReferenceName(branch)
+	ep, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone '%s': %w", url, err)
+	}
+	ep.ClientCert = clientCert(g.authOpts)
+	ep.ClientKey = clientKey(g.authOpts)
+	ep.CaBundle = caBundle(g.authOpts)
+	ep.Proxy = g.proxyOptions(g.authOpts)
+
+	c, err := client.NewClient(ep)
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone '%s': %w", url, err)
+	}
+	session, err := c.NewUploadPackSession(ep, authMethod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to clone '%s': %w", url, err)
+	}
+	defer session.Close()
+
+	ar, err := session.AdvertisedReferencesContext(ctx)
+	if err != nil {
+		if err == transport.ErrRepositoryNotFound {
+			return nil, git.ErrRepositoryNotFound{
+				Message: fmt.Sprintf("unable to clone: %s", err),
+				URL:     url,
+			}
+		}
+		// Mirror the clone of an empty repository by initializing
+		// a new Git repository.
+		if err == transport.ErrEmptyRemoteRepository {
+			if err = os.RemoveAll(g.path); err == nil {
+				if err = g.Init(ctx, url, branch); err == nil {
+					return nil, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("unable to clone '%s': %w", url, err)
+	}
+	if !ar.Capabilities.Supports(capability.DeepenSince) {
+		return nil, git.ErrShallowSinceNotSupported{URL: url}
+	}
+
+	refs, err := ar.AllReferences()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list references of '%s': %w", url, err)
+	}
+	head, ok := refs[ref]
+	if !ok || head.Type() != plumbing.HashReference {
+		return nil, git.ErrRepositoryNotFound{
+			Message: fmt.Sprintf("unable to clone: couldn't find remote ref %q", ref),
+			URL:     url,
+		}
+	}
+
+	req := packp.NewUploadPackRequestFromCapabilities(ar.Capabilities)
+	if err := req.Capabilities.Set(capability.Shallow); err != nil {
+		return nil, err
+	}
+	if err := req.Capabilities.Set(capability.DeepenSince); err != nil {
+		return nil, err
+	}
+	if ar.Capabilities.Supports(capability.NoProgress) {
+		if err := req.Capabilities.Set(capability.NoProgress); err != nil {
+			return nil, err
+		}
+	}
+	req.Wants = []plumbing.Hash{head.Hash()}
+	req.Depth = packp.DepthSince(opts.ShallowSince)
+
+	repo, err := extgogit.Init(g.storer, g.worktreeFS)
+	if err != nil {
+		return nil, fmt.Errorf("unable to init repository: %w", err)
+	}
+	if err := fetchPackSince(ctx, repo, session, req); err != nil {
+		return nil, fmt.Errorf("unable to clone '%s': %w", url, err)
+	}
+
+	if _, err = repo.CreateRemote(&config.RemoteConfig{
+		Name:  git.DefaultRemote,
+		URLs:  []string{url},
+		Fetch: []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", ref, plumbing.NewRemoteReferenceName(git.DefaultRemote, branch)))},
+	}); err != nil {
+		return nil, fmt.Errorf("unable to create remote: %w", err)
+	}
+	if err = repo.CreateBranch(&config.Branch{
+		Name:   branch,
+		Remote: git.DefaultRemote,
+		Merge:  ref,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to create branch '%s': %w", branch, err)
+	}
+	for _, r := range []*plumbing.Reference{
+		plumbing.NewHashReference(ref, head.Hash()),
+		plumbing.NewHashReference(plumbing.NewRemoteReferenceName(git.DefaultRemote, branch), head.Hash()),
+	} {
+		if err = repo.Storer.SetReference(r); err != nil {
+			return nil, fmt.Errorf("unable to set reference '%s': %w", r.Name(), err)
+		}
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repo worktree: %w", err)
+	}
+	err = g.trace(ctx).checkout(func() error {
+		return w.Checkout(&extgogit.CheckoutOptions{
+			Branch:                    ref,
+			Force:                     true,
+			SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to checkout branch '%s': %w", branch, err)
+	}
+
+	cc, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve commit object for HEAD '%s': %w", head.Hash(), err)
+	}
+	g.repository = repo
+	g.sparseCheckoutDirectories = opts.SparseCheckoutDirectories
+	return build.CommitWithRef(cc, nil, ref)
+}
+
+// fetchPackSince requests the pack and stores its objects in the repository,
+// along with the shallow commits reported by the server.
+func fetchPackSince(ctx context.Context, repo *extgogit.Repository, session transport.UploadPackSession,
+	req *packp.UploadPackRequest) (err error) {
+	resp, err := session.UploadPack(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if len(resp.Shallows) > 0 {
+		if err := repo.Storer.SetShallow(resp.Shallows); err != nil {
+			return fmt.Errorf("unable to set shallow commits: %w", err)
+		}
+	}
+
+	var reader io.Reader = resp
+	switch {
+	case req.Capabilities.Supports(capability.Sideband64k):
+		reader = sideband.NewDemuxer(sideband.Sideband64k, resp)
+	case req.Capabilities.Supports(capability.Sideband):
+		reader = sideband.NewDemuxer(sideband.Sideband, resp)
+	}
+	return packfile.UpdateObjectStorage(repo.Storer, reader)
+}
+
+// CommitsSince returns the commits reachable from HEAD which were committed
+// at or after the given time, most recent first. A zero time returns all
+// the commits. The returned list is marked as truncated when the walk
+// reaches a shallow commit, e.g. for a clone with a ShallowSince time or a
+// ShallowClone, as the parents of the commit are not present in the
+// repository.
+func (g *Client) CommitsSince(since time.Time) (*git.CommitList, error) {
+	if g.repository == nil {
+		return nil, git.ErrNoGitRepository
+	}
+
+	head, err := g.repository.Head()
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve HEAD: %w", err)
+	}
+	shallows, err := g.repository.Storer.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve shallow commits: %w", err)
+	}
+	shallow := make(map[plumbing.Hash]struct{}, len(shallows))
+	for _, h := range shallows {
+		shallow[h] = struct{}{}
+	}
+
+	// Walk the parents manually, as the commit iterators of go-git fail
+	// on the missing parents of the shallow commits.
+	list := &git.CommitList{}
+	var commits []*object.Commit
+	seen := map[plumbing.Hash]struct{}{}
+	queue := []plumbing.Hash{head.Hash()}
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+
+		c, err := g.repository.CommitObject(hash)
+		if err != nil {
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				list.Truncated = true
+				continue
+			}
+			return nil, fmt.Errorf("unable to resolve commit '%s': %w", hash, err)
+		}
+		if !since.IsZero() && c.Committer.When.Before(since) {
+			continue
+		}
+		commits = append(commits, c)
+		if _, ok := shallow[hash]; ok {
+			list.Truncated = true
+			continue
+		}
+		queue = append(queue, c.ParentHashes...)
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Committer.When.After(commits[j].Committer.When)
+	})
+	for _, c := range commits {
+		cc, err := build.CommitWithRef(c, nil, head.Name())
+		if err != nil {
+			return nil, err
+		}
+		list.Commits = append(list.Commits, cc)
+	}
+	return list, nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestClone_cloneBranchSince(t *testing.T) {
+	repo, repoPath, err := initRepo(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	oldCommit, err := commitFile(repo, "old", "old", now.Add(-72*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	midCommit, err := commitFile(repo, "mid", "mid", now.Add(-48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCommit, err := commitFile(repo, "new", "new", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		branch        string
+		since         time.Time
+		wantCommits   []string
+		wantTruncated bool
+		wantFiles     []string
+		wantErr       string
+	}{
+		{
+			name:          "history bounded by time",
+			branch:        "master",
+			since:         now.Add(-50 * time.Hour),
+			wantCommits:   []string{newCommit.String(), midCommit.String()},
+			wantTruncated: true,
+			wantFiles:     []string{"old", "mid", "new"},
+		},
+		{
+			name:        "time before the first commit",
+			branch:      "master",
+			since:       now.Add(-96 * time.Hour),
+			wantCommits: []string{newCommit.String(), midCommit.String(), oldCommit.String()},
+			wantFiles:   []string{"old", "mid", "new"},
+		},
+		{
+			name:    "non existing branch",
+			branch:  "invalid",
+			since:   now.Add(-50 * time.Hour),
+			wantErr: "couldn't find remote ref \"refs/heads/invalid\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tmpDir := t.TempDir()
+			ggc, err := NewClient(tmpDir, &git.AuthOptions{Transport: git.HTTP})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cc, err := ggc.Clone(context.TODO(), repoPath, repository.CloneConfig{
+				CheckoutStrategy: repository.CheckoutStrategy{
+					Branch: tt.branch,
+				},
+				ShallowSince: tt.since,
+			})
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				var notFoundErr git.ErrRepositoryNotFound
+				g.Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cc.String()).To(Equal(tt.branch + "@" + git.HashTypeSHA1 + ":" + newCommit.String()))
+			g.Expect(git.IsConcreteCommit(*cc)).To(BeTrue())
+			for _, f := range tt.wantFiles {
+				g.Expect(os.ReadFile(filepath.Join(tmpDir, f))).To(BeEquivalentTo(f))
+			}
+
+			list, err := ggc.CommitsSince(time.Time{})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(list.Truncated).To(Equal(tt.wantTruncated))
+			g.Expect(list.Commits).To(HaveLen(len(tt.wantCommits)))
+			for i, want := range tt.wantCommits {
+				g.Expect(list.Commits[i].Hash.String()).To(Equal(want))
+				g.Expect(list.Commits[i].Reference).To(Equal("refs/heads/" + tt.branch))
+			}
+
+			// The walk stops at the commits older than the given time.
+			list, err = ggc.CommitsSince(now.Add(-2 * time.Hour))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(list.Truncated).To(BeFalse())
+			g.Expect(list.Commits).To(HaveLen(1))
+			g.Expect(list.Commits[0].Hash.String()).To(Equal(newCommit.String()))
+
+			// The clone can be updated with the single branch refspec.
+			remote, err := ggc.repository.Remote(git.DefaultRemote)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(remote.Config().Fetch).To(HaveLen(1))
+			g.Expect(remote.Config().Fetch[0].String()).To(Equal("+refs/heads/" + tt.branch + ":refs/remotes/origin/" + tt.branch))
+		})
+	}
+}
+
+func TestClone_cloneBranchSince_NotSupported(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, _, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = commitFile(repo, "file", "content", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The in-process go-git server does not support deepen-since. As the
+	// cache scheme is not a valid URL for Clone, clone from it directly.
+	url, unregister, err := cacheEntries.register(repo.Storer)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer unregister()
+
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.clone(context.TODO(), url, repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"},
+		ShallowSince:     time.Now().Add(-time.Hour),
+	})
+	var notSupportedErr git.ErrShallowSinceNotSupported
+	g.Expect(errors.As(err, &notSupportedErr)).To(BeTrue())
+	g.Expect(notSupportedErr.URL).To(Equal(url))
+
+	// Callers can fall back to a clone without the time bound.
+	cc, err := ggc.clone(context.TODO(), url, repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(git.IsConcreteCommit(*cc)).To(BeTrue())
+}
+
+func TestClone_ShallowSinceUnsupportedOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     repository.CloneConfig
+		wantErr string
+	}{
+		{
+			name: "tag checkout",
+			cfg: repository.CloneConfig{
+				CheckoutStrategy: repository.CheckoutStrategy{Tag: "v1.0.0"},
+			},
+			wantErr: "shallow clones by time are only supported with a branch checkout",
+		},
+		{
+			name: "submodules",
+			cfg: repository.CloneConfig{
+				CheckoutStrategy:  repository.CheckoutStrategy{Branch: "main"},
+				RecurseSubmodules: true,
+			},
+			wantErr: "shallow clones by time are not supported with submodules",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+			g.Expect(err).ToNot(HaveOccurred())
+			tt.cfg.ShallowSince = time.Now()
+			_, err = ggc.Clone(context.TODO(), "https://example.com/org/repo", tt.cfg)
+			g.Expect(err).To(MatchError(tt.wantErr))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	gohttp "net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// pinnedAuth wraps the http.AuthMethod of an HTTPS operation with the
+// SANs and SPKI hashes the server certificate is pinned to.
+type pinnedAuth struct {
+	auth        http.AuthMethod
+	sans        []string
+	spkiHashes  []string
+	fingerprint string
+}
+
+// newPinnedAuth returns a pinnedAuth for the given auth method and the pins
+// of the git.AuthOptions. The auth method may be nil.
+func newPinnedAuth(auth http.AuthMethod, opts *git.AuthOptions) *pinnedAuth {
+	sans := append([]string(nil), opts.PinnedSANs...)
+	hashes := append([]string(nil), opts.PinnedSPKIHashes...)
+	sort.Strings(sans)
+	sort.Strings(hashes)
+	return &pinnedAuth{
+		auth:        auth,
+		sans:        sans,
+		spkiHashes:  hashes,
+		fingerprint: strings.Join(sans, ",") + "|" + strings.Join(hashes, ","),
+	}
+}
+
+// SetAuth implements http.AuthMethod.
+func (a *pinnedAuth) SetAuth(r *gohttp.Request) {
+	if a.auth != nil {
+		a.auth.SetAuth(r)
+	}
+}
+
+// Name implements transport.AuthMethod.
+func (a *pinnedAuth) Name() string {
+	if a.auth != nil {
+		return a.auth.Name()
+	}
+	return "http-pinned"
+}
+
+// String implements transport.AuthMethod.
+func (a *pinnedAuth) String() string {
+	if a.auth != nil {
+		return a.auth.String()
+	}
+	return fmt.Sprintf("%s - pinned SANs: %v", a.Name(), a.sans)
+}
+
+// unwrap returns the wrapped auth method as a transport.AuthMethod,
+// or an untyped nil when there is none.
+func (a *pinnedAuth) unwrap() transport.AuthMethod {
+	if a.auth == nil {
+		return nil
+	}
+	return a.auth
+}
+
+// checkPinnedTransport returns an error if the HTTPS protocol of go-git
+// does not delegate the sessions of the clients to the shared transport,
+// e.g. when it was replaced with client.InstallProtocol, as the pins of
+// the server identity would not be verified.
+func checkPinnedTransport() error {
+	if _, ok := client.Protocols["https"].(*protocolTransport); !ok {
+		return errors.New("pinned TLS requires the HTTPS protocol of go-git to be the one installed by the gogit clients")
+	}
+	return nil
+}
+
+// verifyPinnedPeer returns a tls.Config.VerifyPeerCertificate function which
+// requires the leaf certificate presented by the server to match at least
+// one of the given SANs, if any, and one of the given SPKI hashes, if any.
+// The check runs after the standard verification against the root CAs.
+func verifyPinnedPeer(sans, spkiHashes []string) (func([][]byte, [][]*x509.Certificate) error, error) {
+	hashes := make([][]byte, 0, len(spkiHashes))
+	for _, h := range spkiHashes {
+		b, err := git.ParseSPKIHash(h)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, b)
+	}
+
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("server did not present a certificate")
+		}
+		leaf, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return fmt.Errorf("failed to parse server certificate: %w", err)
+		}
+
+		if len(sans) > 0 {
+			presented := certificateSANs(leaf)
+			if !containsAny(presented, sans) {
+				return fmt.Errorf("server certificate does not match pinned SANs %v: presented SANs %v", sans, presented)
+			}
+		}
+		if len(hashes) > 0 {
+			sum := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+			matched := false
+			for _, h := range hashes {
+				if bytes.Equal(sum[:], h) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fmt.Errorf("server certificate public key does not match pinned SPKI hashes: presented SANs %v", certificateSANs(leaf))
+			}
+		}
+		return nil
+	}, nil
+}
+
+// certificateSANs returns the DNS, IP and URI Subject Alternative Names
+// of the given certificate.
+func certificateSANs(cert *x509.Certificate) []string {
+	res := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		res = append(res, ip.String())
+	}
+	for _, u := range cert.URIs {
+		res = append(res, u.String())
+	}
+	return res
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, v := range values {
+		for _, w := range wanted {
+			if v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/gittestserver"
+)
+
+const testSPIFFEID = "spiffe://cluster.local/ns/flux-system/sa/git-server"
+
+// testCertificates holds a CA and a server certificate signed by it,
+// in PEM format.
+type testCertificates struct {
+	caPEM   []byte
+	certPEM []byte
+	keyPEM  []byte
+	cert    *x509.Certificate
+}
+
+func newTestCertificates(t *testing.T) testCertificates {
+	t.Helper()
+	g := NewWithT(t)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	ca, err := x509.ParseCertificate(caDER)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	spiffeID, err := url.Parse(testSPIFFEID)
+	g.Expect(err).ToNot(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "git-server"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		URIs:         []*url.URL{spiffeID},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	g.Expect(err).ToNot(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	return testCertificates{
+		caPEM:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		cert:    cert,
+	}
+}
+
+func spkiHash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestClient_ListRemote_PinnedTLS(t *testing.T) {
+	certs := newTestCertificates(t)
+	otherCerts := newTestCertificates(t)
+
+	server, err := gittestserver.NewTempGitServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(server.Root())
+	if err := server.StartHTTPS(certs.certPEM, certs.keyPEM, certs.caPEM, "localhost"); err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopHTTP()
+
+	repoPath := "test.git"
+	if err := server.InitRepo(testRepositoryPath, git.DefaultBranch, repoPath); err != nil {
+		t.Fatal(err)
+	}
+	repoURL := server.HTTPAddress() + "/" + repoPath
+
+	tests := []struct {
+		name       string
+		sans       []string
+		spkiHashes []string
+		wantErr    []string
+	}{
+		{
+			name: "matching SPIFFE ID",
+			sans: []string{testSPIFFEID},
+		},
+		{
+			name: "matching IP address",
+			sans: []string{"10.0.0.1", "127.0.0.1"},
+		},
+		{
+			name:       "matching SPKI hash",
+			spkiHashes: []string{"sha256/" + spkiHash(certs.cert)},
+		},
+		{
+			name:       "matching SAN and SPKI hash",
+			sans:       []string{testSPIFFEID},
+			spkiHashes: []string{spkiHash(otherCerts.cert), spkiHash(certs.cert)},
+		},
+		{
+			name: "mismatched SANs",
+			sans: []string{"spiffe://cluster.local/ns/default/sa/other", "git.example.com"},
+			wantErr: []string{
+				"does not match pinned SANs",
+				"presented SANs [localhost 127.0.0.1 " + testSPIFFEID + "]",
+			},
+		},
+		{
+			name:       "mismatched SPKI hash",
+			spkiHashes: []string{spkiHash(otherCerts.cert)},
+			wantErr: []string{
+				"does not match pinned SPKI hashes",
+				"presented SANs [localhost 127.0.0.1 " + testSPIFFEID + "]",
+			},
+		},
+		{
+			name:       "matching SAN with mismatched SPKI hash",
+			sans:       []string{testSPIFFEID},
+			spkiHashes: []string{spkiHash(otherCerts.cert)},
+			wantErr:    []string{"does not match pinned SPKI hashes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ggc, err := NewClient(t.TempDir(), &git.AuthOptions{
+				Transport:        git.HTTPS,
+				CAFile:           certs.caPEM,
+				PinnedSANs:       tt.sans,
+				PinnedSPKIHashes: tt.spkiHashes,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			refs, err := ggc.ListRemote(context.TODO(), repoURL, nil, nil)
+			if len(tt.wantErr) > 0 {
+				g.Expect(err).To(HaveOccurred())
+				for _, want := range tt.wantErr {
+					g.Expect(err.Error()).To(ContainSubstring(want))
+				}
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(refs).ToNot(BeEmpty())
+		})
+	}
+
+	t.Run("pins require the protocol of the clients", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{
+			Transport:  git.HTTPS,
+			CAFile:     certs.caPEM,
+			PinnedSANs: []string{"git.example.com"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		installed := client.Protocols["https"]
+		client.InstallProtocol("https", githttp.DefaultClient)
+		defer client.InstallProtocol("https", installed)
+
+		_, err = ggc.ListRemote(context.TODO(), repoURL, nil, nil)
+		g.Expect(err).To(MatchError(ContainSubstring("pinned TLS requires the HTTPS protocol")))
+	})
+
+	t.Run("pins do not replace the CA verification", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{
+			Transport:  git.HTTPS,
+			CAFile:     otherCerts.caPEM,
+			PinnedSANs: []string{testSPIFFEID},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = ggc.ListRemote(context.TODO(), repoURL, nil, nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("certificate signed by unknown authority"))
+	})
+}
+
+func Test_verifyPinnedPeer(t *testing.T) {
+	certs := newTestCertificates(t)
+	rawCerts := [][]byte{certs.cert.Raw}
+
+	tests := []struct {
+		name       string
+		sans       []string
+		spkiHashes []string
+		rawCerts   [][]byte
+		wantErr    string
+	}{
+		{
+			name:     "matching DNS name",
+			sans:     []string{"localhost"},
+			rawCerts: rawCerts,
+		},
+		{
+			name:     "matching URI",
+			sans:     []string{testSPIFFEID},
+			rawCerts: rawCerts,
+		},
+		{
+			name:     "mismatched SANs",
+			sans:     []string{"example.com"},
+			rawCerts: rawCerts,
+			wantErr:  "server certificate does not match pinned SANs [example.com]: presented SANs [localhost 127.0.0.1 " + testSPIFFEID + "]",
+		},
+		{
+			name:       "matching SPKI hash",
+			spkiHashes: []string{spkiHash(certs.cert)},
+			rawCerts:   rawCerts,
+		},
+		{
+			name:     "no certificate",
+			sans:     []string{"localhost"},
+			rawCerts: nil,
+			wantErr:  "server did not present a certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			verify, err := verifyPinnedPeer(tt.sans, tt.spkiHashes)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = verify(tt.rawCerts, nil)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+
+	t.Run("invalid SPKI hash", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := verifyPinnedPeer(nil, []string{"not-a-hash"})
+		g.Expect(err).To(MatchError(ContainSubstring("must be a base64 encoded SHA-256 hash")))
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	gohttp "net/http"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// trustMode describes the root CAs the HTTPS server certificates are
+// verified against, and is stated in the verification errors.
+type trustMode string
+
+const (
+	// trustSystemRoots verifies the server certificates against the
+	// system roots, when no CA bundle is provided.
+	trustSystemRoots trustMode = "system roots"
+	// trustSystemRootsAndCA verifies the server certificates against the
+	// system roots and the provided CA bundle.
+	trustSystemRootsAndCA trustMode = "system roots and CA bundle"
+	// trustExclusiveCA verifies the server certificates against the
+	// provided CA bundle only.
+	trustExclusiveCA trustMode = "CA bundle only"
+)
+
+// systemCertPool returns the system roots, and is replaced in tests.
+var systemCertPool = x509.SystemCertPool
+
+// WithoutSystemRoots disables the system roots for the HTTPS remote
+// operations of the client, the server certificates being verified
+// against the CA bundle of the auth options only, which is then required.
+// It is meant for hardened controllers trusting a private CA only.
+func WithoutSystemRoots() ClientOption {
+	return func(c *Client) error {
+		c.disableSystemRoots = true
+		return nil
+	}
+}
+
+// tlsTrustMode returns the trustMode of the HTTPS remote operations with
+// the given git.AuthOptions, or an error if the CA bundle required by the
+// exclusive trust of a CA is missing.
+func (g *Client) tlsTrustMode(opts *git.AuthOptions) (trustMode, error) {
+	hasCA := opts != nil && len(opts.CAFile) > 0
+	exclusive := g.disableSystemRoots || (opts != nil && opts.ExclusiveCA)
+	switch {
+	case !exclusive && hasCA:
+		return trustSystemRootsAndCA, nil
+	case !exclusive:
+		return trustSystemRoots, nil
+	case !hasCA && g.disableSystemRoots:
+		return "", errors.New("a CA bundle is required for HTTPS remotes when the system roots are disabled")
+	case !hasCA:
+		return "", errors.New("a CA bundle is required for HTTPS remotes with ExclusiveCA")
+	default:
+		return trustExclusiveCA, nil
+	}
+}
+
+// defaultTrustMode returns the trustMode of the sessions opened without a
+// pooledAuth, for the given CA bundle.
+func defaultTrustMode(caBundle []byte) trustMode {
+	if len(caBundle) > 0 {
+		return trustSystemRootsAndCA
+	}
+	return trustSystemRoots
+}
+
+// rootCAs returns the root CAs of the given trust mode and CA bundle, or
+// nil for the system roots used by default by the TLS client.
+func rootCAs(mode trustMode, caBundle []byte) (*x509.CertPool, error) {
+	switch mode {
+	case trustExclusiveCA:
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, errors.New("no certificate found in the CA bundle")
+		}
+		return pool, nil
+	case trustSystemRootsAndCA:
+		pool, err := systemCertPool()
+		if err != nil {
+			return nil, err
+		}
+		if pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(caBundle)
+		return pool, nil
+	default:
+		return nil, nil
+	}
+}
+
+// trustTransport is an http.RoundTripper stating the trust mode of the
+// TLS configuration in the verification errors of the server certificates.
+type trustTransport struct {
+	next gohttp.RoundTripper
+	mode trustMode
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *trustTransport) RoundTrip(req *gohttp.Request) (*gohttp.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	var verifyErr *tls.CertificateVerificationError
+	if err != nil && errors.As(err, &verifyErr) {
+		return resp, fmt.Errorf("%w (trust mode: %s)", err, t.mode)
+	}
+	return resp, err
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/gittestserver"
+)
+
+// startHTTPSTestServer starts a git server with a test repository, serving
+// the given certificates, and returns the URL of the repository.
+func startHTTPSTestServer(t *testing.T, certs testCertificates) string {
+	t.Helper()
+	g := NewWithT(t)
+
+	server, err := gittestserver.NewTempGitServer()
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(func() { os.RemoveAll(server.Root()) })
+	g.Expect(server.StartHTTPS(certs.certPEM, certs.keyPEM, certs.caPEM, "localhost")).To(Succeed())
+	t.Cleanup(server.StopHTTP)
+
+	repoPath := "test.git"
+	g.Expect(server.InitRepo(testRepositoryPath, git.DefaultBranch, repoPath)).To(Succeed())
+	return server.HTTPAddress() + "/" + repoPath
+}
+
+func TestClient_ListRemote_TrustMode(t *testing.T) {
+	systemCerts := newTestCertificates(t)
+	privateCerts := newTestCertificates(t)
+	otherCerts := newTestCertificates(t)
+
+	// The CA of the public server stands for a CA of the system roots.
+	defaultSystemCertPool := systemCertPool
+	t.Cleanup(func() { systemCertPool = defaultSystemCertPool })
+	systemCertPool = func() (*x509.CertPool, error) {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(systemCerts.caPEM)
+		return pool, nil
+	}
+
+	publicURL := startHTTPSTestServer(t, systemCerts)
+	privateURL := startHTTPSTestServer(t, privateCerts)
+
+	tests := []struct {
+		name       string
+		caFile     []byte
+		exclusive  bool
+		clientOpts []ClientOption
+		publicErr  string
+		privateErr string
+		err        string
+	}{
+		{
+			name:   "system roots and CA bundle",
+			caFile: privateCerts.caPEM,
+		},
+		{
+			name:       "system roots and unrelated CA bundle",
+			caFile:     otherCerts.caPEM,
+			privateErr: "(trust mode: system roots and CA bundle)",
+		},
+		{
+			name:      "exclusive CA",
+			caFile:    privateCerts.caPEM,
+			exclusive: true,
+			publicErr: "(trust mode: CA bundle only)",
+		},
+		{
+			name:       "exclusive unrelated CA",
+			caFile:     otherCerts.caPEM,
+			exclusive:  true,
+			publicErr:  "(trust mode: CA bundle only)",
+			privateErr: "(trust mode: CA bundle only)",
+		},
+		{
+			name:       "system roots disabled",
+			caFile:     privateCerts.caPEM,
+			clientOpts: []ClientOption{WithoutSystemRoots()},
+			publicErr:  "(trust mode: CA bundle only)",
+		},
+		{
+			name:       "system roots disabled without CA bundle",
+			clientOpts: []ClientOption{WithoutSystemRoots()},
+			err:        "a CA bundle is required for HTTPS remotes when the system roots are disabled",
+		},
+		{
+			name:      "exclusive CA without certificate",
+			caFile:    []byte("not a certificate"),
+			exclusive: true,
+			err:       "no certificate found in the CA bundle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, remote := range []struct {
+				url string
+				err string
+			}{
+				{url: publicURL, err: tt.publicErr},
+				{url: privateURL, err: tt.privateErr},
+			} {
+				g := NewWithT(t)
+
+				opts := append([]ClientOption{WithDiskStorage()}, tt.clientOpts...)
+				ggc, err := NewClient(t.TempDir(), &git.AuthOptions{
+					Transport:   git.HTTPS,
+					CAFile:      tt.caFile,
+					ExclusiveCA: tt.exclusive,
+				}, opts...)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				refs, err := ggc.ListRemote(context.TODO(), remote.url, nil, nil)
+				wantErr := tt.err
+				if wantErr == "" {
+					wantErr = remote.err
+				}
+				if wantErr != "" {
+					g.Expect(err).To(HaveOccurred())
+					g.Expect(err.Error()).To(ContainSubstring(wantErr))
+					continue
+				}
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(refs).ToNot(BeEmpty())
+			}
+		})
+	}
+}
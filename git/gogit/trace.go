@@ -0,0 +1,347 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	gohttp "net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// TraceOperation is a remote operation of the client traced by the
+// TraceHooks.
+type TraceOperation string
+
+const (
+	// TraceOperationClone is the Clone operation, including the listing of
+	// the remote HEAD when a last observed commit is set.
+	TraceOperationClone TraceOperation = "Clone"
+	// TraceOperationPush is the Push operation.
+	TraceOperationPush TraceOperation = "Push"
+	// TraceOperationListRemote is the ListRemote operation.
+	TraceOperationListRemote TraceOperation = "ListRemote"
+)
+
+// TracePhase is a phase of a remote operation traced by the TraceHooks.
+type TracePhase string
+
+const (
+	// TracePhaseConnect is the TCP connection to an HTTP(S) remote, or to
+	// its proxy. It is not traced for the connections reused from the pool.
+	TracePhaseConnect TracePhase = "Connect"
+	// TracePhaseTLSHandshake is the TLS handshake with an HTTPS remote.
+	TracePhaseTLSHandshake TracePhase = "TLSHandshake"
+	// TracePhaseSSHHandshake is the connection to an SSH remote until its
+	// host key is verified, which requires known hosts to be configured.
+	TracePhaseSSHHandshake TracePhase = "SSHHandshake"
+	// TracePhaseRefAdvertisement is the request of the references
+	// advertised by an HTTP(S) remote.
+	TracePhaseRefAdvertisement TracePhase = "RefAdvertisement"
+	// TracePhasePackNegotiation is the upload-pack request to an HTTP(S)
+	// remote, negotiating the objects to fetch and downloading their pack.
+	TracePhasePackNegotiation TracePhase = "PackNegotiation"
+	// TracePhasePackUpload is the receive-pack request to an HTTP(S)
+	// remote, uploading the pack of the objects to push. It ends with the
+	// response of the remote, whose report status is not counted.
+	TracePhasePackUpload TracePhase = "PackUpload"
+	// TracePhaseCheckout is the checkout of the worktree of a clone. When
+	// go-git checks out the worktree at the end of the clone, it is the time
+	// elapsed since the end of the pack negotiation.
+	TracePhaseCheckout TracePhase = "Checkout"
+)
+
+// TraceEvent is a phase of a remote operation.
+type TraceEvent struct {
+	// Operation is the traced operation.
+	Operation TraceOperation
+	// Phase is the phase of the operation.
+	Phase TracePhase
+	// Start is the time the phase started at.
+	Start time.Time
+	// Duration is the duration of the phase.
+	Duration time.Duration
+	// BytesSent is the number of bytes of the requests of the phase.
+	BytesSent int64
+	// BytesReceived is the number of bytes of the responses of the phase.
+	BytesReceived int64
+	// Err is the error the phase failed with, if any.
+	Err error
+}
+
+// TraceHooks are the callbacks of the phases of the remote operations of
+// the client, e.g. to find out whether a slow clone spends its time in the
+// TLS handshake or in the pack negotiation. The callbacks are called
+// synchronously by the operations, possibly from several goroutines, and
+// must not block.
+type TraceHooks struct {
+	// PhaseStarted is called when a phase starts, if set.
+	PhaseStarted func(op TraceOperation, phase TracePhase, start time.Time)
+	// PhaseDone is called when a phase ends, if set.
+	PhaseDone func(event TraceEvent)
+}
+
+// WithTraceHooks configures the client to call the given hooks in the
+// phases of its remote operations. Without hooks, which is the default, the
+// operations are not instrumented.
+func WithTraceHooks(hooks TraceHooks) ClientOption {
+	return func(c *Client) error {
+		c.traceHooks = &hooks
+		return nil
+	}
+}
+
+// operationTrace calls the hooks of the client in the phases of a remote
+// operation. A nil *operationTrace traces nothing.
+type operationTrace struct {
+	hooks *TraceHooks
+	op    TraceOperation
+
+	mu        sync.Mutex
+	connects  map[string]time.Time
+	tlsStart  time.Time
+	lastFetch time.Time
+}
+
+type operationTraceKey struct{}
+
+// withTrace returns a copy of the context holding the trace of the given
+// operation, or the context itself if the client has no trace hooks or the
+// context already holds a trace.
+func (g *Client) withTrace(ctx context.Context, op TraceOperation) context.Context {
+	if g.traceHooks == nil || traceFrom(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, operationTraceKey{}, &operationTrace{
+		hooks:    g.traceHooks,
+		op:       op,
+		connects: make(map[string]time.Time),
+	})
+}
+
+// trace returns the trace of the operation of the context, or nil if the
+// client has no trace hooks.
+func (g *Client) trace(ctx context.Context) *operationTrace {
+	if g.traceHooks == nil {
+		return nil
+	}
+	return traceFrom(ctx)
+}
+
+// traceFrom returns the trace held by the context, if any.
+func traceFrom(ctx context.Context) *operationTrace {
+	t, _ := ctx.Value(operationTraceKey{}).(*operationTrace)
+	return t
+}
+
+// start calls the PhaseStarted hook, and returns the start of the phase.
+func (t *operationTrace) start(phase TracePhase) time.Time {
+	now := time.Now()
+	if t != nil && t.hooks.PhaseStarted != nil {
+		t.hooks.PhaseStarted(t.op, phase, now)
+	}
+	return now
+}
+
+// done calls the PhaseDone hook with the phase started at the given time.
+func (t *operationTrace) done(phase TracePhase, start time.Time, sent, received int64, err error) {
+	if t == nil {
+		return
+	}
+	end := time.Now()
+	if phase == TracePhasePackNegotiation {
+		t.mu.Lock()
+		t.lastFetch = end
+		t.mu.Unlock()
+	}
+	if t.hooks.PhaseDone != nil {
+		t.hooks.PhaseDone(TraceEvent{
+			Operation:     t.op,
+			Phase:         phase,
+			Start:         start,
+			Duration:      end.Sub(start),
+			BytesSent:     sent,
+			BytesReceived: received,
+			Err:           err,
+		})
+	}
+}
+
+// checkout traces the given checkout of the worktree.
+func (t *operationTrace) checkout(checkout func() error) error {
+	if t == nil {
+		return checkout()
+	}
+	start := t.start(TracePhaseCheckout)
+	err := checkout()
+	t.done(TracePhaseCheckout, start, 0, 0, err)
+	return err
+}
+
+// clonedWorktree traces the checkout of the worktree done by go-git at the
+// end of a clone, i.e. since the end of the last pack negotiation.
+func (t *operationTrace) clonedWorktree() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	start := t.lastFetch
+	t.lastFetch = time.Time{}
+	t.mu.Unlock()
+	if start.IsZero() {
+		return
+	}
+	if t.hooks.PhaseStarted != nil {
+		t.hooks.PhaseStarted(t.op, TracePhaseCheckout, start)
+	}
+	t.done(TracePhaseCheckout, start, 0, 0, nil)
+}
+
+// clientTrace returns the httptrace.ClientTrace of the connections of the
+// requests of the operation.
+func (t *operationTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		ConnectStart: func(network, addr string) {
+			start := t.start(TracePhaseConnect)
+			t.mu.Lock()
+			t.connects[network+"/"+addr] = start
+			t.mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			start, ok := t.connects[network+"/"+addr]
+			delete(t.connects, network+"/"+addr)
+			t.mu.Unlock()
+			if ok {
+				t.done(TracePhaseConnect, start, 0, 0, err)
+			}
+		},
+		TLSHandshakeStart: func() {
+			start := t.start(TracePhaseTLSHandshake)
+			t.mu.Lock()
+			t.tlsStart = start
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			t.mu.Lock()
+			start := t.tlsStart
+			t.mu.Unlock()
+			t.done(TracePhaseTLSHandshake, start, 0, 0, err)
+		},
+	}
+}
+
+// hostKeyCallback returns the callback tracing the SSH handshake started
+// at the given time until the host key is verified by the given callback.
+func (t *operationTrace) hostKeyCallback(start time.Time, callback gossh.HostKeyCallback) gossh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		t.done(TracePhaseSSHHandshake, start, 0, 0, err)
+		return err
+	}
+}
+
+// traceTransport traces the requests of the operations of the HTTP(S)
+// remotes, and their connections. It is only set on the HTTP clients of
+// the clients with trace hooks.
+type traceTransport struct {
+	next gohttp.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *traceTransport) RoundTrip(req *gohttp.Request) (*gohttp.Response, error) {
+	trace := traceFrom(req.Context())
+	if trace == nil {
+		return t.next.RoundTrip(req)
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+	var phase TracePhase
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/info/refs"):
+		phase = TracePhaseRefAdvertisement
+	case strings.HasSuffix(req.URL.Path, "/git-upload-pack"):
+		phase = TracePhasePackNegotiation
+	case strings.HasSuffix(req.URL.Path, "/git-receive-pack"):
+		phase = TracePhasePackUpload
+	default:
+		return t.next.RoundTrip(req)
+	}
+
+	start := trace.start(phase)
+	var sent *countingReadCloser
+	if req.Body != nil {
+		sent = &countingReadCloser{ReadCloser: req.Body}
+		req.Body = sent
+	}
+	resp, err := t.next.RoundTrip(req)
+	// go-git does not close the responses of the receive-pack requests, so
+	// the upload ends with the response, without its report status.
+	if err != nil || phase == TracePhasePackUpload {
+		trace.done(phase, start, sent.bytes(), 0, err)
+		return resp, err
+	}
+	resp.Body = &tracedBody{
+		countingReadCloser: countingReadCloser{ReadCloser: resp.Body},
+		done: func(received int64) {
+			trace.done(phase, start, sent.bytes(), received, nil)
+		},
+	}
+	return resp, nil
+}
+
+// countingReadCloser counts the bytes read from an io.ReadCloser.
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+// Read implements io.Reader.
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// bytes returns the number of bytes read, zero for a nil reader.
+func (r *countingReadCloser) bytes() int64 {
+	if r == nil {
+		return 0
+	}
+	return r.n
+}
+
+// tracedBody is a response body ending the phase of its request when
+// closed.
+type tracedBody struct {
+	countingReadCloser
+	once sync.Once
+	done func(received int64)
+}
+
+// Close implements io.Closer.
+func (b *tracedBody) Close() error {
+	err := b.countingReadCloser.Close()
+	b.once.Do(func() { b.done(b.n) })
+	return err
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+// traceRecorder records the events of the trace hooks.
+type traceRecorder struct {
+	mu      sync.Mutex
+	started []TracePhase
+	events  []TraceEvent
+}
+
+func (r *traceRecorder) hooks() TraceHooks {
+	return TraceHooks{
+		PhaseStarted: func(_ TraceOperation, phase TracePhase, _ time.Time) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.started = append(r.started, phase)
+		},
+		PhaseDone: func(event TraceEvent) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.events = append(r.events, event)
+		},
+	}
+}
+
+// phases returns the phases of the recorded events of the given operation.
+func (r *traceRecorder) phases(op TraceOperation) []TracePhase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var phases []TracePhase
+	for _, event := range r.events {
+		if event.Operation == op {
+			phases = append(phases, event.Phase)
+		}
+	}
+	return phases
+}
+
+func TestClient_TraceHooks(t *testing.T) {
+	g := NewWithT(t)
+
+	server := newCacheTestServer(t, "traced.git")
+	repoURL := server.HTTPAddress() + "/traced.git"
+
+	recorder := &traceRecorder{}
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP},
+		WithDiskStorage(), WithTraceHooks(recorder.hooks()))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = ggc.Clone(context.TODO(), repoURL, repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(recorder.phases(TraceOperationClone)).To(Equal([]TracePhase{
+		TracePhaseConnect,
+		TracePhaseRefAdvertisement,
+		TracePhasePackNegotiation,
+		TracePhaseCheckout,
+	}))
+
+	var lastEnd time.Time
+	for _, event := range recorder.events {
+		g.Expect(event.Err).ToNot(HaveOccurred())
+		g.Expect(event.Duration).To(BeNumerically(">=", 0))
+		end := event.Start.Add(event.Duration)
+		g.Expect(end).To(BeTemporally(">=", lastEnd), string(event.Phase))
+		lastEnd = end
+
+		switch event.Phase {
+		case TracePhaseRefAdvertisement:
+			g.Expect(event.BytesReceived).To(BeNumerically(">", 0))
+		case TracePhasePackNegotiation:
+			g.Expect(event.BytesSent).To(BeNumerically(">", 0))
+			g.Expect(event.BytesReceived).To(BeNumerically(">", 0))
+		}
+	}
+	// Each phase ending has started.
+	g.Expect(recorder.started).To(HaveLen(len(recorder.events)))
+
+	_, err = commitFile(ggc.repository, "bar.txt", "new", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ggc.Push(context.TODO(), repository.PushConfig{})).To(Succeed())
+	g.Expect(recorder.phases(TraceOperationPush)).To(ContainElements(
+		TracePhaseRefAdvertisement, TracePhasePackUpload))
+
+	_, err = ggc.ListRemote(context.TODO(), repoURL, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(recorder.phases(TraceOperationListRemote)).To(ContainElement(TracePhaseRefAdvertisement))
+}
+
+func TestClient_WithoutTraceHooks(t *testing.T) {
+	g := NewWithT(t)
+
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx := ggc.withTrace(context.TODO(), TraceOperationClone)
+	g.Expect(traceFrom(ctx)).To(BeNil())
+	g.Expect(ggc.trace(ctx)).To(BeNil())
+
+	// A nil trace traces nothing.
+	var trace *operationTrace
+	g.Expect(trace.checkout(func() error { return nil })).To(Succeed())
+	trace.clonedWorktree()
+	trace.done(TracePhaseConnect, trace.start(TracePhaseConnect), 0, 0, nil)
+}
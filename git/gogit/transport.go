@@ -36,19 +36,34 @@ func transportAuth(opts *git.AuthOptions, fallbackToDefaultKnownHosts bool) (tra
 	}
 	switch opts.Transport {
 	case git.HTTPS, git.HTTP:
+		var auth http.AuthMethod
 		// Some providers (i.e. GitLab) will reject empty credentials for
 		// public repositories.
-		if opts.Username != "" || opts.Password != "" {
-			return &http.BasicAuth{
+		if opts.ProxyAuthPolicy == git.ProxyAuthReplace {
+			// The repository credentials are dropped: the proxy
+			// authenticates the requests to the remote with its own
+			// credentials, and the credentials of the proxy URL are only
+			// sent to the proxy, see proxyOptions.
+			auth = nil
+		} else if opts.CredentialsCallback != nil {
+			auth = &callbackAuth{callback: opts.CredentialsCallback}
+		} else if opts.Username != "" || opts.Password != "" {
+			auth = &http.BasicAuth{
 				Username: opts.Username,
 				Password: opts.Password,
-			}, nil
+			}
 		} else if opts.BearerToken != "" {
-			return &http.TokenAuth{
+			auth = &http.TokenAuth{
 				Token: opts.BearerToken,
-			}, nil
+			}
 		}
-		return nil, nil
+		if opts.Transport == git.HTTPS && (len(opts.PinnedSANs) > 0 || len(opts.PinnedSPKIHashes) > 0) {
+			return newPinnedAuth(auth, opts), nil
+		}
+		if auth == nil {
+			return nil, nil
+		}
+		return auth, nil
 	case git.SSH:
 		// if the custom auth options don't provide a private key and known_hosts, we try
 		// to use the default known_hosts of the machine.
@@ -65,10 +80,11 @@ func transportAuth(opts *git.AuthOptions, fallbackToDefaultKnownHosts bool) (tra
 			}
 			return nil, nil
 		}
-		pk, err := ssh.NewPublicKeys(opts.Username, opts.Identity, opts.Password)
+		signer, err := parsePrivateKey(opts.Identity, opts.Password)
 		if err != nil {
 			return nil, err
 		}
+		pk := &ssh.PublicKeys{User: opts.Username, Signer: signer}
 
 		var callback gossh.HostKeyCallback
 		var hkAlgos []string
@@ -92,6 +108,19 @@ func transportAuth(opts *git.AuthOptions, fallbackToDefaultKnownHosts bool) (tra
 	}
 }
 
+// proxyOptions returns the proxy options for the remote operations with
+// the given git.AuthOptions. The proxy URL of HTTP(S) auth options takes
+// precedence over the proxy options of the client. The credentials of the
+// proxy URL are sent by the HTTP transport in the Proxy-Authorization
+// header, on the CONNECT request for HTTPS remotes.
+func (g *Client) proxyOptions(opts *git.AuthOptions) transport.ProxyOptions {
+	if opts == nil || opts.ProxyURL == "" ||
+		(opts.Transport != git.HTTPS && opts.Transport != git.HTTP) {
+		return g.proxy
+	}
+	return transport.ProxyOptions{URL: opts.ProxyURL}
+}
+
 // clientCert returns the client certificate from the given git.AuthOptions.
 func clientCert(opts *git.AuthOptions) []byte {
 	if opts == nil {
@@ -122,6 +151,7 @@ type CustomPublicKeys struct {
 	pk       *ssh.PublicKeys
 	callback gossh.HostKeyCallback
 	hkAlgos  []string
+	trace    *operationTrace
 }
 
 func (a *CustomPublicKeys) Name() string {
@@ -135,6 +165,10 @@ func (a *CustomPublicKeys) String() string {
 func (a *CustomPublicKeys) ClientConfig() (*gossh.ClientConfig, error) {
 	if a.callback != nil {
 		a.pk.HostKeyCallback = a.callback
+		// go-git dials the remote right after getting the config.
+		if a.trace != nil {
+			a.pk.HostKeyCallback = a.trace.hostKeyCallback(a.trace.start(TracePhaseSSHHandshake), a.callback)
+		}
 	}
 
 	config, err := a.pk.ClientConfig()
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// applyWorktreeModes checks the symlinks of the HEAD commit tree and, if
+// preserveModes is true, applies the modes of its files to the checked out
+// worktree, which go-git does not do when checking out over existing files,
+// or on filesystems ignoring the permissions of the created files: the
+// executable bit of the executable files is set, and the symlinks
+// materialized as regular files are recreated as symlinks. Only the files
+// whose mode differs are changed. The files whose mode could not be applied
+// are returned as warnings, and the files which are not checked out, e.g.
+// with a sparse checkout, are skipped. If rejectUnsafeSymlinks is true, a
+// git.ErrUnsafeSymlink error is returned for the symlinks with an absolute
+// target or a target outside of the worktree.
+func (g *Client) applyWorktreeModes(preserveModes, rejectUnsafeSymlinks bool) ([]string, error) {
+	head, err := g.repository.Head()
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve HEAD: %w", err)
+	}
+	commit, err := g.repository.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve commit object for HEAD '%s': %w", head.Hash(), err)
+	}
+	files, err := commit.Files()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list files of commit '%s': %w", head.Hash(), err)
+	}
+
+	var warnings []string
+	err = files.ForEach(func(f *object.File) error {
+		var warning string
+		var err error
+		switch f.Mode {
+		case filemode.Symlink:
+			warning, err = g.applySymlink(f, preserveModes, rejectUnsafeSymlinks)
+		case filemode.Executable, filemode.Regular, filemode.Deprecated:
+			if preserveModes {
+				warning, err = g.applyExecutableBit(f)
+			}
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// applyExecutableBit sets or clears the executable bit of the regular file
+// in the worktree according to its mode in the tree.
+func (g *Client) applyExecutableBit(f *object.File) (string, error) {
+	fi, err := g.worktreeFS.Lstat(f.Name)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to stat file '%s': %w", f.Name, err)
+	}
+	if !fi.Mode().IsRegular() {
+		return "", nil
+	}
+
+	executable := f.Mode == filemode.Executable
+	if isExecutable(fi.Mode()) == executable {
+		return "", nil
+	}
+	perm := fi.Mode().Perm() &^ 0o111
+	if executable {
+		perm |= (perm & 0o444) >> 2
+	}
+
+	chmod, ok := g.worktreeFS.(billy.Chmod)
+	if !ok {
+		return fmt.Sprintf("unable to apply the mode %s of the file '%s': the filesystem does not support changing modes",
+			f.Mode, f.Name), nil
+	}
+	if err := chmod.Chmod(f.Name, perm); err != nil {
+		return fmt.Sprintf("unable to apply the mode %s of the file '%s': %s", f.Mode, f.Name, err), nil
+	}
+	// Some filesystems, e.g. on Windows, ignore the executable bit.
+	if fi, err = g.worktreeFS.Lstat(f.Name); err != nil || isExecutable(fi.Mode()) != executable {
+		return fmt.Sprintf("unable to apply the mode %s of the file '%s': the filesystem ignores the executable bit",
+			f.Mode, f.Name), nil
+	}
+	return "", nil
+}
+
+// applySymlink recreates the symlink in the worktree if it has been
+// materialized as a regular file and recreate is true. If the symlink
+// cannot be created, the file holding its target is restored, as Git does.
+func (g *Client) applySymlink(f *object.File, recreate, rejectUnsafe bool) (string, error) {
+	target, err := f.Contents()
+	if err != nil {
+		return "", fmt.Errorf("unable to read target of symlink '%s': %w", f.Name, err)
+	}
+	if rejectUnsafe && isUnsafeSymlink(f.Name, target) {
+		return "", git.ErrUnsafeSymlink{Path: f.Name, Target: target}
+	}
+	if !recreate {
+		return "", nil
+	}
+
+	fi, err := g.worktreeFS.Lstat(f.Name)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to stat symlink '%s': %w", f.Name, err)
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		return "", nil
+	}
+	if !fi.Mode().IsRegular() {
+		return fmt.Sprintf("unable to create the symlink '%s': a %s exists at its path", f.Name, fi.Mode().Type()), nil
+	}
+
+	if err := g.worktreeFS.Remove(f.Name); err != nil {
+		return "", fmt.Errorf("unable to remove file '%s': %w", f.Name, err)
+	}
+	symlinkErr := g.worktreeFS.Symlink(target, f.Name)
+	if symlinkErr == nil {
+		if fi, err := g.worktreeFS.Lstat(f.Name); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+			return "", nil
+		}
+		symlinkErr = errors.New("the filesystem does not support symlinks")
+		if err := g.worktreeFS.Remove(f.Name); err != nil {
+			return "", fmt.Errorf("unable to remove file '%s': %w", f.Name, err)
+		}
+	}
+
+	if err := g.writeSymlinkFile(f.Name, target); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("unable to create the symlink '%s' to '%s': %s", f.Name, target, symlinkErr), nil
+}
+
+// writeSymlinkFile writes the target of the symlink as the content of a
+// regular file at its path.
+func (g *Client) writeSymlinkFile(name, target string) error {
+	fl, err := g.worktreeFS.Create(name)
+	if err != nil {
+		return fmt.Errorf("unable to restore file '%s': %w", name, err)
+	}
+	if _, err := io.WriteString(fl, target); err != nil {
+		fl.Close()
+		return fmt.Errorf("unable to restore file '%s': %w", name, err)
+	}
+	return fl.Close()
+}
+
+// isUnsafeSymlink returns true if the target of the symlink at the given
+// path, relative to the root of the worktree, is absolute or outside of
+// the worktree.
+func isUnsafeSymlink(name, target string) bool {
+	if path.IsAbs(target) || filepath.IsAbs(filepath.FromSlash(target)) || filepath.VolumeName(target) != "" {
+		return true
+	}
+	resolved := path.Join(path.Dir(name), target)
+	return resolved == ".." || strings.HasPrefix(resolved, "../")
+}
+
+// isExecutable returns true if the mode has any of the executable bits.
+func isExecutable(mode os.FileMode) bool {
+	return mode.Perm()&0o111 != 0
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+// commitModes commits the given files with their modes, the content of the
+// symlinks being their target.
+func commitModes(repo *extgogit.Repository, files map[string]struct {
+	content string
+	mode    os.FileMode
+}) (plumbing.Hash, error) {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return plumbing.Hash{}, err
+	}
+	for path, file := range files {
+		if file.mode&os.ModeSymlink != 0 {
+			if err := wt.Filesystem.Symlink(file.content, path); err != nil {
+				return plumbing.Hash{}, err
+			}
+		} else {
+			if err := wt.Filesystem.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return plumbing.Hash{}, err
+			}
+			f, err := wt.Filesystem.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.mode)
+			if err != nil {
+				return plumbing.Hash{}, err
+			}
+			if _, err = f.Write([]byte(file.content)); err != nil {
+				f.Close()
+				return plumbing.Hash{}, err
+			}
+			if err = f.Close(); err != nil {
+				return plumbing.Hash{}, err
+			}
+		}
+		if _, err = wt.Add(path); err != nil {
+			return plumbing.Hash{}, err
+		}
+	}
+	return wt.Commit("Adding files with modes", &extgogit.CommitOptions{
+		Author:    mockSignature(time.Now()),
+		Committer: mockSignature(time.Now()),
+	})
+}
+
+// initModesRepo initialises a repository with an executable file and
+// symlinked Helm chart templates.
+func initModesRepo(t *testing.T, extra map[string]string) string {
+	t.Helper()
+
+	repo, repoPath, err := initRepo(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]struct {
+		content string
+		mode    os.FileMode
+	}{
+		"bin/run.sh":                   {content: "#!/bin/sh\n", mode: 0o755},
+		"README.md":                    {content: "# Modes\n", mode: 0o644},
+		"chart/templates/_helpers.tpl": {content: "{{/* helpers */}}\n", mode: 0o644},
+		"chart/templates/shared.tpl":   {content: "_helpers.tpl", mode: os.ModeSymlink},
+		"chart/README.md":              {content: "../README.md", mode: os.ModeSymlink},
+	}
+	for path, target := range extra {
+		files[path] = struct {
+			content string
+			mode    os.FileMode
+		}{content: target, mode: os.ModeSymlink}
+	}
+	if _, err := commitModes(repo, files); err != nil {
+		t.Fatal(err)
+	}
+	return repoPath
+}
+
+// lossyFS is a worktree filesystem which does not support the executable
+// bit nor symlinks, writing the target of the symlinks as regular files.
+type lossyFS struct {
+	billy.Filesystem
+}
+
+func (fs lossyFS) OpenFile(filename string, flag int, perm os.FileMode) (billy.File, error) {
+	return fs.Filesystem.OpenFile(filename, flag, perm&^0o111)
+}
+
+func (fs lossyFS) Symlink(target, link string) error {
+	f, err := fs.Filesystem.Create(link)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte(target)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func TestClone_worktreeModes(t *testing.T) {
+	g := NewWithT(t)
+
+	repoPath := initModesRepo(t, nil)
+	tmpDir := t.TempDir()
+	ggc, err := NewClient(tmpDir, &git.AuthOptions{Transport: git.HTTP})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cc, err := ggc.Clone(context.TODO(), repoPath, repository.CloneConfig{
+		CheckoutStrategy:     repository.CheckoutStrategy{Branch: git.DefaultBranch},
+		PreserveFileModes:    true,
+		RejectUnsafeSymlinks: true,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cc.Warnings).To(BeEmpty())
+
+	fi, err := os.Lstat(filepath.Join(tmpDir, "bin/run.sh"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fi.Mode().Perm() & 0o111).ToNot(BeZero())
+	fi, err = os.Lstat(filepath.Join(tmpDir, "README.md"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fi.Mode().Perm() & 0o111).To(BeZero())
+
+	g.Expect(os.Readlink(filepath.Join(tmpDir, "chart/templates/shared.tpl"))).To(Equal("_helpers.tpl"))
+	g.Expect(os.ReadFile(filepath.Join(tmpDir, "chart/templates/shared.tpl"))).To(BeEquivalentTo("{{/* helpers */}}\n"))
+	g.Expect(os.Readlink(filepath.Join(tmpDir, "chart/README.md"))).To(Equal("../README.md"))
+
+	t.Run("restores the modes lost in the worktree", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(os.Chmod(filepath.Join(tmpDir, "bin/run.sh"), 0o644)).To(Succeed())
+		g.Expect(os.Remove(filepath.Join(tmpDir, "chart/templates/shared.tpl"))).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(tmpDir, "chart/templates/shared.tpl"), nil, 0o644)).To(Succeed())
+
+		warnings, err := ggc.applyWorktreeModes(true, false)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(warnings).To(BeEmpty())
+
+		fi, err := os.Lstat(filepath.Join(tmpDir, "bin/run.sh"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0o755)))
+		g.Expect(os.Readlink(filepath.Join(tmpDir, "chart/templates/shared.tpl"))).To(Equal("_helpers.tpl"))
+	})
+}
+
+func TestClone_worktreeModesWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	repoPath := initModesRepo(t, nil)
+	lossyClient := func(dir string) *Client {
+		sto := filesystem.NewStorage(osfs.New(filepath.Join(dir, extgogit.GitDirName), osfs.WithBoundOS()), cache.NewObjectLRUDefault())
+		ggc, err := NewClient(dir, &git.AuthOptions{Transport: git.HTTP},
+			WithStorer(sto), WithWorkTreeFS(lossyFS{osfs.New(dir, osfs.WithBoundOS())}))
+		g.Expect(err).ToNot(HaveOccurred())
+		return ggc
+	}
+
+	// Without the option, the worktree is left as checked out by go-git.
+	tmpDir := t.TempDir()
+	cc, err := lossyClient(tmpDir).Clone(context.TODO(), repoPath, repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cc.Warnings).To(BeEmpty())
+	fi, err := os.Lstat(filepath.Join(tmpDir, "bin/run.sh"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fi.Mode().Perm() & 0o111).To(BeZero())
+
+	tmpDir = t.TempDir()
+	cc, err = lossyClient(tmpDir).Clone(context.TODO(), repoPath, repository.CloneConfig{
+		CheckoutStrategy:  repository.CheckoutStrategy{Branch: git.DefaultBranch},
+		PreserveFileModes: true,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cc.Warnings).To(ConsistOf(
+		"unable to apply the mode 0100755 of the file 'bin/run.sh': the filesystem does not support changing modes",
+		"unable to create the symlink 'chart/README.md' to '../README.md': the filesystem does not support symlinks",
+		"unable to create the symlink 'chart/templates/shared.tpl' to '_helpers.tpl': the filesystem does not support symlinks",
+	))
+
+	// The symlinks are written as files holding their target, as Git does.
+	g.Expect(filepath.Join(tmpDir, "chart/templates/shared.tpl")).To(BeARegularFile())
+	g.Expect(os.ReadFile(filepath.Join(tmpDir, "chart/templates/shared.tpl"))).To(BeEquivalentTo("_helpers.tpl"))
+}
+
+func TestClone_rejectUnsafeSymlinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		target string
+	}{
+		{name: "absolute target", path: "chart/templates/passwd", target: "/etc/passwd"},
+		{name: "out-of-tree target", path: "chart/templates/secret", target: "../../../secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			repoPath := initModesRepo(t, map[string]string{tt.path: tt.target})
+			cfg := repository.CloneConfig{
+				CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+			}
+
+			ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = ggc.Clone(context.TODO(), repoPath, cfg)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cfg.RejectUnsafeSymlinks = true
+			ggc, err = NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = ggc.Clone(context.TODO(), repoPath, cfg)
+			var symlinkErr git.ErrUnsafeSymlink
+			g.Expect(errors.As(err, &symlinkErr)).To(BeTrue())
+			g.Expect(symlinkErr).To(Equal(git.ErrUnsafeSymlink{Path: tt.path, Target: tt.target}))
+		})
+	}
+}
+
+func Test_isUnsafeSymlink(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   bool
+	}{
+		{name: "chart/templates/link", target: "_helpers.tpl", want: false},
+		{name: "chart/templates/link", target: "../values.yaml", want: false},
+		{name: "chart/templates/link", target: "../../README.md", want: false},
+		{name: "chart/templates/link", target: "../../../README.md", want: true},
+		{name: "chart/templates/link", target: "../../chart/../../README.md", want: true},
+		{name: "link", target: "..", want: true},
+		{name: "link", target: "/etc/passwd", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"->"+tt.target, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isUnsafeSymlink(tt.name, tt.target)).To(Equal(tt.want))
+		})
+	}
+}
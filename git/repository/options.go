@@ -18,6 +18,7 @@ package repository
 
 import (
 	"io"
+	"time"
 
 	"github.com/fluxcd/pkg/git/signature"
 )
@@ -38,6 +39,12 @@ type CloneConfig struct {
 	// not supported by all Implementations.
 	RecurseSubmodules bool
 
+	// SubmoduleRevisions defines if the revisions of the checked out
+	// submodules, as pinned by the superproject, should be reported in
+	// the returned commit, to be rendered with Commit.StringWithSubmodules.
+	// It requires RecurseSubmodules, not supported by all implementations.
+	SubmoduleRevisions bool
+
 	// LastObservedCommit holds the last observed commit hash of a
 	// Git repository.
 	// If provided, the clone operation will compare it with the HEAD commit
@@ -52,9 +59,45 @@ type CloneConfig struct {
 	// not supported by all implementations
 	ShallowClone bool
 
+	// ShallowSince bounds the history of the clone to the commits more recent
+	// than the given time, using the deepen-since capability of the Git
+	// protocol. Only the references of the checked out branch are fetched,
+	// and it takes precedence over ShallowClone. It is only supported with
+	// a Branch checkout, not supported by all implementations.
+	ShallowSince time.Time
+
 	// SparseCheckoutDirectories defines a list of directories to sparse-checkout
 	// when cloning the repository. If provided, only listed directories are checked out.
 	SparseCheckoutDirectories []string
+
+	// PreserveFileModes defines if the modes of the checked out files, i.e.
+	// the executable bit and the symlinks, should be applied to the worktree
+	// where the checkout did not, e.g. over existing files or on filesystems
+	// ignoring the permissions of the created files. The files whose mode
+	// could not be applied are reported in the Warnings of the returned
+	// commit. Not supported by all implementations.
+	PreserveFileModes bool
+
+	// RejectUnsafeSymlinks defines if the clone should fail with a
+	// git.ErrUnsafeSymlink error when a symlink of the checked out tree has
+	// an absolute target or a target outside of the worktree, consistent
+	// with the policy of the tar package. Not supported by all
+	// implementations.
+	RejectUnsafeSymlinks bool
+
+	// MaxObjects is the maximum number of objects received while cloning,
+	// including the fetches into a repository cache. The clone is aborted
+	// with a git.ErrRepositoryTooLarge error beyond it, and the partially
+	// cloned data is discarded. Zero means no limit. Not supported by all
+	// implementations.
+	MaxObjects int
+
+	// MaxSize is the maximum size in bytes of the data received while
+	// cloning, including the fetches into a repository cache. The clone is
+	// aborted with a git.ErrRepositoryTooLarge error beyond it, and the
+	// partially cloned data is discarded. Zero means no limit. Not supported
+	// by all implementations.
+	MaxSize int64
 }
 
 // PushConfig provides configuration options for a Git push.
@@ -71,6 +114,43 @@ type PushConfig struct {
 	// to the Git server when performing a push option. For details, see:
 	// https://git-scm.com/docs/git-push#Documentation/git-push.txt---push-optionltoptiongt
 	Options map[string]string
+
+	// Mode is the mode of pushing to a remote branch holding commits which
+	// are not in the history of the pushed commit, e.g. the prior update
+	// commits of an automation pushing to a branch other than the checkout
+	// branch. It requires at most one refspec. If empty, the refspecs are
+	// pushed as is. Not supported by all implementations.
+	Mode PushMode
+}
+
+// PushMode is the mode of pushing a commit to a remote branch.
+type PushMode string
+
+const (
+	// PushModeReset resets the remote branch to the pushed commit with a
+	// force push, i.e. to the checkout branch and the update commit,
+	// discarding the commits of the remote branch.
+	PushModeReset PushMode = "Reset"
+
+	// PushModeCherryPick cherry-picks the pushed commit onto the head of the
+	// remote branch, keeping its history. It fails with a
+	// git.ErrCherryPickConflict error if the files changed by the commit were
+	// changed differently on the remote branch. The remote branch is created
+	// from the pushed commit if it does not exist.
+	PushModeCherryPick PushMode = "CherryPick"
+)
+
+// PushResult is the result of a Git push.
+type PushResult struct {
+	// Mode is the mode of the push, empty if the refspecs were pushed as is.
+	Mode PushMode
+
+	// Refspecs are the refspecs pushed.
+	Refspecs []string
+
+	// CherryPicked is the hash of the commit created by cherry-picking the
+	// pushed commit onto the remote branch, empty if not cherry-picked.
+	CherryPicked string
 }
 
 // CheckoutStrategy provides options to checkout a repository to a target.
@@ -87,7 +167,10 @@ type CheckoutStrategy struct {
 
 	// RefName is the reference to checkout to. It must conform to the
 	// Git reference format: https://git-scm.com/book/en/v2/Git-Internals-Git-References
-	// Examples: "refs/heads/main", "refs/pull/420/head", "refs/tags/v0.1.0"
+	// Examples: "refs/heads/main", "refs/pull/420/head", "refs/tags/v0.1.0",
+	// "refs/changes/34/1234/2", "refs/merge-requests/1/head", "refs/notes/commits"
+	// The references which are neither branches nor tags are fetched by
+	// refspec, and must point to a commit.
 	// It takes precedence over Branch, Tag and SemVer.
 	RefName string
 
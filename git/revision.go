@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// HashTypeSHA256 is the SHA256 hash algorithm.
+	HashTypeSHA256 = "sha256"
+)
+
+var (
+	// revisionAlgorithmRegexp matches the algorithm of a digest, as defined
+	// by the OCI image specification.
+	revisionAlgorithmRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*$`)
+	// revisionEncodedRegexp matches the encoded part of a digest, as defined
+	// by the OCI image specification.
+	revisionEncodedRegexp = regexp.MustCompile(`^[a-zA-Z0-9=_-]+$`)
+	// revisionHexRegexp matches a lowercase hex encoded hash.
+	revisionHexRegexp = regexp.MustCompile(`^[a-f0-9]+$`)
+)
+
+// revisionHexLengths holds the length of the hex encoded hashes of the
+// well-known algorithms.
+var revisionHexLengths = map[string]int{
+	HashTypeSHA1:   40,
+	HashTypeSHA256: 64,
+	"sha384":       96,
+	"sha512":       128,
+}
+
+// Revision is the revision of an artifact, composed of an optional named
+// reference and of a digest, for example:
+// 'main@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738' for a Git commit, or
+// 'v1.2.3@sha256:2f6c...' for an OCI artifact.
+//
+// The zero value is not a valid Revision, use ParseRevision or NewRevision.
+type Revision struct {
+	ref        string
+	algorithm  string
+	encoded    string
+	submodules string
+}
+
+// NewRevision returns the Revision with the given named reference, which
+// may be empty, and the given algorithm and hash of the digest.
+func NewRevision(ref, algorithm, hash string) (Revision, error) {
+	if strings.Contains(ref, "(+") {
+		return Revision{}, fmt.Errorf("invalid named reference '%s'", ref)
+	}
+	rev := Revision{ref: ref, algorithm: algorithm, encoded: hash}
+	if err := rev.validate(); err != nil {
+		return Revision{}, err
+	}
+	rev.normalize()
+	return rev, nil
+}
+
+// ParseRevision parses the given revision string. It accepts the following
+// formats:
+//
+//   - main@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738
+//   - feature/branch@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738
+//   - v1.2.3@sha256:2f6c3266b1e299a5a4b05bd3c4bbc8fd45ac1eb4a04d3ab5b1ddc92d7b5144e1
+//   - sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738
+//
+// The submodules suffix of the revision strings returned by
+// Commit.StringWithSubmodules is kept, and is part of the formatted
+// Revision. For backwards compatibility with the artifacts produced by
+// older controllers, the following legacy formats are accepted too:
+//
+//   - main/5394cb7f48332b2de7c17dd8b8384bbc84b7e738
+//   - feature/branch/5394cb7f48332b2de7c17dd8b8384bbc84b7e738
+//   - HEAD/5394cb7f48332b2de7c17dd8b8384bbc84b7e738
+//   - latest/2f6c3266b1e299a5a4b05bd3c4bbc8fd45ac1eb4a04d3ab5b1ddc92d7b5144e1
+//   - 5394cb7f48332b2de7c17dd8b8384bbc84b7e738
+//
+// The algorithm of a legacy revision is inferred from the length of the
+// hash, and a 'HEAD' named reference is dropped.
+func ParseRevision(rev string) (Revision, error) {
+	s := rev
+	var submodules string
+	if i := strings.Index(s, "(+"); i >= 0 && strings.HasSuffix(s, ")") {
+		s, submodules = s[:i], s[i+2:len(s)-1]
+		if submodules == "" {
+			return Revision{}, fmt.Errorf("invalid revision '%s': empty submodules suffix", rev)
+		}
+	}
+
+	var r Revision
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		digest := s[:i]
+		r.encoded = s[i+1:]
+		if j := strings.LastIndex(digest, "@"); j >= 0 {
+			r.ref, digest = digest[:j], digest[j+1:]
+			if r.ref == "" {
+				return Revision{}, fmt.Errorf("invalid revision '%s': empty named reference", rev)
+			}
+		}
+		r.algorithm = digest
+	} else {
+		r.encoded = s
+		if i := strings.LastIndex(s, "/"); i >= 0 {
+			r.ref, r.encoded = s[:i], s[i+1:]
+			if r.ref == "HEAD" {
+				r.ref = ""
+			}
+		}
+		r.algorithm = legacyAlgorithm(r.encoded)
+		if r.algorithm == "" {
+			return Revision{}, fmt.Errorf("invalid revision '%s': unable to infer the algorithm of hash '%s'", rev, r.encoded)
+		}
+	}
+	r.submodules = submodules
+
+	if err := r.validate(); err != nil {
+		return Revision{}, fmt.Errorf("invalid revision '%s': %w", rev, err)
+	}
+	r.normalize()
+	return r, nil
+}
+
+// legacyAlgorithm returns the algorithm of a legacy revision hash based on
+// its length, or an empty string if it is not a hex encoded SHA-1 or
+// SHA-256 hash.
+func legacyAlgorithm(hash string) string {
+	if !revisionHexRegexp.MatchString(strings.ToLower(hash)) {
+		return ""
+	}
+	for _, algorithm := range []string{HashTypeSHA1, HashTypeSHA256} {
+		if len(hash) == revisionHexLengths[algorithm] {
+			return algorithm
+		}
+	}
+	return ""
+}
+
+// validate returns an error if the digest of the revision is invalid.
+func (r Revision) validate() error {
+	if !revisionAlgorithmRegexp.MatchString(r.algorithm) {
+		return fmt.Errorf("invalid digest algorithm '%s'", r.algorithm)
+	}
+	if expected, ok := revisionHexLengths[r.algorithm]; ok {
+		if len(r.encoded) != expected || !revisionHexRegexp.MatchString(strings.ToLower(r.encoded)) {
+			return fmt.Errorf("invalid %s hash '%s': expected %d hex characters", r.algorithm, r.encoded, expected)
+		}
+		return nil
+	}
+	if !revisionEncodedRegexp.MatchString(r.encoded) {
+		return fmt.Errorf("invalid %s hash '%s'", r.algorithm, r.encoded)
+	}
+	return nil
+}
+
+// normalize lowercases the hex encoded hashes of the well-known algorithms.
+func (r *Revision) normalize() {
+	if _, ok := revisionHexLengths[r.algorithm]; ok {
+		r.encoded = strings.ToLower(r.encoded)
+	}
+}
+
+// Ref returns the named reference of the revision, e.g. 'main' or 'v1.2.3',
+// or an empty string if the revision has none.
+func (r Revision) Ref() string {
+	return r.ref
+}
+
+// Algorithm returns the algorithm of the digest, e.g. 'sha1'.
+func (r Revision) Algorithm() string {
+	return r.algorithm
+}
+
+// Hash returns the hash of the digest, without the algorithm.
+func (r Revision) Hash() Hash {
+	return Hash(r.encoded)
+}
+
+// Digest returns the digest of the revision, in the format of
+// '<algorithm>:<hash>'.
+func (r Revision) Digest() string {
+	if r.encoded == "" {
+		return ""
+	}
+	return r.algorithm + ":" + r.encoded
+}
+
+// ShortDigest returns the digest of the revision with the hash truncated to
+// n characters, e.g. 'sha1:5394cb7' for n equal to 7. The digest is not
+// truncated if n is not positive or greater than the length of the hash.
+func (r Revision) ShortDigest(n int) string {
+	if n <= 0 || n >= len(r.encoded) {
+		return r.Digest()
+	}
+	return r.algorithm + ":" + r.encoded[:n]
+}
+
+// Equal returns true if both revisions have the same named reference,
+// digest and submodules, whatever the format they were parsed from.
+func (r Revision) Equal(other Revision) bool {
+	return r == other
+}
+
+// String returns the canonical form of the revision, e.g.
+// 'main@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738', which can be
+// parsed back with ParseRevision.
+func (r Revision) String() string {
+	s := r.Digest()
+	if r.ref != "" {
+		s = r.ref + "@" + s
+	}
+	if r.submodules != "" {
+		s += "(+" + r.submodules + ")"
+	}
+	return s
+}
@@ -0,0 +1,434 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testSHA1   = "5394cb7f48332b2de7c17dd8b8384bbc84b7e738"
+	testSHA256 = "2f6c3266b1e299a5a4b05bd3c4bbc8fd45ac1eb4a04d3ab5b1ddc92d7b5144e1"
+)
+
+func TestParseRevision(t *testing.T) {
+	tests := []struct {
+		name          string
+		rev           string
+		wantRef       string
+		wantAlgorithm string
+		wantHash      string
+		wantString    string
+		wantErr       string
+	}{
+		{
+			name:          "branch and sha1 digest",
+			rev:           "main@sha1:" + testSHA1,
+			wantRef:       "main",
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "main@sha1:" + testSHA1,
+		},
+		{
+			name:          "branch with slash and sha1 digest",
+			rev:           "feature/branch@sha1:" + testSHA1,
+			wantRef:       "feature/branch",
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "feature/branch@sha1:" + testSHA1,
+		},
+		{
+			name:          "absolute reference",
+			rev:           "refs/heads/main@sha1:" + testSHA1,
+			wantRef:       "refs/heads/main",
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "refs/heads/main@sha1:" + testSHA1,
+		},
+		{
+			name:          "reference with at sign",
+			rev:           "user@feature@sha1:" + testSHA1,
+			wantRef:       "user@feature",
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "user@feature@sha1:" + testSHA1,
+		},
+		{
+			name:          "sha1 digest",
+			rev:           "sha1:" + testSHA1,
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "sha1:" + testSHA1,
+		},
+		{
+			name:          "OCI tag and sha256 digest",
+			rev:           "v1.2.3@sha256:" + testSHA256,
+			wantRef:       "v1.2.3",
+			wantAlgorithm: "sha256",
+			wantHash:      testSHA256,
+			wantString:    "v1.2.3@sha256:" + testSHA256,
+		},
+		{
+			name:          "sha256 digest",
+			rev:           "sha256:" + testSHA256,
+			wantAlgorithm: "sha256",
+			wantHash:      testSHA256,
+			wantString:    "sha256:" + testSHA256,
+		},
+		{
+			name:          "sha512 digest",
+			rev:           "sha512:" + testSHA256 + testSHA256,
+			wantAlgorithm: "sha512",
+			wantHash:      testSHA256 + testSHA256,
+			wantString:    "sha512:" + testSHA256 + testSHA256,
+		},
+		{
+			name:          "uppercase hash",
+			rev:           "main@sha1:" + strings.ToUpper(testSHA1),
+			wantRef:       "main",
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "main@sha1:" + testSHA1,
+		},
+		{
+			name:          "other algorithm",
+			rev:           "v1@blake3:Abc_123=",
+			wantRef:       "v1",
+			wantAlgorithm: "blake3",
+			wantHash:      "Abc_123=",
+			wantString:    "v1@blake3:Abc_123=",
+		},
+		{
+			name:          "submodules suffix",
+			rev:           "main@sha1:" + testSHA1 + "(+2 submodules: sha256:" + testSHA256 + ")",
+			wantRef:       "main",
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "main@sha1:" + testSHA1 + "(+2 submodules: sha256:" + testSHA256 + ")",
+		},
+		{
+			name:          "legacy branch and hash",
+			rev:           "main/" + testSHA1,
+			wantRef:       "main",
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "main@sha1:" + testSHA1,
+		},
+		{
+			name:          "legacy branch with slash and hash",
+			rev:           "feature/branch/" + testSHA1,
+			wantRef:       "feature/branch",
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "feature/branch@sha1:" + testSHA1,
+		},
+		{
+			name:          "legacy HEAD and hash",
+			rev:           "HEAD/" + testSHA1,
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "sha1:" + testSHA1,
+		},
+		{
+			name:          "legacy OCI tag and digest",
+			rev:           "latest/" + testSHA256,
+			wantRef:       "latest",
+			wantAlgorithm: "sha256",
+			wantHash:      testSHA256,
+			wantString:    "latest@sha256:" + testSHA256,
+		},
+		{
+			name:          "legacy hash",
+			rev:           testSHA1,
+			wantAlgorithm: "sha1",
+			wantHash:      testSHA1,
+			wantString:    "sha1:" + testSHA1,
+		},
+		{
+			name:          "legacy sha256 hash",
+			rev:           testSHA256,
+			wantAlgorithm: "sha256",
+			wantHash:      testSHA256,
+			wantString:    "sha256:" + testSHA256,
+		},
+		{
+			name:    "empty",
+			rev:     "",
+			wantErr: "unable to infer the algorithm",
+		},
+		{
+			name:    "chart version",
+			rev:     "6.0.1",
+			wantErr: "unable to infer the algorithm of hash '6.0.1'",
+		},
+		{
+			name:    "legacy short hash",
+			rev:     "main/5394cb7",
+			wantErr: "unable to infer the algorithm of hash '5394cb7'",
+		},
+		{
+			name:    "short sha1 hash",
+			rev:     "main@sha1:5394cb7",
+			wantErr: "invalid sha1 hash '5394cb7': expected 40 hex characters",
+		},
+		{
+			name:    "non hex sha256 hash",
+			rev:     "sha256:" + strings.Repeat("z", 64),
+			wantErr: "expected 64 hex characters",
+		},
+		{
+			name:    "empty named reference",
+			rev:     "@sha1:" + testSHA1,
+			wantErr: "empty named reference",
+		},
+		{
+			name:    "empty algorithm",
+			rev:     "main@:" + testSHA1,
+			wantErr: "invalid digest algorithm ''",
+		},
+		{
+			name:    "invalid algorithm",
+			rev:     "main@SHA1:" + testSHA1,
+			wantErr: "invalid digest algorithm 'SHA1'",
+		},
+		{
+			name:    "empty hash",
+			rev:     "main@sha1:",
+			wantErr: "invalid sha1 hash ''",
+		},
+		{
+			name:    "invalid hash",
+			rev:     "v1@blake3:abc/def",
+			wantErr: "invalid blake3 hash 'abc/def'",
+		},
+		{
+			name:    "empty submodules suffix",
+			rev:     "main@sha1:" + testSHA1 + "(+)",
+			wantErr: "empty submodules suffix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rev, err := ParseRevision(tt.rev)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				g.Expect(rev).To(BeZero())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rev.Ref()).To(Equal(tt.wantRef))
+			g.Expect(rev.Algorithm()).To(Equal(tt.wantAlgorithm))
+			g.Expect(rev.Hash()).To(Equal(Hash(tt.wantHash)))
+			g.Expect(rev.Digest()).To(Equal(tt.wantAlgorithm + ":" + tt.wantHash))
+			g.Expect(rev.String()).To(Equal(tt.wantString))
+
+			// The canonical form is parsed back to the same revision.
+			parsed, err := ParseRevision(rev.String())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(parsed.Equal(rev)).To(BeTrue())
+		})
+	}
+}
+
+func TestParseRevision_Commit(t *testing.T) {
+	g := NewWithT(t)
+
+	commit := &Commit{
+		Hash:      Hash(testSHA1),
+		Reference: "refs/tags/v1.0.0",
+		Submodules: []SubmoduleRevision{
+			{Path: "lib", Hash: Hash(testSHA1)},
+		},
+	}
+
+	for _, s := range []string{commit.String(), commit.AbsoluteReference(), commit.StringWithSubmodules()} {
+		rev, err := ParseRevision(s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rev.String()).To(Equal(s))
+		g.Expect(rev.Hash()).To(Equal(commit.Hash))
+		g.Expect(rev.Digest()).To(Equal(commit.Hash.Digest()))
+	}
+}
+
+func TestNewRevision(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       string
+		algorithm string
+		hash      string
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "branch",
+			ref:       "main",
+			algorithm: "sha1",
+			hash:      testSHA1,
+			want:      "main@sha1:" + testSHA1,
+		},
+		{
+			name:      "without reference",
+			algorithm: "sha256",
+			hash:      strings.ToUpper(testSHA256),
+			want:      "sha256:" + testSHA256,
+		},
+		{
+			name:      "invalid hash",
+			ref:       "main",
+			algorithm: "sha1",
+			hash:      "abc",
+			wantErr:   "invalid sha1 hash 'abc'",
+		},
+		{
+			name:      "submodules in reference",
+			ref:       "main(+1)",
+			algorithm: "sha1",
+			hash:      testSHA1,
+			wantErr:   "invalid named reference 'main(+1)'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rev, err := NewRevision(tt.ref, tt.algorithm, tt.hash)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rev.String()).To(Equal(tt.want))
+
+			parsed, err := ParseRevision(rev.String())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(parsed).To(Equal(rev))
+		})
+	}
+}
+
+func TestRevision_Equal(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{
+			name: "same revision",
+			a:    "main@sha1:" + testSHA1,
+			b:    "main@sha1:" + testSHA1,
+			want: true,
+		},
+		{
+			name: "legacy and canonical formats",
+			a:    "main/" + testSHA1,
+			b:    "main@sha1:" + testSHA1,
+			want: true,
+		},
+		{
+			name: "legacy HEAD and digest",
+			a:    "HEAD/" + testSHA1,
+			b:    "sha1:" + testSHA1,
+			want: true,
+		},
+		{
+			name: "different case",
+			a:    "main@sha1:" + strings.ToUpper(testSHA1),
+			b:    "main@sha1:" + testSHA1,
+			want: true,
+		},
+		{
+			name: "different reference",
+			a:    "main@sha1:" + testSHA1,
+			b:    "dev@sha1:" + testSHA1,
+		},
+		{
+			name: "with and without reference",
+			a:    "main@sha1:" + testSHA1,
+			b:    "sha1:" + testSHA1,
+		},
+		{
+			name: "different submodules",
+			a:    "main@sha1:" + testSHA1,
+			b:    "main@sha1:" + testSHA1 + "(+1 submodules: sha256:" + testSHA256 + ")",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			a, err := ParseRevision(tt.a)
+			g.Expect(err).ToNot(HaveOccurred())
+			b, err := ParseRevision(tt.b)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(a.Equal(b)).To(Equal(tt.want))
+			g.Expect(b.Equal(a)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRevision_ShortDigest(t *testing.T) {
+	g := NewWithT(t)
+
+	rev, err := ParseRevision("main@sha1:" + testSHA1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rev.ShortDigest(7)).To(Equal("sha1:5394cb7"))
+	g.Expect(rev.ShortDigest(0)).To(Equal("sha1:" + testSHA1))
+	g.Expect(rev.ShortDigest(-1)).To(Equal("sha1:" + testSHA1))
+	g.Expect(rev.ShortDigest(100)).To(Equal("sha1:" + testSHA1))
+}
+
+func FuzzParseRevision(f *testing.F) {
+	for _, seed := range []string{
+		"main@sha1:" + testSHA1,
+		"feature/branch@sha1:" + testSHA1,
+		"v1.2.3@sha256:" + testSHA256,
+		"sha1:" + testSHA1,
+		"main/" + testSHA1,
+		"HEAD/" + testSHA1,
+		"latest/" + testSHA256,
+		testSHA1,
+		"main@sha1:" + testSHA1 + "(+2 submodules: sha256:" + testSHA256 + ")",
+		"v1@blake3:Abc_123=",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		rev, err := ParseRevision(s)
+		if err != nil {
+			return
+		}
+		parsed, err := ParseRevision(rev.String())
+		if err != nil {
+			t.Fatalf("failed to parse the canonical form '%s' of '%s': %v", rev.String(), s, err)
+		}
+		if !parsed.Equal(rev) {
+			t.Fatalf("parsing the canonical form '%s' of '%s' returned '%s'", rev.String(), s, parsed.String())
+		}
+	})
+}
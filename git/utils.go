@@ -59,9 +59,16 @@ func SecurePath(path string) (string, error) {
 // - feature/branch@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738
 // - sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738
 //
+// The submodules suffix of a revision string returned by
+// Commit.StringWithSubmodules is trimmed, as the superproject commit
+// pins the submodule revisions.
+//
 // NOTE: This function is only intended to be used for backwards compatibility
 // with the old revision format. It may be removed in a future release.
 func TransformRevision(rev string) string {
+	if i := strings.Index(rev, "(+"); i >= 0 {
+		rev = rev[:i]
+	}
 	if rev == "" || strings.LastIndex(rev, ":") >= 0 {
 		return rev
 	}
@@ -87,6 +87,11 @@ func TestTransformRevision(t *testing.T) {
 			rev:  "HEAD/5394cb7f48332b2de7c17dd8b8384bbc84b7e738",
 			want: "sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738",
 		},
+		{
+			name: "revision with submodules",
+			rev:  "main@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738(+1 submodules: sha256:ea3e4c5a7a5b3ff6cfc0894a4ea1bb6ea5ba72ac02ce2ea3c56eda84ace6d1ec)",
+			want: "main@sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738",
+		},
 		{
 			name: "empty revision",
 			rev:  "",
@@ -20,10 +20,12 @@ import (
 	"context"
 	_ "crypto/sha256"
 	_ "crypto/sha512"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -50,8 +52,14 @@ type ArchiveFetcher struct {
 	hostnameOverwrite string
 	filename          string
 	logger            any
+	transport         http.RoundTripper
+	tlsConfig         *tls.Config
+	unixSocket        string
 
 	httpClient *retryablehttp.Client
+	// transportErr is the error of configuring the HTTP transport,
+	// returned when fetching.
+	transportErr error
 }
 
 // Option is an option for constructing the ArchiveFetcher.
@@ -113,6 +121,35 @@ func WithFileMode(fileMode fs.FileMode) Option {
 	}
 }
 
+// WithTransport sets the HTTP transport used for downloading archives, e.g.
+// a transport presenting the workload identity of a service mesh. The
+// transport must be an *http.Transport to be combined with WithTLSConfig
+// or WithUnixSocket.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(a *ArchiveFetcher) {
+		a.transport = transport
+	}
+}
+
+// WithTLSConfig sets the TLS client config used for downloading archives
+// over HTTPS, e.g. with a client certificate for mutual TLS.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(a *ArchiveFetcher) {
+		a.tlsConfig = tlsConfig
+	}
+}
+
+// WithUnixSocket configures the HTTP client to connect to the Unix domain
+// socket at the given address instead of the host of the download URLs.
+// The address is either a path or a URL with the unix scheme, e.g.
+// 'unix:///var/run/source.sock'. The download URLs are requested as is,
+// their host is only used for the Host header and the TLS server name.
+func WithUnixSocket(address string) Option {
+	return func(a *ArchiveFetcher) {
+		a.unixSocket = address
+	}
+}
+
 // New creates an *ArchiveFetcher accepting options.
 func New(opts ...Option) *ArchiveFetcher {
 	a := &ArchiveFetcher{
@@ -133,10 +170,69 @@ func New(opts ...Option) *ArchiveFetcher {
 	default:
 		a.httpClient.Logger = a.logger
 	}
+	a.httpClient.HTTPClient.Transport, a.transportErr = a.newTransport(a.httpClient.HTTPClient.Transport)
 
 	return a
 }
 
+// newTransport returns the HTTP transport configured with the transport,
+// TLS config and Unix socket options, based on the given default transport.
+func (a *ArchiveFetcher) newTransport(defaultTransport http.RoundTripper) (http.RoundTripper, error) {
+	transport := defaultTransport
+	if a.transport != nil {
+		transport = a.transport
+	}
+	if a.tlsConfig == nil && a.unixSocket == "" {
+		return transport, nil
+	}
+
+	t, ok := transport.(*http.Transport)
+	if !ok {
+		return transport, fmt.Errorf("the TLS config and Unix socket options require an *http.Transport, got %T", transport)
+	}
+	t = t.Clone()
+
+	if a.tlsConfig != nil {
+		t.TLSClientConfig = a.tlsConfig.Clone()
+	}
+
+	if a.unixSocket != "" {
+		socketPath, err := parseUnixSocket(a.unixSocket)
+		if err != nil {
+			return transport, err
+		}
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+		// The socket is local, a proxy would dial the host of the URL instead.
+		t.Proxy = nil
+	}
+	return t, nil
+}
+
+// parseUnixSocket returns the path of the Unix domain socket at the given
+// address, which is either a path or a URL with the unix scheme.
+func parseUnixSocket(address string) (string, error) {
+	if !strings.Contains(address, "://") {
+		return address, nil
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid Unix socket address '%s': %w", address, err)
+	}
+	if u.Scheme != "unix" {
+		return "", fmt.Errorf("invalid Unix socket address '%s': unsupported scheme '%s'", address, u.Scheme)
+	}
+	if u.Host != "" || u.Path == "" {
+		return "", fmt.Errorf("invalid Unix socket address '%s': must be an absolute path, e.g. 'unix:///var/run/source.sock'", address)
+	}
+	return u.Path, nil
+}
+
 // NewArchiveFetcher configures the retryable HTTP client used for fetching archives.
 //
 // Deprecated: Use New() instead.
@@ -167,6 +263,10 @@ func (r *ArchiveFetcher) Fetch(archiveURL, digest, dir string) error {
 
 // FetchWithContext is the same as Fetch but accepts a context.
 func (r *ArchiveFetcher) FetchWithContext(ctx context.Context, archiveURL, digest, dir string) (err error) {
+	if r.transportErr != nil {
+		return fmt.Errorf("failed to configure the HTTP transport: %w", r.transportErr)
+	}
+
 	if r.hostnameOverwrite != "" {
 		u, err := url.Parse(archiveURL)
 		if err != nil {
@@ -18,10 +18,22 @@ package fetch
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -295,3 +307,190 @@ func TestArchiveFetcher_Fetch(t *testing.T) {
 		})
 	}
 }
+
+// newUnixSocketServer returns an unstarted server listening on a Unix socket,
+// serving the files of root. The first request is answered with a
+// 503 status code to exercise the retries.
+func newUnixSocketServer(t *testing.T, root string) (*httptest.Server, string, *atomic.Int32) {
+	t.Helper()
+
+	// The path of Unix sockets is limited to about 100 characters,
+	// which the directory returned by t.TempDir() may exceed.
+	dir, err := os.MkdirTemp("", "fetch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "source.sock")
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	fileServer := http.FileServer(http.Dir(root))
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fileServer.ServeHTTP(w, r)
+	}))
+	server.Listener.Close()
+	server.Listener = l
+	t.Cleanup(server.Close)
+	return server, socket, &requests
+}
+
+// newClientCertificate returns a self-signed client certificate.
+func newClientCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kustomize-controller"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestArchiveFetcher_UnixSocket(t *testing.T) {
+	g := NewWithT(t)
+
+	artifactServer, err := testserver.NewTempArtifactServer()
+	g.Expect(err).ToNot(HaveOccurred())
+	artifactName := "manifests.tgz"
+	artifactChecksum, err := artifactServer.ArtifactFromDir("testdata", artifactName)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	t.Run("plain HTTP", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server, socket, requests := newUnixSocketServer(t, artifactServer.Root())
+		server.Start()
+
+		for _, address := range []string{"unix://" + socket, socket} {
+			requests.Store(0)
+			tmpDir := t.TempDir()
+			fetcher := New(WithUntar(), WithRetries(1), WithUnixSocket(address))
+			err := fetcher.Fetch("http://source-controller.flux-system.svc/"+artifactName, artifactChecksum, tmpDir)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(filepath.Join(tmpDir, "testdata/manifests.yaml")).To(BeARegularFile())
+			g.Expect(requests.Load()).To(Equal(int32(2)))
+		}
+	})
+
+	t.Run("mutual TLS", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clientCert := newClientCertificate(t)
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCert.Leaf)
+
+		server, socket, requests := newUnixSocketServer(t, artifactServer.Root())
+		server.TLS = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+		}
+		server.StartTLS()
+		rootCAs := x509.NewCertPool()
+		rootCAs.AddCert(server.Certificate())
+
+		// The certificate of the test server is valid for example.com.
+		artifactURL := "https://example.com/" + artifactName
+
+		tmpDir := t.TempDir()
+		fetcher := New(WithUntar(), WithRetries(1), WithUnixSocket("unix://"+socket), WithTLSConfig(&tls.Config{
+			RootCAs:      rootCAs,
+			Certificates: []tls.Certificate{clientCert},
+		}))
+		g.Expect(fetcher.Fetch(artifactURL, artifactChecksum, tmpDir)).To(Succeed())
+		g.Expect(filepath.Join(tmpDir, "testdata/manifests.yaml")).To(BeARegularFile())
+		g.Expect(requests.Load()).To(Equal(int32(2)))
+
+		fetcher = New(WithUntar(), WithUnixSocket("unix://"+socket), WithTLSConfig(&tls.Config{
+			RootCAs: rootCAs,
+		}))
+		g.Expect(fetcher.Fetch(artifactURL, artifactChecksum, t.TempDir())).ToNot(Succeed())
+	})
+}
+
+// countingTransport counts the requests sent through the wrapped transport.
+type countingTransport struct {
+	http.RoundTripper
+	requests atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return c.RoundTripper.RoundTrip(req)
+}
+
+func TestArchiveFetcher_WithTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	testServer, err := testserver.NewTempArtifactServer()
+	g.Expect(err).ToNot(HaveOccurred())
+	testServer.Start()
+	defer testServer.Stop()
+
+	artifactName := "manifests.tgz"
+	artifactChecksum, err := testServer.ArtifactFromDir("testdata", artifactName)
+	g.Expect(err).ToNot(HaveOccurred())
+	artifactURL := fmt.Sprintf("%s/%s", testServer.URL(), artifactName)
+
+	transport := &countingTransport{RoundTripper: http.DefaultTransport}
+	fetcher := New(WithUntar(), WithTransport(transport))
+	g.Expect(fetcher.Fetch(artifactURL, artifactChecksum, t.TempDir())).To(Succeed())
+	g.Expect(transport.requests.Load()).To(Equal(int32(1)))
+
+	fetcher = New(WithUntar(), WithTransport(transport), WithTLSConfig(&tls.Config{}))
+	err = fetcher.Fetch(artifactURL, artifactChecksum, t.TempDir())
+	g.Expect(err).To(MatchError(ContainSubstring("the TLS config and Unix socket options require an *http.Transport")))
+}
+
+func TestArchiveFetcher_WithUnixSocket_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantErr string
+	}{
+		{
+			name:    "unsupported scheme",
+			address: "tcp:///var/run/source.sock",
+			wantErr: "unsupported scheme 'tcp'",
+		},
+		{
+			name:    "relative path",
+			address: "unix://var/run/source.sock",
+			wantErr: "must be an absolute path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fetcher := New(WithUnixSocket(tt.address))
+			err := fetcher.Fetch("http://source-controller/manifests.tgz", "sha256:abc", t.TempDir())
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
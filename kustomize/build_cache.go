@@ -0,0 +1,340 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/provider"
+	"sigs.k8s.io/kustomize/api/resmap"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	securefs "github.com/fluxcd/pkg/kustomize/filesys"
+)
+
+// BuildCache stores the serialized results of kustomize builds,
+// keyed by a hash of the build inputs.
+type BuildCache interface {
+	// Get returns the cached build result for the given key.
+	Get(key string) ([]byte, bool)
+	// Set stores the build result for the given key.
+	Set(key string, value []byte)
+	// Purge removes all the entries from the cache.
+	Purge()
+}
+
+// BuildOptions holds the inputs of a Generator build.
+type BuildOptions struct {
+	// Revision is the revision of the source the build is performed on.
+	// When empty, the build result is not cached.
+	Revision string
+
+	// Vars holds the resolved post-build substitution variables. They are
+	// part of the cache key, so that results built for different
+	// substitution inputs are never shared.
+	Vars map[string]string
+
+	// AllowRemoteBases allows the kustomization to refer to remote bases.
+	// As the content of remote bases is not pinned to the source revision,
+	// the build result is not cached.
+	AllowRemoteBases bool
+
+	// Options holds the krusty options of the build, e.g.
+	// WithLoadRestrictions. The build result is not cached when loading
+	// files from outside the root is allowed with WithLoadRoot, or when the
+	// Helm charts are inflated with WithHelmChartInflation.
+	Options []BuildOption
+}
+
+// buildCacheSpecFields are the Kustomization spec fields which affect
+// the result of a build.
+var buildCacheSpecFields = []string{
+	targetNSField,
+	patchesField,
+	componentsField,
+	ignoreComponentsField,
+	patchesSMField,
+	patchesJson6902Field,
+	imagesField,
+	namePrefixField,
+	nameSuffixField,
+	buildMetadataField,
+}
+
+// Build generates the kustomization file in dirPath and builds it. When the
+// Generator has a BuildCache, the result of a build with identical inputs is
+// returned from the cache without accessing the file system, along with the
+// ScanWarnings and SchemaWarnings of the build. The generated kustomization
+// file is removed from dirPath once the build is done.
+func (g *Generator) Build(dirPath string, opts BuildOptions) (resmap.ResMap, error) {
+	key, ok := g.buildCacheKey(dirPath, opts)
+	if ok {
+		if data, found := g.cache.Get(key); found {
+			var entry buildCacheEntry
+			if err := json.Unmarshal(data, &entry); err == nil {
+				g.scanWarnings, g.schemaWarnings = entry.ScanWarnings, entry.SchemaWarnings
+				return resmap.NewFactory(provider.NewDefaultDepProvider().GetResourceFactory()).NewResMapFromBytes(entry.Resources)
+			}
+		}
+	}
+
+	fs, err := g.buildFS(opts.AllowRemoteBases, newBuildConfig(opts.Options...))
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := g.WriteFile(dirPath, WithSaveOriginalKustomization())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := Build(fs, dirPath, opts.Options...)
+	if cleanErr := CleanDirectory(dirPath, action); cleanErr != nil {
+		err = errors.Join(err, cleanErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		resources, err := res.AsYaml()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize build result: %w", err)
+		}
+		data, err := json.Marshal(buildCacheEntry{
+			Resources:      resources,
+			ScanWarnings:   g.scanWarnings,
+			SchemaWarnings: g.schemaWarnings,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize build result: %w", err)
+		}
+		g.cache.Set(key, data)
+	}
+	return res, nil
+}
+
+// buildCacheEntry is the cached result of a build, along with the warnings
+// of the generation of its kustomization file, which are restored on a hit.
+type buildCacheEntry struct {
+	Resources      []byte   `json:"resources"`
+	ScanWarnings   []string `json:"scanWarnings,omitempty"`
+	SchemaWarnings []string `json:"schemaWarnings,omitempty"`
+}
+
+// InvalidateBuildCache removes all the entries from the BuildCache of the
+// Generator. It must be called when inputs which are not part of the cache
+// key change, e.g. the decryption keys or the schemas of the cluster used by
+// WithSchemaValidation.
+func (g *Generator) InvalidateBuildCache() {
+	if g.cache != nil {
+		g.cache.Purge()
+	}
+}
+
+// buildFS returns the file system used to build the kustomization, rooted
+// at the load root of the build config if configured.
+func (g *Generator) buildFS(allowRemoteBases bool, config *buildConfig) (filesys.FileSystem, error) {
+	if g.root == "" || g.customFS {
+		return g.getFS()
+	}
+	root, err := config.fsRoot(g.root)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case allowRemoteBases:
+		return securefs.MakeFsOnDiskSecureBuild(root)
+	case root != g.root:
+		return securefs.MakeFsOnDiskSecure(root)
+	default:
+		return g.getFS()
+	}
+}
+
+// buildCacheKey returns the cache key for a build of dirPath with the given
+// options. It returns false when the Generator has no BuildCache or when
+// the key can't be computed.
+func (g *Generator) buildCacheKey(dirPath string, opts BuildOptions) (string, bool) {
+	if g.cache == nil || opts.Revision == "" || opts.AllowRemoteBases || g.hasRemoteResources() {
+		return "", false
+	}
+	config := newBuildConfig(opts.Options...)
+	if config.loadRoot != "" || config.helm != nil {
+		return "", false
+	}
+
+	// The path is made relative to the root, as the source is usually
+	// extracted to a different temporary directory for every build.
+	path := dirPath
+	if g.root != "" {
+		rel, err := filepath.Rel(g.root, dirPath)
+		if err != nil {
+			return "", false
+		}
+		path = rel
+	}
+
+	spec := make(map[string]interface{}, len(buildCacheSpecFields))
+	for _, field := range buildCacheSpecFields {
+		if v, ok, _ := unstructured.NestedFieldNoCopy(g.kustomization.Object, specField, field); ok {
+			spec[field] = v
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		Revision string                    `json:"revision"`
+		Path     string                    `json:"path"`
+		Ignore   string                    `json:"ignore,omitempty"`
+		Filter   bool                      `json:"filter,omitempty"`
+		Spec     map[string]interface{}    `json:"spec"`
+		Vars     map[string]string         `json:"vars,omitempty"`
+		Restrict kustypes.LoadRestrictions `json:"loadRestrictions,omitempty"`
+		Plugins  *kustypes.PluginConfig    `json:"pluginConfig"`
+		Stable   bool                      `json:"stableOrder,omitempty"`
+		Owner    *ownershipLabels          `json:"ownershipLabels,omitempty"`
+		Origin   bool                      `json:"originAnnotations,omitempty"`
+		KeepLast bool                      `json:"keepLastDuplicate,omitempty"`
+		Schema   bool                      `json:"schemaValidation,omitempty"`
+		Strict   bool                      `json:"strictSchemaValidation,omitempty"`
+	}{
+		Revision: opts.Revision,
+		Path:     filepath.ToSlash(path),
+		Ignore:   g.ignore,
+		Filter:   g.filter,
+		Spec:     spec,
+		Vars:     opts.Vars,
+		Restrict: config.effectiveLoadRestrictions(),
+		Plugins:  config.pluginConfig,
+		Stable:   config.stableOrder,
+		Owner:    config.ownership,
+		Origin:   config.originAnnotations,
+		KeepLast: g.keepLastDuplicate,
+		Schema:   g.schemaValidator != nil,
+		Strict:   g.strictSchemaValidation,
+	})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+}
+
+// hasRemoteResources returns true if the Kustomization spec refers to
+// components which are not local relative paths.
+func (g *Generator) hasRemoteResources() bool {
+	components, _, _ := g.getNestedStringSlice(specField, componentsField)
+	for _, c := range components {
+		if !IsLocalRelativePath(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// LRUBuildCache is an in-memory BuildCache which evicts the least recently
+// used entries when its size bounds are exceeded. It is safe for
+// concurrent use.
+type LRUBuildCache struct {
+	maxEntries int
+	maxBytes   int
+
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruBuildCacheEntry struct {
+	key   string
+	value []byte
+}
+
+var _ BuildCache = &LRUBuildCache{}
+
+// NewLRUBuildCache returns an LRUBuildCache holding at most maxEntries
+// entries with a total size of at most maxBytes. A value of zero or less
+// disables the respective bound.
+func NewLRUBuildCache(maxEntries, maxBytes int) *LRUBuildCache {
+	return &LRUBuildCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get implements BuildCache.
+func (c *LRUBuildCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return e.Value.(*lruBuildCacheEntry).value, true
+}
+
+// Set implements BuildCache. Values larger than the size bound
+// are not stored.
+func (c *LRUBuildCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+	if c.maxBytes > 0 && len(value) > c.maxBytes {
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruBuildCacheEntry{key: key, value: value})
+	c.size += len(value)
+	for (c.maxEntries > 0 && c.ll.Len() > c.maxEntries) || (c.maxBytes > 0 && c.size > c.maxBytes) {
+		c.remove(c.ll.Back())
+	}
+}
+
+// Purge implements BuildCache.
+func (c *LRUBuildCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.size = 0
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUBuildCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRUBuildCache) remove(e *list.Element) {
+	entry := c.ll.Remove(e).(*lruBuildCacheEntry)
+	delete(c.items, entry.key)
+	c.size -= len(entry.value)
+}
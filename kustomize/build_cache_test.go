@@ -0,0 +1,372 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/otiai10/copy"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/openapi/openapitest"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/fluxcd/pkg/kustomize"
+)
+
+// countingFS is a filesys.FileSystem counting the read operations.
+type countingFS struct {
+	filesys.FileSystem
+	reads atomic.Int64
+}
+
+func (fs *countingFS) ReadFile(path string) ([]byte, error) {
+	fs.reads.Add(1)
+	return fs.FileSystem.ReadFile(path)
+}
+
+func (fs *countingFS) ReadDir(path string) ([]string, error) {
+	fs.reads.Add(1)
+	return fs.FileSystem.ReadDir(path)
+}
+
+func (fs *countingFS) Open(path string) (filesys.File, error) {
+	fs.reads.Add(1)
+	return fs.FileSystem.Open(path)
+}
+
+func (fs *countingFS) Exists(path string) bool {
+	fs.reads.Add(1)
+	return fs.FileSystem.Exists(path)
+}
+
+func (fs *countingFS) IsDir(path string) bool {
+	fs.reads.Add(1)
+	return fs.FileSystem.IsDir(path)
+}
+
+func (fs *countingFS) Walk(path string, walkFn filepath.WalkFunc) error {
+	fs.reads.Add(1)
+	return fs.FileSystem.Walk(path, walkFn)
+}
+
+func (fs *countingFS) Glob(pattern string) ([]string, error) {
+	fs.reads.Add(1)
+	return fs.FileSystem.Glob(pattern)
+}
+
+func newBuildCacheKustomization(namePrefix string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+		"kind":       "Kustomization",
+		"metadata": map[string]interface{}{
+			"name":      "app",
+			"namespace": "flux-system",
+		},
+		"spec": map[string]interface{}{
+			"interval":        "5m",
+			"targetNamespace": "apps",
+			"namePrefix":      namePrefix,
+		},
+	}}
+}
+
+func TestGenerator_Build_Cache(t *testing.T) {
+	g := NewWithT(t)
+
+	tmpDir := t.TempDir()
+	g.Expect(copy.Copy(resourcePath, tmpDir)).To(Succeed())
+
+	cache := kustomize.NewLRUBuildCache(10, 0)
+	fs := &countingFS{FileSystem: filesys.MakeFsOnDisk()}
+	opts := kustomize.BuildOptions{
+		Revision: "main@sha1:6f4b3d1a",
+		Vars:     map[string]string{"cluster_env": "prod"},
+	}
+
+	build := func(ks unstructured.Unstructured, opts kustomize.BuildOptions) (string, int64) {
+		t.Helper()
+		fs.reads.Store(0)
+		gen := kustomize.NewGenerator(tmpDir, ks, kustomize.WithFS(fs), kustomize.WithBuildCache(cache))
+		res, err := gen.Build(tmpDir, opts)
+		g.Expect(err).NotTo(HaveOccurred())
+		data, err := res.AsYaml()
+		g.Expect(err).NotTo(HaveOccurred())
+		return string(data), fs.reads.Load()
+	}
+
+	first, reads := build(newBuildCacheKustomization("prod-"), opts)
+	g.Expect(reads).To(BeNumerically(">", 0))
+	g.Expect(first).To(ContainSubstring("name: prod-"))
+	g.Expect(first).To(ContainSubstring("namespace: apps"))
+	g.Expect(cache.Len()).To(Equal(1))
+
+	// A second build with identical inputs does not touch the file system.
+	second, reads := build(newBuildCacheKustomization("prod-"), opts)
+	g.Expect(reads).To(BeZero())
+	g.Expect(second).To(Equal(first))
+
+	// Fields that do not affect the build are not part of the key.
+	ks := newBuildCacheKustomization("prod-")
+	g.Expect(unstructured.SetNestedField(ks.Object, "10m", "spec", "interval")).To(Succeed())
+	_, reads = build(ks, opts)
+	g.Expect(reads).To(BeZero())
+
+	// A change of the spec, revision or vars results in a new build.
+	changed, reads := build(newBuildCacheKustomization("staging-"), opts)
+	g.Expect(reads).To(BeNumerically(">", 0))
+	g.Expect(changed).To(ContainSubstring("name: staging-"))
+
+	_, reads = build(newBuildCacheKustomization("prod-"), kustomize.BuildOptions{
+		Revision: "main@sha1:9c2e7a10",
+		Vars:     opts.Vars,
+	})
+	g.Expect(reads).To(BeNumerically(">", 0))
+
+	_, reads = build(newBuildCacheKustomization("prod-"), kustomize.BuildOptions{
+		Revision: opts.Revision,
+		Vars:     map[string]string{"cluster_env": "staging"},
+	})
+	g.Expect(reads).To(BeNumerically(">", 0))
+	g.Expect(cache.Len()).To(Equal(4))
+
+	// The generated kustomization file is removed after each build.
+	g.Expect(fs.FileSystem.Exists(filepath.Join(tmpDir, "kustomization.yaml"))).To(BeFalse())
+}
+
+func TestGenerator_Build_CacheBypass(t *testing.T) {
+	tests := []struct {
+		name   string
+		ks     func() unstructured.Unstructured
+		opts   kustomize.BuildOptions
+		cached bool
+	}{
+		{
+			name:   "cached",
+			ks:     func() unstructured.Unstructured { return newBuildCacheKustomization("prod-") },
+			opts:   kustomize.BuildOptions{Revision: "v1.0.0@sha1:6f4b3d1a"},
+			cached: true,
+		},
+		{
+			name: "empty revision",
+			ks:   func() unstructured.Unstructured { return newBuildCacheKustomization("prod-") },
+			opts: kustomize.BuildOptions{},
+		},
+		{
+			name: "remote bases allowed",
+			ks:   func() unstructured.Unstructured { return newBuildCacheKustomization("prod-") },
+			opts: kustomize.BuildOptions{Revision: "v1.0.0@sha1:6f4b3d1a", AllowRemoteBases: true},
+		},
+		{
+			name: "unrestricted loading within the root",
+			ks:   func() unstructured.Unstructured { return newBuildCacheKustomization("prod-") },
+			opts: kustomize.BuildOptions{
+				Revision: "v1.0.0@sha1:6f4b3d1a",
+				Options:  []kustomize.BuildOption{kustomize.WithLoadRestrictions(kustypes.LoadRestrictionsNone)},
+			},
+			cached: true,
+		},
+		{
+			name: "load root wider than the root",
+			ks:   func() unstructured.Unstructured { return newBuildCacheKustomization("prod-") },
+			opts: kustomize.BuildOptions{
+				Revision: "v1.0.0@sha1:6f4b3d1a",
+				Options:  []kustomize.BuildOption{kustomize.WithLoadRoot(os.TempDir())},
+			},
+		},
+		{
+			name: "remote components",
+			ks: func() unstructured.Unstructured {
+				ks := newBuildCacheKustomization("prod-")
+				_ = unstructured.SetNestedStringSlice(ks.Object,
+					[]string{"https://github.com/fluxcd/example//components/app?ref=main"}, "spec", "components")
+				return ks
+			},
+			opts: kustomize.BuildOptions{Revision: "v1.0.0@sha1:6f4b3d1a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tmpDir := t.TempDir()
+			g.Expect(copy.Copy(resourcePath, tmpDir)).To(Succeed())
+
+			cache := kustomize.NewLRUBuildCache(10, 0)
+			gen := kustomize.NewGenerator(tmpDir, tt.ks(), kustomize.WithBuildCache(cache))
+			_, err := gen.Build(tmpDir, tt.opts)
+			if tt.cached {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cache.Len()).To(Equal(1))
+			} else {
+				g.Expect(cache.Len()).To(BeZero())
+			}
+		})
+	}
+}
+
+func TestGenerator_InvalidateBuildCache(t *testing.T) {
+	g := NewWithT(t)
+
+	tmpDir := t.TempDir()
+	g.Expect(copy.Copy(resourcePath, tmpDir)).To(Succeed())
+
+	cache := kustomize.NewLRUBuildCache(10, 0)
+	fs := &countingFS{FileSystem: filesys.MakeFsOnDisk()}
+	gen := kustomize.NewGenerator(tmpDir, newBuildCacheKustomization("prod-"),
+		kustomize.WithFS(fs), kustomize.WithBuildCache(cache))
+	opts := kustomize.BuildOptions{Revision: "main@sha1:6f4b3d1a"}
+
+	_, err := gen.Build(tmpDir, opts)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cache.Len()).To(Equal(1))
+
+	gen.InvalidateBuildCache()
+	g.Expect(cache.Len()).To(BeZero())
+
+	fs.reads.Store(0)
+	_, err = gen.Build(tmpDir, opts)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fs.reads.Load()).To(BeNumerically(">", 0))
+	g.Expect(cache.Len()).To(Equal(1))
+}
+
+func TestLRUBuildCache(t *testing.T) {
+	t.Run("max entries", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := kustomize.NewLRUBuildCache(2, 0)
+		c.Set("a", []byte("a"))
+		c.Set("b", []byte("b"))
+		_, ok := c.Get("a")
+		g.Expect(ok).To(BeTrue())
+
+		// "b" is the least recently used entry.
+		c.Set("c", []byte("c"))
+		g.Expect(c.Len()).To(Equal(2))
+		_, ok = c.Get("b")
+		g.Expect(ok).To(BeFalse())
+		v, ok := c.Get("a")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(v).To(Equal([]byte("a")))
+	})
+
+	t.Run("max bytes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := kustomize.NewLRUBuildCache(0, 10)
+		c.Set("a", []byte("aaaa"))
+		c.Set("b", []byte("bbbb"))
+		c.Set("c", []byte("cccc"))
+		g.Expect(c.Len()).To(Equal(2))
+		_, ok := c.Get("a")
+		g.Expect(ok).To(BeFalse())
+
+		// Values larger than the bound are not stored.
+		c.Set("d", []byte("ddddddddddd"))
+		_, ok = c.Get("d")
+		g.Expect(ok).To(BeFalse())
+		g.Expect(c.Len()).To(Equal(2))
+
+		// Replacing a value updates the size.
+		c.Set("b", []byte("bbbbbb"))
+		g.Expect(c.Len()).To(Equal(2))
+		c.Set("e", []byte("e"))
+		g.Expect(c.Len()).To(Equal(2))
+		_, ok = c.Get("c")
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := kustomize.NewLRUBuildCache(0, 0)
+		c.Set("a", []byte("a"))
+		c.Purge()
+		g.Expect(c.Len()).To(BeZero())
+		_, ok := c.Get("a")
+		g.Expect(ok).To(BeFalse())
+	})
+}
+
+func TestGenerator_Build_CacheWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	// The patch sets an unknown field of the Deployment.
+	ks := newBuildCacheKustomization("prod-")
+	g.Expect(unstructured.SetNestedSlice(ks.Object, []interface{}{
+		map[string]interface{}{
+			"patch": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: podinfo\nspec:\n  replica: 2\n",
+		},
+	}, "spec", "patches")).To(Succeed())
+
+	tmpDir := t.TempDir()
+	g.Expect(copy.Copy(schemaValidationPath+"resources", tmpDir)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(tmpDir, "values.yaml"), []byte("replicas: 1\n"), 0o644)).To(Succeed())
+
+	cache := kustomize.NewLRUBuildCache(10, 0)
+	fs := &countingFS{FileSystem: filesys.MakeFsOnDisk()}
+	opts := kustomize.BuildOptions{Revision: "main@sha1:6f4b3d1a"}
+	schemaClient := openapitest.NewFileClient(schemaValidationPath + "openapi")
+
+	build := func(genOpts ...kustomize.GeneratorOption) (*kustomize.Generator, int64, error) {
+		t.Helper()
+		fs.reads.Store(0)
+		genOpts = append(genOpts, kustomize.WithFS(fs), kustomize.WithBuildCache(cache))
+		gen := kustomize.NewGenerator(tmpDir, ks, genOpts...)
+		_, err := gen.Build(tmpDir, opts)
+		return gen, fs.reads.Load(), err
+	}
+
+	first, reads, err := build(kustomize.WithSchemaValidation(schemaClient))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(BeNumerically(">", 0))
+	g.Expect(first.ScanWarnings()).To(ConsistOf(
+		"values.yaml:1: skipping non-Kubernetes YAML file, missing apiVersion or kind"))
+	g.Expect(first.SchemaWarnings()).To(ConsistOf(
+		"spec.patches[0]: unknown field 'spec.replica' for Deployment.apps"))
+	g.Expect(cache.Len()).To(Equal(1))
+
+	// A hit restores the warnings of the build.
+	second, reads, err := build(kustomize.WithSchemaValidation(schemaClient))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(BeZero())
+	g.Expect(second.ScanWarnings()).To(Equal(first.ScanWarnings()))
+	g.Expect(second.SchemaWarnings()).To(Equal(first.SchemaWarnings()))
+
+	// The strict schema validation fails as on a miss.
+	_, _, err = build(kustomize.WithSchemaValidation(schemaClient), kustomize.WithStrictSchemaValidation())
+	g.Expect(err).To(MatchError(ContainSubstring("invalid patches")))
+	g.Expect(cache.Len()).To(Equal(1))
+
+	// The schema validation and the duplicates handling are part of the key.
+	unvalidated, reads, err := build()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(BeNumerically(">", 0))
+	g.Expect(unvalidated.SchemaWarnings()).To(BeEmpty())
+	g.Expect(cache.Len()).To(Equal(2))
+
+	_, reads, err = build(kustomize.WithDuplicateResourcesKeepLast())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reads).To(BeNumerically(">", 0))
+	g.Expect(cache.Len()).To(Equal(3))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"sigs.k8s.io/kustomize/api/builtins"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resmap"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OwnerNameLabel is the label holding the name of the Kustomization
+	// owning the resources of a build with WithOwnershipLabels.
+	OwnerNameLabel = "kustomize.toolkit.fluxcd.io/name"
+
+	// OwnerNamespaceLabel is the label holding the namespace of the
+	// Kustomization owning the resources of a build with WithOwnershipLabels.
+	OwnerNamespaceLabel = "kustomize.toolkit.fluxcd.io/namespace"
+)
+
+// ownershipLabels holds the owner of the resources of a build.
+type ownershipLabels struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// WithOwnershipLabels sets the OwnerNameLabel and OwnerNamespaceLabel labels
+// of all the resources of the build, including the generated ones, to the
+// given name and namespace of the owning Kustomization. The labels are set
+// once the kustomizations are built, so that they can't be changed or removed
+// by the transformers of the kustomizations.
+func WithOwnershipLabels(name, namespace string) BuildOption {
+	return func(c *buildConfig) {
+		c.ownership = &ownershipLabels{Name: name, Namespace: namespace}
+	}
+}
+
+// WithOriginAnnotations enables the originAnnotations build metadata of the
+// kustomization, which records the origin of each resource in the
+// 'config.kubernetes.io/origin' annotation: the path of the file the resource
+// was loaded from, relative to the kustomization root, or the generator it was
+// produced by, along with the repository and ref of the remote bases.
+// The build metadata is added to the kustomization for the duration of the
+// build.
+func WithOriginAnnotations() BuildOption {
+	return func(c *buildConfig) {
+		c.originAnnotations = true
+	}
+}
+
+// addOwnershipLabels sets the ownership labels on all the resources.
+func (o *ownershipLabels) addOwnershipLabels(res resmap.ResMap) error {
+	t := builtins.LabelTransformerPlugin{
+		Labels: map[string]string{
+			OwnerNameLabel:      o.Name,
+			OwnerNamespaceLabel: o.Namespace,
+		},
+		FieldSpecs: []kustypes.FieldSpec{{
+			Path:               "metadata/labels",
+			CreateIfNotPresent: true,
+		}},
+	}
+	if err := t.Transform(res); err != nil {
+		return fmt.Errorf("failed to add the ownership labels: %w", err)
+	}
+	return nil
+}
+
+// enableOriginAnnotations adds the originAnnotations build metadata to the
+// kustomization in dirPath, and returns a function restoring the original
+// kustomization.
+func enableOriginAnnotations(fs filesys.FileSystem, dirPath string) (func() error, error) {
+	noop := func() error { return nil }
+
+	kfile := ""
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if p := filepath.Join(dirPath, name); fs.Exists(p) {
+			kfile = p
+			break
+		}
+	}
+	if kfile == "" {
+		// Let the build report the missing kustomization.
+		return noop, nil
+	}
+	data, err := fs.ReadFile(kfile)
+	if err != nil {
+		return nil, err
+	}
+	var kus kustypes.Kustomization
+	if err := yaml.Unmarshal(data, &kus); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %w", kfile, err)
+	}
+	if slices.Contains(kus.BuildMetadata, kustypes.OriginAnnotations) {
+		return noop, nil
+	}
+
+	kus.BuildMetadata = append(kus.BuildMetadata, kustypes.OriginAnnotations)
+	rewritten, err := yaml.Marshal(kus)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.WriteFile(kfile, rewritten); err != nil {
+		return nil, err
+	}
+	return func() error {
+		return fs.WriteFile(kfile, data)
+	}, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/fluxcd/pkg/kustomize"
+)
+
+const originAnnotation = "config.kubernetes.io/origin"
+
+// writeBuildMetadataFiles writes an overlay with a generator and a label
+// transformer on top of a base with a plain file.
+func writeBuildMetadataFiles(g *WithT, fs filesys.FileSystem) string {
+	files := map[string]string{
+		"/app/base/kustomization.yaml": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`,
+		"/app/base/deployment.yaml": stableOrderFiles["deployment.yaml"],
+		"/app/overlay/kustomization.yaml": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- ../base
+configMapGenerator:
+- name: app-config
+  namespace: apps
+  literals:
+  - key=value
+labels:
+- pairs:
+    kustomize.toolkit.fluxcd.io/name: other
+`,
+	}
+	for name, content := range files {
+		g.Expect(fs.WriteFile(name, []byte(content))).To(Succeed())
+	}
+	return files["/app/overlay/kustomization.yaml"]
+}
+
+func TestBuild_WithOwnershipLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	fs := filesys.MakeFsInMemory()
+	writeBuildMetadataFiles(g, fs)
+
+	res, err := kustomize.Build(fs, "/app/overlay", kustomize.WithOwnershipLabels("apps", "flux-system"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.Resources()).To(HaveLen(2))
+
+	for _, r := range res.Resources() {
+		g.Expect(r.GetLabels()).To(HaveKeyWithValue(kustomize.OwnerNameLabel, "apps"), r.CurId().String())
+		g.Expect(r.GetLabels()).To(HaveKeyWithValue(kustomize.OwnerNamespaceLabel, "flux-system"), r.CurId().String())
+		g.Expect(r.GetAnnotations()).NotTo(HaveKey(originAnnotation))
+	}
+
+	// The labels are not set by default.
+	res, err = kustomize.Build(fs, "/app/overlay")
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, r := range res.Resources() {
+		g.Expect(r.GetLabels()).To(HaveKeyWithValue(kustomize.OwnerNameLabel, "other"))
+		g.Expect(r.GetLabels()).NotTo(HaveKey(kustomize.OwnerNamespaceLabel))
+	}
+}
+
+func TestBuild_WithOriginAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	fs := filesys.MakeFsInMemory()
+	kustomization := writeBuildMetadataFiles(g, fs)
+
+	res, err := kustomize.Build(fs, "/app/overlay",
+		kustomize.WithOriginAnnotations(), kustomize.WithOwnershipLabels("apps", "flux-system"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	origins := map[string]string{}
+	for _, r := range res.Resources() {
+		origins[r.GetKind()] = r.GetAnnotations()[originAnnotation]
+		g.Expect(r.GetLabels()).To(HaveKeyWithValue(kustomize.OwnerNameLabel, "apps"))
+	}
+	g.Expect(origins["Deployment"]).To(ContainSubstring("path: ../base/deployment.yaml"))
+	g.Expect(origins["ConfigMap"]).To(ContainSubstring("configuredIn: kustomization.yaml"))
+	g.Expect(origins["ConfigMap"]).To(ContainSubstring("kind: ConfigMapGenerator"))
+
+	// The kustomization is restored.
+	data, err := fs.ReadFile("/app/overlay/kustomization.yaml")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal(kustomization))
+}
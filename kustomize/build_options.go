@@ -0,0 +1,171 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+// UnrestrictedLoadingWarning is the warning returned by Warnings when
+// the build is configured with kustypes.LoadRestrictionsNone or with a
+// load root wider than the build root.
+const UnrestrictedLoadingWarning = "kustomize load restrictions are disabled, " +
+	"files outside of the kustomization directories can be referenced"
+
+// BuildOption configures a single kustomize build.
+type BuildOption func(c *buildConfig)
+
+// buildConfig holds the krusty options of a build.
+type buildConfig struct {
+	loadRestrictions kustypes.LoadRestrictions
+	loadRoot         string
+	pluginConfig     *kustypes.PluginConfig
+	helm             *HelmInflationOptions
+	stableOrder      bool
+
+	ownership         *ownershipLabels
+	originAnnotations bool
+}
+
+// WithLoadRestrictions sets the load restrictions of the build:
+//   - kustypes.LoadRestrictionsRootOnly only allows the files in or below
+//     the directory of the kustomization referencing them. This is the
+//     default.
+//   - kustypes.LoadRestrictionsNone allows the files outside of the
+//     directory of the kustomization, and Warnings returns
+//     UnrestrictedLoadingWarning. The files remain confined to the root
+//     of the secure file system of SecureBuild and Generator.Build, which
+//     can be widened with WithLoadRoot, e.g. to the root of a monorepo
+//     holding the shared bases.
+func WithLoadRestrictions(r kustypes.LoadRestrictions) BuildOption {
+	return func(c *buildConfig) {
+		c.loadRestrictions = r
+	}
+}
+
+// WithLoadRoot sets the directory the secure file system of SecureBuild
+// and Generator.Build is confined to, instead of their build root, e.g.
+// the root of a monorepo holding shared bases outside of the build root.
+// The load root must contain the build root. Warnings returns
+// UnrestrictedLoadingWarning, and the build result is not cached.
+func WithLoadRoot(root string) BuildOption {
+	return func(c *buildConfig) {
+		c.loadRoot = root
+	}
+}
+
+// WithPluginConfig sets the plugin config of the build, e.g. to disable
+// some of the builtin plugins such as the Helm chart inflation generator.
+// Defaults to kustypes.DisabledPluginConfig.
+func WithPluginConfig(pc *kustypes.PluginConfig) BuildOption {
+	return func(c *buildConfig) {
+		c.pluginConfig = pc
+	}
+}
+
+// WithHelmChartInflation enables the inflation of the helmCharts of the
+// kustomizations with the Helm SDK, without executing the helm binary.
+// The charts are loaded from the chart home of the kustomization, or
+// fetched from the repositories on the allow list with the getter of the
+// options. The charts of a repository must be pinned to an exact version.
+// Before the build, the helmCharts of each local kustomization are replaced
+// with a file holding the rendered manifests, and the kustomizations are
+// restored once the build is done.
+//
+// The build result is not cached, as the charts can be fetched from outside
+// of the source. The inflation is disabled by default.
+func WithHelmChartInflation(opts HelmInflationOptions) BuildOption {
+	return func(c *buildConfig) {
+		c.helm = &opts
+	}
+}
+
+// Warnings returns the warnings about the given build options which weaken
+// the build isolation, e.g. UnrestrictedLoadingWarning, for controllers to
+// emit them as events.
+func Warnings(opts ...BuildOption) []string {
+	var warnings []string
+	if newBuildConfig(opts...).unrestricted() {
+		warnings = append(warnings, UnrestrictedLoadingWarning)
+	}
+	return warnings
+}
+
+// newBuildConfig returns the build config with the given options applied
+// over the defaults.
+func newBuildConfig(opts ...BuildOption) *buildConfig {
+	c := &buildConfig{
+		pluginConfig: kustypes.DisabledPluginConfig(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// effectiveLoadRestrictions returns the load restrictions of kustomize for
+// the build, which are restricted to the kustomization root unless
+// disabled with kustypes.LoadRestrictionsNone.
+func (c *buildConfig) effectiveLoadRestrictions() kustypes.LoadRestrictions {
+	if c.loadRestrictions == kustypes.LoadRestrictionsNone {
+		return kustypes.LoadRestrictionsNone
+	}
+	return kustypes.LoadRestrictionsRootOnly
+}
+
+// unrestricted returns true if the kustomize load restrictions are disabled
+// or if the files can be loaded from outside of the build root.
+func (c *buildConfig) unrestricted() bool {
+	return c.effectiveLoadRestrictions() == kustypes.LoadRestrictionsNone || c.loadRoot != ""
+}
+
+// fsRoot returns the root of the secure file system for the given build
+// root, which is the load root if configured. It returns an error if the
+// load root does not contain the build root.
+func (c *buildConfig) fsRoot(root string) (string, error) {
+	if c.loadRoot == "" {
+		return root, nil
+	}
+	absLoadRoot, err := filepath.Abs(c.loadRoot)
+	if err != nil {
+		return "", err
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absLoadRoot, absRoot)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("build root '%s' is not within the load root '%s'", root, c.loadRoot)
+	}
+	return c.loadRoot, nil
+}
+
+// krustyOptions returns the krusty options of the build. When the load
+// restrictions are disabled, loading is not restricted by kustomize, but by
+// the file system of the build.
+func (c *buildConfig) krustyOptions() *krusty.Options {
+	return &krusty.Options{
+		LoadRestrictions: c.effectiveLoadRestrictions(),
+		PluginConfig:     c.pluginConfig,
+	}
+}
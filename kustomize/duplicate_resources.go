@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/resid"
+)
+
+// DuplicateResource is a resource defined more than once by the files
+// of a generated kustomization.
+type DuplicateResource struct {
+	// ID is the kustomize ID of the resource, as in the errors of the
+	// build, e.g. 'ConfigMap.v1.[noGrp]/app.default'.
+	ID string
+	// Paths are the paths of the files defining the resource, relative to
+	// the scanned directory, in the order of the scan.
+	Paths []string
+}
+
+// DuplicateResourcesError is the error of the resources defined more than
+// once by the files of a generated kustomization, which kustomize rejects
+// without telling the files involved.
+type DuplicateResourcesError struct {
+	// Duplicates holds the duplicate resources, in the order of the scan.
+	Duplicates []DuplicateResource
+}
+
+// Error returns the duplicate resources along with their files.
+func (e *DuplicateResourcesError) Error() string {
+	msgs := make([]string, 0, len(e.Duplicates))
+	for _, d := range e.Duplicates {
+		msgs = append(msgs, fmt.Sprintf("%s in %s", d.ID, strings.Join(d.Paths, ", ")))
+	}
+	return fmt.Sprintf("found %d resources defined in more than one file: %s",
+		len(e.Duplicates), strings.Join(msgs, "; "))
+}
+
+// WithDuplicateResourcesKeepLast configures the Generator to drop, from a
+// generated kustomization, the files whose resources are all defined again
+// by the files scanned after them, instead of failing with a
+// DuplicateResourcesError, e.g. for repositories migrated from plain
+// manifests with leftover copies. The dropped files are reported by
+// ScanWarnings. The duplicates of the files which also define other
+// resources are still reported as errors.
+func WithDuplicateResourcesKeepLast() GeneratorOption {
+	return func(g *Generator) {
+		g.keepLastDuplicate = true
+	}
+}
+
+// resourceIndex records the files defining the resources found while
+// scanning the manifests of a kustomization, by the kustomize ID of the
+// resources in the order of the scan. The namespace of the IDs is the
+// effective one, as kustomize treats the empty and the default namespaces
+// as equal.
+type resourceIndex struct {
+	ids   []resid.ResId
+	paths map[resid.ResId][]string
+	files map[string][]resid.ResId
+}
+
+// newResourceIndex returns an empty resourceIndex.
+func newResourceIndex() *resourceIndex {
+	return &resourceIndex{
+		paths: make(map[resid.ResId][]string),
+		files: make(map[string][]resid.ResId),
+	}
+}
+
+// add records the resources of the file with the given path. It is a
+// no-op on a nil index.
+func (idx *resourceIndex) add(path string, ids []resid.ResId) {
+	if idx == nil {
+		return
+	}
+	for _, id := range ids {
+		id.Namespace = id.EffectiveNamespace()
+		if _, ok := idx.paths[id]; !ok {
+			idx.ids = append(idx.ids, id)
+		}
+		idx.paths[id] = append(idx.paths[id], path)
+		idx.files[path] = append(idx.files[path], id)
+	}
+}
+
+// duplicates returns the resources defined more than once by the given
+// files, with their paths relative to base.
+func (idx *resourceIndex) duplicates(base string, files []string) []DuplicateResource {
+	var dups []DuplicateResource
+	for _, id := range idx.ids {
+		var paths []string
+		for _, path := range idx.paths[id] {
+			if slices.Contains(files, path) {
+				paths = append(paths, relativePath(base, path))
+			}
+		}
+		if len(paths) > 1 {
+			dups = append(dups, DuplicateResource{ID: id.String(), Paths: paths})
+		}
+	}
+	return dups
+}
+
+// dropShadowed returns the given files without the ones whose resources
+// are all defined again by a file scanned after them, along with the
+// warnings about the dropped files.
+func (idx *resourceIndex) dropShadowed(base string, files []string) ([]string, []string) {
+	var kept, warnings []string
+	for _, file := range files {
+		ids := idx.files[file]
+		if len(ids) == 0 || len(idx.lastIn(file)) > 0 {
+			kept = append(kept, file)
+			continue
+		}
+		var shadowedBy []string
+		for _, id := range ids {
+			paths := idx.paths[id]
+			if rel := relativePath(base, paths[len(paths)-1]); !slices.Contains(shadowedBy, rel) {
+				shadowedBy = append(shadowedBy, rel)
+			}
+		}
+		warnings = append(warnings, (&ManifestError{
+			Path: relativePath(base, file),
+			Message: fmt.Sprintf("skipping file, all its resources are defined again in %s",
+				strings.Join(shadowedBy, ", ")),
+		}).Error())
+	}
+	return kept, warnings
+}
+
+// lastIn returns the resources of the given file for which it is the last
+// file defining them.
+func (idx *resourceIndex) lastIn(file string) []resid.ResId {
+	var last []resid.ResId
+	for _, id := range idx.files[file] {
+		if paths := idx.paths[id]; paths[len(paths)-1] == file {
+			last = append(last, id)
+		}
+	}
+	return last
+}
+
+// relativePath returns the path relative to base, or the path itself if
+// it is not under base.
+func relativePath(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
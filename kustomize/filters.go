@@ -17,6 +17,9 @@ limitations under the License.
 package kustomize
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -32,6 +35,10 @@ import (
 // and/or os.FileInfo.
 type filter func(p string, fi os.FileInfo) bool
 
+// ignoredFilter must return true if a path which does not exist on disk matches the ignore patterns, i.e. the file
+// may be missing because it was excluded from the source.
+type ignoredFilter func(p string) bool
+
 func ignoreFileFilter(ps []gitignore.Pattern, domain []string) filter {
 	matcher := sourceignore.NewDefaultMatcher(ps, domain)
 	return func(p string, fi os.FileInfo) bool {
@@ -39,33 +46,100 @@ func ignoreFileFilter(ps []gitignore.Pattern, domain []string) filter {
 	}
 }
 
+func ignoreMissingFileFilter(ps []gitignore.Pattern, domain []string) ignoredFilter {
+	matcher := sourceignore.NewDefaultMatcher(ps, domain)
+	return func(p string) bool {
+		parts := strings.Split(p, string(filepath.Separator))
+		return matcher.Match(parts, false) || matcher.Match(parts, true)
+	}
+}
+
+// MissingFileError is the error of a file referenced by a kustomization which does not exist on disk.
+type MissingFileError struct {
+	// Field is the kustomization field referencing the file, e.g. 'resources'.
+	Field string
+	// Path is the path of the file relative to the kustomization directory.
+	Path string
+	// Ignored is true if the path matches the ignore patterns, i.e. the file
+	// was likely excluded from the source.
+	Ignored bool
+	// Err is the error returned when accessing the file.
+	Err error
+}
+
+// Error returns the error in the form '<field> '<path>': <reason>'.
+func (e *MissingFileError) Error() string {
+	if e.Ignored {
+		return fmt.Sprintf("%s '%s': missing because it is ignored", e.Field, e.Path)
+	}
+	return fmt.Sprintf("%s '%s': missing on disk", e.Field, e.Path)
+}
+
+// Unwrap returns the error returned when accessing the file.
+func (e *MissingFileError) Unwrap() error {
+	return e.Err
+}
+
+// MissingFilesError aggregates the errors of all the files referenced by a
+// kustomization which do not exist on disk.
+type MissingFilesError struct {
+	// Errors holds the errors of the missing files, in the order of the
+	// resources, components and crds fields.
+	Errors []*MissingFileError
+}
+
+// Error returns the errors of all the missing files.
+func (e *MissingFilesError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("kustomization references %d missing files: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the missing files.
+func (e *MissingFilesError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
 func filterKsWithIgnoreFiles(ks *kustypes.Kustomization, dirPath string, ignorePatterns []gitignore.Pattern, ignoreDomain []string) error {
 	path, err := filepath.Abs(dirPath)
 	if err != nil {
 		return err
 	}
 
-	// Create the filter once with pre-loaded patterns
+	// Create the filters once with pre-loaded patterns
 	filterFunc := ignoreFileFilter(ignorePatterns, ignoreDomain)
+	ignoredFunc := ignoreMissingFileFilter(ignorePatterns, ignoreDomain)
+
+	// Collect the missing files of all the fields, so that they are reported at once.
+	var missing []*MissingFileError
 
 	// filter resources first
-	err = filterSlice(ks, path, &ks.Resources, resourcesField, filterFunc)
+	err = filterSlice(ks, path, &ks.Resources, resourcesField, filterFunc, ignoredFunc, &missing)
 	if err != nil {
 		return err
 	}
 
 	// filter components second
-	err = filterSlice(ks, path, &ks.Components, componentsField, filterFunc)
+	err = filterSlice(ks, path, &ks.Components, componentsField, filterFunc, ignoredFunc, &missing)
 	if err != nil {
 		return err
 	}
 
 	// filter crds third
-	err = filterSlice(ks, path, &ks.Crds, crdsField, filterFunc)
+	err = filterSlice(ks, path, &ks.Crds, crdsField, filterFunc, ignoredFunc, &missing)
 	if err != nil {
 		return err
 	}
 
+	if len(missing) > 0 {
+		return &MissingFilesError{Errors: missing}
+	}
 	return nil
 }
 
@@ -74,7 +148,8 @@ func isUrl(s string) bool {
 	return err == nil && u.Scheme != ""
 }
 
-func filterSlice(ks *kustypes.Kustomization, path string, s *[]string, t string, filter filter) error {
+func filterSlice(ks *kustypes.Kustomization, path string, s *[]string, t string, filter filter,
+	ignored ignoredFilter, missing *[]*MissingFileError) error {
 	start := 0
 	for _, res := range *s {
 		// check if we have a url and skip the source file filters
@@ -82,6 +157,19 @@ func filterSlice(ks *kustypes.Kustomization, path string, s *[]string, t string,
 		if t == crdsField || !isUrl(res) {
 			f := filepath.Join(path, res)
 			info, err := os.Lstat(f)
+			if errors.Is(err, fs.ErrNotExist) {
+				rel, relErr := filepath.Rel(path, f)
+				if relErr != nil {
+					rel = res
+				}
+				*missing = append(*missing, &MissingFileError{
+					Field:   t,
+					Path:    rel,
+					Ignored: ignored(f),
+					Err:     err,
+				})
+				continue
+			}
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluxcd/pkg/sourceignore/gitignore"
+	. "github.com/onsi/gomega"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+func TestFilterKsWithIgnoreFiles(t *testing.T) {
+	patterns := []gitignore.Pattern{
+		gitignore.ParsePattern("secrets/", nil),
+		gitignore.ParsePattern("*.enc.yaml", nil),
+	}
+
+	tests := []struct {
+		name       string
+		files      []string
+		ks         kustypes.Kustomization
+		want       kustypes.Kustomization
+		wantErrors []*MissingFileError
+	}{
+		{
+			name:  "existing files are kept and ignored files filtered",
+			files: []string{"deploy.yaml", "crd.yaml", "token.enc.yaml"},
+			ks: kustypes.Kustomization{
+				Resources: []string{"deploy.yaml", "token.enc.yaml", "https://example.com/app.yaml"},
+				Crds:      []string{"crd.yaml"},
+			},
+			want: kustypes.Kustomization{
+				Resources: []string{"deploy.yaml", "https://example.com/app.yaml"},
+				Crds:      []string{"crd.yaml"},
+			},
+		},
+		{
+			name:  "every missing file is reported",
+			files: []string{"deploy.yaml"},
+			ks: kustypes.Kustomization{
+				Resources:  []string{"deploy.yaml", "service.yaml", "./apps/../ingress.yaml", "secrets"},
+				Components: []string{"components/monitoring"},
+				Crds:       []string{"crds/crd.yaml"},
+			},
+			want: kustypes.Kustomization{
+				Resources:  []string{"deploy.yaml"},
+				Components: []string{},
+				Crds:       []string{},
+			},
+			wantErrors: []*MissingFileError{
+				{Field: resourcesField, Path: "service.yaml"},
+				{Field: resourcesField, Path: "ingress.yaml"},
+				{Field: resourcesField, Path: "secrets", Ignored: true},
+				{Field: componentsField, Path: filepath.Join("components", "monitoring")},
+				{Field: crdsField, Path: filepath.Join("crds", "crd.yaml")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				g.Expect(os.WriteFile(filepath.Join(dir, f), []byte("---"), 0o600)).To(Succeed())
+			}
+
+			ks := tt.ks
+			err := filterKsWithIgnoreFiles(&ks, dir, patterns, nil)
+			if len(tt.wantErrors) == 0 {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(ks).To(Equal(tt.want))
+				return
+			}
+
+			var missingErr *MissingFilesError
+			g.Expect(errors.As(err, &missingErr)).To(BeTrue())
+			g.Expect(errors.Is(err, fs.ErrNotExist)).To(BeTrue())
+			g.Expect(missingErr.Errors).To(HaveLen(len(tt.wantErrors)))
+			for i, want := range tt.wantErrors {
+				got := missingErr.Errors[i]
+				g.Expect(got.Field).To(Equal(want.Field))
+				g.Expect(got.Path).To(Equal(want.Path))
+				g.Expect(got.Ignored).To(Equal(want.Ignored))
+				g.Expect(err.Error()).To(ContainSubstring(got.Error()))
+			}
+			g.Expect(ks).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMissingFileError(t *testing.T) {
+	g := NewWithT(t)
+
+	err := &MissingFilesError{Errors: []*MissingFileError{
+		{Field: resourcesField, Path: "service.yaml", Err: fs.ErrNotExist},
+		{Field: resourcesField, Path: "secrets", Ignored: true, Err: fs.ErrNotExist},
+	}}
+	g.Expect(err.Error()).To(Equal("kustomization references 2 missing files: " +
+		"resources 'service.yaml': missing on disk; " +
+		"resources 'secrets': missing because it is ignored"))
+}
@@ -0,0 +1,461 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/chart/common"
+	"helm.sh/helm/v4/pkg/chart/loader/archive"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/getter"
+	release "helm.sh/helm/v4/pkg/release/v1"
+	repo "helm.sh/helm/v4/pkg/repo/v1"
+	"sigs.k8s.io/kustomize/api/konfig"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+// HelmNetworkPolicy controls the network fetches of the Helm chart inflation.
+type HelmNetworkPolicy string
+
+const (
+	// HelmNetworkAllowListed allows fetching the charts from the
+	// repositories of HelmInflationOptions.RepositoryAllowList.
+	HelmNetworkAllowListed HelmNetworkPolicy = "AllowListed"
+	// HelmNetworkOffline denies all fetches, the charts of the allowed
+	// repositories must be vendored in the chart home of the kustomization,
+	// as '<chartHome>/<name>-<version>/<name>'.
+	HelmNetworkOffline HelmNetworkPolicy = "Offline"
+)
+
+// inflatedChartsFile is the file holding the manifests of the Helm charts
+// of a kustomization, added to its resources for the duration of a build.
+const inflatedChartsFile = ".helm-charts.inflated.yaml"
+
+// HelmInflationOptions configures the inflation of the helmCharts of the
+// kustomizations with the Helm SDK, see WithHelmChartInflation.
+type HelmInflationOptions struct {
+	// RepositoryAllowList is the list of the URLs of the HTTP/S chart
+	// repositories from which charts can be inflated. The charts of other
+	// repositories are rejected, including the vendored ones.
+	RepositoryAllowList []string
+
+	// NetworkPolicy controls whether the charts can be fetched from the
+	// allowed repositories. Defaults to HelmNetworkAllowListed.
+	NetworkPolicy HelmNetworkPolicy
+
+	// Getter fetches the repository indexes and the chart archives.
+	// Defaults to the HTTP getter of Helm, and can be replaced e.g. to
+	// serve the charts from a local mirror in air-gapped environments.
+	Getter getter.Getter
+}
+
+// helmInflater inflates the helmCharts of the kustomizations of a build into
+// files held by the build file system, and restores the kustomizations once
+// the build is done.
+type helmInflater struct {
+	fs        filesys.FileSystem
+	opts      HelmInflationOptions
+	visited   map[string]struct{}
+	originals map[string][]byte
+	created   []string
+}
+
+func newHelmInflater(fs filesys.FileSystem, opts HelmInflationOptions) *helmInflater {
+	return &helmInflater{
+		fs:        fs,
+		opts:      opts,
+		visited:   map[string]struct{}{},
+		originals: map[string][]byte{},
+	}
+}
+
+// inflate walks the kustomization in dirPath and its local resources and
+// components, and replaces the helmCharts of each kustomization with a file
+// resource holding the rendered manifests.
+func (h *helmInflater) inflate(dirPath string) error {
+	dirPath = filepath.Clean(dirPath)
+	if _, ok := h.visited[dirPath]; ok {
+		return nil
+	}
+	h.visited[dirPath] = struct{}{}
+
+	kfile := ""
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if p := filepath.Join(dirPath, name); h.fs.Exists(p) {
+			kfile = p
+			break
+		}
+	}
+	if kfile == "" {
+		return nil
+	}
+	data, err := h.fs.ReadFile(kfile)
+	if err != nil {
+		return err
+	}
+	var kus kustypes.Kustomization
+	if err := yaml.Unmarshal(data, &kus); err != nil {
+		return fmt.Errorf("failed to parse '%s': %w", kfile, err)
+	}
+
+	for _, r := range slices.Concat(kus.Resources, kus.Bases, kus.Components) {
+		if !IsLocalRelativePath(r) {
+			continue
+		}
+		if p := filepath.Join(dirPath, r); h.fs.IsDir(p) {
+			if err := h.inflate(p); err != nil {
+				return err
+			}
+		}
+	}
+
+	charts, globals := kustypes.SplitHelmParameters(kus.HelmChartInflationGenerator)
+	charts = append(kus.HelmCharts, charts...)
+	if kus.HelmGlobals != nil {
+		globals = *kus.HelmGlobals
+	}
+	if len(charts) == 0 {
+		return nil
+	}
+
+	chartHome := globals.ChartHome
+	if chartHome == "" {
+		chartHome = kustypes.HelmDefaultHome
+	}
+	if !filepath.IsAbs(chartHome) {
+		chartHome = filepath.Join(dirPath, chartHome)
+	}
+
+	var manifests bytes.Buffer
+	for _, c := range charts {
+		out, err := h.inflateChart(dirPath, chartHome, c)
+		if err != nil {
+			return fmt.Errorf("failed to inflate Helm chart '%s' in '%s': %w", c.Name, kfile, err)
+		}
+		fmt.Fprintf(&manifests, "---\n%s\n", out)
+	}
+
+	inflated := filepath.Join(dirPath, inflatedChartsFile)
+	if h.fs.Exists(inflated) {
+		return fmt.Errorf("failed to inflate the Helm charts of '%s': '%s' already exists", kfile, inflated)
+	}
+	if err := h.fs.WriteFile(inflated, manifests.Bytes()); err != nil {
+		return err
+	}
+	h.created = append(h.created, inflated)
+
+	kus.HelmCharts = nil
+	kus.HelmGlobals = nil
+	kus.HelmChartInflationGenerator = nil
+	kus.Resources = append(kus.Resources, inflatedChartsFile)
+	rewritten, err := yaml.Marshal(kus)
+	if err != nil {
+		return err
+	}
+	h.originals[kfile] = data
+	return h.fs.WriteFile(kfile, rewritten)
+}
+
+// restore removes the inflated charts files and restores the original
+// kustomizations.
+func (h *helmInflater) restore() error {
+	var errs []error
+	for _, p := range h.created {
+		if err := h.fs.RemoveAll(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, p := range slices.Sorted(maps.Keys(h.originals)) {
+		if err := h.fs.WriteFile(p, h.originals[p]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// inflateChart renders the given chart as 'helm template' would.
+func (h *helmInflater) inflateChart(dirPath, chartHome string, c kustypes.HelmChart) (string, error) {
+	if c.Name == "" {
+		return "", errors.New("chart name cannot be empty")
+	}
+	if c.NameTemplate != "" {
+		return "", errors.New("nameTemplate is not supported, the releaseName must be set instead")
+	}
+
+	chrt, err := h.loadChart(chartHome, c)
+	if err != nil {
+		return "", err
+	}
+	vals, err := h.chartValues(dirPath, chrt, c)
+	if err != nil {
+		return "", err
+	}
+
+	cfg := action.NewConfiguration()
+	cfg.SetLogger(slog.DiscardHandler)
+	install := action.NewInstall(cfg)
+	install.DryRunStrategy = action.DryRunClient
+	install.Replace = true
+	install.ReleaseName = c.ReleaseName
+	if install.ReleaseName == "" {
+		install.ReleaseName = "release-name"
+	}
+	install.Namespace = c.Namespace
+	if install.Namespace == "" {
+		install.Namespace = "default"
+	}
+	install.IncludeCRDs = c.IncludeCRDs
+	install.DisableHooks = c.SkipHooks
+	install.APIVersions = common.VersionSet(c.ApiVersions)
+	if c.KubeVersion != "" {
+		kubeVersion, err := common.ParseKubeVersion(c.KubeVersion)
+		if err != nil {
+			return "", fmt.Errorf("invalid kube version '%s': %w", c.KubeVersion, err)
+		}
+		install.KubeVersion = kubeVersion
+	}
+
+	r, err := install.RunWithContext(context.Background(), chrt, vals)
+	if err != nil {
+		return "", err
+	}
+	rel, ok := r.(*release.Release)
+	if !ok {
+		return "", fmt.Errorf("unexpected release type %T", r)
+	}
+
+	var manifests strings.Builder
+	manifests.WriteString(strings.TrimSpace(rel.Manifest))
+	if !c.SkipHooks {
+		for _, hook := range rel.Hooks {
+			if c.SkipTests && slices.Contains(hook.Events, release.HookTest) {
+				continue
+			}
+			fmt.Fprintf(&manifests, "\n---\n# Source: %s\n%s", hook.Path, hook.Manifest)
+		}
+	}
+	return manifests.String(), nil
+}
+
+// loadChart loads the chart from the chart home of the kustomization, or
+// fetches it from its repository. The charts of a repository must be on the
+// allow list and pinned to an exact version.
+func (h *helmInflater) loadChart(chartHome string, c kustypes.HelmChart) (*chart.Chart, error) {
+	if c.Repo == "" {
+		if c.Version != "" {
+			if _, err := semver.StrictNewVersion(c.Version); err != nil {
+				return nil, fmt.Errorf("version '%s' is not an exact semver version: %w", c.Version, err)
+			}
+		}
+		chrt, err := h.loadChartDir(filepath.Join(chartHome, c.Name))
+		if err != nil {
+			return nil, err
+		}
+		if c.Version != "" && chrt.Metadata.Version != c.Version {
+			return nil, fmt.Errorf("chart version '%s' does not match the requested version '%s'",
+				chrt.Metadata.Version, c.Version)
+		}
+		return chrt, nil
+	}
+
+	if strings.HasPrefix(c.Repo, "oci://") {
+		return nil, fmt.Errorf("OCI repository '%s' is not supported", c.Repo)
+	}
+	repoURL := strings.TrimSuffix(c.Repo, "/")
+	if !h.allowed(repoURL) {
+		return nil, fmt.Errorf("repository '%s' is not on the allow list", c.Repo)
+	}
+	if c.Version == "" {
+		return nil, fmt.Errorf("version must be pinned for the charts of repository '%s'", c.Repo)
+	}
+	if _, err := semver.StrictNewVersion(c.Version); err != nil {
+		return nil, fmt.Errorf("version '%s' is not an exact semver version: %w", c.Version, err)
+	}
+
+	// Prefer the vendored chart, as the helm pull of kustomize would.
+	vendored := filepath.Join(chartHome, fmt.Sprintf("%s-%s", c.Name, c.Version), c.Name)
+	if h.fs.IsDir(vendored) {
+		return h.loadChartDir(vendored)
+	}
+	if h.opts.NetworkPolicy == HelmNetworkOffline {
+		return nil, fmt.Errorf("chart is not vendored in '%s' and fetching it is denied by the network policy", vendored)
+	}
+	return h.fetchChart(repoURL, c.Name, c.Version)
+}
+
+// allowed returns true if the given URL is one of the allowed repositories,
+// or a path below one of them.
+func (h *helmInflater) allowed(u string) bool {
+	for _, a := range h.opts.RepositoryAllowList {
+		a = strings.TrimSuffix(a, "/")
+		if u == a || strings.HasPrefix(u, a+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchChart fetches the given chart version from the repository index,
+// and verifies its digest.
+func (h *helmInflater) fetchChart(repoURL, name, version string) (*chart.Chart, error) {
+	g := h.opts.Getter
+	if g == nil {
+		var err error
+		if g, err = getter.NewHTTPGetter(); err != nil {
+			return nil, err
+		}
+	}
+
+	indexURL := repoURL + "/index.yaml"
+	data, err := g.Get(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repository index '%s': %w", indexURL, err)
+	}
+	var index repo.IndexFile
+	if err := yaml.Unmarshal(data.Bytes(), &index); err != nil {
+		return nil, fmt.Errorf("failed to parse repository index '%s': %w", indexURL, err)
+	}
+
+	var cv *repo.ChartVersion
+	for _, v := range index.Entries[name] {
+		if v != nil && v.Metadata != nil && v.Version == version {
+			cv = v
+			break
+		}
+	}
+	if cv == nil || len(cv.URLs) == 0 {
+		return nil, fmt.Errorf("chart version '%s' not found in repository '%s'", version, repoURL)
+	}
+
+	chartURL, err := repo.ResolveReferenceURL(repoURL, cv.URLs[0])
+	if err != nil {
+		return nil, err
+	}
+	// The index can refer to archives hosted elsewhere, which must also
+	// be allowed.
+	if !h.allowed(chartURL) {
+		return nil, fmt.Errorf("chart URL '%s' is not on the allow list", chartURL)
+	}
+	archiveData, err := g.Get(chartURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart '%s': %w", chartURL, err)
+	}
+	if cv.Digest != "" {
+		if digest := fmt.Sprintf("%x", sha256.Sum256(archiveData.Bytes())); digest != cv.Digest {
+			return nil, fmt.Errorf("chart '%s' digest '%s' does not match the index digest '%s'",
+				chartURL, digest, cv.Digest)
+		}
+	}
+	return loader.LoadArchive(archiveData)
+}
+
+// loadChartDir loads the chart in the given directory of the build file
+// system.
+func (h *helmInflater) loadChartDir(dir string) (*chart.Chart, error) {
+	if !h.fs.IsDir(dir) {
+		return nil, fmt.Errorf("no chart found at '%s'", dir)
+	}
+	var files []*archive.BufferedFile
+	err := h.fs.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := h.fs.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &archive.BufferedFile{Name: filepath.ToSlash(rel), Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart '%s': %w", dir, err)
+	}
+	return loader.LoadFiles(files)
+}
+
+// chartValues returns the values of the chart, merged as the chart inflation
+// generator of kustomize does: the inline values are merged with the values
+// file according to the valuesMerge option, and the additional values files
+// are merged on top.
+func (h *helmInflater) chartValues(dirPath string, chrt *chart.Chart, c kustypes.HelmChart) (map[string]any, error) {
+	vals := chrt.Values
+	if c.ValuesFile != "" {
+		var err error
+		if vals, err = h.loadValues(dirPath, c.ValuesFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.ValuesInline) > 0 {
+		switch c.ValuesMerge {
+		case "", "override":
+			vals = loader.MergeMaps(vals, c.ValuesInline)
+		case "merge":
+			vals = loader.MergeMaps(c.ValuesInline, vals)
+		case "replace":
+			vals = c.ValuesInline
+		default:
+			return nil, fmt.Errorf("valuesMerge must be one of 'override', 'replace' or 'merge', got '%s'", c.ValuesMerge)
+		}
+	}
+
+	for _, f := range c.AdditionalValuesFiles {
+		additional, err := h.loadValues(dirPath, f)
+		if err != nil {
+			return nil, err
+		}
+		vals = loader.MergeMaps(vals, additional)
+	}
+	return vals, nil
+}
+
+// loadValues loads the values file at the given path, relative to the
+// kustomization directory.
+func (h *helmInflater) loadValues(dirPath, path string) (map[string]any, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dirPath, path)
+	}
+	data, err := h.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values file: %w", err)
+	}
+	vals := map[string]any{}
+	if err := yaml.Unmarshal(data, &vals); err != nil {
+		return nil, fmt.Errorf("failed to parse values file '%s': %w", path, err)
+	}
+	return vals, nil
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/otiai10/copy"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/getter"
+	repo "helm.sh/helm/v4/pkg/repo/v1"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fluxcd/pkg/kustomize"
+	securefs "github.com/fluxcd/pkg/kustomize/filesys"
+)
+
+// newHelmRepository serves a chart repository with the demo chart.
+func newHelmRepository(t *testing.T) *httptest.Server {
+	t.Helper()
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	chrt, err := loader.LoadDir("testdata/helmcharts/demo")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = chartutil.Save(chrt, dir)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+
+	index, err := repo.IndexDirectory(dir, server.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+	data, err := yaml.Marshal(index)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(os.WriteFile(filepath.Join(dir, "index.yaml"), data, 0o600)).To(Succeed())
+	return server
+}
+
+// recordingGetter is a getter.Getter recording the fetched URLs.
+type recordingGetter struct {
+	getter.Getter
+	urls []string
+}
+
+func (g *recordingGetter) Get(url string, opts ...getter.Option) (*bytes.Buffer, error) {
+	g.urls = append(g.urls, url)
+	return g.Getter.Get(url, opts...)
+}
+
+func writeHelmKustomization(t *testing.T, dir, charts string) {
+	t.Helper()
+	kus := fmt.Sprintf("apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nhelmCharts:\n%s", charts)
+	if err := os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(kus), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild_HelmChartInflation(t *testing.T) {
+	server := newHelmRepository(t)
+
+	tests := []struct {
+		name        string
+		charts      string
+		vendored    bool
+		opts        kustomize.HelmInflationOptions
+		wantData    map[string]string
+		wantObjects []string
+		wantFetched []string
+		wantErr     string
+	}{
+		{
+			name: "local chart",
+			charts: `- name: demo
+  releaseName: app
+  namespace: apps
+  valuesInline:
+    message: local
+`,
+			wantData:    map[string]string{"message": "local", "replicas": "1"},
+			wantObjects: []string{"ConfigMap/apps/app-demo", "Pod/apps/app-test"},
+		},
+		{
+			name: "local chart without tests",
+			charts: `- name: demo
+  releaseName: app
+  namespace: apps
+  skipTests: true
+`,
+			wantData:    map[string]string{"message": "hello", "replicas": "1"},
+			wantObjects: []string{"ConfigMap/apps/app-demo"},
+		},
+		{
+			name: "remote chart",
+			charts: fmt.Sprintf(`- name: demo
+  repo: %s
+  version: 1.0.0
+  releaseName: app
+  namespace: apps
+  skipHooks: true
+  valuesFile: values.yaml
+  valuesInline:
+    replicas: 2
+`, server.URL),
+			opts:        kustomize.HelmInflationOptions{RepositoryAllowList: []string{server.URL + "/"}},
+			wantData:    map[string]string{"message": "from-file", "replicas": "2"},
+			wantObjects: []string{"ConfigMap/apps/app-demo"},
+			wantFetched: []string{server.URL + "/index.yaml", server.URL + "/demo-1.0.0.tgz"},
+		},
+		{
+			name: "vendored chart offline",
+			charts: fmt.Sprintf(`- name: demo
+  repo: %s
+  version: 1.0.0
+  releaseName: app
+  skipHooks: true
+`, server.URL),
+			vendored: true,
+			opts: kustomize.HelmInflationOptions{
+				RepositoryAllowList: []string{server.URL},
+				NetworkPolicy:       kustomize.HelmNetworkOffline,
+			},
+			wantData:    map[string]string{"message": "hello", "replicas": "1"},
+			wantObjects: []string{"ConfigMap/default/app-demo"},
+		},
+		{
+			name: "remote chart offline",
+			charts: fmt.Sprintf(`- name: demo
+  repo: %s
+  version: 1.0.0
+`, server.URL),
+			opts: kustomize.HelmInflationOptions{
+				RepositoryAllowList: []string{server.URL},
+				NetworkPolicy:       kustomize.HelmNetworkOffline,
+			},
+			wantErr: "fetching it is denied by the network policy",
+		},
+		{
+			name: "repository not allowed",
+			charts: fmt.Sprintf(`- name: demo
+  repo: %s
+  version: 1.0.0
+`, server.URL),
+			opts:    kustomize.HelmInflationOptions{RepositoryAllowList: []string{"https://charts.example.com"}},
+			wantErr: "is not on the allow list",
+		},
+		{
+			name: "floating version",
+			charts: fmt.Sprintf(`- name: demo
+  repo: %s
+  version: ^1.0.0
+`, server.URL),
+			opts:    kustomize.HelmInflationOptions{RepositoryAllowList: []string{server.URL}},
+			wantErr: "version '^1.0.0' is not an exact semver version",
+		},
+		{
+			name: "missing version",
+			charts: fmt.Sprintf(`- name: demo
+  repo: %s
+`, server.URL),
+			opts:    kustomize.HelmInflationOptions{RepositoryAllowList: []string{server.URL}},
+			wantErr: "version must be pinned",
+		},
+		{
+			name: "unknown version",
+			charts: fmt.Sprintf(`- name: demo
+  repo: %s
+  version: 2.0.0
+`, server.URL),
+			opts:        kustomize.HelmInflationOptions{RepositoryAllowList: []string{server.URL}},
+			wantFetched: []string{server.URL + "/index.yaml"},
+			wantErr:     "chart version '2.0.0' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := t.TempDir()
+			chartHome := filepath.Join(dir, "charts", "demo")
+			if tt.vendored {
+				chartHome = filepath.Join(dir, "charts", "demo-1.0.0", "demo")
+			}
+			g.Expect(copy.Copy("testdata/helmcharts/demo", chartHome)).To(Succeed())
+			g.Expect(os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("message: from-file\n"), 0o600)).To(Succeed())
+			writeHelmKustomization(t, dir, tt.charts)
+			original, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			httpGetter, err := getter.NewHTTPGetter()
+			g.Expect(err).NotTo(HaveOccurred())
+			recorder := &recordingGetter{Getter: httpGetter}
+			tt.opts.Getter = recorder
+
+			fs, err := securefs.MakeFsOnDiskSecure(dir)
+			g.Expect(err).NotTo(HaveOccurred())
+			res, err := kustomize.Build(fs, dir, kustomize.WithHelmChartInflation(tt.opts))
+			g.Expect(recorder.urls).To(Equal(tt.wantFetched))
+
+			// The kustomization is restored, whatever the result of the build.
+			restored, readErr := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+			g.Expect(readErr).NotTo(HaveOccurred())
+			g.Expect(string(restored)).To(Equal(string(original)))
+			entries, readErr := os.ReadDir(dir)
+			g.Expect(readErr).NotTo(HaveOccurred())
+			g.Expect(entries).To(HaveLen(3))
+
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var objects []string
+			for _, r := range res.Resources() {
+				objects = append(objects, strings.Join([]string{r.GetKind(), r.GetNamespace(), r.GetName()}, "/"))
+				if r.GetKind() == "ConfigMap" {
+					g.Expect(r.GetDataMap()).To(Equal(tt.wantData))
+				}
+			}
+			g.Expect(objects).To(ConsistOf(tt.wantObjects))
+		})
+	}
+}
+
+func TestBuild_HelmChartInflationNested(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base")
+	g.Expect(copy.Copy("testdata/helmcharts/demo", filepath.Join(base, "charts", "demo"))).To(Succeed())
+	writeHelmKustomization(t, base, "- name: demo\n  releaseName: base\n  skipHooks: true\n")
+	overlay := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nnamePrefix: prod-\nresources:\n- ./base\n"
+	g.Expect(os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(overlay), 0o600)).To(Succeed())
+
+	res, err := kustomize.Build(filesys.MakeFsOnDisk(), dir,
+		kustomize.WithHelmChartInflation(kustomize.HelmInflationOptions{}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.Resources()).To(HaveLen(1))
+	g.Expect(res.Resources()[0].GetName()).To(Equal("prod-base-demo"))
+	g.Expect(filepath.Join(base, ".helm-charts.inflated.yaml")).NotTo(BeAnExistingFile())
+
+	// The inflation is disabled by default.
+	_, err = kustomize.Build(filesys.MakeFsOnDisk(), dir)
+	g.Expect(err).To(HaveOccurred())
+}
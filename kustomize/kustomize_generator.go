@@ -78,7 +78,46 @@ type Generator struct {
 	ignore        string
 	filter        bool
 	fs            filesys.FileSystem
+	customFS      bool
+	cache         BuildCache
+	maxScanErrors int
+	scanWarnings  []string
 	kustomization unstructured.Unstructured
+
+	keepLastDuplicate bool
+
+	schemaValidator        *schemaValidator
+	strictSchemaValidation bool
+	schemaWarnings         []string
+}
+
+// GeneratorOption is a function that can be used to configure a Generator.
+type GeneratorOption func(g *Generator)
+
+// WithBuildCache sets the cache used by Generator.Build to store the
+// build results.
+func WithBuildCache(c BuildCache) GeneratorOption {
+	return func(g *Generator) {
+		g.cache = c
+	}
+}
+
+// WithMaxScanErrors sets the maximum number of invalid files reported
+// by the ScanError returned when generating a kustomization file. A value
+// lower than 1 reports all the invalid files. Defaults to DefaultMaxScanErrors.
+func WithMaxScanErrors(n int) GeneratorOption {
+	return func(g *Generator) {
+		g.maxScanErrors = n
+	}
+}
+
+// WithFS sets the file system used by the Generator to read and write
+// the kustomization files.
+func WithFS(fs filesys.FileSystem) GeneratorOption {
+	return func(g *Generator) {
+		g.fs = fs
+		g.customFS = fs != nil
+	}
 }
 
 // SavingOptions is a function that can be used to apply saving options to a kustomization
@@ -87,23 +126,33 @@ type SavingOptions func(dirPath, file string, action Action) error
 // NewGenerator creates a new kustomize generator
 // It takes a root directory and a kustomization object
 // If the root is empty, no enforcement of the root directory will be done when handling paths.
-func NewGenerator(root string, kustomization unstructured.Unstructured) *Generator {
-	return &Generator{
+func NewGenerator(root string, kustomization unstructured.Unstructured, opts ...GeneratorOption) *Generator {
+	g := &Generator{
 		root:          root,
+		maxScanErrors: DefaultMaxScanErrors,
 		kustomization: kustomization,
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // NewGeneratorWithIgnore creates a new kustomize generator
 // It takes a root directory, a kustomization object and a string of files to ignore
 // The generator will combine the ignore files with the default ignore files i.e. .sourceignore
-func NewGeneratorWithIgnore(root, ignore string, kustomization unstructured.Unstructured) *Generator {
-	return &Generator{
+func NewGeneratorWithIgnore(root, ignore string, kustomization unstructured.Unstructured, opts ...GeneratorOption) *Generator {
+	g := &Generator{
 		root:          root,
 		ignore:        ignore,
 		filter:        true,
+		maxScanErrors: DefaultMaxScanErrors,
 		kustomization: kustomization,
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // WithSaveOriginalKustomization will save the original kustomization file
@@ -282,6 +331,10 @@ func (g *Generator) GenerateManifest(dirPath string) ([]byte, string, Action, er
 		return nil, "", action, fmt.Errorf("unable to get patchesJson6902: %w", err)
 	}
 
+	if err := g.validatePatches(patches, patchesSM, patchesJSON); err != nil {
+		return nil, "", action, err
+	}
+
 	for _, p := range patchesJSON {
 		patch, err := json.Marshal(p.Patch)
 		if err != nil {
@@ -503,8 +556,16 @@ func (g *Generator) getFS() (filesys.FileSystem, error) {
 	return g.fs, err
 }
 
+// ScanWarnings returns the warnings about the files skipped while scanning
+// the manifests of the last generated kustomization file, e.g. the YAML files
+// which are not Kubernetes manifests, for controllers to emit them as events.
+func (g *Generator) ScanWarnings() []string {
+	return g.scanWarnings
+}
+
 // findOrGenerateKustomization returns existing kustomization content or generates new content.
 func (g *Generator) findOrGenerateKustomization(fs filesys.FileSystem, dirPath string, ignorePatterns []gitignore.Pattern, ignoreDomain []string) ([]byte, string, Action, error) {
+	g.scanWarnings = nil
 
 	// Determine if there already is a Kustomization file at the root,
 	// as this means we do not have to generate one.
@@ -521,11 +582,24 @@ func (g *Generator) findOrGenerateKustomization(fs filesys.FileSystem, dirPath s
 		return nil, "", UnchangedAction, err
 	}
 
-	files, err := scanManifests(fs, abs, ignorePatterns, ignoreDomain)
+	index := newResourceIndex()
+	files, warnings, err := scanManifests(fs, abs, ignorePatterns, ignoreDomain, g.maxScanErrors, index)
+	g.scanWarnings = warnings
 	if err != nil {
 		return nil, "", UnchangedAction, err
 	}
 
+	// Reject the resources defined in more than one file before kustomize
+	// does, as its error does not tell the files involved.
+	if g.keepLastDuplicate {
+		var dropped []string
+		files, dropped = index.dropShadowed(abs, files)
+		g.scanWarnings = append(g.scanWarnings, dropped...)
+	}
+	if dups := index.duplicates(abs, files); len(dups) > 0 {
+		return nil, "", UnchangedAction, &DuplicateResourcesError{Duplicates: dups}
+	}
+
 	kus := kustypes.Kustomization{
 		TypeMeta: kustypes.TypeMeta{
 			APIVersion: kustypes.KustomizationVersion,
@@ -553,19 +627,24 @@ func (g *Generator) findOrGenerateKustomization(fs filesys.FileSystem, dirPath s
 
 // scanManifests walks through the given base path parsing all the files and
 // collecting a list of all the yaml file paths which can be used as
-// kustomization resources.
-func scanManifests(fs filesys.FileSystem, base string, ignorePatterns []gitignore.Pattern, ignoreDomain []string) ([]string, error) {
-	var paths []string
+// kustomization resources. The files without any Kubernetes object are
+// skipped and reported as warnings. The invalid files, including the ones
+// mixing Kubernetes objects with other documents, are reported, up to
+// maxErrors, with a ScanError once all the files are parsed. The resources
+// of the collected files are recorded in the given index, if not nil.
+func scanManifests(fs filesys.FileSystem, base string, ignorePatterns []gitignore.Pattern, ignoreDomain []string,
+	maxErrors int, index *resourceIndex) ([]string, []string, error) {
+	var paths, warnings []string
+	var scanErr ScanError
 	pvd := provider.NewDefaultDepProvider()
 	rf := pvd.GetResourceFactory()
 
-	err := fs.Walk(base, func(path string, info os.FileInfo, err error) (walkErr error) {
+	err := fs.Walk(base, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			walkErr = err
-			return
+			return err
 		}
 		if path == base {
-			return
+			return nil
 		}
 		if info.IsDir() {
 			// If a sub-directory contains an existing kustomization file add the
@@ -576,41 +655,50 @@ func scanManifests(fs filesys.FileSystem, base string, ignorePatterns []gitignor
 					return filepath.SkipDir
 				}
 			}
-			return
+			return nil
 		}
 
 		extension := filepath.Ext(path)
 		if extension != ".yaml" && extension != ".yml" {
-			return
+			return nil
 		}
 
 		if shouldIgnoreFile(path, ignorePatterns, ignoreDomain) {
-			return
+			return nil
 		}
 
 		fContents, err := fs.ReadFile(path)
 		if err != nil {
-			walkErr = err
-			return
+			return err
 		}
 
-		// Kustomize YAML parser tends to panic in unpredicted ways due to
-		// (accidental) invalid object data; recover when this happens to ensure
-		// continuity of operations.
-		defer func() {
-			if r := recover(); r != nil {
-				walkErr = fmt.Errorf("recovered from panic while parsing YAML file %s: %v", filepath.Base(path), r)
-			}
-		}()
-
-		if _, err := rf.SliceFromBytes(fContents); err != nil {
-			walkErr = fmt.Errorf("failed to decode Kubernetes YAML from %s: %w", path, err)
-			return
+		relPath, err := filepath.Rel(base, path)
+		if err != nil {
+			relPath = path
 		}
-		paths = append(paths, path)
-		return
+		ids, line, skip, manifestErr := parseManifest(rf, relPath, fContents)
+		switch {
+		case manifestErr != nil:
+			scanErr.add(manifestErr, maxErrors)
+		case skip:
+			warnings = append(warnings, (&ManifestError{
+				Path:    relPath,
+				Line:    line,
+				Message: "skipping non-Kubernetes YAML file, missing apiVersion or kind",
+			}).Error())
+		default:
+			paths = append(paths, path)
+			index.add(path, ids)
+		}
+		return nil
 	})
-	return paths, err
+	if err != nil {
+		return paths, warnings, err
+	}
+	if len(scanErr.Errors) > 0 {
+		return paths, warnings, &scanErr
+	}
+	return paths, warnings, nil
 }
 
 func adaptSelector(selector *kustomize.Selector) (output *kustypes.Selector) {
@@ -632,31 +720,73 @@ var kustomizeBuildMutex sync.Mutex
 
 // Secure Build wraps krusty.MakeKustomizer with the following settings:
 //   - secure on-disk FS denying operations outside root
-//   - load files from outside the kustomization dir path
-//     (but not outside root)
+//   - load files only from the kustomization dir path
 //   - disable plugins except for the builtin ones
-func SecureBuild(root, dirPath string, allowRemoteBases bool) (res resmap.ResMap, err error) {
+//
+// The defaults can be changed per build with WithLoadRestrictions and
+// WithPluginConfig.
+func SecureBuild(root, dirPath string, allowRemoteBases bool, opts ...BuildOption) (res resmap.ResMap, err error) {
 	var fs filesys.FileSystem
 
+	// The load root, if any, widens the root of the secure FS
+	fsRoot, err := newBuildConfig(opts...).fsRoot(root)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create secure FS for root with or without remote base support
 	if allowRemoteBases {
-		fs, err = securefs.MakeFsOnDiskSecureBuild(root)
+		fs, err = securefs.MakeFsOnDiskSecureBuild(fsRoot)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		fs, err = securefs.MakeFsOnDiskSecure(root)
+		fs, err = securefs.MakeFsOnDiskSecure(fsRoot)
 		if err != nil {
 			return nil, err
 		}
 	}
-	return Build(fs, dirPath)
+	return Build(fs, dirPath, opts...)
 }
 
 // Build wraps krusty.MakeKustomizer with the following settings:
-// - load files from outside the kustomization.yaml root
+// - load files only from the kustomization.yaml root
 // - disable plugins except for the builtin ones
-func Build(fs filesys.FileSystem, dirPath string) (res resmap.ResMap, err error) {
+//
+// The defaults can be changed per build with WithLoadRestrictions and
+// WithPluginConfig, the Helm charts can be inflated with
+// WithHelmChartInflation, the resources can be labeled and annotated with
+// WithOwnershipLabels and WithOriginAnnotations, and the output can be
+// normalized with WithStableOrder.
+func Build(fs filesys.FileSystem, dirPath string, opts ...BuildOption) (res resmap.ResMap, err error) {
+	config := newBuildConfig(opts...)
+
+	// Inflate the Helm charts before acquiring the build lock, as the
+	// charts may have to be fetched.
+	if config.helm != nil {
+		inflater := newHelmInflater(fs, *config.helm)
+		defer func() {
+			if restoreErr := inflater.restore(); restoreErr != nil {
+				err = errors.Join(err, restoreErr)
+			}
+		}()
+		if err := inflater.inflate(dirPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.originAnnotations {
+		restore, err := enableOriginAnnotations(fs, dirPath)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if restoreErr := restore(); restoreErr != nil {
+				err = errors.Join(err, restoreErr)
+			}
+		}()
+	}
+
 	// temporary workaround for concurrent map read and map write bug
 	// https://github.com/kubernetes-sigs/kustomize/issues/3659
 	kustomizeBuildMutex.Lock()
@@ -671,10 +801,7 @@ func Build(fs filesys.FileSystem, dirPath string) (res resmap.ResMap, err error)
 		}
 	}()
 
-	buildOptions := &krusty.Options{
-		LoadRestrictions: kustypes.LoadRestrictionsNone,
-		PluginConfig:     kustypes.DisabledPluginConfig(),
-	}
+	buildOptions := config.krustyOptions()
 
 	// Reset the global OpenAPI schema to ensure each build is isolated.
 	// This prevents a custom openapi configuration in one Kustomization
@@ -683,7 +810,22 @@ func Build(fs filesys.FileSystem, dirPath string) (res resmap.ResMap, err error)
 	openapi.ResetOpenAPI()
 
 	k := krusty.MakeKustomizer(buildOptions)
-	return k.Run(fs, dirPath)
+	res, err = k.Run(fs, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	if config.ownership != nil {
+		if err := config.ownership.addOwnershipLabels(res); err != nil {
+			return nil, err
+		}
+	}
+	if !config.stableOrder {
+		return res, nil
+	}
+	if err := res.ApplyFilter(stableOrderFilter{}); err != nil {
+		return nil, fmt.Errorf("failed to normalize the build output: %w", err)
+	}
+	return res, nil
 }
 
 // CleanDirectory removes the kustomization.yaml file from the given directory.
@@ -17,6 +17,7 @@ limitations under the License.
 package kustomize_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -82,6 +83,114 @@ func TestGenerator_NoResources(t *testing.T) {
 	g.Expect(string(data)).To(ContainSubstring("originAnnotations"))
 }
 
+func TestGenerator_ScanErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	tmpDir, err := testTempDir(t)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(copy.Copy("testdata/nokustomization/scanerrors", tmpDir)).To(Succeed())
+
+	gen := kustomize.NewGenerator(tmpDir, unstructured.Unstructured{}, kustomize.WithMaxScanErrors(1))
+	_, err = gen.WriteFile(tmpDir)
+	g.Expect(err).To(MatchError("found 2 invalid YAML files: " +
+		"apps/deployment.yaml:10: found a tab character that violates indentation (and 1 more)"))
+	g.Expect(gen.ScanWarnings()).To(ConsistOf(
+		"values.yaml:1: skipping non-Kubernetes YAML file, missing apiVersion or kind",
+	))
+
+	g.Expect(os.Remove(filepath.Join(tmpDir, "broken.yaml"))).To(Succeed())
+	g.Expect(os.Remove(filepath.Join(tmpDir, "apps", "deployment.yaml"))).To(Succeed())
+	_, err = gen.WriteFile(tmpDir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gen.ScanWarnings()).To(HaveLen(1))
+
+	resMap, err := kustomize.SecureBuild(tmpDir, tmpDir, false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resMap.Resources()).To(HaveLen(1))
+}
+
+func TestGenerator_DuplicateResources(t *testing.T) {
+	const cmID = "ConfigMap.v1.[noGrp]/app.default"
+
+	for _, tt := range []struct {
+		name      string
+		keepLast  bool
+		extraFile string
+		err       string
+		warnings  []string
+	}{
+		{
+			name: "rejects the duplicates across directories",
+			err: "found 1 resources defined in more than one file: " +
+				cmID + " in base/configmap.yaml, overlay/configmap.yaml",
+		},
+		{
+			name:     "keeps the last duplicate",
+			keepLast: true,
+			warnings: []string{
+				"base/configmap.yaml: skipping file, all its resources are defined again in overlay/configmap.yaml",
+			},
+		},
+		{
+			name:     "rejects the duplicates of files defining other resources",
+			keepLast: true,
+			extraFile: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+`,
+			err: "found 1 resources defined in more than one file: " +
+				cmID + " in aa/all.yaml, overlay/configmap.yaml",
+			warnings: []string{
+				"base/configmap.yaml: skipping file, all its resources are defined again in overlay/configmap.yaml",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tmpDir, err := testTempDir(t)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(copy.Copy("testdata/duplicates", tmpDir)).To(Succeed())
+			if tt.extraFile != "" {
+				g.Expect(os.MkdirAll(filepath.Join(tmpDir, "aa"), 0o755)).To(Succeed())
+				g.Expect(os.WriteFile(filepath.Join(tmpDir, "aa", "all.yaml"), []byte(tt.extraFile), 0o644)).To(Succeed())
+			}
+
+			var opts []kustomize.GeneratorOption
+			if tt.keepLast {
+				opts = append(opts, kustomize.WithDuplicateResourcesKeepLast())
+			}
+			gen := kustomize.NewGenerator(tmpDir, unstructured.Unstructured{}, opts...)
+			_, err = gen.WriteFile(tmpDir)
+			g.Expect(gen.ScanWarnings()).To(Equal(tt.warnings))
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(tt.err))
+				var dupErr *kustomize.DuplicateResourcesError
+				g.Expect(errors.As(err, &dupErr)).To(BeTrue())
+				g.Expect(dupErr.Duplicates).To(HaveLen(1))
+				g.Expect(dupErr.Duplicates[0].ID).To(Equal(cmID))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			resMap, err := kustomize.SecureBuild(tmpDir, tmpDir, false)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resMap.Resources()).To(HaveLen(2))
+			for _, res := range resMap.Resources() {
+				if res.GetKind() == "ConfigMap" {
+					g.Expect(res.GetDataMap()).To(HaveKeyWithValue("env", "overlay"))
+				}
+			}
+		})
+	}
+}
+
 func TestGenerator_NameTransformer(t *testing.T) {
 	g := NewWithT(t)
 	dataKS, err := os.ReadFile("./testdata/name/ks.yaml")
@@ -187,8 +296,112 @@ func Test_SecureBuild_panic(t *testing.T) {
 func Test_SecureBuild_rel_basedir(t *testing.T) {
 	g := NewWithT(t)
 
-	_, err := kustomize.SecureBuild("testdata/relbase", "testdata/relbase/clusters/staging/flux-system", false)
+	_, err := kustomize.SecureBuild("testdata/relbase", "testdata/relbase/clusters/staging/flux-system", false,
+		kustomize.WithLoadRestrictions(kustypes.LoadRestrictionsNone))
 	g.Expect(err).ToNot(HaveOccurred())
+
+	// The relative bases are outside of the kustomization root.
+	_, err = kustomize.SecureBuild("testdata/relbase", "testdata/relbase/clusters/staging/flux-system", false)
+	g.Expect(err).To(MatchError(ContainSubstring("security; file")))
+}
+
+func Test_SecureBuild_LoadRestrictions(t *testing.T) {
+	// The kustomization patches the config map with a file outside of its directory.
+	const appDir = "testdata/loadrestrictions/app"
+
+	tests := []struct {
+		name         string
+		root         string
+		opts         []kustomize.BuildOption
+		wantErr      string
+		wantWarnings []string
+	}{
+		{
+			name:    "default denies files outside the kustomization root",
+			root:    "testdata/loadrestrictions",
+			wantErr: "security; file",
+		},
+		{
+			name:    "root only denies files outside the kustomization root",
+			root:    "testdata/loadrestrictions",
+			opts:    []kustomize.BuildOption{kustomize.WithLoadRestrictions(kustypes.LoadRestrictionsRootOnly)},
+			wantErr: "security; file",
+		},
+		{
+			name:         "none allows files outside the kustomization root",
+			root:         "testdata/loadrestrictions",
+			opts:         []kustomize.BuildOption{kustomize.WithLoadRestrictions(kustypes.LoadRestrictionsNone)},
+			wantWarnings: []string{kustomize.UnrestrictedLoadingWarning},
+		},
+		{
+			name:         "none denies files outside the root",
+			root:         appDir,
+			opts:         []kustomize.BuildOption{kustomize.WithLoadRestrictions(kustypes.LoadRestrictionsNone)},
+			wantErr:      "fs-security-constraint",
+			wantWarnings: []string{kustomize.UnrestrictedLoadingWarning},
+		},
+		{
+			name: "load root allows files outside the root",
+			root: appDir,
+			opts: []kustomize.BuildOption{
+				kustomize.WithLoadRestrictions(kustypes.LoadRestrictionsNone),
+				kustomize.WithLoadRoot("testdata/loadrestrictions"),
+			},
+			wantWarnings: []string{kustomize.UnrestrictedLoadingWarning},
+		},
+		{
+			name:         "load root must contain the root",
+			root:         "testdata/loadrestrictions",
+			opts:         []kustomize.BuildOption{kustomize.WithLoadRoot(appDir)},
+			wantErr:      "is not within the load root",
+			wantWarnings: []string{kustomize.UnrestrictedLoadingWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(kustomize.Warnings(tt.opts...)).To(Equal(tt.wantWarnings))
+
+			resMap, err := kustomize.SecureBuild(tt.root, appDir, false, tt.opts...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(resMap.Resources()).To(HaveLen(1))
+			data, err := resMap.Resources()[0].GetFieldValue("data.key")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(data).To(Equal("patched"))
+		})
+	}
+}
+
+func Test_Build_PluginConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	tmpDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(tmpDir, "kustomization.yaml"), []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+helmCharts:
+  - name: podinfo
+    repo: https://stefanprodan.github.io/podinfo
+    version: 6.0.0
+`), 0o600)).To(Succeed())
+
+	// The Helm chart inflation generator is disabled by default.
+	_, err := kustomize.Build(filesys.MakeFsOnDisk(), tmpDir)
+	g.Expect(err).To(MatchError(ContainSubstring("must specify --enable-helm")))
+
+	// An enabled plugin config is used for this build only.
+	pc := kustypes.EnabledPluginConfig(kustypes.BploUseStaticallyLinked)
+	pc.HelmConfig.Command = "helm-does-not-exist"
+	_, err = kustomize.Build(filesys.MakeFsOnDisk(), tmpDir, kustomize.WithPluginConfig(pc))
+	g.Expect(err).To(MatchError(ContainSubstring("helm-does-not-exist")))
+
+	_, err = kustomize.Build(filesys.MakeFsOnDisk(), tmpDir)
+	g.Expect(err).To(MatchError(ContainSubstring("must specify --enable-helm")))
 }
 
 func Test_Components(t *testing.T) {
@@ -17,6 +17,7 @@ limitations under the License.
 package kustomize
 
 import (
+	"errors"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -61,7 +62,7 @@ func TestScanManifests(t *testing.T) {
 			g := NewWithT(t)
 			fs := filesys.MakeFsOnDisk()
 
-			paths, err := scanManifests(fs, tt.base, nil, nil)
+			paths, _, err := scanManifests(fs, tt.base, nil, nil, DefaultMaxScanErrors, nil)
 			g.Expect(paths).To(Equal(tt.wantPaths))
 			g.Expect(err != nil).To(Equal(tt.wantErr))
 		})
@@ -74,6 +75,7 @@ func TestScanManifests_WithIgnorePatterns(t *testing.T) {
 		base           string
 		ignorePatterns string
 		wantPaths      []string
+		wantWarnings   []string
 		wantErr        bool
 	}{
 		{
@@ -82,10 +84,13 @@ func TestScanManifests_WithIgnorePatterns(t *testing.T) {
 			wantPaths: []string{"testdata/ignore-tests/basic/deployment.yaml"},
 		},
 		{
-			name:           "with .sops.yaml - no ignore patterns (should fail)",
+			name:           "with .sops.yaml - no ignore patterns (should warn)",
 			base:           "./testdata/ignore-tests/with-sops",
 			ignorePatterns: "",
-			wantErr:        true, // .sops.yaml should cause parsing error
+			wantPaths:      []string{"testdata/ignore-tests/with-sops/deployment.yaml"},
+			wantWarnings: []string{
+				".sops.yaml:1: skipping non-Kubernetes YAML file, missing apiVersion or kind",
+			},
 		},
 		{
 			name:           "with .sops.yaml - ignore .sops.yaml",
@@ -94,10 +99,13 @@ func TestScanManifests_WithIgnorePatterns(t *testing.T) {
 			wantPaths:      []string{"testdata/ignore-tests/with-sops/deployment.yaml"},
 		},
 		{
-			name:           "with .gitlab-ci.yml - no ignore patterns (should fail)",
+			name:           "with .gitlab-ci.yml - no ignore patterns (should warn)",
 			base:           "./testdata/ignore-tests/with-gitlab-ci",
 			ignorePatterns: "",
-			wantErr:        true, // .gitlab-ci.yml should cause parsing error
+			wantPaths:      []string{"testdata/ignore-tests/with-gitlab-ci/deployment.yaml"},
+			wantWarnings: []string{
+				".gitlab-ci.yml:1: skipping non-Kubernetes YAML file, missing apiVersion or kind",
+			},
 		},
 		{
 			name:           "with .gitlab-ci.yml - ignore .gitlab-ci.yml",
@@ -131,7 +139,7 @@ func TestScanManifests_WithIgnorePatterns(t *testing.T) {
 					sourceignore.ReadPatterns(strings.NewReader(tt.ignorePatterns), ignoreDomain)...)
 			}
 
-			paths, err := scanManifests(fs, tt.base, ignorePatterns, ignoreDomain)
+			paths, warnings, err := scanManifests(fs, tt.base, ignorePatterns, ignoreDomain, DefaultMaxScanErrors, nil)
 
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
@@ -142,6 +150,73 @@ func TestScanManifests_WithIgnorePatterns(t *testing.T) {
 			sort.Strings(paths)
 			sort.Strings(tt.wantPaths)
 			g.Expect(paths).To(Equal(tt.wantPaths))
+			g.Expect(warnings).To(Equal(tt.wantWarnings))
+		})
+	}
+}
+
+func TestScanManifests_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxErrors   int
+		wantErrors  []string
+		wantOmitted int
+	}{
+		{
+			name:      "all errors",
+			maxErrors: DefaultMaxScanErrors,
+			wantErrors: []string{
+				"apps/deployment.yaml:10: found a tab character that violates indentation",
+				"broken.yaml:7: found unexpected end of stream",
+			},
+		},
+		{
+			name:      "max errors",
+			maxErrors: 1,
+			wantErrors: []string{
+				"apps/deployment.yaml:10: found a tab character that violates indentation",
+			},
+			wantOmitted: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fs := filesys.MakeFsOnDisk()
+
+			paths, warnings, err := scanManifests(fs, "testdata/nokustomization/scanerrors", nil, nil, tt.maxErrors, nil)
+			g.Expect(paths).To(Equal([]string{"testdata/nokustomization/scanerrors/configmap.yaml"}))
+			g.Expect(warnings).To(Equal([]string{
+				"values.yaml:1: skipping non-Kubernetes YAML file, missing apiVersion or kind",
+			}))
+
+			var scanErr *ScanError
+			g.Expect(errors.As(err, &scanErr)).To(BeTrue())
+			var msgs []string
+			for _, e := range scanErr.Errors {
+				msgs = append(msgs, e.Error())
+			}
+			g.Expect(msgs).To(Equal(tt.wantErrors))
+			g.Expect(scanErr.Omitted).To(Equal(tt.wantOmitted))
+			g.Expect(err.Error()).To(HavePrefix("found 2 invalid YAML files: apps/deployment.yaml:10: "))
 		})
 	}
 }
+
+func TestScanManifests_MultiDocuments(t *testing.T) {
+	g := NewWithT(t)
+	fs := filesys.MakeFsOnDisk()
+
+	paths, warnings, err := scanManifests(fs, "testdata/nokustomization/scanmixed", nil, nil, DefaultMaxScanErrors, nil)
+	g.Expect(paths).To(Equal([]string{"testdata/nokustomization/scanmixed/configmap.yaml"}))
+	g.Expect(warnings).To(Equal([]string{
+		"values.yaml:1: skipping non-Kubernetes YAML file, missing apiVersion or kind",
+	}))
+
+	var scanErr *ScanError
+	g.Expect(errors.As(err, &scanErr)).To(BeTrue())
+	g.Expect(scanErr.Errors).To(HaveLen(1))
+	g.Expect(scanErr.Errors[0].Error()).To(Equal(
+		"mixed.yaml:9: document is not a Kubernetes object, missing apiVersion or kind"))
+}
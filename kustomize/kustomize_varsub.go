@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/kustomize/api/resource"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
 	"sigs.k8s.io/yaml"
 
 	"github.com/fluxcd/pkg/envsubst"
@@ -40,6 +41,10 @@ const (
 	// the var names before substitution
 	varsubRegex   = "^[_[:alpha:]][_[:alpha:][:digit:]]*$"
 	DisabledValue = "disabled"
+
+	// BuiltinVarPrefix is the reserved prefix of the names of the built-in
+	// variables, e.g. FLUX_KS_NAME or FLUX_SOURCE_REVISION.
+	BuiltinVarPrefix = "FLUX_"
 )
 
 const (
@@ -54,6 +59,11 @@ type SubstituteOptions struct {
 	DryRun bool
 	Strict bool
 	Always bool
+
+	BuiltinVars         map[string]string
+	OverrideBuiltinVars bool
+
+	LegacyTextMode bool
 }
 
 type SubstituteOption func(a *SubstituteOptions)
@@ -85,9 +95,59 @@ func SubstituteWithAlways(always bool) SubstituteOption {
 	}
 }
 
+// SubstituteWithBuiltinVars sets the built-in vars, which are well-known values
+// provided by the caller about the object being reconciled, e.g. its name,
+// namespace or source revision. The names of the built-in vars must start with
+// BuiltinVarPrefix. The built-in vars take precedence over the vars with the
+// same name from the substituteFrom ConfigMaps and Secrets and from the in-line
+// substitute map, unless SubstituteWithOverrideBuiltinVars is set.
+// The built-in vars are available in dryRun mode and enable the substitution
+// for the Kustomizations with a postBuild section even if it declares no vars.
+func SubstituteWithBuiltinVars(vars map[string]string) SubstituteOption {
+	return func(a *SubstituteOptions) {
+		a.BuiltinVars = vars
+	}
+}
+
+// SubstituteWithOverrideBuiltinVars sets the overrideBuiltinVars option.
+// When overrideBuiltinVars is true, the vars from the substituteFrom ConfigMaps
+// and Secrets and from the in-line substitute map take precedence over the
+// built-in vars with the same name.
+func SubstituteWithOverrideBuiltinVars(override bool) SubstituteOption {
+	return func(a *SubstituteOptions) {
+		a.OverrideBuiltinVars = override
+	}
+}
+
+// SubstituteWithLegacyTextMode sets the legacyTextMode option.
+// When legacyTextMode is true, the vars are substituted in the YAML text of
+// the resource, which is then parsed again, instead of in its scalar values.
+// The newlines of the values are removed, as they would corrupt the YAML.
+func SubstituteWithLegacyTextMode(legacy bool) SubstituteOption {
+	return func(a *SubstituteOptions) {
+		a.LegacyTextMode = legacy
+	}
+}
+
 // SubstituteVariables replaces the vars with their values in the specified resource.
 // If a resource is labeled or annotated with
 // 'kustomize.toolkit.fluxcd.io/substitute: disabled' the substitution is skipped.
+//
+// The vars are merged in the following order of precedence, from lowest to
+// highest: the substituteFrom ConfigMaps and Secrets in the order they are
+// listed, the in-line substitute map, and the built-in vars. When the
+// overrideBuiltinVars option is set, the built-in vars have the lowest
+// precedence instead.
+//
+// The vars are substituted in the scalar values and keys of the resource, so
+// that the values cannot corrupt its structure, and the multi-line values,
+// e.g. PEM certificates, are kept as is, apart from their trailing newlines.
+// The values substituted in a quoted or block scalar are always strings,
+// while the ones substituted in a plain scalar are parsed as YAML, as a bool,
+// a number, a flow collection or a string, e.g. 'true' is a bool and '"true"'
+// is a string. The scalars substituted with an empty value are null, as with
+// the text substitution. See SubstituteWithLegacyTextMode for the text
+// substitution.
 func SubstituteVariables(
 	ctx context.Context,
 	kubeClient client.Client,
@@ -99,11 +159,6 @@ func SubstituteVariables(
 		o(&options)
 	}
 
-	resData, err := res.AsYAML()
-	if err != nil {
-		return nil, err
-	}
-
 	if res.GetLabels()[substituteAnnotationKey] == DisabledValue || res.GetAnnotations()[substituteAnnotationKey] == DisabledValue {
 		return nil, nil
 	}
@@ -112,8 +167,9 @@ func SubstituteVariables(
 	// In dryRun mode this step is skipped. This might in different kind of errors.
 	// But if the user is using dryRun, he/she should know what he/she is doing, and we should comply.
 	var vars map[string]string
+	var err error
 	if !options.DryRun {
-		vars, err = LoadVariables(ctx, kubeClient, kustomization)
+		vars, err = loadVariables(ctx, kubeClient, kustomization, options.LegacyTextMode)
 		if err != nil {
 			return nil, err
 		}
@@ -129,12 +185,31 @@ func SubstituteVariables(
 			vars = make(map[string]string)
 		}
 		for k, v := range substitute {
-			vars[k] = strings.ReplaceAll(v, "\n", "")
+			vars[k] = normalizeVar(v, options.LegacyTextMode)
+		}
+	}
+
+	// merge the built-in vars, on their own they enable the substitution
+	// only for Kustomizations with a postBuild section
+	enabled := len(vars) > 0 || options.Always
+	if len(options.BuiltinVars) > 0 {
+		_, hasPostBuild, err := unstructured.NestedFieldNoCopy(kustomization.Object, specField, postBuildField)
+		if err != nil {
+			return nil, err
+		}
+		vars, err = mergeBuiltinVars(vars, options.BuiltinVars, options.OverrideBuiltinVars, options.LegacyTextMode)
+		if err != nil {
+			return nil, err
 		}
+		enabled = enabled || hasPostBuild
 	}
 
 	// run bash variable substitutions
-	if len(vars) > 0 || options.Always {
+	if enabled && options.LegacyTextMode {
+		resData, err := res.AsYAML()
+		if err != nil {
+			return nil, err
+		}
 		jsonData, err := varSubstitution(resData, vars, options.Strict)
 		if err != nil {
 			return nil, fmt.Errorf("envsubst error: %w", err)
@@ -143,6 +218,10 @@ func SubstituteVariables(
 		if err != nil {
 			return nil, fmt.Errorf("UnmarshalJSON: %w", err)
 		}
+	} else if enabled {
+		if err := varSubstitutionNode(res.YNode(), vars, options.Strict); err != nil {
+			return nil, fmt.Errorf("envsubst error: %w", err)
+		}
 	}
 
 	return res, nil
@@ -150,7 +229,14 @@ func SubstituteVariables(
 
 // LoadVariables reads the in-line variables set in the Flux Kustomization and merges them with
 // the vars referred in ConfigMaps and Secrets data keys.
+// The newlines of the values are removed, as for the legacy text substitution.
 func LoadVariables(ctx context.Context, kubeClient client.Client, kustomization unstructured.Unstructured) (map[string]string, error) {
+	return loadVariables(ctx, kubeClient, kustomization, true)
+}
+
+// loadVariables reads the vars referred in the ConfigMaps and Secrets data
+// keys, normalized with normalizeVar.
+func loadVariables(ctx context.Context, kubeClient client.Client, kustomization unstructured.Unstructured, legacy bool) (map[string]string, error) {
 	vars := make(map[string]string)
 	substituteFrom, err := getSubstituteFrom(kustomization)
 	if err != nil {
@@ -169,7 +255,7 @@ func LoadVariables(ctx context.Context, kubeClient client.Client, kustomization
 				return nil, fmt.Errorf("substitute from 'ConfigMap/%s' error: %w", reference.Name, err)
 			}
 			for k, v := range cm.Data {
-				vars[k] = strings.ReplaceAll(v, "\n", "")
+				vars[k] = normalizeVar(v, legacy)
 			}
 		case "Secret":
 			secret := &corev1.Secret{}
@@ -180,7 +266,7 @@ func LoadVariables(ctx context.Context, kubeClient client.Client, kustomization
 				return nil, fmt.Errorf("substitute from 'Secret/%s' error: %w", reference.Name, err)
 			}
 			for k, v := range secret.Data {
-				vars[k] = strings.ReplaceAll(string(v), "\n", "")
+				vars[k] = normalizeVar(string(v), legacy)
 			}
 		}
 	}
@@ -188,21 +274,63 @@ func LoadVariables(ctx context.Context, kubeClient client.Client, kustomization
 	return vars, nil
 }
 
-func varSubstitution(data []byte, vars map[string]string, strict bool) ([]byte, error) {
+// mergeBuiltinVars returns the union of the given vars and built-in vars.
+// The built-in vars take precedence unless override is true.
+func mergeBuiltinVars(vars, builtinVars map[string]string, override, legacy bool) (map[string]string, error) {
+	merged := make(map[string]string, len(vars)+len(builtinVars))
+	for k, v := range builtinVars {
+		if !strings.HasPrefix(k, BuiltinVarPrefix) {
+			return nil, fmt.Errorf("'%s' built-in var name is invalid, must start with '%s'", k, BuiltinVarPrefix)
+		}
+		merged[k] = normalizeVar(v, legacy)
+	}
+	for k, v := range vars {
+		if _, ok := merged[k]; ok && !override {
+			continue
+		}
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+// normalizeVar removes the newlines of the value of a var for the legacy text
+// substitution, or its trailing newlines, as the shell command substitution
+// does, e.g. of the files of a Secret.
+func normalizeVar(v string, legacy bool) string {
+	if legacy {
+		return strings.ReplaceAll(v, "\n", "")
+	}
+	return strings.TrimRight(v, "\n")
+}
+
+// validateVarNames returns an error if a var name does not match varsubRegex.
+func validateVarNames(vars map[string]string) error {
 	r, _ := regexp.Compile(varsubRegex)
 	for v := range vars {
 		if !r.MatchString(v) {
-			return nil, fmt.Errorf("'%s' var name is invalid, must match '%s'", v, varsubRegex)
+			return fmt.Errorf("'%s' var name is invalid, must match '%s'", v, varsubRegex)
 		}
 	}
+	return nil
+}
 
-	output, err := envsubst.Eval(string(data), func(s string) (string, bool) {
+// evalVars substitutes the vars in the given string.
+func evalVars(s string, vars map[string]string, strict bool) (string, error) {
+	return envsubst.Eval(s, func(s string) (string, bool) {
 		if strict {
 			v, exists := vars[s]
 			return v, exists
 		}
 		return vars[s], true
 	})
+}
+
+func varSubstitution(data []byte, vars map[string]string, strict bool) ([]byte, error) {
+	if err := validateVarNames(vars); err != nil {
+		return nil, err
+	}
+
+	output, err := evalVars(string(data), vars, strict)
 	if err != nil {
 		return nil, fmt.Errorf("variable substitution failed: %w", err)
 	}
@@ -215,6 +343,102 @@ func varSubstitution(data []byte, vars map[string]string, strict bool) ([]byte,
 	return jsonData, nil
 }
 
+// varSubstitutionNode substitutes the vars in the scalar values and keys of
+// the given node tree, in place.
+func varSubstitutionNode(node *kyaml.Node, vars map[string]string, strict bool) error {
+	if err := validateVarNames(vars); err != nil {
+		return err
+	}
+	return walkScalars(node, false, func(n *kyaml.Node, isKey bool) error {
+		value, err := evalVars(n.Value, vars, strict)
+		if err != nil {
+			return fmt.Errorf("variable substitution failed: %w", err)
+		}
+		if value != n.Value {
+			setScalarValue(n, value, isKey)
+		}
+		return nil
+	})
+}
+
+// walkScalars calls fn for each scalar node of the tree, telling whether it
+// is the key of a mapping. The aliases are not followed.
+func walkScalars(node *kyaml.Node, isKey bool, fn func(n *kyaml.Node, isKey bool) error) error {
+	switch node.Kind {
+	case kyaml.ScalarNode:
+		return fn(node, isKey)
+	case kyaml.MappingNode:
+		for i, n := range node.Content {
+			if err := walkScalars(n, i%2 == 0, fn); err != nil {
+				return err
+			}
+		}
+	case kyaml.DocumentNode, kyaml.SequenceNode:
+		for _, n := range node.Content {
+			if err := walkScalars(n, false, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setScalarValue sets the substituted value of the scalar node. The empty
+// values are null, as with the text substitution, apart from the keys. The
+// quoted and block scalars keep their style and are strings. The multi-line
+// values of the plain scalars become literal block strings, and the other
+// values are parsed as YAML, the keys being always strings.
+func setScalarValue(n *kyaml.Node, value string, isKey bool) {
+	if value == "" && !isKey {
+		// an empty value is null whatever the style, as in the YAML text
+		n.Value, n.Tag, n.Style = "", kyaml.NodeTagNull, 0
+		return
+	}
+	if n.Style&(kyaml.DoubleQuotedStyle|kyaml.SingleQuotedStyle|kyaml.LiteralStyle|kyaml.FoldedStyle) != 0 {
+		n.Value, n.Tag = value, kyaml.NodeTagString
+		return
+	}
+	if strings.Contains(value, "\n") {
+		n.Value, n.Tag, n.Style = value, kyaml.NodeTagString, kyaml.LiteralStyle
+		return
+	}
+
+	if strings.TrimSpace(value) == "" {
+		// a blank value is null, as in the YAML text
+		n.Value, n.Tag, n.Style = "", kyaml.NodeTagNull, 0
+		if isKey {
+			n.Tag = kyaml.NodeTagString
+		}
+		return
+	}
+
+	// the values parsed with comments, e.g. '#fff', are strings
+	var doc kyaml.Node
+	err := kyaml.Unmarshal([]byte(value), &doc)
+	if err != nil || len(doc.Content) != 1 || hasComments(&doc) || hasComments(doc.Content[0]) {
+		n.Value, n.Tag, n.Style = value, kyaml.NodeTagString, kyaml.DoubleQuotedStyle
+		return
+	}
+
+	parsed := doc.Content[0]
+	switch {
+	case parsed.Kind == kyaml.ScalarNode:
+		n.Value, n.Tag, n.Style = parsed.Value, parsed.Tag, parsed.Style
+		if isKey {
+			n.Tag = kyaml.NodeTagString
+		}
+	case !isKey && parsed.Kind != kyaml.AliasNode && parsed.Style&kyaml.FlowStyle != 0:
+		n.Kind, n.Tag, n.Style, n.Value, n.Content = parsed.Kind, parsed.Tag, parsed.Style, "", parsed.Content
+	default:
+		n.Value, n.Tag, n.Style = value, kyaml.NodeTagString, kyaml.DoubleQuotedStyle
+	}
+}
+
+// hasComments returns true if the node has a comment.
+func hasComments(n *kyaml.Node) bool {
+	return n.HeadComment != "" || n.LineComment != "" || n.FootComment != ""
+}
+
 func getSubstituteFrom(kustomization unstructured.Unstructured) ([]SubstituteReference, error) {
 	substituteFrom, ok, err := unstructured.NestedSlice(kustomization.Object, specField, postBuildField, substituteFromField)
 	if err != nil {
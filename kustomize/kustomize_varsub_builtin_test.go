@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/fluxcd/pkg/kustomize"
+)
+
+func TestKustomization_Varsub_BuiltinVars(t *testing.T) {
+	builtinVars := map[string]string{
+		"FLUX_KS_NAME":         "app",
+		"FLUX_KS_NAMESPACE":    "apps",
+		"FLUX_SOURCE_REVISION": "main@sha1:6f4b3d1a9c2e7a10",
+	}
+
+	// The ConfigMap attempts to shadow a built-in var and defines
+	// a var with the reserved prefix which is not a built-in.
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "shadow-vars", Namespace: "apps"},
+		Data: map[string]string{
+			"FLUX_KS_NAME": "from-configmap",
+			"FLUX_CUSTOM":  "from-configmap",
+			"cluster_env":  "prod",
+		},
+	}
+	kubeClient := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	newKustomization := func(postBuild map[string]interface{}) unstructured.Unstructured {
+		spec := map[string]interface{}{
+			"interval": "5m",
+			"path":     "./",
+		}
+		if postBuild != nil {
+			spec["postBuild"] = postBuild
+		}
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+			"kind":       "Kustomization",
+			"metadata": map[string]interface{}{
+				"name":      "app",
+				"namespace": "apps",
+			},
+			"spec": spec,
+		}}
+	}
+
+	substituteFrom := []interface{}{
+		map[string]interface{}{"kind": "ConfigMap", "name": "shadow-vars"},
+	}
+
+	tests := []struct {
+		name       string
+		postBuild  map[string]interface{}
+		opts       []kustomize.SubstituteOption
+		wantData   map[string]string
+		wantLabels map[string]string
+		wantErr    string
+	}{
+		{
+			name:      "built-in vars only",
+			postBuild: map[string]interface{}{},
+			opts:      []kustomize.SubstituteOption{kustomize.SubstituteWithBuiltinVars(builtinVars)},
+			wantData: map[string]string{
+				"name":      "app",
+				"namespace": "apps",
+				"revision":  "main@sha1:6f4b3d1a9c2e7a10",
+				"custom":    "none",
+			},
+			wantLabels: map[string]string{"environment": "dev"},
+		},
+		{
+			name:      "built-in vars in dry-run mode",
+			postBuild: map[string]interface{}{"substituteFrom": substituteFrom},
+			opts: []kustomize.SubstituteOption{
+				kustomize.SubstituteWithBuiltinVars(builtinVars),
+				kustomize.SubstituteWithDryRun(true),
+			},
+			wantData: map[string]string{
+				"name":      "app",
+				"namespace": "apps",
+				"revision":  "main@sha1:6f4b3d1a9c2e7a10",
+				"custom":    "none",
+			},
+			wantLabels: map[string]string{"environment": "dev"},
+		},
+		{
+			name:      "shadowing from substituteFrom is ignored",
+			postBuild: map[string]interface{}{"substituteFrom": substituteFrom},
+			opts:      []kustomize.SubstituteOption{kustomize.SubstituteWithBuiltinVars(builtinVars)},
+			wantData: map[string]string{
+				"name":      "app",
+				"namespace": "apps",
+				"revision":  "main@sha1:6f4b3d1a9c2e7a10",
+				"custom":    "from-configmap",
+			},
+			wantLabels: map[string]string{"environment": "prod"},
+		},
+		{
+			name: "shadowing from in-line substitute is ignored",
+			postBuild: map[string]interface{}{
+				"substitute": map[string]interface{}{
+					"FLUX_KS_NAMESPACE":    "from-inline",
+					"FLUX_SOURCE_REVISION": "from-inline",
+				},
+			},
+			opts: []kustomize.SubstituteOption{kustomize.SubstituteWithBuiltinVars(builtinVars)},
+			wantData: map[string]string{
+				"name":      "app",
+				"namespace": "apps",
+				"revision":  "main@sha1:6f4b3d1a9c2e7a10",
+				"custom":    "none",
+			},
+			wantLabels: map[string]string{"environment": "dev"},
+		},
+		{
+			name: "shadowing with override",
+			postBuild: map[string]interface{}{
+				"substituteFrom": substituteFrom,
+				"substitute": map[string]interface{}{
+					"FLUX_KS_NAMESPACE": "from-inline",
+				},
+			},
+			opts: []kustomize.SubstituteOption{
+				kustomize.SubstituteWithBuiltinVars(builtinVars),
+				kustomize.SubstituteWithOverrideBuiltinVars(true),
+			},
+			wantData: map[string]string{
+				"name":      "from-configmap",
+				"namespace": "from-inline",
+				"revision":  "main@sha1:6f4b3d1a9c2e7a10",
+				"custom":    "from-configmap",
+			},
+			wantLabels: map[string]string{"environment": "prod"},
+		},
+		{
+			name: "no postBuild",
+			opts: []kustomize.SubstituteOption{kustomize.SubstituteWithBuiltinVars(builtinVars)},
+			wantData: map[string]string{
+				"name":      "${FLUX_KS_NAME}",
+				"namespace": "${FLUX_KS_NAMESPACE}",
+				"revision":  "${FLUX_SOURCE_REVISION}",
+				"custom":    "${FLUX_CUSTOM:=none}",
+			},
+			wantLabels: map[string]string{"environment": "${cluster_env:=dev}"},
+		},
+		{
+			name:      "built-in var without reserved prefix",
+			postBuild: map[string]interface{}{},
+			opts: []kustomize.SubstituteOption{kustomize.SubstituteWithBuiltinVars(map[string]string{
+				"KS_NAME": "app",
+			})},
+			wantErr: "'KS_NAME' built-in var name is invalid, must start with 'FLUX_'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			resMap, err := kustomize.Build(filesys.MakeFsOnDisk(), "./testdata/varsubbuiltin/")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resMap.Resources()).To(HaveLen(1))
+
+			outRes, err := kustomize.SubstituteVariables(context.Background(),
+				kubeClient, newKustomization(tt.postBuild), resMap.Resources()[0], tt.opts...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(outRes).NotTo(BeNil())
+			g.Expect(outRes.GetDataMap()).To(Equal(tt.wantData))
+			g.Expect(outRes.GetLabels()).To(Equal(tt.wantLabels))
+		})
+	}
+}
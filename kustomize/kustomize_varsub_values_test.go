@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize_test
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/fluxcd/pkg/kustomize"
+)
+
+const varsubValuesCert = `-----BEGIN CERTIFICATE-----
+MIIBhTCCASugAwIBAgIQIRi6zePL6mKjOipn+dNuaTAKBggqhkjOPQQDAjASMRAw
+DgYDVQQKEwdBY21lIENvMB4XDTE3MTAyMDE5NDMwNloXDTE4MTAyMDE5NDMwNlow
+-----END CERTIFICATE-----`
+
+func TestKustomization_Varsub_Values(t *testing.T) {
+	// The Secret holds the certificate with a trailing newline, as when
+	// created from a file.
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-certs", Namespace: "apps"},
+		Data: map[string][]byte{
+			"cert": []byte(varsubValuesCert + "\n"),
+		},
+	}
+	kubeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	newKustomization := func(substitute map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+			"kind":       "Kustomization",
+			"metadata": map[string]interface{}{
+				"name":      "app",
+				"namespace": "apps",
+			},
+			"spec": map[string]interface{}{
+				"postBuild": map[string]interface{}{
+					"substitute": substitute,
+					"substituteFrom": []interface{}{
+						map[string]interface{}{"kind": "Secret", "name": "app-certs"},
+					},
+				},
+			},
+		}}
+	}
+	substituteVars := map[string]interface{}{
+		"enabled":  "true",
+		"replicas": "3",
+		"ratio":    "0.5",
+		"version":  "1.10",
+		"ports":    "[80, 443]",
+		"color":    "#fff",
+		"message":  "key: value",
+	}
+
+	substitute := func(g *WithT, kustomization unstructured.Unstructured,
+		opts ...kustomize.SubstituteOption) (map[string]interface{}, error) {
+		resMap, err := kustomize.Build(filesys.MakeFsOnDisk(), "./testdata/varsubvalues/")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(resMap.Resources()).To(HaveLen(1))
+
+		outRes, err := kustomize.SubstituteVariables(context.Background(),
+			kubeClient, kustomization, resMap.Resources()[0], opts...)
+		if err != nil {
+			return nil, err
+		}
+		g.Expect(outRes).NotTo(BeNil())
+		g.Expect(outRes.MustYaml()).NotTo(ContainSubstring("${"))
+
+		return outRes.Map()
+	}
+
+	t.Run("substitutes in the scalar values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := substitute(g, newKustomization(substituteVars))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(obj["spec"]).To(Equal(map[string]interface{}{
+			"enabled":        true,
+			"replicas":       3,
+			"ratio":          0.5,
+			"version":        1.1,
+			"ports":          []interface{}{80, 443},
+			"quotedEnabled":  "true",
+			"quotedReplicas": "3",
+			"quotedVersion":  "v1.10",
+			"color":          "#fff",
+			"message":        "key: value",
+			"tls": map[string]interface{}{
+				"cert":       varsubValuesCert,
+				"quotedCert": varsubValuesCert,
+				"blockCert":  varsubValuesCert + "\n",
+			},
+		}))
+	})
+
+	t.Run("renders the empty values as null", func(t *testing.T) {
+		g := NewWithT(t)
+
+		vars := maps.Clone(substituteVars)
+		vars["enabled"] = ""
+		obj, err := substitute(g, newKustomization(vars))
+		g.Expect(err).NotTo(HaveOccurred())
+		spec := obj["spec"].(map[string]interface{})
+		g.Expect(spec).To(HaveKeyWithValue("enabled", BeNil()))
+		g.Expect(spec).To(HaveKeyWithValue("quotedEnabled", BeNil()))
+	})
+
+	t.Run("substitutes in the text in legacy mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The value with a mapping corrupts the YAML.
+		_, err := substitute(g, newKustomization(substituteVars), kustomize.SubstituteWithLegacyTextMode(true))
+		g.Expect(err).To(MatchError(ContainSubstring("mapping values are not allowed in this context")))
+
+		vars := maps.Clone(substituteVars)
+		delete(vars, "message")
+		obj, err := substitute(g, newKustomization(vars), kustomize.SubstituteWithLegacyTextMode(true))
+		g.Expect(err).NotTo(HaveOccurred())
+		spec := obj["spec"].(map[string]interface{})
+		g.Expect(spec).To(HaveKeyWithValue("quotedEnabled", true))
+		g.Expect(spec).To(HaveKeyWithValue("color", BeNil()))
+		g.Expect(spec["tls"]).To(HaveKeyWithValue("cert",
+			"-----BEGIN CERTIFICATE-----MIIBhTCCASugAwIBAgIQIRi6zePL6mKjOipn+dNuaTAKBggqhkjOPQQDAjASMRAw"+
+				"DgYDVQQKEwdBY21lIENvMB4XDTE3MTAyMDE5NDMwNloXDTE4MTAyMDE5NDMwNlow-----END CERTIFICATE-----"))
+	})
+}
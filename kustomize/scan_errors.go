@@ -0,0 +1,187 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// DefaultMaxScanErrors is the default maximum number of invalid files
+// reported by the ScanError of a Generator.
+const DefaultMaxScanErrors = 10
+
+// ManifestError is the error of a single invalid YAML file found while
+// scanning the manifests of a kustomization.
+type ManifestError struct {
+	// Path is the path of the file relative to the scanned directory.
+	Path string
+	// Line is the line of the error in the file, or 0 if unknown.
+	Line int
+	// Message describes the error.
+	Message string
+}
+
+// Error returns the error in the form '<path>:<line>: <message>'.
+func (e *ManifestError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ScanError aggregates the errors of the invalid YAML files found
+// while scanning the manifests of a kustomization.
+type ScanError struct {
+	// Errors holds the errors of the reported files.
+	Errors []*ManifestError
+	// Omitted is the number of invalid files which are not reported
+	// because the maximum number of errors was reached.
+	Omitted int
+}
+
+// Error returns the errors of all the reported files.
+func (e *ScanError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	msg := fmt.Sprintf("found %d invalid YAML files: %s", len(e.Errors)+e.Omitted, strings.Join(msgs, "; "))
+	if e.Omitted > 0 {
+		msg += fmt.Sprintf(" (and %d more)", e.Omitted)
+	}
+	return msg
+}
+
+// Unwrap returns the errors of the reported files.
+func (e *ScanError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// add records the error of an invalid file, or counts it as omitted
+// once max errors are recorded. A max lower than 1 records all errors.
+func (e *ScanError) add(err *ManifestError, max int) {
+	if max > 0 && len(e.Errors) >= max {
+		e.Omitted++
+		return
+	}
+	e.Errors = append(e.Errors, err)
+}
+
+// yamlErrorLine matches the errors of the YAML parser which carry a line.
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// parseManifest parses the given YAML file with the kustomize resource
+// factory and returns the IDs of its resources. If none of the documents of
+// the file is a Kubernetes object, it returns the line of the first document
+// and skip set to true. If the file is invalid, or if only some of its
+// documents are Kubernetes objects, it returns a ManifestError with the path.
+func parseManifest(rf *resource.Factory, path string, data []byte) (ids []resid.ResId, line int, skip bool, manifestErr *ManifestError) {
+	// Kustomize YAML parser tends to panic in unpredicted ways due to
+	// (accidental) invalid object data; recover when this happens to ensure
+	// continuity of operations.
+	defer func() {
+		if r := recover(); r != nil {
+			manifestErr = &ManifestError{Path: path, Message: fmt.Sprintf("recovered from panic while parsing YAML: %v", r)}
+		}
+	}()
+
+	var objects int
+	var invalidDoc *kyaml.Node
+	dec := kyaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var node kyaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			manifestErr = &ManifestError{Path: path, Message: err.Error()}
+			if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+				manifestErr.Line, _ = strconv.Atoi(m[1])
+				manifestErr.Message = m[2]
+			}
+			return nil, 0, false, manifestErr
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+		doc := node.Content[0]
+		switch {
+		case isEmptyDocument(doc):
+		case isKubernetesObject(doc):
+			objects++
+		case invalidDoc == nil:
+			invalidDoc = doc
+		}
+	}
+
+	if invalidDoc != nil {
+		if objects == 0 {
+			return nil, invalidDoc.Line, true, nil
+		}
+		return nil, 0, false, &ManifestError{
+			Path:    path,
+			Line:    invalidDoc.Line,
+			Message: "document is not a Kubernetes object, missing apiVersion or kind",
+		}
+	}
+
+	resources, err := rf.SliceFromBytes(data)
+	if err != nil {
+		return nil, 0, false, &ManifestError{Path: path, Message: fmt.Sprintf("failed to decode Kubernetes YAML: %s", err)}
+	}
+	for _, res := range resources {
+		ids = append(ids, res.CurId())
+	}
+	return ids, 0, false, nil
+}
+
+// isEmptyDocument returns true if the given YAML document is null.
+func isEmptyDocument(doc *kyaml.Node) bool {
+	return doc.Kind == kyaml.ScalarNode && doc.Tag == kyaml.NodeTagNull
+}
+
+// isKubernetesObject returns true if the given YAML document is a
+// mapping with a non-empty apiVersion and kind.
+func isKubernetesObject(doc *kyaml.Node) bool {
+	if doc.Kind != kyaml.MappingNode {
+		return false
+	}
+	var apiVersion, kind string
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		switch doc.Content[i].Value {
+		case kyaml.APIVersionField:
+			apiVersion = doc.Content[i+1].Value
+		case kyaml.KindField:
+			kind = doc.Content[i+1].Value
+		}
+	}
+	return apiVersion != "" && kind != ""
+}
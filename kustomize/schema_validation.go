@@ -0,0 +1,429 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/openapi"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fluxcd/pkg/apis/kustomize"
+)
+
+// WithSchemaValidation enables the validation of the patches of the
+// kustomization object against the OpenAPI schemas served by the cluster,
+// e.g. with openapi.NewClient(discoveryClient.RESTClient()). The fields of
+// each patch are looked up in the schema of the patch target, and the
+// unknown fields are reported by SchemaWarnings, or returned as an error by
+// GenerateManifest if WithStrictSchemaValidation is set.
+//
+// A strategic merge patch with unknown fields, e.g. a typo or a field of a
+// CRD which does not declare its patch strategy, does not fail the build
+// but silently produces a different object than intended.
+//
+// The patches whose target kind cannot be determined, or whose schema is
+// not served, e.g. for a CRD which is not installed yet, are not validated.
+// A nil client disables the validation, e.g. when discovery is unavailable.
+func WithSchemaValidation(client openapi.Client) GeneratorOption {
+	return func(g *Generator) {
+		if client == nil {
+			g.schemaValidator = nil
+			return
+		}
+		g.schemaValidator = &schemaValidator{client: client}
+	}
+}
+
+// WithStrictSchemaValidation makes GenerateManifest return an error for the
+// unknown fields found by WithSchemaValidation, instead of warnings.
+func WithStrictSchemaValidation() GeneratorOption {
+	return func(g *Generator) {
+		g.strictSchemaValidation = true
+	}
+}
+
+// SchemaWarnings returns the unknown fields of the patches found by
+// WithSchemaValidation when generating the last kustomization file,
+// for controllers to emit them as events.
+func (g *Generator) SchemaWarnings() []string {
+	return g.schemaWarnings
+}
+
+// validatePatches validates the patches of the kustomization object against
+// the schemas of their targets, if enabled. It sets the schema warnings, and
+// returns an error for the unknown fields in strict mode.
+func (g *Generator) validatePatches(patches []kustomize.Patch, patchesSM []apiextensionsv1.JSON,
+	patchesJSON []kustomize.JSON6902Patch) error {
+	g.schemaWarnings = nil
+	if g.schemaValidator == nil {
+		return nil
+	}
+
+	v := g.schemaValidator
+	var warnings []string
+	for i, p := range patches {
+		warnings = append(warnings, v.validatePatch(fmt.Sprintf("spec.patches[%d]", i), p.Patch, p.Target)...)
+	}
+	for i, p := range patchesSM {
+		warnings = append(warnings, v.validatePatch(fmt.Sprintf("spec.patchesStrategicMerge[%d]", i), string(p.Raw), nil)...)
+	}
+	for i, p := range patchesJSON {
+		target := p.Target
+		warnings = append(warnings, v.validateJSON6902(fmt.Sprintf("spec.patchesJson6902[%d]", i), p.Patch, &target)...)
+	}
+
+	if g.strictSchemaValidation && len(warnings) > 0 {
+		errs := make([]error, 0, len(warnings))
+		for _, w := range warnings {
+			errs = append(errs, errors.New(w))
+		}
+		return fmt.Errorf("invalid patches: %w", errors.Join(errs...))
+	}
+	g.schemaWarnings = warnings
+	return nil
+}
+
+// schemaValidator looks up the fields of the patches in the OpenAPI v3
+// schemas of the cluster. The schemas are fetched once per group version.
+type schemaValidator struct {
+	client openapi.Client
+	paths  map[string]openapi.GroupVersion
+	docs   map[string]*openAPIDocument
+}
+
+// openAPIDocument is the OpenAPI v3 document of a group version.
+type openAPIDocument struct {
+	Components struct {
+		Schemas map[string]map[string]any `json:"schemas"`
+	} `json:"components"`
+}
+
+// validatePatch validates a patch of the patches field, which is either a
+// strategic merge patch or a list of JSON 6902 operations.
+func (v *schemaValidator) validatePatch(name, patch string, target *kustomize.Selector) []string {
+	var ops []kustomize.JSON6902
+	if err := yaml.Unmarshal([]byte(patch), &ops); err == nil {
+		return v.validateJSON6902(name, ops, target)
+	}
+
+	var obj map[string]any
+	if err := yaml.Unmarshal([]byte(patch), &obj); err != nil || obj == nil {
+		// The invalid patches are reported by the build.
+		return nil
+	}
+
+	// The kind of the patch is used unless the target selects another one.
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+	if t := targetGVK(target); t.Kind != "" && (t.Kind != gvk.Kind || t.Group != "" || t.Version != "") {
+		gvk = t
+	}
+	doc, root := v.lookup(gvk)
+	if root == nil {
+		return nil
+	}
+
+	var warnings []string
+	doc.validate(root, obj, fieldPath{}, func(path string) {
+		warnings = append(warnings, unknownFieldWarning(name, path, gvk))
+	})
+	return warnings
+}
+
+// validateJSON6902 validates the paths and values of JSON 6902 operations.
+func (v *schemaValidator) validateJSON6902(name string, ops []kustomize.JSON6902, target *kustomize.Selector) []string {
+	gvk := targetGVK(target)
+	doc, root := v.lookup(gvk)
+	if root == nil {
+		return nil
+	}
+
+	var warnings []string
+	report := func(path string) {
+		warnings = append(warnings, unknownFieldWarning(name, path, gvk))
+	}
+	for _, op := range ops {
+		node := doc.validatePointer(root, op.Path, report)
+		if op.From != "" {
+			doc.validatePointer(root, op.From, report)
+		}
+		if node == nil || op.Value == nil || (op.Op != "add" && op.Op != "replace") {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(op.Value.Raw, &value); err == nil {
+			doc.validate(node, value, fieldPath{path: op.Path, pointer: true}, report)
+		}
+	}
+	return warnings
+}
+
+// lookup returns the document and the schema of the given kind, or a nil
+// schema if it is not served. As in the kustomize selectors, an empty group
+// or version matches all of them, the core group being looked up first.
+func (v *schemaValidator) lookup(gvk schema.GroupVersionKind) (*openAPIDocument, map[string]any) {
+	if gvk.Kind == "" {
+		return nil, nil
+	}
+	if v.paths == nil {
+		paths, err := v.client.Paths()
+		if err != nil {
+			// Discovery is unavailable, skip the validation.
+			return nil, nil
+		}
+		v.paths = paths
+		v.docs = make(map[string]*openAPIDocument)
+	}
+
+	var candidates []schema.GroupVersion
+	for path := range v.paths {
+		gv, ok := parseAPIPath(path)
+		if !ok || (gvk.Group != "" && gv.Group != gvk.Group) || (gvk.Version != "" && gv.Version != gvk.Version) {
+			continue
+		}
+		candidates = append(candidates, gv)
+	}
+	slices.SortFunc(candidates, func(a, b schema.GroupVersion) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	for _, gv := range candidates {
+		doc := v.document(gv)
+		if doc == nil {
+			continue
+		}
+		for _, s := range doc.Components.Schemas {
+			if hasGroupVersionKind(s, gv.WithKind(gvk.Kind)) {
+				return doc, s
+			}
+		}
+	}
+	return nil, nil
+}
+
+// parseAPIPath returns the group version of an OpenAPI path, e.g. api/v1 or
+// apis/apps/v1.
+func parseAPIPath(path string) (schema.GroupVersion, bool) {
+	parts := strings.Split(path, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "api":
+		return schema.GroupVersion{Version: parts[1]}, true
+	case len(parts) == 3 && parts[0] == "apis":
+		return schema.GroupVersion{Group: parts[1], Version: parts[2]}, true
+	}
+	return schema.GroupVersion{}, false
+}
+
+// document returns the OpenAPI document of the given path, or nil if it
+// cannot be fetched.
+func (v *schemaValidator) document(gv schema.GroupVersion) *openAPIDocument {
+	path := "apis/" + gv.String()
+	if gv.Group == "" {
+		path = "api/" + gv.Version
+	}
+	if doc, ok := v.docs[path]; ok {
+		return doc
+	}
+	var doc *openAPIDocument
+	if gv, ok := v.paths[path]; ok {
+		if data, err := gv.Schema("application/json"); err == nil {
+			doc = &openAPIDocument{}
+			if err := json.Unmarshal(data, doc); err != nil {
+				doc = nil
+			}
+		}
+	}
+	v.docs[path] = doc
+	return doc
+}
+
+// validate reports the fields of the value which are not declared by the
+// schema node. The directives of the strategic merge patches, e.g. $patch,
+// are ignored.
+func (d *openAPIDocument) validate(node map[string]any, value any, path fieldPath, report func(string)) {
+	node = d.resolve(node)
+	if node == nil || preservesUnknownFields(node) {
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := node["properties"].(map[string]any)
+		additional, _ := node["additionalProperties"].(map[string]any)
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if strings.HasPrefix(k, "$") {
+				continue
+			}
+			child := path.child(k)
+			if p, ok := properties[k].(map[string]any); ok {
+				d.validate(p, value[k], child, report)
+				continue
+			}
+			if additional != nil {
+				d.validate(additional, value[k], child, report)
+				continue
+			}
+			// The objects without properties are free-form.
+			if properties != nil && node["additionalProperties"] != true {
+				report(child.path)
+			}
+		}
+	case []any:
+		items, _ := node["items"].(map[string]any)
+		if items == nil {
+			return
+		}
+		for i, item := range value {
+			d.validate(items, item, path.index(i), report)
+		}
+	}
+}
+
+// fieldPath is the path of a field reported by validate, either a dotted
+// path for the strategic merge patches, or a JSON pointer for the values of
+// the JSON 6902 operations.
+type fieldPath struct {
+	path    string
+	pointer bool
+}
+
+// child returns the path of the given field of the object at the path.
+func (p fieldPath) child(key string) fieldPath {
+	switch {
+	case p.pointer:
+		key = strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+		return fieldPath{path: p.path + "/" + key, pointer: true}
+	case p.path == "":
+		return fieldPath{path: key}
+	default:
+		return fieldPath{path: p.path + "." + key}
+	}
+}
+
+// index returns the path of the given item of the list at the path.
+func (p fieldPath) index(i int) fieldPath {
+	if p.pointer {
+		return fieldPath{path: fmt.Sprintf("%s/%d", p.path, i), pointer: true}
+	}
+	return fieldPath{path: fmt.Sprintf("%s[%d]", p.path, i)}
+}
+
+// validatePointer reports the JSON pointer if it does not match a field
+// declared by the schema, and returns the schema node of the field, or nil
+// if it is unknown or free-form.
+func (d *openAPIDocument) validatePointer(root map[string]any, pointer string, report func(string)) map[string]any {
+	if pointer == "" || pointer == "/" {
+		return root
+	}
+	node := root
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		node = d.resolve(node)
+		if node == nil || preservesUnknownFields(node) {
+			return nil
+		}
+		if items, ok := node["items"].(map[string]any); ok {
+			if _, err := strconv.Atoi(segment); err != nil && segment != "-" {
+				report(pointer)
+				return nil
+			}
+			node = items
+			continue
+		}
+		properties, _ := node["properties"].(map[string]any)
+		if p, ok := properties[segment].(map[string]any); ok {
+			node = p
+			continue
+		}
+		if additional, ok := node["additionalProperties"].(map[string]any); ok {
+			node = additional
+			continue
+		}
+		if properties != nil && node["additionalProperties"] != true {
+			report(pointer)
+		}
+		return nil
+	}
+	return node
+}
+
+// resolve follows the references of the schema node, including those
+// wrapped in allOf by the Kubernetes OpenAPI documents.
+func (d *openAPIDocument) resolve(node map[string]any) map[string]any {
+	for range 32 {
+		if ref, ok := node["$ref"].(string); ok {
+			node = d.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+			if node == nil {
+				return nil
+			}
+			continue
+		}
+		if allOf, ok := node["allOf"].([]any); ok && len(allOf) == 1 && node["properties"] == nil {
+			if next, ok := allOf[0].(map[string]any); ok {
+				node = next
+				continue
+			}
+		}
+		return node
+	}
+	return nil
+}
+
+// preservesUnknownFields returns true if the schema node accepts any field.
+func preservesUnknownFields(node map[string]any) bool {
+	preserve, _ := node["x-kubernetes-preserve-unknown-fields"].(bool)
+	return preserve
+}
+
+// hasGroupVersionKind returns true if the schema is the one of the given kind.
+func hasGroupVersionKind(s map[string]any, gvk schema.GroupVersionKind) bool {
+	gvks, _ := s["x-kubernetes-group-version-kind"].([]any)
+	for _, v := range gvks {
+		m, _ := v.(map[string]any)
+		if m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// targetGVK returns the kind selected by the target of a patch.
+func targetGVK(target *kustomize.Selector) schema.GroupVersionKind {
+	if target == nil {
+		return schema.GroupVersionKind{}
+	}
+	return schema.GroupVersionKind{Group: target.Group, Version: target.Version, Kind: target.Kind}
+}
+
+// unknownFieldWarning returns the warning of an unknown field of a patch.
+func unknownFieldWarning(patch, path string, gvk schema.GroupVersionKind) string {
+	return fmt.Sprintf("%s: unknown field '%s' for %s", patch, path, gvk.GroupKind().String())
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/otiai10/copy"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/openapitest"
+
+	"github.com/fluxcd/pkg/kustomize"
+)
+
+const schemaValidationPath = "./testdata/schemavalidation/"
+
+func TestGenerator_SchemaValidation(t *testing.T) {
+	unknownFields := []string{
+		"spec.patches[0]: unknown field 'spec.replica' for Deployment.apps",
+		"spec.patches[0]: unknown field 'spec.template.spec.containers[0].imagee' for Deployment.apps",
+		"spec.patches[1]: unknown field '/spec/sizes' for Widget.example.com",
+		"spec.patches[1]: unknown field '/spec/parts/-/colour' for Widget.example.com",
+		"spec.patchesStrategicMerge[0]: unknown field 'spec.parts[0].counts' for Widget.example.com",
+		"spec.patchesJson6902[0]: unknown field '/spec/template/spec/containers/0/arguments' for Deployment.apps",
+	}
+
+	for _, tt := range []struct {
+		name     string
+		client   openapi.Client
+		strict   bool
+		warnings []string
+		err      string
+	}{
+		{
+			name:     "warns about the unknown fields",
+			client:   openapitest.NewFileClient(schemaValidationPath + "openapi"),
+			warnings: unknownFields,
+		},
+		{
+			name:   "fails on the unknown fields in strict mode",
+			client: openapitest.NewFileClient(schemaValidationPath + "openapi"),
+			strict: true,
+			err:    "invalid patches: " + strings.Join(unknownFields, "\n"),
+		},
+		{
+			name:   "skips the validation without discovery",
+			strict: true,
+		},
+		{
+			name:   "skips the validation when discovery fails",
+			client: &openapitest.FakeClient{ForcedErr: errors.New("the server could not find the requested resource")},
+			strict: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dataKS, err := os.ReadFile(schemaValidationPath + "ks.yaml")
+			g.Expect(err).NotTo(HaveOccurred())
+			ks, err := readYamlObjects(strings.NewReader(string(dataKS)))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tmpDir, err := testTempDir(t)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(copy.Copy(schemaValidationPath+"resources", tmpDir)).To(Succeed())
+
+			opts := []kustomize.GeneratorOption{kustomize.WithSchemaValidation(tt.client)}
+			if tt.strict {
+				opts = append(opts, kustomize.WithStrictSchemaValidation())
+			}
+			gen := kustomize.NewGenerator(tmpDir, ks[0], opts...)
+
+			_, _, _, err = gen.GenerateManifest(tmpDir)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(tt.err))
+				g.Expect(gen.SchemaWarnings()).To(BeEmpty())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(gen.SchemaWarnings()).To(Equal(tt.warnings))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/fluxcd/pkg/ssa"
+)
+
+// WithStableOrder normalizes the build output, so that builds of identical
+// semantic content produce identical bytes regardless of the order of the
+// input files or of the kustomize version:
+//   - the resources are sorted by their ssa.ReconcileOrder class, then by
+//     group, kind, namespace, name and version
+//   - the fields of the resources are formatted with the kyaml FormatFilter,
+//     e.g. the lists keyed by name such as containers are sorted
+//
+// The normalization is disabled by default.
+func WithStableOrder() BuildOption {
+	return func(c *buildConfig) {
+		c.stableOrder = true
+	}
+}
+
+// BuildChecksum returns the SHA-256 digest of the normalized YAML of the
+// given build result, in the form 'sha256:<hex>', for detecting changes
+// between builds. The result is normalized as with WithStableOrder, without
+// modifying it.
+func BuildChecksum(res resmap.ResMap) (string, error) {
+	normalized := res.DeepCopy()
+	if err := normalized.ApplyFilter(stableOrderFilter{}); err != nil {
+		return "", fmt.Errorf("failed to normalize the build result: %w", err)
+	}
+	data, err := normalized.AsYaml()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize the build result: %w", err)
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), nil
+}
+
+// stableOrderFilter is a kio.Filter which sorts the fields of the resources
+// and the resources themselves, see WithStableOrder.
+type stableOrderFilter struct{}
+
+// Filter implements kio.Filter.
+func (stableOrderFilter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	nodes, err := filters.FormatFilter{}.Filter(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		node *yaml.RNode
+		obj  *unstructured.Unstructured
+	}
+	entries := make([]entry, 0, len(nodes))
+	for _, node := range nodes {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(node.GetApiVersion())
+		obj.SetKind(node.GetKind())
+		obj.SetNamespace(node.GetNamespace())
+		obj.SetName(node.GetName())
+		entries = append(entries, entry{node: node, obj: obj})
+	}
+
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		pair := ssa.SortableUnstructureds{a.obj, b.obj}
+		switch {
+		case pair.Less(0, 1):
+			return -1
+		case pair.Less(1, 0):
+			return 1
+		default:
+			// The same object can be defined in several API versions.
+			return strings.Compare(a.obj.GetAPIVersion(), b.obj.GetAPIVersion())
+		}
+	})
+
+	for i := range entries {
+		nodes[i] = entries[i].node
+	}
+	return nodes, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/fluxcd/pkg/kustomize"
+)
+
+// stableOrderFiles are the manifests of the stable order tests, with the
+// fields of some resources in an unconventional order.
+var stableOrderFiles = map[string]string{
+	"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: apps
+spec:
+  selector:
+    matchLabels:
+      app: app
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+      - name: app
+        image: ghcr.io/fluxcd/app:v1.0.0
+`,
+	"namespace.yaml": `kind: Namespace
+metadata:
+  name: apps
+apiVersion: v1
+`,
+	"configmaps.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  namespace: apps
+  name: b
+data:
+  key: value
+---
+data:
+  key: value
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: a
+  namespace: apps
+`,
+	"service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: apps
+spec:
+  selector:
+    app: app
+  ports:
+  - port: 80
+`,
+	"webhook.yaml": `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: app
+webhooks: []
+`,
+}
+
+func writeStableOrderFiles(g *WithT, fs filesys.FileSystem, dir string, order []string) {
+	for name, content := range stableOrderFiles {
+		g.Expect(fs.WriteFile(filepath.Join(dir, name), []byte(content))).To(Succeed())
+	}
+	kustomization := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n"
+	for _, name := range order {
+		kustomization += fmt.Sprintf("- %s\n", name)
+	}
+	g.Expect(fs.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(kustomization))).To(Succeed())
+}
+
+func TestBuild_WithStableOrder(t *testing.T) {
+	g := NewWithT(t)
+
+	var files []string
+	for name := range stableOrderFiles {
+		files = append(files, name)
+	}
+
+	var expectedYAML []byte
+	var expectedChecksum string
+	for i := range 5 {
+		order := append([]string(nil), files...)
+		rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		fs := filesys.MakeFsInMemory()
+		dir := fmt.Sprintf("/build-%d", i)
+		g.Expect(fs.MkdirAll(dir)).To(Succeed())
+		writeStableOrderFiles(g, fs, dir, order)
+
+		res, err := kustomize.Build(fs, dir, kustomize.WithStableOrder())
+		g.Expect(err).NotTo(HaveOccurred())
+		data, err := res.AsYaml()
+		g.Expect(err).NotTo(HaveOccurred())
+		checksum, err := kustomize.BuildChecksum(res)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		if i == 0 {
+			expectedYAML, expectedChecksum = data, checksum
+			continue
+		}
+		g.Expect(string(data)).To(Equal(string(expectedYAML)), "order %v", order)
+		g.Expect(checksum).To(Equal(expectedChecksum), "order %v", order)
+	}
+
+	// The resources are in the apply order, then sorted by name.
+	var ids []string
+	for _, doc := range strings.Split(string(expectedYAML), "\n---\n") {
+		var kind, name string
+		for _, line := range strings.Split(doc, "\n") {
+			switch {
+			case strings.HasPrefix(line, "kind: "):
+				kind = strings.TrimPrefix(line, "kind: ")
+			case strings.HasPrefix(line, "  name: ") && name == "":
+				name = strings.TrimPrefix(line, "  name: ")
+			}
+		}
+		ids = append(ids, kind+"/"+name)
+	}
+	g.Expect(ids).To(Equal([]string{
+		"Namespace/apps",
+		"ConfigMap/a",
+		"ConfigMap/b",
+		"Service/app",
+		"Deployment/app",
+		"ValidatingWebhookConfiguration/app",
+	}))
+	g.Expect(expectedChecksum).To(MatchRegexp(`^sha256:[a-f0-9]{64}$`))
+}
+
+func TestBuildChecksum(t *testing.T) {
+	g := NewWithT(t)
+
+	build := func(order []string, opts ...kustomize.BuildOption) string {
+		fs := filesys.MakeFsInMemory()
+		g.Expect(fs.MkdirAll("/build")).To(Succeed())
+		writeStableOrderFiles(g, fs, "/build", order)
+		res, err := kustomize.Build(fs, "/build", opts...)
+		g.Expect(err).NotTo(HaveOccurred())
+		before, err := res.AsYaml()
+		g.Expect(err).NotTo(HaveOccurred())
+
+		checksum, err := kustomize.BuildChecksum(res)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		// The build result is not modified.
+		after, err := res.AsYaml()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(after).To(Equal(before))
+		return checksum
+	}
+
+	order := []string{"configmaps.yaml", "deployment.yaml", "namespace.yaml", "service.yaml", "webhook.yaml"}
+	reversed := []string{"webhook.yaml", "service.yaml", "namespace.yaml", "deployment.yaml", "configmaps.yaml"}
+	checksum := build(order)
+	g.Expect(build(reversed)).To(Equal(checksum))
+	g.Expect(build(reversed, kustomize.WithStableOrder())).To(Equal(checksum))
+
+	// A change of content changes the checksum.
+	g.Expect(build(order[1:])).NotTo(Equal(checksum))
+}
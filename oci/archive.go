@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/fluxcd/pkg/sourceignore"
+)
+
+const (
+	// defaultFileMode is the mode of the regular files extracted into
+	// an in-memory file system without permission bits.
+	defaultFileMode fs.FileMode = 0o644
+	// defaultDirMode is the mode of the directories created in an
+	// in-memory file system without permission bits.
+	defaultDirMode fs.FileMode = 0o755
+)
+
+// ignoreFilter returns a tar.WithFilter predicate that excludes the
+// entries of a file system matching the given sourceignore patterns.
+// The patterns are matched against the paths relative to the root of
+// the file system.
+func ignoreFilter(ignorePaths []string) func(string, fs.FileInfo) bool {
+	ps := sourceignore.ReadPatterns(strings.NewReader(strings.Join(ignorePaths, "\n")), nil)
+	matcher := sourceignore.NewMatcher(ps)
+	return func(p string, fi fs.FileInfo) bool {
+		return p != "." && matcher.Match(strings.Split(p, "/"), fi.IsDir())
+	}
+}
+
+// untarToMemFS extracts the gzipped tarball read from r into an in-memory
+// file system. Entries with paths that escape the root are rejected and
+// symlinks are skipped. Entries for which the filter, if any, returns true
+// are skipped too. The extraction fails once the extracted files exceed
+// maxSize bytes, unless maxSize is equal or less than 0.
+func untarToMemFS(r io.Reader, filter func(string, fs.FileInfo) bool, maxSize int) (*memFS, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("requires gzip-compressed body: %w", err)
+	}
+	tr := tar.NewReader(gr)
+
+	// The budget is shared by the entries, so that many small files
+	// cannot exceed it either.
+	remaining := int64(maxSize)
+	mfs := newMemFS()
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tar error: %w", err)
+		}
+
+		name := path.Clean(h.Name)
+		if h.Name == "" || strings.Contains(h.Name, `\`) || path.IsAbs(name) ||
+			name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("tar contained invalid name error %q", h.Name)
+		}
+
+		fi := h.FileInfo()
+		if filter != nil && filter(h.Name, fi) {
+			continue
+		}
+
+		mode := fi.Mode()
+		switch {
+		case mode.IsRegular():
+			var fr io.Reader = tr
+			if maxSize > 0 {
+				fr = io.LimitReader(tr, remaining+1)
+			}
+			data, err := io.ReadAll(fr)
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s: %w", h.Name, err)
+			}
+			if maxSize > 0 {
+				if remaining -= int64(len(data)); remaining < 0 {
+					return nil, fmt.Errorf("tar %q is bigger than max archive size of %d bytes", h.Name, maxSize)
+				}
+			}
+			mfs.addFile(name, data, mode.Perm())
+		case mode.IsDir():
+			mfs.addDir(name, mode.Perm())
+		case mode&fs.ModeSymlink != 0:
+			continue
+		default:
+			return nil, fmt.Errorf("tar file entry %s contained unsupported file type %v", h.Name, mode)
+		}
+	}
+	return mfs, gr.Close()
+}
+
+// readAllLimited reads r until EOF, failing if more than max bytes are
+// read, unless max is equal or less than 0.
+func readAllLimited(r io.Reader, max int) ([]byte, error) {
+	if max <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, int64(max)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > max {
+		return nil, fmt.Errorf("content is bigger than max size of %d bytes", max)
+	}
+	return data, nil
+}
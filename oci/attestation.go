@@ -0,0 +1,327 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+const (
+	// DSSEEnvelopeMediaType is the media type of the layers holding the
+	// DSSE envelopes of the in-toto attestations, and the artifact type of
+	// the attestation manifests returned by the referrers API.
+	DSSEEnvelopeMediaType types.MediaType = "application/vnd.dsse.envelope.v1+json"
+
+	// InTotoPayloadType is the DSSE payload type of the in-toto statements.
+	InTotoPayloadType = "application/vnd.in-toto+json"
+
+	// PredicateTypeSPDX is the in-toto predicate type of the SPDX SBOMs.
+	PredicateTypeSPDX = "https://spdx.dev/Document"
+
+	// PredicateTypeCycloneDX is the in-toto predicate type of the CycloneDX SBOMs.
+	PredicateTypeCycloneDX = "https://cyclonedx.org/bom"
+)
+
+// MissingPolicy is the policy applied by Verify when the artifact does not
+// carry the required attestations.
+type MissingPolicy string
+
+const (
+	// MissingPolicyFail fails the verification.
+	MissingPolicyFail MissingPolicy = "Fail"
+	// MissingPolicyWarn reports a warning in the VerificationResult.
+	MissingPolicyWarn MissingPolicy = "Warn"
+)
+
+// VerifyOption configures the verification of an artifact.
+type VerifyOption func(o *verifyOptions)
+
+type verifyOptions struct {
+	requiredAttestations []string
+	missingPolicy        MissingPolicy
+	tsaCertChain         []byte
+	rekorPublicKey       []byte
+}
+
+// WithRequiredAttestations requires the artifact to carry an in-toto
+// attestation, signed with the key of the verifier, for each of the given
+// predicate types, e.g. PredicateTypeSPDX. When an attestation is missing,
+// the verification fails or reports a warning according to the policy.
+// The attestations are resolved from the cosign attestation tag
+// sha256-<digest>.att and from the referrers API.
+func WithRequiredAttestations(predicateTypes []string, policy MissingPolicy) VerifyOption {
+	return func(o *verifyOptions) {
+		o.requiredAttestations = predicateTypes
+		o.missingPolicy = policy
+	}
+}
+
+// Attestation is an in-toto attestation of an artifact.
+type Attestation struct {
+	// PredicateType is the predicate type of the in-toto statement.
+	PredicateType string
+	// Digest is the digest of the layer holding the DSSE envelope.
+	Digest string
+}
+
+// VerificationResult is the result of the verification of an artifact.
+type VerificationResult struct {
+	// Attestations holds the verified attestations matching
+	// the required predicate types.
+	Attestations []Attestation
+	// Warnings holds the missing attestations when the
+	// MissingPolicyWarn policy is used.
+	Warnings []string
+	// Transparency is the source of the signing time of the verified
+	// signature, e.g. TransparencyTSA when WithTSACertChain is used.
+	Transparency TransparencySource
+	// SignedAt is the signing time of the verified signature, or the time
+	// of the verification when the Transparency is TransparencyNone.
+	SignedAt time.Time
+}
+
+// ErrMissingAttestations is returned by Verify when the artifact does not
+// carry the required attestations and the MissingPolicyFail policy is used.
+type ErrMissingAttestations struct {
+	// Reference is the reference of the artifact.
+	Reference string
+	// PredicateTypes holds the predicate types of the missing attestations.
+	PredicateTypes []string
+	// Err holds the verification errors of the attestations found for the
+	// artifact, if any.
+	Err error
+}
+
+// Error returns the missing predicate types and the verification errors.
+func (e *ErrMissingAttestations) Error() string {
+	msg := fmt.Sprintf("missing attestations for '%s': %s", e.Reference, strings.Join(e.PredicateTypes, ", "))
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %s", msg, e.Err)
+	}
+	return msg
+}
+
+// Unwrap returns the verification errors of the attestations.
+func (e *ErrMissingAttestations) Unwrap() error {
+	return e.Err
+}
+
+// verifyAttestations resolves the attestations of the artifact with the
+// given digest and matches them against the required predicate types.
+func (c *Client) verifyAttestations(ctx context.Context, url string, ref name.Reference, digest gcrv1.Hash,
+	pub crypto.PublicKey, o verifyOptions) (*VerificationResult, error) {
+	result := &VerificationResult{}
+	if len(o.requiredAttestations) == 0 {
+		return result, nil
+	}
+
+	attestations, invalid, err := c.findAttestations(ctx, ref.Context(), digest, pub)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, predicateType := range o.requiredAttestations {
+		found := false
+		for _, att := range attestations {
+			if att.PredicateType == predicateType {
+				result.Attestations = append(result.Attestations, att)
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, predicateType)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	missingErr := &ErrMissingAttestations{Reference: url, PredicateTypes: missing, Err: errors.Join(invalid...)}
+	if o.missingPolicy == MissingPolicyWarn {
+		result.Warnings = append(result.Warnings, missingErr.Error())
+		return result, nil
+	}
+	return result, missingErr
+}
+
+// findAttestations returns the attestations of the artifact with the given
+// digest which are signed with the given key, from the cosign attestation tag
+// and the referrers API, along with the verification errors of the other
+// attestations.
+func (c *Client) findAttestations(ctx context.Context, repo name.Repository, digest gcrv1.Hash,
+	pub crypto.PublicKey) ([]Attestation, []error, error) {
+	var images []gcrv1.Image
+
+	attTag := cosignAttestationTag(repo, digest)
+	img, err := c.pullSignatures(ctx, attTag)
+	if err != nil {
+		return nil, nil, err
+	}
+	if img != nil {
+		images = append(images, img)
+	}
+
+	remoteOpts := append(crane.GetOptions(c.optionsWithContext(ctx)...).Remote,
+		remote.WithFilter("artifactType", string(DSSEEnvelopeMediaType)))
+	index, err := remote.Referrers(repo.Digest(digest.String()), remoteOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching referrers for '%s' failed: %w", digest, err)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing referrers for '%s' failed: %w", digest, err)
+	}
+	for _, desc := range indexManifest.Manifests {
+		if desc.ArtifactType != string(DSSEEnvelopeMediaType) {
+			continue
+		}
+		img, err := crane.Pull(repo.Digest(desc.Digest.String()).String(), c.optionsWithContext(ctx)...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("pulling attestation '%s' failed: %w", desc.Digest, err)
+		}
+		images = append(images, img)
+	}
+
+	var attestations []Attestation
+	var errs []error
+	for _, img := range images {
+		manifest, err := img.Manifest()
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing attestation manifest failed: %w", err)
+		}
+		for _, desc := range manifest.Layers {
+			if desc.MediaType != DSSEEnvelopeMediaType {
+				continue
+			}
+			predicateType, err := verifyAttestationLayer(img, desc, digest, pub)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !slices.ContainsFunc(attestations, func(a Attestation) bool { return a.Digest == desc.Digest.String() }) {
+				attestations = append(attestations, Attestation{PredicateType: predicateType, Digest: desc.Digest.String()})
+			}
+		}
+	}
+	return attestations, errs, nil
+}
+
+// cosignAttestationTag returns the tag of the attestation image
+// for the artifact with the given digest.
+func cosignAttestationTag(repo name.Repository, digest gcrv1.Hash) name.Tag {
+	return repo.Tag(fmt.Sprintf("%s-%s.att", digest.Algorithm, digest.Hex))
+}
+
+// dsseEnvelope is a DSSE envelope.
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+	Signatures  []struct {
+		KeyID string `json:"keyid"`
+		Sig   string `json:"sig"`
+	} `json:"signatures"`
+}
+
+// inTotoStatement is the part of an in-toto statement
+// which is verified.
+type inTotoStatement struct {
+	PredicateType string `json:"predicateType"`
+	Subject       []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// dssePAE returns the DSSE pre-authentication encoding of the payload,
+// which is the message signed by the envelope signatures.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)
+}
+
+// verifyAttestationLayer verifies the signatures of the DSSE envelope in the
+// given layer, checks that the in-toto statement has the artifact digest as
+// subject, and returns the predicate type of the statement.
+func verifyAttestationLayer(img gcrv1.Image, desc gcrv1.Descriptor, digest gcrv1.Hash, pub crypto.PublicKey) (string, error) {
+	layer, err := img.LayerByDigest(desc.Digest)
+	if err != nil {
+		return "", fmt.Errorf("fetching layer %s failed: %w", desc.Digest, err)
+	}
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return "", fmt.Errorf("fetching layer %s failed: %w", desc.Digest, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "", fmt.Errorf("reading layer %s failed: %w", desc.Digest, err)
+	}
+
+	var envelope dsseEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return "", fmt.Errorf("layer %s has an invalid DSSE envelope: %w", desc.Digest, err)
+	}
+	if envelope.PayloadType != InTotoPayloadType {
+		return "", fmt.Errorf("layer %s has an invalid payload type '%s'", desc.Digest, envelope.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return "", fmt.Errorf("layer %s has an invalid payload encoding: %w", desc.Digest, err)
+	}
+
+	pae := dssePAE(envelope.PayloadType, payload)
+	verified := false
+	for _, s := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		if verifySignature(pub, pae, sig) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return "", fmt.Errorf("layer %s: invalid signature", desc.Digest)
+	}
+
+	var statement inTotoStatement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return "", fmt.Errorf("layer %s has an invalid in-toto statement: %w", desc.Digest, err)
+	}
+	for _, subject := range statement.Subject {
+		if subject.Digest[digest.Algorithm] == digest.Hex {
+			return statement.PredicateType, nil
+		}
+	}
+	return "", fmt.Errorf("layer %s is not attesting digest '%s'", desc.Digest, digest)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/gomega"
+)
+
+// attachAttestation signs an in-toto statement of the given predicate type
+// for the subject digest, and attaches it to the artifact at the given URL,
+// either with the cosign attestation tag or as a referrer. It returns the
+// digest of the DSSE envelope layer.
+func attachAttestation(t *testing.T, c *Client, url string, priv *ecdsa.PrivateKey,
+	predicateType string, subject gcrv1.Hash, referrer bool) string {
+	t.Helper()
+	g := NewWithT(t)
+
+	statement, err := json.Marshal(map[string]any{
+		"_type":         "https://in-toto.io/Statement/v1",
+		"predicateType": predicateType,
+		"subject": []map[string]any{
+			{"name": url, "digest": map[string]string{subject.Algorithm: subject.Hex}},
+		},
+		"predicate": map[string]any{},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	digest := sha256.Sum256(dssePAE(InTotoPayloadType, statement))
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	g.Expect(err).NotTo(HaveOccurred())
+	envelope, err := json.Marshal(map[string]any{
+		"payloadType": InTotoPayloadType,
+		"payload":     base64.StdEncoding.EncodeToString(statement),
+		"signatures":  []map[string]string{{"keyid": "", "sig": base64.StdEncoding.EncodeToString(sig)}},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	layer := static.NewLayer(envelope, DSSEEnvelopeMediaType)
+	layerDigest, err := layer.Digest()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ref, artifactDigest, err := c.resolveDigest(context.Background(), url)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	if !referrer {
+		attTag := cosignAttestationTag(ref.Context(), artifactDigest)
+		img, err := c.pullSignatures(context.Background(), attTag)
+		g.Expect(err).NotTo(HaveOccurred())
+		if img == nil {
+			img = mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+			img = mutate.ConfigMediaType(img, types.OCIConfigJSON)
+		}
+		img, err = mutate.Append(img, mutate.Addendum{
+			Layer:       layer,
+			Annotations: map[string]string{"predicateType": predicateType},
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(crane.Push(img, attTag.String(), c.options...)).To(Succeed())
+		return layerDigest.String()
+	}
+
+	desc, err := crane.Head(url, c.options...)
+	g.Expect(err).NotTo(HaveOccurred())
+	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, types.MediaType(DSSEEnvelopeMediaType))
+	img, err = mutate.Append(img, mutate.Addendum{Layer: layer})
+	g.Expect(err).NotTo(HaveOccurred())
+	img = mutate.Subject(img, *desc).(gcrv1.Image)
+	imgDigest, err := img.Digest()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	dst, err := name.ParseReference(ref.Context().Digest(imgDigest.String()).String())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(remote.Write(dst, img, crane.GetOptions(c.options...).Remote...)).To(Succeed())
+	return layerDigest.String()
+}
+
+func Test_Verify_RequiredAttestations(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	password := []byte("s3cr3t")
+	priv, privPEM, pubPEM := generateCosignKeyPair(t, password)
+	signer, err := NewSignerFromKey(privPEM, password)
+	g.Expect(err).NotTo(HaveOccurred())
+	verifier, err := NewVerifierFromKey(pubPEM)
+	g.Expect(err).NotTo(HaveOccurred())
+	otherPriv, _, _ := generateCosignKeyPair(t, password)
+
+	newArtifact := func(repo string) (string, gcrv1.Hash) {
+		url := pushRandomArtifact(t, c, repo)
+		_, err := c.Sign(ctx, url, signer)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, digest, err := c.resolveDigest(ctx, url)
+		g.Expect(err).NotTo(HaveOccurred())
+		return url, digest
+	}
+
+	// An artifact with an SPDX attestation attached with the cosign tag,
+	// and a CycloneDX attestation attached as a referrer.
+	url, digest := newArtifact("test-attestations")
+	spdxDigest := attachAttestation(t, c, url, priv, PredicateTypeSPDX, digest, false)
+	cdxDigest := attachAttestation(t, c, url, priv, PredicateTypeCycloneDX, digest, true)
+
+	// An artifact with attestations signed with another key or for another digest.
+	invalidURL, invalidDigest := newArtifact("test-attestations-invalid")
+	attachAttestation(t, c, invalidURL, otherPriv, PredicateTypeSPDX, invalidDigest, false)
+	attachAttestation(t, c, invalidURL, priv, PredicateTypeCycloneDX, digest, true)
+
+	tests := []struct {
+		name             string
+		url              string
+		opts             []VerifyOption
+		wantAttestations []Attestation
+		wantWarnings     []string
+		wantErr          string
+		wantMissing      []string
+	}{
+		{
+			name: "no required attestations",
+			url:  url,
+		},
+		{
+			name: "attestation from the cosign tag",
+			url:  url,
+			opts: []VerifyOption{WithRequiredAttestations([]string{PredicateTypeSPDX}, MissingPolicyFail)},
+			wantAttestations: []Attestation{
+				{PredicateType: PredicateTypeSPDX, Digest: spdxDigest},
+			},
+		},
+		{
+			name: "attestations from the cosign tag and the referrers",
+			url:  url,
+			opts: []VerifyOption{WithRequiredAttestations([]string{PredicateTypeSPDX, PredicateTypeCycloneDX}, MissingPolicyFail)},
+			wantAttestations: []Attestation{
+				{PredicateType: PredicateTypeSPDX, Digest: spdxDigest},
+				{PredicateType: PredicateTypeCycloneDX, Digest: cdxDigest},
+			},
+		},
+		{
+			name:        "missing attestation fails",
+			url:         url,
+			opts:        []VerifyOption{WithRequiredAttestations([]string{PredicateTypeSPDX, "https://slsa.dev/provenance/v1"}, MissingPolicyFail)},
+			wantErr:     "missing attestations for '" + url + "': https://slsa.dev/provenance/v1",
+			wantMissing: []string{"https://slsa.dev/provenance/v1"},
+		},
+		{
+			name: "missing attestation warns",
+			url:  url,
+			opts: []VerifyOption{WithRequiredAttestations([]string{PredicateTypeSPDX, "https://slsa.dev/provenance/v1"}, MissingPolicyWarn)},
+			wantAttestations: []Attestation{
+				{PredicateType: PredicateTypeSPDX, Digest: spdxDigest},
+			},
+			wantWarnings: []string{"missing attestations for '" + url + "': https://slsa.dev/provenance/v1"},
+		},
+		{
+			name:        "invalid attestations",
+			url:         invalidURL,
+			opts:        []VerifyOption{WithRequiredAttestations([]string{PredicateTypeSPDX, PredicateTypeCycloneDX}, MissingPolicyFail)},
+			wantErr:     "invalid signature",
+			wantMissing: []string{PredicateTypeSPDX, PredicateTypeCycloneDX},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := c.VerifyArtifact(ctx, tt.url, verifier, tt.opts...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				var missingErr *ErrMissingAttestations
+				g.Expect(errors.As(err, &missingErr)).To(BeTrue())
+				g.Expect(missingErr.PredicateTypes).To(Equal(tt.wantMissing))
+				g.Expect(c.Verify(ctx, tt.url, verifier, tt.opts...)).To(MatchError(err.Error()))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.Attestations).To(Equal(tt.wantAttestations))
+			g.Expect(result.Warnings).To(Equal(tt.wantWarnings))
+		})
+	}
+}
+
+func Test_Verify_RequiredAttestations_Unsigned(t *testing.T) {
+	g := NewWithT(t)
+	c := NewClient(DefaultOptions())
+	url := pushRandomArtifact(t, c, "test-attestations-unsigned")
+
+	_, _, pubPEM := generateCosignKeyPair(t, []byte("s3cr3t"))
+	verifier, err := NewVerifierFromKey(pubPEM)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The signatures are verified before the attestations.
+	_, err = c.VerifyArtifact(context.Background(), url, verifier,
+		WithRequiredAttestations([]string{PredicateTypeSPDX}, MissingPolicyWarn))
+	g.Expect(err).To(MatchError(ContainSubstring("no signatures found")))
+}
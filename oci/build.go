@@ -19,12 +19,11 @@ package oci
 import (
 	"fmt"
 	"io"
+	iofs "io/fs"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/fluxcd/pkg/oci/internal/fs"
-	"github.com/fluxcd/pkg/sourceignore"
 	"github.com/fluxcd/pkg/tar"
 )
 
@@ -35,20 +34,13 @@ func (c *Client) Build(artifactPath, sourceDir string, ignorePaths []string) (er
 }
 
 func build(artifactPath, sourceDir string, ignorePaths []string) (err error) {
-	absSrc, err := filepath.Abs(sourceDir)
+	fsys, dir, cleanup, err := sourceFS(sourceDir)
 	if err != nil {
 		return err
 	}
+	defer cleanup()
 
-	srcInfo, err := os.Stat(absSrc)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("source path does not exist: %s", absSrc)
-		}
-		return fmt.Errorf("invalid source path %s: %w", absSrc, err)
-	}
-
-	tf, err := os.CreateTemp(filepath.Split(absSrc))
+	tf, err := os.CreateTemp(filepath.Split(dir))
 	if err != nil {
 		return err
 	}
@@ -59,33 +51,7 @@ func build(artifactPath, sourceDir string, ignorePaths []string) (err error) {
 		}
 	}()
 
-	// If the source is a single file, stage it in a temp dir so Tar can
-	// archive it as a directory tree containing that one entry.
-	tarDir := absSrc
-	if !srcInfo.IsDir() {
-		stage, stageErr := os.MkdirTemp("", "oci-build-")
-		if stageErr != nil {
-			tf.Close()
-			return stageErr
-		}
-		defer os.RemoveAll(stage)
-
-		if err := copyFileContents(filepath.Join(stage, srcInfo.Name()), absSrc, srcInfo.Mode()); err != nil {
-			tf.Close()
-			return err
-		}
-		tarDir = stage
-	}
-
-	ignore := strings.Join(ignorePaths, "\n")
-	domain := strings.Split(filepath.Clean(tarDir), string(filepath.Separator))
-	ps := sourceignore.ReadPatterns(strings.NewReader(ignore), domain)
-	matcher := sourceignore.NewMatcher(ps)
-	filter := func(p string, fi os.FileInfo) bool {
-		return matcher.Match(strings.Split(p, string(filepath.Separator)), fi.IsDir())
-	}
-
-	if _, err := tar.Tar(tarDir, tf, tar.WithFilter(filter)); err != nil {
+	if _, err := tar.Tar(".", tf, tar.WithFS(fsys), tar.WithFilter(ignoreFilter(ignorePaths))); err != nil {
 		tf.Close()
 		return err
 	}
@@ -98,6 +64,40 @@ func build(artifactPath, sourceDir string, ignorePaths []string) (err error) {
 	return fs.RenameWithFallback(tmpName, artifactPath)
 }
 
+// sourceFS returns a file system rooted at the given source path, together
+// with the absolute source path and a cleanup function. If the source is a
+// single file, it is staged in a temp dir so that it can be archived as a
+// directory tree containing that one entry.
+func sourceFS(sourcePath string) (iofs.FS, string, func(), error) {
+	absSrc, err := filepath.Abs(sourcePath)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	srcInfo, err := os.Stat(absSrc)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil, fmt.Errorf("source path does not exist: %s", absSrc)
+		}
+		return nil, "", nil, fmt.Errorf("invalid source path %s: %w", absSrc, err)
+	}
+
+	if srcInfo.IsDir() {
+		return os.DirFS(absSrc), absSrc, func() {}, nil
+	}
+
+	stage, err := os.MkdirTemp("", "oci-build-")
+	if err != nil {
+		return nil, "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(stage) }
+	if err := copyFileContents(filepath.Join(stage, srcInfo.Name()), absSrc, srcInfo.Mode()); err != nil {
+		cleanup()
+		return nil, "", nil, err
+	}
+	return os.DirFS(stage), absSrc, cleanup, nil
+}
+
 func copyFileContents(dst, src string, mode os.FileMode) (err error) {
 	sf, err := os.Open(src)
 	if err != nil {
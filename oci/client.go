@@ -18,14 +18,22 @@ package oci
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 )
 
 // Client holds the options for accessing remote OCI registries.
 type Client struct {
-	options []crane.Option
+	options     []crane.Option
+	maxPullSize int64
+	userAgent   string
+	headers     http.Header
+
+	tokens        *tokenStore
+	rateLimitHook func(RateLimit)
 }
 
 // NewClient returns an OCI client configured with the given crane options.
@@ -35,7 +43,7 @@ func NewClient(opts []crane.Option) *Client {
 	}
 	options = append(options, opts...)
 
-	return &Client{options: options}
+	return &Client{options: options, tokens: newTokenStore()}
 }
 
 // DefaultOptions returns an empty list of client options.
@@ -48,12 +56,39 @@ func (c *Client) GetOptions() []crane.Option {
 	return c.options
 }
 
-// optionsWithContext returns the crane options for the given context.
+// optionsWithContext returns the crane options for the given context,
+// including the user agent and extra headers of the client, the reuse
+// of the registry tokens, and the verification of the fetched content.
 func (c *Client) optionsWithContext(ctx context.Context) []crane.Option {
 	options := []crane.Option{
-		crane.WithContext(ctx),
+		crane.WithContext(withContentDescriptors(c.withRequestHeaders(ctx))),
+	}
+	options = append(options, c.options...)
+	options = append(options, c.withTransport(options))
+	return options
+}
+
+// withTransport returns a crane.Option wrapping the transport of the given
+// options with the transports of the client: the headerTransport if the
+// client has a user agent or extra headers, the contentTransport verifying
+// the fetched content, the tokenTransport reusing the registry tokens, and
+// the rateLimitTransport reporting the rate limits. The transport is not
+// wrapped if it is a transport.Wrapper, e.g. from WithRetryTransport, which
+// sets up its own headerTransport and contentTransport and holds the token
+// of its repository.
+func (c *Client) withTransport(options []crane.Option) crane.Option {
+	base := crane.GetOptions(options...).Transport
+	if _, ok := base.(*transport.Wrapper); ok {
+		return func(*crane.Options) {}
+	}
+	if c.userAgent != "" || len(c.headers) > 0 {
+		base = &headerTransport{next: base}
+	}
+	base = &contentTransport{next: base}
+	if c.tokens != nil {
+		base = &tokenTransport{store: c.tokens, next: base}
 	}
-	return append(options, c.options...)
+	return crane.WithTransport(&rateLimitTransport{hook: c.rateLimitHook, next: base})
 }
 
 // WithRetryBackOff returns a function for setting the given backoff on crane.Option.
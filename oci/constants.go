@@ -29,6 +29,10 @@ const (
 	// the date and time on which the OCI artifact was built (RFC 3339).
 	CreatedAnnotation = "org.opencontainers.image.created"
 
+	// ArtifactNameAnnotation is the annotation for specifying the name of
+	// an artifact in the image index of an artifact set.
+	ArtifactNameAnnotation = "io.fluxcd.artifact.name"
+
 	// OCIRepositoryPrefix is the prefix used for OCIRepository URLs.
 	OCIRepositoryPrefix = "oci://"
 )
@@ -0,0 +1,315 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// manifestLimit is the maximum size of the manifests read for verification,
+// the same as go-containerregistry.
+const manifestLimit = 100 * 1024 * 1024
+
+// ErrContentMismatch is returned when the content served by a registry does
+// not match its descriptor, e.g. a manifest served with a media type which
+// differs from the one declared by its index or by the manifest itself, or
+// a blob whose size or digest differs from the ones declared by its manifest.
+type ErrContentMismatch struct {
+	// Reference is the reference of the manifest or blob,
+	// e.g. 'ghcr.io/org/repo@sha256:...'.
+	Reference string
+	// Kind is the kind of content, i.e. 'manifest' or 'blob'.
+	Kind string
+	// Field is the mismatching field of the descriptor,
+	// i.e. 'media type', 'size' or 'digest'.
+	Field string
+	// Expected is the value of the field declared by the descriptor.
+	Expected string
+	// Actual is the value of the field of the served content.
+	Actual string
+}
+
+// Error implements error.
+func (e *ErrContentMismatch) Error() string {
+	return fmt.Sprintf("%s '%s' does not match its descriptor: %s is '%s', expected '%s'",
+		e.Kind, e.Reference, e.Field, e.Actual, e.Expected)
+}
+
+// contentDescriptors holds the descriptors declared by the manifests and
+// indexes fetched during an operation, against which the manifests and blobs
+// they reference are verified.
+type contentDescriptors struct {
+	mu          sync.Mutex
+	descriptors map[v1.Hash]v1.Descriptor
+}
+
+type contentDescriptorsKey struct{}
+
+// withContentDescriptors returns a copy of the context holding the
+// descriptors of the content fetched with it, or the context itself if it
+// already holds them, e.g. to verify the artifact pulled from an index
+// against the descriptor of the index.
+func withContentDescriptors(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(contentDescriptorsKey{}).(*contentDescriptors); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, contentDescriptorsKey{}, &contentDescriptors{
+		descriptors: make(map[v1.Hash]v1.Descriptor),
+	})
+}
+
+// get returns the descriptor recorded for the digest, if any.
+func (d *contentDescriptors) get(digest v1.Hash) (v1.Descriptor, bool) {
+	if d == nil {
+		return v1.Descriptor{}, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	desc, ok := d.descriptors[digest]
+	return desc, ok
+}
+
+// add records the given descriptors.
+func (d *contentDescriptors) add(descs ...v1.Descriptor) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, desc := range descs {
+		d.descriptors[desc.Digest] = desc
+	}
+}
+
+// contentManifest holds the fields of the manifests and indexes which
+// identify their kind and reference other content.
+type contentManifest struct {
+	MediaType types.MediaType `json:"mediaType,omitempty"`
+	Config    *v1.Descriptor  `json:"config,omitempty"`
+	Layers    []v1.Descriptor `json:"layers,omitempty"`
+	Manifests []v1.Descriptor `json:"manifests,omitempty"`
+}
+
+// contentTransport verifies the manifests and blobs served by the registries
+// before they are parsed:
+//
+//   - The media type of a manifest, i.e. its Content-Type, must match the
+//     mediaType field of the manifest, its kind (index or image manifest),
+//     and the media type declared by the index referencing it.
+//   - The size and digest of a manifest must match the ones declared by the
+//     index referencing it, the digest it was requested by and the
+//     Docker-Content-Digest header.
+//   - The size and digest of a blob must match the ones declared by the
+//     manifest referencing it and the digest it was requested by. The media
+//     type of the blobs is not verified, as the registries serve them as
+//     application/octet-stream.
+//
+// The descriptors declared by the manifests are recorded in the context of
+// the requests, see withContentDescriptors. A mismatch fails the request with
+// an *ErrContentMismatch.
+type contentTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *contentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	// The blobs may be served by a storage the registry redirects to.
+	origin := req
+	for origin.Response != nil && origin.Response.Request != nil {
+		origin = origin.Response.Request
+	}
+	repo, kind, identifier, ok := parseContentPath(origin.URL.Path)
+	if !ok {
+		return resp, nil
+	}
+	descs, _ := req.Context().Value(contentDescriptorsKey{}).(*contentDescriptors)
+	reference := fmt.Sprintf("%s/%s:%s", origin.URL.Host, repo, identifier)
+	digest, err := v1.NewHash(identifier)
+	if err == nil {
+		reference = fmt.Sprintf("%s/%s@%s", origin.URL.Host, repo, identifier)
+	}
+
+	switch kind {
+	case "manifests":
+		body, err := verifyManifest(resp, reference, digest, descs)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	case "blobs":
+		if err != nil {
+			return resp, nil
+		}
+		h, err := v1.Hasher(digest.Algorithm)
+		if err != nil {
+			return resp, nil
+		}
+		size := resp.ContentLength
+		if desc, ok := descs.get(digest); ok {
+			if size >= 0 && size != desc.Size {
+				resp.Body.Close()
+				return nil, &ErrContentMismatch{Reference: reference, Kind: "blob", Field: "size",
+					Expected: fmt.Sprint(desc.Size), Actual: fmt.Sprint(size)}
+			}
+			size = desc.Size
+		}
+		resp.Body = &verifyingReader{
+			ReadCloser: resp.Body,
+			reference:  reference,
+			digest:     digest,
+			size:       size,
+			hash:       h,
+		}
+	}
+	return resp, nil
+}
+
+// parseContentPath returns the repository, kind and identifier of the
+// manifest or blob of the API request path, e.g.
+// '/v2/org/repo/manifests/latest'.
+func parseContentPath(path string) (repo, kind, identifier string, ok bool) {
+	path, ok = strings.CutPrefix(path, "/v2/")
+	if !ok {
+		return "", "", "", false
+	}
+	for _, k := range []string{"manifests", "blobs"} {
+		if i := strings.LastIndex(path, "/"+k+"/"); i > 0 {
+			return path[:i], k, path[i+len(k)+2:], true
+		}
+	}
+	return "", "", "", false
+}
+
+// verifyManifest reads the manifest of the response and verifies it against
+// its descriptor, if any, the digest it was requested by, if any, and its
+// headers. It records the descriptors declared by the manifest and returns
+// its content.
+func verifyManifest(resp *http.Response, reference string, requested v1.Hash, descs *contentDescriptors) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, manifestLimit))
+	if err != nil {
+		return nil, err
+	}
+	mismatch := func(field string, expected, actual any) error {
+		return &ErrContentMismatch{Reference: reference, Kind: "manifest", Field: field,
+			Expected: fmt.Sprint(expected), Actual: fmt.Sprint(actual)}
+	}
+
+	mediaType := types.MediaType(resp.Header.Get("Content-Type"))
+	if mt, _, err := mime.ParseMediaType(string(mediaType)); err == nil {
+		mediaType = types.MediaType(mt)
+	}
+	digest, size, err := v1.SHA256(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// The schema 1 manifests are signed, their digest being the one of the
+	// payload served in the Docker-Content-Digest header.
+	if !mediaType.IsSchema1() {
+		if requested.Algorithm == digest.Algorithm && requested != digest {
+			return nil, mismatch("digest", requested, digest)
+		}
+		if header, err := v1.NewHash(resp.Header.Get("Docker-Content-Digest")); err == nil &&
+			header.Algorithm == digest.Algorithm && header != digest {
+			return nil, mismatch("digest", header, digest)
+		}
+	}
+	if desc, ok := descs.get(requested); ok {
+		if desc.Size != size {
+			return nil, mismatch("size", desc.Size, size)
+		}
+		if desc.MediaType != "" && desc.MediaType != mediaType {
+			return nil, mismatch("media type", desc.MediaType, mediaType)
+		}
+	}
+
+	var m contentManifest
+	if mediaType.IsSchema1() || json.Unmarshal(body, &m) != nil {
+		// Leave the unknown formats to the parsing logic.
+		return body, nil
+	}
+	switch {
+	case m.MediaType != "" && m.MediaType != mediaType:
+		return nil, mismatch("media type", m.MediaType, mediaType)
+	case m.Manifests != nil && mediaType.IsImage():
+		return nil, mismatch("media type", "an index media type", mediaType)
+	case (m.Config != nil || m.Layers != nil) && mediaType.IsIndex():
+		return nil, mismatch("media type", "an image manifest media type", mediaType)
+	}
+
+	descs.add(m.Manifests...)
+	descs.add(m.Layers...)
+	if m.Config != nil {
+		descs.add(*m.Config)
+	}
+	return body, nil
+}
+
+// verifyingReader verifies the size and digest of a blob while it is read,
+// failing the read which exceeds the size or reaches the end of the blob
+// with a mismatching size or digest.
+type verifyingReader struct {
+	io.ReadCloser
+	reference string
+	digest    v1.Hash
+	// size is the expected size, or -1 if unknown.
+	size int64
+	hash hash.Hash
+	read int64
+}
+
+// Read implements io.Reader.
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	r.read += int64(n)
+	if r.size >= 0 && r.read > r.size {
+		return n, r.mismatch("size", r.size, r.read)
+	}
+	if err != io.EOF {
+		return n, err
+	}
+	if r.size >= 0 && r.read != r.size {
+		return n, r.mismatch("size", r.size, r.read)
+	}
+	if actual := (v1.Hash{Algorithm: r.digest.Algorithm, Hex: fmt.Sprintf("%x", r.hash.Sum(nil))}); actual != r.digest {
+		return n, r.mismatch("digest", r.digest, actual)
+	}
+	return n, io.EOF
+}
+
+func (r *verifyingReader) mismatch(field string, expected, actual any) error {
+	return &ErrContentMismatch{Reference: r.reference, Kind: "blob", Field: field,
+		Expected: fmt.Sprint(expected), Actual: fmt.Sprint(actual)}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/gomega"
+)
+
+// misbehavingRegistry is a proxy to the test registry altering its
+// responses with the given function.
+type misbehavingRegistry struct {
+	mu     sync.Mutex
+	modify func(resp *http.Response) error
+}
+
+func (r *misbehavingRegistry) start(t *testing.T) string {
+	t.Helper()
+	target, err := url.Parse("http://" + dockerReg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		r.mu.Lock()
+		modify := r.modify
+		r.mu.Unlock()
+		if modify == nil || resp.Request.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+			return nil
+		}
+		return modify(resp)
+	}
+	server := httptest.NewServer(proxy)
+	t.Cleanup(server.Close)
+	return strings.Replace(server.Listener.Addr().String(), "127.0.0.1", "localhost", 1)
+}
+
+func (r *misbehavingRegistry) set(modify func(resp *http.Response) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modify = modify
+}
+
+// replaceBody replaces the body of the response and its length.
+func replaceBody(resp *http.Response, modify func([]byte) []byte) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	body = modify(body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return nil
+}
+
+func isManifest(resp *http.Response) bool {
+	return strings.Contains(resp.Request.URL.Path, "/manifests/")
+}
+
+func isBlob(digest string) func(resp *http.Response) bool {
+	return func(resp *http.Response) bool {
+		return strings.HasSuffix(resp.Request.URL.Path, "/blobs/"+digest)
+	}
+}
+
+func TestClient_ContentVerification(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	reg := &misbehavingRegistry{}
+	repo := fmt.Sprintf("%s/test-content-%s", reg.start(t), randStringRunes(5))
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("app: test\n"), 0o600)).To(Succeed())
+	artifact, err := c.Push(ctx, repo+":latest", dir)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = c.PushIndex(ctx, repo+":index", []IndexEntry{{Name: "app", URL: artifact}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, manifest, _, err := c.fetchImage(ctx, artifact, &PullOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	layer := manifest.Layers[0].Digest.String()
+	config := manifest.Config.Digest.String()
+
+	for _, tt := range []struct {
+		name     string
+		modify   func(resp *http.Response) error
+		pull     func() error
+		field    string
+		expected string
+	}{
+		{
+			name: "manifest served with the media type of an index",
+			modify: func(resp *http.Response) error {
+				if isManifest(resp) {
+					resp.Header.Set("Content-Type", string(types.OCIImageIndex))
+				}
+				return nil
+			},
+			pull: func() error {
+				_, err := c.Pull(ctx, repo+":latest", t.TempDir())
+				return err
+			},
+			field:    "media type",
+			expected: string(types.OCIManifestSchema1),
+		},
+		{
+			name: "manifest served with a digest header of another manifest",
+			modify: func(resp *http.Response) error {
+				if isManifest(resp) {
+					resp.Header.Set("Docker-Content-Digest", config)
+				}
+				return nil
+			},
+			pull: func() error {
+				_, err := c.List(ctx, repo, ListOptions{})
+				return err
+			},
+			field:    "digest",
+			expected: config,
+		},
+		{
+			name: "blob with altered content",
+			modify: func(resp *http.Response) error {
+				if !isBlob(layer)(resp) {
+					return nil
+				}
+				return replaceBody(resp, func(b []byte) []byte {
+					b[len(b)-1] ^= 0xff
+					return b
+				})
+			},
+			pull: func() error {
+				// The layers pulled by Pull are also verified against the
+				// digest of the manifest, failing with an *IntegrityError.
+				l, err := crane.PullLayer(repo+"@"+layer, c.optionsWithContext(ctx)...)
+				if err != nil {
+					return err
+				}
+				rc, err := l.Compressed()
+				if err != nil {
+					return err
+				}
+				defer rc.Close()
+				_, err = io.ReadAll(rc)
+				return err
+			},
+			field:    "digest",
+			expected: layer,
+		},
+		{
+			name: "blob with altered length",
+			modify: func(resp *http.Response) error {
+				if !isBlob(layer)(resp) {
+					return nil
+				}
+				return replaceBody(resp, func(b []byte) []byte {
+					return append(b, ' ')
+				})
+			},
+			pull: func() error {
+				_, err := c.Pull(ctx, repo+":latest", t.TempDir())
+				return err
+			},
+			field:    "size",
+			expected: fmt.Sprint(manifest.Layers[0].Size),
+		},
+		{
+			name: "manifest with another media type than its index descriptor",
+			modify: func(resp *http.Response) error {
+				if !strings.HasSuffix(resp.Request.URL.Path, "/manifests/index") {
+					return nil
+				}
+				resp.Header.Del("Docker-Content-Digest")
+				return replaceBody(resp, func(b []byte) []byte {
+					return bytes.Replace(b, []byte(`"mediaType":"`+types.OCIManifestSchema1+`"`),
+						[]byte(`"mediaType":"`+types.DockerManifestSchema2+`"`), 1)
+				})
+			},
+			pull: func() error {
+				_, err := c.PullFromIndex(ctx, repo+":index", map[string]string{ArtifactNameAnnotation: "app"}, t.TempDir())
+				return err
+			},
+			field:    "media type",
+			expected: string(types.DockerManifestSchema2),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reg.set(tt.modify)
+			defer reg.set(nil)
+
+			err := tt.pull()
+			g.Expect(err).To(HaveOccurred())
+			var mismatch *ErrContentMismatch
+			g.Expect(errors.As(err, &mismatch)).To(BeTrue(), err.Error())
+			g.Expect(mismatch.Field).To(Equal(tt.field))
+			g.Expect(mismatch.Expected).To(Equal(tt.expected))
+		})
+	}
+
+	// The content served as is passes the verification.
+	_, err = c.Pull(ctx, repo+":latest", t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = c.PullFromIndex(ctx, repo+":index", map[string]string{ArtifactNameAnnotation: "app"}, t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ExtractStats holds the number of tarball entries extracted and skipped
+// by a pull with WithExtractPaths.
+type ExtractStats struct {
+	Extracted int `json:"extracted"`
+	Skipped   int `json:"skipped"`
+}
+
+// WithExtractPaths restricts the extraction of a tarball layer to the
+// entries matching at least one of the given globs, e.g. 'deploy/prod' or
+// 'apps/*/kustomization.yaml'. The globs are matched with path.Match
+// against the slash-separated paths of the archive entries, ignoring any
+// leading './'. The entries under a matching directory are extracted too,
+// preserving the directory structure. The pull fails if no entry matches,
+// and the Extraction field of the returned Metadata holds the number of
+// extracted and skipped entries.
+func WithExtractPaths(globs []string) PullOption {
+	return func(o *PullOptions) {
+		o.extractPaths = globs
+	}
+}
+
+// errStaticExtractPaths is returned when extract paths are set for the pull
+// of a static layer, which is not an archive.
+var errStaticExtractPaths = errors.New("extract paths are not supported for static layers")
+
+// extractPathSelector selects the tarball entries matching the extract paths
+// and counts the extracted and skipped entries. A nil selector selects all
+// the entries.
+type extractPathSelector struct {
+	globs []string
+	stats ExtractStats
+}
+
+// newExtractPathSelector returns a selector for the given globs, or nil if
+// no glob is given.
+func newExtractPathSelector(globs []string) (*extractPathSelector, error) {
+	if len(globs) == 0 {
+		return nil, nil
+	}
+	s := &extractPathSelector{}
+	for _, glob := range globs {
+		g := path.Clean(glob)
+		if path.IsAbs(g) || g == ".." || strings.HasPrefix(g, "../") {
+			return nil, fmt.Errorf("invalid extract path '%s': must be relative to the root of the artifact", glob)
+		}
+		if _, err := path.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("invalid extract path '%s': %w", glob, err)
+		}
+		s.globs = append(s.globs, g)
+	}
+	return s, nil
+}
+
+// exclude returns true if the tarball entry must be skipped, i.e. if neither
+// the entry nor one of its parent directories matches a glob. Symlinks are
+// always skipped, as they are never extracted.
+func (s *extractPathSelector) exclude(name string, fi fs.FileInfo) bool {
+	if fi.Mode()&fs.ModeSymlink == 0 && s.match(path.Clean(name)) {
+		s.stats.Extracted++
+		return false
+	}
+	s.stats.Skipped++
+	return true
+}
+
+// match returns true if the path or one of its parents matches a glob.
+func (s *extractPathSelector) match(p string) bool {
+	for {
+		for _, g := range s.globs {
+			if ok, _ := path.Match(g, p); ok {
+				return true
+			}
+		}
+		if p == "." || p == "/" {
+			return false
+		}
+		p = path.Dir(p)
+	}
+}
+
+// filter returns the filter of the tarball entries, or nil if all the
+// entries are selected.
+func (s *extractPathSelector) filter() func(string, fs.FileInfo) bool {
+	if s == nil {
+		return nil
+	}
+	return s.exclude
+}
+
+// result returns the extraction stats, or an error if no entry matched.
+func (s *extractPathSelector) result() (*ExtractStats, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if s.stats.Extracted == 0 {
+		return nil, fmt.Errorf("no entries of the artifact match the extract paths %q", s.globs)
+	}
+	stats := s.stats
+	return &stats, nil
+}
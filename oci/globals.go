@@ -33,5 +33,5 @@ var (
 	CanonicalContentMediaType = types.MediaType(fmt.Sprintf("%s.tar+gzip", CanonicalMediaTypePrefix))
 
 	// UserAgent string used for OCI calls.
-	UserAgent = "flux/v2"
+	UserAgent = defaultUserAgent
 )
@@ -16,7 +16,10 @@ require (
 	github.com/fluxcd/pkg/version v0.16.0
 	github.com/google/go-containerregistry v0.21.5
 	github.com/onsi/gomega v1.40.0
+	github.com/secure-systems-lab/go-securesystemslib v0.10.0
 	github.com/sirupsen/logrus v1.9.4
+	go.yaml.in/yaml/v3 v3.0.4
+	golang.org/x/net v0.53.0
 )
 
 require (
@@ -84,9 +87,7 @@ require (
 	go.opentelemetry.io/otel/trace v1.43.0 // indirect
 	go.opentelemetry.io/proto/otlp v1.10.0 // indirect
 	go.yaml.in/yaml/v2 v2.4.3 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/crypto v0.50.0 // indirect
-	golang.org/x/net v0.53.0 // indirect
 	golang.org/x/sync v0.20.0 // indirect
 	golang.org/x/sys v0.43.0 // indirect
 	golang.org/x/text v0.36.0 // indirect
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// defaultUserAgent is the user agent of the OCI calls when no component is set.
+const defaultUserAgent = "flux/v2"
+
+// reservedHeaders are the headers which cannot be set with Client.WithExtraHeaders:
+// the hop-by-hop headers, the headers managed by the HTTP client and the Authorization
+// header, which is set from the configured credentials.
+var reservedHeaders = []string{
+	"Authorization",
+	"Connection",
+	"Content-Length",
+	"Host",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+	"User-Agent",
+}
+
+// SetUserAgent sets the default user agent of the OCI calls to identify the
+// given Flux component and version, e.g. 'source-controller/v1.6.0 flux/v2'.
+// It is meant to be called by the controllers at startup, before creating
+// any Client or calling WithRetryTransport.
+func SetUserAgent(component, version string) {
+	UserAgent = fmt.Sprintf("%s/%s %s", component, version, defaultUserAgent)
+}
+
+// WithUserAgent sets the user agent of all the remote operations of the client,
+// in place of the default UserAgent.
+func (c *Client) WithUserAgent(userAgent string) *Client {
+	c.userAgent = userAgent
+	return c
+}
+
+// WithExtraHeaders sets the given headers on all the requests of the remote
+// operations of the client, e.g. for the registries which route and audit the
+// requests by custom headers. It returns an error if a header is invalid or is
+// a reserved header, such as the hop-by-hop headers and Authorization.
+func (c *Client) WithExtraHeaders(headers map[string]string) (*Client, error) {
+	h := make(http.Header, len(headers))
+	for name, value := range headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return nil, fmt.Errorf("invalid header name '%s'", name)
+		}
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return nil, fmt.Errorf("invalid value for header '%s'", name)
+		}
+		for _, reserved := range reservedHeaders {
+			if http.CanonicalHeaderKey(name) == reserved {
+				return nil, fmt.Errorf("header '%s' cannot be set", name)
+			}
+		}
+		h.Set(name, value)
+	}
+	c.headers = h
+	return c, nil
+}
+
+// requestHeaders holds the user agent and extra headers of the requests.
+type requestHeaders struct {
+	userAgent string
+	headers   http.Header
+}
+
+type requestHeadersKey struct{}
+
+// withRequestHeaders returns a copy of the context holding the user agent and
+// extra headers of the client, if any.
+func (c *Client) withRequestHeaders(ctx context.Context) context.Context {
+	if c.userAgent == "" && len(c.headers) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, requestHeadersKey{}, &requestHeaders{
+		userAgent: c.userAgent,
+		headers:   c.headers,
+	})
+}
+
+// headerTransport sets the user agent and extra headers found in the context
+// of the requests. It is the innermost transport, so that the user agent is
+// not overridden by the transports of go-containerregistry.
+type headerTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rh, ok := req.Context().Value(requestHeadersKey{}).(*requestHeaders)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	maps.Copy(req.Header, rh.headers)
+	if rh.userAgent != "" {
+		req.Header.Set("User-Agent", rh.userAgent)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	. "github.com/onsi/gomega"
+)
+
+// headersRecorder is a reverse proxy to the test registry which records
+// the headers of the requests.
+type headersRecorder struct {
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func (r *headersRecorder) requests() []http.Header {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.headers
+}
+
+func (r *headersRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.headers = nil
+}
+
+func newHeadersRecorder(t *testing.T) (*headersRecorder, string) {
+	t.Helper()
+	target, err := url.Parse("http://" + dockerReg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	recorder := &headersRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		recorder.mu.Lock()
+		recorder.headers = append(recorder.headers, req.Header.Clone())
+		recorder.mu.Unlock()
+		proxy.ServeHTTP(w, req)
+	}))
+	t.Cleanup(server.Close)
+	return recorder, strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestClient_WithExtraHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantErr string
+	}{
+		{
+			name:    "custom headers",
+			headers: map[string]string{"X-Flux-Tenant": "team-a", "x-request-source": "flux"},
+		},
+		{
+			name:    "authorization",
+			headers: map[string]string{"authorization": "Bearer token"},
+			wantErr: "header 'authorization' cannot be set",
+		},
+		{
+			name:    "hop-by-hop header",
+			headers: map[string]string{"Connection": "close"},
+			wantErr: "header 'Connection' cannot be set",
+		},
+		{
+			name:    "user agent",
+			headers: map[string]string{"User-Agent": "custom"},
+			wantErr: "header 'User-Agent' cannot be set",
+		},
+		{
+			name:    "invalid name",
+			headers: map[string]string{"X Tenant": "team-a"},
+			wantErr: "invalid header name 'X Tenant'",
+		},
+		{
+			name:    "invalid value",
+			headers: map[string]string{"X-Tenant": "team-a\r\nX-Injected: true"},
+			wantErr: "invalid value for header 'X-Tenant'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := NewClient(DefaultOptions()).WithExtraHeaders(tt.headers)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(c.headers).To(HaveLen(len(tt.headers)))
+		})
+	}
+}
+
+func TestClient_RequestHeaders(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	recorder, registry := newHeadersRecorder(t)
+	repo := fmt.Sprintf("%s/test-headers-%s", registry, randStringRunes(5))
+	img, err := random.Image(1024, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(crane.Push(img, repo+":v0.0.1")).To(Succeed())
+
+	expectHeaders := func(g *WithT) {
+		requests := recorder.requests()
+		g.Expect(requests).ToNot(BeEmpty())
+		for _, h := range requests {
+			g.Expect(h.Get("User-Agent")).To(HavePrefix("source-controller/v1.0.0"))
+			g.Expect(h.Get("X-Flux-Tenant")).To(Equal("team-a"))
+		}
+	}
+
+	t.Run("with the default transport", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder.reset()
+
+		c, err := NewClient(DefaultOptions()).
+			WithUserAgent("source-controller/v1.0.0").
+			WithExtraHeaders(map[string]string{"X-Flux-Tenant": "team-a"})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = c.Tag(ctx, repo+":v0.0.1", "v0.0.2")
+		g.Expect(err).ToNot(HaveOccurred())
+		metas, err := c.List(ctx, repo, ListOptions{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(metas).To(HaveLen(2))
+		expectHeaders(g)
+	})
+
+	t.Run("with the retry transport", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ref, err := name.ParseReference(repo + ":v0.0.1")
+		g.Expect(err).ToNot(HaveOccurred())
+		transportOpt, err := WithRetryTransport(ctx, ref, authn.Anonymous, remote.Backoff{
+			Duration: time.Second,
+			Steps:    1,
+		}, []string{ref.Context().Scope(transport.PushScope)}, true)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		opts := append(DefaultOptions(), transportOpt)
+		c, err := NewClient(opts).
+			WithUserAgent("source-controller/v1.0.0").
+			WithExtraHeaders(map[string]string{"X-Flux-Tenant": "team-a"})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Only record the requests of the client, after the ping of the registry.
+		recorder.reset()
+		_, err = c.Tag(ctx, repo+":v0.0.1", "v0.0.4")
+		g.Expect(err).ToNot(HaveOccurred())
+		expectHeaders(g)
+	})
+
+	t.Run("with the default user agent", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder.reset()
+
+		defer func(userAgent string) { UserAgent = userAgent }(UserAgent)
+		SetUserAgent("kustomize-controller", "v1.0.0")
+		g.Expect(UserAgent).To(Equal("kustomize-controller/v1.0.0 flux/v2"))
+
+		c := NewClient(DefaultOptions())
+		_, err := c.Tag(ctx, repo+":v0.0.1", "v0.0.3")
+		g.Expect(err).ToNot(HaveOccurred())
+		for _, h := range recorder.requests() {
+			g.Expect(h.Get("User-Agent")).To(HavePrefix("kustomize-controller/v1.0.0 flux/v2"))
+			g.Expect(h.Get("X-Flux-Tenant")).To(BeEmpty())
+		}
+	})
+}
@@ -0,0 +1,372 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	archivetar "archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	// HelmChartConfigMediaType is the OCI media type of the config
+	// of a Helm chart, holding the chart metadata as JSON.
+	HelmChartConfigMediaType types.MediaType = "application/vnd.cncf.helm.config.v1+json"
+
+	// HelmChartContentMediaType is the OCI media type of the layer
+	// holding the packaged Helm chart.
+	HelmChartContentMediaType types.MediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// HelmChartProvenanceMediaType is the OCI media type of the layer
+	// holding the provenance file of a Helm chart.
+	HelmChartProvenanceMediaType types.MediaType = "application/vnd.cncf.helm.chart.provenance.v1.prov"
+)
+
+const (
+	// helmChartFileName is the name of the chart metadata file
+	// in the root directory of a packaged chart.
+	helmChartFileName = "Chart.yaml"
+
+	// titleAnnotation and versionAnnotation are the OpenContainers
+	// annotations set by Helm to the chart name and version.
+	titleAnnotation   = "org.opencontainers.image.title"
+	versionAnnotation = "org.opencontainers.image.version"
+)
+
+// HelmChartMetadata holds the chart metadata stored in the
+// config of a Helm chart artifact.
+type HelmChartMetadata struct {
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Name       string `json:"name" yaml:"name"`
+	Version    string `json:"version" yaml:"version"`
+	AppVersion string `json:"appVersion,omitempty" yaml:"appVersion,omitempty"`
+}
+
+// HelmChart is a Helm chart pulled from an OCI repository.
+type HelmChart struct {
+	// Metadata holds the upstream information of the artifact.
+	Metadata *Metadata
+	// Chart holds the chart metadata of the artifact config.
+	Chart HelmChartMetadata
+	// Content holds the packaged chart, i.e. the gzipped tarball.
+	Content []byte
+	// Provenance holds the provenance file of the chart, or nil
+	// if the artifact has no provenance layer.
+	Provenance []byte
+}
+
+// PullChart downloads a Helm chart from an OCI repository and returns the
+// packaged chart and its provenance file, if any. The artifact must use the
+// Helm media types, and the name and version in the Chart.yaml of the packaged
+// chart must match the chart metadata of the artifact config.
+//
+// Of the pull options, only WithExpectedDigest and the provenance options,
+// e.g. WithExpectedSource, apply to charts.
+func (c *Client) PullChart(ctx context.Context, url string, opts ...PullOption) (*HelmChart, error) {
+	o := &PullOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	img, manifest, meta, err := c.fetchImage(ctx, url, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Config.MediaType != HelmChartConfigMediaType {
+		return nil, fmt.Errorf("artifact '%s' is not a Helm chart: unsupported config media type '%s'",
+			url, manifest.Config.MediaType)
+	}
+
+	rawConfig, err := img.RawConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("reading chart config failed: %w", err)
+	}
+	chart := &HelmChart{Metadata: meta}
+	if err := json.Unmarshal(rawConfig, &chart.Chart); err != nil {
+		return nil, fmt.Errorf("parsing chart config failed: %w", err)
+	}
+
+	var declared int64
+	for _, desc := range manifest.Layers {
+		if desc.MediaType == HelmChartContentMediaType || desc.MediaType == HelmChartProvenanceMediaType {
+			declared += desc.Size
+		}
+	}
+	limit := c.newPullSizeLimit(url, declared)
+	if err := limit.check(); err != nil {
+		return nil, err
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list layers: %w", err)
+	}
+	for i, desc := range manifest.Layers {
+		var dst *[]byte
+		switch desc.MediaType {
+		case HelmChartContentMediaType:
+			dst = &chart.Content
+		case HelmChartProvenanceMediaType:
+			dst = &chart.Provenance
+		default:
+			continue
+		}
+		if *dst != nil {
+			return nil, fmt.Errorf("artifact '%s' contains more than one layer of type '%s'", url, desc.MediaType)
+		}
+		if *dst, err = readLayer(layers[i], desc, url, i, limit); err != nil {
+			return nil, err
+		}
+	}
+	if chart.Content == nil {
+		return nil, fmt.Errorf("artifact '%s' does not contain a layer of type '%s'", url, HelmChartContentMediaType)
+	}
+
+	chartFile, err := helmChartMetadata(chart.Content)
+	if err != nil {
+		return nil, err
+	}
+	if chartFile.Name != chart.Chart.Name || chartFile.Version != chart.Chart.Version {
+		return nil, fmt.Errorf("chart '%s' version '%s' does not match the config of artifact '%s': expected chart '%s' version '%s'",
+			chartFile.Name, chartFile.Version, url, chart.Chart.Name, chart.Chart.Version)
+	}
+
+	return chart, nil
+}
+
+// PushChart uploads the packaged Helm chart and its provenance file to the
+// given OCI repository, following the layout used by Helm, and returns the
+// digest. The provenance layer is omitted if prov is empty. The config of the
+// artifact holds the metadata read from the Chart.yaml of the packaged chart.
+// As with PushArtifact, the upload is skipped if the URL already refers to the
+// chart.
+//
+// Of the push options, only WithPushMetadata applies to charts.
+func (c *Client) PushChart(ctx context.Context, url string, chart, prov []byte, opts ...PushOption) (string, error) {
+	o := newPushOptions(opts...)
+
+	ref, err := name.ParseReference(url)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	rawChartFile, err := readHelmChartFile(chart)
+	if err != nil {
+		return "", err
+	}
+	var chartFile HelmChartMetadata
+	if err := yaml.Unmarshal(rawChartFile, &chartFile); err != nil {
+		return "", fmt.Errorf("parsing %s failed: %w", helmChartFileName, err)
+	}
+	if chartFile.Name == "" || chartFile.Version == "" {
+		return "", fmt.Errorf("%s must contain the chart name and version", helmChartFileName)
+	}
+
+	// Helm stores the whole chart metadata in the config,
+	// so we keep the fields we do not know about.
+	var chartMeta map[string]any
+	if err := yaml.Unmarshal(rawChartFile, &chartMeta); err != nil {
+		return "", fmt.Errorf("parsing %s failed: %w", helmChartFileName, err)
+	}
+	config, err := json.Marshal(chartMeta)
+	if err != nil {
+		return "", fmt.Errorf("encoding chart config failed: %w", err)
+	}
+
+	if o.meta.Created == "" {
+		o.meta.Created = time.Now().UTC().Format(time.RFC3339)
+	}
+	annotations := o.meta.ToAnnotations()
+	if annotations[CreatedAnnotation] == "" {
+		annotations[CreatedAnnotation] = o.meta.Created
+	}
+	if _, ok := annotations[titleAnnotation]; !ok {
+		annotations[titleAnnotation] = chartFile.Name
+	}
+	if _, ok := annotations[versionAnnotation]; !ok {
+		annotations[versionAnnotation] = chartFile.Version
+	}
+
+	layers := []gcrv1.Layer{static.NewLayer(chart, HelmChartContentMediaType)}
+	if len(prov) > 0 {
+		layers = append(layers, static.NewLayer(prov, HelmChartProvenanceMediaType))
+	}
+
+	img, err := newHelmChartImage(config, layers, annotations)
+	if err != nil {
+		return "", fmt.Errorf("creating chart artifact failed: %w", err)
+	}
+
+	result, err := c.pushImage(ctx, ref, img)
+	if err != nil {
+		return "", err
+	}
+	return result.Digest, nil
+}
+
+// readLayer returns the content of the layer, verifying it against
+// the digest of the layer descriptor and enforcing the size limit.
+func readLayer(layer gcrv1.Layer, desc gcrv1.Descriptor, reference string, layerIndex int,
+	limit *pullSizeLimit) ([]byte, error) {
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("reading layer failed: %w", err)
+	}
+	defer rc.Close()
+
+	verifier, err := newDigestVerifier(limit.reader(rc), desc, reference, layerIndex)
+	if err != nil {
+		return nil, fmt.Errorf("reading layer failed: %w", err)
+	}
+	data, err := io.ReadAll(verifier)
+	if err != nil {
+		if lerr := limit.exceeded(); lerr != nil {
+			return nil, lerr
+		}
+		var integrityErr *IntegrityError
+		if errors.As(err, &integrityErr) {
+			return nil, integrityErr
+		}
+		return nil, fmt.Errorf("reading layer failed: %w", err)
+	}
+	return data, nil
+}
+
+// helmChartMetadata returns the metadata of the Chart.yaml of the packaged chart.
+func helmChartMetadata(chart []byte) (*HelmChartMetadata, error) {
+	rawChartFile, err := readHelmChartFile(chart)
+	if err != nil {
+		return nil, err
+	}
+	meta := &HelmChartMetadata{}
+	if err := yaml.Unmarshal(rawChartFile, meta); err != nil {
+		return nil, fmt.Errorf("parsing %s failed: %w", helmChartFileName, err)
+	}
+	return meta, nil
+}
+
+// readHelmChartFile returns the content of the Chart.yaml in the root
+// directory of the packaged chart.
+func readHelmChartFile(chart []byte) ([]byte, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(chart))
+	if err != nil {
+		return nil, fmt.Errorf("reading packaged chart failed: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := archivetar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("packaged chart does not contain a %s file", helmChartFileName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading packaged chart failed: %w", err)
+		}
+		if hdr.Typeflag != archivetar.TypeReg {
+			continue
+		}
+		dir, file := path.Split(path.Clean(hdr.Name))
+		if file != helmChartFileName || dir == "" || path.Dir(path.Clean(dir)) != "." {
+			continue
+		}
+		return io.ReadAll(tr)
+	}
+}
+
+// helmChartImage is an OCI artifact with the layout of a Helm chart.
+// Unlike the images built with mutate, its config is not an image
+// config file but the chart metadata.
+type helmChartImage struct {
+	config   []byte
+	manifest []byte
+	layers   map[gcrv1.Hash]gcrv1.Layer
+}
+
+// newHelmChartImage returns an image with the given config and
+// layers, and the manifest annotations.
+func newHelmChartImage(config []byte, layers []gcrv1.Layer, annotations map[string]string) (gcrv1.Image, error) {
+	configDigest, configSize, err := gcrv1.SHA256(bytes.NewReader(config))
+	if err != nil {
+		return nil, err
+	}
+	m := gcrv1.Manifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		Config: gcrv1.Descriptor{
+			MediaType: HelmChartConfigMediaType,
+			Digest:    configDigest,
+			Size:      configSize,
+		},
+		Annotations: annotations,
+	}
+
+	img := &helmChartImage{
+		config: config,
+		layers: make(map[gcrv1.Hash]gcrv1.Layer, len(layers)),
+	}
+	for _, l := range layers {
+		desc, err := partial.Descriptor(l)
+		if err != nil {
+			return nil, err
+		}
+		m.Layers = append(m.Layers, *desc)
+		img.layers[desc.Digest] = l
+	}
+	if img.manifest, err = json.Marshal(m); err != nil {
+		return nil, err
+	}
+	return partial.CompressedToImage(img)
+}
+
+// RawConfigFile implements partial.CompressedImageCore.
+func (i *helmChartImage) RawConfigFile() ([]byte, error) {
+	return i.config, nil
+}
+
+// MediaType implements partial.CompressedImageCore.
+func (i *helmChartImage) MediaType() (types.MediaType, error) {
+	return types.OCIManifestSchema1, nil
+}
+
+// RawManifest implements partial.CompressedImageCore.
+func (i *helmChartImage) RawManifest() ([]byte, error) {
+	return i.manifest, nil
+}
+
+// LayerByDigest implements partial.CompressedImageCore.
+func (i *helmChartImage) LayerByDigest(h gcrv1.Hash) (partial.CompressedLayer, error) {
+	if l, ok := i.layers[h]; ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("layer %s not found", h)
+}
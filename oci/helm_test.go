@@ -0,0 +1,304 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	archivetar "archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/gomega"
+)
+
+// testHelmChart returns a packaged chart with the given files,
+// in the root directory named after the chart like helm package does.
+func testHelmChart(t *testing.T, dir string, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := archivetar.NewWriter(gzw)
+	for _, p := range []string{"Chart.yaml", "values.yaml", "templates/configmap.yaml"} {
+		content, ok := files[p]
+		if !ok {
+			continue
+		}
+		hdr := &archivetar.Header{
+			Name:     dir + "/" + p,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: archivetar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pushHelmLayout uploads the chart with the manifest layout used by helm push:
+// the chart metadata as JSON config, the chart layer followed by the optional
+// provenance layer, and the title and version annotations.
+func pushHelmLayout(t *testing.T, c *Client, url string, config, chart, prov []byte) string {
+	t.Helper()
+	ctx := context.Background()
+
+	ref, err := name.ParseReference(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteOpts := crane.GetOptions(c.optionsWithContext(ctx)...).Remote
+
+	upload := func(content []byte, mediaType types.MediaType) gcrv1.Descriptor {
+		layer := static.NewLayer(content, mediaType)
+		if err := remote.WriteLayer(ref.Context(), layer, remoteOpts...); err != nil {
+			t.Fatal(err)
+		}
+		digest, err := layer.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gcrv1.Descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content))}
+	}
+
+	m := gcrv1.Manifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		Config:        upload(config, HelmChartConfigMediaType),
+		Layers:        []gcrv1.Descriptor{upload(chart, HelmChartContentMediaType)},
+		Annotations: map[string]string{
+			"org.opencontainers.image.title":   "podinfo",
+			"org.opencontainers.image.version": "6.5.0",
+		},
+	}
+	if prov != nil {
+		m.Layers = append(m.Layers, upload(prov, HelmChartProvenanceMediaType))
+	}
+	rawManifest, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Put(ref, testRawManifest(rawManifest), remoteOpts...); err != nil {
+		t.Fatal(err)
+	}
+	digest, _, err := gcrv1.SHA256(bytes.NewReader(rawManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref.Context().Digest(digest.String()).String()
+}
+
+type testRawManifest []byte
+
+func (m testRawManifest) RawManifest() ([]byte, error) {
+	return m, nil
+}
+
+func (m testRawManifest) MediaType() (types.MediaType, error) {
+	return types.OCIManifestSchema1, nil
+}
+
+func TestClient_PullChart(t *testing.T) {
+	chartYAML := "apiVersion: v2\nname: podinfo\nversion: 6.5.0\nappVersion: 6.5.0\n"
+	chart := func(t *testing.T) []byte {
+		return testHelmChart(t, "podinfo", map[string]string{
+			"Chart.yaml":               chartYAML,
+			"values.yaml":              "replicaCount: 1\n",
+			"templates/configmap.yaml": "kind: ConfigMap\n",
+		})
+	}
+	config := []byte(`{"apiVersion":"v2","name":"podinfo","version":"6.5.0","appVersion":"6.5.0"}`)
+	prov := []byte("-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA512\n\nname: podinfo\n")
+
+	tests := []struct {
+		name    string
+		config  []byte
+		chart   func(t *testing.T) []byte
+		prov    []byte
+		wantErr string
+	}{
+		{
+			name:   "chart with provenance",
+			config: config,
+			chart:  chart,
+			prov:   prov,
+		},
+		{
+			name:   "chart without provenance",
+			config: config,
+			chart:  chart,
+		},
+		{
+			name:    "version mismatch",
+			config:  []byte(`{"apiVersion":"v2","name":"podinfo","version":"6.6.0"}`),
+			chart:   chart,
+			wantErr: "chart 'podinfo' version '6.5.0' does not match the config of artifact",
+		},
+		{
+			name:    "name mismatch",
+			config:  []byte(`{"apiVersion":"v2","name":"other","version":"6.5.0"}`),
+			chart:   chart,
+			wantErr: "expected chart 'other' version '6.5.0'",
+		},
+		{
+			name:   "missing Chart.yaml",
+			config: config,
+			chart: func(t *testing.T) []byte {
+				return testHelmChart(t, "podinfo", map[string]string{"values.yaml": "replicaCount: 1\n"})
+			},
+			wantErr: "packaged chart does not contain a Chart.yaml file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			c := NewClient(DefaultOptions())
+
+			url := fmt.Sprintf("%s/charts/podinfo-%s:6.5.0", dockerReg, randStringRunes(5))
+			content := tt.chart(t)
+			digest := pushHelmLayout(t, c, url, tt.config, content, tt.prov)
+
+			got, err := c.PullChart(ctx, url)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Metadata.Digest).To(Equal(digest))
+			g.Expect(got.Metadata.URL).To(Equal(url))
+			g.Expect(got.Metadata.Annotations).To(HaveKeyWithValue("org.opencontainers.image.title", "podinfo"))
+			g.Expect(got.Chart).To(Equal(HelmChartMetadata{
+				APIVersion: "v2",
+				Name:       "podinfo",
+				Version:    "6.5.0",
+				AppVersion: "6.5.0",
+			}))
+			g.Expect(got.Content).To(Equal(content))
+			g.Expect(got.Provenance).To(Equal(tt.prov))
+		})
+	}
+}
+
+func TestClient_PullChart_NotAChart(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	url := fmt.Sprintf("%s/%s:v1", dockerReg, randStringRunes(5))
+	_, err := c.Push(ctx, url, "testdata/artifact")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = c.PullChart(ctx, url)
+	g.Expect(err).To(MatchError(ContainSubstring("unsupported config media type 'application/vnd.cncf.flux.config.v1+json'")))
+}
+
+func TestClient_PushChart(t *testing.T) {
+	chartYAML := "apiVersion: v2\nname: podinfo\nversion: 6.5.0\ndescription: Podinfo Helm chart\n" +
+		"dependencies:\n- name: redis\n  version: 1.0.0\n"
+	chart := testHelmChart(t, "podinfo", map[string]string{"Chart.yaml": chartYAML})
+	prov := []byte("-----BEGIN PGP SIGNED MESSAGE-----\n")
+
+	tests := []struct {
+		name string
+		prov []byte
+	}{
+		{name: "with provenance", prov: prov},
+		{name: "without provenance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			c := NewClient(DefaultOptions())
+
+			url := fmt.Sprintf("%s/charts/podinfo-%s:6.5.0", dockerReg, randStringRunes(5))
+			digest, err := c.PushChart(ctx, url, chart, tt.prov, WithPushMetadata(Metadata{
+				Source:   "https://github.com/stefanprodan/podinfo",
+				Revision: "6.5.0@sha1:b3b00fe35424a45d373bf4c7214178bc36fd7872",
+			}))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			manifest, err := crane.Manifest(url, c.optionsWithContext(ctx)...)
+			g.Expect(err).ToNot(HaveOccurred())
+			m, err := gcrv1.ParseManifest(bytes.NewReader(manifest))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(m.MediaType).To(Equal(types.OCIManifestSchema1))
+			g.Expect(m.Config.MediaType).To(Equal(HelmChartConfigMediaType))
+			g.Expect(m.Annotations).To(HaveKeyWithValue("org.opencontainers.image.title", "podinfo"))
+			g.Expect(m.Annotations).To(HaveKeyWithValue("org.opencontainers.image.version", "6.5.0"))
+			g.Expect(m.Annotations).To(HaveKey(CreatedAnnotation))
+			wantLayers := []types.MediaType{HelmChartContentMediaType}
+			if tt.prov != nil {
+				wantLayers = append(wantLayers, HelmChartProvenanceMediaType)
+			}
+			g.Expect(m.Layers).To(HaveLen(len(wantLayers)))
+			for i, l := range m.Layers {
+				g.Expect(l.MediaType).To(Equal(wantLayers[i]))
+			}
+
+			config, err := crane.Config(url, c.optionsWithContext(ctx)...)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(config).To(MatchJSON(`{"apiVersion":"v2","name":"podinfo","version":"6.5.0",` +
+				`"description":"Podinfo Helm chart","dependencies":[{"name":"redis","version":"1.0.0"}]}`))
+
+			d, err := name.NewDigest(digest)
+			g.Expect(err).ToNot(HaveOccurred())
+			got, err := c.PullChart(ctx, url, WithExpectedDigest(d.DigestStr()))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Metadata.Digest).To(Equal(digest))
+			g.Expect(got.Metadata.Source).To(Equal("https://github.com/stefanprodan/podinfo"))
+			g.Expect(got.Content).To(Equal(chart))
+			g.Expect(got.Provenance).To(Equal(tt.prov))
+		})
+	}
+}
+
+func TestClient_PushChart_InvalidChart(t *testing.T) {
+	g := NewWithT(t)
+	c := NewClient(DefaultOptions())
+	url := fmt.Sprintf("%s/charts/%s:v1", dockerReg, randStringRunes(5))
+
+	_, err := c.PushChart(context.Background(), url, []byte("not a chart"), nil)
+	g.Expect(err).To(MatchError(ContainSubstring("reading packaged chart failed")))
+
+	chart := testHelmChart(t, "podinfo", map[string]string{"Chart.yaml": "apiVersion: v2\nname: podinfo\n"})
+	_, err = c.PushChart(context.Background(), url, chart, nil)
+	g.Expect(err).To(MatchError("Chart.yaml must contain the chart name and version"))
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// IndexEntry is an artifact referenced from the image index
+// assembled by PushIndex.
+type IndexEntry struct {
+	// Name is the name of the artifact in the index, set as the
+	// ArtifactNameAnnotation of its manifest descriptor.
+	Name string
+	// URL is the URL of the artifact, e.g. 'ghcr.io/org/repo:staging'
+	// or 'ghcr.io/org/repo@sha256:...'. The artifact is copied to the
+	// repository of the index if it lives in another one.
+	URL string
+	// Annotations are additional annotations of the manifest descriptor,
+	// which PullFromIndex can select the artifact on.
+	Annotations map[string]string
+}
+
+// PushIndex assembles an image index referencing the given artifacts,
+// uploads it to the given OCI repository and returns the result. The
+// manifest descriptors of the artifacts are annotated with their name and
+// annotations, while the index itself is annotated with the Metadata of
+// WithPushMetadata, the other push options being ignored.
+func (c *Client) PushIndex(ctx context.Context, url string, entries []IndexEntry, opts ...PushOption) (*PushResult, error) {
+	ref, err := name.ParseReference(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no artifact to reference from the index")
+	}
+
+	o := newPushOptions(opts...)
+	if o.meta.Created == "" {
+		o.meta.Created = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	remoteOpts := crane.GetOptions(c.optionsWithContext(ctx)...).Remote
+	addenda := make([]mutate.IndexAddendum, 0, len(entries))
+	names := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("artifact '%s' has no name", entry.URL)
+		}
+		if _, ok := names[entry.Name]; ok {
+			return nil, fmt.Errorf("duplicate artifact name '%s'", entry.Name)
+		}
+		names[entry.Name] = struct{}{}
+
+		entryRef, err := name.ParseReference(entry.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL of artifact '%s': %w", entry.Name, err)
+		}
+		desc, err := remote.Get(entryRef, remoteOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("fetching artifact '%s' failed: %w", entry.Name, err)
+		}
+		if !desc.MediaType.IsImage() {
+			return nil, fmt.Errorf("artifact '%s' has unsupported media type '%s'", entry.Name, desc.MediaType)
+		}
+		img, err := desc.Image()
+		if err != nil {
+			return nil, fmt.Errorf("parsing artifact '%s' failed: %w", entry.Name, err)
+		}
+
+		annotations := maps.Clone(entry.Annotations)
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[ArtifactNameAnnotation] = entry.Name
+		addenda = append(addenda, mutate.IndexAddendum{
+			Add: img,
+			Descriptor: gcrv1.Descriptor{
+				MediaType:   desc.MediaType,
+				Annotations: annotations,
+			},
+		})
+	}
+
+	idx := mutate.IndexMediaType(empty.Index, types.OCIImageIndex)
+	idx = mutate.AppendManifests(idx, addenda...)
+	idx = mutate.Annotations(idx, o.meta.ToAnnotations()).(gcrv1.ImageIndex)
+
+	digest, err := idx.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("parsing index digest failed: %w", err)
+	}
+	result := &PushResult{Digest: ref.Context().Digest(digest.String()).String()}
+
+	// A failure to check the existence, e.g. the reference not existing yet,
+	// is handled by uploading the index.
+	if desc, err := remote.Head(ref, remoteOpts...); err == nil && desc.Digest == digest {
+		result.AlreadyExists = true
+		return result, nil
+	}
+
+	// The artifacts missing from the repository of the index are
+	// uploaded along with it.
+	if err := remote.WriteIndex(ref, idx, remoteOpts...); err != nil {
+		return nil, fmt.Errorf("pushing index failed: %w", err)
+	}
+	return result, nil
+}
+
+// ResolveFromIndex returns the digest URL of the single artifact of the
+// image index at the given URL whose manifest descriptor has all the
+// annotations of the selector, e.g. the ArtifactNameAnnotation. An error
+// listing the candidates is returned if none or several artifacts match.
+func (c *Client) ResolveFromIndex(ctx context.Context, indexURL string, selector map[string]string) (string, error) {
+	ref, err := name.ParseReference(indexURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	index, err := remote.Index(ref, crane.GetOptions(c.optionsWithContext(ctx)...).Remote...)
+	if err != nil {
+		return "", fmt.Errorf("fetching index failed: %w", err)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return "", fmt.Errorf("parsing index manifest failed: %w", err)
+	}
+
+	var matches []gcrv1.Descriptor
+	for _, desc := range indexManifest.Manifests {
+		if matchesSelector(desc.Annotations, selector) {
+			matches = append(matches, desc)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return ref.Context().Digest(matches[0].Digest.String()).String(), nil
+	case 0:
+		return "", fmt.Errorf("no artifact in index '%s' matches the selector '%s', candidates: %s",
+			indexURL, formatSelector(selector), formatCandidates(indexManifest.Manifests))
+	default:
+		return "", fmt.Errorf("%d artifacts in index '%s' match the selector '%s', candidates: %s",
+			len(matches), indexURL, formatSelector(selector), formatCandidates(matches))
+	}
+}
+
+// PullFromIndex resolves the artifact of the image index at the given URL
+// matching the selector as ResolveFromIndex does, then downloads it by
+// digest and extracts its content to the given outPath as Pull does.
+func (c *Client) PullFromIndex(ctx context.Context, indexURL string, selector map[string]string,
+	outPath string, opts ...PullOption) (*Metadata, error) {
+	ctx = withContentDescriptors(ctx)
+	url, err := c.ResolveFromIndex(ctx, indexURL, selector)
+	if err != nil {
+		return nil, err
+	}
+	return c.Pull(ctx, url, outPath, opts...)
+}
+
+// matchesSelector returns true if the annotations contain all the
+// key-value pairs of the selector.
+func matchesSelector(annotations, selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := annotations[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// formatSelector returns the key-value pairs of the selector sorted by key.
+func formatSelector(selector map[string]string) string {
+	pairs := make([]string, 0, len(selector))
+	for _, k := range slices.Sorted(maps.Keys(selector)) {
+		pairs = append(pairs, k+"="+selector[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// formatCandidates returns the names and digests of the manifest descriptors.
+func formatCandidates(descs []gcrv1.Descriptor) string {
+	if len(descs) == 0 {
+		return "none"
+	}
+	candidates := make([]string, 0, len(descs))
+	for _, desc := range descs {
+		candidate := desc.Digest.String()
+		if artifactName := desc.Annotations[ArtifactNameAnnotation]; artifactName != "" {
+			candidate = fmt.Sprintf("'%s' (%s)", artifactName, candidate)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return strings.Join(candidates, ", ")
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	. "github.com/onsi/gomega"
+)
+
+func Test_PushIndex_PullFromIndex(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	repo := fmt.Sprintf("%s/test-index-%s", dockerReg, randStringRunes(5))
+
+	stagingDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(stagingDir, "env.yaml"), []byte("env: staging\n"), 0o600)).To(Succeed())
+	production := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(production, "env.yaml"), []byte("env: production\n"), 0o600)).To(Succeed())
+
+	staging, err := c.Push(ctx, repo+":staging", stagingDir)
+	g.Expect(err).ToNot(HaveOccurred())
+	// The production artifact lives in another repository, and is copied
+	// to the repository of the index.
+	prod, err := c.Push(ctx, repo+"-prod:latest", production)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	entries := []IndexEntry{
+		{Name: "staging", URL: staging, Annotations: map[string]string{"io.fluxcd.artifact.tier": "test"}},
+		{Name: "production", URL: prod, Annotations: map[string]string{"io.fluxcd.artifact.tier": "test"}},
+	}
+	meta := Metadata{Source: "github.com/fluxcd/flux2", Revision: "v1.0.0"}
+	result, err := c.PushIndex(ctx, repo+":envs", entries, WithPushMetadata(meta))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.AlreadyExists).To(BeFalse())
+
+	index, err := crane.Manifest(repo+":envs", c.options...)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(index)).To(ContainSubstring(`"io.fluxcd.artifact.name":"staging"`))
+	g.Expect(string(index)).To(ContainSubstring(`"org.opencontainers.image.revision":"v1.0.0"`))
+
+	// Pushing the same index again is a no-op.
+	again, err := c.PushIndex(ctx, repo+":envs", entries, WithPushMetadata(meta))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(again.Digest).To(Equal(result.Digest))
+	g.Expect(again.AlreadyExists).To(BeTrue())
+
+	for _, tt := range []struct {
+		name     string
+		selector map[string]string
+		content  string
+		err      string
+	}{
+		{
+			name:     "pulls the artifact matching the name",
+			selector: map[string]string{ArtifactNameAnnotation: "staging"},
+			content:  "env: staging\n",
+		},
+		{
+			name:     "pulls the artifact copied from another repository",
+			selector: map[string]string{ArtifactNameAnnotation: "production", "io.fluxcd.artifact.tier": "test"},
+			content:  "env: production\n",
+		},
+		{
+			name:     "fails when no artifact matches",
+			selector: map[string]string{ArtifactNameAnnotation: "dev"},
+			err: fmt.Sprintf("no artifact in index '%s:envs' matches the selector 'io.fluxcd.artifact.name=dev', candidates: 'staging' (%s), 'production' (%s)",
+				repo, digestOf(staging), digestOf(prod)),
+		},
+		{
+			name:     "fails when several artifacts match",
+			selector: map[string]string{"io.fluxcd.artifact.tier": "test"},
+			err: fmt.Sprintf("2 artifacts in index '%s:envs' match the selector 'io.fluxcd.artifact.tier=test', candidates: 'staging' (%s), 'production' (%s)",
+				repo, digestOf(staging), digestOf(prod)),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			outPath := t.TempDir()
+			pulled, err := c.PullFromIndex(ctx, repo+":envs", tt.selector, outPath)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(tt.err))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pulled.Digest).To(HavePrefix(repo + "@"))
+
+			content, err := os.ReadFile(filepath.Join(outPath, "env.yaml"))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(content)).To(Equal(tt.content))
+		})
+	}
+}
+
+func Test_PushIndex_Errors(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	repo := fmt.Sprintf("%s/test-index-%s", dockerReg, randStringRunes(5))
+
+	artifact, err := c.Push(ctx, repo+":v1", "testdata/artifact")
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	for _, tt := range []struct {
+		name    string
+		entries []IndexEntry
+		err     string
+	}{
+		{
+			name: "no artifact",
+			err:  "no artifact to reference from the index",
+		},
+		{
+			name:    "unnamed artifact",
+			entries: []IndexEntry{{URL: artifact}},
+			err:     fmt.Sprintf("artifact '%s' has no name", artifact),
+		},
+		{
+			name:    "duplicate name",
+			entries: []IndexEntry{{Name: "a", URL: artifact}, {Name: "a", URL: artifact}},
+			err:     "duplicate artifact name 'a'",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := c.PushIndex(ctx, repo+":index", tt.entries)
+			g.Expect(err).To(MatchError(tt.err))
+		})
+	}
+}
+
+// digestOf returns the digest of the given digest URL.
+func digestOf(url string) string {
+	_, digest, _ := strings.Cut(url, "@")
+	return digest
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"hash"
+	"io"
+
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// IntegrityError is returned when the content downloaded from a registry
+// does not match the digest it is referenced by.
+type IntegrityError struct {
+	// Reference is the artifact reference that was pulled.
+	Reference string
+	// LayerIndex is the index of the mismatching layer in the manifest,
+	// or -1 if the manifest itself does not match the expected digest.
+	LayerIndex int
+	// Expected is the digest the content was expected to have.
+	Expected string
+	// Actual is the digest computed from the downloaded content.
+	Actual string
+	// Err is the underlying error, set to io.ErrUnexpectedEOF when the
+	// content ended before the size of the descriptor was read.
+	Err error
+}
+
+// Error implements error.
+func (e *IntegrityError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("integrity check failed for '%s': layer %d with digest '%s': %s",
+			e.Reference, e.LayerIndex, e.Expected, e.Err)
+	}
+	if e.LayerIndex < 0 {
+		return fmt.Sprintf("integrity check failed for '%s': manifest digest '%s' does not match expected digest '%s'",
+			e.Reference, e.Actual, e.Expected)
+	}
+	return fmt.Sprintf("integrity check failed for '%s': layer %d digest '%s' does not match expected digest '%s'",
+		e.Reference, e.LayerIndex, e.Actual, e.Expected)
+}
+
+// Unwrap returns the underlying error.
+func (e *IntegrityError) Unwrap() error {
+	return e.Err
+}
+
+// digestVerifier hashes the content read through it and checks it
+// against the expected digest once the expected size has been read.
+type digestVerifier struct {
+	reader     io.Reader
+	hasher     hash.Hash
+	expected   gcrv1.Hash
+	size       int64
+	read       int64
+	reference  string
+	layerIndex int
+}
+
+func newDigestVerifier(r io.Reader, desc gcrv1.Descriptor, reference string, layerIndex int) (*digestVerifier, error) {
+	h, err := gcrv1.Hasher(desc.Digest.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &digestVerifier{
+		reader:     r,
+		hasher:     h,
+		expected:   desc.Digest,
+		size:       desc.Size,
+		reference:  reference,
+		layerIndex: layerIndex,
+	}, nil
+}
+
+// Read implements io.Reader. Once all the bytes described by the
+// descriptor have been read, any error returned by the underlying
+// reader is replaced by an *IntegrityError if the digest does not match.
+// An EOF before the size of the descriptor is reached is returned as an
+// *IntegrityError wrapping io.ErrUnexpectedEOF.
+func (v *digestVerifier) Read(p []byte) (int, error) {
+	n, err := v.reader.Read(p)
+	v.hasher.Write(p[:n])
+	v.read += int64(n)
+	if err == io.EOF && v.read < v.size {
+		return n, &IntegrityError{
+			Reference:  v.reference,
+			LayerIndex: v.layerIndex,
+			Expected:   v.expected.String(),
+			Actual:     v.actual().String(),
+			Err:        io.ErrUnexpectedEOF,
+		}
+	}
+	if err != nil && v.read >= v.size {
+		if verr := v.verify(); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+// drain reads the remaining content and returns the verification result.
+func (v *digestVerifier) drain() error {
+	_, err := io.Copy(io.Discard, v)
+	return err
+}
+
+func (v *digestVerifier) actual() gcrv1.Hash {
+	return gcrv1.Hash{
+		Algorithm: v.expected.Algorithm,
+		Hex:       fmt.Sprintf("%x", v.hasher.Sum(nil)),
+	}
+}
+
+func (v *digestVerifier) verify() error {
+	actual := v.actual()
+	if actual != v.expected {
+		return &IntegrityError{
+			Reference:  v.reference,
+			LayerIndex: v.layerIndex,
+			Expected:   v.expected.String(),
+			Actual:     actual.String(),
+		}
+	}
+	return nil
+}
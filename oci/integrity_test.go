@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	. "github.com/onsi/gomega"
+)
+
+func Test_digestVerifierTruncatedBody(t *testing.T) {
+	content := []byte("the content of the layer blob")
+	digest, size, err := gcrv1.SHA256(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := gcrv1.Descriptor{Digest: digest, Size: size}
+
+	for _, tt := range []struct {
+		name string
+		read func(v *digestVerifier) error
+	}{
+		{
+			name: "read",
+			read: func(v *digestVerifier) error {
+				_, err := io.ReadAll(v)
+				return err
+			},
+		},
+		{
+			name: "drain",
+			read: func(v *digestVerifier) error {
+				return v.drain()
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			v, err := newDigestVerifier(bytes.NewReader(content[:len(content)/2]), desc, "test", 0)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = tt.read(v)
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue(), err.Error())
+
+			var integrityErr *IntegrityError
+			g.Expect(errors.As(err, &integrityErr)).To(BeTrue())
+			g.Expect(integrityErr.LayerIndex).To(Equal(0))
+			g.Expect(integrityErr.Expected).To(Equal(digest.String()))
+		})
+	}
+
+	t.Run("complete body", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v, err := newDigestVerifier(bytes.NewReader(content), desc, "test", 0)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v.drain()).To(Succeed())
+	})
+}
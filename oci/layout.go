@@ -0,0 +1,258 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// layoutRefNameAnnotation is the annotation of the OCI image layout index
+// holding the tag of the manifests.
+const layoutRefNameAnnotation = "org.opencontainers.image.ref.name"
+
+// TransferOption is a function for configuring the export and import of
+// artifacts to and from OCI image layouts.
+type TransferOption func(o *transferOptions)
+
+// transferOptions holds the options of the export and import of artifacts.
+type transferOptions struct {
+	signatures bool
+}
+
+// WithTransferSignatures transfers the cosign signatures and attestations
+// of the artifact along with it, from the signature and attestation tags,
+// and the referrers of the artifact, e.g. the attestations attached with
+// the referrers API. On export, the signatures are written to the layout,
+// and on import, the signatures of the layout are pushed to the target
+// repository.
+func WithTransferSignatures() TransferOption {
+	return func(o *transferOptions) {
+		o.signatures = true
+	}
+}
+
+// newTransferOptions returns the transferOptions with the given options applied.
+func newTransferOptions(opts ...TransferOption) *transferOptions {
+	o := &transferOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Export writes the artifact at the given URL, with its manifest, config
+// and layers, to a new OCI image layout in layoutDir, e.g. for transferring
+// the artifact to an air-gapped registry with Import. The artifact is tagged
+// in the layout with the tag of the URL, or its digest. It returns the
+// digest URL of the artifact.
+func (c *Client) Export(ctx context.Context, url, layoutDir string, opts ...TransferOption) (string, error) {
+	o := newTransferOptions(opts...)
+
+	ref, err := name.ParseReference(url)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(layoutDir, "index.json")); err == nil {
+		return "", fmt.Errorf("'%s' already contains an OCI image layout", layoutDir)
+	}
+	remoteOpts := crane.GetOptions(c.optionsWithContext(ctx)...).Remote
+
+	desc, err := remote.Get(ref, remoteOpts...)
+	if err != nil {
+		return "", fmt.Errorf("fetching artifact '%s' failed: %w", url, err)
+	}
+
+	path, err := layout.Write(layoutDir, empty.Index)
+	if err != nil {
+		return "", fmt.Errorf("creating OCI image layout '%s' failed: %w", layoutDir, err)
+	}
+
+	if err := appendToLayout(path, desc, ref.Identifier()); err != nil {
+		return "", fmt.Errorf("writing artifact '%s' failed: %w", url, err)
+	}
+
+	if o.signatures {
+		repo := ref.Context()
+		for _, tag := range []name.Tag{
+			cosignSignatureTag(repo, desc.Digest),
+			cosignAttestationTag(repo, desc.Digest),
+		} {
+			img, err := c.pullSignatures(ctx, tag)
+			if err != nil {
+				return "", err
+			}
+			if img == nil {
+				continue
+			}
+			if err := path.AppendImage(img, layout.WithAnnotations(map[string]string{
+				layoutRefNameAnnotation: tag.TagStr(),
+			})); err != nil {
+				return "", fmt.Errorf("writing signatures '%s' failed: %w", tag, err)
+			}
+		}
+
+		// The referrers are written without tag.
+		index, err := remote.Referrers(repo.Digest(desc.Digest.String()), remoteOpts...)
+		if err != nil {
+			return "", fmt.Errorf("fetching referrers for '%s' failed: %w", desc.Digest, err)
+		}
+		indexManifest, err := index.IndexManifest()
+		if err != nil {
+			return "", fmt.Errorf("parsing referrers for '%s' failed: %w", desc.Digest, err)
+		}
+		for _, referrer := range indexManifest.Manifests {
+			referrerDesc, err := remote.Get(repo.Digest(referrer.Digest.String()), remoteOpts...)
+			if err != nil {
+				return "", fmt.Errorf("fetching referrer '%s' failed: %w", referrer.Digest, err)
+			}
+			if err := appendToLayout(path, referrerDesc, ""); err != nil {
+				return "", fmt.Errorf("writing referrer '%s' failed: %w", referrer.Digest, err)
+			}
+		}
+	}
+
+	return ref.Context().Digest(desc.Digest.String()).String(), nil
+}
+
+// Import pushes the artifact of the OCI image layout in layoutDir, written
+// by Export, to the given URL. The manifests are pushed as is, the digest of
+// the artifact is preserved, and it must match the digest of the URL if
+// any. It returns the digest URL of the artifact.
+func (c *Client) Import(ctx context.Context, layoutDir, url string, opts ...TransferOption) (string, error) {
+	o := newTransferOptions(opts...)
+
+	ref, err := name.ParseReference(url)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	remoteOpts := crane.GetOptions(c.optionsWithContext(ctx)...).Remote
+
+	path, err := layout.FromPath(layoutDir)
+	if err != nil {
+		return "", fmt.Errorf("reading OCI image layout '%s' failed: %w", layoutDir, err)
+	}
+	index, err := path.ImageIndex()
+	if err != nil {
+		return "", fmt.Errorf("reading OCI image layout '%s' failed: %w", layoutDir, err)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return "", fmt.Errorf("parsing OCI image layout '%s' index failed: %w", layoutDir, err)
+	}
+
+	var artifact *gcrv1.Descriptor
+	var signatures, referrers []gcrv1.Descriptor
+	for _, desc := range indexManifest.Manifests {
+		tag := desc.Annotations[layoutRefNameAnnotation]
+		switch {
+		case tag == "":
+			referrers = append(referrers, desc)
+		case isCosignTag(tag):
+			signatures = append(signatures, desc)
+		case artifact != nil:
+			return "", fmt.Errorf("the OCI image layout '%s' contains more than one artifact", layoutDir)
+		default:
+			artifact = &desc
+		}
+	}
+	if artifact == nil {
+		return "", fmt.Errorf("the OCI image layout '%s' contains no artifact", layoutDir)
+	}
+	if d, ok := ref.(name.Digest); ok && d.DigestStr() != artifact.Digest.String() {
+		return "", fmt.Errorf("the digest of the artifact '%s' does not match the digest of the URL '%s'",
+			artifact.Digest, url)
+	}
+
+	if err := writeFromLayout(index, *artifact, ref, remoteOpts); err != nil {
+		return "", fmt.Errorf("pushing artifact failed: %w", err)
+	}
+
+	if o.signatures {
+		for _, desc := range signatures {
+			tag := ref.Context().Tag(desc.Annotations[layoutRefNameAnnotation])
+			if err := writeFromLayout(index, desc, tag, remoteOpts); err != nil {
+				return "", fmt.Errorf("pushing signatures '%s' failed: %w", tag, err)
+			}
+		}
+		for _, desc := range referrers {
+			if err := writeFromLayout(index, desc, ref.Context().Digest(desc.Digest.String()), remoteOpts); err != nil {
+				return "", fmt.Errorf("pushing referrer '%s' failed: %w", desc.Digest, err)
+			}
+		}
+	}
+
+	return ref.Context().Digest(artifact.Digest.String()).String(), nil
+}
+
+// appendToLayout appends the image or index of the given descriptor to the
+// layout, tagged with the given tag if not empty.
+func appendToLayout(path layout.Path, desc *remote.Descriptor, tag string) error {
+	var opts []layout.Option
+	if tag != "" {
+		opts = append(opts, layout.WithAnnotations(map[string]string{layoutRefNameAnnotation: tag}))
+	}
+	if desc.MediaType.IsIndex() {
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return err
+		}
+		return path.AppendIndex(idx, opts...)
+	}
+	img, err := desc.Image()
+	if err != nil {
+		return err
+	}
+	return path.AppendImage(img, opts...)
+}
+
+// writeFromLayout pushes the image or index of the given descriptor of the
+// layout index to the given reference.
+func writeFromLayout(index gcrv1.ImageIndex, desc gcrv1.Descriptor, ref name.Reference, opts []remote.Option) error {
+	if desc.MediaType.IsIndex() {
+		idx, err := index.ImageIndex(desc.Digest)
+		if err != nil {
+			return err
+		}
+		return remote.WriteIndex(ref, idx, opts...)
+	}
+	img, err := index.Image(desc.Digest)
+	if err != nil {
+		return err
+	}
+	return remote.Write(ref, img, opts...)
+}
+
+// isCosignTag returns true if the given tag is a cosign signature or
+// attestation tag.
+func isCosignTag(tag string) bool {
+	algorithm, rest, ok := strings.Cut(tag, "-")
+	if !ok || algorithm != "sha256" {
+		return false
+	}
+	return strings.HasSuffix(rest, ".sig") || strings.HasSuffix(rest, ".att")
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	. "github.com/onsi/gomega"
+)
+
+func Test_ExportImport(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	password := []byte("s3cr3t")
+	priv, privPEM, pubPEM := generateCosignKeyPair(t, password)
+	signer, err := NewSignerFromKey(privPEM, password)
+	g.Expect(err).NotTo(HaveOccurred())
+	verifier, err := NewVerifierFromKey(pubPEM)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Push a signed artifact with an attestation attached as a referrer.
+	url := fmt.Sprintf("%s/test-export-%s:v1.0.0", dockerReg, randStringRunes(5))
+	_, err = c.Push(ctx, url, "testdata/artifact")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = c.Sign(ctx, url, signer)
+	g.Expect(err).NotTo(HaveOccurred())
+	digest, err := crane.Digest(url, c.options...)
+	g.Expect(err).NotTo(HaveOccurred())
+	hash, err := gcrv1.NewHash(digest)
+	g.Expect(err).NotTo(HaveOccurred())
+	const predicateType = "https://slsa.dev/provenance/v1"
+	attachAttestation(t, c, url, priv, predicateType, hash, true)
+
+	t.Run("round-trips the artifact with the signatures", func(t *testing.T) {
+		g := NewWithT(t)
+
+		layoutDir := filepath.Join(t.TempDir(), "layout")
+		exported, err := c.Export(ctx, url, layoutDir, WithTransferSignatures())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(exported).To(HaveSuffix("@" + digest))
+
+		target := fmt.Sprintf("%s/test-import-%s:v1.0.0", dockerReg, randStringRunes(5))
+		imported, err := c.Import(ctx, layoutDir, target, WithTransferSignatures())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(imported).To(HaveSuffix("@" + digest))
+
+		targetDigest, err := crane.Digest(target, c.options...)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(targetDigest).To(Equal(digest))
+
+		result, err := c.VerifyArtifact(ctx, target, verifier,
+			WithRequiredAttestations([]string{predicateType}, MissingPolicyFail))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(result.Attestations).To(HaveLen(1))
+
+		meta, err := c.Pull(ctx, target, t.TempDir())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(meta.Digest).To(HaveSuffix("@" + digest))
+	})
+
+	t.Run("transfers the signatures only when opted in", func(t *testing.T) {
+		g := NewWithT(t)
+
+		layoutDir := filepath.Join(t.TempDir(), "layout")
+		_, err := c.Export(ctx, url, layoutDir)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		target := fmt.Sprintf("%s/test-import-%s:v1.0.0", dockerReg, randStringRunes(5))
+		_, err = c.Import(ctx, layoutDir, target, WithTransferSignatures())
+		g.Expect(err).NotTo(HaveOccurred())
+		targetDigest, err := crane.Digest(target, c.options...)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(targetDigest).To(Equal(digest))
+
+		err = c.Verify(ctx, target, verifier)
+		g.Expect(err).To(MatchError(ContainSubstring("no signatures found")))
+
+		// The signatures of the layout are not pushed without the option.
+		layoutDir = filepath.Join(t.TempDir(), "layout")
+		_, err = c.Export(ctx, url, layoutDir, WithTransferSignatures())
+		g.Expect(err).NotTo(HaveOccurred())
+		_, err = c.Import(ctx, layoutDir, target)
+		g.Expect(err).NotTo(HaveOccurred())
+		err = c.Verify(ctx, target, verifier)
+		g.Expect(err).To(MatchError(ContainSubstring("no signatures found")))
+	})
+
+	t.Run("imports by digest", func(t *testing.T) {
+		g := NewWithT(t)
+
+		layoutDir := filepath.Join(t.TempDir(), "layout")
+		_, err := c.Export(ctx, url, layoutDir)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		repo := fmt.Sprintf("%s/test-import-%s", dockerReg, randStringRunes(5))
+		imported, err := c.Import(ctx, layoutDir, repo+"@"+digest)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(imported).To(Equal(repo + "@" + digest))
+
+		_, err = c.Import(ctx, layoutDir, repo+"@sha256:"+hash.Hex[1:]+"0")
+		g.Expect(err).To(MatchError(ContainSubstring("does not match the digest of the URL")))
+	})
+
+	t.Run("does not overwrite a layout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		layoutDir := filepath.Join(t.TempDir(), "layout")
+		_, err := c.Export(ctx, url, layoutDir)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, err = c.Export(ctx, url, layoutDir)
+		g.Expect(err).To(MatchError(ContainSubstring("already contains an OCI image layout")))
+	})
+
+	t.Run("fails for an invalid layout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := c.Import(ctx, t.TempDir(), url)
+		g.Expect(err).To(MatchError(ContainSubstring("reading OCI image layout")))
+	})
+}
+
+func Test_isCosignTag(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, err := name.NewRepository("registry/repo")
+	g.Expect(err).NotTo(HaveOccurred())
+	hash, err := gcrv1.NewHash("sha256:" + fmt.Sprintf("%064x", 1))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(isCosignTag(cosignSignatureTag(repo, hash).TagStr())).To(BeTrue())
+	g.Expect(isCosignTag(cosignAttestationTag(repo, hash).TagStr())).To(BeTrue())
+	g.Expect(isCosignTag("v1.0.0")).To(BeFalse())
+	g.Expect(isCosignTag("sha256-" + hash.Hex)).To(BeFalse())
+	g.Expect(isCosignTag(hash.String())).To(BeFalse())
+}
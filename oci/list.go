@@ -45,7 +45,7 @@ type ListOptions struct {
 // List fetches the tags and their manifests for a given OCI repository.
 func (c *Client) List(ctx context.Context, url string, opts ListOptions) ([]Metadata, error) {
 	metas := make([]Metadata, 0)
-	tags, err := crane.ListTags(url, c.options...)
+	tags, err := crane.ListTags(url, c.optionsWithContext(ctx)...)
 	if err != nil {
 		return nil, fmt.Errorf("listing tags failed: %w", err)
 	}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// memFS is a read-only in-memory file system holding the content
+// of a pulled artifact. It implements fs.ReadDirFS and fs.ReadFileFS.
+type memFS struct {
+	entries map[string]*memEntry
+}
+
+// memEntry is a file or directory of a memFS.
+type memEntry struct {
+	name string
+	mode fs.FileMode
+	data []byte
+}
+
+var (
+	_ fs.ReadDirFS  = &memFS{}
+	_ fs.ReadFileFS = &memFS{}
+	_ fs.StatFS     = &memFS{}
+)
+
+// newMemFS returns a memFS with an empty root directory.
+func newMemFS() *memFS {
+	return &memFS{
+		entries: map[string]*memEntry{
+			".": {name: ".", mode: fs.ModeDir | defaultDirMode},
+		},
+	}
+}
+
+// addDir adds the directory and its missing parents to the file system.
+func (m *memFS) addDir(name string, perm fs.FileMode) {
+	if name == "." {
+		if perm != 0 {
+			m.entries["."].mode = fs.ModeDir | perm
+		}
+		return
+	}
+	m.addParents(name)
+	if perm == 0 {
+		perm = defaultDirMode
+	}
+	m.entries[name] = &memEntry{name: path.Base(name), mode: fs.ModeDir | perm}
+}
+
+// addFile adds the regular file and its missing parents to the file system.
+func (m *memFS) addFile(name string, data []byte, perm fs.FileMode) {
+	m.addParents(name)
+	m.entries[name] = &memEntry{name: path.Base(name), mode: perm, data: data}
+}
+
+// addParents adds the missing parent directories of name.
+func (m *memFS) addParents(name string) {
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if _, ok := m.entries[dir]; ok {
+			return
+		}
+		m.entries[dir] = &memEntry{name: path.Base(dir), mode: fs.ModeDir | defaultDirMode}
+	}
+}
+
+// Open opens the named file or directory.
+func (m *memFS) Open(name string) (fs.File, error) {
+	e, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if e.mode.IsDir() {
+		entries, _ := m.ReadDir(name)
+		return &memDir{entry: e, entries: entries}, nil
+	}
+	return &memFile{entry: e, Reader: bytes.NewReader(e.data)}, nil
+}
+
+// ReadFile returns the content of the named file.
+func (m *memFS) ReadFile(name string) ([]byte, error) {
+	e, err := m.lookup("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if e.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	return bytes.Clone(e.data), nil
+}
+
+// Stat returns the FileInfo of the named file or directory.
+func (m *memFS) Stat(name string) (fs.FileInfo, error) {
+	e, err := m.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ReadDir returns the entries of the named directory, sorted by name.
+func (m *memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	e, err := m.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !e.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	var entries []fs.DirEntry
+	for p, child := range m.entries {
+		if p == "." || !strings.HasPrefix(p, prefix) || strings.Contains(p[len(prefix):], "/") {
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(child))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+func (m *memFS) lookup(op, name string) (*memEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	e, ok := m.entries[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return e, nil
+}
+
+// memEntry implements fs.FileInfo.
+func (e *memEntry) Name() string       { return e.name }
+func (e *memEntry) Size() int64        { return int64(len(e.data)) }
+func (e *memEntry) Mode() fs.FileMode  { return e.mode }
+func (e *memEntry) ModTime() time.Time { return time.Time{} }
+func (e *memEntry) IsDir() bool        { return e.mode.IsDir() }
+func (e *memEntry) Sys() any           { return nil }
+
+// memFile is an open regular file of a memFS.
+type memFile struct {
+	*bytes.Reader
+	entry *memEntry
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.entry, nil }
+func (f *memFile) Close() error               { return nil }
+
+// memDir is an open directory of a memFS.
+type memDir struct {
+	entry   *memEntry
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.entry, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.entry.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return remaining[:n], nil
+}
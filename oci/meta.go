@@ -25,6 +25,19 @@ type Metadata struct {
 	Digest      string            `json:"digest"`
 	URL         string            `json:"url"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Extraction holds the number of extracted and skipped entries
+	// of a pull with WithExtractPaths, nil otherwise.
+	Extraction *ExtractStats `json:"extraction,omitempty"`
+
+	// Warnings holds the missing provenance annotations of a pull with
+	// WithExpectedSource or WithExpectedRevisionPrefix, when the
+	// MissingPolicyWarn policy is used.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// RateLimit holds the last rate limit reported by the registry during
+	// a pull, e.g. the remaining pulls of Docker Hub, nil if not reported.
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
 }
 
 // ToAnnotations returns the OpenContainers annotations map.
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithExpectedSource requires the source annotation of the pulled artifact,
+// i.e. 'org.opencontainers.image.source', to be equal to the given URL.
+// The pull fails with a *ProvenanceError if the annotation has another
+// value. When the annotation is missing, the pull fails or reports a
+// warning according to the policy set with WithMissingProvenancePolicy.
+func WithExpectedSource(url string) PullOption {
+	return func(o *PullOptions) {
+		o.expectedSource = url
+	}
+}
+
+// WithExpectedRevisionPrefix requires the revision annotation of the pulled
+// artifact, i.e. 'org.opencontainers.image.revision', to start with the
+// given reference, e.g. 'main@sha1:' or 'v1.2.3'. The pull fails with a
+// *ProvenanceError if the annotation has another value. When the annotation
+// is missing, the pull fails or reports a warning according to the policy
+// set with WithMissingProvenancePolicy.
+func WithExpectedRevisionPrefix(ref string) PullOption {
+	return func(o *PullOptions) {
+		o.expectedRevisionPrefix = ref
+	}
+}
+
+// WithMissingProvenancePolicy sets the policy applied when the artifact
+// does not carry the annotations required by WithExpectedSource or
+// WithExpectedRevisionPrefix. With MissingPolicyWarn, the missing
+// annotations are reported in the Warnings of the returned Metadata.
+// Defaults to MissingPolicyFail.
+func WithMissingProvenancePolicy(policy MissingPolicy) PullOption {
+	return func(o *PullOptions) {
+		o.missingProvenancePolicy = policy
+	}
+}
+
+// ProvenanceError is returned when the source or revision annotation of a
+// pulled artifact does not match the expected provenance.
+type ProvenanceError struct {
+	// Reference is the artifact reference that was pulled.
+	Reference string
+	// Annotation is the key of the mismatching annotation.
+	Annotation string
+	// Expected is the expected value of the annotation, or the expected
+	// prefix for the revision annotation.
+	Expected string
+	// Actual is the value of the annotation, empty if it is missing.
+	Actual string
+}
+
+// Error implements error.
+func (e *ProvenanceError) Error() string {
+	if e.Actual == "" {
+		return fmt.Sprintf("provenance check failed for '%s': annotation '%s' is missing, expected '%s'",
+			e.Reference, e.Annotation, e.Expected)
+	}
+	if e.Annotation == RevisionAnnotation {
+		return fmt.Sprintf("provenance check failed for '%s': annotation '%s' value '%s' does not start with expected prefix '%s'",
+			e.Reference, e.Annotation, e.Actual, e.Expected)
+	}
+	return fmt.Sprintf("provenance check failed for '%s': annotation '%s' value '%s' does not match expected value '%s'",
+		e.Reference, e.Annotation, e.Actual, e.Expected)
+}
+
+// verifyProvenance checks the source and revision of the artifact metadata
+// against the expected provenance. The missing annotations are added to the
+// warnings of the metadata when the MissingPolicyWarn policy is used.
+func verifyProvenance(meta *Metadata, o *PullOptions) error {
+	checks := []struct {
+		annotation string
+		expected   string
+		actual     string
+		match      func(actual, expected string) bool
+	}{
+		{
+			annotation: SourceAnnotation,
+			expected:   o.expectedSource,
+			actual:     meta.Source,
+			match:      func(actual, expected string) bool { return actual == expected },
+		},
+		{
+			annotation: RevisionAnnotation,
+			expected:   o.expectedRevisionPrefix,
+			actual:     meta.Revision,
+			match:      strings.HasPrefix,
+		},
+	}
+
+	for _, check := range checks {
+		if check.expected == "" {
+			continue
+		}
+		provenanceErr := &ProvenanceError{
+			Reference:  meta.URL,
+			Annotation: check.annotation,
+			Expected:   check.expected,
+			Actual:     check.actual,
+		}
+		switch {
+		case check.actual == "" && o.missingProvenancePolicy == MissingPolicyWarn:
+			meta.Warnings = append(meta.Warnings, provenanceErr.Error())
+		case check.actual == "" || !check.match(check.actual, check.expected):
+			return provenanceErr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_PullWithExpectedProvenance(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	const (
+		source   = "https://github.com/fluxcd/flux2"
+		revision = "main@sha1:66e6db7fd6afa3c26d0c6d00cd5aba6c0a6c3fa4"
+	)
+	url := fmt.Sprintf("%s/test-provenance-%s:latest", dockerReg, randStringRunes(5))
+	_, err := c.Push(ctx, url, "testdata/artifact", WithPushMetadata(Metadata{
+		Source:   source,
+		Revision: revision,
+	}))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	unannotatedURL := fmt.Sprintf("%s/test-provenance-%s:latest", dockerReg, randStringRunes(5))
+	_, err = c.Push(ctx, unannotatedURL, "testdata/artifact")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tests := []struct {
+		name         string
+		url          string
+		opts         []PullOption
+		wantErr      *ProvenanceError
+		wantWarnings int
+	}{
+		{
+			name: "matching source and revision",
+			url:  url,
+			opts: []PullOption{WithExpectedSource(source), WithExpectedRevisionPrefix("main@sha1:")},
+		},
+		{
+			name: "mismatching source",
+			url:  url,
+			opts: []PullOption{WithExpectedSource("https://github.com/fluxcd/flux2-multi-tenancy")},
+			wantErr: &ProvenanceError{
+				Reference:  url,
+				Annotation: SourceAnnotation,
+				Expected:   "https://github.com/fluxcd/flux2-multi-tenancy",
+				Actual:     source,
+			},
+		},
+		{
+			name: "mismatching revision prefix",
+			url:  url,
+			opts: []PullOption{WithExpectedSource(source), WithExpectedRevisionPrefix("v1.0.0@sha1:")},
+			wantErr: &ProvenanceError{
+				Reference:  url,
+				Annotation: RevisionAnnotation,
+				Expected:   "v1.0.0@sha1:",
+				Actual:     revision,
+			},
+		},
+		{
+			name: "missing annotations fail by default",
+			url:  unannotatedURL,
+			opts: []PullOption{WithExpectedSource(source)},
+			wantErr: &ProvenanceError{
+				Reference:  unannotatedURL,
+				Annotation: SourceAnnotation,
+				Expected:   source,
+			},
+		},
+		{
+			name: "missing annotations fail with the fail policy",
+			url:  unannotatedURL,
+			opts: []PullOption{WithExpectedRevisionPrefix("main@sha1:"), WithMissingProvenancePolicy(MissingPolicyFail)},
+			wantErr: &ProvenanceError{
+				Reference:  unannotatedURL,
+				Annotation: RevisionAnnotation,
+				Expected:   "main@sha1:",
+			},
+		},
+		{
+			name: "missing annotations warn with the warn policy",
+			url:  unannotatedURL,
+			opts: []PullOption{
+				WithExpectedSource(source),
+				WithExpectedRevisionPrefix("main@sha1:"),
+				WithMissingProvenancePolicy(MissingPolicyWarn),
+			},
+			wantWarnings: 2,
+		},
+		{
+			name: "mismatching annotations fail with the warn policy",
+			url:  url,
+			opts: []PullOption{WithExpectedSource("https://example.com"), WithMissingProvenancePolicy(MissingPolicyWarn)},
+			wantErr: &ProvenanceError{
+				Reference:  url,
+				Annotation: SourceAnnotation,
+				Expected:   "https://example.com",
+				Actual:     source,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			extractTo := filepath.Join(t.TempDir(), "artifact")
+			m, err := c.Pull(ctx, tt.url, extractTo, tt.opts...)
+			if tt.wantErr != nil {
+				var provenanceErr *ProvenanceError
+				g.Expect(errors.As(err, &provenanceErr)).To(BeTrue(), "unexpected error: %v", err)
+				g.Expect(provenanceErr).To(Equal(tt.wantErr))
+				// The content is not extracted.
+				g.Expect(extractTo).ToNot(BeADirectory())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(m.Warnings).To(HaveLen(tt.wantWarnings))
+			for _, w := range m.Warnings {
+				g.Expect(w).To(ContainSubstring("is missing"))
+			}
+			g.Expect(filepath.Join(extractTo, "deployment.yaml")).To(BeAnExistingFile())
+		})
+	}
+}
+
+func TestProvenanceError_Error(t *testing.T) {
+	g := NewWithT(t)
+
+	err := &ProvenanceError{
+		Reference:  "ghcr.io/org/repo:latest",
+		Annotation: SourceAnnotation,
+		Expected:   "https://github.com/org/a",
+		Actual:     "https://github.com/org/b",
+	}
+	g.Expect(err.Error()).To(Equal("provenance check failed for 'ghcr.io/org/repo:latest': annotation " +
+		"'org.opencontainers.image.source' value 'https://github.com/org/b' does not match expected value 'https://github.com/org/a'"))
+
+	err = &ProvenanceError{
+		Reference:  "ghcr.io/org/repo:latest",
+		Annotation: RevisionAnnotation,
+		Expected:   "main@sha1:",
+		Actual:     "dev@sha1:abc",
+	}
+	g.Expect(err.Error()).To(Equal("provenance check failed for 'ghcr.io/org/repo:latest': annotation " +
+		"'org.opencontainers.image.revision' value 'dev@sha1:abc' does not start with expected prefix 'main@sha1:'"))
+
+	err.Actual = ""
+	g.Expect(err.Error()).To(Equal("provenance check failed for 'ghcr.io/org/repo:latest': annotation " +
+		"'org.opencontainers.image.revision' is missing, expected 'main@sha1:'"))
+}
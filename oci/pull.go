@@ -20,8 +20,10 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 
 	"github.com/google/go-containerregistry/pkg/crane"
@@ -39,8 +41,16 @@ var (
 
 // PullOptions contains options for pulling a layer.
 type PullOptions struct {
-	layerIndex int
-	layerType  LayerType
+	layerIndex     int
+	layerType      LayerType
+	expectedDigest string
+	extractPaths   []string
+
+	expectedSource          string
+	expectedRevisionPrefix  string
+	missingProvenancePolicy MissingPolicy
+
+	maxFSSize int
 }
 
 // PullOption is a function for configuring PullOptions.
@@ -60,39 +70,85 @@ func WithPullLayerIndex(i int) PullOption {
 	}
 }
 
+// WithExpectedDigest pins the artifact to the given manifest digest,
+// e.g. 'sha256:...'. The manifest is requested by digest instead of
+// being resolved through the tag in the URL, so the pulled content can
+// not change between resolution and download. The Digest field of the
+// returned Metadata holds the verified digest.
+func WithExpectedDigest(d string) PullOption {
+	return func(o *PullOptions) {
+		o.expectedDigest = d
+	}
+}
+
+// WithPullFSMaxSize sets the limit size (bytes) of the content extracted
+// into memory by PullFS, tar.DefaultMaxUntarSize by default. When max is
+// equal or less than 0 disables size checks.
+func WithPullFSMaxSize(max int) PullOption {
+	return func(o *PullOptions) {
+		o.maxFSSize = max
+	}
+}
+
+// StaticLayerFileName is the name of the file holding the content of
+// a static layer in the file system returned by PullFS.
+const StaticLayerFileName = "artifact"
+
 // Pull downloads an artifact from an OCI repository and extracts the content.
 // It untar or copies the content to the given outPath depending on the layerType.
 // If no layer type is given, it tries to determine the right type by checking compressed content of the layer.
 func (c *Client) Pull(ctx context.Context, url, outPath string, opts ...PullOption) (*Metadata, error) {
+	return c.pull(ctx, url, func(blob io.Reader, layerType LayerType, selector *extractPathSelector) error {
+		return extractLayerType(outPath, blob, layerType, selector)
+	}, opts...)
+}
+
+// PullFS downloads an artifact from an OCI repository and returns its content
+// as a read-only in-memory file system, without writing to disk. Tarball layers
+// are extracted preserving the file modes, while the content of static layers
+// is exposed as a single file named StaticLayerFileName. The extracted content
+// is capped at tar.DefaultMaxUntarSize bytes; use WithPullFSMaxSize to raise,
+// lower, or disable the limit.
+// If no layer type is given, it tries to determine the right type by checking compressed content of the layer.
+func (c *Client) PullFS(ctx context.Context, url string, opts ...PullOption) (fs.FS, error) {
 	o := &PullOptions{
-		layerIndex: 0,
+		maxFSSize: tar.DefaultMaxUntarSize,
 	}
 	for _, opt := range opts {
 		opt(o)
 	}
-	ref, err := name.ParseReference(url)
-	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
-	}
 
-	img, err := crane.Pull(url, c.optionsWithContext(ctx)...)
+	var fsys fs.FS
+	_, err := c.pull(ctx, url, func(blob io.Reader, layerType LayerType, selector *extractPathSelector) error {
+		var err error
+		fsys, err = extractLayerTypeFS(blob, layerType, selector, o.maxFSSize)
+		return err
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
+	return fsys, nil
+}
 
-	digest, err := img.Digest()
-	if err != nil {
-		return nil, fmt.Errorf("parsing digest failed: %w", err)
+// pull downloads an artifact from an OCI repository and extracts the content
+// of the selected layer with the given extract function.
+func (c *Client) pull(ctx context.Context, url string, extract selectiveBlobExtractor, opts ...PullOption) (*Metadata, error) {
+	o := &PullOptions{
+		layerIndex: 0,
+	}
+	for _, opt := range opts {
+		opt(o)
 	}
 
-	manifest, err := img.Manifest()
+	selector, err := newExtractPathSelector(o.extractPaths)
 	if err != nil {
-		return nil, fmt.Errorf("parsing manifest failed: %w", err)
+		return nil, err
 	}
 
-	meta := MetadataFromAnnotations(manifest.Annotations)
-	meta.URL = url
-	meta.Digest = ref.Context().Digest(digest.String()).String()
+	img, manifest, meta, err := c.fetchImage(ctx, url, o)
+	if err != nil {
+		return nil, err
+	}
 
 	layers, err := img.Layers()
 	if err != nil {
@@ -107,21 +163,136 @@ func (c *Client) Pull(ctx context.Context, url, outPath string, opts ...PullOpti
 		return nil, fmt.Errorf("index '%d' out of bound for '%d' layers in artifact", o.layerIndex, len(layers))
 	}
 
-	err = extractLayer(layers[o.layerIndex], outPath, o.layerType)
+	desc := manifest.Layers[o.layerIndex]
+	limit := c.newPullSizeLimit(url, desc.Size)
+	if err := limit.check(); err != nil {
+		return nil, err
+	}
+
+	err = extractLayer(layers[o.layerIndex], desc, url, o.layerIndex, o.layerType, limit,
+		func(blob io.Reader, layerType LayerType) error {
+			return extract(blob, layerType, selector)
+		})
 	if err != nil {
 		return nil, err
 	}
+	if meta.Extraction, err = selector.result(); err != nil {
+		return nil, err
+	}
 	return meta, nil
 }
 
-// extractLayer extracts the Layer to the path
-func extractLayer(layer gcrv1.Layer, path string, layerType LayerType) error {
-	var blob io.Reader
-	blob, err := layer.Compressed()
+// fetchImage resolves the artifact at the given URL, pinning it to the
+// expected digest if any, verifies its provenance against the expected
+// source and revision if any, and returns the image with its manifest and
+// metadata.
+func (c *Client) fetchImage(ctx context.Context, url string, o *PullOptions) (gcrv1.Image, *gcrv1.Manifest, *Metadata, error) {
+	ref, err := name.ParseReference(url)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	pullRef := ref
+	if o.expectedDigest != "" {
+		if _, err := gcrv1.NewHash(o.expectedDigest); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid expected digest '%s': %w", o.expectedDigest, err)
+		}
+		if d, ok := ref.(name.Digest); ok && d.DigestStr() != o.expectedDigest {
+			return nil, nil, nil, fmt.Errorf("URL digest '%s' does not match expected digest '%s'",
+				d.DigestStr(), o.expectedDigest)
+		}
+		pullRef = ref.Context().Digest(o.expectedDigest)
+	}
+
+	ctx, rateLimits := withRateLimitRecorder(ctx)
+	img, err := crane.Pull(pullRef.String(), c.optionsWithContext(ctx)...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parsing digest failed: %w", err)
+	}
+
+	if o.expectedDigest != "" && digest.String() != o.expectedDigest {
+		return nil, nil, nil, &IntegrityError{
+			Reference:  url,
+			LayerIndex: -1,
+			Expected:   o.expectedDigest,
+			Actual:     digest.String(),
+		}
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parsing manifest failed: %w", err)
+	}
+
+	meta := MetadataFromAnnotations(manifest.Annotations)
+	meta.URL = url
+	meta.Digest = ref.Context().Digest(digest.String()).String()
+	meta.RateLimit = rateLimits.get()
+
+	if err := verifyProvenance(meta, o); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return img, manifest, meta, nil
+}
+
+// blobExtractor extracts the content of a layer blob of the given type.
+type blobExtractor func(blob io.Reader, layerType LayerType) error
+
+// selectiveBlobExtractor extracts the entries of a layer blob of the given
+// type selected by the extract path selector.
+type selectiveBlobExtractor func(blob io.Reader, layerType LayerType, selector *extractPathSelector) error
+
+// extractLayer extracts the Layer with the extract function, verifying the
+// downloaded content against the digest of the layer descriptor and
+// enforcing the size limit while streaming.
+func extractLayer(layer gcrv1.Layer, desc gcrv1.Descriptor, reference string, layerIndex int,
+	layerType LayerType, limit *pullSizeLimit, extract blobExtractor) error {
+	rc, err := layer.Compressed()
+	if err != nil {
+		return fmt.Errorf("extracting layer failed: %w", err)
+	}
+	defer rc.Close()
+
+	verifier, err := newDigestVerifier(limit.reader(rc), desc, reference, layerIndex)
 	if err != nil {
 		return fmt.Errorf("extracting layer failed: %w", err)
 	}
 
+	if err := extractBlob(verifier, layerType, extract); err != nil {
+		// The content read beyond the limit is not verified.
+		if lerr := limit.exceeded(); lerr != nil {
+			return lerr
+		}
+		// A tampered blob usually fails extraction before it is fully read,
+		// in which case the integrity error is the most relevant one.
+		var integrityErr *IntegrityError
+		if verr := verifier.drain(); errors.As(verr, &integrityErr) {
+			return integrityErr
+		}
+		return err
+	}
+
+	// The extraction may not consume the trailing bytes of the blob,
+	// read them to complete the verification.
+	if err := verifier.drain(); err != nil {
+		if lerr := limit.exceeded(); lerr != nil {
+			return lerr
+		}
+		return err
+	}
+	return nil
+}
+
+// extractBlob extracts the blob with the extract function, detecting
+// the layer type from the content if none is given.
+func extractBlob(blob io.Reader, layerType LayerType, extract blobExtractor) error {
+
 	actualLayerType := layerType
 	if actualLayerType == "" {
 		bufReader := bufio.NewReader(blob)
@@ -135,21 +306,30 @@ func extractLayer(layer gcrv1.Layer, path string, layerType LayerType) error {
 		blob = bufReader
 	}
 
-	return extractLayerType(path, blob, actualLayerType)
+	return extract(blob, actualLayerType)
 }
 
 // extractLayerType extracts the contents of a io.Reader to the given path.
 // If the LayerType is LayerTypeTarball, it will untar to a directory,
 // If the LayerType is LayerTypeStatic, it will copy to a file.
-func extractLayerType(path string, blob io.Reader, layerType LayerType) error {
+// The entries of a tarball are filtered with the selector, if any.
+func extractLayerType(path string, blob io.Reader, layerType LayerType, selector *extractPathSelector) error {
 	switch layerType {
 	case LayerTypeTarball:
-		return tar.Untar(blob, path, tar.WithMaxUntarSize(-1), tar.WithSkipSymlinks())
+		opts := []tar.Option{tar.WithMaxUntarSize(-1), tar.WithSkipSymlinks()}
+		if filter := selector.filter(); filter != nil {
+			opts = append(opts, tar.WithFilter(filter))
+		}
+		return tar.Untar(blob, path, opts...)
 	case LayerTypeStatic:
+		if selector != nil {
+			return errStaticExtractPaths
+		}
 		f, err := os.Create(path)
 		if err != nil {
 			return err
 		}
+		defer f.Close()
 
 		_, err = io.Copy(f, blob)
 		if err != nil {
@@ -161,6 +341,31 @@ func extractLayerType(path string, blob io.Reader, layerType LayerType) error {
 	}
 }
 
+// extractLayerTypeFS extracts the contents of a io.Reader to an in-memory file system.
+// If the LayerType is LayerTypeTarball, it will untar the content,
+// If the LayerType is LayerTypeStatic, it will copy the content to a single file.
+// The entries of a tarball are filtered with the selector, if any.
+// The content is capped at maxSize bytes, unless maxSize is equal or less than 0.
+func extractLayerTypeFS(blob io.Reader, layerType LayerType, selector *extractPathSelector, maxSize int) (fs.FS, error) {
+	switch layerType {
+	case LayerTypeTarball:
+		return untarToMemFS(blob, selector.filter(), maxSize)
+	case LayerTypeStatic:
+		if selector != nil {
+			return nil, errStaticExtractPaths
+		}
+		data, err := readAllLimited(blob, maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("error copying layer content: %w", err)
+		}
+		mfs := newMemFS()
+		mfs.addFile(StaticLayerFileName, data, defaultFileMode)
+		return mfs, nil
+	default:
+		return nil, fmt.Errorf("unsupported layer type: '%s'", layerType)
+	}
+}
+
 // isGzipBlob reads the first two bytes from a bufio.Reader and
 // checks that they are equal to the expected gzip file headers.
 func isGzipBlob(buf *bufio.Reader) (bool, error) {
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"io"
+)
+
+// ErrArtifactTooLarge is returned when the size of the artifact content
+// pulled from a registry exceeds the limit set with Client.WithMaxPullSize.
+type ErrArtifactTooLarge struct {
+	// Reference is the artifact reference that was pulled.
+	Reference string
+	// Limit is the maximum size in bytes of the pulled content.
+	Limit int64
+	// Declared is the size in bytes of the pulled layers,
+	// as declared by their descriptors in the manifest.
+	Declared int64
+	// Actual is the number of bytes read before the download was aborted,
+	// or zero if the artifact was rejected based on its declared size.
+	Actual int64
+}
+
+// Error implements error.
+func (e *ErrArtifactTooLarge) Error() string {
+	if e.Actual == 0 {
+		return fmt.Sprintf("artifact '%s' exceeds the maximum pull size of %d bytes: declared size is %d bytes",
+			e.Reference, e.Limit, e.Declared)
+	}
+	return fmt.Sprintf("artifact '%s' exceeds the maximum pull size of %d bytes: declared size is %d bytes, read %d bytes",
+		e.Reference, e.Limit, e.Declared, e.Actual)
+}
+
+// WithMaxPullSize sets the maximum size in bytes of the layers downloaded
+// by Pull, PullFS and PullChart. The sizes declared by the layer descriptors
+// are checked before downloading, and the limit is enforced while streaming
+// in case the registry serves more content than declared. A pull exceeding
+// the limit fails with an *ErrArtifactTooLarge.
+// A size of zero or less disables the limit, which is the default.
+func (c *Client) WithMaxPullSize(bytes int64) *Client {
+	c.maxPullSize = bytes
+	return c
+}
+
+// pullSizeLimit enforces the maximum pull size over all the layers
+// downloaded for an artifact. A nil *pullSizeLimit enforces no limit.
+type pullSizeLimit struct {
+	reference string
+	limit     int64
+	declared  int64
+	read      int64
+	err       error
+}
+
+// newPullSizeLimit returns the size limit for pulling layers of the
+// given declared size from the reference, or nil if the client
+// has no maximum pull size.
+func (c *Client) newPullSizeLimit(reference string, declared int64) *pullSizeLimit {
+	if c.maxPullSize <= 0 {
+		return nil
+	}
+	return &pullSizeLimit{
+		reference: reference,
+		limit:     c.maxPullSize,
+		declared:  declared,
+	}
+}
+
+// check returns an *ErrArtifactTooLarge if the declared size
+// exceeds the limit.
+func (l *pullSizeLimit) check() error {
+	if l == nil || l.declared <= l.limit {
+		return nil
+	}
+	return &ErrArtifactTooLarge{
+		Reference: l.reference,
+		Limit:     l.limit,
+		Declared:  l.declared,
+	}
+}
+
+// exceeded returns the *ErrArtifactTooLarge recorded while streaming, if any.
+func (l *pullSizeLimit) exceeded() error {
+	if l == nil {
+		return nil
+	}
+	return l.err
+}
+
+// reader returns a reader counting the bytes read from r against the limit.
+func (l *pullSizeLimit) reader(r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &limitedReader{reader: r, limit: l}
+}
+
+// limitedReader fails with an *ErrArtifactTooLarge once more bytes
+// than allowed by the limit have been read, and on any read after.
+type limitedReader struct {
+	reader io.Reader
+	limit  *pullSizeLimit
+}
+
+// Read implements io.Reader.
+func (r *limitedReader) Read(p []byte) (int, error) {
+	l := r.limit
+	if l.err != nil {
+		return 0, l.err
+	}
+	n, err := r.reader.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		l.err = &ErrArtifactTooLarge{
+			Reference: l.reference,
+			Limit:     l.limit,
+			Declared:  l.declared,
+			Actual:    l.read,
+		}
+		return n - int(l.read-l.limit), l.err
+	}
+	return n, err
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/gomega"
+)
+
+func TestClient_WithMaxPullSize(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	artifact := filepath.Join(t.TempDir(), "artifact.tgz")
+	g.Expect(build(artifact, "testdata/artifact", nil)).To(Succeed())
+	layer, err := tarball.LayerFromFile(artifact, tarball.WithMediaType(CanonicalContentMediaType))
+	g.Expect(err).ToNot(HaveOccurred())
+	size, err := layer.Size()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, CanonicalConfigMediaType)
+	img, err = mutate.Append(img, mutate.Addendum{Layer: layer})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	url := fmt.Sprintf("%s/test-max-pull-size-%s:latest", dockerReg, randStringRunes(5))
+	g.Expect(crane.Push(img, url, NewClient(DefaultOptions()).optionsWithContext(ctx)...)).To(Succeed())
+
+	tests := []struct {
+		name    string
+		limit   int64
+		wantErr bool
+	}{
+		{
+			name:  "unlimited by default",
+			limit: 0,
+		},
+		{
+			name:  "declared size at the limit",
+			limit: size,
+		},
+		{
+			name:    "declared size over the limit",
+			limit:   size - 1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := NewClient(DefaultOptions()).WithMaxPullSize(tt.limit)
+
+			_, pullErr := c.Pull(ctx, url, filepath.Join(t.TempDir(), "artifact"))
+			_, pullFSErr := c.PullFS(ctx, url)
+
+			for _, err := range []error{pullErr, pullFSErr} {
+				if !tt.wantErr {
+					g.Expect(err).ToNot(HaveOccurred())
+					continue
+				}
+				var tooLarge *ErrArtifactTooLarge
+				g.Expect(errors.As(err, &tooLarge)).To(BeTrue(), fmt.Sprint(err))
+				g.Expect(*tooLarge).To(Equal(ErrArtifactTooLarge{
+					Reference: url,
+					Limit:     tt.limit,
+					Declared:  size,
+				}))
+			}
+		})
+	}
+}
+
+func TestClient_PullChart_MaxPullSize(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	chart := testHelmChart(t, "podinfo", map[string]string{
+		"Chart.yaml": "apiVersion: v2\nname: podinfo\nversion: 6.5.0\n",
+	})
+	config := []byte(`{"apiVersion":"v2","name":"podinfo","version":"6.5.0"}`)
+	prov := []byte("-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA512\n\nname: podinfo\n")
+	declared := int64(len(chart) + len(prov))
+
+	url := fmt.Sprintf("%s/charts/podinfo-%s:6.5.0", dockerReg, randStringRunes(5))
+	pushHelmLayout(t, NewClient(DefaultOptions()), url, config, chart, prov)
+
+	_, err := NewClient(DefaultOptions()).WithMaxPullSize(declared).PullChart(ctx, url)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = NewClient(DefaultOptions()).WithMaxPullSize(declared-1).PullChart(ctx, url)
+	var tooLarge *ErrArtifactTooLarge
+	g.Expect(errors.As(err, &tooLarge)).To(BeTrue(), fmt.Sprint(err))
+	g.Expect(tooLarge.Declared).To(Equal(declared))
+	g.Expect(tooLarge.Actual).To(BeZero())
+	g.Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("exceeds the maximum pull size of %d bytes", declared-1)))
+}
+
+func Test_PullUnderReportedLayerSize(t *testing.T) {
+	content := make([]byte, 64*1024)
+	_, err := rand.Read(content)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	layer := static.NewLayer(content, CanonicalContentMediaType)
+	desc, err := partial.Descriptor(layer)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	// The descriptor declares a size below the limit,
+	// while the layer holds more content than allowed.
+	desc.Size = 512
+	const limit = 4096
+
+	tests := []struct {
+		name string
+		pull func(limit *pullSizeLimit) error
+	}{
+		{
+			name: "extract layer",
+			pull: func(limit *pullSizeLimit) error {
+				return extractLayer(layer, *desc, "test", 0, LayerTypeStatic, limit, func(blob io.Reader, _ LayerType) error {
+					_, err := io.Copy(io.Discard, blob)
+					return err
+				})
+			},
+		},
+		{
+			name: "extract layer with trailing content",
+			pull: func(limit *pullSizeLimit) error {
+				return extractLayer(layer, *desc, "test", 0, LayerTypeStatic, limit, func(blob io.Reader, _ LayerType) error {
+					_, err := io.CopyN(io.Discard, blob, int64(desc.Size))
+					return err
+				})
+			},
+		},
+		{
+			name: "read layer",
+			pull: func(limit *pullSizeLimit) error {
+				data, err := readLayer(layer, *desc, "test", 0, limit)
+				if err == nil && !bytes.Equal(data, content) {
+					return fmt.Errorf("unexpected content")
+				}
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := NewClient(DefaultOptions()).WithMaxPullSize(limit)
+			pullLimit := c.newPullSizeLimit("test", desc.Size)
+			g.Expect(pullLimit.check()).To(Succeed())
+
+			err := tt.pull(pullLimit)
+			var tooLarge *ErrArtifactTooLarge
+			g.Expect(errors.As(err, &tooLarge)).To(BeTrue(), fmt.Sprint(err))
+			g.Expect(tooLarge.Limit).To(Equal(int64(limit)))
+			g.Expect(tooLarge.Declared).To(Equal(desc.Size))
+			g.Expect(tooLarge.Actual).To(BeNumerically(">", limit))
+			g.Expect(err.Error()).To(ContainSubstring("declared size is 512 bytes, read"))
+
+			// Without a limit, the content is only verified against the digest.
+			g.Expect(tt.pull(NewClient(DefaultOptions()).newPullSizeLimit("test", desc.Size))).To(Succeed())
+		})
+	}
+}
@@ -18,11 +18,22 @@ package oci
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
@@ -81,3 +92,246 @@ func Test_PullAnyTarball(t *testing.T) {
 		g.Expect(extractTo + "/" + entry).To(Or(BeAnExistingFile(), BeADirectory()))
 	}
 }
+
+func Test_PullWithExpectedDigest(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	repo := "test-expected-digest" + randStringRunes(5)
+	url := fmt.Sprintf("%s/%s:latest", dockerReg, repo)
+
+	artifact := filepath.Join(t.TempDir(), "artifact.tgz")
+	g.Expect(build(artifact, "testdata/artifact", nil)).To(Succeed())
+	layer, err := tarball.LayerFromFile(artifact, tarball.WithMediaType(CanonicalContentMediaType))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, CanonicalConfigMediaType)
+	img, err = mutate.Append(img, mutate.Addendum{Layer: layer})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(crane.Push(img, url, c.optionsWithContext(ctx)...)).To(Succeed())
+
+	pinned, err := img.Digest()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Move the tag to a different artifact.
+	otherImg := mutate.Annotations(img, map[string]string{RevisionAnnotation: "other"}).(gcrv1.Image)
+	g.Expect(crane.Push(otherImg, url, c.optionsWithContext(ctx)...)).To(Succeed())
+
+	t.Run("pulls the pinned digest instead of the tag", func(t *testing.T) {
+		g := NewWithT(t)
+		extractTo := filepath.Join(t.TempDir(), "artifact")
+		m, err := c.Pull(ctx, url, extractTo, WithExpectedDigest(pinned.String()))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(m.Digest).To(Equal(fmt.Sprintf("%s/%s@%s", dockerReg, repo, pinned)))
+		g.Expect(m.Revision).To(BeEmpty())
+		g.Expect(filepath.Join(extractTo, "deployment.yaml")).To(BeAnExistingFile())
+	})
+
+	t.Run("fails for an invalid digest", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := c.Pull(ctx, url, t.TempDir(), WithExpectedDigest("sha256:foo"))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("invalid expected digest"))
+	})
+
+	t.Run("fails for a URL with a different digest", func(t *testing.T) {
+		g := NewWithT(t)
+		otherDigest, err := otherImg.Digest()
+		g.Expect(err).ToNot(HaveOccurred())
+		digestURL := fmt.Sprintf("%s/%s@%s", dockerReg, repo, otherDigest)
+		_, err = c.Pull(ctx, digestURL, t.TempDir(), WithExpectedDigest(pinned.String()))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("does not match expected digest"))
+	})
+}
+
+func Test_PullTamperedLayer(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	artifact := filepath.Join(t.TempDir(), "artifact.tgz")
+	g.Expect(build(artifact, "testdata/artifact", nil)).To(Succeed())
+	layer, err := tarball.LayerFromFile(artifact, tarball.WithMediaType(CanonicalContentMediaType))
+	g.Expect(err).ToNot(HaveOccurred())
+	layerDigest, err := layer.Digest()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, CanonicalConfigMediaType)
+	img, err = mutate.Append(img, mutate.Addendum{Layer: layer})
+	g.Expect(err).ToNot(HaveOccurred())
+	imgDigest, err := img.Digest()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Serve the layer blob with a flipped byte once tampering is enabled.
+	var tamper atomic.Bool
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !tamper.Load() || r.Method != http.MethodGet ||
+			!strings.HasSuffix(r.URL.Path, "/blobs/"+layerDigest.String()) {
+			reg.ServeHTTP(w, r)
+			return
+		}
+		rec := httptest.NewRecorder()
+		reg.ServeHTTP(rec, r)
+		body := rec.Body.Bytes()
+		body[len(body)/2] ^= 0xff
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	url := fmt.Sprintf("%s/test-tampered:latest", strings.TrimPrefix(srv.URL, "http://"))
+	g.Expect(crane.Push(img, url, c.optionsWithContext(ctx)...)).To(Succeed())
+	tamper.Store(true)
+
+	for _, tt := range []struct {
+		name      string
+		layerType LayerType
+	}{
+		{name: "tarball layer", layerType: LayerTypeTarball},
+		{name: "static layer", layerType: LayerTypeStatic},
+		{name: "detected layer type"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := []PullOption{WithExpectedDigest(imgDigest.String())}
+			if tt.layerType != "" {
+				opts = append(opts, WithPullLayerType(tt.layerType))
+			}
+
+			_, err := c.Pull(ctx, url, filepath.Join(t.TempDir(), "artifact"), opts...)
+			g.Expect(err).To(HaveOccurred())
+
+			var integrityErr *IntegrityError
+			g.Expect(errors.As(err, &integrityErr)).To(BeTrue(), err.Error())
+			g.Expect(integrityErr.LayerIndex).To(Equal(0))
+			g.Expect(integrityErr.Expected).To(Equal(layerDigest.String()))
+			g.Expect(integrityErr.Actual).ToNot(Equal(layerDigest.String()))
+		})
+	}
+}
+
+func Test_PullWithExtractPaths(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	url := fmt.Sprintf("%s/%s:latest", dockerReg, "test-extract-paths"+randStringRunes(5))
+	artifact := filepath.Join(t.TempDir(), "artifact.tgz")
+	g.Expect(build(artifact, "testdata/artifact", nil)).To(Succeed())
+	layer, err := tarball.LayerFromFile(artifact, tarball.WithMediaType(CanonicalContentMediaType))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, CanonicalConfigMediaType)
+	img, err = mutate.Append(img, mutate.Addendum{Layer: layer})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(crane.Push(img, url, c.optionsWithContext(ctx)...)).To(Succeed())
+
+	allEntries := []string{
+		"deploy",
+		"deploy/repo.yaml",
+		"deployment.yaml",
+		"ignore-dir",
+		"ignore-dir/deployment.yaml",
+		"ignore.txt",
+		"somedir",
+		"somedir/git",
+		"somedir/git/repo.yaml",
+		"somedir/repo.yaml",
+	}
+
+	tests := []struct {
+		name      string
+		globs     []string
+		want      []string
+		wantStats *ExtractStats
+		wantErr   string
+	}{
+		{
+			name:      "directory subtree",
+			globs:     []string{"./somedir/"},
+			want:      []string{"somedir", "somedir/git", "somedir/git/repo.yaml", "somedir/repo.yaml"},
+			wantStats: &ExtractStats{Extracted: 4, Skipped: 7},
+		},
+		{
+			name:      "nested directory",
+			globs:     []string{"somedir/git"},
+			want:      []string{"somedir", "somedir/git", "somedir/git/repo.yaml"},
+			wantStats: &ExtractStats{Extracted: 2, Skipped: 9},
+		},
+		{
+			name:      "file patterns in multiple directories",
+			globs:     []string{"*/repo.yaml", "*.txt"},
+			want:      []string{"deploy", "deploy/repo.yaml", "ignore.txt", "somedir", "somedir/repo.yaml"},
+			wantStats: &ExtractStats{Extracted: 3, Skipped: 8},
+		},
+		{
+			name:      "root",
+			globs:     []string{"."},
+			want:      allEntries,
+			wantStats: &ExtractStats{Extracted: 11, Skipped: 0},
+		},
+		{
+			name:    "no match",
+			globs:   []string{"deploy/prod"},
+			wantErr: "no entries of the artifact match the extract paths",
+		},
+		{
+			name:    "invalid pattern",
+			globs:   []string{"deploy/[a"},
+			wantErr: "invalid extract path 'deploy/[a'",
+		},
+		{
+			name:    "path outside of the artifact",
+			globs:   []string{"../deploy"},
+			wantErr: "must be relative to the root of the artifact",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			extractTo := filepath.Join(t.TempDir(), "artifact")
+			m, err := c.Pull(ctx, url, extractTo, WithExtractPaths(tt.globs))
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(m.Extraction).To(Equal(tt.wantStats))
+
+			for _, entry := range allEntries {
+				if slices.Contains(tt.want, entry) {
+					g.Expect(filepath.Join(extractTo, entry)).To(Or(BeAnExistingFile(), BeADirectory()))
+				} else {
+					g.Expect(filepath.Join(extractTo, entry)).ToNot(BeAnExistingFile())
+				}
+			}
+
+			fsys, err := c.PullFS(ctx, url, WithExtractPaths(tt.globs))
+			g.Expect(err).ToNot(HaveOccurred())
+			for _, entry := range allEntries {
+				_, err := fs.Stat(fsys, entry)
+				g.Expect(err == nil).To(Equal(slices.Contains(tt.want, entry)), entry)
+			}
+		})
+	}
+
+	t.Run("without extract paths", func(t *testing.T) {
+		g := NewWithT(t)
+		m, err := c.Pull(ctx, url, filepath.Join(t.TempDir(), "artifact"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(m.Extraction).To(BeNil())
+	})
+}
@@ -19,6 +19,7 @@ package oci
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"time"
@@ -28,9 +29,12 @@ import (
 	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/static"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/fluxcd/pkg/tar"
 )
 
 // LayerType is an enumeration of the supported layer types
@@ -92,24 +96,93 @@ func WithPushMetadata(meta Metadata) PushOption {
 	}
 }
 
+// PushResult is the result of pushing an artifact.
+type PushResult struct {
+	// Digest is the digest URL of the artifact.
+	Digest string
+	// AlreadyExists is true if the URL already referred to the artifact, in
+	// which case nothing was uploaded.
+	AlreadyExists bool
+	// UploadedBlobs are the digests of the config and layers uploaded to the
+	// repository.
+	UploadedBlobs []string
+	// ExistingBlobs are the digests of the config and layers which already
+	// existed in the repository, and were not uploaded.
+	ExistingBlobs []string
+}
+
 // Push creates an artifact from the given path, uploads the artifact
 // to the given OCI repository and returns the digest.
 func (c *Client) Push(ctx context.Context, url, sourcePath string, opts ...PushOption) (string, error) {
+	result, err := c.PushArtifact(ctx, url, sourcePath, opts...)
+	if err != nil {
+		return "", err
+	}
+	return result.Digest, nil
+}
+
+// PushArtifact creates an artifact from the given path, uploads the
+// artifact to the given OCI repository and returns the result.
+//
+// The digest of the artifact is computed before uploading it. If the URL
+// already refers to it, e.g. when pushing the same content with the same
+// created timestamp, the upload is skipped and the result is flagged with
+// AlreadyExists. Otherwise, only the config and layers missing from the
+// repository are uploaded.
+func (c *Client) PushArtifact(ctx context.Context, url, sourcePath string, opts ...PushOption) (*PushResult, error) {
+	o := newPushOptions(opts...)
+	return c.push(ctx, url, o, func() (gcrv1.Layer, error) {
+		return createLayer(sourcePath, o.layerType, o.layerOpts)
+	})
+}
+
+// PushFS creates an artifact from the given file system, uploads the artifact
+// to the given OCI repository and returns the digest. For the LayerTypeTarball
+// layer type, the file system is archived from its root. For the LayerTypeStatic
+// layer type, the file system must contain a single regular file at its root.
+//
+// The file modes reported by the file system are preserved, and identical
+// content produces an identical digest regardless of the file system
+// implementation.
+func (c *Client) PushFS(ctx context.Context, url string, fsys fs.FS, opts ...PushOption) (string, error) {
+	result, err := c.PushArtifactFS(ctx, url, fsys, opts...)
+	if err != nil {
+		return "", err
+	}
+	return result.Digest, nil
+}
+
+// PushArtifactFS creates an artifact from the given file system as PushFS
+// does, uploads it as PushArtifact does and returns the result.
+func (c *Client) PushArtifactFS(ctx context.Context, url string, fsys fs.FS, opts ...PushOption) (*PushResult, error) {
+	o := newPushOptions(opts...)
+	return c.push(ctx, url, o, func() (gcrv1.Layer, error) {
+		return createLayerFS(fsys, ".", o.layerType, o.layerOpts)
+	})
+}
+
+// newPushOptions returns the PushOptions with the given options applied.
+func newPushOptions(opts ...PushOption) *PushOptions {
 	o := &PushOptions{
 		layerType: LayerTypeTarball,
 	}
-
 	for _, opt := range opts {
 		opt(o)
 	}
+	return o
+}
+
+// push creates an artifact from the layer returned by newLayer, uploads the
+// artifact to the given OCI repository and returns the result.
+func (c *Client) push(ctx context.Context, url string, o *PushOptions, newLayer func() (gcrv1.Layer, error)) (*PushResult, error) {
 	ref, err := name.ParseReference(url)
 	if err != nil {
-		return "", fmt.Errorf("invalid URL: %w", err)
+		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	layer, err := createLayer(sourcePath, o.layerType, o.layerOpts)
+	layer, err := newLayer()
 	if err != nil {
-		return "", fmt.Errorf("error creating layer: %w", err)
+		return nil, fmt.Errorf("error creating layer: %w", err)
 	}
 
 	if o.meta.Created == "" {
@@ -125,58 +198,154 @@ func (c *Client) Push(ctx context.Context, url, sourcePath string, opts ...PushO
 	}
 	created, err := time.Parse(time.RFC3339, createdValue)
 	if err != nil {
-		return "", fmt.Errorf("invalid created timestamp %q: %w", createdValue, err)
+		return nil, fmt.Errorf("invalid created timestamp %q: %w", createdValue, err)
 	}
 
 	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
 	img = mutate.ConfigMediaType(img, CanonicalConfigMediaType)
 	configFile, err := img.ConfigFile()
 	if err != nil {
-		return "", fmt.Errorf("reading artifact config failed: %w", err)
+		return nil, fmt.Errorf("reading artifact config failed: %w", err)
 	}
 	configFile.Created = gcrv1.Time{Time: created}
 	img, err = mutate.ConfigFile(img, configFile)
 	if err != nil {
-		return "", fmt.Errorf("setting artifact config failed: %w", err)
+		return nil, fmt.Errorf("setting artifact config failed: %w", err)
 	}
 	img = mutate.Annotations(img, annotations).(gcrv1.Image)
 
 	img, err = mutate.Append(img, mutate.Addendum{Layer: layer})
 	if err != nil {
-		return "", fmt.Errorf("appeding content to artifact failed: %w", err)
+		return nil, fmt.Errorf("appeding content to artifact failed: %w", err)
 	}
 
-	if err := crane.Push(img, url, c.optionsWithContext(ctx)...); err != nil {
-		return "", fmt.Errorf("pushing artifact failed: %w", err)
-	}
+	return c.pushImage(ctx, ref, img)
+}
 
+// pushImage uploads the image to the given reference, unless the reference
+// already refers to it, and returns the result. The existence of the config
+// and layers is checked beforehand for reporting which ones are uploaded,
+// the registry protocol skipping the upload of the existing ones.
+func (c *Client) pushImage(ctx context.Context, ref name.Reference, img gcrv1.Image) (*PushResult, error) {
 	digest, err := img.Digest()
 	if err != nil {
-		return "", fmt.Errorf("parsing artifact digest failed: %w", err)
+		return nil, fmt.Errorf("parsing artifact digest failed: %w", err)
+	}
+	result := &PushResult{Digest: ref.Context().Digest(digest.String()).String()}
+	options := c.optionsWithContext(ctx)
+	remoteOpts := crane.GetOptions(options...).Remote
+
+	// A failure to check the existence, e.g. the reference not existing yet,
+	// is handled by uploading the image.
+	if desc, err := remote.Head(ref, remoteOpts...); err == nil && desc.Digest == digest {
+		result.AlreadyExists = true
+		return result, nil
+	}
+
+	blobs, err := imageBlobs(img)
+	if err != nil {
+		return nil, fmt.Errorf("parsing artifact manifest failed: %w", err)
+	}
+	for _, blob := range blobs {
+		if blobExists(ref.Context().Digest(blob.String()), remoteOpts) {
+			result.ExistingBlobs = append(result.ExistingBlobs, blob.String())
+		} else {
+			result.UploadedBlobs = append(result.UploadedBlobs, blob.String())
+		}
 	}
 
-	return ref.Context().Digest(digest.String()).String(), err
+	if err := crane.Push(img, ref.String(), options...); err != nil {
+		return nil, fmt.Errorf("pushing artifact failed: %w", err)
+	}
+	return result, nil
+}
+
+// imageBlobs returns the digests of the config and layers of the image.
+func imageBlobs(img gcrv1.Image) ([]gcrv1.Hash, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	blobs := []gcrv1.Hash{manifest.Config.Digest}
+	for _, layer := range manifest.Layers {
+		blobs = append(blobs, layer.Digest)
+	}
+	return blobs, nil
 }
 
-// createLayer creates a layer depending on the layerType.
+// blobExists returns true if the blob exists in the repository.
+func blobExists(ref name.Digest, opts []remote.Option) bool {
+	layer, err := remote.Layer(ref, opts...)
+	if err != nil {
+		return false
+	}
+	checker, ok := layer.(interface{ Exists() (bool, error) })
+	if !ok {
+		return false
+	}
+	exists, err := checker.Exists()
+	return err == nil && exists
+}
+
+// createLayer creates a layer from the given path depending on the layerType.
 func createLayer(path string, layerType LayerType, opts layerOptions) (gcrv1.Layer, error) {
+	switch layerType {
+	case LayerTypeTarball:
+		fsys, _, cleanup, err := sourceFS(path)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		return createLayerFS(fsys, ".", layerType, opts)
+	case LayerTypeStatic:
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file for static layer: %w", err)
+		}
+		return createLayerFS(os.DirFS(filepath.Dir(absPath)), filepath.Base(absPath), layerType, opts)
+	default:
+		return nil, fmt.Errorf("unsupported layer type: '%s'", layerType)
+	}
+}
+
+// createLayerFS creates a layer from the named file or directory of fsys
+// depending on the layerType. For LayerTypeStatic, if name is the root of
+// fsys, the single regular file at the root is used.
+func createLayerFS(fsys fs.FS, name string, layerType LayerType, opts layerOptions) (gcrv1.Layer, error) {
 	switch layerType {
 	case LayerTypeTarball:
 		var ociMediaType = CanonicalContentMediaType
-		var tmpDir string
+		sub, err := fs.Sub(fsys, name)
+		if err != nil {
+			return nil, err
+		}
 		tmpDir, err := os.MkdirTemp("", "oci")
 		if err != nil {
 			return nil, err
 		}
 		defer os.RemoveAll(tmpDir)
 		tmpFile := filepath.Join(tmpDir, "artifact.tgz")
-		if err := build(tmpFile, path, opts.ignorePaths); err != nil {
+		f, err := os.Create(tmpFile)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tar.Tar(".", f, tar.WithFS(sub), tar.WithFilter(ignoreFilter(opts.ignorePaths))); err != nil {
+			f.Close()
+			return nil, err
+		}
+		if err := f.Close(); err != nil {
 			return nil, err
 		}
 		return tarball.LayerFromFile(tmpFile, tarball.WithMediaType(ociMediaType), tarball.WithCompressedCaching)
 	case LayerTypeStatic:
 		var ociMediaType = getLayerMediaType(opts.mediaTypeExt)
-		content, err := os.ReadFile(path)
+		if name == "." {
+			var err error
+			if name, err = singleRegularFile(fsys); err != nil {
+				return nil, fmt.Errorf("error reading file for static layer: %w", err)
+			}
+		}
+		content, err := fs.ReadFile(fsys, name)
 		if err != nil {
 			return nil, fmt.Errorf("error reading file for static layer: %w", err)
 		}
@@ -186,6 +355,29 @@ func createLayer(path string, layerType LayerType, opts layerOptions) (gcrv1.Lay
 	}
 }
 
+// singleRegularFile returns the name of the single regular file at the root
+// of fsys, or an error if there is none or more than one.
+func singleRegularFile(fsys fs.FS) (string, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return "", err
+	}
+	var name string
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if name != "" {
+			return "", fmt.Errorf("file system contains more than one file")
+		}
+		name = e.Name()
+	}
+	if name == "" {
+		return "", fmt.Errorf("file system does not contain any file")
+	}
+	return name, nil
+}
+
 func getLayerMediaType(extension string) types.MediaType {
 	if extension == "" {
 		return CanonicalMediaTypePrefix
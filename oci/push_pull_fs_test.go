@@ -0,0 +1,277 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	archivetar "archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/tar"
+)
+
+// testMapFS returns an in-memory file system and writes the same content,
+// with the same file modes, to a temporary directory.
+func testMapFS(t *testing.T) (fstest.MapFS, string) {
+	t.Helper()
+
+	mfs := fstest.MapFS{
+		".":                         {Mode: fs.ModeDir | 0o755},
+		"deploy":                    {Mode: fs.ModeDir | 0o755},
+		"deploy/deployment.yaml":    {Data: []byte("kind: Deployment\n"), Mode: 0o644},
+		"deploy/scripts":            {Mode: fs.ModeDir | 0o750},
+		"deploy/scripts/hook.sh":    {Data: []byte("#!/bin/sh\necho ok\n"), Mode: 0o755},
+		"kustomization.yaml":        {Data: []byte("resources:\n- deploy/deployment.yaml\n"), Mode: 0o600},
+		"ignored/secret.enc.yaml":   {Data: []byte("secret"), Mode: 0o644},
+		"ignored":                   {Mode: fs.ModeDir | 0o755},
+		"README.md":                 {Data: []byte("# test\n"), Mode: 0o644},
+		"deploy/scripts/.gitignore": {Data: []byte("*.log\n"), Mode: 0o644},
+	}
+
+	dir := t.TempDir()
+	for p, f := range mfs {
+		abs := filepath.Join(dir, filepath.FromSlash(p))
+		if f.Mode.IsDir() {
+			if err := os.MkdirAll(abs, 0o700); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(abs), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(abs, f.Data, f.Mode.Perm()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Apply the modes explicitly, as they are subject to the umask.
+	for p, f := range mfs {
+		if err := os.Chmod(filepath.Join(dir, filepath.FromSlash(p)), f.Mode.Perm()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return mfs, dir
+}
+
+func Test_PushFS_DigestMatchesPush(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	repo := "test-push-fs" + randStringRunes(5)
+
+	mfs, dir := testMapFS(t)
+	opts := []PushOption{
+		WithPushMetadata(Metadata{
+			Source:   "github.com/fluxcd/flux2",
+			Revision: "rev",
+			Created:  time.Now().UTC().Format(time.RFC3339),
+		}),
+		WithPushIgnorePaths("ignored/"),
+	}
+
+	fsDigest, err := c.PushFS(ctx, fmt.Sprintf("%s/%s:fs", dockerReg, repo), mfs, opts...)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	dirDigest, err := c.Push(ctx, fmt.Sprintf("%s/%s:dir", dockerReg, repo), dir, opts...)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(fsDigest).To(Equal(dirDigest))
+
+	// Pushing the same content again must produce the same digest.
+	again, err := c.PushFS(ctx, fmt.Sprintf("%s/%s:again", dockerReg, repo), mfs, opts...)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(again).To(Equal(fsDigest))
+}
+
+func Test_PushFS_PullFS(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	repo := "test-pull-fs" + randStringRunes(5)
+	url := fmt.Sprintf("%s/%s:v1", dockerReg, repo)
+
+	mfs, _ := testMapFS(t)
+	digest, err := c.PushFS(ctx, url, mfs, WithPushIgnorePaths("ignored/"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	fsys, err := c.PullFS(ctx, url)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fstest.TestFS(fsys,
+		"README.md",
+		"kustomization.yaml",
+		"deploy/deployment.yaml",
+		"deploy/scripts/hook.sh",
+		"deploy/scripts/.gitignore",
+	)).To(Succeed())
+
+	_, err = fs.Stat(fsys, "ignored/secret.enc.yaml")
+	g.Expect(err).To(MatchError(fs.ErrNotExist))
+
+	for _, p := range []string{"kustomization.yaml", "deploy/scripts/hook.sh", "deploy/scripts"} {
+		fi, err := fs.Stat(fsys, p)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(fi.Mode()).To(Equal(mfs[p].Mode), p)
+	}
+
+	data, err := fs.ReadFile(fsys, "deploy/deployment.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(Equal(mfs["deploy/deployment.yaml"].Data))
+
+	// A pinned pull returns the same content.
+	ref, err := name.ParseReference(digest)
+	g.Expect(err).ToNot(HaveOccurred())
+	pinned, err := c.PullFS(ctx, url, WithExpectedDigest(ref.Identifier()))
+	g.Expect(err).ToNot(HaveOccurred())
+	data, err = fs.ReadFile(pinned, "README.md")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(Equal(mfs["README.md"].Data))
+}
+
+func Test_PushFS_PullFS_MaxSize(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	repo := "test-max-size-fs" + randStringRunes(5)
+	url := fmt.Sprintf("%s/%s:v1", dockerReg, repo)
+
+	mfs, _ := testMapFS(t)
+	_, err := c.PushFS(ctx, url, mfs, WithPushIgnorePaths("ignored/"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The limit applies to the files of the layer together, each of them
+	// is smaller than the limit.
+	_, err = c.PullFS(ctx, url, WithPullFSMaxSize(50))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("bigger than max archive size of 50 bytes"))
+
+	for _, max := range []int{200, tar.UnlimitedUntarSize} {
+		fsys, err := c.PullFS(ctx, url, WithPullFSMaxSize(max))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = fs.Stat(fsys, "kustomization.yaml")
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	staticURL := fmt.Sprintf("%s/%s:static", dockerReg, repo)
+	_, err = c.PushFS(ctx, staticURL, fstest.MapFS{
+		"config.yaml": {Data: []byte("apiVersion: v1\nkind: ConfigMap\n")},
+	}, WithPushLayerType(LayerTypeStatic))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = c.PullFS(ctx, staticURL, WithPullFSMaxSize(10))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("bigger than max size of 10 bytes"))
+}
+
+func Test_PushFS_PullFS_Static(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	repo := "test-static-fs" + randStringRunes(5)
+	url := fmt.Sprintf("%s/%s:v1", dockerReg, repo)
+
+	content := []byte("apiVersion: v1\nkind: ConfigMap\n")
+	_, err := c.PushFS(ctx, url, fstest.MapFS{
+		"config.yaml": {Data: content},
+	}, WithPushLayerType(LayerTypeStatic))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	fsys, err := c.PullFS(ctx, url)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fstest.TestFS(fsys, StaticLayerFileName)).To(Succeed())
+
+	data, err := fs.ReadFile(fsys, StaticLayerFileName)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(Equal(content))
+
+	// A static layer requires a single file.
+	_, err = c.PushFS(ctx, url, fstest.MapFS{
+		"a.yaml": {Data: content},
+		"b.yaml": {Data: content},
+	}, WithPushLayerType(LayerTypeStatic))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("more than one file"))
+}
+
+func Test_ignoreFilter(t *testing.T) {
+	g := NewWithT(t)
+
+	// The tarball of a directory is the same on disk and through a file system.
+	var expected bytes.Buffer
+	_, err := tar.Tar("testdata/artifact", &expected)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var got bytes.Buffer
+	_, err = tar.Tar(".", &got, tar.WithFS(os.DirFS("testdata/artifact")), tar.WithFilter(ignoreFilter(nil)))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Bytes()).To(Equal(expected.Bytes()))
+
+	// The ignore patterns match the paths relative to the root and file
+	// systems without permission bits get default modes.
+	var filtered bytes.Buffer
+	_, err = tar.Tar(".", &filtered, tar.WithFS(fstest.MapFS{
+		"dir":          {Mode: fs.ModeDir},
+		"dir/file.txt": {Data: []byte("test")},
+		"dir/skip.log": {Data: []byte("test")},
+		"ignored/a":    {Data: []byte("test")},
+	}), tar.WithFilter(ignoreFilter([]string{"*.log", "/ignored/"})))
+	g.Expect(err).ToNot(HaveOccurred())
+	mfs, err := untarToMemFS(&filtered, nil, tar.DefaultMaxUntarSize)
+	g.Expect(err).ToNot(HaveOccurred())
+	fi, err := fs.Stat(mfs, "dir/file.txt")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fi.Mode()).To(Equal(defaultFileMode))
+	fi, err = fs.Stat(mfs, "dir")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fi.Mode()).To(Equal(fs.ModeDir | defaultDirMode))
+	_, err = fs.Stat(mfs, "dir/skip.log")
+	g.Expect(err).To(MatchError(fs.ErrNotExist))
+	_, err = fs.Stat(mfs, "ignored")
+	g.Expect(err).To(MatchError(fs.ErrNotExist))
+}
+
+func Test_untarToMemFS_InvalidPath(t *testing.T) {
+	g := NewWithT(t)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := archivetar.NewWriter(gw)
+	g.Expect(tw.WriteHeader(&archivetar.Header{
+		Name:     "../escape.txt",
+		Mode:     0o644,
+		Size:     4,
+		Typeflag: archivetar.TypeReg,
+	})).To(Succeed())
+	_, err := tw.Write([]byte("test"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tw.Close()).To(Succeed())
+	g.Expect(gw.Close()).To(Succeed())
+
+	_, err = untarToMemFS(&buf, nil, tar.DefaultMaxUntarSize)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid name"))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	. "github.com/onsi/gomega"
+)
+
+// writeCountingTransport counts the requests writing to the registry.
+type writeCountingTransport struct {
+	writes atomic.Int32
+}
+
+func (t *writeCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPut, http.MethodPost, http.MethodPatch:
+		t.writes.Add(1)
+	}
+	return remote.DefaultTransport.RoundTrip(req)
+}
+
+func Test_PushArtifact_existing(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	transport := &writeCountingTransport{}
+	c := NewClient([]crane.Option{crane.WithTransport(transport)})
+	repo := fmt.Sprintf("%s/test-push-existing-%s", dockerReg, randStringRunes(5))
+	meta := Metadata{
+		Source:   "github.com/fluxcd/flux2",
+		Revision: "rev",
+		Created:  "2026-01-01T00:00:00Z",
+	}
+
+	result, err := c.PushArtifact(ctx, repo+":v1", "testdata/artifact", WithPushMetadata(meta))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.AlreadyExists).To(BeFalse())
+	g.Expect(result.UploadedBlobs).To(HaveLen(2))
+	g.Expect(result.ExistingBlobs).To(BeEmpty())
+	g.Expect(transport.writes.Load()).ToNot(BeZero())
+	digest := result.Digest
+
+	t.Run("re-push skips the upload", func(t *testing.T) {
+		g := NewWithT(t)
+		transport.writes.Store(0)
+
+		result, err := c.PushArtifact(ctx, repo+":v1", "testdata/artifact", WithPushMetadata(meta))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(&PushResult{Digest: digest, AlreadyExists: true}))
+		g.Expect(transport.writes.Load()).To(BeZero())
+
+		// Push returns the digest of the existing artifact.
+		url, err := c.Push(ctx, repo+":v1", "testdata/artifact", WithPushMetadata(meta))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(url).To(Equal(digest))
+		g.Expect(transport.writes.Load()).To(BeZero())
+	})
+
+	t.Run("push to a new tag uploads the manifest only", func(t *testing.T) {
+		g := NewWithT(t)
+		transport.writes.Store(0)
+
+		result, err := c.PushArtifact(ctx, repo+":v2", "testdata/artifact", WithPushMetadata(meta))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Digest).To(Equal(digest))
+		g.Expect(result.AlreadyExists).To(BeFalse())
+		g.Expect(result.UploadedBlobs).To(BeEmpty())
+		g.Expect(result.ExistingBlobs).To(HaveLen(2))
+		g.Expect(transport.writes.Load()).To(Equal(int32(1)))
+	})
+
+	t.Run("push with new metadata uploads the config only", func(t *testing.T) {
+		g := NewWithT(t)
+		transport.writes.Store(0)
+
+		newMeta := meta
+		newMeta.Created = "2026-01-02T00:00:00Z"
+		result, err := c.PushArtifact(ctx, repo+":v1", "testdata/artifact", WithPushMetadata(newMeta))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Digest).ToNot(Equal(digest))
+		g.Expect(result.AlreadyExists).To(BeFalse())
+		g.Expect(result.UploadedBlobs).To(HaveLen(1))
+		g.Expect(result.ExistingBlobs).To(HaveLen(1))
+
+		// The upload session, content and commit of the config, and the manifest.
+		g.Expect(transport.writes.Load()).To(Equal(int32(4)))
+	})
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitLimitHeader is the header of the number of requests allowed
+	// in the rate limit window, e.g. '100;w=21600'.
+	rateLimitLimitHeader = "RateLimit-Limit"
+	// rateLimitRemainingHeader is the header of the number of requests
+	// remaining in the rate limit window, e.g. '76;w=21600'.
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	// rateLimitSourceHeader is the header of the identity the rate limit is
+	// accounted to by Docker Hub, i.e. the client IP or the user ID.
+	rateLimitSourceHeader = "Docker-RateLimit-Source"
+)
+
+// RateLimit is the rate limit of a registry, as reported by the RateLimit
+// headers of its responses, e.g. for the pulls from Docker Hub.
+type RateLimit struct {
+	// Registry is the host of the registry.
+	Registry string `json:"registry"`
+	// Repository is the repository of the request reporting the rate limit.
+	Repository string `json:"repository,omitempty"`
+	// Limit is the number of requests allowed in the window, zero if not
+	// reported.
+	Limit int `json:"limit,omitempty"`
+	// Remaining is the number of requests remaining in the window.
+	Remaining int `json:"remaining"`
+	// Window is the duration of the window, zero if not reported.
+	Window time.Duration `json:"window,omitempty"`
+	// Source is the identity the rate limit is accounted to, if reported.
+	Source string `json:"source,omitempty"`
+}
+
+// WithRateLimitHook sets a function called with the rate limit reported by
+// the responses of the registries to the remote operations of the client,
+// e.g. to export the remaining requests of Docker Hub as a metric. The hook
+// is called concurrently by the operations, and must not block.
+func (c *Client) WithRateLimitHook(hook func(RateLimit)) *Client {
+	c.rateLimitHook = hook
+	return c
+}
+
+// parseRateLimit returns the rate limit reported by the headers of the
+// response to the request, or nil if it reports no remaining requests.
+func parseRateLimit(req *http.Request, resp *http.Response) *RateLimit {
+	remaining, window, ok := parseRateLimitHeader(resp.Header.Get(rateLimitRemainingHeader))
+	if !ok {
+		return nil
+	}
+	rl := &RateLimit{
+		Registry:  req.URL.Host,
+		Remaining: remaining,
+		Window:    window,
+		Source:    resp.Header.Get(rateLimitSourceHeader),
+	}
+	if limit, limitWindow, ok := parseRateLimitHeader(resp.Header.Get(rateLimitLimitHeader)); ok {
+		rl.Limit = limit
+		if rl.Window == 0 {
+			rl.Window = limitWindow
+		}
+	}
+	// The repository is the path of the API request between '/v2/' and the
+	// kind of resource, e.g. '/v2/library/nginx/manifests/latest'.
+	if path, ok := strings.CutPrefix(req.URL.Path, "/v2/"); ok {
+		for _, kind := range []string{"/manifests/", "/blobs/", "/tags/"} {
+			if i := strings.LastIndex(path, kind); i > 0 {
+				rl.Repository = path[:i]
+				break
+			}
+		}
+	}
+	return rl
+}
+
+// parseRateLimitHeader parses a value of the RateLimit headers, i.e. a
+// number optionally followed by the window in seconds, e.g. '100;w=21600'.
+func parseRateLimitHeader(value string) (int, time.Duration, bool) {
+	if value == "" {
+		return 0, 0, false
+	}
+	parts := strings.Split(value, ";")
+	n, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, false
+	}
+	var window time.Duration
+	for _, param := range parts[1:] {
+		if w, ok := strings.CutPrefix(strings.TrimSpace(param), "w="); ok {
+			if seconds, err := strconv.Atoi(w); err == nil {
+				window = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return n, window, true
+}
+
+// rateLimitRecorder holds the last rate limit reported during an operation.
+type rateLimitRecorder struct {
+	mu   sync.Mutex
+	last *RateLimit
+}
+
+type rateLimitRecorderKey struct{}
+
+// withRateLimitRecorder returns a copy of the context holding a recorder of
+// the rate limits reported to the requests made with it.
+func withRateLimitRecorder(ctx context.Context) (context.Context, *rateLimitRecorder) {
+	r := &rateLimitRecorder{}
+	return context.WithValue(ctx, rateLimitRecorderKey{}, r), r
+}
+
+// get returns a copy of the last reported rate limit, or nil.
+func (r *rateLimitRecorder) get() *RateLimit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last == nil {
+		return nil
+	}
+	rl := *r.last
+	return &rl
+}
+
+// rateLimitTransport reports the rate limits of the responses to the hook
+// of the client and to the recorder of the request context, if any.
+type rateLimitTransport struct {
+	hook func(RateLimit)
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	rl := parseRateLimit(req, resp)
+	if rl == nil {
+		return resp, nil
+	}
+	if r, ok := req.Context().Value(rateLimitRecorderKey{}).(*rateLimitRecorder); ok {
+		r.mu.Lock()
+		r.last = rl
+		r.mu.Unlock()
+	}
+	if t.hook != nil {
+		t.hook(*rl)
+	}
+	return resp, nil
+}
@@ -44,6 +44,9 @@ func WithRetryTransport(ctx context.Context,
 	insecure bool) (crane.Option, error) {
 	var retryTransport http.RoundTripper
 	retryTransport = remote.DefaultTransport.(*http.Transport).Clone()
+	// Set the user agent and extra headers of the Client in the innermost transport.
+	retryTransport = &headerTransport{next: retryTransport}
+	retryTransport = &contentTransport{next: retryTransport}
 	if logs.Enabled(logs.Debug) {
 		retryTransport = transport.NewLogger(retryTransport)
 	}
@@ -0,0 +1,488 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/secure-systems-lab/go-securesystemslib/encrypted"
+)
+
+const (
+	// CosignSignatureMediaType is the media type of the cosign simple
+	// signing payload layers.
+	CosignSignatureMediaType types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// CosignSignatureAnnotation is the layer annotation holding the
+	// base64 encoded signature of the payload.
+	CosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// cosignSignatureType is the type of the cosign simple signing payload.
+	cosignSignatureType = "cosign container image signature"
+)
+
+// The KMS key reference schemes supported by cosign.
+const (
+	KMSSchemeAWS            = "awskms://"
+	KMSSchemeAzure          = "azurekms://"
+	KMSSchemeGCP            = "gcpkms://"
+	KMSSchemeHashiCorpVault = "hashivault://"
+)
+
+var kmsSchemes = []string{KMSSchemeAWS, KMSSchemeAzure, KMSSchemeGCP, KMSSchemeHashiCorpVault}
+
+// The PEM block types of the cosign private keys.
+const (
+	sigstorePrivateKeyPEMType = "ENCRYPTED SIGSTORE PRIVATE KEY"
+	cosignPrivateKeyPEMType   = "ENCRYPTED COSIGN PRIVATE KEY"
+)
+
+// KMSSigner signs digests with a key held by a key management service.
+type KMSSigner interface {
+	// PublicKey returns the public key of the KMS key.
+	PublicKey(ctx context.Context) (crypto.PublicKey, error)
+	// SignDigest signs the digest computed with the given hash function.
+	SignDigest(ctx context.Context, digest []byte, hash crypto.Hash) ([]byte, error)
+}
+
+// KMSProvider returns the KMSSigner for a KMS key reference, e.g.
+// awskms:///arn:aws:kms:us-east-1:123456789012:key/1234. Implementations
+// are expected to obtain the credentials for the KMS from the provider
+// of the github.com/fluxcd/pkg/auth module matching the scheme.
+type KMSProvider interface {
+	NewKMSSigner(ctx context.Context, keyRef string) (KMSSigner, error)
+}
+
+// Signer signs OCI artifacts in the cosign simple signing format.
+type Signer struct {
+	sign func(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// Verifier verifies the cosign signatures of OCI artifacts.
+type Verifier struct {
+	publicKey crypto.PublicKey
+}
+
+// NewSignerFromKey returns a Signer for the given cosign private key in PEM
+// format, as generated by `cosign generate-key-pair`, encrypted with the
+// given password.
+func NewSignerFromKey(key, password []byte) (*Signer, error) {
+	priv, err := parseCosignPrivateKey(key, password)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{
+		sign: func(_ context.Context, payload []byte) ([]byte, error) {
+			if _, ok := priv.(ed25519.PrivateKey); ok {
+				return priv.Sign(rand.Reader, payload, crypto.Hash(0))
+			}
+			digest := sha256.Sum256(payload)
+			return priv.Sign(rand.Reader, digest[:], crypto.SHA256)
+		},
+	}, nil
+}
+
+// NewSignerFromKMS returns a Signer for the given KMS key reference, e.g.
+// awskms:///arn:aws:kms:us-east-1:123456789012:key/1234, using the provider
+// to access the KMS.
+func NewSignerFromKMS(ctx context.Context, keyRef string, provider KMSProvider) (*Signer, error) {
+	kms, err := newKMSSigner(ctx, keyRef, provider)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{
+		sign: func(ctx context.Context, payload []byte) ([]byte, error) {
+			digest := sha256.Sum256(payload)
+			return kms.SignDigest(ctx, digest[:], crypto.SHA256)
+		},
+	}, nil
+}
+
+// NewVerifierFromKey returns a Verifier for the given public key in PEM format.
+func NewVerifierFromKey(key []byte) (*Verifier, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, errors.New("failed to decode public key: no PEM data found")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	if err := checkKeyType(pub); err != nil {
+		return nil, err
+	}
+	return &Verifier{publicKey: pub}, nil
+}
+
+// NewVerifierFromKMS returns a Verifier for the public key of the given KMS
+// key reference, using the provider to access the KMS.
+func NewVerifierFromKMS(ctx context.Context, keyRef string, provider KMSProvider) (*Verifier, error) {
+	kms, err := newKMSSigner(ctx, keyRef, provider)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := kms.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key of KMS key '%s': %w", keyRef, err)
+	}
+	if err := checkKeyType(pub); err != nil {
+		return nil, err
+	}
+	return &Verifier{publicKey: pub}, nil
+}
+
+// IsKMSKeyRef returns true if the given key reference uses
+// one of the KMS schemes supported by cosign.
+func IsKMSKeyRef(keyRef string) bool {
+	for _, scheme := range kmsSchemes {
+		if strings.HasPrefix(keyRef, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign signs the artifact at the given URL and uploads the signature to the
+// same OCI repository, using the cosign tag naming sha256-<digest>.sig.
+// Signatures already present for the artifact are preserved. It returns the
+// reference of the signature image.
+func (c *Client) Sign(ctx context.Context, url string, signer *Signer) (string, error) {
+	ref, digest, err := c.resolveDigest(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(newCosignPayload(ref.Context(), digest))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal signature payload: %w", err)
+	}
+	sig, err := signer.sign(ctx, payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign artifact '%s': %w", url, err)
+	}
+	encodedSig := base64.StdEncoding.EncodeToString(sig)
+
+	sigTag := cosignSignatureTag(ref.Context(), digest)
+	img, err := c.pullSignatures(ctx, sigTag)
+	if err != nil {
+		return "", err
+	}
+	if img == nil {
+		img = mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+		img = mutate.ConfigMediaType(img, types.OCIConfigJSON)
+	}
+
+	img, err = mutate.Append(img, mutate.Addendum{
+		Layer:       static.NewLayer(payload, CosignSignatureMediaType),
+		Annotations: map[string]string{CosignSignatureAnnotation: encodedSig},
+	})
+	if err != nil {
+		return "", fmt.Errorf("appending signature failed: %w", err)
+	}
+
+	if err := crane.Push(img, sigTag.String(), c.optionsWithContext(ctx)...); err != nil {
+		return "", fmt.Errorf("pushing signature failed: %w", err)
+	}
+
+	return sigTag.String(), nil
+}
+
+// Verify verifies that the artifact at the given URL has at least one cosign
+// signature made with the key of the verifier, and that it carries the
+// attestations required by the options.
+func (c *Client) Verify(ctx context.Context, url string, verifier *Verifier, opts ...VerifyOption) error {
+	_, err := c.VerifyArtifact(ctx, url, verifier, opts...)
+	return err
+}
+
+// VerifyArtifact verifies the artifact at the given URL like Verify, and
+// returns the VerificationResult holding the matched attestations, e.g. for
+// recording their digests in the status of an object.
+func (c *Client) VerifyArtifact(ctx context.Context, url string, verifier *Verifier,
+	opts ...VerifyOption) (*VerificationResult, error) {
+	o := verifyOptions{missingPolicy: MissingPolicyFail}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	tv, err := newTransparencyVerifier(o)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, digest, err := c.resolveDigest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.verifySignatures(ctx, url, ref, digest, verifier, tv)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.verifyAttestations(ctx, url, ref, digest, verifier.publicKey, o)
+	if result != nil {
+		result.Transparency = info.transparency
+		result.SignedAt = info.signedAt
+	}
+	return result, err
+}
+
+// verifySignatures verifies that the artifact with the given digest has
+// at least one cosign signature made with the key of the verifier, and
+// returns the signing time of the first valid signature.
+func (c *Client) verifySignatures(ctx context.Context, url string, ref name.Reference, digest gcrv1.Hash,
+	verifier *Verifier, tv *transparencyVerifier) (signatureInfo, error) {
+	sigTag := cosignSignatureTag(ref.Context(), digest)
+	img, err := c.pullSignatures(ctx, sigTag)
+	if err != nil {
+		return signatureInfo{}, err
+	}
+	if img == nil {
+		return signatureInfo{}, fmt.Errorf("no signatures found for '%s'", url)
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return signatureInfo{}, fmt.Errorf("parsing signature manifest failed: %w", err)
+	}
+
+	var errs []error
+	for _, desc := range manifest.Layers {
+		if desc.MediaType != CosignSignatureMediaType {
+			continue
+		}
+		info, err := verifyCosignLayer(img, desc, digest, verifier.publicKey, tv)
+		if err == nil {
+			return info, nil
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return signatureInfo{}, fmt.Errorf("no signatures found for '%s'", url)
+	}
+	return signatureInfo{}, fmt.Errorf("no valid signatures found for '%s': %w", url, errors.Join(errs...))
+}
+
+// resolveDigest returns the parsed reference and the manifest digest
+// of the artifact at the given URL.
+func (c *Client) resolveDigest(ctx context.Context, url string) (name.Reference, gcrv1.Hash, error) {
+	ref, err := name.ParseReference(url)
+	if err != nil {
+		return nil, gcrv1.Hash{}, fmt.Errorf("invalid URL: %w", err)
+	}
+	d, err := crane.Digest(url, c.optionsWithContext(ctx)...)
+	if err != nil {
+		return nil, gcrv1.Hash{}, fmt.Errorf("fetching digest for '%s' failed: %w", url, err)
+	}
+	digest, err := gcrv1.NewHash(d)
+	if err != nil {
+		return nil, gcrv1.Hash{}, fmt.Errorf("parsing digest for '%s' failed: %w", url, err)
+	}
+	return ref, digest, nil
+}
+
+// pullSignatures returns the signature image for the given tag,
+// or nil if the artifact has no signatures.
+func (c *Client) pullSignatures(ctx context.Context, sigTag name.Tag) (gcrv1.Image, error) {
+	img, err := crane.Pull(sigTag.String(), c.optionsWithContext(ctx)...)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("pulling signatures '%s' failed: %w", sigTag, err)
+	}
+	return img, nil
+}
+
+// cosignSignatureTag returns the tag of the signature image
+// for the artifact with the given digest.
+func cosignSignatureTag(repo name.Repository, digest gcrv1.Hash) name.Tag {
+	return repo.Tag(fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex))
+}
+
+// cosignPayload is the cosign simple signing payload.
+type cosignPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]any `json:"optional"`
+}
+
+func newCosignPayload(repo name.Repository, digest gcrv1.Hash) cosignPayload {
+	var p cosignPayload
+	p.Critical.Identity.DockerReference = repo.Name()
+	p.Critical.Image.DockerManifestDigest = digest.String()
+	p.Critical.Type = cosignSignatureType
+	return p
+}
+
+// verifyCosignLayer verifies the signature of the payload in the given
+// layer and its signing time, checks that the payload refers to the
+// artifact digest, and returns the signing time.
+func verifyCosignLayer(img gcrv1.Image, desc gcrv1.Descriptor, digest gcrv1.Hash, pub crypto.PublicKey,
+	tv *transparencyVerifier) (signatureInfo, error) {
+	encodedSig, ok := desc.Annotations[CosignSignatureAnnotation]
+	if !ok {
+		return signatureInfo{}, fmt.Errorf("layer %s has no signature annotation", desc.Digest)
+	}
+	sig, err := base64.StdEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return signatureInfo{}, fmt.Errorf("layer %s has an invalid signature encoding: %w", desc.Digest, err)
+	}
+
+	layer, err := img.LayerByDigest(desc.Digest)
+	if err != nil {
+		return signatureInfo{}, fmt.Errorf("fetching layer %s failed: %w", desc.Digest, err)
+	}
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return signatureInfo{}, fmt.Errorf("fetching layer %s failed: %w", desc.Digest, err)
+	}
+	defer rc.Close()
+	payload, err := io.ReadAll(rc)
+	if err != nil {
+		return signatureInfo{}, fmt.Errorf("reading layer %s failed: %w", desc.Digest, err)
+	}
+
+	if err := verifySignature(pub, payload, sig); err != nil {
+		return signatureInfo{}, fmt.Errorf("layer %s: %w", desc.Digest, err)
+	}
+
+	var p cosignPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return signatureInfo{}, fmt.Errorf("layer %s has an invalid payload: %w", desc.Digest, err)
+	}
+	if p.Critical.Type != cosignSignatureType {
+		return signatureInfo{}, fmt.Errorf("layer %s has an invalid payload type '%s'", desc.Digest, p.Critical.Type)
+	}
+	if p.Critical.Image.DockerManifestDigest != digest.String() {
+		return signatureInfo{}, fmt.Errorf("layer %s is signing digest '%s' instead of '%s'",
+			desc.Digest, p.Critical.Image.DockerManifestDigest, digest)
+	}
+
+	info, err := tv.verify(desc.Annotations, payload, sig, pub)
+	if err != nil {
+		return signatureInfo{}, fmt.Errorf("layer %s: %w", desc.Digest, err)
+	}
+	return info, nil
+}
+
+// verifySignature verifies the signature of the payload with the given public key.
+func verifySignature(pub crypto.PublicKey, payload, sig []byte) error {
+	digest := sha256.Sum256(payload)
+	var ok bool
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(k, digest[:], sig)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, payload, sig)
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+	if !ok {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// parseCosignPrivateKey decrypts and parses the given cosign private key.
+func parseCosignPrivateKey(key, password []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, errors.New("failed to decode private key: no PEM data found")
+	}
+	if block.Type != sigstorePrivateKeyPEMType && block.Type != cosignPrivateKeyPEMType {
+		return nil, fmt.Errorf("unsupported private key PEM type '%s'", block.Type)
+	}
+
+	der, err := encrypted.Decrypt(block.Bytes, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", priv)
+	}
+	if err := checkKeyType(signer.Public()); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// checkKeyType returns an error if the public key type is not supported.
+func checkKeyType(pub crypto.PublicKey) error {
+	switch pub.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// newKMSSigner validates the KMS key reference and returns
+// the KMSSigner from the provider.
+func newKMSSigner(ctx context.Context, keyRef string, provider KMSProvider) (KMSSigner, error) {
+	if !IsKMSKeyRef(keyRef) {
+		return nil, fmt.Errorf("unsupported KMS key reference '%s': must start with one of %s",
+			keyRef, strings.Join(kmsSchemes, ", "))
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("a KMS provider is required for the key reference '%s'", keyRef)
+	}
+	kms, err := provider.NewKMSSigner(ctx, keyRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create KMS signer for '%s': %w", keyRef, err)
+	}
+	return kms, nil
+}
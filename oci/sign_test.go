@@ -0,0 +1,333 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	. "github.com/onsi/gomega"
+	"github.com/secure-systems-lab/go-securesystemslib/encrypted"
+)
+
+// generateCosignKeyPair returns a private key encrypted with the given
+// password and the public key, in the format of `cosign generate-key-pair`.
+func generateCosignKeyPair(t *testing.T, password []byte) (*ecdsa.PrivateKey, []byte, []byte) {
+	t.Helper()
+	g := NewWithT(t)
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	g.Expect(err).NotTo(HaveOccurred())
+	encryptedDER, err := encrypted.Encrypt(der, password)
+	g.Expect(err).NotTo(HaveOccurred())
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: encryptedDER})
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	g.Expect(err).NotTo(HaveOccurred())
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	return priv, privPEM, pubPEM
+}
+
+func pushRandomArtifact(t *testing.T, c *Client, repo string) string {
+	t.Helper()
+	g := NewWithT(t)
+
+	url := fmt.Sprintf("%s/%s:v1.0.0", dockerReg, repo)
+	img, err := random.Image(1024, 1)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(crane.Push(img, url, c.options...)).To(Succeed())
+	return url
+}
+
+// fakeKMS is a KMSSigner and KMSProvider backed by an in-memory key.
+type fakeKMS struct {
+	keyRef string
+	key    *ecdsa.PrivateKey
+	err    error
+}
+
+func (f *fakeKMS) NewKMSSigner(_ context.Context, keyRef string) (KMSSigner, error) {
+	if keyRef != f.keyRef {
+		return nil, fmt.Errorf("key '%s' not found", keyRef)
+	}
+	return f, nil
+}
+
+func (f *fakeKMS) PublicKey(context.Context) (crypto.PublicKey, error) {
+	return &f.key.PublicKey, f.err
+}
+
+func (f *fakeKMS) SignDigest(_ context.Context, digest []byte, hash crypto.Hash) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if hash != crypto.SHA256 {
+		return nil, fmt.Errorf("unexpected hash %s", hash)
+	}
+	return ecdsa.SignASN1(rand.Reader, f.key, digest)
+}
+
+func Test_SignVerify_Key(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	url := pushRandomArtifact(t, c, "test-sign-key")
+
+	password := []byte("s3cr3t")
+	_, privPEM, pubPEM := generateCosignKeyPair(t, password)
+
+	signer, err := NewSignerFromKey(privPEM, password)
+	g.Expect(err).NotTo(HaveOccurred())
+	verifier, err := NewVerifierFromKey(pubPEM)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The artifact is not signed yet.
+	err = c.Verify(ctx, url, verifier)
+	g.Expect(err).To(MatchError(ContainSubstring("no signatures found")))
+
+	sigRef, err := c.Sign(ctx, url, signer)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The signature is stored alongside the artifact with the cosign tag naming.
+	digest, err := crane.Digest(url, c.options...)
+	g.Expect(err).NotTo(HaveOccurred())
+	wantTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	g.Expect(sigRef).To(Equal(fmt.Sprintf("%s/test-sign-key:%s", dockerReg, wantTag)))
+	tags, err := crane.ListTags(fmt.Sprintf("%s/test-sign-key", dockerReg), c.options...)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tags).To(ContainElement(wantTag))
+	g.Expect(IsCosignArtifact(wantTag)).To(BeTrue())
+
+	g.Expect(c.Verify(ctx, url, verifier)).To(Succeed())
+
+	// The signature is not valid for another key.
+	_, otherPrivPEM, otherPubPEM := generateCosignKeyPair(t, password)
+	otherVerifier, err := NewVerifierFromKey(otherPubPEM)
+	g.Expect(err).NotTo(HaveOccurred())
+	err = c.Verify(ctx, url, otherVerifier)
+	g.Expect(err).To(MatchError(ContainSubstring("no valid signatures found")))
+	g.Expect(err).To(MatchError(ContainSubstring("invalid signature")))
+
+	// Signing with another key preserves the existing signatures.
+	otherSigner, err := NewSignerFromKey(otherPrivPEM, password)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = c.Sign(ctx, url, otherSigner)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Verify(ctx, url, verifier)).To(Succeed())
+	g.Expect(c.Verify(ctx, url, otherVerifier)).To(Succeed())
+
+	sigImg, err := crane.Pull(sigRef, c.options...)
+	g.Expect(err).NotTo(HaveOccurred())
+	layers, err := sigImg.Layers()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(layers).To(HaveLen(2))
+}
+
+func Test_Verify_DigestMismatch(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	password := []byte("s3cr3t")
+	_, privPEM, pubPEM := generateCosignKeyPair(t, password)
+	signer, err := NewSignerFromKey(privPEM, password)
+	g.Expect(err).NotTo(HaveOccurred())
+	verifier, err := NewVerifierFromKey(pubPEM)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	signedURL := pushRandomArtifact(t, c, "test-sign-mismatch-a")
+	sigRef, err := c.Sign(ctx, signedURL, signer)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Copy the signature to the signature tag of another artifact.
+	url := pushRandomArtifact(t, c, "test-sign-mismatch-b")
+	digest, err := crane.Digest(url, c.options...)
+	g.Expect(err).NotTo(HaveOccurred())
+	dst := fmt.Sprintf("%s/test-sign-mismatch-b:%s.sig", dockerReg, strings.Replace(digest, ":", "-", 1))
+	g.Expect(crane.Copy(sigRef, dst, c.options...)).To(Succeed())
+
+	err = c.Verify(ctx, url, verifier)
+	g.Expect(err).To(MatchError(ContainSubstring("instead of '" + digest + "'")))
+}
+
+func Test_NewSignerFromKey(t *testing.T) {
+	password := []byte("s3cr3t")
+	_, privPEM, pubPEM := generateCosignKeyPair(t, password)
+
+	tests := []struct {
+		name     string
+		key      []byte
+		password []byte
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			key:      privPEM,
+			password: password,
+		},
+		{
+			name:     "legacy cosign PEM type",
+			key:      []byte(strings.ReplaceAll(string(privPEM), "SIGSTORE", "COSIGN")),
+			password: password,
+		},
+		{
+			name:     "wrong password",
+			key:      privPEM,
+			password: []byte("wrong"),
+			wantErr:  "failed to decrypt private key",
+		},
+		{
+			name:     "public key",
+			key:      pubPEM,
+			password: password,
+			wantErr:  "unsupported private key PEM type 'PUBLIC KEY'",
+		},
+		{
+			name:    "not PEM",
+			key:     []byte("foo"),
+			wantErr: "no PEM data found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			signer, err := NewSignerFromKey(tt.key, tt.password)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				g.Expect(signer).To(BeNil())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(signer).NotTo(BeNil())
+		})
+	}
+}
+
+func Test_SignVerify_KMS(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	url := pushRandomArtifact(t, c, "test-sign-kms")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	const keyRef = "awskms:///arn:aws:kms:us-east-1:123456789012:key/1234abcd"
+	kms := &fakeKMS{keyRef: keyRef, key: key}
+
+	signer, err := NewSignerFromKMS(ctx, keyRef, kms)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = c.Sign(ctx, url, signer)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	verifier, err := NewVerifierFromKMS(ctx, keyRef, kms)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Verify(ctx, url, verifier)).To(Succeed())
+
+	// The signature can be verified with the exported public key.
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	g.Expect(err).NotTo(HaveOccurred())
+	pubVerifier, err := NewVerifierFromKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Verify(ctx, url, pubVerifier)).To(Succeed())
+
+	// KMS failures are returned.
+	kms.err = errors.New("throttled")
+	_, err = c.Sign(ctx, url, signer)
+	g.Expect(err).To(MatchError(ContainSubstring("throttled")))
+	_, err = NewVerifierFromKMS(ctx, keyRef, kms)
+	g.Expect(err).To(MatchError(ContainSubstring("throttled")))
+}
+
+func Test_NewSignerFromKMS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		name     string
+		keyRef   string
+		provider KMSProvider
+		wantErr  string
+	}{
+		{
+			name:     "aws",
+			keyRef:   "awskms:///alias/flux",
+			provider: &fakeKMS{keyRef: "awskms:///alias/flux", key: key},
+		},
+		{
+			name:     "azure",
+			keyRef:   "azurekms://flux.vault.azure.net/flux",
+			provider: &fakeKMS{keyRef: "azurekms://flux.vault.azure.net/flux", key: key},
+		},
+		{
+			name:     "gcp",
+			keyRef:   "gcpkms://projects/flux/locations/global/keyRings/flux/cryptoKeys/flux",
+			provider: &fakeKMS{keyRef: "gcpkms://projects/flux/locations/global/keyRings/flux/cryptoKeys/flux", key: key},
+		},
+		{
+			name:     "hashicorp vault",
+			keyRef:   "hashivault://flux",
+			provider: &fakeKMS{keyRef: "hashivault://flux", key: key},
+		},
+		{
+			name:     "unsupported scheme",
+			keyRef:   "pkcs11://flux",
+			provider: &fakeKMS{keyRef: "pkcs11://flux", key: key},
+			wantErr:  "unsupported KMS key reference 'pkcs11://flux'",
+		},
+		{
+			name:    "no provider",
+			keyRef:  "hashivault://flux",
+			wantErr: "a KMS provider is required",
+		},
+		{
+			name:     "key not found",
+			keyRef:   "hashivault://other",
+			provider: &fakeKMS{keyRef: "hashivault://flux", key: key},
+			wantErr:  "key 'hashivault://other' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			signer, err := NewSignerFromKMS(context.Background(), tt.keyRef, tt.provider)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				g.Expect(signer).To(BeNil())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(signer).NotTo(BeNil())
+		})
+	}
+}
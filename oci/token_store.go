@@ -0,0 +1,251 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultTokenExpiresIn is the validity of the registry tokens without
+	// an expires_in field, as defined by the Docker token specification.
+	defaultTokenExpiresIn = 60 * time.Second
+
+	// tokenExpiryMargin is subtracted from the validity of the registry
+	// tokens, so that a reused token does not expire in the middle of an
+	// operation.
+	tokenExpiryMargin = 10 * time.Second
+)
+
+// bearerRealmRegexp matches the realm of a Bearer challenge.
+var bearerRealmRegexp = regexp.MustCompile(`(?i)^\s*bearer\s.*\brealm="([^"]+)"`)
+
+// tokenStore holds the bearer tokens issued by the token endpoints of the
+// registries to the remote operations of a Client, so that the operations
+// on the same repository reuse the token within its validity window instead
+// of authenticating again, which counts against the rate limits of
+// registries such as Docker Hub. The tokens are keyed by the token
+// endpoint, the service and scopes of the request, i.e. the registry and
+// repository, and the credentials.
+type tokenStore struct {
+	mu     sync.Mutex
+	realms map[string]struct{}
+	tokens map[string]cachedToken
+	now    func() time.Time
+}
+
+// cachedToken is a token endpoint response and its expiry.
+type cachedToken struct {
+	header http.Header
+	body   []byte
+	expiry time.Time
+}
+
+func newTokenStore() *tokenStore {
+	return &tokenStore{
+		realms: map[string]struct{}{},
+		tokens: map[string]cachedToken{},
+		now:    time.Now,
+	}
+}
+
+// tokenTransport serves the requests to the token endpoints from the
+// tokenStore, and stores the tokens of their responses. The token endpoints
+// are learned from the Bearer challenges of the registries.
+type tokenTransport struct {
+	store *tokenStore
+	next  http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.store.isRealm(req.URL) {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized {
+			t.store.addRealms(resp.Header.Values("WWW-Authenticate"))
+		}
+		return resp, err
+	}
+
+	key, err := tokenKey(req)
+	if err != nil {
+		return nil, err
+	}
+	if token, ok := t.store.get(key); ok {
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         req.Proto,
+			ProtoMajor:    req.ProtoMajor,
+			ProtoMinor:    req.ProtoMinor,
+			Header:        token.header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(token.body)),
+			ContentLength: int64(len(token.body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.store.set(key, resp.Header, body)
+	return resp, nil
+}
+
+// isRealm returns true if the URL is a known token endpoint.
+func (s *tokenStore) isRealm(u *url.URL) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.realms[realmKey(u)]
+	return ok
+}
+
+// addRealms records the token endpoints of the Bearer challenges.
+func (s *tokenStore) addRealms(challenges []string) {
+	for _, challenge := range challenges {
+		m := bearerRealmRegexp.FindStringSubmatch(challenge)
+		if m == nil {
+			continue
+		}
+		u, err := url.Parse(m[1])
+		if err != nil {
+			continue
+		}
+		s.mu.Lock()
+		s.realms[realmKey(u)] = struct{}{}
+		s.mu.Unlock()
+	}
+}
+
+// get returns the unexpired token of the given key.
+func (s *tokenStore) get(key string) (cachedToken, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[key]
+	if !ok {
+		return cachedToken{}, false
+	}
+	if !s.now().Before(token.expiry) {
+		delete(s.tokens, key)
+		return cachedToken{}, false
+	}
+	return token, true
+}
+
+// set stores the token of a token endpoint response until its expiry, as
+// computed from its expires_in and issued_at fields. Responses which cannot
+// be decoded, or expire within the expiry margin, are not stored.
+func (s *tokenStore) set(key string, header http.Header, body []byte) {
+	var response struct {
+		Token       string    `json:"token"`
+		AccessToken string    `json:"access_token"`
+		ExpiresIn   int       `json:"expires_in"`
+		IssuedAt    time.Time `json:"issued_at"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return
+	}
+	if response.Token == "" && response.AccessToken == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	issuedAt := response.IssuedAt
+	if issuedAt.IsZero() || issuedAt.After(now) {
+		issuedAt = now
+	}
+	expiresIn := defaultTokenExpiresIn
+	if response.ExpiresIn > 0 {
+		expiresIn = time.Duration(response.ExpiresIn) * time.Second
+	}
+	expiry := issuedAt.Add(expiresIn - tokenExpiryMargin)
+	if !now.Before(expiry) {
+		return
+	}
+
+	// Drop the expired tokens, so that the store does not grow with the
+	// repositories accessed over time.
+	for k, token := range s.tokens {
+		if !now.Before(token.expiry) {
+			delete(s.tokens, k)
+		}
+	}
+	s.tokens[key] = cachedToken{header: header.Clone(), body: body, expiry: expiry}
+}
+
+// realmKey returns the token endpoint of the URL, without its query.
+func realmKey(u *url.URL) string {
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + u.EscapedPath()
+}
+
+// tokenKey returns the key of a token endpoint request: the endpoint, the
+// service and scopes, either from the query for a GET request or from the
+// form of a POST request, and a digest of the credentials.
+func tokenKey(req *http.Request) (string, error) {
+	values := req.URL.Query()
+	digest := sha256.New()
+	digest.Write([]byte(req.Header.Get("Authorization")))
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		digest.Write([]byte{0})
+		digest.Write(body)
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for k, v := range form {
+				values[k] = append(values[k], v...)
+			}
+		}
+	}
+
+	var scopes []string
+	for _, scope := range values["scope"] {
+		scopes = append(scopes, strings.Fields(scope)...)
+	}
+	slices.Sort(scopes)
+	return strings.Join([]string{
+		req.Method,
+		realmKey(req.URL),
+		values.Get("service"),
+		strings.Join(slices.Compact(scopes), " "),
+		hex.EncodeToString(digest.Sum(nil)),
+	}, "\n"), nil
+}
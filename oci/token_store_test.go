@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	. "github.com/onsi/gomega"
+)
+
+// newTokenAuthRegistry starts an in-memory registry requiring the bearer
+// tokens of its token endpoint, which counts the token requests, and
+// reporting a rate limit on the manifest responses. It returns the host of
+// the registry.
+func newTokenAuthRegistry(t *testing.T, tokenRequests *atomic.Int32) string {
+	t.Helper()
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+
+	var host string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			n := tokenRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"token":      fmt.Sprintf("token-%d", n),
+				"expires_in": 300,
+				"issued_at":  time.Now().UTC().Format(time.RFC3339),
+			})
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer token-") {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="http://%s/token",service="test-registry"`, host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.Contains(r.URL.Path, "/manifests/") {
+			w.Header().Set("RateLimit-Limit", "100;w=21600")
+			w.Header().Set("RateLimit-Remaining", "76;w=21600")
+			w.Header().Set("Docker-RateLimit-Source", "192.0.2.1")
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	// The realm must not be an IP address of the loopback interface.
+	host = strings.Replace(srv.Listener.Addr().String(), "127.0.0.1", "localhost", 1)
+	return host
+}
+
+func TestClient_TokenReuse(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	var tokenRequests atomic.Int32
+	host := newTokenAuthRegistry(t, &tokenRequests)
+	url := host + "/org/app:v1"
+
+	var mu sync.Mutex
+	var hooked []RateLimit
+	c := NewClient(DefaultOptions()).WithRateLimitHook(func(rl RateLimit) {
+		mu.Lock()
+		defer mu.Unlock()
+		hooked = append(hooked, rl)
+	})
+
+	// The artifacts are pushed with another client, as the push reuses its
+	// tokens of the pull scope.
+	pusher := NewClient(DefaultOptions())
+	_, err := pusher.Push(ctx, url, "testdata/artifact", WithPushMetadata(Metadata{
+		Source:   "github.com/fluxcd/flux2",
+		Revision: "rev",
+	}))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tokenRequests.Load()).ToNot(BeZero())
+
+	// The pulls reuse the token of the repository pull scope.
+	tokenRequests.Store(0)
+	var meta *Metadata
+	for range 3 {
+		meta, err = c.Pull(ctx, url, t.TempDir())
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(tokenRequests.Load()).To(Equal(int32(1)))
+
+	expectedRateLimit := RateLimit{
+		Registry:   host,
+		Repository: "org/app",
+		Limit:      100,
+		Remaining:  76,
+		Window:     6 * time.Hour,
+		Source:     "192.0.2.1",
+	}
+	g.Expect(meta.RateLimit).To(Equal(&expectedRateLimit))
+	mu.Lock()
+	g.Expect(hooked).ToNot(BeEmpty())
+	g.Expect(hooked[len(hooked)-1]).To(Equal(expectedRateLimit))
+	mu.Unlock()
+
+	// The tokens are not shared across clients.
+	_, err = NewClient(DefaultOptions()).Pull(ctx, url, t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tokenRequests.Load()).To(Equal(int32(2)))
+
+	// Another repository gets its own token.
+	_, err = pusher.Push(ctx, host+"/org/other:v1", "testdata/artifact")
+	g.Expect(err).ToNot(HaveOccurred())
+	tokenRequests.Store(0)
+	_, err = c.Pull(ctx, host+"/org/other:v1", t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = c.Pull(ctx, url, t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tokenRequests.Load()).To(Equal(int32(1)))
+
+	// The expired token is renewed.
+	c.tokens.now = func() time.Time { return time.Now().Add(5 * time.Minute) }
+	_, err = c.Pull(ctx, url, t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tokenRequests.Load()).To(Equal(int32(2)))
+}
+
+func Test_parseRateLimitHeader(t *testing.T) {
+	for _, tt := range []struct {
+		value     string
+		n         int
+		window    time.Duration
+		wantValid bool
+	}{
+		{value: "100;w=21600", n: 100, window: 6 * time.Hour, wantValid: true},
+		{value: "76", n: 76, wantValid: true},
+		{value: " 5 ; w=60", n: 5, window: time.Minute, wantValid: true},
+		{value: ""},
+		{value: "unlimited;w=60"},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			g := NewWithT(t)
+			n, window, ok := parseRateLimitHeader(tt.value)
+			g.Expect(ok).To(Equal(tt.wantValid))
+			g.Expect(n).To(Equal(tt.n))
+			g.Expect(window).To(Equal(tt.window))
+		})
+	}
+}
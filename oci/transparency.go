@@ -0,0 +1,566 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	_ "crypto/sha512"
+)
+
+const (
+	// CosignCertificateAnnotation is the layer annotation holding the PEM
+	// encoded certificate of the key which made the signature.
+	CosignCertificateAnnotation = "dev.sigstore.cosign/certificate"
+
+	// CosignRFC3161TimestampAnnotation is the layer annotation holding the
+	// RFC3161 timestamp of the signature, signed by a timestamp authority.
+	CosignRFC3161TimestampAnnotation = "dev.sigstore.cosign/rfc3161timestamp"
+
+	// CosignBundleAnnotation is the layer annotation holding the Rekor
+	// bundle of the signature, i.e. the signed entry timestamp of the
+	// transparency log entry.
+	CosignBundleAnnotation = "dev.sigstore.cosign/bundle"
+)
+
+// TransparencySource is the source of the signing time of a verified
+// signature.
+type TransparencySource string
+
+const (
+	// TransparencyNone means that the signing time of the signature is
+	// not verified, the certificate of the signature, if any, is checked
+	// against the current time.
+	TransparencyNone TransparencySource = "none"
+	// TransparencyRekor means that the signature is recorded in the Rekor
+	// transparency log, and signed at the integrated time of its entry.
+	TransparencyRekor TransparencySource = "rekor"
+	// TransparencyTSA means that the signature is signed at the time of
+	// its RFC3161 timestamp, issued by a timestamp authority.
+	TransparencyTSA TransparencySource = "tsa"
+)
+
+// WithTSACertChain requires the cosign signatures to carry an RFC3161
+// timestamp signed by the timestamp authority with the given certificate
+// chain in PEM format, ordered from the timestamp authority certificate to
+// the root certificate, like the `--timestamp-certificate-chain` flag of
+// cosign. The certificate of a signature is checked against the time of its
+// timestamp, so that signatures made with a certificate which is now
+// expired are accepted.
+func WithTSACertChain(pem []byte) VerifyOption {
+	return func(o *verifyOptions) {
+		o.tsaCertChain = pem
+	}
+}
+
+// WithRekorPublicKey requires the cosign signatures to carry a Rekor bundle
+// whose signed entry timestamp is signed with the given public key in PEM
+// format. The certificate of a signature is checked against the integrated
+// time of its transparency log entry.
+func WithRekorPublicKey(pem []byte) VerifyOption {
+	return func(o *verifyOptions) {
+		o.rekorPublicKey = pem
+	}
+}
+
+// signatureInfo holds the verified signing time of a signature.
+type signatureInfo struct {
+	transparency TransparencySource
+	signedAt     time.Time
+}
+
+// transparencyVerifier verifies the signing time of the cosign signatures.
+type transparencyVerifier struct {
+	tsaCerts         []*x509.Certificate
+	tsaRoots         *x509.CertPool
+	tsaIntermediates *x509.CertPool
+	rekorPublicKey   crypto.PublicKey
+}
+
+// newTransparencyVerifier returns the transparencyVerifier configured by the
+// given options.
+func newTransparencyVerifier(o verifyOptions) (*transparencyVerifier, error) {
+	v := &transparencyVerifier{}
+
+	if o.tsaCertChain != nil {
+		var certs []*x509.Certificate
+		for rest := o.tsaCertChain; ; {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse TSA certificate chain: %w", err)
+			}
+			certs = append(certs, cert)
+		}
+		if len(certs) == 0 {
+			return nil, errors.New("failed to parse TSA certificate chain: no certificates found")
+		}
+		v.tsaCerts = certs
+		v.tsaRoots = x509.NewCertPool()
+		v.tsaRoots.AddCert(certs[len(certs)-1])
+		v.tsaIntermediates = x509.NewCertPool()
+		for i := 1; i < len(certs)-1; i++ {
+			v.tsaIntermediates.AddCert(certs[i])
+		}
+	}
+
+	if o.rekorPublicKey != nil {
+		block, _ := pem.Decode(o.rekorPublicKey)
+		if block == nil {
+			return nil, errors.New("failed to decode Rekor public key: no PEM data found")
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Rekor public key: %w", err)
+		}
+		if err := checkKeyType(pub); err != nil {
+			return nil, err
+		}
+		v.rekorPublicKey = pub
+	}
+
+	return v, nil
+}
+
+// verify verifies the signing time of the signature of the payload in the
+// layer with the given annotations, and the validity of the certificate of
+// the signature at that time. The timestamp takes precedence over the Rekor
+// bundle for the signing time when both are required.
+func (v *transparencyVerifier) verify(annotations map[string]string, payload, sig []byte,
+	pub crypto.PublicKey) (signatureInfo, error) {
+	info := signatureInfo{transparency: TransparencyNone, signedAt: time.Now()}
+
+	if v.rekorPublicKey != nil {
+		encoded, ok := annotations[CosignBundleAnnotation]
+		if !ok {
+			return info, errors.New("no Rekor bundle found")
+		}
+		integratedTime, err := v.verifyRekorBundle([]byte(encoded), payload, sig)
+		if err != nil {
+			return info, fmt.Errorf("invalid Rekor bundle: %w", err)
+		}
+		info = signatureInfo{transparency: TransparencyRekor, signedAt: integratedTime}
+	}
+
+	if v.tsaCerts != nil {
+		encoded, ok := annotations[CosignRFC3161TimestampAnnotation]
+		if !ok {
+			return info, errors.New("no RFC3161 timestamp found")
+		}
+		var ts struct {
+			SignedRFC3161Timestamp []byte `json:"SignedRFC3161Timestamp"`
+		}
+		if err := json.Unmarshal([]byte(encoded), &ts); err != nil {
+			return info, fmt.Errorf("invalid RFC3161 timestamp encoding: %w", err)
+		}
+		genTime, err := v.verifyTimestamp(ts.SignedRFC3161Timestamp, sig)
+		if err != nil {
+			return info, fmt.Errorf("invalid RFC3161 timestamp: %w", err)
+		}
+		info = signatureInfo{transparency: TransparencyTSA, signedAt: genTime}
+	}
+
+	if encoded, ok := annotations[CosignCertificateAnnotation]; ok {
+		if err := verifyCertificateAt([]byte(encoded), pub, info.signedAt); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// verifyCertificateAt checks that the given PEM certificate is for the
+// public key of the verifier, and that it is valid at the given time.
+func verifyCertificateAt(certPEM []byte, pub crypto.PublicKey, t time.Time) error {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return errors.New("failed to decode certificate: no PEM data found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	if k, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(pub) {
+		return errors.New("certificate does not match the public key")
+	}
+	if t.Before(cert.NotBefore) || t.After(cert.NotAfter) {
+		return fmt.Errorf("certificate is not valid at signing time %s: valid from %s to %s",
+			t.UTC().Format(time.RFC3339), cert.NotBefore.UTC().Format(time.RFC3339), cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// rekorBundle is the Rekor bundle of a cosign signature.
+type rekorBundle struct {
+	SignedEntryTimestamp []byte `json:"SignedEntryTimestamp"`
+	Payload              struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogIndex       int64  `json:"logIndex"`
+		LogID          string `json:"logID"`
+	} `json:"Payload"`
+}
+
+// hashedRekord is the part of a Rekor hashedrekord entry which is verified.
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content string `json:"content"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifyRekorBundle verifies the signed entry timestamp of the given Rekor
+// bundle, checks that its entry records the signature of the payload, and
+// returns the integrated time of the entry.
+func (v *transparencyVerifier) verifyRekorBundle(data, payload, sig []byte) (time.Time, error) {
+	var bundle rekorBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return time.Time{}, err
+	}
+
+	// The signed entry timestamp is the signature of the canonical JSON
+	// of the bundle payload, whose keys are sorted.
+	canonical, err := json.Marshal(map[string]any{
+		"body":           bundle.Payload.Body,
+		"integratedTime": bundle.Payload.IntegratedTime,
+		"logIndex":       bundle.Payload.LogIndex,
+		"logID":          bundle.Payload.LogID,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := verifySignature(v.rekorPublicKey, canonical, bundle.SignedEntryTimestamp); err != nil {
+		return time.Time{}, fmt.Errorf("signed entry timestamp: %w", err)
+	}
+
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid entry encoding: %w", err)
+	}
+	var entry hashedRekord
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return time.Time{}, fmt.Errorf("invalid entry: %w", err)
+	}
+	if entry.Kind != "hashedrekord" {
+		return time.Time{}, fmt.Errorf("unsupported entry kind '%s'", entry.Kind)
+	}
+	digest := sha256.Sum256(payload)
+	if entry.Spec.Data.Hash.Algorithm != "sha256" || entry.Spec.Data.Hash.Value != hex.EncodeToString(digest[:]) {
+		return time.Time{}, errors.New("entry does not match the payload digest")
+	}
+	entrySig, err := base64.StdEncoding.DecodeString(entry.Spec.Signature.Content)
+	if err != nil || !bytes.Equal(entrySig, sig) {
+		return time.Time{}, errors.New("entry does not match the signature")
+	}
+
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+// The ASN.1 object identifiers of the RFC3161 timestamps.
+var (
+	oidSignedData         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidTSTInfo            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	oidAttrContentType    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttrMessageDigest  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidDigestAlgSHA256    = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidDigestAlgSHA384    = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidDigestAlgSHA512    = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	timestampStatusGrants = []int{0, 1}
+)
+
+// timeStampResp is the RFC3161 TimeStampResp.
+type timeStampResp struct {
+	Status struct {
+		Status       int
+		StatusString asn1.RawValue  `asn1:"optional"`
+		FailInfo     asn1.BitString `asn1:"optional"`
+	}
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+// contentInfo is the CMS ContentInfo.
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+// signedData is the CMS SignedData.
+type signedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	EncapContentInfo struct {
+		EContentType asn1.ObjectIdentifier
+		EContent     []byte `asn1:"explicit,optional,tag:0"`
+	}
+	Certificates asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs         asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos  []signerInfo  `asn1:"set"`
+}
+
+// signerInfo is the CMS SignerInfo.
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// issuerAndSerialNumber is the CMS IssuerAndSerialNumber.
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// attribute is a CMS Attribute.
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+// tstInfo is the RFC3161 TSTInfo.
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint struct {
+		HashAlgorithm pkix.AlgorithmIdentifier
+		HashedMessage []byte
+	}
+	SerialNumber *big.Int
+	GenTime      time.Time `asn1:"generalized"`
+	Accuracy     struct {
+		Seconds int `asn1:"optional"`
+		Millis  int `asn1:"optional,tag:0"`
+		Micros  int `asn1:"optional,tag:1"`
+	} `asn1:"optional"`
+	Ordering   bool          `asn1:"optional"`
+	Nonce      *big.Int      `asn1:"optional"`
+	TSA        asn1.RawValue `asn1:"optional,explicit,tag:0"`
+	Extensions asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// verifyTimestamp verifies the given DER encoded RFC3161 timestamp response
+// against the TSA certificate chain, checks that it timestamps the given
+// signature, and returns the time of the timestamp.
+func (v *transparencyVerifier) verifyTimestamp(resp, sig []byte) (time.Time, error) {
+	var tsr timeStampResp
+	if _, err := asn1.Unmarshal(resp, &tsr); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !slices.Contains(timestampStatusGrants, tsr.Status.Status) {
+		return time.Time{}, fmt.Errorf("timestamp not granted, status %d", tsr.Status.Status)
+	}
+
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(tsr.TimeStampToken.FullBytes, &ci); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return time.Time{}, fmt.Errorf("unsupported token content type %s", ci.ContentType)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse signed data: %w", err)
+	}
+	if !sd.EncapContentInfo.EContentType.Equal(oidTSTInfo) {
+		return time.Time{}, fmt.Errorf("unsupported content type %s", sd.EncapContentInfo.EContentType)
+	}
+	var info tstInfo
+	if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent, &info); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse TSTInfo: %w", err)
+	}
+
+	// The timestamp must be for the signature.
+	imprintHash, err := digestHash(info.MessageImprint.HashAlgorithm.Algorithm)
+	if err != nil {
+		return time.Time{}, err
+	}
+	h := imprintHash.New()
+	h.Write(sig)
+	if !bytes.Equal(h.Sum(nil), info.MessageImprint.HashedMessage) {
+		return time.Time{}, errors.New("message imprint does not match the signature")
+	}
+
+	if len(sd.SignerInfos) != 1 {
+		return time.Time{}, fmt.Errorf("expected one signer, found %d", len(sd.SignerInfos))
+	}
+	si := sd.SignerInfos[0]
+
+	var embedded []*x509.Certificate
+	if len(sd.Certificates.Bytes) > 0 {
+		embedded, err = x509.ParseCertificates(sd.Certificates.Bytes)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse certificates: %w", err)
+		}
+	}
+	signer, err := v.findTSASigner(si.SID, embedded)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if _, err := signer.Verify(x509.VerifyOptions{
+		Roots:         v.tsaRoots,
+		Intermediates: v.tsaIntermediates,
+		CurrentTime:   info.GenTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}); err != nil {
+		return time.Time{}, fmt.Errorf("TSA certificate verification failed: %w", err)
+	}
+
+	if err := verifySignerInfo(si, sd.EncapContentInfo.EContent, signer.PublicKey); err != nil {
+		return time.Time{}, err
+	}
+	return info.GenTime, nil
+}
+
+// findTSASigner returns the certificate identified by the given signer
+// identifier, from the certificates embedded in the timestamp or the TSA
+// certificate chain.
+func (v *transparencyVerifier) findTSASigner(sid asn1.RawValue, embedded []*x509.Certificate) (*x509.Certificate, error) {
+	var match func(cert *x509.Certificate) bool
+	switch {
+	case sid.Class == asn1.ClassContextSpecific && sid.Tag == 0:
+		match = func(cert *x509.Certificate) bool {
+			return bytes.Equal(cert.SubjectKeyId, sid.Bytes)
+		}
+	default:
+		var ias issuerAndSerialNumber
+		if _, err := asn1.Unmarshal(sid.FullBytes, &ias); err != nil {
+			return nil, fmt.Errorf("failed to parse signer identifier: %w", err)
+		}
+		match = func(cert *x509.Certificate) bool {
+			return bytes.Equal(cert.RawIssuer, ias.Issuer.FullBytes) && cert.SerialNumber.Cmp(ias.SerialNumber) == 0
+		}
+	}
+
+	for _, cert := range append(embedded, v.tsaCerts...) {
+		if match(cert) {
+			return cert, nil
+		}
+	}
+	return nil, errors.New("TSA certificate not found")
+}
+
+// verifySignerInfo verifies the signature of the signed attributes of the
+// signer, and checks that they hold the digest of the given content.
+func verifySignerInfo(si signerInfo, content []byte, pub crypto.PublicKey) error {
+	if len(si.SignedAttrs.FullBytes) == 0 {
+		return errors.New("signer has no signed attributes")
+	}
+	hash, err := digestHash(si.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	// The signed attributes are signed with their SET OF tag in place
+	// of the implicit tag of the field.
+	signed := bytes.Clone(si.SignedAttrs.FullBytes)
+	signed[0] = 0x31
+
+	var attrs []attribute
+	if _, err := asn1.UnmarshalWithParams(signed, &attrs, "set"); err != nil {
+		return fmt.Errorf("failed to parse signed attributes: %w", err)
+	}
+	var contentTypeOK, digestOK bool
+	h := hash.New()
+	h.Write(content)
+	for _, attr := range attrs {
+		switch {
+		case attr.Type.Equal(oidAttrContentType):
+			var contentType asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(attr.Values.Bytes, &contentType); err == nil {
+				contentTypeOK = contentType.Equal(oidTSTInfo)
+			}
+		case attr.Type.Equal(oidAttrMessageDigest):
+			var digest []byte
+			if _, err := asn1.Unmarshal(attr.Values.Bytes, &digest); err == nil {
+				digestOK = bytes.Equal(digest, h.Sum(nil))
+			}
+		}
+	}
+	if !contentTypeOK {
+		return errors.New("signed attributes have an invalid content type")
+	}
+	if !digestOK {
+		return errors.New("signed attributes have an invalid message digest")
+	}
+
+	h = hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+	var ok bool
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(k, digest, si.Signature)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(k, hash, digest, si.Signature) == nil
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, signed, si.Signature)
+	default:
+		return fmt.Errorf("unsupported TSA public key type %T", pub)
+	}
+	if !ok {
+		return errors.New("invalid TSA signature")
+	}
+	return nil
+}
+
+// digestHash returns the hash function of the given digest algorithm.
+func digestHash(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(oidDigestAlgSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(oidDigestAlgSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(oidDigestAlgSHA512):
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported digest algorithm %s", oid)
+	}
+}
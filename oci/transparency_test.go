@@ -0,0 +1,446 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/gomega"
+)
+
+// testTSA is a timestamp authority issuing RFC3161 timestamps.
+type testTSA struct {
+	key       *ecdsa.PrivateKey
+	cert      *x509.Certificate
+	chainPEM  []byte
+	serial    int64
+	embedCert bool
+}
+
+// newTestCertificate returns a certificate for the key, signed by the parent
+// certificate and key, or self-signed if the parent is nil.
+func newTestCertificate(t *testing.T, tmpl *x509.Certificate, key *ecdsa.PrivateKey,
+	parent *x509.Certificate, parentKey *ecdsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+	g := NewWithT(t)
+
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	g.Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	g.Expect(err).NotTo(HaveOccurred())
+	return cert
+}
+
+func newTestTSA(t *testing.T) *testTSA {
+	t.Helper()
+	g := NewWithT(t)
+
+	now := time.Now()
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	root := newTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-tsa-root"},
+		NotBefore:             now.Add(-24 * time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, rootKey, nil, nil)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	cert := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-tsa"},
+		NotBefore:    now.Add(-24 * time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}, key, root, rootKey)
+
+	chainPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	chainPEM = append(chainPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw})...)
+	return &testTSA{key: key, cert: cert, chainPEM: chainPEM, embedCert: true}
+}
+
+// timestamp returns the DER encoded RFC3161 timestamp response of the
+// given message at the given time.
+func (tsa *testTSA) timestamp(t *testing.T, message []byte, genTime time.Time) []byte {
+	t.Helper()
+	g := NewWithT(t)
+	tsa.serial++
+
+	imprint := sha256.Sum256(message)
+	var info tstInfo
+	info.Version = 1
+	info.Policy = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 2}
+	info.MessageImprint.HashAlgorithm = pkix.AlgorithmIdentifier{Algorithm: oidDigestAlgSHA256}
+	info.MessageImprint.HashedMessage = imprint[:]
+	info.SerialNumber = big.NewInt(tsa.serial)
+	info.GenTime = genTime.UTC().Truncate(time.Second)
+	info.Nonce = big.NewInt(42)
+	content, err := asn1.Marshal(info)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	marshalSet := func(v any) asn1.RawValue {
+		der, err := asn1.Marshal(v)
+		g.Expect(err).NotTo(HaveOccurred())
+		return asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: der}
+	}
+	contentDigest := sha256.Sum256(content)
+	signedAttrs, err := asn1.MarshalWithParams([]attribute{
+		{Type: oidAttrContentType, Values: marshalSet(oidTSTInfo)},
+		{Type: oidAttrMessageDigest, Values: marshalSet(contentDigest[:])},
+	}, "set")
+	g.Expect(err).NotTo(HaveOccurred())
+	signedAttrsDigest := sha256.Sum256(signedAttrs)
+	sig, err := ecdsa.SignASN1(rand.Reader, tsa.key, signedAttrsDigest[:])
+	g.Expect(err).NotTo(HaveOccurred())
+	// The signed attributes are encoded with their implicit tag.
+	signedAttrs[0] = 0xa0
+
+	sid, err := asn1.Marshal(issuerAndSerialNumber{
+		Issuer:       asn1.RawValue{FullBytes: tsa.cert.RawIssuer},
+		SerialNumber: tsa.cert.SerialNumber,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	digestAlgorithms, err := asn1.MarshalWithParams([]pkix.AlgorithmIdentifier{{Algorithm: oidDigestAlgSHA256}}, "set")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	var sd signedData
+	sd.Version = 3
+	sd.DigestAlgorithms = asn1.RawValue{FullBytes: digestAlgorithms}
+	sd.EncapContentInfo.EContentType = oidTSTInfo
+	sd.EncapContentInfo.EContent = content
+	if tsa.embedCert {
+		sd.Certificates = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: tsa.cert.Raw}
+	}
+	sd.SignerInfos = []signerInfo{{
+		Version:            1,
+		SID:                asn1.RawValue{FullBytes: sid},
+		DigestAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: oidDigestAlgSHA256},
+		SignedAttrs:        asn1.RawValue{FullBytes: signedAttrs},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		Signature:          sig,
+	}}
+	sdDER, err := asn1.Marshal(sd)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ciDER, err := asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: sdDER},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	var resp timeStampResp
+	resp.TimeStampToken = asn1.RawValue{FullBytes: ciDER}
+	der, err := asn1.Marshal(resp)
+	g.Expect(err).NotTo(HaveOccurred())
+	return der
+}
+
+// timestampAnnotation returns the cosign annotation of the given timestamp.
+func timestampAnnotation(t *testing.T, resp []byte) string {
+	t.Helper()
+	b, err := json.Marshal(map[string][]byte{"SignedRFC3161Timestamp": resp})
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+	return string(b)
+}
+
+// rekorAnnotation returns the cosign annotation of a Rekor bundle, signed
+// with the given key, recording the signature of the payload.
+func rekorAnnotation(t *testing.T, key *ecdsa.PrivateKey, payload, sig []byte, integratedTime time.Time) string {
+	t.Helper()
+	g := NewWithT(t)
+
+	digest := sha256.Sum256(payload)
+	body, err := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data": map[string]any{
+				"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(digest[:])},
+			},
+			"signature": map[string]any{"content": base64.StdEncoding.EncodeToString(sig)},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	var bundle rekorBundle
+	bundle.Payload.Body = base64.StdEncoding.EncodeToString(body)
+	bundle.Payload.IntegratedTime = integratedTime.Unix()
+	bundle.Payload.LogIndex = 7
+	bundle.Payload.LogID = "c0d23d6ad406973f9559f3ba2d1ca01f84147d8ffc5b8445c224f98b9591801d"
+	canonical, err := json.Marshal(map[string]any{
+		"body":           bundle.Payload.Body,
+		"integratedTime": bundle.Payload.IntegratedTime,
+		"logIndex":       bundle.Payload.LogIndex,
+		"logID":          bundle.Payload.LogID,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	setDigest := sha256.Sum256(canonical)
+	bundle.SignedEntryTimestamp, err = ecdsa.SignASN1(rand.Reader, key, setDigest[:])
+	g.Expect(err).NotTo(HaveOccurred())
+
+	b, err := json.Marshal(bundle)
+	g.Expect(err).NotTo(HaveOccurred())
+	return string(b)
+}
+
+// pushSignature signs the artifact at the given URL with the key, and pushes
+// the signature with the annotations returned for the payload and signature.
+func pushSignature(t *testing.T, c *Client, url string, key *ecdsa.PrivateKey,
+	annotations func(payload, sig []byte) map[string]string) {
+	t.Helper()
+	g := NewWithT(t)
+
+	ref, err := name.ParseReference(url)
+	g.Expect(err).NotTo(HaveOccurred())
+	d, err := crane.Digest(url, c.options...)
+	g.Expect(err).NotTo(HaveOccurred())
+	digest, err := gcrv1.NewHash(d)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	payload, err := json.Marshal(newCosignPayload(ref.Context(), digest))
+	g.Expect(err).NotTo(HaveOccurred())
+	payloadDigest := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, payloadDigest[:])
+	g.Expect(err).NotTo(HaveOccurred())
+
+	layerAnnotations := annotations(payload, sig)
+	layerAnnotations[CosignSignatureAnnotation] = base64.StdEncoding.EncodeToString(sig)
+
+	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, types.OCIConfigJSON)
+	img, err = mutate.Append(img, mutate.Addendum{
+		Layer:       static.NewLayer(payload, CosignSignatureMediaType),
+		Annotations: layerAnnotations,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(crane.Push(img, cosignSignatureTag(ref.Context(), digest).String(), c.options...)).To(Succeed())
+}
+
+func Test_VerifyArtifact_Transparency(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+	verifier, err := NewVerifierFromKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	rekorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+	rekorDER, err := x509.MarshalPKIXPublicKey(&rekorKey.PublicKey)
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+	rekorPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rekorDER})
+
+	tsa := newTestTSA(t)
+	otherTSA := newTestTSA(t)
+	unembeddedTSA := newTestTSA(t)
+	unembeddedTSA.embedCert = false
+
+	// The certificate of the signing key expired an hour ago, and was
+	// valid two hours ago when the signature was timestamped.
+	now := time.Now()
+	signedAt := now.Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	expiredCert := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "test-signer"},
+		NotBefore:    now.Add(-3 * time.Hour),
+		NotAfter:     now.Add(-time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}, key, nil, nil)
+	expiredCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: expiredCert.Raw}))
+
+	tests := []struct {
+		name             string
+		annotations      func(t *testing.T, payload, sig []byte) map[string]string
+		opts             []VerifyOption
+		wantErr          string
+		wantTransparency TransparencySource
+		wantSignedAt     time.Time
+	}{
+		{
+			name: "no transparency",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{}
+			},
+			wantTransparency: TransparencyNone,
+		},
+		{
+			name: "expired certificate without timestamp",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{CosignCertificateAnnotation: expiredCertPEM}
+			},
+			wantErr: "certificate is not valid at signing time",
+		},
+		{
+			name: "TSA timestamp with expired certificate",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{
+					CosignCertificateAnnotation:      expiredCertPEM,
+					CosignRFC3161TimestampAnnotation: timestampAnnotation(t, tsa.timestamp(t, sig, signedAt)),
+				}
+			},
+			opts:             []VerifyOption{WithTSACertChain(tsa.chainPEM)},
+			wantTransparency: TransparencyTSA,
+			wantSignedAt:     signedAt,
+		},
+		{
+			name: "TSA certificate from the chain",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{
+					CosignRFC3161TimestampAnnotation: timestampAnnotation(t, unembeddedTSA.timestamp(t, sig, signedAt)),
+				}
+			},
+			opts:             []VerifyOption{WithTSACertChain(unembeddedTSA.chainPEM)},
+			wantTransparency: TransparencyTSA,
+			wantSignedAt:     signedAt,
+		},
+		{
+			name: "timestamp after the certificate expiry",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{
+					CosignCertificateAnnotation:      expiredCertPEM,
+					CosignRFC3161TimestampAnnotation: timestampAnnotation(t, tsa.timestamp(t, sig, now)),
+				}
+			},
+			opts:    []VerifyOption{WithTSACertChain(tsa.chainPEM)},
+			wantErr: "certificate is not valid at signing time",
+		},
+		{
+			name: "missing timestamp",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{}
+			},
+			opts:    []VerifyOption{WithTSACertChain(tsa.chainPEM)},
+			wantErr: "no RFC3161 timestamp found",
+		},
+		{
+			name: "timestamp of another TSA",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{
+					CosignRFC3161TimestampAnnotation: timestampAnnotation(t, otherTSA.timestamp(t, sig, signedAt)),
+				}
+			},
+			opts:    []VerifyOption{WithTSACertChain(tsa.chainPEM)},
+			wantErr: "TSA certificate verification failed",
+		},
+		{
+			name: "timestamp of another signature",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{
+					CosignRFC3161TimestampAnnotation: timestampAnnotation(t, tsa.timestamp(t, payload, signedAt)),
+				}
+			},
+			opts:    []VerifyOption{WithTSACertChain(tsa.chainPEM)},
+			wantErr: "message imprint does not match the signature",
+		},
+		{
+			name: "Rekor bundle with expired certificate",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{
+					CosignCertificateAnnotation: expiredCertPEM,
+					CosignBundleAnnotation:      rekorAnnotation(t, rekorKey, payload, sig, signedAt),
+				}
+			},
+			opts:             []VerifyOption{WithRekorPublicKey(rekorPEM)},
+			wantTransparency: TransparencyRekor,
+			wantSignedAt:     signedAt,
+		},
+		{
+			name: "Rekor bundle of another signature",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{
+					CosignBundleAnnotation: rekorAnnotation(t, rekorKey, payload, []byte("other"), signedAt),
+				}
+			},
+			opts:    []VerifyOption{WithRekorPublicKey(rekorPEM)},
+			wantErr: "entry does not match the signature",
+		},
+		{
+			name: "Rekor bundle signed with another key",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{
+					CosignBundleAnnotation: rekorAnnotation(t, key, payload, sig, signedAt),
+				}
+			},
+			opts:    []VerifyOption{WithRekorPublicKey(rekorPEM)},
+			wantErr: "signed entry timestamp: invalid signature",
+		},
+		{
+			name: "invalid TSA certificate chain",
+			annotations: func(t *testing.T, payload, sig []byte) map[string]string {
+				return map[string]string{}
+			},
+			opts:    []VerifyOption{WithTSACertChain([]byte("foo"))},
+			wantErr: "no certificates found",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			url := pushRandomArtifact(t, c, fmt.Sprintf("test-transparency-%d", i))
+			pushSignature(t, c, url, key, func(payload, sig []byte) map[string]string {
+				return tt.annotations(t, payload, sig)
+			})
+
+			result, err := c.VerifyArtifact(ctx, url, verifier, tt.opts...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.Transparency).To(Equal(tt.wantTransparency))
+			if !tt.wantSignedAt.IsZero() {
+				g.Expect(result.SignedAt).To(BeTemporally("==", tt.wantSignedAt))
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/pflag"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+	kubeflowcontrol "k8s.io/client-go/util/flowcontrol"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/cli-utils/pkg/flowcontrol"
+)
+
+// PriorityLevelHeader is the request header identifying the priority level
+// of the call category of a request, e.g. for matching the requests in the
+// audit policy or in an API proxy.
+const PriorityLevelHeader = "X-Flux-Priority-Level"
+
+// CallCategory is the category of a request sent to the Kubernetes API.
+type CallCategory string
+
+const (
+	// CallCategoryRead is the category of the get, list and watch requests.
+	CallCategoryRead CallCategory = "reads"
+	// CallCategoryWrite is the category of the create, update, patch and
+	// delete requests, except for the requests to the status subresource.
+	CallCategoryWrite CallCategory = "writes"
+	// CallCategoryStatus is the category of the update and patch requests
+	// to the status subresource.
+	CallCategoryStatus CallCategory = "status"
+)
+
+// CallCategoryOptions contains the configuration of the requests of a
+// CallCategory. The zero value leaves the requests unchanged.
+type CallCategoryOptions struct {
+	// UserAgentSuffix is appended to the user agent of the requests.
+	UserAgentSuffix string
+
+	// PriorityLevel is the value of the PriorityLevelHeader of the requests.
+	PriorityLevel string
+
+	// QPS indicates the maximum queries-per-second of the requests, in
+	// addition to the limit of all the requests. Zero disables the limit.
+	QPS float32
+
+	// Burst indicates the maximum burst queries-per-second of the requests.
+	// Defaults to the QPS rounded up.
+	Burst int
+}
+
+// CallCategoriesOptions contains the configuration of the requests sent to
+// the Kubernetes API per CallCategory.
+//
+// The struct can be bound to the main flag set next to the Options, and used
+// to get a rest.Config with GetCategorizedConfigOrDie:
+//
+//	clientOptions.BindFlags(flag.CommandLine)
+//	categoriesOptions.BindFlags(flag.CommandLine)
+//	flag.Parse()
+//
+//	restConfig := client.GetCategorizedConfigOrDie(clientOptions, categoriesOptions)
+type CallCategoriesOptions struct {
+	// Reads configures the requests of the CallCategoryRead.
+	Reads CallCategoryOptions
+
+	// Writes configures the requests of the CallCategoryWrite.
+	Writes CallCategoryOptions
+
+	// Status configures the requests of the CallCategoryStatus.
+	Status CallCategoryOptions
+}
+
+// BindFlags will parse the given pflag.FlagSet for the call category flags,
+// e.g. --kube-api-status-qps, and set the CallCategoriesOptions accordingly.
+func (o *CallCategoriesOptions) BindFlags(fs *pflag.FlagSet) {
+	for category, opts := range map[CallCategory]*CallCategoryOptions{
+		CallCategoryRead:   &o.Reads,
+		CallCategoryWrite:  &o.Writes,
+		CallCategoryStatus: &o.Status,
+	} {
+		fs.StringVar(&opts.UserAgentSuffix, fmt.Sprintf("kube-api-%s-user-agent-suffix", category), "",
+			fmt.Sprintf("The suffix appended to the user agent of the %s requests sent to the Kubernetes API.", category))
+		fs.StringVar(&opts.PriorityLevel, fmt.Sprintf("kube-api-%s-priority-level", category), "",
+			fmt.Sprintf("The value of the %s header of the %s requests sent to the Kubernetes API.", PriorityLevelHeader, category))
+		fs.Float32Var(&opts.QPS, fmt.Sprintf("kube-api-%s-qps", category), 0,
+			fmt.Sprintf("The maximum queries-per-second of the %s requests sent to the Kubernetes API, "+
+				"ignored when the API server has Priority and Fairness enabled. Zero disables the limit.", category))
+		fs.IntVar(&opts.Burst, fmt.Sprintf("kube-api-%s-burst", category), 0,
+			fmt.Sprintf("The maximum burst queries-per-second of the %s requests sent to the Kubernetes API.", category))
+	}
+}
+
+// get returns the options of the given category.
+func (o CallCategoriesOptions) get(category CallCategory) CallCategoryOptions {
+	switch category {
+	case CallCategoryStatus:
+		return o.Status
+	case CallCategoryWrite:
+		return o.Writes
+	default:
+		return o.Reads
+	}
+}
+
+// GetCategorizedConfigOrDie returns the rest.Config of GetConfigOrDie with a
+// transport applying the CallCategoriesOptions to each request, according to
+// its CallCategory. Like the limits of the Options, the per-category limits
+// are disabled when the Kubernetes apiserver has the PriorityAndFairness flow
+// control filter enabled, in which case the requests are only tagged with
+// their priority level and user agent suffix.
+func GetCategorizedConfigOrDie(opts Options, categoriesOpts CallCategoriesOptions) *rest.Config {
+	return categorizedConfig(ctrl.GetConfigOrDie(), opts, categoriesOpts, flowcontrol.IsEnabled)
+}
+
+func categorizedConfig(config *rest.Config, opts Options, categoriesOpts CallCategoriesOptions,
+	flowcontrolChecker func(context.Context, *rest.Config) (bool, error)) *rest.Config {
+	enabled, err := flowcontrolChecker(context.Background(), config)
+	rateLimit := err != nil || !enabled
+	if rateLimit {
+		config.QPS = opts.QPS
+		config.Burst = opts.Burst
+	} else {
+		// A negative QPS indicates that the client should not have a rate limiter.
+		config.QPS = -1
+		config.Burst = -1
+	}
+
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newCategorizedRoundTripper(rt, categoriesOpts, rateLimit)
+	})
+	return config
+}
+
+// RequestCallCategory returns the CallCategory of the given request.
+func RequestCallCategory(req *http.Request) CallCategory {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return CallCategoryRead
+	}
+	if strings.HasSuffix(strings.TrimSuffix(req.URL.Path, "/"), "/status") {
+		return CallCategoryStatus
+	}
+	return CallCategoryWrite
+}
+
+// categorizedRoundTripper applies the CallCategoriesOptions to the requests.
+type categorizedRoundTripper struct {
+	next     http.RoundTripper
+	opts     CallCategoriesOptions
+	limiters map[CallCategory]kubeflowcontrol.RateLimiter
+}
+
+var _ utilnet.RoundTripperWrapper = &categorizedRoundTripper{}
+
+func newCategorizedRoundTripper(next http.RoundTripper, opts CallCategoriesOptions, rateLimit bool) *categorizedRoundTripper {
+	rt := &categorizedRoundTripper{
+		next:     next,
+		opts:     opts,
+		limiters: make(map[CallCategory]kubeflowcontrol.RateLimiter),
+	}
+	if !rateLimit {
+		return rt
+	}
+	for _, category := range []CallCategory{CallCategoryRead, CallCategoryWrite, CallCategoryStatus} {
+		o := opts.get(category)
+		if o.QPS <= 0 {
+			continue
+		}
+		burst := o.Burst
+		if burst <= 0 {
+			burst = int(o.QPS + 0.999)
+		}
+		rt.limiters[category] = kubeflowcontrol.NewTokenBucketRateLimiter(o.QPS, burst)
+	}
+	return rt
+}
+
+// RoundTrip waits for the rate limiter of the category of the request, if
+// any, and sends the request with the user agent suffix and priority level
+// of the category.
+func (rt *categorizedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	category := RequestCallCategory(req)
+	if limiter, ok := rt.limiters[category]; ok {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("client rate limiter for %s requests: %w", category, err)
+		}
+	}
+
+	opts := rt.opts.get(category)
+	if opts.UserAgentSuffix == "" && opts.PriorityLevel == "" {
+		return rt.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	if opts.UserAgentSuffix != "" {
+		userAgent := req.Header.Get("User-Agent")
+		if userAgent == "" {
+			userAgent = rest.DefaultKubernetesUserAgent()
+		}
+		req.Header.Set("User-Agent", userAgent+" "+opts.UserAgentSuffix)
+	}
+	if opts.PriorityLevel != "" {
+		req.Header.Set(PriorityLevelHeader, opts.PriorityLevel)
+	}
+	return rt.next.RoundTrip(req)
+}
+
+// WrappedRoundTripper returns the wrapped round tripper.
+func (rt *categorizedRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.next
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+// recordingRoundTripper records the requests and responds with an empty body.
+type recordingRoundTripper struct {
+	requests []*http.Request
+}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestGetCategorizedConfig(t *testing.T) {
+	categoriesOpts := CallCategoriesOptions{
+		Reads: CallCategoryOptions{
+			UserAgentSuffix: "reads",
+			PriorityLevel:   "workload-low",
+		},
+		Writes: CallCategoryOptions{
+			UserAgentSuffix: "writes",
+			PriorityLevel:   "workload-high",
+		},
+		Status: CallCategoryOptions{
+			PriorityLevel: "global-default",
+		},
+	}
+
+	tests := []struct {
+		description       string
+		method            string
+		path              string
+		opts              CallCategoriesOptions
+		expectedCategory  CallCategory
+		expectedUserAgent string
+		expectedPriority  string
+	}{
+		{
+			description:       "get requests are reads",
+			method:            http.MethodGet,
+			path:              "/api/v1/namespaces/default/configmaps/test",
+			opts:              categoriesOpts,
+			expectedCategory:  CallCategoryRead,
+			expectedUserAgent: "controller/v1 reads",
+			expectedPriority:  "workload-low",
+		},
+		{
+			description:       "get requests to the status subresource are reads",
+			method:            http.MethodGet,
+			path:              "/apis/apps/v1/namespaces/default/deployments/test/status",
+			opts:              categoriesOpts,
+			expectedCategory:  CallCategoryRead,
+			expectedUserAgent: "controller/v1 reads",
+			expectedPriority:  "workload-low",
+		},
+		{
+			description:       "patch requests are writes",
+			method:            http.MethodPatch,
+			path:              "/api/v1/namespaces/default/configmaps/test",
+			opts:              categoriesOpts,
+			expectedCategory:  CallCategoryWrite,
+			expectedUserAgent: "controller/v1 writes",
+			expectedPriority:  "workload-high",
+		},
+		{
+			description:       "delete requests are writes",
+			method:            http.MethodDelete,
+			path:              "/api/v1/namespaces/default/configmaps/test",
+			opts:              categoriesOpts,
+			expectedCategory:  CallCategoryWrite,
+			expectedUserAgent: "controller/v1 writes",
+			expectedPriority:  "workload-high",
+		},
+		{
+			description:       "put requests to the status subresource are status",
+			method:            http.MethodPut,
+			path:              "/apis/apps/v1/namespaces/default/deployments/test/status",
+			opts:              categoriesOpts,
+			expectedCategory:  CallCategoryStatus,
+			expectedUserAgent: "controller/v1",
+			expectedPriority:  "global-default",
+		},
+		{
+			description:       "patch requests to the status subresource are status",
+			method:            http.MethodPatch,
+			path:              "/apis/apps/v1/namespaces/default/deployments/test/status",
+			opts:              categoriesOpts,
+			expectedCategory:  CallCategoryStatus,
+			expectedUserAgent: "controller/v1",
+			expectedPriority:  "global-default",
+		},
+		{
+			description:       "requests are unchanged by default",
+			method:            http.MethodPatch,
+			path:              "/api/v1/namespaces/default/configmaps/test",
+			expectedCategory:  CallCategoryWrite,
+			expectedUserAgent: "controller/v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			recorder := &recordingRoundTripper{}
+			config := &rest.Config{Host: "https://example.com", UserAgent: "controller/v1", Transport: recorder}
+			config = categorizedConfig(config, Options{QPS: 50, Burst: 300}, tt.opts,
+				func(context.Context, *rest.Config) (bool, error) { return false, nil })
+
+			client, err := rest.HTTPClientFor(config)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(tt.method, config.Host+tt.path, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCategory, RequestCallCategory(req))
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			assert.NoError(t, resp.Body.Close())
+
+			assert.Len(t, recorder.requests, 1)
+			got := recorder.requests[0]
+			assert.Equal(t, tt.expectedUserAgent, got.Header.Get("User-Agent"))
+			assert.Equal(t, tt.expectedPriority, got.Header.Get(PriorityLevelHeader))
+			_, ok := got.Header[PriorityLevelHeader]
+			assert.Equal(t, tt.expectedPriority != "", ok)
+		})
+	}
+}
+
+func TestGetCategorizedConfig_FlowControl(t *testing.T) {
+	tests := []struct {
+		description        string
+		flowControlEnabled bool
+		expectedQPS        float32
+		expectedBurst      int
+		expectedLimiters   int
+	}{
+		{
+			description:      "per-category limits when flow control is disabled",
+			expectedQPS:      50,
+			expectedBurst:    300,
+			expectedLimiters: 2,
+		},
+		{
+			description:        "no limits when flow control is enabled",
+			flowControlEnabled: true,
+			expectedQPS:        -1,
+			expectedBurst:      -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			recorder := &recordingRoundTripper{}
+			config := &rest.Config{Host: "https://example.com", Transport: recorder}
+			config = categorizedConfig(config, Options{QPS: 50, Burst: 300}, CallCategoriesOptions{
+				Writes: CallCategoryOptions{QPS: 10},
+				Status: CallCategoryOptions{QPS: 5, Burst: 10, PriorityLevel: "global-default"},
+			}, func(context.Context, *rest.Config) (bool, error) { return tt.flowControlEnabled, nil })
+
+			assert.Equal(t, tt.expectedQPS, config.QPS)
+			assert.Equal(t, tt.expectedBurst, config.Burst)
+
+			rt, ok := config.WrapTransport(recorder).(*categorizedRoundTripper)
+			assert.True(t, ok)
+			assert.Len(t, rt.limiters, tt.expectedLimiters)
+			if tt.expectedLimiters > 0 {
+				assert.Equal(t, float32(10), rt.limiters[CallCategoryWrite].QPS())
+				assert.Equal(t, float32(5), rt.limiters[CallCategoryStatus].QPS())
+			}
+		})
+	}
+}
+
+func TestCallCategoriesOptions_BindFlags(t *testing.T) {
+	var opts CallCategoriesOptions
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.BindFlags(fs)
+	assert.Equal(t, CallCategoriesOptions{}, opts)
+
+	err := fs.Parse([]string{
+		"--kube-api-reads-user-agent-suffix=reads",
+		"--kube-api-writes-priority-level=workload-high",
+		"--kube-api-status-qps=5",
+		"--kube-api-status-burst=10",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, CallCategoriesOptions{
+		Reads:  CallCategoryOptions{UserAgentSuffix: "reads"},
+		Writes: CallCategoryOptions{PriorityLevel: "workload-high"},
+		Status: CallCategoryOptions{QPS: 5, Burst: 10},
+	}, opts)
+}
@@ -239,11 +239,11 @@ func (i *Impersonator) newStatusPoller(reader rc.Reader, restMapper apimeta.REST
 		opts = *i.pollingOpts
 	}
 
-	pollingReaders := append(i.pollingReaders, statusreaders.NewCustomJobStatusReader)
-	for _, ctor := range pollingReaders {
+	for _, ctor := range i.pollingReaders {
 		sr := ctor(restMapper)
 		opts.CustomStatusReaders = append(opts.CustomStatusReaders, sr)
 	}
+	opts.CustomStatusReaders = append(opts.CustomStatusReaders, statusreaders.NewCustomStatusReaders(restMapper)...)
 
 	return polling.NewStatusPoller(reader, restMapper, opts)
 }
@@ -63,7 +63,8 @@ type Checker struct {
 }
 
 // NewChecker constructs and returns a new reconciled status Checker for a
-// controller.
+// controller. If condns has no negative polarity conditions, the polarities
+// registered with conditions.RegisterPolarity are used instead.
 func NewChecker(cli client.Client, condns *Conditions) *Checker {
 	warnChecks := []checkFunc{
 		check_WARN0001,
@@ -149,28 +150,47 @@ func (c Checker) CheckErr(ctx context.Context, obj conditions.Getter) {
 
 // Check performs all the warn and fail checks and returns the results.
 func (c Checker) Check(ctx context.Context, obj conditions.Getter) (fail, warn error) {
-	if c.requireConditions && c.conditions == nil {
-		return fmt.Errorf("no conditions context provided"), nil
-	}
 	// Fetch the latest version of the object.
 	if !c.DisableFetch {
 		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
 			return err, nil
 		}
 	}
+	condns := c.conditionsFor(obj)
+	if c.requireConditions && condns == nil {
+		return fmt.Errorf("no conditions context provided"), nil
+	}
 	warnErrs := []error{}
 	for _, check := range c.warnChecks {
-		if err := check(ctx, obj, c.conditions); err != nil {
+		if err := check(ctx, obj, condns); err != nil {
 			warnErrs = append(warnErrs, err)
 		}
 	}
 	warn = kerrors.NewAggregate(warnErrs)
 	failErr := []error{}
 	for _, check := range c.failChecks {
-		if err := check(ctx, obj, c.conditions); err != nil {
+		if err := check(ctx, obj, condns); err != nil {
 			failErr = append(failErr, err)
 		}
 	}
 	fail = kerrors.NewAggregate(failErr)
 	return fail, warn
 }
+
+// conditionsFor returns the conditions context to check the given object
+// with. If no negative polarity conditions are explicitly given, the
+// registered polarities and the ones defined by the object are used.
+func (c Checker) conditionsFor(obj conditions.Getter) *Conditions {
+	if c.conditions != nil && len(c.conditions.NegativePolarity) > 0 {
+		return c.conditions
+	}
+	negative := conditions.NegativePolarityConditions(obj)
+	if len(negative) == 0 {
+		return c.conditions
+	}
+	condns := &Conditions{NegativePolarity: negative}
+	if c.conditions != nil {
+		condns.PositivePolarity = c.conditions.PositivePolarity
+	}
+	return condns
+}
@@ -79,3 +79,49 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestCheck_RegisteredPolarity(t *testing.T) {
+	conditions.RegisterPolarity("CheckerTestDegraded", conditions.NegativePolarity)
+
+	tests := []struct {
+		name     string
+		condns   *Conditions
+		wantWarn string
+	}{
+		{
+			name:     "registered polarity without conditions context",
+			wantWarn: "Negative polarity condition present when Ready condition is True: [CheckerTestDegraded]",
+		},
+		{
+			name:     "registered polarity without negative polarity conditions",
+			condns:   &Conditions{PositivePolarity: []string{meta.ReadyCondition}},
+			wantWarn: "Negative polarity condition present when Ready condition is True: [CheckerTestDegraded]",
+		},
+		{
+			name:   "explicit negative polarity conditions take precedence",
+			condns: &Conditions{NegativePolarity: []string{"CheckerTestOther"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &testdata.Fake{}
+			obj.Generation = 1
+			obj.Status.ObservedGeneration = 1
+			conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "Ready")
+			conditions.MarkTrue(obj, "CheckerTestDegraded", "Rsn", "Msg")
+
+			checker := NewChecker(fakeclient.NewClientBuilder().Build(), tt.condns)
+			checker.DisableFetch = true
+
+			_, warn := checker.Check(context.TODO(), obj)
+			if tt.wantWarn == "" {
+				g.Expect(warn).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(warn).To(MatchError(ContainSubstring(tt.wantWarn)))
+		})
+	}
+}
@@ -42,6 +42,11 @@ limitations under the License.
 //	    conditions := &check.Conditions{NegativePolarity: []string{"TestCondition1", "TestCondition2"}}
 //	    checker := check.NewChecker(client, conditions)
 //
+//	    // Alternatively, if the controller registers the polarity of its
+//	    // conditions with conditions.RegisterPolarity, no conditions context
+//	    // is needed.
+//	    checker = check.NewChecker(client, nil)
+//
 //	    // Check object status.
 //	    checker.CheckErr(context.TODO(), obj)
 //	}
@@ -143,6 +143,7 @@ func check_FAIL0007(ctx context.Context, obj conditions.Getter, condns *Conditio
 // NOTE: This is only true for full reconciliation status. This is not true
 // during mid-reconciliation status. Ready can be of any value when any of the
 // status condition's ObservedGeneration is less than the object Generation.
+// The conditions set with conditions.SetWithObservedGeneration are ignored.
 func check_FAIL0008(ctx context.Context, obj conditions.Getter, condns *Conditions) error {
 	if !conditions.IsReady(obj) {
 		return nil
@@ -151,7 +152,7 @@ func check_FAIL0008(ctx context.Context, obj conditions.Getter, condns *Conditio
 	// Collect problematic conditions with ObservedGeneration != object Generation.
 	probConditions := []string{}
 	for _, c := range obj.GetConditions() {
-		if c.ObservedGeneration < objectGen {
+		if c.ObservedGeneration < objectGen && !conditions.HasExplicitObservedGeneration(obj, c.Type) {
 			probConditions = append(probConditions, c.Type)
 		}
 	}
@@ -166,6 +167,7 @@ func check_FAIL0008(ctx context.Context, obj conditions.Getter, condns *Conditio
 // NOTE: This is only true for full reconciliation status. This is not true
 // during mid-reconciliation patching. Reconciling condition can be updated with
 // new observed generation while Ready=True in the previous generation.
+// The conditions set with conditions.SetWithObservedGeneration are ignored.
 func check_FAIL0009(ctx context.Context, obj conditions.Getter, condns *Conditions) error {
 	if !conditions.IsReady(obj) {
 		return nil
@@ -177,7 +179,7 @@ func check_FAIL0009(ctx context.Context, obj conditions.Getter, condns *Conditio
 	// Collect the problematic conditions with ObservedGeneration != og.
 	probConditions := []string{}
 	for _, c := range obj.GetConditions() {
-		if c.ObservedGeneration != og {
+		if c.ObservedGeneration != og && !conditions.HasExplicitObservedGeneration(obj, c.Type) {
 			probConditions = append(probConditions, c.Type)
 		}
 	}
@@ -402,6 +402,7 @@ func Test_check_FAIL0008(t *testing.T) {
 	tests := []struct {
 		name             string
 		objectGeneration int64
+		annotations      map[string]string
 		conditions       []metav1.Condition
 		wantErr          bool
 		errCheck         func(t *WithT, err error)
@@ -459,6 +460,35 @@ func Test_check_FAIL0008(t *testing.T) {
 				t.Expect(err.Error()).To(ContainSubstring("TestCondition2"))
 			},
 		},
+		{
+			name:             "explicit conditions ObservedGeneration < object Generation, Ready True",
+			objectGeneration: 3,
+			annotations: map[string]string{
+				conditions.ExplicitObservedGenerationAnnotation: "TestCondition1",
+			},
+			conditions: []metav1.Condition{
+				{
+					Type:               meta.ReadyCondition,
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 3,
+				},
+				{
+					Type:               "TestCondition1",
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 1,
+				},
+				{
+					Type:               "TestCondition2",
+					Status:             metav1.ConditionFalse,
+					ObservedGeneration: 2,
+				},
+			},
+			wantErr: true,
+			errCheck: func(t *WithT, err error) {
+				t.Expect(err.Error()).ToNot(ContainSubstring("TestCondition1"))
+				t.Expect(err.Error()).To(ContainSubstring("TestCondition2"))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -466,6 +496,7 @@ func Test_check_FAIL0008(t *testing.T) {
 			g := NewWithT(t)
 			obj := &testdata.Fake{}
 			obj.SetGeneration(tt.objectGeneration)
+			obj.SetAnnotations(tt.annotations)
 			obj.SetConditions(tt.conditions)
 
 			err := check_FAIL0008(context.TODO(), obj, nil)
@@ -482,6 +513,7 @@ func Test_check_FAIL0009(t *testing.T) {
 	tests := []struct {
 		name                   string
 		rootObservedGeneration int64
+		annotations            map[string]string
 		conditions             []metav1.Condition
 		wantErr                bool
 		errCheck               func(t *WithT, err error)
@@ -539,6 +571,30 @@ func Test_check_FAIL0009(t *testing.T) {
 				t.Expect(err.Error()).To(ContainSubstring("TestCondition2"))
 			},
 		},
+		{
+			name:                   "explicit conditions ObservedGeneration != Root ObservedGeneration, Ready True",
+			rootObservedGeneration: 3,
+			annotations: map[string]string{
+				conditions.ExplicitObservedGenerationAnnotation: "TestCondition1,TestCondition2",
+			},
+			conditions: []metav1.Condition{
+				{
+					Type:               meta.ReadyCondition,
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 3,
+				},
+				{
+					Type:               "TestCondition1",
+					Status:             metav1.ConditionFalse,
+					ObservedGeneration: 7,
+				},
+				{
+					Type:               "TestCondition2",
+					Status:             metav1.ConditionFalse,
+					ObservedGeneration: 2,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -550,6 +606,7 @@ func Test_check_FAIL0009(t *testing.T) {
 
 			obj := &testdata.Fake{}
 			obj.Status.ObservedGeneration = tt.rootObservedGeneration
+			obj.SetAnnotations(tt.annotations)
 			obj.SetConditions(tt.conditions)
 
 			err := check_FAIL0009(context.TODO(), obj, nil)
@@ -24,17 +24,26 @@ the `metav1.Condition` type.
 package conditions
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/object"
 )
 
+// ExplicitObservedGenerationAnnotation is the annotation holding the comma-separated list of the condition types
+// whose ObservedGeneration was set with SetWithObservedGeneration, e.g. to the generation of a referenced object.
+const ExplicitObservedGenerationAnnotation = "conditions.fluxcd.io/explicit-observed-generation"
+
 // Getter interface defines methods that a Kubernetes resource object should implement in order to use the conditions
 // package for getting conditions.
 type Getter interface {
 	client.Object
-	meta.ObjectWithConditions
+	object.ObjectWithConditions
 }
 
 // Get returns the condition with the given type, if the condition does not exists, it returns nil.
@@ -154,6 +163,64 @@ func GetObservedGeneration(from Getter, t string) int64 {
 	return 0
 }
 
+// GetExplicitObservedGeneration returns the ObservedGeneration of the condition with the given type if it was set with
+// SetWithObservedGeneration, and true. Otherwise, it returns 0 and false.
+func GetExplicitObservedGeneration(from Getter, t string) (int64, bool) {
+	if !HasExplicitObservedGeneration(from, t) {
+		return 0, false
+	}
+	if c := Get(from, t); c != nil {
+		return c.ObservedGeneration, true
+	}
+	return 0, false
+}
+
+// HasExplicitObservedGeneration returns true if the condition with the given type was set with
+// SetWithObservedGeneration, i.e. its ObservedGeneration is not the generation of the object.
+func HasExplicitObservedGeneration(from Getter, t string) bool {
+	return slices.Contains(explicitObservedGenerationTypes(from), t)
+}
+
+// explicitObservedGenerationTypes returns the condition types recorded in the ExplicitObservedGenerationAnnotation
+// of the object.
+func explicitObservedGenerationTypes(from client.Object) []string {
+	v := from.GetAnnotations()[ExplicitObservedGenerationAnnotation]
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// GetForGeneration returns the condition with the given type if it was observed for the given generation or a later
+// one, otherwise it returns nil.
+func GetForGeneration(from Getter, t string, gen int64) *metav1.Condition {
+	if c := Get(from, t); c != nil && c.ObservedGeneration >= gen {
+		return c
+	}
+	return nil
+}
+
+// GetForCurrentGeneration returns the condition with the given type if it was observed for the current generation of
+// the object, otherwise it returns nil.
+func GetForCurrentGeneration(from Getter, t string) *metav1.Condition {
+	return GetForGeneration(from, t, from.GetGeneration())
+}
+
+// IsTrueForGeneration is true if the condition with the given type is True and was observed for the given generation
+// or a later one, otherwise it is false.
+func IsTrueForGeneration(from Getter, t string, gen int64) bool {
+	if c := GetForGeneration(from, t, gen); c != nil {
+		return c.Status == metav1.ConditionTrue
+	}
+	return false
+}
+
+// IsTrueForCurrentGeneration is true if the condition with the given type is True and was observed for the current
+// generation of the object, otherwise it is false.
+func IsTrueForCurrentGeneration(from Getter, t string) bool {
+	return IsTrueForGeneration(from, t, from.GetGeneration())
+}
+
 // summary returns a condition with the summary of all the conditions existing on an object. If the object does not have
 // other conditions, no summary condition is generated.
 func summary(from Getter, t string, options ...MergeOption) *metav1.Condition {
@@ -164,6 +231,12 @@ func summary(from Getter, t string, options ...MergeOption) *metav1.Condition {
 		o(mergeOpt)
 	}
 
+	// Fall back to the registered polarities if the negative polarity
+	// condition types are not explicitly given.
+	if len(mergeOpt.negativePolarityConditionTypes) == 0 {
+		mergeOpt.negativePolarityConditionTypes = NegativePolarityConditions(from, t)
+	}
+
 	// Identifies the conditions in scope for the Summary by taking all the existing conditions except t,
 	// or, if a list of conditions types is specified, only the conditions the condition in that list.
 	conditionsInScope := make([]localizedCondition, 0, len(conditions))
@@ -173,6 +246,10 @@ func summary(from Getter, t string, options ...MergeOption) *metav1.Condition {
 			continue
 		}
 
+		if mergeOpt.currentGenerationOnly && c.ObservedGeneration < from.GetGeneration() {
+			continue
+		}
+
 		if mergeOpt.conditionTypes != nil {
 			found := false
 			for _, tt := range mergeOpt.conditionTypes {
@@ -230,6 +307,8 @@ type mirrorOptions struct {
 	fallbackTo      *bool
 	fallbackReason  string
 	fallbackMessage string
+	sourcePrefix    bool
+	invertPolarity  bool
 }
 
 // MirrorOptions defines an option for mirroring conditions.
@@ -245,15 +324,51 @@ func WithFallbackValue(fallbackValue bool, reason string, message string) Mirror
 	}
 }
 
+// WithSourcePrefix prefixes the message of the mirrored condition with the identity of the source object, in the
+// format 'Kind/namespace/name: message'.
+func WithSourcePrefix() MirrorOptions {
+	return func(c *mirrorOptions) {
+		c.sourcePrefix = true
+	}
+}
+
+// WithInvertedPolarity inverts the status of the mirrored condition, e.g. for mirroring a negative polarity condition
+// of the source object into a positive polarity condition of the target object. The Unknown status and the fallback
+// value are not inverted.
+func WithInvertedPolarity() MirrorOptions {
+	return func(c *mirrorOptions) {
+		c.invertPolarity = true
+	}
+}
+
 // mirror mirrors the Ready condition from a dependent object into the target condition; if the Ready condition does not
 // exists in the source object, no target conditions is generated.
 func mirror(from Getter, targetCondition string, options ...MirrorOptions) *metav1.Condition {
+	return mirrorType(from, meta.ReadyCondition, targetCondition, options...)
+}
+
+// mirrorType mirrors the condition of the given type from a dependent object into the target condition; if the
+// condition does not exists in the source object, no target conditions is generated.
+func mirrorType(from Getter, sourceCondition, targetCondition string, options ...MirrorOptions) *metav1.Condition {
 	mirrorOpt := &mirrorOptions{}
 	for _, o := range options {
 		o(mirrorOpt)
 	}
 
-	condition := Get(from, meta.ReadyCondition)
+	condition := Get(from, sourceCondition)
+	if condition != nil {
+		if mirrorOpt.invertPolarity {
+			switch condition.Status {
+			case metav1.ConditionTrue:
+				condition.Status = metav1.ConditionFalse
+			case metav1.ConditionFalse:
+				condition.Status = metav1.ConditionTrue
+			}
+		}
+		if mirrorOpt.sourcePrefix {
+			condition.Message = fmt.Sprintf("%s: %s", sourceIdentity(from), condition.Message)
+		}
+	}
 
 	if mirrorOpt.fallbackTo != nil && condition == nil {
 		switch *mirrorOpt.fallbackTo {
@@ -271,6 +386,16 @@ func mirror(from Getter, targetCondition string, options ...MirrorOptions) *meta
 	return condition
 }
 
+// sourceIdentity returns the identity of the given object in the format 'Kind/namespace/name', or 'Kind/name' for
+// cluster-scoped objects.
+func sourceIdentity(from Getter) string {
+	kind := from.GetObjectKind().GroupVersionKind().Kind
+	if from.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", kind, from.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, from.GetNamespace(), from.GetName())
+}
+
 // aggregate the conditions from a list of depending objects into the target object; the condition scope can be set
 // using WithConditions; if none of the source objects have the conditions within the scope, no target condition is
 // generated.
@@ -282,6 +407,18 @@ func aggregate(from []Getter, targetCondition string, options ...MergeOption) *m
 		o(mergeOpt)
 	}
 
+	// Fall back to the registered polarities if the negative polarity
+	// condition types are not explicitly given.
+	if len(mergeOpt.negativePolarityConditionTypes) == 0 {
+		for i := range from {
+			for _, t := range NegativePolarityConditions(from[i], targetCondition) {
+				if !stringInSlice(mergeOpt.negativePolarityConditionTypes, t) {
+					mergeOpt.negativePolarityConditionTypes = append(mergeOpt.negativePolarityConditionTypes, t)
+				}
+			}
+		}
+	}
+
 	conditionsInScope := make([]localizedCondition, 0, len(from))
 	for i := range from {
 		conditions := from[i].GetConditions()
@@ -123,6 +123,47 @@ func TestIsMethods(t *testing.T) {
 	g.Expect(GetObservedGeneration(obj, "false2")).ToNot(BeZero())
 }
 
+func TestGenerationMethods(t *testing.T) {
+	g := NewWithT(t)
+
+	current := true1.DeepCopy()
+	current.ObservedGeneration = 3
+	lagging := true1.DeepCopy()
+	lagging.Type = "lagging"
+	lagging.ObservedGeneration = 2
+	ahead := false1.DeepCopy()
+	ahead.ObservedGeneration = 4
+
+	obj := getterWithConditions(current, lagging, ahead)
+	obj.SetGeneration(3)
+
+	// test GetForGeneration
+	g.Expect(GetForGeneration(obj, "nil1", 3)).To(BeNil())
+	g.Expect(GetForGeneration(obj, "true1", 3)).To(HaveSameStateOf(current))
+	g.Expect(GetForGeneration(obj, "lagging", 3)).To(BeNil())
+	g.Expect(GetForGeneration(obj, "lagging", 2)).To(HaveSameStateOf(lagging))
+	g.Expect(GetForGeneration(obj, "false1", 3)).To(HaveSameStateOf(ahead))
+
+	// test GetForCurrentGeneration
+	g.Expect(GetForCurrentGeneration(obj, "true1")).To(HaveSameStateOf(current))
+	g.Expect(GetForCurrentGeneration(obj, "lagging")).To(BeNil())
+
+	// test IsTrueForGeneration
+	g.Expect(IsTrueForGeneration(obj, "nil1", 3)).To(BeFalse())
+	g.Expect(IsTrueForGeneration(obj, "true1", 3)).To(BeTrue())
+	g.Expect(IsTrueForGeneration(obj, "true1", 4)).To(BeFalse())
+	g.Expect(IsTrueForGeneration(obj, "lagging", 3)).To(BeFalse())
+	g.Expect(IsTrueForGeneration(obj, "false1", 3)).To(BeFalse())
+
+	// test IsTrueForCurrentGeneration
+	g.Expect(IsTrueForCurrentGeneration(obj, "true1")).To(BeTrue())
+	g.Expect(IsTrueForCurrentGeneration(obj, "lagging")).To(BeFalse())
+
+	// the generation unaware methods are unchanged
+	g.Expect(IsTrue(obj, "lagging")).To(BeTrue())
+	g.Expect(Get(obj, "lagging")).To(HaveSameStateOf(lagging))
+}
+
 func TestIsReadyStalledReconciling(t *testing.T) {
 	g := NewWithT(t)
 
@@ -294,6 +335,64 @@ func TestSummary(t *testing.T) {
 	}
 }
 
+func TestSummary_WithCurrentGenerationOnly(t *testing.T) {
+	withGeneration := func(c *metav1.Condition, gen int64) *metav1.Condition {
+		c = c.DeepCopy()
+		c.ObservedGeneration = gen
+		return c
+	}
+	fooCurrent := withGeneration(TrueCondition("foo", "reason trueFoo", "message trueFoo"), 5)
+	barLagging := withGeneration(FalseCondition("bar", "reason falseBar", "message falseBar"), 4)
+	bazCurrent := withGeneration(FalseCondition("baz", "reason falseBaz", "message falseBaz"), 5)
+
+	tests := []struct {
+		name       string
+		conditions []*metav1.Condition
+		options    []MergeOption
+		want       *metav1.Condition
+	}{
+		{
+			name:       "Considers lagging conditions without the option",
+			conditions: []*metav1.Condition{fooCurrent, barLagging},
+			want:       FalseCondition(meta.ReadyCondition, "reason falseBar", "message falseBar"),
+		},
+		{
+			name:       "Ignores lagging conditions",
+			conditions: []*metav1.Condition{fooCurrent, barLagging},
+			options:    []MergeOption{WithCurrentGenerationOnly()},
+			want:       TrueCondition(meta.ReadyCondition, "reason trueFoo", "message trueFoo"),
+		},
+		{
+			name:       "Ignores lagging conditions with the step counter",
+			conditions: []*metav1.Condition{fooCurrent, barLagging, bazCurrent},
+			options:    []MergeOption{WithCurrentGenerationOnly(), WithStepCounter()},
+			want:       FalseCondition(meta.ReadyCondition, "reason falseBaz", "1 of 2 completed"),
+		},
+		{
+			name:       "Returns nil when all the conditions are lagging",
+			conditions: []*metav1.Condition{barLagging},
+			options:    []MergeOption{WithCurrentGenerationOnly()},
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			from := getterWithConditions(tt.conditions...)
+			from.SetGeneration(5)
+
+			got := summary(from, meta.ReadyCondition, tt.options...)
+			if tt.want == nil {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got).To(HaveSameStateOf(tt.want))
+		})
+	}
+}
+
 func TestAggregate(t *testing.T) {
 	ready1 := TrueCondition(meta.ReadyCondition, "reason true1", "message true1")
 	ready2 := FalseCondition(meta.ReadyCondition, "reason false1", "message false1")
@@ -32,7 +32,8 @@ import (
 )
 
 // MatchConditions returns a custom matcher to check equality of a metav1.Condition slice, the condition messages are
-// checked for a subset string match.
+// checked for a subset string match. The ObservedGeneration of a condition is only checked when it is set on the
+// expected condition.
 func MatchConditions(expected []metav1.Condition) types.GomegaMatcher {
 	return &matchConditions{
 		expected: expected,
@@ -59,7 +60,8 @@ func (m matchConditions) NegatedFailureMessage(actual interface{}) (message stri
 	return fmt.Sprintf("expected\n\t%#v\nto not match\n\t%#v\n", actual, m.expected)
 }
 
-// MatchCondition returns a custom matcher to check equality of metav1.Condition.
+// MatchCondition returns a custom matcher to check equality of metav1.Condition, the condition message is checked for
+// a subset string match. The ObservedGeneration is only checked when it is set on the expected condition.
 func MatchCondition(expected metav1.Condition) types.GomegaMatcher {
 	return &matchCondition{
 		expected: expected,
@@ -92,6 +94,12 @@ func (m matchCondition) Match(actual interface{}) (success bool, err error) {
 	if !ok {
 		return ok, err
 	}
+	if m.expected.ObservedGeneration != 0 {
+		ok, err = Equal(m.expected.ObservedGeneration).Match(actualCondition.ObservedGeneration)
+		if !ok {
+			return ok, err
+		}
+	}
 
 	return ok, err
 }
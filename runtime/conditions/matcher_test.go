@@ -194,6 +194,59 @@ func TestMatchCondition(t *testing.T) {
 			},
 			expectMatch: true,
 		},
+		{
+			name: "with a matching observed generation",
+			actual: metav1.Condition{
+				Type:               "type",
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 2,
+				Reason:             "reason",
+				Message:            "message",
+			},
+			expected: metav1.Condition{
+				Type:               "type",
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 2,
+				Reason:             "reason",
+				Message:            "message",
+			},
+			expectMatch: true,
+		},
+		{
+			name: "with a different observed generation",
+			actual: metav1.Condition{
+				Type:               "type",
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 3,
+				Reason:             "reason",
+				Message:            "message",
+			},
+			expected: metav1.Condition{
+				Type:               "type",
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 2,
+				Reason:             "reason",
+				Message:            "message",
+			},
+			expectMatch: false,
+		},
+		{
+			name: "with an unset expected observed generation",
+			actual: metav1.Condition{
+				Type:               "type",
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 3,
+				Reason:             "reason",
+				Message:            "message",
+			},
+			expected: metav1.Condition{
+				Type:    "type",
+				Status:  metav1.ConditionTrue,
+				Reason:  "reason",
+				Message: "message",
+			},
+			expectMatch: true,
+		},
 		{
 			name: "with a different type",
 			actual: metav1.Condition{
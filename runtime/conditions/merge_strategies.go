@@ -43,7 +43,8 @@ type mergeOptions struct {
 
 	stepCounter int
 
-	withLatestGeneration bool
+	withLatestGeneration  bool
+	currentGenerationOnly bool
 }
 
 // MergeOption defines an option for computing a summary of conditions.
@@ -66,6 +67,9 @@ func WithConditions(t ...string) MergeOption {
 // WithNegativePolarityConditions instructs merge about the condition types that adhere to a "normal-false" or
 // "abnormal-true" pattern, i.e. that conditions are present with a value of True whenever something unusual happens.
 //
+// If this option is not specified, or no condition types are given, the polarity registered with RegisterPolarity or
+// defined by a PolarityProvider object is used instead.
+//
 // NOTE: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
 // IMPORTANT: This option works only while generating the Summary or Aggregated condition.
 func WithNegativePolarityConditions(t ...string) MergeOption {
@@ -241,3 +245,11 @@ func WithLatestGeneration() MergeOption {
 		c.withLatestGeneration = true
 	}
 }
+
+// WithCurrentGenerationOnly instructs summary to ignore the conditions with an observed generation behind the
+// current generation of the object, so that stale conditions do not contribute to the summary.
+func WithCurrentGenerationOnly() MergeOption {
+	return func(c *mergeOptions) {
+		c.currentGenerationOnly = true
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MetricsErrorReasonList is the reason of the collection errors caused
+	// by a failure to list the objects of a kind.
+	MetricsErrorReasonList = "list"
+	// MetricsErrorReasonLimit is the reason of the collection errors caused
+	// by the series of a kind exceeding the maximum number of series.
+	MetricsErrorReasonLimit = "limit"
+
+	defaultMetricsPageSize = 500
+	defaultMetricsTimeout  = 30 * time.Second
+)
+
+// MetricsExporter is a prometheus.Collector exporting the status conditions
+// of all the objects of the given kinds as the gotk_object_condition gauge,
+// with the kind, namespace, name, type and status labels. The objects are
+// listed on each scrape, there is no background synchronisation.
+//
+// The failures to list the objects of a kind, and the series dropped when
+// exceeding the maximum number of series, do not fail the scrape but are
+// counted in the gotk_object_condition_collection_errors_total counter, with
+// the kind and reason labels.
+//
+// Use NewMetricsExporter to initialise it.
+type MetricsExporter struct {
+	client     client.Reader
+	gvks       []schema.GroupVersionKind
+	namespaces []string
+	maxSeries  int
+	pageSize   int64
+	timeout    time.Duration
+
+	conditionDesc *prometheus.Desc
+	errorsCounter *prometheus.CounterVec
+}
+
+// MetricsExporterOption is a function for configuring a MetricsExporter.
+type MetricsExporterOption func(e *MetricsExporter)
+
+// WithMetricsNamespaces restricts the exported objects to the given
+// namespaces. By default, the objects of all namespaces are exported.
+func WithMetricsNamespaces(namespaces ...string) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.namespaces = namespaces
+	}
+}
+
+// WithMetricsMaxSeries sets the maximum number of series exported on a
+// scrape, to bound the cardinality of the gauge. The conditions exceeding
+// it are dropped. Zero, the default, means unlimited.
+func WithMetricsMaxSeries(n int) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.maxSeries = n
+	}
+}
+
+// WithMetricsPageSize sets the number of objects fetched per list request.
+// It defaults to 500.
+func WithMetricsPageSize(n int64) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.pageSize = n
+	}
+}
+
+// WithMetricsTimeout sets the timeout of the listing of the objects on a
+// scrape. It defaults to 30 seconds.
+func WithMetricsTimeout(timeout time.Duration) MetricsExporterOption {
+	return func(e *MetricsExporter) {
+		e.timeout = timeout
+	}
+}
+
+// NewMetricsExporter returns a new MetricsExporter listing the objects of
+// the given kinds with the client. The kinds must implement status
+// conditions with the metav1.Condition type.
+func NewMetricsExporter(c client.Reader, gvks []schema.GroupVersionKind, opts ...MetricsExporterOption) *MetricsExporter {
+	e := &MetricsExporter{
+		client:   c,
+		gvks:     gvks,
+		pageSize: defaultMetricsPageSize,
+		timeout:  defaultMetricsTimeout,
+		conditionDesc: prometheus.NewDesc(
+			"gotk_object_condition",
+			"The current condition status of a GitOps Toolkit object.",
+			[]string{"kind", "namespace", "name", "type", "status"},
+			nil,
+		),
+		errorsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotk_object_condition_collection_errors_total",
+				Help: "The total number of errors collecting the condition status of GitOps Toolkit objects.",
+			},
+			[]string{"kind", "reason"},
+		),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Describe implements prometheus.Collector.
+func (e *MetricsExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.conditionDesc
+	e.errorsCounter.Describe(ch)
+}
+
+// Collect implements prometheus.Collector, listing the objects and sending
+// a gauge per condition status.
+func (e *MetricsExporter) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	namespaces := e.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var series int
+	for _, gvk := range e.gvks {
+		for _, namespace := range namespaces {
+			if err := e.collectKind(ctx, ch, gvk, namespace, &series); err != nil {
+				e.errorsCounter.WithLabelValues(gvk.Kind, MetricsErrorReasonList).Inc()
+			}
+		}
+	}
+	e.errorsCounter.Collect(ch)
+}
+
+// collectKind lists the objects of the given kind in the namespace, page
+// per page, and sends the gauges of their conditions, up to the maximum
+// number of series.
+func (e *MetricsExporter) collectKind(ctx context.Context, ch chan<- prometheus.Metric,
+	gvk schema.GroupVersionKind, namespace string, series *int) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	var continueToken string
+	for {
+		opts := []client.ListOption{client.Limit(e.pageSize), client.Continue(continueToken)}
+		if namespace != metav1.NamespaceAll {
+			opts = append(opts, client.InNamespace(namespace))
+		}
+		if err := e.client.List(ctx, list, opts...); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			for _, condition := range UnstructuredGetter(obj).GetConditions() {
+				e.collectCondition(ch, gvk.Kind, obj, condition, series)
+			}
+		}
+		if continueToken = list.GetContinue(); continueToken == "" {
+			return nil
+		}
+	}
+}
+
+// collectCondition sends the gauges of the condition, 1 for the current
+// status and 0 for the others, as the metrics.Recorder does. The condition is
+// dropped and counted as a collection error if it would exceed the maximum
+// number of series.
+func (e *MetricsExporter) collectCondition(ch chan<- prometheus.Metric, kind string,
+	obj *unstructured.Unstructured, condition metav1.Condition, series *int) {
+	statuses := []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+	if e.maxSeries > 0 && *series+len(statuses) > e.maxSeries {
+		e.errorsCounter.WithLabelValues(kind, MetricsErrorReasonLimit).Inc()
+		return
+	}
+	*series += len(statuses)
+
+	for _, status := range statuses {
+		var value float64
+		if status == condition.Status {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(e.conditionDesc, prometheus.GaugeValue, value,
+			kind, obj.GetNamespace(), obj.GetName(), condition.Type, string(status))
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+var (
+	fakeGVK  = testdata.FakeGroupVersion.WithKind("Fake")
+	otherGVK = testdata.FakeGroupVersion.WithKind("OtherFake")
+)
+
+func newMetricsObject(gvk schema.GroupVersionKind, namespace, name string, conditions ...metav1.Condition) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	UnstructuredSetter(u).SetConditions(conditions)
+	return u
+}
+
+func newMetricsClient(funcs interceptor.Funcs) client.WithWatch {
+	return fake.NewClientBuilder().
+		WithScheme(runtime.NewScheme()).
+		WithObjects(
+			newMetricsObject(fakeGVK, "default", "a",
+				metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Succeeded"}),
+			newMetricsObject(fakeGVK, "flux-system", "b",
+				metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Failed"},
+				metav1.Condition{Type: "Stalled", Status: metav1.ConditionTrue, Reason: "Failed"}),
+			newMetricsObject(otherGVK, "default", "c",
+				metav1.Condition{Type: "Ready", Status: metav1.ConditionUnknown, Reason: "Progressing"}),
+		).
+		WithInterceptorFuncs(funcs).
+		Build()
+}
+
+func TestMetricsExporter(t *testing.T) {
+	g := NewWithT(t)
+
+	exporter := NewMetricsExporter(newMetricsClient(interceptor.Funcs{}), []schema.GroupVersionKind{fakeGVK, otherGVK})
+
+	expected := `
+# HELP gotk_object_condition The current condition status of a GitOps Toolkit object.
+# TYPE gotk_object_condition gauge
+gotk_object_condition{kind="Fake",name="a",namespace="default",status="False",type="Ready"} 0
+gotk_object_condition{kind="Fake",name="a",namespace="default",status="True",type="Ready"} 1
+gotk_object_condition{kind="Fake",name="a",namespace="default",status="Unknown",type="Ready"} 0
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="False",type="Ready"} 1
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="True",type="Ready"} 0
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="Unknown",type="Ready"} 0
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="False",type="Stalled"} 0
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="True",type="Stalled"} 1
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="Unknown",type="Stalled"} 0
+gotk_object_condition{kind="OtherFake",name="c",namespace="default",status="False",type="Ready"} 0
+gotk_object_condition{kind="OtherFake",name="c",namespace="default",status="True",type="Ready"} 0
+gotk_object_condition{kind="OtherFake",name="c",namespace="default",status="Unknown",type="Ready"} 1
+`
+	g.Expect(testutil.CollectAndCompare(exporter, strings.NewReader(expected), "gotk_object_condition")).To(Succeed())
+}
+
+func TestMetricsExporter_Namespaces(t *testing.T) {
+	g := NewWithT(t)
+
+	exporter := NewMetricsExporter(newMetricsClient(interceptor.Funcs{}), []schema.GroupVersionKind{fakeGVK, otherGVK},
+		WithMetricsNamespaces("flux-system"))
+
+	expected := `
+# HELP gotk_object_condition The current condition status of a GitOps Toolkit object.
+# TYPE gotk_object_condition gauge
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="False",type="Ready"} 1
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="True",type="Ready"} 0
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="Unknown",type="Ready"} 0
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="False",type="Stalled"} 0
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="True",type="Stalled"} 1
+gotk_object_condition{kind="Fake",name="b",namespace="flux-system",status="Unknown",type="Stalled"} 0
+`
+	g.Expect(testutil.CollectAndCompare(exporter, strings.NewReader(expected), "gotk_object_condition")).To(Succeed())
+}
+
+func TestMetricsExporter_MaxSeries(t *testing.T) {
+	g := NewWithT(t)
+
+	exporter := NewMetricsExporter(newMetricsClient(interceptor.Funcs{}), []schema.GroupVersionKind{fakeGVK, otherGVK},
+		WithMetricsMaxSeries(6))
+
+	g.Expect(testutil.CollectAndCount(exporter, "gotk_object_condition")).To(Equal(6))
+
+	expected := `
+# HELP gotk_object_condition_collection_errors_total The total number of errors collecting the condition status of GitOps Toolkit objects.
+# TYPE gotk_object_condition_collection_errors_total counter
+gotk_object_condition_collection_errors_total{kind="Fake",reason="limit"} 2
+gotk_object_condition_collection_errors_total{kind="OtherFake",reason="limit"} 2
+`
+	g.Expect(testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"gotk_object_condition_collection_errors_total")).To(Succeed())
+}
+
+func TestMetricsExporter_ListError(t *testing.T) {
+	g := NewWithT(t)
+
+	c := newMetricsClient(interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if list.GetObjectKind().GroupVersionKind().Kind == "OtherFakeList" {
+				return errors.New("forbidden")
+			}
+			return c.List(ctx, list, opts...)
+		},
+	})
+	exporter := NewMetricsExporter(c, []schema.GroupVersionKind{otherGVK, fakeGVK})
+
+	// The conditions of the other kinds are still exported.
+	g.Expect(testutil.CollectAndCount(exporter, "gotk_object_condition")).To(Equal(9))
+
+	expected := `
+# HELP gotk_object_condition_collection_errors_total The total number of errors collecting the condition status of GitOps Toolkit objects.
+# TYPE gotk_object_condition_collection_errors_total counter
+gotk_object_condition_collection_errors_total{kind="OtherFake",reason="list"} 2
+`
+	g.Expect(testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"gotk_object_condition_collection_errors_total")).To(Succeed())
+}
+
+func TestMetricsExporter_Pagination(t *testing.T) {
+	g := NewWithT(t)
+
+	// The fake client does not paginate, return the objects one per page.
+	c := newMetricsClient(interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+			g.Expect(listOpts.Limit).To(Equal(int64(1)))
+
+			all := &unstructured.UnstructuredList{}
+			all.SetGroupVersionKind(list.GetObjectKind().GroupVersionKind())
+			if err := c.List(ctx, all); err != nil {
+				return err
+			}
+			page := list.(*unstructured.UnstructuredList)
+			var i int
+			if listOpts.Continue != "" {
+				i = int(listOpts.Continue[0] - '0')
+			}
+			page.Items = all.Items[i : i+1]
+			page.SetContinue("")
+			if i+1 < len(all.Items) {
+				page.SetContinue(string(rune('0' + i + 1)))
+			}
+			return nil
+		},
+	})
+	exporter := NewMetricsExporter(c, []schema.GroupVersionKind{fakeGVK}, WithMetricsPageSize(1))
+
+	g.Expect(testutil.CollectAndCount(exporter, "gotk_object_condition")).To(Equal(9))
+}
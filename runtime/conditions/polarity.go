@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Polarity is the polarity of a condition type.
+//
+// NOTE: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
+type Polarity string
+
+const (
+	// PositivePolarity is the polarity of the condition types that adhere
+	// to a "normal-true" pattern, e.g. Ready.
+	PositivePolarity Polarity = "Positive"
+	// NegativePolarity is the polarity of the condition types that adhere
+	// to a "normal-false" or "abnormal-true" pattern, e.g. Stalled.
+	NegativePolarity Polarity = "Negative"
+)
+
+// PolarityProvider is an optional interface implemented by the objects which
+// define the polarity of their own condition types. The polarity returned by
+// the object takes precedence over the registered polarity.
+type PolarityProvider interface {
+	// GetConditionPolarity returns the polarity of the given condition type,
+	// and false if the object does not define it.
+	GetConditionPolarity(conditionType string) (Polarity, bool)
+}
+
+// polarityRegistry holds the registered polarity of the condition types,
+// and the order in which they were registered.
+var polarityRegistry = struct {
+	sync.RWMutex
+	polarities map[string]Polarity
+	order      []string
+}{
+	polarities: map[string]Polarity{},
+}
+
+// RegisterPolarity registers the polarity of the given condition type. The
+// registered polarity is used by SetSummary, SetAggregate and the status
+// checker when no negative polarity condition types are explicitly given.
+// The registration order of the negative polarity condition types defines
+// their priority, highest first.
+//
+// RegisterPolarity is meant to be called at init time, and panics if the
+// condition type is already registered with a different polarity.
+func RegisterPolarity(conditionType string, polarity Polarity) {
+	if polarity != PositivePolarity && polarity != NegativePolarity {
+		panic(fmt.Sprintf("invalid polarity '%s' for condition type '%s'", polarity, conditionType))
+	}
+
+	polarityRegistry.Lock()
+	defer polarityRegistry.Unlock()
+
+	if p, ok := polarityRegistry.polarities[conditionType]; ok {
+		if p != polarity {
+			panic(fmt.Sprintf("condition type '%s' is already registered with %s polarity, cannot register it with %s polarity",
+				conditionType, p, polarity))
+		}
+		return
+	}
+	polarityRegistry.polarities[conditionType] = polarity
+	polarityRegistry.order = append(polarityRegistry.order, conditionType)
+}
+
+// GetPolarity returns the polarity of the given condition type for the given
+// object, which is the one defined by the object if it implements
+// PolarityProvider, or else the registered one. It returns false if the
+// polarity of the condition type is unknown.
+func GetPolarity(from Getter, conditionType string) (Polarity, bool) {
+	if pp, ok := from.(PolarityProvider); ok {
+		if p, ok := pp.GetConditionPolarity(conditionType); ok {
+			return p, true
+		}
+	}
+
+	polarityRegistry.RLock()
+	defer polarityRegistry.RUnlock()
+	p, ok := polarityRegistry.polarities[conditionType]
+	return p, ok
+}
+
+// NegativePolarityConditions returns the condition types with negative
+// polarity for the given object, in priority order: the registered condition
+// types in their registration order, followed by the condition types of the
+// object and the given additional condition types.
+func NegativePolarityConditions(from Getter, conditionTypes ...string) []string {
+	polarityRegistry.RLock()
+	candidates := append([]string{}, polarityRegistry.order...)
+	polarityRegistry.RUnlock()
+
+	if from != nil {
+		for _, c := range from.GetConditions() {
+			candidates = append(candidates, c.Type)
+		}
+	}
+	candidates = append(candidates, conditionTypes...)
+
+	var result []string
+	seen := make(map[string]struct{}, len(candidates))
+	for _, t := range candidates {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		if p, _ := GetPolarity(from, t); p == NegativePolarity {
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+// withPolarities registers the given polarities for the duration of the test.
+func withPolarities(t *testing.T, polarities map[string]Polarity, order ...string) {
+	t.Helper()
+
+	polarityRegistry.Lock()
+	saved := polarityRegistry.polarities
+	savedOrder := polarityRegistry.order
+	polarityRegistry.polarities = map[string]Polarity{}
+	polarityRegistry.order = nil
+	polarityRegistry.Unlock()
+
+	t.Cleanup(func() {
+		polarityRegistry.Lock()
+		defer polarityRegistry.Unlock()
+		polarityRegistry.polarities = saved
+		polarityRegistry.order = savedOrder
+	})
+
+	for _, conditionType := range order {
+		RegisterPolarity(conditionType, polarities[conditionType])
+	}
+}
+
+// polarityFake is a Fake object defining the polarity of its conditions.
+type polarityFake struct {
+	testdata.Fake
+	polarities map[string]Polarity
+}
+
+func (f *polarityFake) GetConditionPolarity(conditionType string) (Polarity, bool) {
+	p, ok := f.polarities[conditionType]
+	return p, ok
+}
+
+func TestRegisterPolarity(t *testing.T) {
+	g := NewWithT(t)
+	withPolarities(t, nil)
+
+	RegisterPolarity("Degraded", NegativePolarity)
+	RegisterPolarity("Healthy", PositivePolarity)
+
+	// Registering the same polarity again is a no-op.
+	g.Expect(func() { RegisterPolarity("Degraded", NegativePolarity) }).ToNot(Panic())
+	g.Expect(polarityRegistry.order).To(Equal([]string{"Degraded", "Healthy"}))
+
+	g.Expect(func() { RegisterPolarity("Degraded", PositivePolarity) }).To(PanicWith(
+		"condition type 'Degraded' is already registered with Negative polarity, cannot register it with Positive polarity"))
+	g.Expect(func() { RegisterPolarity("Healthy", NegativePolarity) }).To(PanicWith(
+		"condition type 'Healthy' is already registered with Positive polarity, cannot register it with Negative polarity"))
+	g.Expect(func() { RegisterPolarity("Other", "Neutral") }).To(PanicWith(
+		"invalid polarity 'Neutral' for condition type 'Other'"))
+
+	p, ok := GetPolarity(nil, "Degraded")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(p).To(Equal(NegativePolarity))
+	_, ok = GetPolarity(nil, "Other")
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestNegativePolarityConditions(t *testing.T) {
+	withPolarities(t, map[string]Polarity{
+		"Degraded":                NegativePolarity,
+		"Healthy":                 PositivePolarity,
+		meta.StalledCondition:     NegativePolarity,
+		meta.ReconcilingCondition: NegativePolarity,
+	}, meta.StalledCondition, "Degraded", "Healthy", meta.ReconcilingCondition)
+
+	tests := []struct {
+		name       string
+		obj        Getter
+		additional []string
+		want       []string
+	}{
+		{
+			name: "registered polarities in registration order",
+			obj:  nil,
+			want: []string{meta.StalledCondition, "Degraded", meta.ReconcilingCondition},
+		},
+		{
+			name: "object without polarities",
+			obj:  getterWithConditions(TrueCondition("Unregistered", "", "")),
+			want: []string{meta.StalledCondition, "Degraded", meta.ReconcilingCondition},
+		},
+		{
+			name: "object polarities take precedence",
+			obj: &polarityFake{
+				Fake: testdata.Fake{Status: testdata.FakeStatus{Conditions: []metav1.Condition{
+					*TrueCondition("Unregistered", "", ""),
+					*TrueCondition("Healthy", "", ""),
+				}}},
+				polarities: map[string]Polarity{
+					"Degraded":     PositivePolarity,
+					"Unregistered": NegativePolarity,
+				},
+			},
+			additional: []string{"Missing"},
+			want:       []string{meta.StalledCondition, meta.ReconcilingCondition, "Unregistered"},
+		},
+		{
+			name: "object polarities of additional condition types",
+			obj: &polarityFake{
+				polarities: map[string]Polarity{"Missing": NegativePolarity},
+			},
+			additional: []string{"Missing", "Degraded"},
+			want:       []string{meta.StalledCondition, "Degraded", meta.ReconcilingCondition, "Missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(NegativePolarityConditions(tt.obj, tt.additional...)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSetSummary_RegisteredPolarity(t *testing.T) {
+	withPolarities(t, map[string]Polarity{
+		"Degraded": NegativePolarity,
+	}, "Degraded")
+
+	tests := []struct {
+		name       string
+		conditions []*metav1.Condition
+		options    []MergeOption
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name: "registered negative polarity",
+			conditions: []*metav1.Condition{
+				TrueCondition("Degraded", "Broken", "broken"),
+				TrueCondition("Healthy", "Fine", "fine"),
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "Broken",
+		},
+		{
+			name: "registered negative polarity with normal value",
+			conditions: []*metav1.Condition{
+				FalseCondition("Degraded", "NotBroken", "not broken"),
+				TrueCondition("Healthy", "Fine", "fine"),
+			},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: "Fine",
+		},
+		{
+			name: "explicit list takes precedence",
+			conditions: []*metav1.Condition{
+				TrueCondition("Degraded", "Broken", "broken"),
+				TrueCondition("Healthy", "Unhealthy", "unhealthy"),
+			},
+			options:    []MergeOption{WithNegativePolarityConditions("Healthy")},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "Unhealthy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := setterWithConditions(tt.conditions...)
+			SetSummary(obj, meta.ReadyCondition, append(tt.options,
+				WithConditions("Degraded", "Healthy"))...)
+
+			ready := Get(obj, meta.ReadyCondition)
+			g.Expect(ready).ToNot(BeNil())
+			g.Expect(ready.Status).To(Equal(tt.wantStatus))
+			g.Expect(ready.Reason).To(Equal(tt.wantReason))
+		})
+	}
+}
+
+func TestSetAggregate_RegisteredPolarity(t *testing.T) {
+	g := NewWithT(t)
+	withPolarities(t, map[string]Polarity{
+		"Degraded": NegativePolarity,
+	}, "Degraded")
+
+	source1 := getterWithConditions(FalseCondition("Degraded", "NotBroken", ""))
+	source2 := getterWithConditions(TrueCondition("Degraded", "Broken", ""))
+	target := setterWithConditions()
+
+	SetAggregate(target, "Healthy", []Getter{source1, source2}, WithConditions("Degraded"))
+
+	g.Expect(IsFalse(target, "Healthy")).To(BeTrue())
+	g.Expect(GetReason(target, "Healthy")).To(Equal("Broken"))
+}
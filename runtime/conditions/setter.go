@@ -25,7 +25,9 @@ package conditions
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -49,8 +51,29 @@ func Set(to Setter, condition *metav1.Condition) {
 		return
 	}
 
+	set(to, condition, to.GetGeneration())
+	setExplicitObservedGeneration(to, condition.Type, false)
+}
+
+// SetWithObservedGeneration sets the given condition with the given observed generation instead of the generation of
+// the object, e.g. the generation of a referenced object the condition is about. The condition type is recorded in the
+// ExplicitObservedGenerationAnnotation of the object, for the checks comparing the observed generation of the
+// conditions to the generation of the object to ignore it. The annotation is updated by a later Set of the condition.
+//
+// NOTE: The LastTransitionTime is updated in the same way as with Set.
+func SetWithObservedGeneration(to Setter, condition *metav1.Condition, gen int64) {
+	if to == nil || condition == nil {
+		return
+	}
+
+	set(to, condition, gen)
+	setExplicitObservedGeneration(to, condition.Type, true)
+}
+
+// set sets the given condition with the given observed generation.
+func set(to Setter, condition *metav1.Condition, gen int64) {
 	// Always set the observed generation on the condition.
-	condition.ObservedGeneration = to.GetGeneration()
+	condition.ObservedGeneration = gen
 
 	// Trim the message to the maximum accepted length.
 	condition.Message = trimConditionMessage(condition.Message, maxMessageLength)
@@ -170,6 +193,26 @@ func SetMirror(to Setter, targetCondition string, from Getter, options ...Mirror
 	Set(to, mirror(from, targetCondition, options...))
 }
 
+// Mirror sets the condition of the toType of the target object to a copy of the condition of the fromType of the
+// source object, with the status, reason and message of the source condition and the generation of the target object
+// as observed generation. If the source object does not have the condition, the target condition is deleted, unless
+// a fallback value is given with WithFallbackValue. The message can be prefixed with the identity of the source object
+// using WithSourcePrefix, and the status inverted for sources of the opposite polarity using WithInvertedPolarity.
+func Mirror(from Getter, fromType string, to Setter, toType string, options ...MirrorOptions) {
+	if from == nil || to == nil {
+		return
+	}
+
+	condition := mirrorType(from, fromType, toType, options...)
+	if condition == nil {
+		if Has(to, toType) {
+			Delete(to, toType)
+		}
+		return
+	}
+	Set(to, condition)
+}
+
 // SetAggregate creates a new condition with the aggregation of all the conditions from a list of dependency objects,
 // or a subset using WithConditions; if none of the source objects have a condition within the scope of the merge
 // operation, no target condition is generated.
@@ -191,6 +234,70 @@ func Delete(to Setter, t string) {
 		}
 	}
 	to.SetConditions(newConditions)
+	setExplicitObservedGeneration(to, t, false)
+}
+
+// setExplicitObservedGeneration adds or removes the given condition type from the ExplicitObservedGenerationAnnotation
+// of the object. The annotation is removed once it does not hold any condition type.
+func setExplicitObservedGeneration(to Setter, t string, explicit bool) {
+	types := explicitObservedGenerationTypes(to)
+	if slices.Contains(types, t) == explicit {
+		return
+	}
+
+	if explicit {
+		types = append(types, t)
+		sort.Strings(types)
+	} else {
+		types = slices.DeleteFunc(types, func(s string) bool { return s == t })
+	}
+
+	annotations := to.GetAnnotations()
+	if len(types) == 0 {
+		delete(annotations, ExplicitObservedGenerationAnnotation)
+		to.SetAnnotations(annotations)
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ExplicitObservedGenerationAnnotation] = strings.Join(types, ",")
+	to.SetAnnotations(annotations)
+}
+
+// Sort sorts the conditions of the given object in a stable order, regardless of the order in which they were set:
+// meta.ReadyCondition first, followed by the given condition types in the provided order, followed by all the other
+// conditions sorted lexicographically by Type. All the condition fields are preserved.
+//
+// NOTE: Set re-sorts the conditions, therefore Sort should be called after all the conditions have been set, e.g.
+// right before patching the object.
+func Sort(to Setter, order ...string) {
+	if to == nil {
+		return
+	}
+
+	weights := make(map[string]int, len(order)+1)
+	weights[meta.ReadyCondition] = 0
+	for i, t := range order {
+		if _, ok := weights[t]; !ok {
+			weights[t] = i + 1
+		}
+	}
+
+	conditions := to.GetConditions()
+	sort.SliceStable(conditions, func(i, j int) bool {
+		w1, ok1 := weights[conditions[i].Type]
+		w2, ok2 := weights[conditions[j].Type]
+		switch {
+		case ok1 && ok2:
+			return w1 < w2
+		case ok1, ok2:
+			return !ok2
+		default:
+			return conditions[i].Type < conditions[j].Type
+		}
+	})
+	to.SetConditions(conditions)
 }
 
 // conditionWeights defines the weight of condition types that have priority in lexicographicLess.
@@ -178,6 +178,65 @@ func TestSet(t *testing.T) {
 	}
 }
 
+func TestSort(t *testing.T) {
+	a := TrueCondition("a", "", "")
+	b := FalseCondition("b", "reason", "message")
+	c := TrueCondition("c", "", "")
+	ready := TrueCondition(meta.ReadyCondition, "", "")
+	reconciling := TrueCondition(meta.ReconcilingCondition, "", "")
+
+	tests := []struct {
+		name  string
+		to    Setter
+		order []string
+		want  []string
+	}{
+		{
+			name: "Ready first, then the rest lexicographically",
+			to:   setterWithConditions(c, reconciling, a, ready, b),
+			want: []string{meta.ReadyCondition, meta.ReconcilingCondition, "a", "b", "c"},
+		},
+		{
+			name:  "Ready first, then the given order, then the rest lexicographically",
+			to:    setterWithConditions(c, reconciling, a, ready, b),
+			order: []string{"c", meta.ReconcilingCondition},
+			want:  []string{meta.ReadyCondition, "c", meta.ReconcilingCondition, "a", "b"},
+		},
+		{
+			name:  "Ready is not moved by the given order",
+			to:    setterWithConditions(b, a, ready),
+			order: []string{"b", meta.ReadyCondition},
+			want:  []string{meta.ReadyCondition, "b", "a"},
+		},
+		{
+			name:  "Unknown types in the given order are ignored",
+			to:    setterWithConditions(b, a),
+			order: []string{"d"},
+			want:  []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			before := append([]metav1.Condition(nil), tt.to.GetConditions()...)
+			Sort(tt.to, tt.order...)
+
+			var got []string
+			for _, c := range tt.to.GetConditions() {
+				got = append(got, c.Type)
+			}
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(tt.to.GetConditions()).To(ConsistOf(before))
+		})
+	}
+
+	t.Run("nil setter", func(t *testing.T) {
+		Sort(nil)
+	})
+}
+
 func TestSetLastTransitionTime(t *testing.T) {
 	x := metav1.Date(2012, time.January, 1, 12, 15, 30, 5e8, time.UTC)
 
@@ -272,6 +331,52 @@ func TestSetObservedGeneration(t *testing.T) {
 	g.Expect(Get(obj, "foo2").LastTransitionTime).To(Equal(x))
 }
 
+func TestSetWithObservedGeneration(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &testdata.Fake{}
+	obj.Generation = 4
+
+	// Set the conditions with the generation of a referenced object.
+	SetWithObservedGeneration(obj, TrueCondition("foo", "reasonFoo", "messageFoo"), 7)
+	SetWithObservedGeneration(obj, TrueCondition("bar", "reasonBar", "messageBar"), 2)
+	Set(obj, TrueCondition("baz", "reasonBaz", "messageBaz"))
+
+	g.Expect(Get(obj, "foo").ObservedGeneration).To(BeEquivalentTo(7))
+	g.Expect(Get(obj, "bar").ObservedGeneration).To(BeEquivalentTo(2))
+	g.Expect(Get(obj, "baz").ObservedGeneration).To(BeEquivalentTo(4))
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(ExplicitObservedGenerationAnnotation, "bar,foo"))
+
+	gen, ok := GetExplicitObservedGeneration(obj, "foo")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(gen).To(BeEquivalentTo(7))
+	gen, ok = GetExplicitObservedGeneration(obj, "baz")
+	g.Expect(ok).To(BeFalse())
+	g.Expect(gen).To(BeZero())
+	g.Expect(HasExplicitObservedGeneration(obj, "bar")).To(BeTrue())
+	g.Expect(HasExplicitObservedGeneration(obj, "baz")).To(BeFalse())
+
+	// A new referenced generation updates the ObservedGeneration but not the LastTransitionTime.
+	x := metav1.Date(2012, time.January, 1, 12, 15, 30, 5e8, time.UTC)
+	conditions := obj.GetConditions()
+	for i := range conditions {
+		conditions[i].LastTransitionTime = x
+	}
+	obj.SetConditions(conditions)
+	SetWithObservedGeneration(obj, TrueCondition("foo", "reasonFoo", "messageFoo"), 8)
+	g.Expect(Get(obj, "foo").ObservedGeneration).To(BeEquivalentTo(8))
+	g.Expect(Get(obj, "foo").LastTransitionTime).To(Equal(x))
+
+	// Set and Delete remove the condition types from the annotation.
+	Set(obj, TrueCondition("foo", "reasonFoo", "messageFoo"))
+	g.Expect(Get(obj, "foo").ObservedGeneration).To(BeEquivalentTo(4))
+	g.Expect(HasExplicitObservedGeneration(obj, "foo")).To(BeFalse())
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(ExplicitObservedGenerationAnnotation, "bar"))
+
+	Delete(obj, "bar")
+	g.Expect(obj.GetAnnotations()).ToNot(HaveKey(ExplicitObservedGenerationAnnotation))
+}
+
 func TestMarkMethods(t *testing.T) {
 	g := NewWithT(t)
 
@@ -346,6 +451,93 @@ func TestSetMirror(t *testing.T) {
 	g.Expect(Has(target, "foo")).To(BeTrue())
 }
 
+func TestMirrorBetweenObjects(t *testing.T) {
+	sourceReady := FalseCondition("SourceReady", "ChartPullFailed", "pull failed")
+	sourceReady.ObservedGeneration = 3
+	stalled := TrueCondition(meta.StalledCondition, "InvalidChart", "invalid chart")
+	unknown := UnknownCondition(meta.StalledCondition, "Progressing", "progressing")
+
+	tests := []struct {
+		name     string
+		source   []*metav1.Condition
+		target   []*metav1.Condition
+		fromType string
+		options  []MirrorOptions
+		want     *metav1.Condition
+	}{
+		{
+			name:     "copies the status, reason and message",
+			source:   []*metav1.Condition{sourceReady},
+			fromType: "SourceReady",
+			want:     FalseCondition("Mirrored", "ChartPullFailed", "pull failed"),
+		},
+		{
+			name:     "prefixes the message with the source identity",
+			source:   []*metav1.Condition{sourceReady},
+			fromType: "SourceReady",
+			options:  []MirrorOptions{WithSourcePrefix()},
+			want:     FalseCondition("Mirrored", "ChartPullFailed", "Fake/default/source: pull failed"),
+		},
+		{
+			name:     "inverts the status of a negative polarity source",
+			source:   []*metav1.Condition{stalled},
+			fromType: meta.StalledCondition,
+			options:  []MirrorOptions{WithInvertedPolarity()},
+			want:     FalseCondition("Mirrored", "InvalidChart", "invalid chart"),
+		},
+		{
+			name:     "does not invert the unknown status",
+			source:   []*metav1.Condition{unknown},
+			fromType: meta.StalledCondition,
+			options:  []MirrorOptions{WithInvertedPolarity()},
+			want:     UnknownCondition("Mirrored", "Progressing", "progressing"),
+		},
+		{
+			name:     "removes the target condition when the source condition is absent",
+			source:   []*metav1.Condition{stalled},
+			target:   []*metav1.Condition{TrueCondition("Mirrored", "Succeeded", "")},
+			fromType: "SourceReady",
+		},
+		{
+			name:     "sets the fallback value when the source condition is absent",
+			target:   []*metav1.Condition{TrueCondition("Mirrored", "Succeeded", "")},
+			fromType: "SourceReady",
+			options:  []MirrorOptions{WithFallbackValue(false, "SourceMissing", "no source"), WithInvertedPolarity()},
+			want:     FalseCondition("Mirrored", "SourceMissing", "no source"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			source := &testdata.Fake{}
+			source.SetGroupVersionKind(testdata.FakeGroupVersion.WithKind("Fake"))
+			source.SetNamespace("default")
+			source.SetName("source")
+			source.SetConditions(conditionList(tt.source...))
+
+			target := &testdata.Fake{}
+			target.SetGeneration(7)
+			target.SetConditions(conditionList(tt.target...))
+
+			Mirror(source, tt.fromType, target, "Mirrored", tt.options...)
+
+			// The source conditions are left untouched.
+			g.Expect(source.GetConditions()).To(Equal(conditionList(tt.source...)))
+
+			got := Get(target, "Mirrored")
+			if tt.want == nil {
+				g.Expect(got).To(BeNil())
+				g.Expect(target.GetConditions()).To(BeEmpty())
+				return
+			}
+			g.Expect(got).To(HaveSameStateOf(tt.want))
+			g.Expect(got.ObservedGeneration).To(Equal(int64(7)))
+		})
+	}
+}
+
 func TestSetAggregate(t *testing.T) {
 	g := NewWithT(t)
 	source1 := getterWithConditions(TrueCondition(meta.ReadyCondition, "", ""))
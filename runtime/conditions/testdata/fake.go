@@ -17,6 +17,8 @@ limitations under the License.
 package testdata
 
 import (
+	"time"
+
 	"github.com/fluxcd/pkg/apis/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,15 +47,17 @@ var (
 )
 
 type FakeSpec struct {
-	Suspend  bool            `json:"suspend,omitempty"`
-	Value    string          `json:"value,omitempty"`
-	Interval metav1.Duration `json:"interval"`
+	Suspend   bool                       `json:"suspend,omitempty"`
+	Value     string                     `json:"value,omitempty"`
+	Interval  metav1.Duration            `json:"interval"`
+	DependsOn []meta.DependencyReference `json:"dependsOn,omitempty"`
 }
 
 type FakeStatus struct {
 	ObservedGeneration          int64              `json:"observedGeneration,omitempty"`
 	Conditions                  []metav1.Condition `json:"conditions,omitempty"`
 	ObservedValue               string             `json:"observedValue,omitempty"`
+	Artifact                    *meta.Artifact     `json:"artifact,omitempty"`
 	meta.ReconcileRequestStatus `json:",inline"`
 }
 
@@ -65,10 +69,27 @@ func (f *Fake) SetConditions(conditions []metav1.Condition) {
 	f.Status.Conditions = conditions
 }
 
+func (f Fake) GetRequeueAfter() time.Duration {
+	return f.Spec.Interval.Duration
+}
+
+func (f Fake) GetSuspend() bool {
+	return f.Spec.Suspend
+}
+
+func (f Fake) GetArtifact() *meta.Artifact {
+	return f.Status.Artifact
+}
+
+func (f Fake) GetDependsOn() []meta.DependencyReference {
+	return f.Spec.DependsOn
+}
+
 func (f *Fake) DeepCopyInto(out *Fake) {
 	*out = *f
 	out.TypeMeta = f.TypeMeta
 	f.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	f.Spec.DeepCopyInto(&out.Spec)
 	f.Status.DeepCopyInto(&out.Status)
 }
 
@@ -119,6 +140,11 @@ func (in *FakeList) DeepCopyObject() runtime.Object {
 
 func (f *FakeSpec) DeepCopyInto(out *FakeSpec) {
 	*out = *f
+	if f.DependsOn != nil {
+		in, out := &f.DependsOn, &out.DependsOn
+		*out = make([]meta.DependencyReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 func (f *FakeSpec) DeepCopy() *FakeSpec {
@@ -139,6 +165,11 @@ func (f *FakeStatus) DeepCopyInto(out *FakeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if f.Artifact != nil {
+		in, out := &f.Artifact, &out.Artifact
+		*out = new(meta.Artifact)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 func (f *FakeStatus) DeepCopy() *FakeStatus {
@@ -26,12 +26,14 @@ package conditions
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -73,6 +75,18 @@ func UnstructuredUnmarshalField(u *unstructured.Unstructured, v interface{}, fie
 	return nil
 }
 
+// UnstructuredValidate returns an error listing the entries of the conditions of an Unstructured object which are
+// skipped by UnstructuredGetter, i.e. the entries which are not objects, cannot be decoded into a metav1.Condition,
+// or have no type. It returns nil if the object has no conditions.
+func UnstructuredValidate(u *unstructured.Unstructured) error {
+	_, _, errs := unstructuredConditions(u)
+	return kerrors.NewAggregate(errs)
+}
+
+// conditionFields are the fields of a metav1.Condition in the entries of the conditions, replaced when setting a
+// condition.
+var conditionFields = []string{"type", "status", "observedGeneration", "lastTransitionTime", "reason", "message"}
+
 type unstructuredWrapper struct {
 	*unstructured.Unstructured
 }
@@ -81,27 +95,24 @@ type unstructuredWrapper struct {
 //
 // NOTE: Due to the constraints of JSON-unmarshal, this operation is to be considered best effort.
 // In more details:
-//   - Errors during JSON-unmarshal are ignored and a empty collection list is returned.
+//   - The entries which cannot be decoded, or have no type, are skipped; UnstructuredValidate returns their errors.
 //   - It's not possible to detect if the object has an empty condition list or if it does not implement conditions;
 //     in both cases the operation returns an empty slice.
 //   - If the object doesn't implement status conditions as defined in GitOps Toolkit API,
 //     JSON-unmarshal matches incoming object keys to the keys; this can lead to to conditions values partially set.
 func (c *unstructuredWrapper) GetConditions() []metav1.Condition {
-	conditions := []metav1.Condition{}
-	if err := UnstructuredUnmarshalField(c.Unstructured, &conditions, "status", "conditions"); err != nil {
-		return nil
-	}
+	conditions, _, _ := unstructuredConditions(c.Unstructured)
 	return conditions
 }
 
 // SetConditions set the conditions into an Unstructured object.
 //
-// NOTE: Due to the constraints of JSON-unmarshal, this operation is to be considered best effort.
-// In more details:
-//   - Errors during JSON-unmarshal are ignored and a empty collection list is returned.
-//   - It's not possible to detect if the object has an empty condition list or if it does not implement conditions;
-//     in both cases the operation returns an empty slice is returned.
+// NOTE: The fields of the existing entries of the conditions which are unknown to metav1.Condition, e.g. the fields
+// of a different type of conditions, are preserved for the conditions of the same type. The entries skipped by
+// GetConditions are removed. A missing or null status is created.
 func (c *unstructuredWrapper) SetConditions(conditions []metav1.Condition) {
+	_, entries, _ := unstructuredConditions(c.Unstructured)
+
 	v := make([]interface{}, 0, len(conditions))
 	for i := range conditions {
 		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
@@ -109,8 +120,21 @@ func (c *unstructuredWrapper) SetConditions(conditions []metav1.Condition) {
 			log.Log.Error(err, "Failed to convert Condition to unstructured map. This error shouldn't have occurred, please file an issue.", "groupVersionKind", c.GroupVersionKind(), "name", c.GetName(), "namespace", c.GetNamespace())
 			continue
 		}
+		if existing, ok := entries[conditions[i].Type]; ok {
+			merged := maps.Clone(existing)
+			for _, field := range conditionFields {
+				delete(merged, field)
+			}
+			for k, val := range m {
+				merged[k] = val
+			}
+			m = merged
+		}
 		v = append(v, m)
 	}
+	if status, found := c.Unstructured.Object["status"]; found && status == nil {
+		delete(c.Unstructured.Object, "status")
+	}
 	// unstructured.SetNestedField returns an error only if value cannot be set because one of
 	// the nesting levels is not a map[string]interface{}; this is not the case so the error should never happen here.
 	err := unstructured.SetNestedField(c.Unstructured.Object, v, "status", "conditions")
@@ -118,3 +142,47 @@ func (c *unstructuredWrapper) SetConditions(conditions []metav1.Condition) {
 		log.Log.Error(err, "Failed to set Conditions on unstructured object. This error shouldn't have occurred, please file an issue.", "groupVersionKind", c.GroupVersionKind(), "name", c.GetName(), "namespace", c.GetNamespace())
 	}
 }
+
+// unstructuredConditions decodes the entries of the conditions of an Unstructured object. It returns the valid
+// conditions, the raw entries of the valid conditions by type, and the errors of the invalid entries. The conditions
+// are nil if the object has no conditions.
+func unstructuredConditions(u *unstructured.Unstructured) ([]metav1.Condition, map[string]map[string]interface{}, []error) {
+	value, found, err := unstructured.NestedFieldNoCopy(u.Object, "status", "conditions")
+	if err != nil {
+		return nil, nil, []error{errors.Wrapf(err, "failed to retrieve field \"status.conditions\" from %q", u.GroupVersionKind())}
+	}
+	if !found || value == nil {
+		return nil, nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, nil, []error{errors.Errorf("field \"status.conditions\" of %q is of the type %T, expected []interface{}", u.GroupVersionKind(), value)}
+	}
+
+	conditions := []metav1.Condition{}
+	entries := map[string]map[string]interface{}{}
+	var errs []error
+	for i, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			errs = append(errs, errors.Errorf("invalid condition at index %d: of the type %T, expected an object", i, item))
+			continue
+		}
+		var condition metav1.Condition
+		b, err := json.Marshal(entry)
+		if err == nil {
+			err = json.Unmarshal(b, &condition)
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid condition at index %d", i))
+			continue
+		}
+		if condition.Type == "" {
+			errs = append(errs, errors.Errorf("invalid condition at index %d: missing type", i))
+			continue
+		}
+		conditions = append(conditions, condition)
+		entries[condition.Type] = entry
+	}
+	return conditions, entries, errs
+}
@@ -102,3 +102,132 @@ func TestUnstructuredSetConditions(t *testing.T) {
 	s.SetConditions(conditions)
 	g.Expect(s.GetConditions()).To(Equal(conditions))
 }
+
+func TestUnstructuredSetConditions_noStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		object map[string]interface{}
+	}{
+		{
+			name:   "missing status",
+			object: map[string]interface{}{"kind": "Bucket"},
+		},
+		{
+			name:   "null status",
+			object: map[string]interface{}{"kind": "Bucket", "status": nil},
+		},
+		{
+			name: "status without conditions",
+			object: map[string]interface{}{
+				"kind":   "Bucket",
+				"status": map[string]interface{}{"observedGeneration": int64(2)},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			u := &unstructured.Unstructured{Object: tt.object}
+			s := UnstructuredSetter(u)
+			g.Expect(s.GetConditions()).To(BeNil())
+			g.Expect(UnstructuredValidate(u)).To(Succeed())
+
+			MarkTrue(s, "Ready", "Succeeded", "reconciled %s", "bucket")
+			g.Expect(IsTrue(UnstructuredGetter(u), "Ready")).To(BeTrue())
+			g.Expect(GetMessage(s, "Ready")).To(Equal("reconciled bucket"))
+
+			if status, ok := tt.object["status"].(map[string]interface{}); ok && len(status) > 1 {
+				g.Expect(status).To(HaveKeyWithValue("observedGeneration", int64(2)))
+			}
+		})
+	}
+}
+
+func TestUnstructuredSetConditions_preservesUnknownFields(t *testing.T) {
+	g := NewWithT(t)
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Cluster",
+		"status": map[string]interface{}{
+			"phase": "Provisioned",
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":               "Ready",
+					"status":             "False",
+					"reason":             "Provisioning",
+					"message":            "provisioning",
+					"observedGeneration": int64(1),
+					"lastTransitionTime": "2026-01-01T00:00:00Z",
+					"severity":           "Warning",
+				},
+				map[string]interface{}{
+					"type":               "Available",
+					"status":             "True",
+					"reason":             "Available",
+					"lastTransitionTime": "2026-01-01T00:00:00Z",
+					"severity":           "Info",
+				},
+			},
+		},
+	}}
+
+	s := UnstructuredSetter(u)
+	MarkTrue(s, "Ready", "Provisioned", "provisioned")
+	SetSummary(s, "Summary", WithConditions("Ready", "Available"))
+
+	status := u.Object["status"].(map[string]interface{})
+	g.Expect(status).To(HaveKeyWithValue("phase", "Provisioned"))
+	entries := status["conditions"].([]interface{})
+	g.Expect(entries).To(HaveLen(3))
+
+	ready := entries[0].(map[string]interface{})
+	g.Expect(ready).To(HaveKeyWithValue("status", "True"))
+	g.Expect(ready).To(HaveKeyWithValue("reason", "Provisioned"))
+	g.Expect(ready).To(HaveKeyWithValue("severity", "Warning"))
+	// The fields of the condition removed by the update are not preserved.
+	g.Expect(ready).ToNot(HaveKey("observedGeneration"))
+
+	available := entries[1].(map[string]interface{})
+	g.Expect(available).To(HaveKeyWithValue("severity", "Info"))
+
+	g.Expect(IsTrue(s, "Summary")).To(BeTrue())
+}
+
+func TestUnstructuredValidate(t *testing.T) {
+	g := NewWithT(t)
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Bucket",
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"status": "True", "reason": "NoType"},
+				"Ready",
+				map[string]interface{}{"type": "Ready", "status": "True", "reason": "Succeeded"},
+				map[string]interface{}{"type": "Stalled", "status": map[string]interface{}{}},
+			},
+		},
+	}}
+
+	conditions := UnstructuredGetter(u).GetConditions()
+	g.Expect(conditions).To(HaveLen(1))
+	g.Expect(conditions[0].Type).To(Equal("Ready"))
+
+	err := UnstructuredValidate(u)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid condition at index 0: missing type"))
+	g.Expect(err.Error()).To(ContainSubstring("invalid condition at index 1: of the type string"))
+	g.Expect(err.Error()).To(ContainSubstring("invalid condition at index 3"))
+	g.Expect(err.Error()).ToNot(ContainSubstring("index 2"))
+
+	// The invalid entries are removed when setting the conditions.
+	s := UnstructuredSetter(u)
+	MarkFalse(s, "Ready", "Failed", "failed")
+	g.Expect(UnstructuredValidate(u)).To(Succeed())
+	g.Expect(s.GetConditions()).To(HaveLen(1))
+	g.Expect(IsFalse(s, "Ready")).To(BeTrue())
+
+	// The conditions field must be a list.
+	u.Object["status"] = map[string]interface{}{"conditions": "Ready"}
+	g.Expect(UnstructuredGetter(u).GetConditions()).To(BeNil())
+	g.Expect(UnstructuredValidate(u)).To(MatchError(ContainSubstring("expected []interface{}")))
+}
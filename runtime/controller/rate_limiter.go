@@ -17,6 +17,9 @@ limitations under the License.
 package controller
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -29,6 +32,7 @@ const (
 	defaultMaxRetryDelay = 15 * time.Minute
 	flagMinRetryDelay    = "min-retry-delay"
 	flagMaxRetryDelay    = "max-retry-delay"
+	flagPerKindDelays    = "rate-limiter-per-kind"
 )
 
 // RateLimiterOptions defines the configurable options for rate limiters
@@ -41,6 +45,20 @@ type RateLimiterOptions struct {
 	// MaxRetryDelay represents the maximum amount of time in which an
 	// object being reconciled will have to wait before a retry.
 	MaxRetryDelay time.Duration
+
+	// PerKindRetryDelays overrides MinRetryDelay and MaxRetryDelay
+	// for the objects of the given kinds.
+	PerKindRetryDelays map[string]RetryDelays
+}
+
+// RetryDelays holds the minimum and maximum retry delays of the exponential
+// failure backoff of an object kind.
+type RetryDelays struct {
+	// MinRetryDelay is the delay of the first retry.
+	MinRetryDelay time.Duration
+
+	// MaxRetryDelay caps the delay between retries.
+	MaxRetryDelay time.Duration
 }
 
 // BindFlags will parse the given pflag.FlagSet for the controller and
@@ -50,6 +68,82 @@ func (o *RateLimiterOptions) BindFlags(fs *pflag.FlagSet) {
 		"The minimum amount of time for which an object being reconciled will have to wait before a retry.")
 	fs.DurationVar(&o.MaxRetryDelay, flagMaxRetryDelay, defaultMaxRetryDelay,
 		"The maximum amount of time for which an object being reconciled will have to wait before a retry.")
+	fs.Var(&perKindRetryDelaysValue{delays: &o.PerKindRetryDelays}, flagPerKindDelays,
+		"A comma separated list of <kind>=<min>:<max> pairs overriding the minimum and maximum retry delays "+
+			"for the objects of a kind, e.g. 'GitRepository=5s:10m,Kustomization=1s:5m'.")
+}
+
+// ParsePerKindRetryDelays parses a comma separated list of <kind>=<min>:<max>
+// pairs, e.g. 'GitRepository=5s:10m,Kustomization=1s:5m', into a map of
+// RetryDelays by kind. The returned error names the malformed segment.
+func ParsePerKindRetryDelays(s string) (map[string]RetryDelays, error) {
+	res := make(map[string]RetryDelays)
+	if strings.TrimSpace(s) == "" {
+		return res, nil
+	}
+	for _, segment := range strings.Split(s, ",") {
+		segment = strings.TrimSpace(segment)
+		kind, delays, ok := strings.Cut(segment, "=")
+		kind = strings.TrimSpace(kind)
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("invalid rate limiter segment '%s': must be in the format <kind>=<min>:<max>", segment)
+		}
+		minStr, maxStr, ok := strings.Cut(delays, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limiter segment '%s': must be in the format <kind>=<min>:<max>", segment)
+		}
+		minDelay, err := time.ParseDuration(strings.TrimSpace(minStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limiter segment '%s': invalid min retry delay: %w", segment, err)
+		}
+		maxDelay, err := time.ParseDuration(strings.TrimSpace(maxStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limiter segment '%s': invalid max retry delay: %w", segment, err)
+		}
+		if minDelay <= 0 || maxDelay < minDelay {
+			return nil, fmt.Errorf("invalid rate limiter segment '%s': min retry delay must be positive and not greater than max retry delay", segment)
+		}
+		if _, exists := res[kind]; exists {
+			return nil, fmt.Errorf("invalid rate limiter segment '%s': duplicate kind '%s'", segment, kind)
+		}
+		res[kind] = RetryDelays{MinRetryDelay: minDelay, MaxRetryDelay: maxDelay}
+	}
+	return res, nil
+}
+
+// perKindRetryDelaysValue implements pflag.Value for the per kind retry delays.
+type perKindRetryDelaysValue struct {
+	delays *map[string]RetryDelays
+}
+
+func (v *perKindRetryDelaysValue) String() string {
+	if v.delays == nil || len(*v.delays) == 0 {
+		return ""
+	}
+	kinds := make([]string, 0, len(*v.delays))
+	for kind := range *v.delays {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	pairs := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		d := (*v.delays)[kind]
+		pairs = append(pairs, fmt.Sprintf("%s=%s:%s", kind, d.MinRetryDelay, d.MaxRetryDelay))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v *perKindRetryDelaysValue) Set(s string) error {
+	delays, err := ParsePerKindRetryDelays(s)
+	if err != nil {
+		return err
+	}
+	*v.delays = delays
+	return nil
+}
+
+func (v *perKindRetryDelaysValue) Type() string {
+	return "mapStringRetryDelays"
 }
 
 // GetRateLimiter returns a new exponential failure workqueue.TypedRateLimiter
@@ -63,3 +157,51 @@ func GetRateLimiter(opts RateLimiterOptions) workqueue.TypedRateLimiter[reconcil
 func GetDefaultRateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
 	return workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](defaultMinRetryDelay, defaultMaxRetryDelay)
 }
+
+// GetPerKindRateLimiter returns a new workqueue.TypedRateLimiter which selects
+// an exponential failure rate limiter by the kind of the request, as returned
+// by kindOf. The kinds in RateLimiterOptions.PerKindRetryDelays use their own
+// retry delays, while any other kind uses MinRetryDelay and MaxRetryDelay.
+func GetPerKindRateLimiter(opts RateLimiterOptions,
+	kindOf func(reconcile.Request) string) workqueue.TypedRateLimiter[reconcile.Request] {
+	limiters := make(map[string]workqueue.TypedRateLimiter[reconcile.Request], len(opts.PerKindRetryDelays))
+	for kind, d := range opts.PerKindRetryDelays {
+		limiters[kind] = workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](d.MinRetryDelay, d.MaxRetryDelay)
+	}
+	return &perKindRateLimiter{
+		kindOf:   kindOf,
+		limiters: limiters,
+		fallback: GetRateLimiter(opts),
+	}
+}
+
+// perKindRateLimiter dispatches the requests to a rate limiter by kind.
+type perKindRateLimiter struct {
+	kindOf   func(reconcile.Request) string
+	limiters map[string]workqueue.TypedRateLimiter[reconcile.Request]
+	fallback workqueue.TypedRateLimiter[reconcile.Request]
+}
+
+func (r *perKindRateLimiter) limiterFor(item reconcile.Request) workqueue.TypedRateLimiter[reconcile.Request] {
+	if r.kindOf != nil {
+		if l, ok := r.limiters[r.kindOf(item)]; ok {
+			return l
+		}
+	}
+	return r.fallback
+}
+
+// When returns the delay before the item should be retried.
+func (r *perKindRateLimiter) When(item reconcile.Request) time.Duration {
+	return r.limiterFor(item).When(item)
+}
+
+// Forget stops tracking the failures of the item.
+func (r *perKindRateLimiter) Forget(item reconcile.Request) {
+	r.limiterFor(item).Forget(item)
+}
+
+// NumRequeues returns the number of failures of the item.
+func (r *perKindRateLimiter) NumRequeues(item reconcile.Request) int {
+	return r.limiterFor(item).NumRequeues(item)
+}
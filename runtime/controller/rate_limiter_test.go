@@ -22,6 +22,8 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/fluxcd/pkg/runtime/controller"
 )
@@ -78,3 +80,124 @@ func Test_RateLimiterOptions_BindFlags(t *testing.T) {
 		})
 	}
 }
+
+func Test_RateLimiterOptions_BindFlags_PerKind(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandLine []string
+		expected    map[string]controller.RetryDelays
+		expectedErr string
+	}{
+		{
+			name:        "no per kind delays",
+			commandLine: []string{""},
+			expected:    nil,
+		},
+		{
+			name:        "multiple kinds",
+			commandLine: []string{"--rate-limiter-per-kind=GitRepository=5s:10m,Kustomization=1s:5m"},
+			expected: map[string]controller.RetryDelays{
+				"GitRepository": {MinRetryDelay: 5 * time.Second, MaxRetryDelay: 10 * time.Minute},
+				"Kustomization": {MinRetryDelay: time.Second, MaxRetryDelay: 5 * time.Minute},
+			},
+		},
+		{
+			name:        "missing delays",
+			commandLine: []string{"--rate-limiter-per-kind=GitRepository=5s:10m,Kustomization"},
+			expectedErr: "invalid rate limiter segment 'Kustomization'",
+		},
+		{
+			name:        "missing max delay",
+			commandLine: []string{"--rate-limiter-per-kind=GitRepository=5s"},
+			expectedErr: "invalid rate limiter segment 'GitRepository=5s'",
+		},
+		{
+			name:        "invalid duration",
+			commandLine: []string{"--rate-limiter-per-kind=HelmRelease=1x:5m"},
+			expectedErr: "invalid rate limiter segment 'HelmRelease=1x:5m': invalid min retry delay",
+		},
+		{
+			name:        "min greater than max",
+			commandLine: []string{"--rate-limiter-per-kind=HelmRelease=10m:1m"},
+			expectedErr: "invalid rate limiter segment 'HelmRelease=10m:1m'",
+		},
+		{
+			name:        "duplicate kind",
+			commandLine: []string{"--rate-limiter-per-kind=Bucket=1s:1m,Bucket=2s:2m"},
+			expectedErr: "duplicate kind 'Bucket'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			opts := controller.RateLimiterOptions{}
+			opts.BindFlags(f)
+
+			err := f.Parse(tt.commandLine)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(opts.PerKindRetryDelays).To(Equal(tt.expected))
+		})
+	}
+}
+
+func Test_GetPerKindRateLimiter(t *testing.T) {
+	g := NewWithT(t)
+
+	opts := controller.RateLimiterOptions{
+		MinRetryDelay: time.Second,
+		MaxRetryDelay: 4 * time.Second,
+		PerKindRetryDelays: map[string]controller.RetryDelays{
+			"GitRepository": {MinRetryDelay: 5 * time.Second, MaxRetryDelay: 20 * time.Second},
+			"Kustomization": {MinRetryDelay: 100 * time.Millisecond, MaxRetryDelay: 300 * time.Millisecond},
+		},
+	}
+	gitRepo := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "repo"}}
+	ks := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}
+	other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "podinfo"}}
+	kinds := map[reconcile.Request]string{
+		gitRepo: "GitRepository",
+		ks:      "Kustomization",
+		other:   "HelmRelease",
+	}
+	limiter := controller.GetPerKindRateLimiter(opts, func(req reconcile.Request) string {
+		return kinds[req]
+	})
+
+	for _, tt := range []struct {
+		req      reconcile.Request
+		expected []time.Duration
+	}{
+		{
+			req:      gitRepo,
+			expected: []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 20 * time.Second},
+		},
+		{
+			req:      ks,
+			expected: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond},
+		},
+		{
+			req:      other,
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second},
+		},
+	} {
+		for _, d := range tt.expected {
+			g.Expect(limiter.When(tt.req)).To(Equal(d), tt.req.String())
+		}
+		g.Expect(limiter.NumRequeues(tt.req)).To(Equal(len(tt.expected)))
+	}
+
+	// Forgetting an object only resets its own backoff.
+	limiter.Forget(gitRepo)
+	g.Expect(limiter.NumRequeues(gitRepo)).To(Equal(0))
+	g.Expect(limiter.When(gitRepo)).To(Equal(5 * time.Second))
+	g.Expect(limiter.NumRequeues(ks)).To(Equal(4))
+	g.Expect(limiter.When(other)).To(Equal(4 * time.Second))
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagObjectLevelWorkloadIdentity     = "object-level-workload-identity"
+	flagAllowedServiceAccountNamespaces = "allowed-service-account-namespaces"
+)
+
+// WorkloadIdentityOptions defines the configurable options for the
+// object-level workload identity of the reconcilers. Consumers are expected
+// to pass ObjectLevelEnabled to auth.SetObjectLevelWorkloadIdentityEnabled
+// and AllowedServiceAccountNamespaces to auth.WithAllowedServiceAccountNamespaces.
+type WorkloadIdentityOptions struct {
+	// ObjectLevelEnabled, if set to true allows the objects of all namespaces
+	// to specify a service account for workload identity.
+	ObjectLevelEnabled bool
+
+	// AllowedServiceAccountNamespaces is the list of namespaces whose objects
+	// are allowed to specify a service account for workload identity even
+	// when object-level workload identity is disabled.
+	AllowedServiceAccountNamespaces []string
+}
+
+// BindFlags will parse the given pflag.FlagSet for the controller and
+// set the WorkloadIdentityOptions accordingly.
+func (o *WorkloadIdentityOptions) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.ObjectLevelEnabled, flagObjectLevelWorkloadIdentity, false,
+		"Allow the objects of all namespaces to use object-level workload identity.")
+	fs.StringSliceVar(&o.AllowedServiceAccountNamespaces, flagAllowedServiceAccountNamespaces, nil,
+		"The namespaces allowed to use object-level workload identity when it is disabled, comma-separated.")
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/fluxcd/pkg/runtime/controller"
+)
+
+func Test_WorkloadIdentityOptions_BindFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantEnabled bool
+		wantAllowed []string
+	}{
+		{
+			name: "empty flags set default values",
+			args: []string{""},
+		},
+		{
+			name:        "--object-level-workload-identity set to true",
+			args:        []string{"--object-level-workload-identity=true"},
+			wantEnabled: true,
+		},
+		{
+			name:        "--allowed-service-account-namespaces set",
+			args:        []string{"--allowed-service-account-namespaces=flux-system,tenant-a"},
+			wantAllowed: []string{"flux-system", "tenant-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			opts := controller.WorkloadIdentityOptions{}
+			opts.BindFlags(f)
+
+			err := f.Parse(tt.args)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(opts.ObjectLevelEnabled).To(Equal(tt.wantEnabled))
+			g.Expect(opts.AllowedServiceAccountNamespaces).To(Equal(tt.wantAllowed))
+		})
+	}
+}
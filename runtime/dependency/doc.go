@@ -15,5 +15,6 @@ limitations under the License.
 */
 
 // Package dependency contains an utility for sorting a set of Kubernetes
-// resource objects that implement the Dependent interface.
+// resource objects that implement the Dependent interface, and the
+// readiness checks of their dependencies.
 package dependency
@@ -0,0 +1,198 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	celgo "github.com/google/cel-go/cel"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/cel"
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+// ReadyResult is the result of the evaluation of a ReadyCheck.
+type ReadyResult struct {
+	// Ready is true if the dependency satisfies the check.
+	Ready bool
+	// Message explains why the dependency does not satisfy the check, for
+	// inclusion in the Reconciling condition message of the dependent. It is
+	// empty if Ready is true.
+	Message string
+}
+
+// ReadyCheck is a readiness gate of a dependency, evaluated before the
+// reconciliation of the dependent proceeds.
+type ReadyCheck interface {
+	// Check evaluates the readiness of the dependency for the dependent,
+	// both as unstructured objects.
+	Check(ctx context.Context, self, dep *unstructured.Unstructured) (ReadyResult, error)
+}
+
+// ReadyCondition is the default ReadyCheck, satisfied if the Ready
+// condition of the dependency is True for its current generation.
+type ReadyCondition struct{}
+
+// Check implements ReadyCheck.
+func (ReadyCondition) Check(_ context.Context, _, dep *unstructured.Unstructured) (ReadyResult, error) {
+	getter := conditions.UnstructuredGetter(dep)
+	ready := conditions.Get(getter, meta.ReadyCondition)
+	if ready == nil {
+		return notReady("dependency '%s' is not ready: no Ready condition", dependencyName(dep)), nil
+	}
+	if ready.Status != metav1.ConditionTrue {
+		if ready.Message == "" {
+			return notReady("dependency '%s' is not ready: Ready is %s", dependencyName(dep), ready.Status), nil
+		}
+		return notReady("dependency '%s' is not ready: Ready is %s with message '%s'",
+			dependencyName(dep), ready.Status, ready.Message), nil
+	}
+
+	observedGeneration, _, err := unstructured.NestedInt64(dep.Object, "status", "observedGeneration")
+	if err != nil {
+		return ReadyResult{}, fmt.Errorf("unable to read the observed generation of dependency '%s': %w",
+			dependencyName(dep), err)
+	}
+	if generation := dep.GetGeneration(); observedGeneration != generation {
+		return notReady("dependency '%s' is not ready: observed generation %d does not match generation %d",
+			dependencyName(dep), observedGeneration, generation), nil
+	}
+	return ReadyResult{Ready: true}, nil
+}
+
+// RevisionMatch is a ReadyCheck satisfied if a status field of the
+// dependency matches the source revision of the dependent, e.g. to wait
+// for the dependency to apply the revision the dependent is about to
+// apply.
+type RevisionMatch struct {
+	// Revision is the source revision of the dependent.
+	Revision string
+	// FieldPath is the path of the revision field of the dependency. It
+	// defaults to status.lastAppliedRevision.
+	FieldPath []string
+}
+
+// Check implements ReadyCheck.
+func (c RevisionMatch) Check(_ context.Context, _, dep *unstructured.Unstructured) (ReadyResult, error) {
+	fieldPath := c.FieldPath
+	if len(fieldPath) == 0 {
+		fieldPath = []string{"status", "lastAppliedRevision"}
+	}
+	field := strings.Join(fieldPath, ".")
+
+	revision, found, err := unstructured.NestedString(dep.Object, fieldPath...)
+	if err != nil {
+		return ReadyResult{}, fmt.Errorf("unable to read the field '%s' of dependency '%s': %w",
+			field, dependencyName(dep), err)
+	}
+	if !found || revision == "" {
+		return notReady("dependency '%s' is not ready: no revision at '%s', expected '%s'",
+			dependencyName(dep), field, c.Revision), nil
+	}
+	if revision != c.Revision {
+		return notReady("dependency '%s' is not ready: revision '%s' at '%s' does not match '%s'",
+			dependencyName(dep), revision, field, c.Revision), nil
+	}
+	return ReadyResult{Ready: true}, nil
+}
+
+// CELExpression is a ReadyCheck satisfied if a CEL expression evaluates to
+// true. The expression has access to the dependent as 'self' and to the
+// dependency as 'dep'.
+type CELExpression struct {
+	expr *cel.Expression
+}
+
+// NewCELExpression compiles the given CEL expression into a CELExpression.
+// The expression must return a boolean.
+func NewCELExpression(expr string) (*CELExpression, error) {
+	e, err := cel.NewExpression(expr,
+		cel.WithCompile(),
+		cel.WithOutputType(celgo.BoolType),
+		cel.WithStructVariables("self", "dep"))
+	if err != nil {
+		return nil, err
+	}
+	return &CELExpression{expr: e}, nil
+}
+
+// Check implements ReadyCheck.
+func (c *CELExpression) Check(ctx context.Context, self, dep *unstructured.Unstructured) (ReadyResult, error) {
+	ready, err := c.expr.EvaluateBoolean(ctx, map[string]any{
+		"self": self.UnstructuredContent(),
+		"dep":  dep.UnstructuredContent(),
+	})
+	if err != nil {
+		return ReadyResult{}, fmt.Errorf("unable to evaluate the readiness of dependency '%s': %w",
+			dependencyName(dep), err)
+	}
+	if !ready {
+		return notReady("dependency '%s' is not ready: the expression '%s' evaluated to false",
+			dependencyName(dep), c.expr), nil
+	}
+	return ReadyResult{Ready: true}, nil
+}
+
+// NewReadyChecks returns the ReadyCheck of the given dependency reference:
+// the CEL expression of its ReadyExpr field if set, replacing the
+// ReadyCondition check, or in addition to it if additive is true, as with
+// the AdditiveCELDependencyCheck feature gate. It returns the
+// ReadyCondition check if ReadyExpr is not set.
+func NewReadyChecks(ref meta.DependencyReference, additive bool) ([]ReadyCheck, error) {
+	if ref.ReadyExpr == "" {
+		return []ReadyCheck{ReadyCondition{}}, nil
+	}
+	expr, err := NewCELExpression(ref.ReadyExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid readyExpr of dependency '%s': %w", ref.Name, err)
+	}
+	if additive {
+		return []ReadyCheck{ReadyCondition{}, expr}, nil
+	}
+	return []ReadyCheck{expr}, nil
+}
+
+// CheckReady evaluates the checks of the dependency in order, and returns
+// the result of the first one not satisfied. If no checks are given, the
+// ReadyCondition check is evaluated.
+func CheckReady(ctx context.Context, self, dep *unstructured.Unstructured, checks ...ReadyCheck) (ReadyResult, error) {
+	if len(checks) == 0 {
+		checks = []ReadyCheck{ReadyCondition{}}
+	}
+	for _, check := range checks {
+		result, err := check.Check(ctx, self, dep)
+		if err != nil || !result.Ready {
+			return result, err
+		}
+	}
+	return ReadyResult{Ready: true}, nil
+}
+
+// notReady returns a ReadyResult not satisfied with the formatted message.
+func notReady(format string, a ...any) ReadyResult {
+	return ReadyResult{Message: fmt.Sprintf(format, a...)}
+}
+
+// dependencyName returns the namespaced name of the dependency.
+func dependencyName(dep *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s", dep.GetNamespace(), dep.GetName())
+}
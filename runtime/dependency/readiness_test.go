@@ -0,0 +1,283 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependency_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/dependency"
+)
+
+func newDependency(generation, observedGeneration int64, revision string, conditions ...map[string]any) *unstructured.Unstructured {
+	status := map[string]any{
+		"observedGeneration": observedGeneration,
+	}
+	if revision != "" {
+		status["lastAppliedRevision"] = revision
+	}
+	if len(conditions) > 0 {
+		items := make([]any, 0, len(conditions))
+		for _, c := range conditions {
+			items = append(items, c)
+		}
+		status["conditions"] = items
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+		"kind":       "Kustomization",
+		"metadata": map[string]any{
+			"name":       "backend",
+			"namespace":  "apps",
+			"generation": generation,
+		},
+		"status": status,
+	}}
+}
+
+func readyCondition(status, message string) map[string]any {
+	return map[string]any{
+		"type":               meta.ReadyCondition,
+		"status":             status,
+		"reason":             meta.ReconciliationSucceededReason,
+		"message":            message,
+		"lastTransitionTime": "2026-01-01T00:00:00Z",
+	}
+}
+
+var dependent = &unstructured.Unstructured{Object: map[string]any{
+	"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+	"kind":       "Kustomization",
+	"metadata": map[string]any{
+		"name":      "frontend",
+		"namespace": "apps",
+		"annotations": map[string]any{
+			"app.example.com/min-revision": "main@sha1:2",
+		},
+	},
+}}
+
+func TestReadyCondition(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		dep     *unstructured.Unstructured
+		ready   bool
+		message string
+	}{
+		{
+			name:  "ready",
+			dep:   newDependency(2, 2, "", readyCondition("True", "Applied revision: main@sha1:1")),
+			ready: true,
+		},
+		{
+			name:    "not ready",
+			dep:     newDependency(2, 2, "", readyCondition("False", "kustomization path not found")),
+			message: "dependency 'apps/backend' is not ready: Ready is False with message 'kustomization path not found'",
+		},
+		{
+			name:    "unknown without message",
+			dep:     newDependency(2, 2, "", readyCondition("Unknown", "")),
+			message: "dependency 'apps/backend' is not ready: Ready is Unknown",
+		},
+		{
+			name:    "no Ready condition",
+			dep:     newDependency(2, 2, ""),
+			message: "dependency 'apps/backend' is not ready: no Ready condition",
+		},
+		{
+			name:    "stale generation",
+			dep:     newDependency(3, 2, "", readyCondition("True", "Applied revision: main@sha1:1")),
+			message: "dependency 'apps/backend' is not ready: observed generation 2 does not match generation 3",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := dependency.ReadyCondition{}.Check(context.Background(), dependent, tt.dep)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(dependency.ReadyResult{Ready: tt.ready, Message: tt.message}))
+		})
+	}
+}
+
+func TestRevisionMatch(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		check   dependency.RevisionMatch
+		dep     *unstructured.Unstructured
+		ready   bool
+		message string
+		err     string
+	}{
+		{
+			name:  "matching revision",
+			check: dependency.RevisionMatch{Revision: "main@sha1:2"},
+			dep:   newDependency(1, 1, "main@sha1:2"),
+			ready: true,
+		},
+		{
+			name:    "different revision",
+			check:   dependency.RevisionMatch{Revision: "main@sha1:2"},
+			dep:     newDependency(1, 1, "main@sha1:1"),
+			message: "dependency 'apps/backend' is not ready: revision 'main@sha1:1' at 'status.lastAppliedRevision' does not match 'main@sha1:2'",
+		},
+		{
+			name:    "no revision",
+			check:   dependency.RevisionMatch{Revision: "main@sha1:2"},
+			dep:     newDependency(1, 1, ""),
+			message: "dependency 'apps/backend' is not ready: no revision at 'status.lastAppliedRevision', expected 'main@sha1:2'",
+		},
+		{
+			name: "custom field",
+			check: dependency.RevisionMatch{
+				Revision:  "main@sha1:2",
+				FieldPath: []string{"status", "lastAttemptedRevision"},
+			},
+			dep:     newDependency(1, 1, "main@sha1:2"),
+			message: "dependency 'apps/backend' is not ready: no revision at 'status.lastAttemptedRevision', expected 'main@sha1:2'",
+		},
+		{
+			name: "invalid field",
+			check: dependency.RevisionMatch{
+				Revision:  "main@sha1:2",
+				FieldPath: []string{"status", "observedGeneration"},
+			},
+			dep: newDependency(1, 1, ""),
+			err: "unable to read the field 'status.observedGeneration' of dependency 'apps/backend'",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := tt.check.Check(context.Background(), dependent, tt.dep)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.err)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(dependency.ReadyResult{Ready: tt.ready, Message: tt.message}))
+		})
+	}
+}
+
+func TestCELExpression(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		expr    string
+		dep     *unstructured.Unstructured
+		ready   bool
+		message string
+		err     string
+	}{
+		{
+			name:  "true",
+			expr:  "dep.status.lastAppliedRevision == self.metadata.annotations['app.example.com/min-revision']",
+			dep:   newDependency(1, 1, "main@sha1:2"),
+			ready: true,
+		},
+		{
+			name: "false",
+			expr: "dep.status.lastAppliedRevision == self.metadata.annotations['app.example.com/min-revision']",
+			dep:  newDependency(1, 1, "main@sha1:1"),
+			message: "dependency 'apps/backend' is not ready: the expression " +
+				"'dep.status.lastAppliedRevision == self.metadata.annotations['app.example.com/min-revision']' evaluated to false",
+		},
+		{
+			name: "missing field",
+			expr: "dep.status.lastAppliedRevision == 'main@sha1:2'",
+			dep:  newDependency(1, 1, ""),
+			err:  "unable to evaluate the readiness of dependency 'apps/backend'",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			check, err := dependency.NewCELExpression(tt.expr)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			result, err := check.Check(context.Background(), dependent, tt.dep)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.err)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(dependency.ReadyResult{Ready: tt.ready, Message: tt.message}))
+		})
+	}
+
+	t.Run("non-boolean expression", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := dependency.NewCELExpression("dep.metadata.name")
+		g.Expect(err).To(MatchError(ContainSubstring("output type mismatch")))
+	})
+}
+
+func TestCheckReady(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	ready := newDependency(1, 1, "main@sha1:1", readyCondition("True", ""))
+
+	result, err := dependency.CheckReady(ctx, dependent, ready)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Ready).To(BeTrue())
+
+	// The first check not satisfied explains the result.
+	result, err = dependency.CheckReady(ctx, dependent, ready,
+		dependency.ReadyCondition{}, dependency.RevisionMatch{Revision: "main@sha1:2"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(dependency.ReadyResult{
+		Message: "dependency 'apps/backend' is not ready: revision 'main@sha1:1' at 'status.lastAppliedRevision' does not match 'main@sha1:2'",
+	}))
+}
+
+func TestNewReadyChecks(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	notReady := newDependency(1, 1, "main@sha1:2", readyCondition("False", "failed"))
+	ref := meta.DependencyReference{
+		Name:      "backend",
+		ReadyExpr: "dep.status.lastAppliedRevision == 'main@sha1:2'",
+	}
+
+	checks, err := dependency.NewReadyChecks(meta.DependencyReference{Name: "backend"}, false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(checks).To(Equal([]dependency.ReadyCheck{dependency.ReadyCondition{}}))
+
+	// The expression replaces the Ready condition check.
+	checks, err = dependency.NewReadyChecks(ref, false)
+	g.Expect(err).NotTo(HaveOccurred())
+	result, err := dependency.CheckReady(ctx, dependent, notReady, checks...)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Ready).To(BeTrue())
+
+	// The additive expression is evaluated after the Ready condition check.
+	checks, err = dependency.NewReadyChecks(ref, true)
+	g.Expect(err).NotTo(HaveOccurred())
+	result, err = dependency.CheckReady(ctx, dependent, notReady, checks...)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Message).To(Equal("dependency 'apps/backend' is not ready: Ready is False with message 'failed'"))
+
+	_, err = dependency.NewReadyChecks(meta.DependencyReference{Name: "backend", ReadyExpr: "dep."}, false)
+	g.Expect(err).To(MatchError(ContainSubstring("invalid readyExpr of dependency 'backend'")))
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/object"
 )
 
 // Dependent interface defines methods that a Kubernetes resource object should
@@ -29,7 +30,7 @@ import (
 type Dependent interface {
 	GetName() string
 	GetNamespace() string
-	meta.ObjectWithDependencies
+	object.ObjectWithDependencies
 }
 
 const (
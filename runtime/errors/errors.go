@@ -18,6 +18,7 @@ package errors
 
 import (
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -97,3 +98,75 @@ func (e *GarbageCollectionError) Error() string {
 func (e *GarbageCollectionError) Unwrap() error {
 	return e.Err
 }
+
+// ErrWaiting describes a reconciliation which cannot progress until a
+// condition is met, e.g. a dependency becomes ready. It is not a failure: the
+// reconciliation is retried after RequeueAfter, with the object marked as
+// Reconciling with the given Reason and Message.
+type ErrWaiting struct {
+	// RequeueAfter is the duration after which the reconciliation is retried.
+	// When zero, the object is requeued with exponential backoff.
+	RequeueAfter time.Duration
+	// Reason is the reason of the Reconciling condition.
+	Reason string
+	// Message is the message of the Reconciling condition.
+	Message string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// NewWaiting returns a new ErrWaiting with the given requeue duration,
+// reason and formatted message.
+func NewWaiting(requeueAfter time.Duration, reason, messageFormat string, messageArgs ...any) *ErrWaiting {
+	return &ErrWaiting{
+		RequeueAfter: requeueAfter,
+		Reason:       reason,
+		Message:      fmt.Sprintf(messageFormat, messageArgs...),
+	}
+}
+
+func (e *ErrWaiting) Error() string {
+	if e.Message == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *ErrWaiting) Unwrap() error {
+	return e.Err
+}
+
+// ErrTemporary describes a reconciliation failure which is expected to
+// resolve on its own, e.g. a rate limited API call. The reconciliation is
+// retried after RequeueAfter instead of with exponential backoff, with the
+// object marked as not Ready with the given Reason.
+type ErrTemporary struct {
+	// RequeueAfter is the duration after which the reconciliation is retried.
+	// When zero, the object is requeued with exponential backoff.
+	RequeueAfter time.Duration
+	// Reason is the reason of the Ready condition.
+	Reason string
+	// Err is the underlying error.
+	Err error
+}
+
+// NewTemporary returns a new ErrTemporary for the given error, requeue
+// duration and reason.
+func NewTemporary(err error, requeueAfter time.Duration, reason string) *ErrTemporary {
+	return &ErrTemporary{
+		RequeueAfter: requeueAfter,
+		Reason:       reason,
+		Err:          err,
+	}
+}
+
+func (e *ErrTemporary) Error() string {
+	if e.Err == nil {
+		return "temporary failure"
+	}
+	return e.Err.Error()
+}
+
+func (e *ErrTemporary) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+const (
+	// DefaultFileSinkMaxSize is the default size in bytes of the events file
+	// after which it is rotated.
+	DefaultFileSinkMaxSize int64 = 100 * 1024 * 1024
+
+	// DefaultFileSinkMaxFiles is the default number of rotated events files
+	// kept next to the current one.
+	DefaultFileSinkMaxFiles = 5
+)
+
+// FileSyncPolicy defines when the events written by a FileSink are flushed
+// to stable storage.
+type FileSyncPolicy string
+
+const (
+	// FileSyncAlways flushes the file after every event, an event is
+	// persisted when Write returns.
+	FileSyncAlways FileSyncPolicy = "always"
+
+	// FileSyncNone leaves the flushing of the file to the operating system,
+	// the events written right before a crash may be lost.
+	FileSyncNone FileSyncPolicy = "none"
+)
+
+// ErrFileSinkClosed is returned when writing to a closed FileSink.
+var ErrFileSinkClosed = errors.New("file sink is closed")
+
+// FileSink is a Sink that appends the events, as JSON lines, to a file for
+// audit purposes. The file is rotated when it exceeds a maximum size, the
+// rotated files are suffixed with their generation, e.g. 'events.log.1' for
+// the most recent one.
+//
+// Use NewFileSink to create a working FileSink, and register it in the
+// Sinks broadcaster of a Recorder.
+type FileSink struct {
+	path        string
+	maxSize     int64
+	maxFiles    int
+	syncPolicy  FileSyncPolicy
+	minSeverity string
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+var _ Sink = &FileSink{}
+
+// FileSinkOption configures a FileSink.
+type FileSinkOption func(*FileSink)
+
+// WithFileMaxSize sets the size in bytes after which the events file is
+// rotated. A size of zero disables the rotation. Defaults to
+// DefaultFileSinkMaxSize.
+func WithFileMaxSize(size int64) FileSinkOption {
+	return func(s *FileSink) {
+		s.maxSize = size
+	}
+}
+
+// WithFileMaxFiles sets the number of rotated events files to keep, the
+// older ones are removed. With zero, the events file is discarded when
+// rotated. Defaults to DefaultFileSinkMaxFiles.
+func WithFileMaxFiles(n int) FileSinkOption {
+	return func(s *FileSink) {
+		s.maxFiles = n
+	}
+}
+
+// WithFileSyncPolicy sets when the events are flushed to stable storage.
+// Defaults to FileSyncAlways.
+func WithFileSyncPolicy(policy FileSyncPolicy) FileSinkOption {
+	return func(s *FileSink) {
+		s.syncPolicy = policy
+	}
+}
+
+// WithFileMinSeverity sets the minimum severity (info, error) of the events
+// written to the file. Defaults to all the severities.
+func WithFileMinSeverity(severity string) FileSinkOption {
+	return func(s *FileSink) {
+		s.minSeverity = severity
+	}
+}
+
+// NewFileSink opens, or creates, the events file at the given path and
+// returns a FileSink appending to it. If the last line of an existing file
+// is incomplete, e.g. after a crash in the middle of a write, it is
+// truncated so that the file only contains complete events.
+func NewFileSink(path string, opts ...FileSinkOption) (*FileSink, error) {
+	s := &FileSink{
+		path:       path,
+		maxSize:    DefaultFileSinkMaxSize,
+		maxFiles:   DefaultFileSinkMaxFiles,
+		syncPolicy: FileSyncAlways,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.maxSize < 0 {
+		return nil, fmt.Errorf("invalid max size %d: must not be negative", s.maxSize)
+	}
+	if s.maxFiles < 0 {
+		return nil, fmt.Errorf("invalid max files %d: must not be negative", s.maxFiles)
+	}
+	switch s.syncPolicy {
+	case FileSyncAlways, FileSyncNone:
+	default:
+		return nil, fmt.Errorf("invalid sync policy '%s'", s.syncPolicy)
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Name returns the name of the sink.
+func (s *FileSink) Name() string {
+	return SinkFile
+}
+
+// Write appends the given event to the file, rotating it first if the event
+// would make it exceed the maximum size.
+func (s *FileSink) Write(event eventv1.Event) error {
+	if !meetsSeverity(event.Severity, s.minSeverity) {
+		filteredEventsCounter.WithLabelValues(SinkFile, event.Severity).Inc()
+		return nil
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return ErrFileSinkClosed
+	}
+
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write event to '%s': %w", s.path, err)
+	}
+	if s.syncPolicy == FileSyncAlways {
+		if err := s.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync '%s': %w", s.path, err)
+		}
+	}
+	return nil
+}
+
+// Close flushes and closes the events file. Any further Write returns
+// ErrFileSinkClosed.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := errors.Join(s.file.Sync(), s.file.Close())
+	s.file = nil
+	return err
+}
+
+// open opens the events file in append mode, after truncating its trailing
+// incomplete line if any.
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", s.path, err)
+	}
+	size, err := truncatePartialLine(f)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to recover '%s': %w", s.path, err)
+	}
+	s.file = f
+	s.size = size
+	return nil
+}
+
+// rotate closes the events file, shifts the rotated files by one generation
+// and opens a new events file.
+func (s *FileSink) rotate() error {
+	if err := errors.Join(s.file.Sync(), s.file.Close()); err != nil {
+		return fmt.Errorf("failed to close '%s': %w", s.path, err)
+	}
+	s.file = nil
+
+	if s.maxFiles == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate '%s': %w", s.path, err)
+		}
+	} else {
+		if err := os.Remove(s.rotatedPath(s.maxFiles)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate '%s': %w", s.path, err)
+		}
+		for i := s.maxFiles - 1; i > 0; i-- {
+			if err := os.Rename(s.rotatedPath(i), s.rotatedPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to rotate '%s': %w", s.path, err)
+			}
+		}
+		if err := os.Rename(s.path, s.rotatedPath(1)); err != nil {
+			return fmt.Errorf("failed to rotate '%s': %w", s.path, err)
+		}
+	}
+
+	return s.open()
+}
+
+// rotatedPath returns the path of the rotated events file of the given
+// generation.
+func (s *FileSink) rotatedPath(generation int) string {
+	return fmt.Sprintf("%s.%d", s.path, generation)
+}
+
+// truncatePartialLine truncates the given file after its last newline when
+// the file does not end with one, and returns the resulting size.
+func truncatePartialLine(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+
+	buf := make([]byte, 4096)
+	for end := size; end > 0; {
+		start := max(end-int64(len(buf)), 0)
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			offset := start + int64(i) + 1
+			if offset == size {
+				return size, nil
+			}
+			return offset, f.Truncate(offset)
+		}
+		end = start
+	}
+
+	if size == 0 {
+		return 0, nil
+	}
+	return 0, f.Truncate(0)
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+func newTestEvent(message string) eventv1.Event {
+	return eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "ConfigMap",
+			Namespace: "gitops-system",
+			Name:      "webapp",
+		},
+		Severity:            eventv1.EventSeverityInfo,
+		Message:             message,
+		Reason:              "sync",
+		ReportingController: "test-controller",
+	}
+}
+
+// eventLineSize returns the size in bytes of the given event in the file.
+func eventLineSize(t *testing.T, event eventv1.Event) int64 {
+	t.Helper()
+	b, err := json.Marshal(event)
+	require.NoError(t, err)
+	return int64(len(b) + 1)
+}
+
+// readEvents returns the messages of the events in the given file.
+func readEvents(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var messages []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event eventv1.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		messages = append(messages, event.Message)
+	}
+	require.NoError(t, scanner.Err())
+	return messages
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	// All the events have the same size as their messages have the same length.
+	lineSize := eventLineSize(t, newTestEvent("event-0"))
+
+	for _, tt := range []struct {
+		name     string
+		maxSize  int64
+		maxFiles int
+		events   int
+		expected map[string][]string
+	}{
+		{
+			name:     "file filled up to the max size is not rotated",
+			maxSize:  2 * lineSize,
+			maxFiles: 2,
+			events:   2,
+			expected: map[string][]string{
+				"events.log": {"event-0", "event-1"},
+			},
+		},
+		{
+			name:     "event exceeding the max size by one byte rotates the file",
+			maxSize:  2*lineSize - 1,
+			maxFiles: 2,
+			events:   2,
+			expected: map[string][]string{
+				"events.log":   {"event-1"},
+				"events.log.1": {"event-0"},
+			},
+		},
+		{
+			name:     "event larger than the max size is written to a new file",
+			maxSize:  lineSize / 2,
+			maxFiles: 2,
+			events:   2,
+			expected: map[string][]string{
+				"events.log":   {"event-1"},
+				"events.log.1": {"event-0"},
+			},
+		},
+		{
+			name:     "rotated files are shifted and the oldest removed",
+			maxSize:  lineSize,
+			maxFiles: 2,
+			events:   4,
+			expected: map[string][]string{
+				"events.log":   {"event-3"},
+				"events.log.1": {"event-2"},
+				"events.log.2": {"event-1"},
+			},
+		},
+		{
+			name:     "events file is discarded without rotated files",
+			maxSize:  lineSize,
+			maxFiles: 0,
+			events:   3,
+			expected: map[string][]string{
+				"events.log": {"event-2"},
+			},
+		},
+		{
+			name:     "rotation disabled",
+			maxSize:  0,
+			maxFiles: 2,
+			events:   3,
+			expected: map[string][]string{
+				"events.log": {"event-0", "event-1", "event-2"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			sink, err := NewFileSink(filepath.Join(dir, "events.log"),
+				WithFileMaxSize(tt.maxSize), WithFileMaxFiles(tt.maxFiles))
+			require.NoError(t, err)
+
+			for i := range tt.events {
+				require.NoError(t, sink.Write(newTestEvent(fmt.Sprintf("event-%d", i))))
+			}
+			require.NoError(t, sink.Close())
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			files := make(map[string][]string)
+			for _, entry := range entries {
+				files[entry.Name()] = readEvents(t, filepath.Join(dir, entry.Name()))
+			}
+			require.Equal(t, tt.expected, files)
+		})
+	}
+}
+
+func TestFileSink_PartialLine(t *testing.T) {
+	line, err := json.Marshal(newTestEvent("complete"))
+	require.NoError(t, err)
+	line = append(line, '\n')
+
+	for _, tt := range []struct {
+		name     string
+		content  []byte
+		expected []string
+	}{
+		{
+			name:     "empty file",
+			expected: []string{"appended"},
+		},
+		{
+			name:     "complete lines are kept",
+			content:  line,
+			expected: []string{"complete", "appended"},
+		},
+		{
+			name:     "incomplete trailing line is truncated",
+			content:  append(append([]byte{}, line...), line[:len(line)/2]...),
+			expected: []string{"complete", "appended"},
+		},
+		{
+			name:     "single incomplete line is truncated",
+			content:  line[:len(line)/2],
+			expected: []string{"appended"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "events.log")
+			require.NoError(t, os.WriteFile(path, tt.content, 0o600))
+
+			sink, err := NewFileSink(path)
+			require.NoError(t, err)
+			require.NoError(t, sink.Write(newTestEvent("appended")))
+			require.NoError(t, sink.Close())
+
+			require.Equal(t, tt.expected, readEvents(t, path))
+		})
+	}
+}
+
+func TestFileSink_ConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.log")
+	lineSize := eventLineSize(t, newTestEvent("worker-0-event-00"))
+
+	const workers, events = 8, 50
+	maxFiles := workers * events
+	sink, err := NewFileSink(path, WithFileMaxSize(10*lineSize), WithFileMaxFiles(maxFiles),
+		WithFileSyncPolicy(FileSyncNone))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for i := range events {
+				require.NoError(t, sink.Write(newTestEvent(fmt.Sprintf("worker-%d-event-%02d", w, i))))
+			}
+		})
+	}
+	wg.Wait()
+	require.NoError(t, sink.Close())
+
+	// Every event is written exactly once, as a complete line.
+	seen := make(map[string]bool)
+	paths := []string{path}
+	for i := 1; i <= maxFiles; i++ {
+		paths = append(paths, fmt.Sprintf("%s.%d", path, i))
+	}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		require.NoError(t, err)
+		require.LessOrEqual(t, info.Size(), 10*lineSize)
+		for _, message := range readEvents(t, p) {
+			require.False(t, seen[message], "duplicate event %s", message)
+			seen[message] = true
+		}
+	}
+	require.Len(t, seen, workers*events)
+}
+
+func TestFileSink_MinSeverity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := NewFileSink(path, WithFileMinSeverity(eventv1.EventSeverityError))
+	require.NoError(t, err)
+
+	filtered := testutil.ToFloat64(filteredEventsCounter.WithLabelValues(SinkFile, eventv1.EventSeverityInfo))
+
+	info := newTestEvent("info")
+	require.NoError(t, sink.Write(info))
+	failure := newTestEvent("error")
+	failure.Severity = eventv1.EventSeverityError
+	require.NoError(t, sink.Write(failure))
+	require.NoError(t, sink.Close())
+
+	require.Equal(t, []string{"error"}, readEvents(t, path))
+	require.Equal(t, filtered+1, testutil.ToFloat64(filteredEventsCounter.WithLabelValues(SinkFile, eventv1.EventSeverityInfo)))
+	require.ErrorIs(t, sink.Write(failure), ErrFileSinkClosed)
+}
+
+func TestFileSink_InvalidOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	_, err := NewFileSink(path, WithFileMaxSize(-1))
+	require.Error(t, err)
+	_, err = NewFileSink(path, WithFileMaxFiles(-1))
+	require.Error(t, err)
+	_, err = NewFileSink(path, WithFileSyncPolicy("sometimes"))
+	require.Error(t, err)
+}
+
+func TestEventRecorder_Sinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	eventRecorder, err := NewRecorderForScheme(env.GetScheme(), record.NewFakeRecorder(10), ctrl.Log, "", "test-controller")
+	require.NoError(t, err)
+	eventRecorder.Sinks = NewBroadcaster()
+	eventRecorder.Sinks.Register(sink)
+
+	obj := &corev1.ConfigMap{}
+	obj.Namespace = "gitops-system"
+	obj.Name = "webapp"
+
+	eventRecorder.Event(obj, corev1.EventTypeNormal, "sync", "sync webapp")
+	eventRecorder.Event(obj, corev1.EventTypeWarning, "sync", "sync failed")
+	// Trace events are recorded in the Kubernetes API only.
+	eventRecorder.Event(obj, eventv1.EventTypeTrace, "sync", "sync trace")
+
+	require.Equal(t, []string{"sync webapp", "sync failed"}, readEvents(t, path))
+}
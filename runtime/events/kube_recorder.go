@@ -0,0 +1,438 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	kuberecorder "k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/record/util"
+	"k8s.io/client-go/tools/reference"
+	"k8s.io/utils/clock"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+const (
+	// EnvPodName is the environment variable key used to specify the name
+	// of the controller pod, recorded as the reporting instance of the events.
+	EnvPodName = "POD_NAME"
+
+	// eventSeriesTolerance is the period after which an isomorphic
+	// events.k8s.io/v1 event is recorded as a new event instead of
+	// incrementing the series of the previous one.
+	eventSeriesTolerance = 6 * time.Minute
+
+	// eventNoteLimit is the maximum length in bytes of the note of an
+	// events.k8s.io/v1 event accepted by the Kubernetes API.
+	eventNoteLimit = 1024
+
+	// kubeEventTimeout is the timeout of the Kubernetes API requests
+	// recording an event.
+	kubeEventTimeout = 5 * time.Second
+)
+
+// InvolvedNamespaceAnnotation is the annotation holding the namespace of the
+// regarding object of the events recorded in another namespace, see
+// WithEventNamespace.
+const InvolvedNamespaceAnnotation = eventv1.Group + "/involved-namespace"
+
+// RelatedEventRecorder is implemented by the Kubernetes event recorders which
+// can reference an object related to the regarding object of an event.
+type RelatedEventRecorder interface {
+	// AnnotatedRelatedEventf is like AnnotatedEventf, with the related object
+	// referenced in the event. The related object can be nil.
+	AnnotatedRelatedEventf(regarding, related runtime.Object, annotations map[string]string,
+		eventtype, reason, messageFmt string, args ...interface{})
+}
+
+// KubeRecorder records events in the Kubernetes API, with the reporting
+// controller, the reporting instance and the related object set.
+//
+// The events are recorded with the events.k8s.io/v1 API, isomorphic events
+// being recorded as a series. On clusters not serving the events.k8s.io/v1
+// API, the events are recorded with the core/v1 API and aggregated like with
+// the client-go event recorder.
+//
+// The reporting instance is the name of the controller pod given in the
+// POD_NAME environment variable, falling back to the hostname.
+//
+// Use NewKubeRecorder to create a working KubeRecorder.
+type KubeRecorder struct {
+	client              kubernetes.Interface
+	scheme              *runtime.Scheme
+	log                 logr.Logger
+	reportingController string
+	reportingInstance   string
+	eventsV1            bool
+	namespace           string
+
+	clock      clock.PassiveClock
+	correlator *kuberecorder.EventCorrelator
+
+	mu        sync.Mutex
+	series    map[seriesKey]*eventsv1.Event
+	forbidden map[string]bool
+}
+
+// KubeRecorderOption configures a KubeRecorder.
+type KubeRecorderOption func(*KubeRecorder)
+
+// WithEventNamespace records all the events in the given namespace instead
+// of the namespace of their regarding object, e.g. the namespace of a
+// controller reconciling the objects of tenant namespaces it is not allowed
+// to record events in. The namespace of the regarding object is recorded in
+// the InvolvedNamespaceAnnotation annotation of the events.
+//
+// The core/v1 API requires the regarding object of an event to be in the
+// namespace of the event, so on clusters not serving the events.k8s.io/v1
+// API the events are still recorded in the namespace of their regarding
+// object.
+func WithEventNamespace(namespace string) KubeRecorderOption {
+	return func(r *KubeRecorder) {
+		r.namespace = namespace
+	}
+}
+
+var (
+	_ kuberecorder.EventRecorder = &KubeRecorder{}
+	_ RelatedEventRecorder       = &KubeRecorder{}
+)
+
+// seriesKey identifies the isomorphic events.k8s.io/v1 events.
+type seriesKey struct {
+	eventType string
+	reason    string
+	note      string
+	regarding corev1.ObjectReference
+	related   corev1.ObjectReference
+}
+
+// NewKubeRecorder creates a KubeRecorder for the given reporting controller.
+// The API used for recording the events is determined with the discovery API
+// of the given client, the core/v1 API being used if the events.k8s.io/v1
+// API can't be discovered.
+func NewKubeRecorder(client kubernetes.Interface, scheme *runtime.Scheme,
+	log logr.Logger, reportingController string, opts ...KubeRecorderOption) (*KubeRecorder, error) {
+	instance, err := reportingInstance()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the reporting instance: %w", err)
+	}
+
+	c := clock.RealClock{}
+	r := &KubeRecorder{
+		client:              client,
+		scheme:              scheme,
+		log:                 log,
+		reportingController: reportingController,
+		reportingInstance:   instance,
+		eventsV1:            servesEventsV1(client.Discovery()),
+		clock:               c,
+		correlator:          kuberecorder.NewEventCorrelator(c),
+		series:              make(map[seriesKey]*eventsv1.Event),
+		forbidden:           make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// servesEventsV1 returns true if the cluster serves the events.k8s.io/v1 API.
+func servesEventsV1(client discovery.DiscoveryInterface) bool {
+	_, err := client.ServerResourcesForGroupVersion(eventsv1.SchemeGroupVersion.String())
+	return err == nil
+}
+
+// reportingInstance returns the name of the controller pod from the POD_NAME
+// environment variable, falling back to the hostname.
+func reportingInstance() (string, error) {
+	if name := os.Getenv(EnvPodName); name != "" {
+		return name, nil
+	}
+	return os.Hostname()
+}
+
+// Event records an event in the Kubernetes API.
+func (r *KubeRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.AnnotatedRelatedEventf(object, nil, nil, eventtype, reason, "%s", message)
+}
+
+// Eventf records an event in the Kubernetes API.
+func (r *KubeRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedRelatedEventf(object, nil, nil, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedEventf records an event with the given annotations in the Kubernetes API.
+func (r *KubeRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string,
+	eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedRelatedEventf(object, nil, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// RelatedEventf records an event regarding the given object in the Kubernetes
+// API, referencing the related object, e.g. the source of a Kustomization.
+func (r *KubeRecorder) RelatedEventf(regarding, related runtime.Object,
+	eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedRelatedEventf(regarding, related, nil, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedRelatedEventf records an event with the given annotations regarding
+// the given object in the Kubernetes API, referencing the related object.
+// The reason is also recorded as the action of events.k8s.io/v1 events.
+func (r *KubeRecorder) AnnotatedRelatedEventf(regarding, related runtime.Object, annotations map[string]string,
+	eventtype, reason, messageFmt string, args ...interface{}) {
+	ref, err := reference.GetReference(r.scheme, regarding)
+	if err != nil {
+		r.log.Error(err, "failed to get object reference")
+		return
+	}
+	log := r.log.WithValues("name", ref.Name, "namespace", ref.Namespace, "reconciler kind", ref.Kind)
+
+	var relatedRef *corev1.ObjectReference
+	if related != nil {
+		relatedRef, err = reference.GetReference(r.scheme, related)
+		if err != nil {
+			log.Error(err, "failed to get related object reference")
+		}
+	}
+
+	if !util.ValidateEventType(eventtype) {
+		log.Error(fmt.Errorf("unsupported event type '%s'", eventtype), "unable to record Kubernetes event")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kubeEventTimeout)
+	defer cancel()
+
+	message := fmt.Sprintf(messageFmt, args...)
+	namespace := eventNamespace(ref)
+	if r.eventsV1 {
+		if r.namespace != "" && r.namespace != ref.Namespace {
+			namespace = r.namespace
+			if ref.Namespace != "" {
+				annotations = maps.Clone(annotations)
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+				annotations[InvolvedNamespaceAnnotation] = ref.Namespace
+			}
+		}
+		err = r.recordEventsV1(ctx, namespace, ref, relatedRef, annotations, eventtype, reason, message)
+	} else {
+		err = r.recordCoreV1(ctx, namespace, ref, relatedRef, annotations, eventtype, reason, message)
+	}
+	if r.isForbidden(namespace, err) {
+		return
+	}
+	if err != nil {
+		log.Error(err, "unable to record Kubernetes event")
+	}
+}
+
+// isForbidden returns true if the given error of recording an event in the
+// given namespace is a permission denial. The first denial in a namespace is
+// logged once, the following ones are not logged until an event is
+// recorded in the namespace, as the missing permissions would otherwise be
+// reported by every event.
+func (r *KubeRecorder) isForbidden(namespace string, err error) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !apierrors.IsForbidden(err) {
+		if err == nil {
+			delete(r.forbidden, namespace)
+		}
+		return false
+	}
+	if !r.forbidden[namespace] {
+		r.forbidden[namespace] = true
+		r.log.Info("not allowed to record Kubernetes events in namespace, "+
+			"the events are dropped until the permissions are granted",
+			"namespace", namespace, "error", err.Error())
+	}
+	return true
+}
+
+// recordEventsV1 records the event with the events.k8s.io/v1 API, patching the
+// series of the isomorphic event recorded within the series tolerance if any.
+func (r *KubeRecorder) recordEventsV1(ctx context.Context, namespace string, regarding, related *corev1.ObjectReference,
+	annotations map[string]string, eventtype, reason, message string) error {
+	now := r.clock.Now()
+	event := &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        util.GenerateEventName(regarding.Name, now.UnixNano()),
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		EventTime:           metav1.NewMicroTime(now),
+		ReportingController: r.reportingController,
+		ReportingInstance:   r.reportingInstance,
+		Action:              reason,
+		Reason:              reason,
+		Regarding:           *regarding,
+		Related:             related,
+		Note:                truncateNote(message),
+		Type:                eventtype,
+	}
+
+	key := seriesKey{
+		eventType: event.Type,
+		reason:    event.Reason,
+		note:      event.Note,
+		regarding: event.Regarding,
+	}
+	if related != nil {
+		key.related = *related
+	}
+
+	event = r.observeSeries(key, event, now)
+	events := r.client.EventsV1().Events(event.Namespace)
+	if event.Series != nil {
+		patch, err := json.Marshal(map[string]any{"series": event.Series})
+		if err != nil {
+			return fmt.Errorf("failed to marshal the event series: %w", err)
+		}
+		_, err = events.Patch(ctx, event.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err == nil || !apierrors.IsNotFound(err) {
+			return err
+		}
+		// The event was deleted, record the series in a new event.
+		event.ResourceVersion = ""
+	}
+	_, err := events.Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
+
+// observeSeries returns the event to record, i.e. a copy of the isomorphic
+// event with its series incremented if any, or the given event.
+func (r *KubeRecorder) observeSeries(key seriesKey, event *eventsv1.Event, now time.Time) *eventsv1.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, e := range r.series {
+		lastObserved := e.EventTime.Time
+		if e.Series != nil {
+			lastObserved = e.Series.LastObservedTime.Time
+		}
+		if now.Sub(lastObserved) > eventSeriesTolerance {
+			delete(r.series, k)
+		}
+	}
+
+	isomorphic, ok := r.series[key]
+	if !ok {
+		r.series[key] = event.DeepCopy()
+		return event
+	}
+	if isomorphic.Series == nil {
+		isomorphic.Series = &eventsv1.EventSeries{Count: 1}
+	}
+	isomorphic.Series.Count++
+	isomorphic.Series.LastObservedTime = metav1.NewMicroTime(now)
+	return isomorphic.DeepCopy()
+}
+
+// recordCoreV1 records the event with the core/v1 API, aggregating and
+// rate limiting the events with the client-go event correlator.
+func (r *KubeRecorder) recordCoreV1(ctx context.Context, namespace string, involved, related *corev1.ObjectReference,
+	annotations map[string]string, eventtype, reason, message string) error {
+	now := metav1.NewTime(r.clock.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        util.GenerateEventName(involved.Name, now.UnixNano()),
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		InvolvedObject:      *involved,
+		Related:             related,
+		Reason:              reason,
+		Message:             message,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+		Type:                eventtype,
+		Source:              corev1.EventSource{Component: r.reportingController},
+		ReportingController: r.reportingController,
+		ReportingInstance:   r.reportingInstance,
+	}
+
+	result, err := r.correlator.EventCorrelate(event)
+	if err != nil {
+		return err
+	}
+	if result.Skip {
+		return nil
+	}
+	event = result.Event
+
+	events := r.client.CoreV1().Events(event.Namespace)
+	var recorded *corev1.Event
+	if event.Count > 1 {
+		recorded, err = events.Patch(ctx, event.Name, types.StrategicMergePatchType, result.Patch, metav1.PatchOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	if recorded == nil {
+		// Making sure that ResourceVersion is empty on creation.
+		event.ResourceVersion = ""
+		recorded, err = events.Create(ctx, event, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+	}
+	r.correlator.UpdateState(recorded)
+	return nil
+}
+
+// eventNamespace returns the namespace of the events regarding the given
+// object, i.e. the default namespace for cluster-scoped objects.
+func eventNamespace(ref *corev1.ObjectReference) string {
+	if ref.Namespace == "" {
+		return metav1.NamespaceDefault
+	}
+	return ref.Namespace
+}
+
+// truncateNote truncates the given note to the maximum length accepted by the
+// events.k8s.io/v1 API, without splitting a UTF-8 character.
+func truncateNote(note string) string {
+	if len(note) <= eventNoteLimit {
+		return note
+	}
+	const ellipsis = "..."
+	n := eventNoteLimit - len(ellipsis)
+	for n > 0 && !utf8.RuneStart(note[n]) {
+		n--
+	}
+	return note[:n] + ellipsis
+}
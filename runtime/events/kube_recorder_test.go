@@ -0,0 +1,305 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// newFakeClientset returns a fake clientset serving the events.k8s.io/v1
+// API if eventsV1 is true.
+func newFakeClientset(eventsV1 bool) *fake.Clientset {
+	clientset := fake.NewClientset()
+	if eventsV1 {
+		clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: eventsv1.SchemeGroupVersion.String(),
+				APIResources: []metav1.APIResource{{Name: "events", Namespaced: true, Kind: "Event"}},
+			},
+		}
+	}
+	return clientset
+}
+
+func newKubeRecorderTestObjects() (*runtime.Scheme, *corev1.ConfigMap, *corev1.Secret) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	regarding := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapp", Namespace: "gitops-system", UID: "regarding-uid"},
+	}
+	related := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapp-source", Namespace: "flux-system", UID: "related-uid"},
+	}
+	return scheme, regarding, related
+}
+
+func TestKubeRecorder_EventsV1(t *testing.T) {
+	t.Setenv(EnvPodName, "test-controller-7d9f8b6c4-x2x9z")
+	scheme, regarding, related := newKubeRecorderTestObjects()
+
+	clientset := newFakeClientset(true)
+	recorder, err := NewKubeRecorder(clientset, scheme, ctrl.Log, "test-controller")
+	require.NoError(t, err)
+	require.True(t, recorder.eventsV1)
+
+	annotations := map[string]string{"event.toolkit.fluxcd.io/revision": "main@sha1:a1b2c3"}
+	recorder.AnnotatedRelatedEventf(regarding, related, annotations, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied %s", "revision")
+
+	list, err := clientset.EventsV1().Events("gitops-system").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	event := list.Items[0]
+	require.Equal(t, "test-controller", event.ReportingController)
+	require.Equal(t, "test-controller-7d9f8b6c4-x2x9z", event.ReportingInstance)
+	require.Equal(t, "ReconciliationSucceeded", event.Reason)
+	require.Equal(t, "ReconciliationSucceeded", event.Action)
+	require.Equal(t, "applied revision", event.Note)
+	require.Equal(t, corev1.EventTypeNormal, event.Type)
+	require.Equal(t, annotations, event.Annotations)
+	require.False(t, event.EventTime.IsZero())
+	require.Nil(t, event.Series)
+	require.Equal(t, "ConfigMap", event.Regarding.Kind)
+	require.Equal(t, "webapp", event.Regarding.Name)
+	require.Equal(t, "gitops-system", event.Regarding.Namespace)
+	require.NotNil(t, event.Related)
+	require.Equal(t, "Secret", event.Related.Kind)
+	require.Equal(t, "webapp-source", event.Related.Name)
+	require.Equal(t, "flux-system", event.Related.Namespace)
+
+	// An isomorphic event increments the series of the recorded event.
+	recorder.AnnotatedRelatedEventf(regarding, related, annotations, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied %s", "revision")
+	recorder.AnnotatedRelatedEventf(regarding, related, annotations, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied %s", "revision")
+	list, err = clientset.EventsV1().Events("gitops-system").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.NotNil(t, list.Items[0].Series)
+	require.Equal(t, int32(3), list.Items[0].Series.Count)
+
+	// An event with another note is a new event.
+	recorder.Eventf(regarding, corev1.EventTypeWarning, "ReconciliationFailed", "failed to apply revision")
+	list, err = clientset.EventsV1().Events("gitops-system").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+
+	coreList, err := clientset.CoreV1().Events("").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, coreList.Items)
+}
+
+func TestKubeRecorder_EventsV1_TruncateNote(t *testing.T) {
+	scheme, regarding, _ := newKubeRecorderTestObjects()
+
+	clientset := newFakeClientset(true)
+	recorder, err := NewKubeRecorder(clientset, scheme, ctrl.Log, "test-controller")
+	require.NoError(t, err)
+
+	recorder.Event(regarding, corev1.EventTypeWarning, "ReconciliationFailed", strings.Repeat("é", eventNoteLimit))
+
+	list, err := clientset.EventsV1().Events("gitops-system").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	note := list.Items[0].Note
+	require.LessOrEqual(t, len(note), eventNoteLimit)
+	require.True(t, strings.HasSuffix(note, "é..."))
+}
+
+func TestKubeRecorder_CoreV1(t *testing.T) {
+	t.Setenv(EnvPodName, "test-controller-7d9f8b6c4-x2x9z")
+	scheme, regarding, related := newKubeRecorderTestObjects()
+
+	clientset := newFakeClientset(false)
+	recorder, err := NewKubeRecorder(clientset, scheme, ctrl.Log, "test-controller")
+	require.NoError(t, err)
+	require.False(t, recorder.eventsV1)
+
+	annotations := map[string]string{"event.toolkit.fluxcd.io/revision": "main@sha1:a1b2c3"}
+	recorder.AnnotatedRelatedEventf(regarding, related, annotations, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied %s", "revision")
+
+	list, err := clientset.CoreV1().Events("gitops-system").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	event := list.Items[0]
+	require.Equal(t, "test-controller", event.ReportingController)
+	require.Equal(t, "test-controller-7d9f8b6c4-x2x9z", event.ReportingInstance)
+	require.Equal(t, "test-controller", event.Source.Component)
+	require.Equal(t, "ReconciliationSucceeded", event.Reason)
+	require.Equal(t, "applied revision", event.Message)
+	require.Equal(t, corev1.EventTypeNormal, event.Type)
+	require.Equal(t, annotations, event.Annotations)
+	require.Equal(t, int32(1), event.Count)
+	require.Equal(t, "ConfigMap", event.InvolvedObject.Kind)
+	require.Equal(t, "webapp", event.InvolvedObject.Name)
+	require.Equal(t, "gitops-system", event.InvolvedObject.Namespace)
+	require.NotNil(t, event.Related)
+	require.Equal(t, "Secret", event.Related.Kind)
+	require.Equal(t, "webapp-source", event.Related.Name)
+	require.Equal(t, "flux-system", event.Related.Namespace)
+
+	// An identical event increments the count of the recorded event.
+	recorder.AnnotatedRelatedEventf(regarding, related, annotations, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied %s", "revision")
+	list, err = clientset.CoreV1().Events("gitops-system").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, int32(2), list.Items[0].Count)
+
+	v1List, err := clientset.EventsV1().Events("").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, v1List.Items)
+}
+
+func TestKubeRecorder_EventNamespace(t *testing.T) {
+	scheme, regarding, related := newKubeRecorderTestObjects()
+	clusterScoped := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant", UID: "namespace-uid"}}
+	sameNamespace := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "flux", Namespace: "flux-system", UID: "same-uid"}}
+
+	t.Run("events.k8s.io/v1", func(t *testing.T) {
+		clientset := newFakeClientset(true)
+		recorder, err := NewKubeRecorder(clientset, scheme, ctrl.Log, "test-controller", WithEventNamespace("flux-system"))
+		require.NoError(t, err)
+
+		annotations := map[string]string{"event.toolkit.fluxcd.io/revision": "main@sha1:a1b2c3"}
+		recorder.AnnotatedRelatedEventf(regarding, related, annotations, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision")
+		recorder.Event(clusterScoped, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision")
+		recorder.Event(sameNamespace, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision")
+		require.Len(t, annotations, 1)
+
+		list, err := clientset.EventsV1().Events("").List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, list.Items, 3)
+		events := make(map[string]eventsv1.Event)
+		for _, event := range list.Items {
+			require.Equal(t, "flux-system", event.Namespace)
+			events[event.Regarding.Name] = event
+		}
+
+		// The namespace of the regarding object is kept in the reference
+		// and the annotations.
+		require.Equal(t, "gitops-system", events["webapp"].Regarding.Namespace)
+		require.Equal(t, map[string]string{
+			"event.toolkit.fluxcd.io/revision": "main@sha1:a1b2c3",
+			InvolvedNamespaceAnnotation:        "gitops-system",
+		}, events["webapp"].Annotations)
+		require.NotContains(t, events["tenant"].Annotations, InvolvedNamespaceAnnotation)
+		require.NotContains(t, events["flux"].Annotations, InvolvedNamespaceAnnotation)
+	})
+
+	t.Run("core/v1", func(t *testing.T) {
+		clientset := newFakeClientset(false)
+		recorder, err := NewKubeRecorder(clientset, scheme, ctrl.Log, "test-controller", WithEventNamespace("flux-system"))
+		require.NoError(t, err)
+
+		recorder.Event(regarding, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision")
+
+		// The core/v1 events are recorded in the namespace of the involved object.
+		list, err := clientset.CoreV1().Events("").List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		require.Equal(t, "gitops-system", list.Items[0].Namespace)
+		require.NotContains(t, list.Items[0].Annotations, InvolvedNamespaceAnnotation)
+	})
+}
+
+func TestKubeRecorder_Forbidden(t *testing.T) {
+	scheme, regarding, _ := newKubeRecorderTestObjects()
+
+	var forbidden atomic.Bool
+	forbidden.Store(true)
+	clientset := newFakeClientset(true)
+	clientset.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if !forbidden.Load() {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewForbidden(eventsv1.Resource("events"), "", errors.New("RBAC: access denied"))
+	})
+
+	var logs []string
+	log := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{})
+	recorder, err := NewKubeRecorder(clientset, scheme, log, "test-controller")
+	require.NoError(t, err)
+
+	// The denials are logged once.
+	for i := range 3 {
+		recorder.Eventf(regarding, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision %d", i)
+	}
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "not allowed to record Kubernetes events in namespace")
+	require.Contains(t, logs[0], `"namespace"="gitops-system"`)
+	require.Contains(t, logs[0], "RBAC: access denied")
+
+	// The denials are logged again once an event was recorded.
+	forbidden.Store(false)
+	recorder.Eventf(regarding, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision %d", 3)
+	forbidden.Store(true)
+	recorder.Eventf(regarding, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision %d", 4)
+	recorder.Eventf(regarding, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision %d", 5)
+	require.Len(t, logs, 2)
+
+	list, err := clientset.EventsV1().Events("gitops-system").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+}
+
+func TestKubeRecorder_ReportingInstance(t *testing.T) {
+	scheme, _, _ := newKubeRecorderTestObjects()
+
+	t.Setenv(EnvPodName, "")
+	recorder, err := NewKubeRecorder(newFakeClientset(true), scheme, ctrl.Log, "test-controller")
+	require.NoError(t, err)
+	require.NotEmpty(t, recorder.reportingInstance)
+
+	t.Setenv(EnvPodName, "test-controller-7d9f8b6c4-x2x9z")
+	recorder, err = NewKubeRecorder(newFakeClientset(true), scheme, ctrl.Log, "test-controller")
+	require.NoError(t, err)
+	require.Equal(t, "test-controller-7d9f8b6c4-x2x9z", recorder.reportingInstance)
+}
+
+func TestEventRecorder_RelatedEventf(t *testing.T) {
+	scheme, regarding, related := newKubeRecorderTestObjects()
+
+	clientset := newFakeClientset(true)
+	kubeRecorder, err := NewKubeRecorder(clientset, scheme, ctrl.Log, "test-controller")
+	require.NoError(t, err)
+	eventRecorder, err := NewRecorderForScheme(scheme, kubeRecorder, ctrl.Log, "", "test-controller")
+	require.NoError(t, err)
+
+	eventRecorder.RelatedEventf(regarding, related, corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision")
+
+	list, err := clientset.EventsV1().Events("gitops-system").List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.NotNil(t, list.Items[0].Related)
+	require.Equal(t, "webapp-source", list.Items[0].Related.Name)
+}
@@ -17,14 +17,15 @@ limitations under the License.
 package events
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	kuberecorder "k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -77,20 +79,55 @@ type Recorder struct {
 
 	// Log is the recorder logger.
 	Log logr.Logger
+
+	// KubernetesMinSeverity is the minimum severity (trace, info, error) of
+	// the events recorded in the Kubernetes API. Defaults to all severities.
+	KubernetesMinSeverity string
+
+	// WebhookMinSeverity is the minimum severity (info, error) of the events
+	// posted to the webhook address. Defaults to info, trace events are never
+	// posted to the webhook.
+	WebhookMinSeverity string
+
+	// SeverityEventTypes overrides the mapping of event severities to
+	// Kubernetes event types, see DefaultSeverityEventTypes.
+	SeverityEventTypes map[string]string
+
+	// Sinks forwards the events to additional destinations, e.g. a FileSink.
+	// Trace events are never forwarded to the sinks.
+	Sinks *Broadcaster
+
+	// Spool holds the events that could not be posted to the webhook address,
+	// replayed by ReplaySpool. If nil, these events are dropped.
+	Spool *Spool
 }
 
-var _ kuberecorder.EventRecorder = &Recorder{}
+var (
+	_ kuberecorder.EventRecorder = &Recorder{}
+	_ RelatedEventRecorder       = &Recorder{}
+)
 
 // NewRecorder creates an event Recorder with a Kubernetes event recorder and an external event recorder based on the
 // given webhook. The recorder performs automatic retries for connection errors and 500-range response codes from the
-// external recorder.
-func NewRecorder(mgr ctrl.Manager, log logr.Logger, webhook, reportingController string) (*Recorder, error) {
+// external recorder. The Kubernetes events are recorded with a KubeRecorder
+// configured with the given options.
+func NewRecorder(mgr ctrl.Manager, log logr.Logger, webhook, reportingController string,
+	opts ...KubeRecorderOption) (*Recorder, error) {
 	if webhook != "" {
 		if _, err := url.Parse(webhook); err != nil {
 			return nil, err
 		}
 	}
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Kubernetes client: %w", err)
+	}
+	eventRecorder, err := NewKubeRecorder(clientset, mgr.GetScheme(), log, reportingController, opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	httpClient := retryablehttp.NewClient()
 	httpClient.HTTPClient.Timeout = 5 * time.Second
 	httpClient.CheckRetry = checkRetry
@@ -101,7 +138,7 @@ func NewRecorder(mgr ctrl.Manager, log logr.Logger, webhook, reportingController
 		Webhook:             webhook,
 		ReportingController: reportingController,
 		Client:              httpClient,
-		EventRecorder:       mgr.GetEventRecorderFor(reportingController),
+		EventRecorder:       eventRecorder,
 		Log:                 log,
 	}, nil
 }
@@ -157,13 +194,30 @@ func (r *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt s
 	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
 }
 
-// AnnotatedEventf constructs an event from the given information and performs a HTTP POST to the webhook address.
+// AnnotatedEventf constructs an event from the given information, performs a HTTP POST to the webhook address and
+// writes it to the registered sinks.
 // It also logs the event if debug logs are enabled in the logger.
 func (r *Recorder) AnnotatedEventf(
 	object runtime.Object,
 	inputAnnotations map[string]string,
 	eventtype, reason string,
 	messageFmt string, args ...interface{}) {
+	r.AnnotatedRelatedEventf(object, nil, inputAnnotations, eventtype, reason, messageFmt, args...)
+}
+
+// RelatedEventf records an event like Eventf, referencing the related object in the Kubernetes event,
+// e.g. the source of a Kustomization.
+func (r *Recorder) RelatedEventf(object, related runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedRelatedEventf(object, related, nil, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedRelatedEventf records an event like AnnotatedEventf, referencing the related object in the Kubernetes
+// event if the Kubernetes event recorder is a RelatedEventRecorder. The related object can be nil.
+func (r *Recorder) AnnotatedRelatedEventf(
+	object, related runtime.Object,
+	inputAnnotations map[string]string,
+	eventtype, reason string,
+	messageFmt string, args ...interface{}) {
 
 	ref, err := reference.GetReference(r.Scheme, object)
 	if err != nil {
@@ -199,23 +253,37 @@ func (r *Recorder) AnnotatedEventf(
 	// Convert the eventType to severity.
 	severity := eventTypeToSeverity(eventtype)
 
+	// Forward the event to the Kubernetes recorder if it meets the threshold.
+	if meetsSeverity(severity, r.KubernetesMinSeverity) {
+		k8sEventType := severityToEventType(severity, r.SeverityEventTypes)
+		if relatedRecorder, ok := r.EventRecorder.(RelatedEventRecorder); ok {
+			relatedRecorder.AnnotatedRelatedEventf(object, related, annotations, k8sEventType, reason, messageFmt, args...)
+		} else {
+			r.EventRecorder.AnnotatedEventf(object, annotations, k8sEventType, reason, messageFmt, args...)
+		}
+	} else {
+		filteredEventsCounter.WithLabelValues(SinkKubernetes, severity).Inc()
+	}
+
 	// Do not send trace events to notification controller,
-	// traces are persisted as Kubernetes events only as normal events.
+	// traces are persisted as Kubernetes events only.
 	if severity == eventv1.EventSeverityTrace {
-		r.EventRecorder.AnnotatedEventf(object, annotations, corev1.EventTypeNormal, reason, messageFmt, args...)
 		return
 	}
 
-	// Forward the event to the Kubernetes recorder.
-	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
-
 	// If no webhook address is provided, skip posting to event recorder
 	// endpoint.
-	if r.Webhook == "" {
+	postWebhook := r.Webhook != ""
+	if postWebhook && !meetsSeverity(severity, r.WebhookMinSeverity) {
+		filteredEventsCounter.WithLabelValues(SinkWebhook, severity).Inc()
+		postWebhook = false
+	}
+
+	if !postWebhook && r.Sinks.Len() == 0 {
 		return
 	}
 
-	if r.Client == nil {
+	if postWebhook && r.Client == nil {
 		err := fmt.Errorf("retryable HTTP client has not been initialized")
 		log.Error(err, "unable to record event")
 		return
@@ -241,9 +309,9 @@ func (r *Recorder) AnnotatedEventf(
 		return
 	}
 
-	hostname, err := os.Hostname()
+	instance, err := reportingInstance()
 	if err != nil {
-		log.Error(err, "failed to get hostname")
+		log.Error(err, "failed to get the reporting instance")
 		return
 	}
 
@@ -255,7 +323,15 @@ func (r *Recorder) AnnotatedEventf(
 		Reason:              reason,
 		Metadata:            annotations,
 		ReportingController: r.ReportingController,
-		ReportingInstance:   hostname,
+		ReportingInstance:   instance,
+	}
+
+	if err := r.Sinks.Broadcast(event); err != nil {
+		log.Error(err, "unable to record event")
+	}
+
+	if !postWebhook {
+		return
 	}
 
 	body, err := json.Marshal(event)
@@ -264,12 +340,63 @@ func (r *Recorder) AnnotatedEventf(
 		return
 	}
 
+	// Spool the event after the spooled events of the object, to preserve
+	// their ordering.
+	if r.Spool != nil && r.Spool.Pending(event.InvolvedObject) {
+		if err := r.Spool.Append(event); err != nil {
+			log.Error(err, "unable to spool event")
+		}
+		return
+	}
+
 	if _, err := r.Client.Post(r.Webhook, "application/json", body); err != nil {
-		log.Error(err, "unable to record event")
+		if r.Spool == nil {
+			log.Error(err, "unable to record event")
+			return
+		}
+		if spoolErr := r.Spool.Append(event); spoolErr != nil {
+			log.Error(errors.Join(err, spoolErr), "unable to record event")
+		}
 		return
 	}
 }
 
+// ReplaySpool replays the events of the Spool to the webhook address until
+// the context is canceled. It returns immediately if the Recorder has no
+// Spool or webhook address. It can be run by the manager with
+// manager.RunnableFunc(recorder.ReplaySpool).
+func (r *Recorder) ReplaySpool(ctx context.Context) error {
+	if r.Spool == nil || r.Webhook == "" {
+		return nil
+	}
+	if r.Client == nil {
+		return fmt.Errorf("retryable HTTP client has not been initialized")
+	}
+	return r.Spool.Replay(ctx, r.postSpooled)
+}
+
+// postSpooled posts the payload of a spooled event to the webhook address
+// without the retries of the HTTP client, as the replays of the spool are
+// retried with its own backoff.
+func (r *Recorder) postSpooled(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Webhook, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.Client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // eventTypeToSeverity maps the given eventType string to a GOTK event severity
 // type.
 func eventTypeToSeverity(eventType string) string {
@@ -23,11 +23,15 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	crtlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 )
@@ -191,3 +195,202 @@ func TestEventRecorder_Webhook(t *testing.T) {
 	_, err = NewRecorder(env, ctrl.Log, "http://example.com", "test-controller")
 	require.NoError(t, err)
 }
+
+func TestEventRecorder_KubernetesMinSeverity(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		minSeverity    string
+		eventType      string
+		expectRecorded bool
+		expectedEvent  string
+	}{
+		{
+			name:           "trace recorded by default",
+			eventType:      eventv1.EventTypeTrace,
+			expectRecorded: true,
+			expectedEvent:  "Normal sync sync webapp",
+		},
+		{
+			name:           "trace filtered with info threshold",
+			minSeverity:    eventv1.EventSeverityInfo,
+			eventType:      eventv1.EventTypeTrace,
+			expectRecorded: false,
+		},
+		{
+			name:           "info recorded with info threshold",
+			minSeverity:    eventv1.EventSeverityInfo,
+			eventType:      corev1.EventTypeNormal,
+			expectRecorded: true,
+			expectedEvent:  "Normal sync sync webapp",
+		},
+		{
+			name:           "info filtered with error threshold",
+			minSeverity:    eventv1.EventSeverityError,
+			eventType:      corev1.EventTypeNormal,
+			expectRecorded: false,
+		},
+		{
+			name:           "error recorded with error threshold",
+			minSeverity:    eventv1.EventSeverityError,
+			eventType:      corev1.EventTypeWarning,
+			expectRecorded: true,
+			expectedEvent:  "Warning sync sync webapp",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			requestCount := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestCount++
+			}))
+			defer ts.Close()
+
+			kubeRecorder := record.NewFakeRecorder(10)
+			eventRecorder, err := NewRecorderForScheme(env.GetScheme(), kubeRecorder, ctrl.Log, ts.URL, "test-controller")
+			require.NoError(t, err)
+			eventRecorder.KubernetesMinSeverity = tt.minSeverity
+
+			obj := &corev1.ConfigMap{}
+			obj.Namespace = "gitops-system"
+			obj.Name = "webapp"
+
+			severity := eventTypeToSeverity(tt.eventType)
+			filtered := testutil.ToFloat64(filteredEventsCounter.WithLabelValues(SinkKubernetes, severity))
+
+			eventRecorder.AnnotatedEventf(obj, nil, tt.eventType, "sync", "sync %s", obj.Name)
+
+			if tt.expectRecorded {
+				require.Len(t, kubeRecorder.Events, 1)
+				require.Equal(t, tt.expectedEvent, <-kubeRecorder.Events)
+				require.Equal(t, filtered, testutil.ToFloat64(filteredEventsCounter.WithLabelValues(SinkKubernetes, severity)))
+			} else {
+				require.Empty(t, kubeRecorder.Events)
+				require.Equal(t, filtered+1, testutil.ToFloat64(filteredEventsCounter.WithLabelValues(SinkKubernetes, severity)))
+			}
+
+			// The webhook sink is not affected by the Kubernetes threshold.
+			if severity == eventv1.EventSeverityTrace {
+				require.Equal(t, 0, requestCount)
+			} else {
+				require.Equal(t, 1, requestCount)
+			}
+		})
+	}
+}
+
+func TestEventRecorder_WebhookMinSeverity(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		minSeverity   string
+		eventType     string
+		expectPosted  bool
+		expectCounted bool
+	}{
+		{
+			name:         "info posted by default",
+			eventType:    corev1.EventTypeNormal,
+			expectPosted: true,
+		},
+		{
+			name:         "trace never posted",
+			minSeverity:  eventv1.EventSeverityTrace,
+			eventType:    eventv1.EventTypeTrace,
+			expectPosted: false,
+		},
+		{
+			name:          "info filtered with error threshold",
+			minSeverity:   eventv1.EventSeverityError,
+			eventType:     corev1.EventTypeNormal,
+			expectPosted:  false,
+			expectCounted: true,
+		},
+		{
+			name:         "error posted with error threshold",
+			minSeverity:  eventv1.EventSeverityError,
+			eventType:    corev1.EventTypeWarning,
+			expectPosted: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			requestCount := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestCount++
+			}))
+			defer ts.Close()
+
+			kubeRecorder := record.NewFakeRecorder(10)
+			eventRecorder, err := NewRecorderForScheme(env.GetScheme(), kubeRecorder, ctrl.Log, ts.URL, "test-controller")
+			require.NoError(t, err)
+			eventRecorder.WebhookMinSeverity = tt.minSeverity
+
+			obj := &corev1.ConfigMap{}
+			obj.Namespace = "gitops-system"
+			obj.Name = "webapp"
+
+			severity := eventTypeToSeverity(tt.eventType)
+			filtered := testutil.ToFloat64(filteredEventsCounter.WithLabelValues(SinkWebhook, severity))
+
+			eventRecorder.AnnotatedEventf(obj, nil, tt.eventType, "sync", "sync %s", obj.Name)
+
+			if tt.expectPosted {
+				require.Equal(t, 1, requestCount)
+			} else {
+				require.Equal(t, 0, requestCount)
+			}
+			if tt.expectCounted {
+				require.Equal(t, filtered+1, testutil.ToFloat64(filteredEventsCounter.WithLabelValues(SinkWebhook, severity)))
+			} else {
+				require.Equal(t, filtered, testutil.ToFloat64(filteredEventsCounter.WithLabelValues(SinkWebhook, severity)))
+			}
+
+			// The Kubernetes sink is not affected by the webhook threshold.
+			require.Len(t, kubeRecorder.Events, 1)
+		})
+	}
+}
+
+func TestEventRecorder_SeverityEventTypes(t *testing.T) {
+	kubeRecorder := record.NewFakeRecorder(10)
+	eventRecorder, err := NewRecorderForScheme(env.GetScheme(), kubeRecorder, ctrl.Log, "", "test-controller")
+	require.NoError(t, err)
+	eventRecorder.SeverityEventTypes = map[string]string{
+		eventv1.EventSeverityTrace: corev1.EventTypeWarning,
+	}
+
+	obj := &corev1.ConfigMap{}
+	obj.Namespace = "gitops-system"
+	obj.Name = "webapp"
+
+	eventRecorder.AnnotatedEventf(obj, nil, eventv1.EventTypeTrace, "sync", "sync %s", obj.Name)
+	require.Equal(t, "Warning sync sync webapp", <-kubeRecorder.Events)
+
+	// Severities without an override fall back to the defaults.
+	eventRecorder.AnnotatedEventf(obj, nil, corev1.EventTypeWarning, "sync", "sync %s", obj.Name)
+	require.Equal(t, "Warning sync sync webapp", <-kubeRecorder.Events)
+	eventRecorder.AnnotatedEventf(obj, nil, corev1.EventTypeNormal, "sync", "sync %s", obj.Name)
+	require.Equal(t, "Normal sync sync webapp", <-kubeRecorder.Events)
+}
+
+func TestDefaultSeverityEventTypes(t *testing.T) {
+	defaults := DefaultSeverityEventTypes()
+	require.Equal(t, corev1.EventTypeWarning, defaults[eventv1.EventSeverityError])
+
+	// Modifying the returned map does not change the defaults.
+	defaults[eventv1.EventSeverityError] = corev1.EventTypeNormal
+	require.Equal(t, corev1.EventTypeWarning, DefaultSeverityEventTypes()[eventv1.EventSeverityError])
+	require.Equal(t, corev1.EventTypeWarning, severityToEventType(eventv1.EventSeverityError, nil))
+}
+
+func TestCollectors(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	for _, c := range Collectors() {
+		require.NoError(t, reg.Register(c))
+	}
+
+	// The collectors are registered explicitly, not at import.
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	for _, c := range []prometheus.Collector{filteredEventsCounter, spoolDroppedCounter, spoolCorruptedCounter} {
+		require.ErrorAs(t, reg.Register(c), &alreadyRegistered)
+		require.NoError(t, crtlmetrics.Registry.Register(c))
+		crtlmetrics.Registry.Unregister(c)
+	}
+}
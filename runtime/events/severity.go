@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"maps"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+const (
+	// SinkKubernetes is the metrics label value for the Kubernetes events sink.
+	SinkKubernetes = "kubernetes"
+	// SinkWebhook is the metrics label value for the external webhook sink.
+	SinkWebhook = "webhook"
+	// SinkFile is the metrics label value for the file events sink.
+	SinkFile = "file"
+)
+
+// defaultSeverityEventTypes maps the GOTK event severities to the Kubernetes
+// event types used when recording events in the Kubernetes API.
+var defaultSeverityEventTypes = map[string]string{
+	eventv1.EventSeverityTrace: corev1.EventTypeNormal,
+	eventv1.EventSeverityInfo:  corev1.EventTypeNormal,
+	eventv1.EventSeverityError: corev1.EventTypeWarning,
+}
+
+// DefaultSeverityEventTypes returns a copy of the mapping of the GOTK event
+// severities to the Kubernetes event types used when recording events in the
+// Kubernetes API.
+func DefaultSeverityEventTypes() map[string]string {
+	return maps.Clone(defaultSeverityEventTypes)
+}
+
+// filteredEventsCounter counts the events dropped by a sink because their
+// severity was below the configured minimum.
+var filteredEventsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gotk_event_filtered_total",
+		Help: "The total number of events filtered out by severity, per sink.",
+	},
+	[]string{"sink", "severity"},
+)
+
+// Collectors returns a slice of Prometheus collectors of the metrics recorded
+// by the event recorders and sinks, which can be used to register them in a
+// metrics registry, e.g. crtlmetrics.Registry.MustRegister(events.Collectors()...).
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		filteredEventsCounter,
+		spoolDroppedCounter,
+		spoolCorruptedCounter,
+	}
+}
+
+// severityRank returns the ordering rank of the given severity, where a
+// higher rank means a more severe event. Unknown severities rank as info.
+func severityRank(severity string) int {
+	switch severity {
+	case eventv1.EventSeverityTrace:
+		return 0
+	case eventv1.EventSeverityError:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// meetsSeverity returns true if the given severity is equal to or more severe
+// than the minimum severity. An empty minimum accepts all severities.
+func meetsSeverity(severity, minSeverity string) bool {
+	if minSeverity == "" {
+		return true
+	}
+	return severityRank(severity) >= severityRank(minSeverity)
+}
+
+// severityToEventType maps the given severity to a Kubernetes event type using
+// the overrides, falling back to the default severity event types.
+func severityToEventType(severity string, overrides map[string]string) string {
+	if eventType, ok := overrides[severity]; ok && eventType != "" {
+		return eventType
+	}
+	if eventType, ok := defaultSeverityEventTypes[severity]; ok {
+		return eventType
+	}
+	return corev1.EventTypeNormal
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+// Sink is a destination of the events emitted by a Recorder, in addition to
+// the Kubernetes API and the webhook address.
+type Sink interface {
+	// Name returns the name of the sink, used in logs and metrics.
+	Name() string
+
+	// Write records the given event. It must be safe for concurrent use,
+	// and is responsible for filtering the events by severity.
+	Write(event eventv1.Event) error
+}
+
+// Broadcaster forwards the events of a Recorder to all the registered sinks.
+// The zero value is ready to use, and a nil Broadcaster has no sinks.
+type Broadcaster struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+// NewBroadcaster returns a Broadcaster with the given sinks registered.
+func NewBroadcaster(sinks ...Sink) *Broadcaster {
+	return &Broadcaster{sinks: sinks}
+}
+
+// Register adds the given sink to the broadcaster.
+func (b *Broadcaster) Register(sink Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// Len returns the number of registered sinks.
+func (b *Broadcaster) Len() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.sinks)
+}
+
+// Broadcast writes the given event to all the registered sinks. A failing
+// sink does not prevent the others from recording the event, the errors of
+// all the sinks are joined.
+func (b *Broadcaster) Broadcast(event eventv1.Event) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.RLock()
+	sinks := b.sinks
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Write(event); err != nil {
+			errs = append(errs, fmt.Errorf("%s sink: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,471 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+const (
+	// DefaultSpoolMaxSize is the default size in bytes of the events held
+	// by a Spool, after which the oldest events are dropped.
+	DefaultSpoolMaxSize int64 = 10 * 1024 * 1024
+
+	// DefaultSpoolMaxAge is the default age after which the events held by
+	// a Spool are dropped.
+	DefaultSpoolMaxAge = 24 * time.Hour
+
+	// DefaultSpoolMinBackoff is the default delay before replaying the
+	// events held by a Spool.
+	DefaultSpoolMinBackoff = time.Second
+
+	// DefaultSpoolMaxBackoff is the default maximum delay between the
+	// failed replays of the events held by a Spool.
+	DefaultSpoolMaxBackoff = 5 * time.Minute
+)
+
+const (
+	// SpoolDropReasonSize is the metrics label value for the events dropped
+	// from a Spool exceeding its maximum size.
+	SpoolDropReasonSize = "size"
+	// SpoolDropReasonAge is the metrics label value for the events dropped
+	// from a Spool for exceeding its maximum age.
+	SpoolDropReasonAge = "age"
+)
+
+// ErrSpoolClosed is returned when appending to a closed Spool.
+var ErrSpoolClosed = errors.New("spool is closed")
+
+// spoolDroppedCounter counts the events dropped from the spool before they
+// could be replayed.
+var spoolDroppedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gotk_event_spool_dropped_total",
+		Help: "The total number of spooled events dropped before being replayed, per reason.",
+	},
+	[]string{"reason"},
+)
+
+// spoolCorruptedCounter counts the records of the spool file that could not
+// be decoded.
+var spoolCorruptedCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "gotk_event_spool_corrupted_total",
+		Help: "The total number of corrupted records skipped when loading the events spool.",
+	},
+)
+
+// Spool is a bounded on-disk queue of the events that could not be posted
+// to the webhook address of a Recorder, e.g. during an outage of
+// notification-controller. The events are replayed in order with an
+// exponential backoff, and the events of an involved object with spooled
+// events are spooled after them, so that the events of each object are
+// delivered in order.
+//
+// The events are appended as JSON lines to the spool file, which is
+// compacted as they are replayed or dropped. The records of the file which
+// cannot be decoded are skipped when loading it.
+//
+// Use NewSpool to create a working Spool, set it as the Spool of a Recorder
+// and run Recorder.ReplaySpool.
+type Spool struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	now        func() time.Time
+
+	mu         sync.Mutex
+	file       *os.File
+	fileSize   int64
+	records    []spoolRecord
+	size       int64
+	pending    map[string]int
+	nextID     uint64
+	needsWrite bool
+	notify     chan struct{}
+}
+
+// spoolRecord is a line of the spool file.
+type spoolRecord struct {
+	// Key identifies the involved object of the event.
+	Key string `json:"key"`
+	// Time is the time the event was spooled.
+	Time time.Time `json:"time"`
+	// Event is the JSON payload of the event.
+	Event json.RawMessage `json:"event"`
+
+	id   uint64
+	size int64
+}
+
+// SpoolOption configures a Spool.
+type SpoolOption func(*Spool)
+
+// WithSpoolMaxSize sets the size in bytes of the spooled events after which
+// the oldest ones are dropped. Defaults to DefaultSpoolMaxSize.
+func WithSpoolMaxSize(size int64) SpoolOption {
+	return func(s *Spool) {
+		s.maxSize = size
+	}
+}
+
+// WithSpoolMaxAge sets the age after which the spooled events are dropped.
+// Defaults to DefaultSpoolMaxAge.
+func WithSpoolMaxAge(age time.Duration) SpoolOption {
+	return func(s *Spool) {
+		s.maxAge = age
+	}
+}
+
+// WithSpoolBackoff sets the delay before replaying the spooled events, which
+// doubles after each failed replay up to the given maximum. Defaults to
+// DefaultSpoolMinBackoff and DefaultSpoolMaxBackoff.
+func WithSpoolBackoff(minBackoff, maxBackoff time.Duration) SpoolOption {
+	return func(s *Spool) {
+		s.minBackoff = minBackoff
+		s.maxBackoff = maxBackoff
+	}
+}
+
+// NewSpool opens, or creates, the spool file at the given path and returns a
+// Spool holding its events. The corrupted records of an existing file are
+// skipped and counted in the gotk_event_spool_corrupted_total metric, the
+// expired ones are dropped.
+func NewSpool(path string, opts ...SpoolOption) (*Spool, error) {
+	s := &Spool{
+		path:       path,
+		maxSize:    DefaultSpoolMaxSize,
+		maxAge:     DefaultSpoolMaxAge,
+		minBackoff: DefaultSpoolMinBackoff,
+		maxBackoff: DefaultSpoolMaxBackoff,
+		now:        time.Now,
+		pending:    map[string]int{},
+		notify:     make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.maxSize <= 0 {
+		return nil, fmt.Errorf("invalid max size %d: must be positive", s.maxSize)
+	}
+	if s.maxAge <= 0 {
+		return nil, fmt.Errorf("invalid max age %s: must be positive", s.maxAge)
+	}
+	if s.minBackoff <= 0 || s.maxBackoff < s.minBackoff {
+		return nil, fmt.Errorf("invalid backoff %s to %s: must be positive and increasing", s.minBackoff, s.maxBackoff)
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Append spools the given event, dropping the oldest events if the spool
+// would exceed its maximum size.
+func (s *Spool) Append(event eventv1.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	record := spoolRecord{
+		Key:   spoolKey(event.InvolvedObject),
+		Time:  s.now(),
+		Event: payload,
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal spool record: %w", err)
+	}
+	line = append(line, '\n')
+	record.size = int64(len(line))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return ErrSpoolClosed
+	}
+	if record.size > s.maxSize {
+		spoolDroppedCounter.WithLabelValues(SpoolDropReasonSize).Inc()
+		return fmt.Errorf("event of %d bytes exceeds the spool max size of %d bytes", record.size, s.maxSize)
+	}
+
+	s.dropExpired()
+	for len(s.records) > 0 && s.size+record.size > s.maxSize {
+		s.dropHead()
+		spoolDroppedCounter.WithLabelValues(SpoolDropReasonSize).Inc()
+	}
+	// The dropped records remain in the file until it is compacted.
+	if s.needsWrite && s.fileSize+record.size > 2*s.maxSize {
+		if err := s.compact(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.fileSize += int64(n)
+	if err != nil {
+		s.needsWrite = true
+		return fmt.Errorf("failed to write event to '%s': %w", s.path, err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync '%s': %w", s.path, err)
+	}
+	s.push(record)
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Pending returns true if the spool holds events of the given involved
+// object, in which case its new events must be spooled after them.
+func (s *Spool) Pending(ref corev1.ObjectReference) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending[spoolKey(ref)] > 0
+}
+
+// Len returns the number of spooled events.
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+// Replay posts the spooled events in order with the given function until
+// the context is canceled, waiting for the backoff delay before each
+// replay. The delay doubles after each failed replay, and is reset once all
+// the events are replayed.
+func (s *Spool) Replay(ctx context.Context, post func(ctx context.Context, payload []byte) error) error {
+	backoff := s.minBackoff
+	for {
+		if s.Len() == 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-s.notify:
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		if err := s.replay(ctx, post); err != nil {
+			backoff = min(backoff*2, s.maxBackoff)
+			continue
+		}
+		backoff = s.minBackoff
+	}
+}
+
+// Close compacts and closes the spool file. Any further Append returns
+// ErrSpoolClosed.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	var err error
+	if s.needsWrite {
+		err = s.compact()
+	}
+	err = errors.Join(err, s.file.Close())
+	s.file = nil
+	return err
+}
+
+// replay posts the spooled events in order until one fails, and removes the
+// posted ones from the spool.
+func (s *Spool) replay(ctx context.Context, post func(ctx context.Context, payload []byte) error) error {
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.file != nil && s.needsWrite {
+			_ = s.compact()
+		}
+	}()
+
+	for ctx.Err() == nil {
+		s.mu.Lock()
+		s.dropExpired()
+		if len(s.records) == 0 {
+			s.mu.Unlock()
+			return nil
+		}
+		record := s.records[0]
+		s.mu.Unlock()
+
+		if err := post(ctx, record.Event); err != nil {
+			return err
+		}
+
+		// The record may have been dropped while being posted.
+		s.mu.Lock()
+		if len(s.records) > 0 && s.records[0].id == record.id {
+			s.dropHead()
+		}
+		s.mu.Unlock()
+	}
+	return ctx.Err()
+}
+
+// load reads the records of the spool file, skipping the corrupted and
+// expired ones.
+func (s *Spool) load() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", s.path, err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var record spoolRecord
+			// An incomplete last line, e.g. after a crash in the middle of
+			// a write, is corrupted.
+			if line[len(line)-1] != '\n' || json.Unmarshal(line, &record) != nil || record.Key == "" || len(record.Event) == 0 {
+				spoolCorruptedCounter.Inc()
+				s.needsWrite = true
+			} else {
+				record.size = int64(len(line))
+				s.push(record)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read '%s': %w", s.path, err)
+		}
+	}
+	s.dropExpired()
+	return nil
+}
+
+// compact rewrites the spool file with the spooled records, and reopens it
+// in append mode.
+func (s *Spool) compact() error {
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			return fmt.Errorf("failed to close '%s': %w", s.path, err)
+		}
+		s.file = nil
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create '%s': %w", tmp, err)
+	}
+	w := bufio.NewWriter(f)
+	var size int64
+	for _, record := range s.records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to marshal spool record: %w", err)
+		}
+		n, _ := w.Write(append(line, '\n'))
+		size += int64(n)
+	}
+	if err := errors.Join(w.Flush(), f.Sync(), f.Close()); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", tmp, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace '%s': %w", s.path, err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", s.path, err)
+	}
+	s.file = file
+	s.fileSize = size
+	s.needsWrite = false
+	return nil
+}
+
+// push adds the record at the tail of the spool.
+func (s *Spool) push(record spoolRecord) {
+	s.nextID++
+	record.id = s.nextID
+	s.records = append(s.records, record)
+	s.size += record.size
+	s.pending[record.Key]++
+}
+
+// dropHead removes the record at the head of the spool.
+func (s *Spool) dropHead() {
+	record := s.records[0]
+	s.records[0] = spoolRecord{}
+	s.records = s.records[1:]
+	s.size -= record.size
+	if s.pending[record.Key]--; s.pending[record.Key] <= 0 {
+		delete(s.pending, record.Key)
+	}
+	s.needsWrite = true
+}
+
+// dropExpired removes the records older than the max age from the head of
+// the spool.
+func (s *Spool) dropExpired() {
+	for len(s.records) > 0 && s.now().Sub(s.records[0].Time) > s.maxAge {
+		s.dropHead()
+		spoolDroppedCounter.WithLabelValues(SpoolDropReasonAge).Inc()
+	}
+}
+
+// spoolKey returns the key of the involved object of an event.
+func spoolKey(ref corev1.ObjectReference) string {
+	return fmt.Sprintf("%s/%s/%s/%s", ref.APIVersion, ref.Kind, ref.Namespace, ref.Name)
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+// toggledWebhook is a webhook server failing while down, recording the
+// messages of the received events otherwise.
+type toggledWebhook struct {
+	*httptest.Server
+	down atomic.Bool
+
+	mu       sync.Mutex
+	messages []string
+}
+
+func newToggledWebhook(t *testing.T) *toggledWebhook {
+	t.Helper()
+	w := &toggledWebhook{}
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if w.down.Load() {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event eventv1.Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.messages = append(w.messages, event.Message)
+	}))
+	t.Cleanup(w.Close)
+	return w
+}
+
+func (w *toggledWebhook) received() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.messages...)
+}
+
+func TestRecorder_Spool_Outage(t *testing.T) {
+	webhook := newToggledWebhook(t)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	eventRecorder, err := NewRecorderForScheme(scheme, record.NewFakeRecorder(100), ctrl.Log, webhook.URL, "test-controller")
+	require.NoError(t, err)
+	eventRecorder.Client.RetryMax = 0
+
+	spool, err := NewSpool(filepath.Join(t.TempDir(), "spool"), WithSpoolBackoff(10*time.Millisecond, 50*time.Millisecond))
+	require.NoError(t, err)
+	defer spool.Close()
+	eventRecorder.Spool = spool
+
+	newObject := func(name string) *corev1.ConfigMap {
+		obj := &corev1.ConfigMap{}
+		obj.Namespace = "gitops-system"
+		obj.Name = name
+		return obj
+	}
+	objA, objB, objC := newObject("a"), newObject("b"), newObject("c")
+
+	// The events posted during the outage are spooled.
+	webhook.down.Store(true)
+	eventRecorder.Event(objA, corev1.EventTypeNormal, "sync", "a-1")
+	eventRecorder.Event(objB, corev1.EventTypeNormal, "sync", "b-1")
+	eventRecorder.Event(objA, corev1.EventTypeNormal, "sync", "a-2")
+	require.Equal(t, 3, spool.Len())
+	require.Empty(t, webhook.received())
+
+	// Once the webhook recovers, the events of an object with spooled events
+	// are spooled after them, the others are posted.
+	webhook.down.Store(false)
+	eventRecorder.Event(objA, corev1.EventTypeNormal, "sync", "a-3")
+	eventRecorder.Event(objC, corev1.EventTypeNormal, "sync", "c-1")
+	require.Equal(t, 4, spool.Len())
+	require.Equal(t, []string{"c-1"}, webhook.received())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- eventRecorder.ReplaySpool(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return spool.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"c-1", "a-1", "b-1", "a-2", "a-3"}, webhook.received())
+
+	// A new outage is spooled and replayed with the backoff.
+	webhook.down.Store(true)
+	eventRecorder.Event(objB, corev1.EventTypeNormal, "sync", "b-2")
+	require.Equal(t, 1, spool.Len())
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 1, spool.Len())
+	webhook.down.Store(false)
+	require.Eventually(t, func() bool {
+		return spool.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"c-1", "a-1", "b-1", "a-2", "a-3", "b-2"}, webhook.received())
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestSpool_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+
+	spool, err := NewSpool(path)
+	require.NoError(t, err)
+	for _, msg := range []string{"event-0", "event-1", "event-2"} {
+		require.NoError(t, spool.Append(newTestEvent(msg)))
+	}
+	require.NoError(t, spool.Close())
+	require.ErrorIs(t, spool.Append(newTestEvent("event-3")), ErrSpoolClosed)
+
+	// The events are replayed in order after a restart.
+	spool, err = NewSpool(path)
+	require.NoError(t, err)
+	defer spool.Close()
+	require.Equal(t, 3, spool.Len())
+	require.True(t, spool.Pending(newTestEvent("").InvolvedObject))
+
+	var messages []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = spool.replay(ctx, func(_ context.Context, payload []byte) error {
+		var event eventv1.Event
+		require.NoError(t, json.Unmarshal(payload, &event))
+		messages = append(messages, event.Message)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"event-0", "event-1", "event-2"}, messages)
+	require.False(t, spool.Pending(newTestEvent("").InvolvedObject))
+
+	// The replayed events are removed from the file.
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Zero(t, info.Size())
+}
+
+func TestSpool_Limits(t *testing.T) {
+	t.Run("drops the oldest events above the max size", func(t *testing.T) {
+		spool, err := NewSpool(filepath.Join(t.TempDir(), "spool"), WithSpoolMaxSize(1024))
+		require.NoError(t, err)
+		defer spool.Close()
+
+		dropped := testutil.ToFloat64(spoolDroppedCounter.WithLabelValues(SpoolDropReasonSize))
+		for i := range 10 {
+			require.NoError(t, spool.Append(newTestEvent(fmt.Sprintf("event-%d", i))))
+		}
+		n := spool.Len()
+		require.Less(t, n, 10)
+		require.Greater(t, n, 0)
+		require.Equal(t, dropped+float64(10-n), testutil.ToFloat64(spoolDroppedCounter.WithLabelValues(SpoolDropReasonSize)))
+		require.LessOrEqual(t, spool.size, int64(1024))
+
+		// An event larger than the spool is rejected.
+		event := newTestEvent(string(make([]byte, 2048)))
+		require.Error(t, spool.Append(event))
+		require.Equal(t, n, spool.Len())
+	})
+
+	t.Run("drops the expired events", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "spool")
+		spool, err := NewSpool(path, WithSpoolMaxAge(time.Hour))
+		require.NoError(t, err)
+
+		now := time.Now().Add(-2 * time.Hour)
+		spool.now = func() time.Time { return now }
+		require.NoError(t, spool.Append(newTestEvent("event-0")))
+		now = now.Add(30 * time.Minute)
+		require.NoError(t, spool.Append(newTestEvent("event-1")))
+
+		dropped := testutil.ToFloat64(spoolDroppedCounter.WithLabelValues(SpoolDropReasonAge))
+		now = now.Add(45 * time.Minute)
+		require.NoError(t, spool.Append(newTestEvent("event-2")))
+		require.Equal(t, 2, spool.Len())
+		require.Equal(t, dropped+1, testutil.ToFloat64(spoolDroppedCounter.WithLabelValues(SpoolDropReasonAge)))
+		require.NoError(t, spool.Close())
+
+		// The expired events are dropped when loading the file.
+		spool, err = NewSpool(path, WithSpoolMaxAge(time.Minute))
+		require.NoError(t, err)
+		defer spool.Close()
+		require.Zero(t, spool.Len())
+	})
+}
+
+func TestSpool_Corruption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+	spool, err := NewSpool(path)
+	require.NoError(t, err)
+	require.NoError(t, spool.Append(newTestEvent("event-0")))
+	require.NoError(t, spool.Append(newTestEvent("event-1")))
+	require.NoError(t, spool.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := splitLines(b)
+	require.Len(t, lines, 2)
+	corrupted := lines[0] + "{not json\n" + `{"key":"","event":null}` + "\n" + lines[1] + `{"key":"partial`
+	require.NoError(t, os.WriteFile(path, []byte(corrupted), 0o600))
+
+	before := testutil.ToFloat64(spoolCorruptedCounter)
+	spool, err = NewSpool(path)
+	require.NoError(t, err)
+	defer spool.Close()
+	require.Equal(t, 2, spool.Len())
+	require.Equal(t, before+3, testutil.ToFloat64(spoolCorruptedCounter))
+
+	// The corrupted records are removed from the file.
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, splitLines(b), 2)
+}
+
+// splitLines returns the lines of b, each with its trailing newline.
+func splitLines(b []byte) []string {
+	var lines []string
+	start := 0
+	for i, c := range b {
+		if c == '\n' {
+			lines = append(lines, string(b[start:i+1]))
+			start = i + 1
+		}
+	}
+	if start < len(b) {
+		lines = append(lines, string(b[start:]))
+	}
+	return lines
+}
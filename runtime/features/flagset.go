@@ -0,0 +1,268 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/pflag"
+)
+
+// Gate describes a feature gate registered in a FlagSet.
+type Gate struct {
+	// Default is the state of the gate when it is not set with the
+	// --feature-gates flag.
+	Default bool
+
+	// Description is the description of the gate, listed in the usage of
+	// the --feature-gates flag.
+	Description string
+
+	// Deprecated marks the gate for removal. A warning is logged at startup
+	// by FlagSet.WarnDeprecated when the gate is set.
+	Deprecated bool
+
+	// DeprecationMessage is added to the deprecation warning, e.g. to
+	// point to the replacement of the gate.
+	DeprecationMessage string
+}
+
+// featureGateDesc is the description of the metric of the state of the
+// feature gates.
+var featureGateDesc = prometheus.NewDesc(
+	"gotk_feature_gate",
+	"The state of a feature gate of the controller, 1 if enabled, 0 otherwise.",
+	[]string{"name"}, nil,
+)
+
+// FlagSet is a set of feature gates, registered with their default value
+// and description, and set on the command line with the --feature-gates
+// flag, e.g. '--feature-gates=Foo=true,Bar=false'.
+//
+// The gates are registered with Register before the flags are parsed, so
+// that the flag rejects the unknown gates, and looked up with Enabled.
+// FlagSet implements prometheus.Collector, exposing the state of each gate
+// with the gotk_feature_gate gauge once registered in a metrics registry.
+type FlagSet struct {
+	mu    sync.RWMutex
+	gates map[string]Gate
+	set   map[string]bool
+}
+
+var (
+	_ pflag.Value          = &FlagSet{}
+	_ prometheus.Collector = &FlagSet{}
+)
+
+// NewFlagSet returns an empty FlagSet.
+func NewFlagSet() *FlagSet {
+	return &FlagSet{
+		gates: make(map[string]Gate),
+		set:   make(map[string]bool),
+	}
+}
+
+// Register registers the given feature gates. It returns an error if a
+// gate is already registered.
+func (f *FlagSet) Register(gates map[string]Gate) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name := range gates {
+		if _, ok := f.gates[name]; ok {
+			return fmt.Errorf("feature-gate '%s' already registered", name)
+		}
+	}
+	for name, gate := range gates {
+		f.gates[name] = gate
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (f *FlagSet) MustRegister(gates map[string]Gate) {
+	if err := f.Register(gates); err != nil {
+		panic(err)
+	}
+}
+
+// Enabled returns whether the given feature gate is enabled, i.e. its value
+// set with the --feature-gates flag or its default value.
+// It panics if the gate is not registered, so that the typos in the gate
+// names are caught by the tests.
+func (f *FlagSet) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	gate, ok := f.gates[name]
+	if !ok {
+		panic(fmt.Sprintf("feature-gate '%s' not registered", name))
+	}
+	if enabled, ok := f.set[name]; ok {
+		return enabled
+	}
+	return gate.Default
+}
+
+// SetEnabled sets the state of the given feature gate, e.g. in tests.
+// It returns an error if the gate is not registered.
+func (f *FlagSet) SetEnabled(name string, enabled bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.gates[name]; !ok {
+		return f.unknownGateError(name)
+	}
+	f.set[name] = enabled
+	return nil
+}
+
+// BindFlags binds the --feature-gates flag to the FlagSet. The usage of the
+// flag lists the gates registered so far.
+func (f *FlagSet) BindFlags(fs *pflag.FlagSet) {
+	fs.Var(f, flagFeatureGates, f.usage())
+}
+
+// usage returns the usage of the --feature-gates flag.
+func (f *FlagSet) usage() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	var b strings.Builder
+	b.WriteString("A comma separated list of key=value pairs defining the state of experimental features.")
+	for _, name := range f.names() {
+		gate := f.gates[name]
+		fmt.Fprintf(&b, "\n%s=true|false (default %t)", name, gate.Default)
+		if gate.Deprecated {
+			b.WriteString(" DEPRECATED")
+		}
+		if gate.Description != "" {
+			fmt.Fprintf(&b, ": %s", gate.Description)
+		}
+	}
+	return b.String()
+}
+
+// Set implements pflag.Value. It parses a comma separated list of key=value
+// pairs, and returns an error listing the valid gates for the unknown ones.
+func (f *FlagSet) Set(value string) error {
+	values := make(map[string]bool)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid feature-gate '%s': expected name=true|false", pair)
+		}
+		name = strings.TrimSpace(name)
+		enabled, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("invalid value of feature-gate '%s': %w", name, err)
+		}
+		values[name] = enabled
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name := range values {
+		if _, ok := f.gates[name]; !ok {
+			return f.unknownGateError(name)
+		}
+	}
+	for name, enabled := range values {
+		f.set[name] = enabled
+	}
+	return nil
+}
+
+// String implements pflag.Value. It returns the gates set with the flag.
+func (f *FlagSet) String() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	var pairs []string
+	for name, enabled := range f.set {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, enabled))
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Type implements pflag.Value.
+func (f *FlagSet) Type() string {
+	return "mapStringBool"
+}
+
+// WarnDeprecated logs a warning for each deprecated gate set with the
+// --feature-gates flag. It is meant to be called at startup, once the
+// logger is configured.
+func (f *FlagSet) WarnDeprecated(log logr.Logger) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, name := range f.names() {
+		gate := f.gates[name]
+		enabled, ok := f.set[name]
+		if !ok || !gate.Deprecated {
+			continue
+		}
+		msg := fmt.Sprintf("feature-gate '%s' is deprecated and will be removed in a future release", name)
+		if gate.DeprecationMessage != "" {
+			msg += ": " + gate.DeprecationMessage
+		}
+		log.Info(msg, "featureGate", name, "enabled", enabled)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (f *FlagSet) Describe(ch chan<- *prometheus.Desc) {
+	ch <- featureGateDesc
+}
+
+// Collect implements prometheus.Collector.
+func (f *FlagSet) Collect(ch chan<- prometheus.Metric) {
+	f.mu.RLock()
+	names := f.names()
+	f.mu.RUnlock()
+	for _, name := range names {
+		var value float64
+		if f.Enabled(name) {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(featureGateDesc, prometheus.GaugeValue, value, name)
+	}
+}
+
+// names returns the sorted names of the registered gates. It must be
+// called with the lock held.
+func (f *FlagSet) names() []string {
+	names := make([]string, 0, len(f.gates))
+	for name := range f.gates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// unknownGateError returns the error of a gate which is not registered,
+// listing the registered ones. It must be called with the lock held.
+func (f *FlagSet) unknownGateError(name string) error {
+	return fmt.Errorf("feature-gate '%s' not supported, valid feature gates: %s",
+		name, strings.Join(f.names(), ", "))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/pflag"
+)
+
+func newTestFlagSet(g *WithT) *FlagSet {
+	f := NewFlagSet()
+	g.Expect(f.Register(map[string]Gate{
+		"InvisibleMessages": {Default: false, Description: "Hide the messages."},
+		"TimeTravel":        {Default: true},
+		"OldBehavior": {
+			Deprecated:         true,
+			DeprecationMessage: "use TimeTravel instead",
+		},
+	})).To(Succeed())
+	return f
+}
+
+func TestFlagSet_Parse(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandLine []string
+		want        map[string]bool
+		wantErr     string
+	}{
+		{
+			name: "defaults",
+			want: map[string]bool{"InvisibleMessages": false, "TimeTravel": true, "OldBehavior": false},
+		},
+		{
+			name:        "opt-in and opt-out",
+			commandLine: []string{"--feature-gates=InvisibleMessages=true, TimeTravel=false"},
+			want:        map[string]bool{"InvisibleMessages": true, "TimeTravel": false, "OldBehavior": false},
+		},
+		{
+			name:        "repeated flag",
+			commandLine: []string{"--feature-gates=InvisibleMessages=true", "--feature-gates=OldBehavior=true"},
+			want:        map[string]bool{"InvisibleMessages": true, "TimeTravel": true, "OldBehavior": true},
+		},
+		{
+			name:        "unknown gate",
+			commandLine: []string{"--feature-gates=InvisibleMessages=true,Teleport=true"},
+			wantErr:     "feature-gate 'Teleport' not supported, valid feature gates: InvisibleMessages, OldBehavior, TimeTravel",
+		},
+		{
+			name:        "invalid value",
+			commandLine: []string{"--feature-gates=TimeTravel=maybe"},
+			wantErr:     "invalid value of feature-gate 'TimeTravel'",
+		},
+		{
+			name:        "missing value",
+			commandLine: []string{"--feature-gates=TimeTravel"},
+			wantErr:     "invalid feature-gate 'TimeTravel': expected name=true|false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			features := newTestFlagSet(g)
+			fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+			features.BindFlags(fs)
+
+			err := fs.Parse(tt.commandLine)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			for name, enabled := range tt.want {
+				g.Expect(features.Enabled(name)).To(Equal(enabled), name)
+			}
+		})
+	}
+}
+
+func TestFlagSet_Register(t *testing.T) {
+	g := NewWithT(t)
+
+	features := newTestFlagSet(g)
+	err := features.Register(map[string]Gate{"TimeTravel": {}})
+	g.Expect(err).To(MatchError("feature-gate 'TimeTravel' already registered"))
+	g.Expect(func() { features.MustRegister(map[string]Gate{"TimeTravel": {}}) }).To(Panic())
+
+	// The unregistered gates panic on lookup.
+	g.Expect(func() { features.Enabled("TimeTarvel") }).To(PanicWith("feature-gate 'TimeTarvel' not registered"))
+
+	g.Expect(features.SetEnabled("TimeTravel", false)).To(Succeed())
+	g.Expect(features.Enabled("TimeTravel")).To(BeFalse())
+	g.Expect(features.SetEnabled("Teleport", true)).ToNot(Succeed())
+
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	features.BindFlags(fs)
+	usage := fs.Lookup(flagFeatureGates).Usage
+	g.Expect(usage).To(ContainSubstring("InvisibleMessages=true|false (default false): Hide the messages."))
+	g.Expect(usage).To(ContainSubstring("OldBehavior=true|false (default false) DEPRECATED"))
+}
+
+func TestFlagSet_WarnDeprecated(t *testing.T) {
+	g := NewWithT(t)
+
+	var logs []string
+	log := funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{})
+
+	features := newTestFlagSet(g)
+	features.WarnDeprecated(log)
+	g.Expect(logs).To(BeEmpty())
+
+	g.Expect(features.Set("OldBehavior=true,TimeTravel=false")).To(Succeed())
+	features.WarnDeprecated(log)
+	g.Expect(logs).To(HaveLen(1))
+	g.Expect(logs[0]).To(ContainSubstring("feature-gate 'OldBehavior' is deprecated and will be removed in a future release: use TimeTravel instead"))
+}
+
+func TestFlagSet_Metrics(t *testing.T) {
+	g := NewWithT(t)
+
+	features := newTestFlagSet(g)
+	g.Expect(features.Set("InvisibleMessages=true")).To(Succeed())
+
+	expected := `
+# HELP gotk_feature_gate The state of a feature gate of the controller, 1 if enabled, 0 otherwise.
+# TYPE gotk_feature_gate gauge
+gotk_feature_gate{name="InvisibleMessages"} 1
+gotk_feature_gate{name="OldBehavior"} 0
+gotk_feature_gate{name="TimeTravel"} 1
+`
+	g.Expect(testutil.CollectAndCompare(features, strings.NewReader(expected))).To(Succeed())
+
+	g.Expect(features.SetEnabled("TimeTravel", false)).To(Succeed())
+	g.Expect(testutil.CollectAndCompare(features, strings.NewReader(
+		strings.Replace(expected, `{name="TimeTravel"} 1`, `{name="TimeTravel"} 0`, 1)))).To(Succeed())
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DedupRepeatsKey is the key of the number of identical errors
+	// suppressed since the previous log of the error, set on the summary logs.
+	DedupRepeatsKey = "repeats"
+
+	defaultDedupWindow = 5 * time.Minute
+)
+
+// dedupSuppressedCounter counts the error logs suppressed by the dedup
+// loggers.
+var dedupSuppressedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gotk_log_errors_suppressed_total",
+		Help: "The total number of repeating error logs suppressed, per controller.",
+	},
+	[]string{"controller"},
+)
+
+// dedupSummariesCounter counts the summary logs of the suppressed errors
+// emitted by the dedup loggers.
+var dedupSummariesCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gotk_log_errors_summarized_total",
+		Help: "The total number of summary logs of repeating errors, per controller.",
+	},
+	[]string{"controller"},
+)
+
+// DedupCollectors returns a slice of Prometheus collectors of the metrics
+// recorded by the dedup loggers, which can be used to register them in a
+// metrics registry, e.g. crtlmetrics.Registry.MustRegister(logger.DedupCollectors()...).
+func DedupCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		dedupSuppressedCounter,
+		dedupSummariesCounter,
+	}
+}
+
+// DedupOption is a function for configuring a dedup logger.
+type DedupOption func(s *dedupState)
+
+// WithDedupWindow sets the window within which the identical errors of an
+// object are suppressed. It defaults to five minutes.
+func WithDedupWindow(window time.Duration) DedupOption {
+	return func(s *dedupState) {
+		s.window = window
+	}
+}
+
+// WithDedupClock sets the clock driving the window, for testing.
+func WithDedupClock(c clock.PassiveClock) DedupOption {
+	return func(s *dedupState) {
+		s.clock = c
+	}
+}
+
+// NewDedupLogger wraps the given logger to suppress the repeating error logs
+// of the reconciled objects, e.g. the error of an object failing on each
+// retry. An error log is identified by the name and namespace of the object,
+// taken from the name and namespace values of the logger, as set by
+// controller-runtime for the reconcilers, and the error string.
+//
+// The first error log of an object is passed through, and the identical
+// error logs within the window that follows are suppressed. The first error
+// log after the window is passed through with the number of suppressed logs
+// set as the repeats value, and opens a new window. A different error of
+// the object resets the window. The Info logs, and the error logs without
+// name and namespace, are never suppressed.
+//
+// The suppressed logs and the summary logs are counted in the
+// gotk_log_errors_suppressed_total and gotk_log_errors_summarized_total
+// counters, with the controller label. The returned logger, and the loggers
+// derived from it, are safe for concurrent use and share the same state.
+func NewDedupLogger(log logr.Logger, opts ...DedupOption) logr.Logger {
+	s := &dedupState{
+		window:  defaultDedupWindow,
+		clock:   clock.RealClock{},
+		entries: make(map[dedupKey]*dedupEntry),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	sink := log.GetSink()
+	if sink == nil {
+		return log
+	}
+	// Skip the frame of the dedup sink in the caller of the logs.
+	if cs, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = cs.WithCallDepth(1)
+	}
+	return logr.New(&dedupSink{sink: sink, state: s})
+}
+
+// dedupKey identifies the error logs of an object.
+type dedupKey struct {
+	name      string
+	namespace string
+}
+
+// dedupEntry records the last error logged for an object.
+type dedupEntry struct {
+	errHash   uint64
+	start     time.Time
+	lastSeen  time.Time
+	suppress  int
+	lastSink  logr.LogSink
+	lastErr   error
+	lastMsg   string
+	lastKVs   []any
+	lastLabel string
+}
+
+// dedupState is the state shared by the sinks derived from a dedup logger.
+type dedupState struct {
+	window time.Duration
+	clock  clock.PassiveClock
+
+	mu        sync.Mutex
+	entries   map[dedupKey]*dedupEntry
+	lastSweep time.Time
+}
+
+// dedupSink is a logr.LogSink suppressing the repeating error logs.
+type dedupSink struct {
+	sink  logr.LogSink
+	state *dedupState
+
+	name       string
+	namespace  string
+	controller string
+}
+
+var _ logr.LogSink = &dedupSink{}
+
+// Init implements logr.LogSink.
+func (d *dedupSink) Init(info logr.RuntimeInfo) {
+	d.sink.Init(info)
+}
+
+// Enabled implements logr.LogSink.
+func (d *dedupSink) Enabled(level int) bool {
+	return d.sink.Enabled(level)
+}
+
+// Info implements logr.LogSink, the Info logs are never suppressed.
+func (d *dedupSink) Info(level int, msg string, keysAndValues ...any) {
+	d.sink.Info(level, msg, keysAndValues...)
+}
+
+// Error implements logr.LogSink, suppressing the error if it repeats the
+// last error of the object within the window.
+func (d *dedupSink) Error(err error, msg string, keysAndValues ...any) {
+	name, namespace, controller := d.name, d.namespace, d.controller
+	lookupValues(keysAndValues, &name, &namespace, &controller)
+	if name == "" && namespace == "" {
+		d.sink.Error(err, msg, keysAndValues...)
+		return
+	}
+
+	key := dedupKey{name: name, namespace: namespace}
+	hash := errorHash(err)
+	s := d.state
+
+	s.mu.Lock()
+	now := s.clock.Now()
+	evicted := s.sweep(now)
+	e, ok := s.entries[key]
+	repeats := 0
+	switch {
+	case !ok || e.errHash != hash:
+		if ok && e.suppress > 0 {
+			evicted = append(evicted, e)
+		}
+		e = &dedupEntry{errHash: hash, start: now}
+		s.entries[key] = e
+	case now.Sub(e.start) < s.window:
+		e.suppress++
+		e.lastSeen = now
+		e.lastSink, e.lastErr, e.lastMsg, e.lastKVs, e.lastLabel = d.sink, err, msg, keysAndValues, controller
+		s.mu.Unlock()
+		dedupSuppressedCounter.WithLabelValues(controller).Inc()
+		logSummaries(evicted)
+		return
+	default:
+		repeats = e.suppress
+		e.start = now
+		e.suppress = 0
+	}
+	e.lastSeen = now
+	s.mu.Unlock()
+
+	logSummaries(evicted)
+	if repeats > 0 {
+		dedupSummariesCounter.WithLabelValues(controller).Inc()
+		keysAndValues = append(keysAndValues[:len(keysAndValues):len(keysAndValues)], DedupRepeatsKey, repeats)
+	}
+	d.sink.Error(err, msg, keysAndValues...)
+}
+
+// WithValues implements logr.LogSink.
+func (d *dedupSink) WithValues(keysAndValues ...any) logr.LogSink {
+	c := *d
+	c.sink = d.sink.WithValues(keysAndValues...)
+	lookupValues(keysAndValues, &c.name, &c.namespace, &c.controller)
+	return &c
+}
+
+// WithName implements logr.LogSink.
+func (d *dedupSink) WithName(name string) logr.LogSink {
+	c := *d
+	c.sink = d.sink.WithName(name)
+	return &c
+}
+
+// sweep removes the entries of the objects which have not logged an error
+// for a window, at most once per window, and returns the removed entries
+// with suppressed logs to summarize. It must be called with the lock held.
+// The suppressed logs of an object which stops failing are thus summarized
+// only on a later error log.
+func (s *dedupState) sweep(now time.Time) []*dedupEntry {
+	if now.Sub(s.lastSweep) < s.window {
+		return nil
+	}
+	s.lastSweep = now
+
+	var evicted []*dedupEntry
+	for key, e := range s.entries {
+		if now.Sub(e.lastSeen) < s.window {
+			continue
+		}
+		if e.suppress > 0 {
+			evicted = append(evicted, e)
+		}
+		delete(s.entries, key)
+	}
+	return evicted
+}
+
+// logSummaries logs the last suppressed error of the given entries with
+// the number of suppressed logs, e.g. before logging a different error of
+// the object.
+func logSummaries(entries []*dedupEntry) {
+	for _, e := range entries {
+		dedupSummariesCounter.WithLabelValues(e.lastLabel).Inc()
+		kvs := append(e.lastKVs[:len(e.lastKVs):len(e.lastKVs)], DedupRepeatsKey, e.suppress)
+		e.lastSink.Error(e.lastErr, e.lastMsg, kvs...)
+	}
+}
+
+// lookupValues sets the name, namespace and controller to the values of the
+// matching keys, if any.
+func lookupValues(keysAndValues []any, name, namespace, controller *string) {
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "name":
+			*name = fmt.Sprint(keysAndValues[i+1])
+		case "namespace":
+			*namespace = fmt.Sprint(keysAndValues[i+1])
+		case "controller":
+			*controller = fmt.Sprint(keysAndValues[i+1])
+		}
+	}
+}
+
+// errorHash returns the hash of the error string.
+func errorHash(err error) uint64 {
+	h := fnv.New64a()
+	if err != nil {
+		_, _ = h.Write([]byte(err.Error()))
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+// logRecorder records the lines of a funcr logger.
+type logRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *logRecorder) logger() logr.Logger {
+	return funcr.New(func(_, args string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.lines = append(r.lines, args)
+	}, funcr.Options{})
+}
+
+func (r *logRecorder) reset() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lines := r.lines
+	r.lines = nil
+	return lines
+}
+
+func TestDedupLogger(t *testing.T) {
+	g := NewWithT(t)
+
+	rec := &logRecorder{}
+	clock := clocktesting.NewFakePassiveClock(time.Now())
+	log := NewDedupLogger(rec.logger(), WithDedupWindow(time.Minute), WithDedupClock(clock)).
+		WithValues("controller", "dedup-test")
+	objLog := log.WithValues("name", "app", "namespace", "default")
+	errFailed := errors.New("failed to fetch artifact")
+
+	suppressed := func() float64 {
+		return testutil.ToFloat64(dedupSuppressedCounter.WithLabelValues("dedup-test"))
+	}
+	summarized := func() float64 {
+		return testutil.ToFloat64(dedupSummariesCounter.WithLabelValues("dedup-test"))
+	}
+	suppressedBefore, summarizedBefore := suppressed(), summarized()
+
+	// The first error is logged, and the identical ones within the window
+	// are suppressed.
+	objLog.Error(errFailed, "Reconciler error")
+	for range 3 {
+		clock.SetTime(clock.Now().Add(10 * time.Second))
+		objLog.Error(errFailed, "Reconciler error")
+	}
+	g.Expect(rec.reset()).To(ConsistOf(
+		`"msg"="Reconciler error" "error"="failed to fetch artifact" "controller"="dedup-test" "name"="app" "namespace"="default"`,
+	))
+	g.Expect(suppressed() - suppressedBefore).To(Equal(3.0))
+
+	// The Info logs and the error logs of other objects are not suppressed.
+	objLog.Info("Reconciliation started")
+	log.Error(errFailed, "Reconciler error", "name", "other", "namespace", "default")
+	log.Error(errFailed, "Failed to start")
+	log.Error(errFailed, "Failed to start")
+	g.Expect(rec.reset()).To(HaveLen(4))
+
+	// The first error after the window is logged with the repeat count.
+	clock.SetTime(clock.Now().Add(31 * time.Second))
+	objLog.Error(errFailed, "Reconciler error")
+	g.Expect(rec.reset()).To(ConsistOf(
+		`"msg"="Reconciler error" "error"="failed to fetch artifact" "controller"="dedup-test" "name"="app" "namespace"="default" "repeats"=3`,
+	))
+	g.Expect(summarized() - summarizedBefore).To(Equal(1.0))
+
+	// A different error resets the window, after summarizing the
+	// suppressed ones.
+	objLog.Error(errFailed, "Reconciler error")
+	clock.SetTime(clock.Now().Add(time.Second))
+	objLog.Error(errors.New("failed to apply"), "Reconciler error")
+	objLog.Error(errors.New("failed to apply"), "Reconciler error")
+	objLog.Error(errFailed, "Reconciler error")
+	g.Expect(rec.reset()).To(Equal([]string{
+		`"msg"="Reconciler error" "error"="failed to fetch artifact" "controller"="dedup-test" "name"="app" "namespace"="default" "repeats"=1`,
+		`"msg"="Reconciler error" "error"="failed to apply" "controller"="dedup-test" "name"="app" "namespace"="default"`,
+		`"msg"="Reconciler error" "error"="failed to apply" "controller"="dedup-test" "name"="app" "namespace"="default" "repeats"=1`,
+		`"msg"="Reconciler error" "error"="failed to fetch artifact" "controller"="dedup-test" "name"="app" "namespace"="default"`,
+	}))
+	g.Expect(suppressed() - suppressedBefore).To(Equal(5.0))
+	g.Expect(summarized() - summarizedBefore).To(Equal(3.0))
+}
+
+func TestDedupLogger_Eviction(t *testing.T) {
+	g := NewWithT(t)
+
+	rec := &logRecorder{}
+	clock := clocktesting.NewFakePassiveClock(time.Now())
+	log := NewDedupLogger(rec.logger(), WithDedupWindow(time.Minute), WithDedupClock(clock))
+
+	// The suppressed errors of an object which stopped failing are
+	// summarized on a later error log.
+	log.Error(errors.New("timeout"), "Reconciler error", "name", "app", "namespace", "default")
+	log.Error(errors.New("timeout"), "Reconciler error", "name", "app", "namespace", "default")
+	clock.SetTime(clock.Now().Add(2 * time.Minute))
+	log.Error(errors.New("not found"), "Reconciler error", "name", "other", "namespace", "default")
+	g.Expect(rec.reset()).To(Equal([]string{
+		`"msg"="Reconciler error" "error"="timeout" "name"="app" "namespace"="default"`,
+		`"msg"="Reconciler error" "error"="timeout" "name"="app" "namespace"="default" "repeats"=1`,
+		`"msg"="Reconciler error" "error"="not found" "name"="other" "namespace"="default"`,
+	}))
+
+	// The evicted object starts a new window.
+	log.Error(errors.New("timeout"), "Reconciler error", "name", "app", "namespace", "default")
+	g.Expect(rec.reset()).To(HaveLen(1))
+}
+
+func TestDedupLogger_Concurrency(t *testing.T) {
+	g := NewWithT(t)
+
+	rec := &logRecorder{}
+	log := NewDedupLogger(rec.logger(), WithDedupWindow(time.Hour))
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			objLog := log.WithValues("name", "app", "namespace", "default", "worker", i)
+			for range 100 {
+				objLog.Error(errors.New("failed"), "Reconciler error")
+				objLog.Info("Retrying")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A single error log and all the Info logs.
+	g.Expect(rec.reset()).To(HaveLen(1 + 10*100))
+}
+
+func TestNewLogger_ErrorDedupWindow(t *testing.T) {
+	g := NewWithT(t)
+
+	_, ok := NewLogger(Options{}).GetSink().(*dedupSink)
+	g.Expect(ok).To(BeFalse())
+	_, ok = NewLogger(Options{ErrorDedupWindow: time.Minute}).GetSink().(*dedupSink)
+	g.Expect(ok).To(BeTrue())
+}
+
+func TestDedupCollectors(t *testing.T) {
+	g := NewWithT(t)
+
+	reg := prometheus.NewPedanticRegistry()
+	for _, c := range DedupCollectors() {
+		g.Expect(reg.Register(c)).To(Succeed())
+	}
+	g.Expect(reg.Register(dedupSuppressedCounter)).To(BeAssignableToTypeOf(prometheus.AlreadyRegisteredError{}))
+	g.Expect(reg.Register(dedupSummariesCounter)).To(BeAssignableToTypeOf(prometheus.AlreadyRegisteredError{}))
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package logger
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap/zapcore"
@@ -26,8 +28,9 @@ import (
 )
 
 const (
-	flagLogEncoding = "log-encoding"
-	flagLogLevel    = "log-level"
+	flagLogEncoding         = "log-encoding"
+	flagLogLevel            = "log-level"
+	flagLogErrorDedupWindow = "log-error-dedup-window"
 )
 
 var levelStrings = map[string]zapcore.Level{
@@ -77,6 +80,11 @@ var stackLevelStrings = map[string]zapcore.Level{
 type Options struct {
 	LogEncoding string
 	LogLevel    string
+
+	// ErrorDedupWindow enables the suppression of the repeating error logs
+	// of the reconciled objects within the window, see NewDedupLogger.
+	// Zero, the default, disables the suppression.
+	ErrorDedupWindow time.Duration
 }
 
 // BindFlags will parse the given pflag.FlagSet for logger option flags and set the Options accordingly.
@@ -85,6 +93,9 @@ func (o *Options) BindFlags(fs *pflag.FlagSet) {
 		"Log encoding format. Can be 'json' or 'console'.")
 	fs.StringVar(&o.LogLevel, flagLogLevel, "info",
 		"Log verbosity level. Can be one of 'trace', 'debug', 'info', 'error'.")
+	fs.DurationVar(&o.ErrorDedupWindow, flagLogErrorDedupWindow, 0,
+		"The window within which the identical error logs of an object are suppressed and summarized. "+
+			"Zero disables the suppression.")
 }
 
 // NewLogger returns a logger configured with the given Options, and timestamps set to the ISO8601 format.
@@ -118,7 +129,11 @@ func NewLogger(opts Options) logr.Logger {
 		zapOpts.StacktraceLevel = l
 	}
 
-	return zap.New(zap.UseFlagOptions(&zapOpts))
+	log := zap.New(zap.UseFlagOptions(&zapOpts))
+	if opts.ErrorDedupWindow > 0 {
+		log = NewDedupLogger(log, WithDedupWindow(opts.ErrorDedupWindow))
+	}
+	return log
 }
 
 // SetLogger sets the logger for the controller-runtime and klog packages to the given logger.
@@ -17,4 +17,6 @@ limitations under the License.
 // Package object provides helpers for interacting with GitOps Toolkit objects
 // using unstructured types. The helpers assist in reading and writing certain
 // attributes of the objects without converting them to their original types.
+// The package also defines the canonical interfaces of the common fields of
+// the objects, which the helpers use when implemented.
 package object
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"errors"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/fluxcd/pkg/apis/meta"
+)
+
+// ErrArtifactNotFound is returned by GetArtifact when the object has no
+// status.artifact.
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// ObjectWithConditions describes an object with status conditions.
+type ObjectWithConditions = meta.ObjectWithConditions
+
+// ObjectWithDependencies describes an object with dependencies, usually set
+// in .spec.dependsOn.
+type ObjectWithDependencies = meta.ObjectWithDependencies
+
+// ObjectWithArtifact describes an object with an artifact, usually set in
+// .status.artifact.
+type ObjectWithArtifact interface {
+	// GetArtifact returns the artifact of the object, or nil if the object
+	// has no artifact.
+	GetArtifact() *meta.Artifact
+}
+
+// ObjectWithInterval describes an object reconciled at an interval, usually
+// set in .spec.interval.
+type ObjectWithInterval interface {
+	// GetRequeueAfter returns the duration after which the object must be
+	// reconciled again.
+	GetRequeueAfter() time.Duration
+}
+
+// ObjectWithSuspend describes an object whose reconciliation can be
+// suspended, usually with .spec.suspend.
+type ObjectWithSuspend interface {
+	// GetSuspend returns true if the reconciliation of the object is
+	// suspended.
+	GetSuspend() bool
+}
+
+// IsSuspended returns true if the reconciliation of the given runtime object
+// is suspended. The ObjectWithSuspend interface is used when implemented by
+// the object, otherwise the spec.suspend value is read. An object without
+// spec.suspend is not suspended.
+func IsSuspended(obj runtime.Object) bool {
+	if o, ok := obj.(ObjectWithSuspend); ok {
+		return o.GetSuspend()
+	}
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return false
+	}
+	suspend, _, _ := unstructured.NestedBool(u.Object, "spec", "suspend")
+	return suspend
+}
+
+// GetArtifact returns the artifact of the given runtime object. The
+// ObjectWithArtifact interface is used when implemented by the object,
+// otherwise the status.artifact value is read. ErrArtifactNotFound is
+// returned when the object has no artifact.
+func GetArtifact(obj runtime.Object) (*meta.Artifact, error) {
+	if o, ok := obj.(ObjectWithArtifact); ok {
+		if artifact := o.GetArtifact(); artifact != nil {
+			return artifact, nil
+		}
+		return nil, ErrArtifactNotFound
+	}
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	content, found, err := unstructured.NestedMap(u.Object, "status", "artifact")
+	if err != nil {
+		return nil, err
+	}
+	if !found || content == nil {
+		return nil, ErrArtifactNotFound
+	}
+	var artifact meta.Artifact
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &artifact); err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+var (
+	_ ObjectWithConditions   = &testdata.Fake{}
+	_ ObjectWithArtifact     = &testdata.Fake{}
+	_ ObjectWithInterval     = &testdata.Fake{}
+	_ ObjectWithSuspend      = &testdata.Fake{}
+	_ ObjectWithDependencies = &testdata.Fake{}
+)
+
+func TestIsSuspended(t *testing.T) {
+	suspended := &testdata.Fake{}
+	suspended.Spec.Suspend = true
+
+	tests := []struct {
+		name string
+		obj  runtime.Object
+		want bool
+	}{
+		{
+			name: "suspended object",
+			obj:  suspended,
+			want: true,
+		},
+		{
+			name: "not suspended object",
+			obj:  &testdata.Fake{},
+		},
+		{
+			name: "suspended unstructured object",
+			obj: &unstructured.Unstructured{Object: map[string]any{
+				"spec": map[string]any{"suspend": true},
+			}},
+			want: true,
+		},
+		{
+			name: "object without suspend",
+			obj:  &corev1.Secret{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsSuspended(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetArtifact(t *testing.T) {
+	g := NewWithT(t)
+
+	// Get unset artifact.
+	obj := &testdata.Fake{}
+	_, err := GetArtifact(obj)
+	g.Expect(err).To(Equal(ErrArtifactNotFound))
+
+	// Get set artifact.
+	obj.Status.Artifact = &meta.Artifact{Revision: "v1.0.0", Digest: "sha256:abc"}
+	artifact, err := GetArtifact(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(artifact).To(Equal(obj.Status.Artifact))
+
+	// Get artifact of an unstructured object.
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"artifact": map[string]any{
+				"revision": "main@sha1:abc",
+				"path":     "gitrepository/default/repo/abc.tar.gz",
+			},
+		},
+	}}
+	artifact, err = GetArtifact(u)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(artifact.Revision).To(Equal("main@sha1:abc"))
+	g.Expect(artifact.Path).To(Equal("gitrepository/default/repo/abc.tar.gz"))
+
+	// Get non-existent artifact.
+	_, err = GetArtifact(&corev1.Secret{})
+	g.Expect(err).To(Equal(ErrArtifactNotFound))
+}
+
+func TestGetRequeueInterval_ObjectWithInterval(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &intervalObject{requeueAfter: 5 * time.Minute}
+	pd, err := GetRequeueInterval(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pd).To(Equal(5 * time.Minute))
+
+	// A non-positive duration falls back to spec.interval.
+	obj.requeueAfter = 0
+	_, err = GetRequeueInterval(obj)
+	g.Expect(err).To(Equal(ErrRequeueIntervalNotFound))
+}
+
+// intervalObject is an object without spec.interval implementing
+// ObjectWithInterval.
+type intervalObject struct {
+	corev1.Secret
+	requeueAfter time.Duration
+}
+
+func (o *intervalObject) GetRequeueAfter() time.Duration {
+	return o.requeueAfter
+}
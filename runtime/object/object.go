@@ -95,9 +95,15 @@ func GetStatusObservedGeneration(obj runtime.Object) (int64, error) {
 	return og, nil
 }
 
-// GetRequeueInterval returns the spec.interval of a given runtime object, if
-// present.
+// GetRequeueInterval returns the requeue interval of a given runtime object.
+// The ObjectWithInterval interface is used when implemented by the object and
+// returning a positive duration, otherwise the spec.interval value is read.
 func GetRequeueInterval(obj runtime.Object) (time.Duration, error) {
+	if o, ok := obj.(ObjectWithInterval); ok {
+		if d := o.GetRequeueAfter(); d > 0 {
+			return d, nil
+		}
+	}
 	period := time.Second
 	u, err := toUnstructured(obj)
 	if err != nil {
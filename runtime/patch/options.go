@@ -44,6 +44,14 @@ type HelperOptions struct {
 
 	// FieldOwner defines the field owner configuration for Kubernetes patch operations.
 	FieldOwner string
+
+	// SortConditions sorts the conditions of the object before patching it, using conditions.Sort with
+	// ConditionsOrder.
+	SortConditions bool
+
+	// ConditionsOrder defines the condition types to be sorted right after the Ready condition when
+	// SortConditions is enabled.
+	ConditionsOrder []string
 }
 
 // WithForceOverwriteConditions allows the patch helper to overwrite conditions in case of conflicts.
@@ -82,3 +90,16 @@ type WithFieldOwner string
 func (w WithFieldOwner) ApplyToHelper(in *HelperOptions) {
 	in.FieldOwner = string(w)
 }
+
+// WithSortedConditions sorts the conditions of the object before patching it, so that their order is stable across
+// reconciliations: Ready first, followed by the condition types in Order, followed by all the other conditions sorted
+// lexicographically by type.
+type WithSortedConditions struct {
+	Order []string
+}
+
+// ApplyToHelper applies this configuration to the given HelperOptions.
+func (w WithSortedConditions) ApplyToHelper(in *HelperOptions) {
+	in.SortConditions = true
+	in.ConditionsOrder = w.Order
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -138,9 +139,13 @@ type Helper struct {
 
 // NewHelper returns an initialised Helper.
 func NewHelper(obj client.Object, crClient client.Client) (*Helper, error) {
+	if obj == nil {
+		return nil, errors.New("cannot create a patch helper for a nil object")
+	}
+
 	// Get the GroupVersionKind of the object,
 	// used to validate against later on.
-	gvk, err := apiutil.GVKForObject(obj, crClient.Scheme())
+	gvk, err := gvkForObject(obj, crClient.Scheme(), schema.GroupVersionKind{})
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +155,12 @@ func NewHelper(obj client.Object, crClient client.Client) (*Helper, error) {
 	if err != nil {
 		return nil, err
 	}
+	unstructuredObj.SetGroupVersionKind(gvk)
+
+	// Keep a copy of the object with its TypeMeta restored, so that the
+	// patches can be issued for unstructured objects too.
+	beforeObject := obj.DeepCopyObject().(client.Object)
+	beforeObject.GetObjectKind().SetGroupVersionKind(gvk)
 
 	// Check if the object satisfies the GitOps Toolkit API conditions contract.
 	_, canInterfaceConditions := obj.(conditions.Setter)
@@ -158,15 +169,47 @@ func NewHelper(obj client.Object, crClient client.Client) (*Helper, error) {
 		client:             crClient,
 		gvk:                gvk,
 		before:             unstructuredObj,
-		beforeObject:       obj.DeepCopyObject().(client.Object),
+		beforeObject:       beforeObject,
 		isConditionsSetter: canInterfaceConditions,
 	}, nil
 }
 
+// gvkForObject returns the GroupVersionKind of the given object.
+//
+// The kind of typed objects is looked up in the scheme, regardless of their TypeMeta.
+// The kind of unstructured and metav1.PartialObjectMetadata objects is read from their
+// TypeMeta, falling back to the given GroupVersionKind of the original snapshot when
+// it is missing, e.g. after a round-trip through the scheme cleared it.
+func gvkForObject(obj client.Object, scheme *runtime.Scheme, snapshot schema.GroupVersionKind) (schema.GroupVersionKind, error) {
+	switch obj.(type) {
+	case runtime.Unstructured, *metav1.PartialObjectMetadata:
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Kind != "" && gvk.Version != "" {
+			return gvk, nil
+		}
+		if !snapshot.Empty() {
+			return snapshot, nil
+		}
+		return schema.GroupVersionKind{}, errors.Errorf("cannot determine the GroupVersionKind of %T object '%s': "+
+			"apiVersion and kind must be set", obj, client.ObjectKeyFromObject(obj))
+	default:
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return schema.GroupVersionKind{}, errors.Wrapf(err, "cannot determine the GroupVersionKind of %T object '%s'",
+				obj, client.ObjectKeyFromObject(obj))
+		}
+		return gvk, nil
+	}
+}
+
 // Patch will attempt to patch the given object, including its status.
 func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) error {
+	if obj == nil {
+		return errors.New("cannot patch a nil object")
+	}
+
 	// Get the GroupVersionKind of the object that we want to patch.
-	gvk, err := apiutil.GVKForObject(obj, h.client.Scheme())
+	gvk, err := gvkForObject(obj, h.client.Scheme(), h.gvk)
 	if err != nil {
 		return err
 	}
@@ -180,11 +223,18 @@ func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) e
 		opt.ApplyToHelper(options)
 	}
 
+	// Sort the conditions before calculating any patch, so the resulting
+	// object is the same for the conditions and the status patches.
+	if options.SortConditions && h.isConditionsSetter {
+		conditions.Sort(obj.(conditions.Setter), options.ConditionsOrder...)
+	}
+
 	// Convert the object to unstructured to compare against our before copy.
 	h.after, err = ToUnstructured(obj)
 	if err != nil {
 		return err
 	}
+	h.after.SetGroupVersionKind(h.gvk)
 
 	// Determine if the object has status.
 	if unstructuredHasStatus(h.after) {
@@ -202,7 +252,7 @@ func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) e
 	}
 
 	// Calculate and store the top-level field changes (e.g. "metadata", "spec", "status") we have before/after.
-	h.changes, err = h.calculateChanges(obj)
+	h.changes, err = h.calculateChanges()
 	if err != nil {
 		return err
 	}
@@ -227,7 +277,7 @@ func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) e
 		// Given that we pass in metadata.resourceVersion to perform a 3-way-merge conflict resolution,
 		// patching conditions first avoids an extra loop if spec or status patch succeeds first
 		// given that causes the resourceVersion to mutate.
-		h.patchStatusConditions(ctx, obj, options.ForceOverwriteConditions, options.OwnedConditions, statusOpts),
+		h.patchStatusConditions(ctx, obj, options, statusOpts),
 
 		// Then proceed to patch the rest of the object.
 		h.patch(ctx, obj, clientOpts...),
@@ -268,7 +318,7 @@ func (h *Helper) patchStatus(ctx context.Context, obj client.Object, opts ...cli
 //
 // Condition changes are then applied to the latest version of the object, and if there are no unresolvable conflicts,
 // the patch is sent again.
-func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, forceOverwrite bool, ownedConditions []string, opts ...client.SubResourcePatchOption) error {
+func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, options *HelperOptions, opts ...client.SubResourcePatchOption) error {
 	// Nothing to do if the object isn't a condition patcher.
 	if !h.isConditionsSetter {
 		return nil
@@ -280,11 +330,11 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 	// interface any longer, although this shouldn't happen because we already check when creating the patcher.
 	before, ok := h.beforeObject.(conditions.Getter)
 	if !ok {
-		return errors.Errorf("object %s doesn't satisfy conditions.Getter, cannot patch", before.GetObjectKind())
+		return errors.Errorf("object %s doesn't satisfy conditions.Getter, cannot patch", h.gvk)
 	}
 	after, ok := obj.(conditions.Getter)
 	if !ok {
-		return errors.Errorf("object %s doesn't satisfy conditions.Getter, cannot patch", after.GetObjectKind())
+		return errors.Errorf("object %s doesn't satisfy conditions.Getter, cannot patch", h.gvk)
 	}
 
 	// Store the diff from the before/after object, and return early if there are no changes.
@@ -292,7 +342,8 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 		before,
 		after,
 	)
-	if diff.IsZero() {
+	// When sorting, a change in the order of the conditions must be patched too.
+	if diff.IsZero() && (!options.SortConditions || sameConditionsOrder(before.GetConditions(), after.GetConditions())) {
 		return nil
 	}
 
@@ -313,7 +364,7 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 	return wait.ExponentialBackoff(backoff, func() (bool, error) {
 		latest, ok := before.DeepCopyObject().(conditions.Setter)
 		if !ok {
-			return false, errors.Errorf("object %s doesn't satisfy conditions.Setter, cannot patch", latest.GetObjectKind())
+			return false, errors.Errorf("object %s doesn't satisfy conditions.Setter, cannot patch", h.gvk)
 		}
 
 		// Get a new copy of the object.
@@ -325,10 +376,15 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 		conditionsPatch := client.MergeFromWithOptions(latest.DeepCopyObject().(conditions.Setter), client.MergeFromWithOptimisticLock{})
 
 		// Set the condition patch previously created on the new object.
-		if err := diff.Apply(latest, conditions.WithForceOverwrite(forceOverwrite), conditions.WithOwnedConditions(ownedConditions...)); err != nil {
+		if err := diff.Apply(latest, conditions.WithForceOverwrite(options.ForceOverwriteConditions), conditions.WithOwnedConditions(options.OwnedConditions...)); err != nil {
 			return false, err
 		}
 
+		// Apply the same order as in the status patch.
+		if options.SortConditions {
+			conditions.Sort(latest, options.ConditionsOrder...)
+		}
+
 		// Issue the patch.
 		err := h.client.Status().Patch(ctx, latest, conditionsPatch, opts...)
 		switch {
@@ -363,16 +419,29 @@ func (h *Helper) calculatePatch(afterObj client.Object, focus patchType) (client
 	return beforeObj, afterObj, nil
 }
 
+// sameConditionsOrder returns true if both slices hold the same condition types in the same order.
+func sameConditionsOrder(a, b []metav1.Condition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type {
+			return false
+		}
+	}
+	return true
+}
+
 func (h *Helper) shouldPatch(in string) bool {
 	return h.changes[in]
 }
 
 // calculate changes tries to build a patch from the before/after objects we have and store in a map which top-level
 // fields (e.g. `metadata`, `spec`, `status`, etc.) have changed.
-func (h *Helper) calculateChanges(after client.Object) (map[string]bool, error) {
-	// Calculate patch data.
-	patch := client.MergeFrom(h.beforeObject)
-	diff, err := patch.Data(after)
+func (h *Helper) calculateChanges() (map[string]bool, error) {
+	// Calculate patch data from the unstructured copies, which have their TypeMeta restored.
+	patch := client.MergeFrom(h.before)
+	diff, err := patch.Data(h.after)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to calculate patch data")
 	}
@@ -32,8 +32,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/conditions/testdata"
@@ -269,6 +272,83 @@ func TestPatchHelper(t *testing.T) {
 				}, timeout).Should(BeTrue())
 			})
 
+			t.Run("should keep a stable conditions order across patches", func(t *testing.T) {
+				g := NewWithT(t)
+
+				obj := obj.DeepCopy()
+
+				t.Log("Creating the object")
+				g.Expect(env.Create(ctx, obj)).To(Succeed())
+				defer func() {
+					g.Expect(env.Delete(ctx, obj)).To(Succeed())
+				}()
+				key := client.ObjectKey{Name: obj.Name, Namespace: obj.Namespace}
+
+				t.Log("Checking that the object has been created")
+				g.Eventually(func() error {
+					obj := obj.DeepCopy()
+					if err := env.Get(ctx, key, obj); err != nil {
+						return err
+					}
+					return nil
+				}).Should(Succeed())
+
+				// The object is not refreshed between patches, overwrite the
+				// conditions to not depend on the timestamps of the latest copy.
+				patchOpts := []Option{
+					WithForceOverwriteConditions{},
+					WithSortedConditions{Order: []string{"Foo"}},
+				}
+				conditionTypes := func(obj *testdata.Fake) []string {
+					var types []string
+					for _, c := range obj.Status.Conditions {
+						types = append(types, c.Type)
+					}
+					return types
+				}
+				expectedOrder := []string{meta.ReadyCondition, "Foo", "Bar", "Baz"}
+
+				t.Log("Setting all the conditions")
+				patcher, err := NewHelper(obj, env)
+				g.Expect(err).NotTo(HaveOccurred())
+				conditions.MarkTrue(obj, "Baz", meta.SucceededReason, "")
+				conditions.MarkTrue(obj, "Bar", meta.SucceededReason, "")
+				conditions.MarkTrue(obj, "Foo", meta.SucceededReason, "")
+				conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "")
+				g.Expect(patcher.Patch(ctx, obj, patchOpts...)).To(Succeed())
+				g.Expect(conditionTypes(obj)).To(Equal(expectedOrder))
+
+				g.Eventually(func() []string {
+					objAfter := obj.DeepCopy()
+					if err := env.Get(ctx, key, objAfter); err != nil {
+						return nil
+					}
+					return conditionTypes(objAfter)
+				}, timeout).Should(Equal(expectedOrder))
+
+				t.Log("Changing the Foo condition")
+				patcher, err = NewHelper(obj, env)
+				g.Expect(err).NotTo(HaveOccurred())
+				conditions.MarkFalse(obj, "Foo", meta.FailedReason, "")
+				g.Expect(patcher.Patch(ctx, obj, patchOpts...)).To(Succeed())
+
+				t.Log("Changing the Baz condition")
+				patcher, err = NewHelper(obj, env)
+				g.Expect(err).NotTo(HaveOccurred())
+				conditions.MarkFalse(obj, "Baz", meta.FailedReason, "")
+				g.Expect(patcher.Patch(ctx, obj, patchOpts...)).To(Succeed())
+				g.Expect(conditionTypes(obj)).To(Equal(expectedOrder))
+
+				t.Log("Validating the order is stable")
+				g.Eventually(func() bool {
+					objAfter := obj.DeepCopy()
+					if err := env.Get(ctx, key, objAfter); err != nil {
+						return false
+					}
+					return cmp.Equal(obj.Status.Conditions, objAfter.Status.Conditions)
+				}, timeout).Should(BeTrue())
+			})
+
 			t.Run("should recover if there is a resolvable conflict", func(t *testing.T) {
 				g := NewWithT(t)
 
@@ -963,3 +1043,149 @@ func TestPatchHelper(t *testing.T) {
 		g.Expect(patcher.Patch(ctx, node)).NotTo(Succeed())
 	})
 }
+
+func TestHelper_ObjectKinds(t *testing.T) {
+	scheme := runtime.NewScheme()
+	g := NewWithT(t)
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	newConfigMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: configMapGVK.GroupVersion().String(),
+				Kind:       configMapGVK.Kind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+			Data: map[string]string{"key": "value"},
+		}
+	}
+
+	tests := []struct {
+		name string
+		// object returns the object to patch, given the existing ConfigMap.
+		object func(g *WithT, cm *corev1.ConfigMap) client.Object
+		// clearTypeMeta clears the TypeMeta of the object before it is patched.
+		clearTypeMeta bool
+	}{
+		{
+			name: "typed object",
+			object: func(_ *WithT, cm *corev1.ConfigMap) client.Object {
+				return cm
+			},
+		},
+		{
+			name: "typed object with cleared TypeMeta",
+			object: func(_ *WithT, cm *corev1.ConfigMap) client.Object {
+				cm.TypeMeta = metav1.TypeMeta{}
+				return cm
+			},
+			clearTypeMeta: true,
+		},
+		{
+			name: "unstructured object",
+			object: func(g *WithT, cm *corev1.ConfigMap) client.Object {
+				u, err := ToUnstructured(cm)
+				g.Expect(err).ToNot(HaveOccurred())
+				return u
+			},
+		},
+		{
+			name: "unstructured object with TypeMeta cleared after the snapshot",
+			object: func(g *WithT, cm *corev1.ConfigMap) client.Object {
+				u, err := ToUnstructured(cm)
+				g.Expect(err).ToNot(HaveOccurred())
+				return u
+			},
+			clearTypeMeta: true,
+		},
+		{
+			name: "partial metadata object",
+			object: func(_ *WithT, cm *corev1.ConfigMap) client.Object {
+				return &metav1.PartialObjectMetadata{
+					TypeMeta:   cm.TypeMeta,
+					ObjectMeta: *cm.ObjectMeta.DeepCopy(),
+				}
+			},
+		},
+		{
+			name: "partial metadata object with TypeMeta cleared after the snapshot",
+			object: func(_ *WithT, cm *corev1.ConfigMap) client.Object {
+				return &metav1.PartialObjectMetadata{
+					TypeMeta:   cm.TypeMeta,
+					ObjectMeta: *cm.ObjectMeta.DeepCopy(),
+				}
+			},
+			clearTypeMeta: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(newConfigMap()).Build()
+			existing := &corev1.ConfigMap{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(newConfigMap()), existing)).To(Succeed())
+			existing.SetGroupVersionKind(configMapGVK)
+
+			obj := tt.object(g, existing)
+			patcher, err := NewHelper(obj, c)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			obj.SetLabels(map[string]string{"patched": "true"})
+			if tt.clearTypeMeta {
+				obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+			}
+			g.Expect(patcher.Patch(ctx, obj)).To(Succeed())
+
+			patched := &corev1.ConfigMap{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), patched)).To(Succeed())
+			g.Expect(patched.GetLabels()).To(HaveKeyWithValue("patched", "true"))
+			g.Expect(patched.Data).To(HaveKeyWithValue("key", "value"))
+		})
+	}
+
+	t.Run("returns an error for an object without kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+		u := &unstructured.Unstructured{}
+		u.SetName("test")
+		u.SetNamespace("default")
+
+		_, err := NewHelper(u, c)
+		g.Expect(err).To(MatchError(ContainSubstring(
+			"cannot determine the GroupVersionKind of *unstructured.Unstructured object 'default/test'")))
+
+		_, err = NewHelper(&metav1.PartialObjectMetadata{}, c)
+		g.Expect(err).To(MatchError(ContainSubstring("apiVersion and kind must be set")))
+
+		_, err = NewHelper(&unregistered{}, c)
+		g.Expect(err).To(MatchError(ContainSubstring("cannot determine the GroupVersionKind of *patch.unregistered object")))
+	})
+
+	t.Run("returns an error for an object of another kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(newConfigMap()).Build()
+		patcher, err := NewHelper(newConfigMap(), c)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		g.Expect(patcher.Patch(ctx, u)).To(MatchError(ContainSubstring("unmatched GroupVersionKind")))
+	})
+}
+
+// unregistered is an object whose type is not registered in any scheme.
+type unregistered struct {
+	corev1.ConfigMap
+}
+
+func (u *unregistered) DeepCopyObject() runtime.Object {
+	return &unregistered{ConfigMap: *u.ConfigMap.DeepCopy()}
+}
@@ -15,5 +15,5 @@ limitations under the License.
 */
 
 // Package probes contains a helper to configure sensible default health and ready probes on a controller-runtime
-// manager.
+// manager, and a Registry of named readiness checks which can be added and removed at runtime.
 package probes
@@ -0,0 +1,323 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+const (
+	// DefaultCheckTimeout is the default timeout of a registered check.
+	DefaultCheckTimeout = 5 * time.Second
+
+	// ReadyzCheckName is the name of the readyz check of the manager
+	// reporting the readiness of a Registry.
+	ReadyzCheckName = "checks"
+)
+
+// Status is the status of a check, or of all the checks of a Registry.
+type Status string
+
+const (
+	// StatusOK means the check passed.
+	StatusOK Status = "ok"
+	// StatusDegraded means a check marked as degraded on failure failed.
+	StatusDegraded Status = "degraded"
+	// StatusFailed means the check failed, or timed out.
+	StatusFailed Status = "failed"
+	// StatusSkipped means a dependency of the check did not pass.
+	StatusSkipped Status = "skipped"
+)
+
+// Check is a named readiness check, e.g. to verify that the
+// storage of the controller is writable.
+type Check func(ctx context.Context) error
+
+// CheckOption configures a registered check.
+type CheckOption func(o *checkOptions)
+
+type checkOptions struct {
+	timeout      time.Duration
+	degraded     bool
+	dependencies []string
+}
+
+// WithTimeout sets the timeout of the check. A check which does not
+// return within the timeout fails. Defaults to DefaultCheckTimeout.
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(o *checkOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithDegradedOnFailure marks the check as degraded but not fatal: when it
+// fails, the failure is reported in the response but the probe passes.
+func WithDegradedOnFailure() CheckOption {
+	return func(o *checkOptions) {
+		o.degraded = true
+	}
+}
+
+// WithDependencies sets the names of the checks the check depends on. The
+// check runs once its dependencies passed, and is skipped otherwise, e.g.
+// when a dependency failed or is not registered. A skipped check fails the
+// probe unless marked with WithDegradedOnFailure.
+func WithDependencies(names ...string) CheckOption {
+	return func(o *checkOptions) {
+		o.dependencies = names
+	}
+}
+
+// CheckResult is the result of a check.
+type CheckResult struct {
+	// Name is the name of the check.
+	Name string `json:"name"`
+	// Status is the status of the check.
+	Status Status `json:"status"`
+	// Error is the error of the failed check, or the reason it was skipped.
+	Error string `json:"error,omitempty"`
+	// Duration is the time the check took to run.
+	Duration string `json:"duration,omitempty"`
+}
+
+// Response is the result of the checks of a Registry.
+type Response struct {
+	// Status is StatusFailed if a check which is not marked as degraded
+	// did not pass, StatusDegraded if a check marked as degraded did not
+	// pass, and StatusOK otherwise.
+	Status Status `json:"status"`
+	// Checks holds the results of the checks sorted by name.
+	Checks []CheckResult `json:"checks"`
+}
+
+// Registry holds named readiness checks which can be added and removed while
+// the manager is running, e.g. when shards are added. The registry serves
+// the results of the checks as JSON, and is wired into the readyz endpoint
+// of a manager with SetupReadyz.
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]*registeredCheck
+}
+
+type registeredCheck struct {
+	check Check
+	checkOptions
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{checks: map[string]*registeredCheck{}}
+}
+
+// Add registers the check with the given name. It returns an error if a
+// check with the same name is already registered, or if the dependencies
+// of the check would form a cycle.
+func (r *Registry) Add(name string, check Check, opts ...CheckOption) error {
+	if name == "" {
+		return errors.New("check name cannot be empty")
+	}
+	if check == nil {
+		return fmt.Errorf("check '%s' cannot be nil", name)
+	}
+	c := &registeredCheck{check: check, checkOptions: checkOptions{timeout: DefaultCheckTimeout}}
+	for _, opt := range opts {
+		opt(&c.checkOptions)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.checks[name]; ok {
+		return fmt.Errorf("check '%s' is already registered", name)
+	}
+	if r.dependsOn(c.dependencies, name, map[string]bool{}) {
+		return fmt.Errorf("check '%s' has a dependency cycle", name)
+	}
+	r.checks[name] = c
+	return nil
+}
+
+// Remove unregisters the check with the given name, if any.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checks, name)
+}
+
+// dependsOn returns true if any of the given dependencies is, or
+// transitively depends on, the check with the given name.
+func (r *Registry) dependsOn(dependencies []string, name string, visited map[string]bool) bool {
+	for _, dep := range dependencies {
+		if dep == name {
+			return true
+		}
+		if visited[dep] {
+			continue
+		}
+		visited[dep] = true
+		if c, ok := r.checks[dep]; ok && r.dependsOn(c.dependencies, name, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the registered checks concurrently, each check waiting for its
+// dependencies, and returns their results.
+func (r *Registry) Run(ctx context.Context) *Response {
+	r.mu.RLock()
+	checks := make(map[string]*registeredCheck, len(r.checks))
+	for name, c := range r.checks {
+		checks[name] = c
+	}
+	r.mu.RUnlock()
+
+	results := make(map[string]*CheckResult, len(checks))
+	done := make(map[string]chan struct{}, len(checks))
+	for name := range checks {
+		results[name] = &CheckResult{Name: name}
+		done[name] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for name, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[name])
+			result := results[name]
+
+			for _, dep := range c.dependencies {
+				if _, ok := checks[dep]; !ok {
+					result.Status = StatusSkipped
+					result.Error = fmt.Sprintf("dependency '%s' is not registered", dep)
+					return
+				}
+				<-done[dep]
+				if results[dep].Status != StatusOK {
+					result.Status = StatusSkipped
+					result.Error = fmt.Sprintf("dependency '%s' did not pass", dep)
+					return
+				}
+			}
+
+			start := time.Now()
+			err := runWithTimeout(ctx, c.check, c.timeout)
+			result.Duration = time.Since(start).Round(time.Millisecond).String()
+			switch {
+			case err == nil:
+				result.Status = StatusOK
+			case c.degraded:
+				result.Status = StatusDegraded
+				result.Error = err.Error()
+			default:
+				result.Status = StatusFailed
+				result.Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	resp := &Response{Status: StatusOK, Checks: make([]CheckResult, 0, len(results))}
+	for name, result := range results {
+		switch {
+		case result.Status == StatusOK:
+		case result.Status == StatusFailed || !checks[name].degraded:
+			resp.Status = StatusFailed
+		case resp.Status != StatusFailed:
+			resp.Status = StatusDegraded
+		}
+		resp.Checks = append(resp.Checks, *result)
+	}
+	sort.Slice(resp.Checks, func(i, j int) bool {
+		return resp.Checks[i].Name < resp.Checks[j].Name
+	})
+	return resp
+}
+
+// runWithTimeout runs the check and returns an error if it does not return
+// within the timeout, even if the check does not honour the context.
+func runWithTimeout(ctx context.Context, check Check, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check(ctx)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		return ctx.Err()
+	}
+}
+
+// ServeHTTP runs the checks and writes the Response as JSON, with the
+// status code 503 if the Response status is StatusFailed and 200 otherwise.
+// The handler can be served next to the manager probes, e.g. as an extra
+// handler of the metrics server.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	resp := r.Run(req.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if resp.Status == StatusFailed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Checker returns a healthz.Checker which fails when the Response
+// status of the checks is StatusFailed, with the errors of the
+// failed checks.
+func (r *Registry) Checker() healthz.Checker {
+	return func(req *http.Request) error {
+		resp := r.Run(req.Context())
+		if resp.Status != StatusFailed {
+			return nil
+		}
+		var msgs []string
+		for _, c := range resp.Checks {
+			if c.Status != StatusOK && c.Status != StatusDegraded {
+				msgs = append(msgs, fmt.Sprintf("%s: %s", c.Name, c.Error))
+			}
+		}
+		return errors.New(strings.Join(msgs, "; "))
+	}
+}
+
+// SetupReadyz adds the checks of the registry to the readyz endpoint
+// of the given manager, as the ReadyzCheckName check.
+func (r *Registry) SetupReadyz(mgr ctrl.Manager) error {
+	return mgr.AddReadyzCheck(ReadyzCheckName, r.Checker())
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probes_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/runtime/probes"
+)
+
+func passing(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("storage is read-only") }
+
+// slow blocks without honouring the context.
+func slow(context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+type testCheck struct {
+	name  string
+	check probes.Check
+	opts  []probes.CheckOption
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []testCheck
+		wantCode   int
+		wantStatus probes.Status
+		wantChecks []probes.CheckResult
+	}{
+		{
+			name:       "no checks",
+			wantCode:   http.StatusOK,
+			wantStatus: probes.StatusOK,
+			wantChecks: []probes.CheckResult{},
+		},
+		{
+			name: "passing checks",
+			checks: []testCheck{
+				{name: "storage", check: passing},
+				{name: "token-cache", check: passing},
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: probes.StatusOK,
+			wantChecks: []probes.CheckResult{
+				{Name: "storage", Status: probes.StatusOK},
+				{Name: "token-cache", Status: probes.StatusOK},
+			},
+		},
+		{
+			name: "failing check",
+			checks: []testCheck{
+				{name: "storage", check: failing},
+				{name: "token-cache", check: passing},
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: probes.StatusFailed,
+			wantChecks: []probes.CheckResult{
+				{Name: "storage", Status: probes.StatusFailed, Error: "storage is read-only"},
+				{Name: "token-cache", Status: probes.StatusOK},
+			},
+		},
+		{
+			name: "degraded check",
+			checks: []testCheck{
+				{name: "storage", check: failing, opts: []probes.CheckOption{probes.WithDegradedOnFailure()}},
+				{name: "token-cache", check: passing},
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: probes.StatusDegraded,
+			wantChecks: []probes.CheckResult{
+				{Name: "storage", Status: probes.StatusDegraded, Error: "storage is read-only"},
+				{Name: "token-cache", Status: probes.StatusOK},
+			},
+		},
+		{
+			name: "slow check",
+			checks: []testCheck{
+				{name: "leader", check: slow, opts: []probes.CheckOption{probes.WithTimeout(50 * time.Millisecond)}},
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: probes.StatusFailed,
+			wantChecks: []probes.CheckResult{
+				{Name: "leader", Status: probes.StatusFailed, Error: "timed out after 50ms"},
+			},
+		},
+		{
+			name: "failed dependency",
+			checks: []testCheck{
+				{name: "storage", check: failing, opts: []probes.CheckOption{probes.WithDegradedOnFailure()}},
+				{name: "artifacts", check: passing, opts: []probes.CheckOption{probes.WithDependencies("storage")}},
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: probes.StatusFailed,
+			wantChecks: []probes.CheckResult{
+				{Name: "artifacts", Status: probes.StatusSkipped, Error: "dependency 'storage' did not pass"},
+				{Name: "storage", Status: probes.StatusDegraded, Error: "storage is read-only"},
+			},
+		},
+		{
+			name: "passed dependency",
+			checks: []testCheck{
+				{name: "storage", check: passing},
+				{name: "artifacts", check: passing, opts: []probes.CheckOption{probes.WithDependencies("storage")}},
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: probes.StatusOK,
+			wantChecks: []probes.CheckResult{
+				{Name: "artifacts", Status: probes.StatusOK},
+				{Name: "storage", Status: probes.StatusOK},
+			},
+		},
+		{
+			name: "missing dependency of a degraded check",
+			checks: []testCheck{
+				{name: "artifacts", check: passing, opts: []probes.CheckOption{
+					probes.WithDependencies("storage"),
+					probes.WithDegradedOnFailure(),
+				}},
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: probes.StatusDegraded,
+			wantChecks: []probes.CheckResult{
+				{Name: "artifacts", Status: probes.StatusSkipped, Error: "dependency 'storage' is not registered"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := probes.NewRegistry()
+			for _, c := range tt.checks {
+				g.Expect(r.Add(c.name, c.check, c.opts...)).To(Succeed())
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			g.Expect(rec.Code).To(Equal(tt.wantCode))
+			g.Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+			var resp probes.Response
+			g.Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+			g.Expect(resp.Status).To(Equal(tt.wantStatus))
+			for i := range resp.Checks {
+				if resp.Checks[i].Status != probes.StatusSkipped {
+					g.Expect(resp.Checks[i].Duration).ToNot(BeEmpty())
+				}
+				resp.Checks[i].Duration = ""
+			}
+			g.Expect(resp.Checks).To(Equal(tt.wantChecks))
+
+			err := r.Checker()(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			g.Expect(err != nil).To(Equal(tt.wantStatus == probes.StatusFailed))
+		})
+	}
+}
+
+func TestRegistry_AddRemove(t *testing.T) {
+	g := NewWithT(t)
+
+	r := probes.NewRegistry()
+	g.Expect(r.Add("", passing)).To(MatchError("check name cannot be empty"))
+	g.Expect(r.Add("storage", nil)).To(MatchError("check 'storage' cannot be nil"))
+
+	g.Expect(r.Add("shard-1", failing)).To(Succeed())
+	g.Expect(r.Add("shard-1", passing)).To(MatchError("check 'shard-1' is already registered"))
+	g.Expect(r.Run(context.Background()).Status).To(Equal(probes.StatusFailed))
+
+	// Removing the failing shard makes the registry ready again.
+	r.Remove("shard-1")
+	r.Remove("unknown")
+	g.Expect(r.Run(context.Background()).Status).To(Equal(probes.StatusOK))
+
+	// Dependency cycles are rejected, including through removed and re-added checks.
+	g.Expect(r.Add("a", passing)).To(Succeed())
+	g.Expect(r.Add("b", passing, probes.WithDependencies("a"))).To(Succeed())
+	g.Expect(r.Add("self", passing, probes.WithDependencies("self"))).To(MatchError("check 'self' has a dependency cycle"))
+	r.Remove("a")
+	g.Expect(r.Add("a", passing, probes.WithDependencies("b"))).To(MatchError("check 'a' has a dependency cycle"))
+	g.Expect(r.Add("a", passing)).To(Succeed())
+	g.Expect(r.Run(context.Background()).Checks).To(HaveLen(2))
+}
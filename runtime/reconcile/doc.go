@@ -17,5 +17,6 @@ limitations under the License.
 // Package reconcile provides helpers for the reconciliation. They help finalize
 // the results of a reconciliation and also create patch helper options based
 // on the finalized results that can be used with the patch helper during the
-// reconciliation.
+// reconciliation. The Pipeline runs the ordered sub-reconcilers of a
+// reconciliation and aggregates their results for the ResultFinalizer.
 package reconcile
@@ -0,0 +1,220 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	runtimeerrors "github.com/fluxcd/pkg/runtime/errors"
+)
+
+// ErrorPolicy defines how a Pipeline handles the error of a step.
+type ErrorPolicy string
+
+const (
+	// StopOnError stops the pipeline at the first step returning an error.
+	StopOnError ErrorPolicy = "StopOnError"
+	// ContinueOnError runs all the steps of the pipeline regardless of
+	// their errors, which are joined.
+	ContinueOnError ErrorPolicy = "ContinueOnError"
+)
+
+// StepFunc is the function of a pipeline step, reconciling one aspect of
+// the object.
+type StepFunc[T conditions.Setter] func(ctx context.Context, obj T) (ctrl.Result, error)
+
+// Step is a named sub-reconciler of a Pipeline.
+type Step[T conditions.Setter] struct {
+	// Name is the name of the step, unique in the pipeline.
+	Name string
+	// ConditionType is the type of the condition owned by the step, if any.
+	// When the step fails without updating the condition, the condition is
+	// set to its abnormal status with the error as message: True for the
+	// negative polarity condition types, e.g. FetchFailed, and False for
+	// the others.
+	ConditionType string
+	// FailureReason is the reason of the condition set on failure, unless
+	// the error is a runtimeerrors.ErrTemporary with a reason. Defaults to
+	// meta.ReconciliationFailedReason.
+	FailureReason string
+	// Func is the function of the step.
+	Func StepFunc[T]
+}
+
+// Pipeline runs an ordered list of steps reconciling an object, and
+// aggregates their results with LowestRequeuingResult, for finalizing them
+// with the ResultFinalizer.
+type Pipeline[T conditions.Setter] struct {
+	policy ErrorPolicy
+	steps  []Step[T]
+	err    error
+}
+
+// NewPipeline returns a new Pipeline handling the errors of its steps with
+// the given policy.
+func NewPipeline[T conditions.Setter](policy ErrorPolicy) *Pipeline[T] {
+	return &Pipeline[T]{policy: policy}
+}
+
+// Add appends the given steps to the pipeline. It is meant to be called when
+// setting up the reconciler. A step without name or function, or with a name
+// already registered, is not added and the error is returned by Run.
+func (p *Pipeline[T]) Add(steps ...Step[T]) *Pipeline[T] {
+	for _, step := range steps {
+		if err := p.validateStep(step); err != nil {
+			p.err = errors.Join(p.err, err)
+			continue
+		}
+		p.steps = append(p.steps, step)
+	}
+	return p
+}
+
+// validateStep returns an error if the step has no name or function, or if
+// its name is already registered.
+func (p *Pipeline[T]) validateStep(step Step[T]) error {
+	if step.Name == "" || step.Func == nil {
+		return errors.New("pipeline step must have a name and a function")
+	}
+	for _, s := range p.steps {
+		if s.Name == step.Name {
+			return fmt.Errorf("pipeline step '%s' is already registered", step.Name)
+		}
+	}
+	return nil
+}
+
+// OwnedConditions returns the condition types owned by the steps, in order,
+// e.g. for Conditions.Owned or AddPatchOptions.
+func (p *Pipeline[T]) OwnedConditions() []string {
+	var owned []string
+	for _, step := range p.steps {
+		if step.ConditionType != "" {
+			owned = append(owned, step.ConditionType)
+		}
+	}
+	return owned
+}
+
+// Run runs the steps in order and returns the lowest requeuing result of the
+// run steps along with their error. With StopOnError, the first error is
+// returned as is. With ContinueOnError, the errors are joined, which keeps
+// them inspectable with errors.As by the ResultFinalizer. A panicking step
+// is recovered into an error naming the step. If steps were rejected by Add,
+// no step is run and the rejection error is returned.
+func (p *Pipeline[T]) Run(ctx context.Context, obj T) (ctrl.Result, error) {
+	if p.err != nil {
+		return ctrl.Result{}, p.err
+	}
+	var result ctrl.Result
+	var errs []error
+	for _, step := range p.steps {
+		res, err := p.runStep(ctx, step, obj)
+		result = LowestRequeuingResult(result, res)
+		if err == nil {
+			continue
+		}
+		if p.policy != ContinueOnError {
+			return result, err
+		}
+		errs = append(errs, err)
+	}
+	return result, errors.Join(errs...)
+}
+
+// runStep runs the step, recovering its panic, and records its failure in
+// the condition it owns if the step left the condition untouched.
+func (p *Pipeline[T]) runStep(ctx context.Context, step Step[T], obj T) (res ctrl.Result, err error) {
+	var before *metav1.Condition
+	if step.ConditionType != "" {
+		before = conditions.Get(obj, step.ConditionType)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			res, err = ctrl.Result{}, fmt.Errorf("pipeline step '%s' panicked: %v", step.Name, r)
+		}
+		if err != nil && step.ConditionType != "" && conditionUnchanged(before, conditions.Get(obj, step.ConditionType)) {
+			markStepFailure(obj, step, err)
+		}
+	}()
+	return step.Func(ctx, obj)
+}
+
+// conditionUnchanged returns true if the condition was neither added,
+// removed nor modified.
+func conditionUnchanged(before, after *metav1.Condition) bool {
+	if before == nil || after == nil {
+		return before == after
+	}
+	return before.Status == after.Status && before.Reason == after.Reason &&
+		before.Message == after.Message && before.ObservedGeneration == after.ObservedGeneration
+}
+
+// markStepFailure sets the condition owned by the step to its abnormal
+// status. Waiting is not a failure, the condition is left untouched.
+func markStepFailure[T conditions.Setter](obj T, step Step[T], err error) {
+	var waitErr *runtimeerrors.ErrWaiting
+	if errors.As(err, &waitErr) {
+		return
+	}
+
+	reason := step.FailureReason
+	if reason == "" {
+		reason = meta.ReconciliationFailedReason
+	}
+	var tempErr *runtimeerrors.ErrTemporary
+	if errors.As(err, &tempErr) && tempErr.Reason != "" {
+		reason = tempErr.Reason
+	}
+
+	if p, _ := conditions.GetPolarity(obj, step.ConditionType); p == conditions.NegativePolarity {
+		conditions.MarkTrue(obj, step.ConditionType, reason, "%s", err.Error())
+		return
+	}
+	conditions.MarkFalse(obj, step.ConditionType, reason, "%s", err.Error())
+}
+
+// LowestRequeuingResult returns the result requeuing the soonest of the
+// given results, weighting them as follows:
+//
+//   - An immediate requeue, i.e. Requeue without RequeueAfter, is the lowest.
+//   - A requeue after a period is lower than one after a longer period.
+//   - No requeue, i.e. the zero result, is the highest.
+func LowestRequeuingResult(i, j ctrl.Result) ctrl.Result {
+	switch {
+	case i.IsZero():
+		return j
+	case j.IsZero():
+		return i
+	case i.RequeueAfter == 0:
+		return i
+	case j.RequeueAfter == 0:
+		return j
+	case j.RequeueAfter < i.RequeueAfter:
+		return j
+	default:
+		return i
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+	runtimeerrors "github.com/fluxcd/pkg/runtime/errors"
+)
+
+// pipelineObject is a fake object defining the polarity of its conditions.
+type pipelineObject struct {
+	*testdata.Fake
+}
+
+func (pipelineObject) GetConditionPolarity(conditionType string) (conditions.Polarity, bool) {
+	switch conditionType {
+	case fetchFailedCondition:
+		return conditions.NegativePolarity, true
+	case artifactInStorageCondition:
+		return conditions.PositivePolarity, true
+	}
+	return "", false
+}
+
+func TestLowestRequeuingResult(t *testing.T) {
+	immediate := ctrl.Result{Requeue: true}
+	short := ctrl.Result{RequeueAfter: time.Second}
+	long := ctrl.Result{RequeueAfter: time.Minute}
+	none := ctrl.Result{}
+
+	for _, tt := range []struct {
+		name string
+		i, j ctrl.Result
+		want ctrl.Result
+	}{
+		{name: "none and none", i: none, j: none, want: none},
+		{name: "none and immediate", i: none, j: immediate, want: immediate},
+		{name: "immediate and none", i: immediate, j: none, want: immediate},
+		{name: "none and period", i: none, j: long, want: long},
+		{name: "period and none", i: long, j: none, want: long},
+		{name: "immediate and period", i: immediate, j: short, want: immediate},
+		{name: "period and immediate", i: short, j: immediate, want: immediate},
+		{name: "longer and shorter period", i: long, j: short, want: short},
+		{name: "shorter and longer period", i: short, j: long, want: short},
+		{name: "requeue with period", i: long, j: ctrl.Result{Requeue: true, RequeueAfter: time.Second}, want: ctrl.Result{Requeue: true, RequeueAfter: time.Second}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(LowestRequeuingResult(tt.i, tt.j)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPipeline_Run(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	errStore := errors.New("store failed")
+
+	type outcome struct {
+		result ctrl.Result
+		err    error
+		panic  bool
+	}
+
+	for _, tt := range []struct {
+		name           string
+		policy         ErrorPolicy
+		fetch          outcome
+		store          outcome
+		wantResult     ctrl.Result
+		wantErr        string
+		wantRun        []string
+		wantConditions []metav1.Condition
+	}{
+		{
+			name:       "all steps succeed",
+			policy:     StopOnError,
+			fetch:      outcome{result: ctrl.Result{RequeueAfter: time.Minute}},
+			store:      outcome{result: ctrl.Result{RequeueAfter: 10 * time.Minute}},
+			wantResult: ctrl.Result{RequeueAfter: time.Minute},
+			wantRun:    []string{"fetch", "store"},
+		},
+		{
+			name:       "immediate requeue wins",
+			policy:     StopOnError,
+			fetch:      outcome{result: ctrl.Result{RequeueAfter: time.Minute}},
+			store:      outcome{result: ctrl.Result{Requeue: true}},
+			wantResult: ctrl.Result{Requeue: true},
+			wantRun:    []string{"fetch", "store"},
+		},
+		{
+			name:       "stops on error",
+			policy:     StopOnError,
+			fetch:      outcome{err: errFetch},
+			wantErr:    "fetch failed",
+			wantRun:    []string{"fetch"},
+			wantResult: ctrl.Result{},
+			wantConditions: []metav1.Condition{
+				{Type: fetchFailedCondition, Status: metav1.ConditionTrue, Reason: "FetchFailedReason", Message: "fetch failed"},
+			},
+		},
+		{
+			name:       "continues on error",
+			policy:     ContinueOnError,
+			fetch:      outcome{err: errFetch},
+			store:      outcome{result: ctrl.Result{RequeueAfter: time.Minute}, err: errStore},
+			wantErr:    "fetch failed\nstore failed",
+			wantRun:    []string{"fetch", "store"},
+			wantResult: ctrl.Result{RequeueAfter: time.Minute},
+			wantConditions: []metav1.Condition{
+				{Type: fetchFailedCondition, Status: metav1.ConditionTrue, Reason: "FetchFailedReason", Message: "fetch failed"},
+				{Type: artifactInStorageCondition, Status: metav1.ConditionFalse, Reason: meta.ReconciliationFailedReason, Message: "store failed"},
+			},
+		},
+		{
+			name:       "recovers a panic",
+			policy:     ContinueOnError,
+			fetch:      outcome{panic: true},
+			store:      outcome{result: ctrl.Result{RequeueAfter: time.Minute}},
+			wantErr:    "pipeline step 'fetch' panicked: boom",
+			wantRun:    []string{"fetch", "store"},
+			wantResult: ctrl.Result{RequeueAfter: time.Minute},
+			wantConditions: []metav1.Condition{
+				{Type: fetchFailedCondition, Status: metav1.ConditionTrue, Reason: "FetchFailedReason", Message: "pipeline step 'fetch' panicked: boom"},
+			},
+		},
+		{
+			name:       "uses the reason of a temporary error",
+			policy:     StopOnError,
+			fetch:      outcome{err: runtimeerrors.NewTemporary(errFetch, time.Second, "RateLimited")},
+			wantErr:    "fetch failed",
+			wantRun:    []string{"fetch"},
+			wantResult: ctrl.Result{},
+			wantConditions: []metav1.Condition{
+				{Type: fetchFailedCondition, Status: metav1.ConditionTrue, Reason: "RateLimited", Message: "fetch failed"},
+			},
+		},
+		{
+			name:       "leaves the condition untouched when waiting",
+			policy:     StopOnError,
+			fetch:      outcome{err: runtimeerrors.NewWaiting(time.Second, meta.DependencyNotReadyReason, "waiting")},
+			wantErr:    "waiting",
+			wantRun:    []string{"fetch"},
+			wantResult: ctrl.Result{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var run []string
+			stepFunc := func(name string, o outcome) StepFunc[pipelineObject] {
+				return func(ctx context.Context, obj pipelineObject) (ctrl.Result, error) {
+					run = append(run, name)
+					if o.panic {
+						panic("boom")
+					}
+					return o.result, o.err
+				}
+			}
+
+			p := NewPipeline[pipelineObject](tt.policy).Add(
+				Step[pipelineObject]{
+					Name:          "fetch",
+					ConditionType: fetchFailedCondition,
+					FailureReason: "FetchFailedReason",
+					Func:          stepFunc("fetch", tt.fetch),
+				},
+				Step[pipelineObject]{
+					Name:          "store",
+					ConditionType: artifactInStorageCondition,
+					Func:          stepFunc("store", tt.store),
+				},
+			)
+			g.Expect(p.OwnedConditions()).To(Equal([]string{fetchFailedCondition, artifactInStorageCondition}))
+
+			obj := pipelineObject{&testdata.Fake{}}
+			result, err := p.Run(context.TODO(), obj)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(result).To(Equal(tt.wantResult))
+			g.Expect(run).To(Equal(tt.wantRun))
+
+			g.Expect(obj.GetConditions()).To(HaveLen(len(tt.wantConditions)))
+			for _, want := range tt.wantConditions {
+				got := conditions.Get(obj, want.Type)
+				g.Expect(got).ToNot(BeNil())
+				g.Expect(got.Status).To(Equal(want.Status))
+				g.Expect(got.Reason).To(Equal(want.Reason))
+				g.Expect(got.Message).To(Equal(want.Message))
+			}
+		})
+	}
+}
+
+func TestPipeline_Run_StepOwnsCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := pipelineObject{&testdata.Fake{}}
+	conditions.MarkTrue(obj, fetchFailedCondition, "OldReason", "old failure")
+
+	p := NewPipeline[pipelineObject](StopOnError).Add(Step[pipelineObject]{
+		Name:          "fetch",
+		ConditionType: fetchFailedCondition,
+		Func: func(ctx context.Context, obj pipelineObject) (ctrl.Result, error) {
+			conditions.MarkTrue(obj, fetchFailedCondition, "AuthFailed", "authentication failed")
+			return ctrl.Result{}, errors.New("fetch failed")
+		},
+	})
+
+	// The condition updated by the step on failure is kept as is.
+	_, err := p.Run(context.TODO(), obj)
+	g.Expect(err).To(MatchError("fetch failed"))
+	got := conditions.Get(obj, fetchFailedCondition)
+	g.Expect(got.Reason).To(Equal("AuthFailed"))
+	g.Expect(got.Message).To(Equal("authentication failed"))
+}
+
+func TestPipeline_Add(t *testing.T) {
+	g := NewWithT(t)
+
+	var ran bool
+	noop := func(ctx context.Context, obj pipelineObject) (ctrl.Result, error) {
+		ran = true
+		return ctrl.Result{}, nil
+	}
+	p := NewPipeline[pipelineObject](StopOnError).Add(Step[pipelineObject]{Name: "fetch", Func: noop})
+	_, err := p.Run(context.TODO(), pipelineObject{&testdata.Fake{}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ran).To(BeTrue())
+
+	p.Add(Step[pipelineObject]{Name: "fetch", Func: noop}, Step[pipelineObject]{Name: "store"})
+	g.Expect(p.OwnedConditions()).To(BeEmpty())
+
+	ran = false
+	_, err = p.Run(context.TODO(), pipelineObject{&testdata.Fake{}})
+	g.Expect(err).To(MatchError(ContainSubstring("pipeline step 'fetch' is already registered")))
+	g.Expect(err).To(MatchError(ContainSubstring("pipeline step must have a name and a function")))
+	g.Expect(ran).To(BeFalse())
+}
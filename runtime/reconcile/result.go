@@ -18,11 +18,14 @@ package reconcile
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
+	runtimeerrors "github.com/fluxcd/pkg/runtime/errors"
 	"github.com/fluxcd/pkg/runtime/object"
 	"github.com/fluxcd/pkg/runtime/patch"
 )
@@ -39,7 +42,8 @@ type Conditions struct {
 	// on.
 	Summarize []string
 	// NegativePolarity conditions are the conditions in Summarize with negative
-	// polarity.
+	// polarity. If empty, the polarities registered with
+	// conditions.RegisterPolarity are used.
 	NegativePolarity []string
 }
 
@@ -64,9 +68,10 @@ type IsResultSuccess func(ctrl.Result, error) bool
 // compliant object status and appropriate runtime results based on the status
 // observations.
 type ResultFinalizer struct {
-	isSuccess       IsResultSuccess
-	readySuccessMsg string
-	conditions      []Conditions
+	isSuccess         IsResultSuccess
+	readySuccessMsg   string
+	conditions        []Conditions
+	violationCallback func(violation string)
 }
 
 // NewResultFinalizer returns a new ResultFinalizer.
@@ -78,6 +83,24 @@ func NewResultFinalizer(isSuccess IsResultSuccess, readySuccessMsg string, condi
 	}
 }
 
+// WithViolationCallback sets a callback invoked for each inconsistent
+// combination of conditions corrected by Finalize, e.g. Reconciling removed
+// because Stalled is True, with a description naming the condition types
+// and their status before and after the correction. It allows controllers
+// to log or count the corrections to find the bugs causing them, while the
+// corrections stay the same.
+func (rs *ResultFinalizer) WithViolationCallback(callback func(violation string)) *ResultFinalizer {
+	rs.violationCallback = callback
+	return rs
+}
+
+// violation reports a correction made to the conditions of the object.
+func (rs ResultFinalizer) violation(format string, args ...any) {
+	if rs.violationCallback != nil {
+		rs.violationCallback(fmt.Sprintf(format, args...))
+	}
+}
+
 // Finalize computes the result of reconciliation. It takes ctrl.Result, error from
 // the reconciliation, and a conditions.Setter with conditions, and analyzes
 // them to return a reconciliation error. It mutates the object status
@@ -97,6 +120,9 @@ func (rs ResultFinalizer) Finalize(obj conditions.Setter, res ctrl.Result, recEr
 	// If reconcile error isn't nil, a retry needs to be attempted. Since
 	// it's not stalled situation, ensure Stalled condition is removed.
 	if recErr != nil {
+		if conditions.IsTrue(obj, meta.StalledCondition) {
+			rs.violation("%s=True removed: reconciliation failed with an error", meta.StalledCondition)
+		}
 		conditions.Delete(obj, meta.StalledCondition)
 	}
 
@@ -110,12 +136,19 @@ func (rs ResultFinalizer) Finalize(obj conditions.Setter, res ctrl.Result, recEr
 		// requeue is requested in the ctrl.Result, it is not a stalled
 		// situation. Ensure Stalled condition is removed.
 		if !res.IsZero() && !rs.isSuccess(res, nil) {
+			if conditions.IsTrue(obj, meta.StalledCondition) {
+				rs.violation("%s=True removed: requeue requested", meta.StalledCondition)
+			}
 			conditions.Delete(obj, meta.StalledCondition)
 		}
 		// If it's still Stalled and Ready is unset or True, ensure Ready value
 		// matches with Stalled.
 		overwriteReady := conditions.IsUnknown(obj, meta.ReadyCondition) || conditions.IsTrue(obj, meta.ReadyCondition)
 		if conditions.IsTrue(obj, meta.StalledCondition) && overwriteReady {
+			if conditions.IsTrue(obj, meta.ReadyCondition) {
+				rs.violation("%s=True overwritten with %s=False: %s=True",
+					meta.ReadyCondition, meta.ReadyCondition, meta.StalledCondition)
+			}
 			sc := conditions.Get(obj, meta.StalledCondition)
 			conditions.MarkFalse(obj, meta.ReadyCondition, sc.Reason, "%s", sc.Message)
 		}
@@ -124,6 +157,12 @@ func (rs ResultFinalizer) Finalize(obj conditions.Setter, res ctrl.Result, recEr
 	// If it's a successful result or Stalled=True, ensure Reconciling is
 	// removed.
 	if successResult || conditions.IsTrue(obj, meta.StalledCondition) {
+		// Reconciling is expected to be removed on success, only report
+		// it being set along with Stalled.
+		if conditions.IsTrue(obj, meta.StalledCondition) && conditions.Has(obj, meta.ReconcilingCondition) {
+			rs.violation("%s=%s removed: %s=True", meta.ReconcilingCondition,
+				conditions.Get(obj, meta.ReconcilingCondition).Status, meta.StalledCondition)
+		}
 		conditions.Delete(obj, meta.ReconcilingCondition)
 	}
 
@@ -135,6 +174,10 @@ func (rs ResultFinalizer) Finalize(obj conditions.Setter, res ctrl.Result, recEr
 	// reason, preserve the value.
 	if recErr != nil {
 		if conditions.IsUnknown(obj, meta.ReadyCondition) || conditions.IsTrue(obj, meta.ReadyCondition) {
+			if conditions.IsTrue(obj, meta.ReadyCondition) {
+				rs.violation("%s=True overwritten with %s=False: reconciliation failed with an error",
+					meta.ReadyCondition, meta.ReadyCondition)
+			}
 			conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, "%s", recErr.Error())
 		}
 	}
@@ -182,6 +225,58 @@ func (rs ResultFinalizer) Finalize(obj conditions.Setter, res ctrl.Result, recEr
 	return recErr
 }
 
+// FinalizeResult is like Finalize, but it also computes the ctrl.Result of
+// the reconciliation for the runtime errors requesting a requeue after a
+// given duration:
+//   - runtimeerrors.ErrWaiting is not considered a failure. The object is
+//     marked as Reconciling and not Ready with the reason and message of the
+//     error, and no error is returned.
+//   - runtimeerrors.ErrTemporary marks the object as Reconciling and not
+//     Ready with the reason and message of the error. The error is not
+//     returned, for the requeue to happen after the given duration instead
+//     of with exponential backoff.
+//
+// The returned ctrl.Result requeues after the duration of the error. When the
+// duration is zero, the error is returned for the object to be requeued with
+// exponential backoff. Other results and errors are returned as is.
+func (rs ResultFinalizer) FinalizeResult(obj conditions.Setter, res ctrl.Result, recErr error) (ctrl.Result, error) {
+	var waitingErr *runtimeerrors.ErrWaiting
+	var temporaryErr *runtimeerrors.ErrTemporary
+	var requeueAfter time.Duration
+	var dropErr bool
+	switch {
+	case errors.As(recErr, &waitingErr):
+		reason := waitingErr.Reason
+		if reason == "" {
+			reason = meta.ProgressingReason
+		}
+		requeueAfter = waitingErr.RequeueAfter
+		conditions.MarkReconciling(obj, reason, "%s", recErr.Error())
+		conditions.MarkFalse(obj, meta.ReadyCondition, reason, "%s", recErr.Error())
+		if requeueAfter > 0 {
+			recErr = nil
+		}
+	case errors.As(recErr, &temporaryErr):
+		reason := temporaryErr.Reason
+		if reason == "" {
+			reason = meta.FailedReason
+		}
+		requeueAfter = temporaryErr.RequeueAfter
+		conditions.MarkReconciling(obj, reason, "%s", recErr.Error())
+		conditions.MarkFalse(obj, meta.ReadyCondition, reason, "%s", recErr.Error())
+		dropErr = requeueAfter > 0
+	}
+	if requeueAfter > 0 {
+		res = ctrl.Result{RequeueAfter: requeueAfter}
+	}
+
+	recErr = rs.Finalize(obj, res, recErr)
+	if dropErr {
+		recErr = nil
+	}
+	return res, recErr
+}
+
 // ProgressiveStatus helps report the progressive status of an object based on
 // the given status value and drift information.
 // It always sets Reconciling=True with the given status values.
@@ -19,6 +19,7 @@ package reconcile
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/fluxcd/pkg/runtime/conditions"
 	conditionscheck "github.com/fluxcd/pkg/runtime/conditions/check"
 	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+	runtimeerrors "github.com/fluxcd/pkg/runtime/errors"
 	"github.com/fluxcd/pkg/runtime/patch"
 )
 
@@ -93,6 +95,7 @@ func TestResultFinalizer(t *testing.T) {
 		wantErr                    bool
 		wantLastHandledReconcileAt string
 		assertConditions           []metav1.Condition
+		wantViolations             []string
 	}{
 		{
 			name: "result with error and stalled",
@@ -105,6 +108,7 @@ func TestResultFinalizer(t *testing.T) {
 			assertConditions: []metav1.Condition{
 				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "foo failed"),
 			},
+			wantViolations: []string{"Stalled=True removed: reconciliation failed with an error"},
 		},
 		{
 			name: "result with error, reconciling and stalled",
@@ -121,6 +125,7 @@ func TestResultFinalizer(t *testing.T) {
 				*conditions.TrueCondition(meta.ReconcilingCondition, "SomeReasonX", "some msg X"),
 				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "foo failed"),
 			},
+			wantViolations: []string{"Stalled=True removed: reconciliation failed with an error"},
 		},
 		{
 			name:       "result with error, no ready value, set ready value",
@@ -155,6 +160,7 @@ func TestResultFinalizer(t *testing.T) {
 			assertConditions: []metav1.Condition{
 				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "%s", "foo failed"),
 			},
+			wantViolations: []string{"Ready=True overwritten with Ready=False: reconciliation failed with an error"},
 		},
 		{
 			name: "result with error, not ready and reconciling, no change",
@@ -183,6 +189,7 @@ func TestResultFinalizer(t *testing.T) {
 				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "%s", "foo failed"),
 				*conditions.TrueCondition(meta.ReconcilingCondition, "SomeReasonX", "%s", "some msg X"),
 			},
+			wantViolations: []string{"Ready=True overwritten with Ready=False: reconciliation failed with an error"},
 		},
 		{
 			name: "stalled and reconciling, Ready=False, remove reconciling, retain ready",
@@ -197,6 +204,7 @@ func TestResultFinalizer(t *testing.T) {
 				*conditions.TrueCondition(meta.StalledCondition, "SomeReasonY", "%s", "some msg Y"),
 				*conditions.FalseCondition(meta.ReadyCondition, "SomeReasonZ", "%s", "some msg Z"),
 			},
+			wantViolations: []string{"Reconciling=True removed: Stalled=True"},
 		},
 		{
 			name: "stalled and reconciling, empty ready, remove reconciling, set ready",
@@ -210,6 +218,7 @@ func TestResultFinalizer(t *testing.T) {
 				*conditions.TrueCondition(meta.StalledCondition, "SomeReasonY", "%s", "some msg Y"),
 				*conditions.FalseCondition(meta.ReadyCondition, "SomeReasonY", "%s", "some msg Y"),
 			},
+			wantViolations: []string{"Reconciling=True removed: Stalled=True"},
 		},
 		{
 			name: "stalled and reconciling, Ready=True, remove reconciling, overwrite ready",
@@ -224,6 +233,10 @@ func TestResultFinalizer(t *testing.T) {
 				*conditions.TrueCondition(meta.StalledCondition, "SomeReasonY", "%s", "some msg Y"),
 				*conditions.FalseCondition(meta.ReadyCondition, "SomeReasonY", "%s", "some msg Y"),
 			},
+			wantViolations: []string{
+				"Ready=True overwritten with Ready=False: Stalled=True",
+				"Reconciling=True removed: Stalled=True",
+			},
 		},
 		{
 			name: "not success result due to requeue, remove stalled",
@@ -236,6 +249,7 @@ func TestResultFinalizer(t *testing.T) {
 			assertConditions: []metav1.Condition{
 				*conditions.FalseCondition(meta.ReadyCondition, "SomeReasonY", "%s", "some msg Y"),
 			},
+			wantViolations: []string{"Stalled=True removed: requeue requested"},
 		},
 		{
 			name: "not success result due to arbitrary requeueAfter, remove stalled",
@@ -248,6 +262,7 @@ func TestResultFinalizer(t *testing.T) {
 			assertConditions: []metav1.Condition{
 				*conditions.FalseCondition(meta.ReadyCondition, "SomeReasonY", "%s", "some msg Y"),
 			},
+			wantViolations: []string{"Stalled=True removed: requeue requested"},
 		},
 		{
 			name: "not success result and explicit no requeue, keep stalled, add Ready=False",
@@ -406,6 +421,7 @@ func TestResultFinalizer(t *testing.T) {
 				*conditions.FalseCondition(meta.ReadyCondition, "SomeReasonY", "%s", "some msg Y"),
 				*conditions.TrueCondition(meta.StalledCondition, "SomeReasonY", "%s", "some msg Y"),
 			},
+			wantViolations: []string{"Reconciling=True removed: Stalled=True"},
 		},
 		{
 			name:                "not ready after summarize and result is success, should set error",
@@ -447,10 +463,15 @@ func TestResultFinalizer(t *testing.T) {
 				tt.beforeFunc(obj)
 			}
 
-			rf := NewResultFinalizer(isSuccess, readySuccessMsg, tt.summarizeConditions...)
+			var violations []string
+			rf := NewResultFinalizer(isSuccess, readySuccessMsg, tt.summarizeConditions...).
+				WithViolationCallback(func(violation string) {
+					violations = append(violations, violation)
+				})
 			gotErr := rf.Finalize(obj, tt.result, tt.recErr)
 			g.Expect(gotErr != nil).To(Equal(tt.wantErr))
 			g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(tt.assertConditions))
+			g.Expect(violations).To(Equal(tt.wantViolations))
 			if tt.wantLastHandledReconcileAt != "" {
 				g.Expect(obj.Status.LastHandledReconcileAt).To(Equal(tt.wantLastHandledReconcileAt))
 			}
@@ -482,6 +503,7 @@ func TestResultFinalizer_successNoRequeue(t *testing.T) {
 		wantErr                    bool
 		wantLastHandledReconcileAt string
 		assertConditions           []metav1.Condition
+		wantViolations             []string
 	}{
 		{
 			name: "result with error and stalled",
@@ -494,6 +516,7 @@ func TestResultFinalizer_successNoRequeue(t *testing.T) {
 			assertConditions: []metav1.Condition{
 				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "%s", "foo failed"),
 			},
+			wantViolations: []string{"Stalled=True removed: reconciliation failed with an error"},
 		},
 		{
 			name: "stalled, Ready=True, overwrite ready",
@@ -554,10 +577,15 @@ func TestResultFinalizer_successNoRequeue(t *testing.T) {
 				tt.beforeFunc(obj)
 			}
 
-			rf := NewResultFinalizer(isSuccess, readySuccessMsg)
+			var violations []string
+			rf := NewResultFinalizer(isSuccess, readySuccessMsg).
+				WithViolationCallback(func(violation string) {
+					violations = append(violations, violation)
+				})
 			gotErr := rf.Finalize(obj, tt.result, tt.recErr)
 			g.Expect(gotErr != nil).To(Equal(tt.wantErr))
 			g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(tt.assertConditions))
+			g.Expect(violations).To(Equal(tt.wantViolations))
 			if tt.wantLastHandledReconcileAt != "" {
 				g.Expect(obj.Status.LastHandledReconcileAt).To(Equal(tt.wantLastHandledReconcileAt))
 			}
@@ -567,6 +595,168 @@ func TestResultFinalizer_successNoRequeue(t *testing.T) {
 	}
 }
 
+func TestResultFinalizer_FinalizeResult(t *testing.T) {
+	readySuccessMsg := "Success"
+	successInterval := time.Minute
+	resultSuccess := ctrl.Result{RequeueAfter: successInterval}
+
+	summarizeReadyConditions := Conditions{
+		Target:           meta.ReadyCondition,
+		Owned:            []string{meta.ReadyCondition, meta.ReconcilingCondition, meta.StalledCondition},
+		Summarize:        []string{meta.StalledCondition, meta.ReconcilingCondition},
+		NegativePolarity: []string{meta.StalledCondition, meta.ReconcilingCondition},
+	}
+
+	isSuccess := func(res ctrl.Result, err error) bool {
+		if err != nil || res.RequeueAfter != successInterval || res.Requeue {
+			return false
+		}
+		return true
+	}
+
+	tests := []struct {
+		name                string
+		summarizeConditions []Conditions
+		beforeFunc          func(obj conditions.Setter)
+		result              ctrl.Result
+		recErr              error
+		statusObservedGen   int64
+		wantResult          ctrl.Result
+		wantErr             bool
+		assertConditions    []metav1.Condition
+	}{
+		{
+			name:              "success",
+			result:            resultSuccess,
+			statusObservedGen: 1,
+			wantResult:        resultSuccess,
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReadyCondition, meta.SucceededReason, "%s", readySuccessMsg),
+			},
+		},
+		{
+			name:       "generic error",
+			recErr:     errors.New("foo failed"),
+			wantResult: ctrl.Result{},
+			wantErr:    true,
+			assertConditions: []metav1.Condition{
+				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "foo failed"),
+			},
+		},
+		{
+			name:       "waiting",
+			result:     resultSuccess,
+			recErr:     runtimeerrors.NewWaiting(10*time.Second, meta.DependencyNotReadyReason, "dependency '%s' is not ready", "default/dep"),
+			wantResult: ctrl.Result{RequeueAfter: 10 * time.Second},
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReconcilingCondition, meta.DependencyNotReadyReason, "dependency 'default/dep' is not ready"),
+				*conditions.FalseCondition(meta.ReadyCondition, meta.DependencyNotReadyReason, "dependency 'default/dep' is not ready"),
+			},
+		},
+		{
+			name: "wrapped waiting",
+			beforeFunc: func(obj conditions.Setter) {
+				conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "previous success")
+			},
+			recErr: fmt.Errorf("dependency check: %w", &runtimeerrors.ErrWaiting{
+				RequeueAfter: 5 * time.Second,
+				Reason:       meta.DependencyNotReadyReason,
+				Message:      "waiting for dependency",
+			}),
+			wantResult: ctrl.Result{RequeueAfter: 5 * time.Second},
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReconcilingCondition, meta.DependencyNotReadyReason, "dependency check: waiting for dependency"),
+				*conditions.FalseCondition(meta.ReadyCondition, meta.DependencyNotReadyReason, "dependency check: waiting for dependency"),
+			},
+		},
+		{
+			name:                "waiting with summary",
+			summarizeConditions: []Conditions{summarizeReadyConditions},
+			beforeFunc: func(obj conditions.Setter) {
+				conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "previous success")
+			},
+			recErr:     runtimeerrors.NewWaiting(5*time.Second, meta.DependencyNotReadyReason, "waiting for dependency"),
+			wantResult: ctrl.Result{RequeueAfter: 5 * time.Second},
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReconcilingCondition, meta.DependencyNotReadyReason, "waiting for dependency"),
+				*conditions.FalseCondition(meta.ReadyCondition, meta.DependencyNotReadyReason, "waiting for dependency"),
+			},
+		},
+		{
+			name:       "waiting with default reason",
+			recErr:     &runtimeerrors.ErrWaiting{RequeueAfter: time.Second, Err: errors.New("not yet")},
+			wantResult: ctrl.Result{RequeueAfter: time.Second},
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReconcilingCondition, meta.ProgressingReason, "not yet"),
+				*conditions.FalseCondition(meta.ReadyCondition, meta.ProgressingReason, "not yet"),
+			},
+		},
+		{
+			name:       "waiting without requeue duration",
+			recErr:     runtimeerrors.NewWaiting(0, meta.DependencyNotReadyReason, "waiting for dependency"),
+			wantResult: ctrl.Result{},
+			wantErr:    true,
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReconcilingCondition, meta.DependencyNotReadyReason, "waiting for dependency"),
+				*conditions.FalseCondition(meta.ReadyCondition, meta.DependencyNotReadyReason, "waiting for dependency"),
+			},
+		},
+		{
+			name: "temporary",
+			beforeFunc: func(obj conditions.Setter) {
+				conditions.MarkStalled(obj, "SomeReasonX", "some msg X")
+			},
+			recErr:     runtimeerrors.NewTemporary(errors.New("rate limited"), 30*time.Second, "RateLimited"),
+			wantResult: ctrl.Result{RequeueAfter: 30 * time.Second},
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReconcilingCondition, "RateLimited", "rate limited"),
+				*conditions.FalseCondition(meta.ReadyCondition, "RateLimited", "rate limited"),
+			},
+		},
+		{
+			name:       "temporary without requeue duration",
+			recErr:     fmt.Errorf("fetch failed: %w", runtimeerrors.NewTemporary(errors.New("rate limited"), 0, "")),
+			wantResult: ctrl.Result{},
+			wantErr:    true,
+			assertConditions: []metav1.Condition{
+				*conditions.TrueCondition(meta.ReconcilingCondition, meta.FailedReason, "fetch failed: rate limited"),
+				*conditions.FalseCondition(meta.ReadyCondition, meta.FailedReason, "fetch failed: rate limited"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			condns := &conditionscheck.Conditions{
+				NegativePolarity: []string{
+					meta.StalledCondition,
+					meta.ReconcilingCondition,
+				},
+			}
+			checker := conditionscheck.NewChecker(fakeclient.NewClientBuilder().Build(), condns)
+			checker.DisableFetch = true
+
+			obj := &testdata.Fake{}
+			obj.ObjectMeta.Generation = 1
+			obj.Status.ObservedGeneration = tt.statusObservedGen
+
+			if tt.beforeFunc != nil {
+				tt.beforeFunc(obj)
+			}
+
+			rf := NewResultFinalizer(isSuccess, readySuccessMsg, tt.summarizeConditions...)
+			gotResult, gotErr := rf.FinalizeResult(obj, tt.result, tt.recErr)
+			g.Expect(gotErr != nil).To(Equal(tt.wantErr))
+			g.Expect(gotResult).To(Equal(tt.wantResult))
+			g.Expect(obj.Status.Conditions).To(conditions.MatchConditions(tt.assertConditions))
+			// kstatus comformance check.
+			checker.CheckErr(context.TODO(), obj)
+		})
+	}
+}
+
 func TestAddPatchOptions(t *testing.T) {
 	tests := []struct {
 		name                         string
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"fmt"
+)
+
+// ObjectWithRevisions describes an object tracking the revisions it last
+// attempted and applied, usually in .status.lastAttemptedRevision and
+// .status.lastAppliedRevision.
+type ObjectWithRevisions interface {
+	GetLastAttemptedRevision() string
+	SetLastAttemptedRevision(revision string)
+	GetLastAppliedRevision() string
+	SetLastAppliedRevision(revision string)
+}
+
+// RevisionTracker updates the last attempted and applied revisions of an
+// object consistently over a reconciliation: the attempted revision is set
+// when the reconciliation starts with Attempt, and the applied revision is
+// set to the attempted one only when the reconciliation succeeds with
+// Succeed. The applied revision is therefore never ahead of the attempted
+// revision, and a failure leaves the last applied revision untouched.
+type RevisionTracker struct {
+	obj ObjectWithRevisions
+}
+
+// NewRevisionTracker returns a RevisionTracker updating the revisions of
+// the given object.
+func NewRevisionTracker(obj ObjectWithRevisions) *RevisionTracker {
+	return &RevisionTracker{obj: obj}
+}
+
+// Attempt records the given revision as the last attempted revision of the
+// object. It is meant to be called at the start of the reconciliation, once
+// the revision to apply is known.
+func (t *RevisionTracker) Attempt(revision string) {
+	t.obj.SetLastAttemptedRevision(revision)
+}
+
+// Succeed records the last attempted revision as the last applied revision
+// of the object, and returns the message fragment of the success, e.g.
+// "applied revision main@sha1:...".
+func (t *RevisionTracker) Succeed() string {
+	revision := t.obj.GetLastAttemptedRevision()
+	t.obj.SetLastAppliedRevision(revision)
+	return fmt.Sprintf("applied revision %s", revision)
+}
+
+// Fail leaves the last applied revision of the object untouched, and returns
+// the message fragment of the failure, e.g. "failed to apply revision
+// main@sha1:..., last applied main@sha1:...", followed by the given error if
+// not nil.
+func (t *RevisionTracker) Fail(err error) string {
+	msg := fmt.Sprintf("failed to apply revision %s", t.obj.GetLastAttemptedRevision())
+	if applied := t.obj.GetLastAppliedRevision(); applied != "" {
+		msg = fmt.Sprintf("%s, last applied %s", msg, applied)
+	}
+	if err != nil {
+		msg = fmt.Sprintf("%s: %s", msg, err.Error())
+	}
+	return msg
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type fakeObjectWithRevisions struct {
+	lastAttemptedRevision string
+	lastAppliedRevision   string
+}
+
+func (o *fakeObjectWithRevisions) GetLastAttemptedRevision() string  { return o.lastAttemptedRevision }
+func (o *fakeObjectWithRevisions) SetLastAttemptedRevision(r string) { o.lastAttemptedRevision = r }
+func (o *fakeObjectWithRevisions) GetLastAppliedRevision() string    { return o.lastAppliedRevision }
+func (o *fakeObjectWithRevisions) SetLastAppliedRevision(r string)   { o.lastAppliedRevision = r }
+
+func TestRevisionTracker(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &fakeObjectWithRevisions{}
+	tracker := NewRevisionTracker(obj)
+
+	// A failure before any success has no last applied revision.
+	tracker.Attempt("main@sha1:1")
+	g.Expect(obj.lastAttemptedRevision).To(Equal("main@sha1:1"))
+	g.Expect(obj.lastAppliedRevision).To(BeEmpty())
+	g.Expect(tracker.Fail(errors.New("apply failed"))).To(Equal("failed to apply revision main@sha1:1: apply failed"))
+	g.Expect(obj.lastAppliedRevision).To(BeEmpty())
+
+	// A success applies the attempted revision.
+	tracker.Attempt("main@sha1:2")
+	g.Expect(tracker.Succeed()).To(Equal("applied revision main@sha1:2"))
+	g.Expect(obj.lastAttemptedRevision).To(Equal("main@sha1:2"))
+	g.Expect(obj.lastAppliedRevision).To(Equal("main@sha1:2"))
+
+	// A failure keeps the last applied revision.
+	tracker.Attempt("main@sha1:3")
+	g.Expect(obj.lastAttemptedRevision).To(Equal("main@sha1:3"))
+	g.Expect(obj.lastAppliedRevision).To(Equal("main@sha1:2"))
+	g.Expect(tracker.Fail(errors.New("health check failed"))).To(
+		Equal("failed to apply revision main@sha1:3, last applied main@sha1:2: health check failed"))
+	g.Expect(tracker.Fail(nil)).To(Equal("failed to apply revision main@sha1:3, last applied main@sha1:2"))
+	g.Expect(obj.lastAttemptedRevision).To(Equal("main@sha1:3"))
+	g.Expect(obj.lastAppliedRevision).To(Equal("main@sha1:2"))
+
+	// The recovery of the same revision applies it.
+	tracker.Attempt("main@sha1:3")
+	g.Expect(tracker.Succeed()).To(Equal("applied revision main@sha1:3"))
+	g.Expect(obj.lastAttemptedRevision).To(Equal("main@sha1:3"))
+	g.Expect(obj.lastAppliedRevision).To(Equal("main@sha1:3"))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+// ObjectWithTimeout describes an object with an optional reconcile timeout,
+// usually set in .spec.timeout.
+type ObjectWithTimeout interface {
+	GetTimeout() *metav1.Duration
+}
+
+// timeoutContextKey is the context key for the reconcile timeout.
+type timeoutContextKey struct{}
+
+// ContextWithTimeout returns a copy of the context with a deadline set to the
+// timeout of the reconciliation of the given object. The timeout of the object
+// is used when set to a positive value, otherwise defaultTimeout is used. The
+// timeout is capped to maxTimeout when maxTimeout is positive.
+// The selected timeout is stored in the returned context and can be retrieved
+// with TimeoutFromContext. If the selected timeout is not positive, no deadline
+// is set.
+func ContextWithTimeout(ctx context.Context, obj ObjectWithTimeout,
+	defaultTimeout, maxTimeout time.Duration) (context.Context, context.CancelFunc) {
+	timeout := defaultTimeout
+	if obj != nil {
+		if t := obj.GetTimeout(); t != nil && t.Duration > 0 {
+			timeout = t.Duration
+		}
+	}
+	if maxTimeout > 0 && timeout > maxTimeout {
+		timeout = maxTimeout
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	ctx = context.WithValue(ctx, timeoutContextKey{}, timeout)
+	return context.WithTimeout(ctx, timeout)
+}
+
+// TimeoutFromContext returns the reconcile timeout stored in the context by
+// ContextWithTimeout, and whether it was found.
+func TimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(timeoutContextKey{}).(time.Duration)
+	return timeout, ok
+}
+
+// MarkReadyOnTimeout marks the Ready condition of the object as False with
+// the meta.ReconciliationTimeoutReason reason if the reconciliation failed
+// because the deadline of the context was exceeded. The message mentions the
+// timeout stored in the context by ContextWithTimeout. It returns true if the
+// condition was set.
+func MarkReadyOnTimeout(ctx context.Context, obj conditions.Setter, err error) bool {
+	if err == nil {
+		return false
+	}
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	if timeout, ok := TimeoutFromContext(ctx); ok {
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ReconciliationTimeoutReason,
+			"reconciliation timed out after %s: %s", timeout, err.Error())
+	} else {
+		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ReconciliationTimeoutReason,
+			"reconciliation timed out: %s", err.Error())
+	}
+	return true
+}
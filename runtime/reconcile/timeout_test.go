@@ -0,0 +1,208 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+type fakeObjectWithTimeout struct {
+	timeout *metav1.Duration
+}
+
+func (o fakeObjectWithTimeout) GetTimeout() *metav1.Duration {
+	return o.timeout
+}
+
+func TestContextWithTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		obj            ObjectWithTimeout
+		defaultTimeout time.Duration
+		maxTimeout     time.Duration
+		wantTimeout    time.Duration
+		wantDeadline   bool
+	}{
+		{
+			name:           "object timeout",
+			obj:            fakeObjectWithTimeout{timeout: &metav1.Duration{Duration: 2 * time.Minute}},
+			defaultTimeout: 5 * time.Minute,
+			maxTimeout:     10 * time.Minute,
+			wantTimeout:    2 * time.Minute,
+			wantDeadline:   true,
+		},
+		{
+			name:           "object timeout not set",
+			obj:            fakeObjectWithTimeout{},
+			defaultTimeout: 5 * time.Minute,
+			maxTimeout:     10 * time.Minute,
+			wantTimeout:    5 * time.Minute,
+			wantDeadline:   true,
+		},
+		{
+			name:           "object timeout zero",
+			obj:            fakeObjectWithTimeout{timeout: &metav1.Duration{}},
+			defaultTimeout: 5 * time.Minute,
+			wantTimeout:    5 * time.Minute,
+			wantDeadline:   true,
+		},
+		{
+			name:           "nil object",
+			defaultTimeout: 5 * time.Minute,
+			wantTimeout:    5 * time.Minute,
+			wantDeadline:   true,
+		},
+		{
+			name:           "object timeout capped",
+			obj:            fakeObjectWithTimeout{timeout: &metav1.Duration{Duration: time.Hour}},
+			defaultTimeout: 5 * time.Minute,
+			maxTimeout:     10 * time.Minute,
+			wantTimeout:    10 * time.Minute,
+			wantDeadline:   true,
+		},
+		{
+			name:           "default timeout capped",
+			obj:            fakeObjectWithTimeout{},
+			defaultTimeout: time.Hour,
+			maxTimeout:     10 * time.Minute,
+			wantTimeout:    10 * time.Minute,
+			wantDeadline:   true,
+		},
+		{
+			name:           "no cap",
+			obj:            fakeObjectWithTimeout{timeout: &metav1.Duration{Duration: time.Hour}},
+			defaultTimeout: 5 * time.Minute,
+			wantTimeout:    time.Hour,
+			wantDeadline:   true,
+		},
+		{
+			name: "no timeout",
+			obj:  fakeObjectWithTimeout{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			start := time.Now()
+			ctx, cancel := ContextWithTimeout(context.Background(), tt.obj, tt.defaultTimeout, tt.maxTimeout)
+			defer cancel()
+
+			timeout, ok := TimeoutFromContext(ctx)
+			g.Expect(ok).To(Equal(tt.wantDeadline))
+			g.Expect(timeout).To(Equal(tt.wantTimeout))
+
+			deadline, ok := ctx.Deadline()
+			g.Expect(ok).To(Equal(tt.wantDeadline))
+			if tt.wantDeadline {
+				g.Expect(deadline).To(BeTemporally("~", start.Add(tt.wantTimeout), time.Second))
+			}
+		})
+	}
+}
+
+func TestTimeoutFromContext(t *testing.T) {
+	g := NewWithT(t)
+
+	_, ok := TimeoutFromContext(context.Background())
+	g.Expect(ok).To(BeFalse())
+
+	ctx, cancel := ContextWithTimeout(context.Background(), nil, time.Minute, 0)
+	defer cancel()
+	// The timeout is retrievable from derived contexts.
+	ctx, cancel = context.WithCancel(ctx)
+	defer cancel()
+	timeout, ok := TimeoutFromContext(ctx)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(timeout).To(Equal(time.Minute))
+}
+
+func TestMarkReadyOnTimeout(t *testing.T) {
+	expiredCtx := func() context.Context {
+		ctx, cancel := ContextWithTimeout(context.Background(), nil, time.Nanosecond, 0)
+		t.Cleanup(cancel)
+		<-ctx.Done()
+		return ctx
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		err         error
+		wantMarked  bool
+		wantMessage string
+	}{
+		{
+			name: "no error",
+			ctx:  expiredCtx(),
+		},
+		{
+			name: "other error",
+			ctx:  context.Background(),
+			err:  errors.New("apply failed"),
+		},
+		{
+			name:        "context deadline exceeded",
+			ctx:         expiredCtx(),
+			err:         fmt.Errorf("health check failed: %w", context.DeadlineExceeded),
+			wantMarked:  true,
+			wantMessage: "reconciliation timed out after 1ns: health check failed: context deadline exceeded",
+		},
+		{
+			name:        "error after context deadline exceeded",
+			ctx:         expiredCtx(),
+			err:         errors.New("apply failed"),
+			wantMarked:  true,
+			wantMessage: "reconciliation timed out after 1ns: apply failed",
+		},
+		{
+			name:        "deadline exceeded without timeout in context",
+			ctx:         context.Background(),
+			err:         context.DeadlineExceeded,
+			wantMarked:  true,
+			wantMessage: "reconciliation timed out: context deadline exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &testdata.Fake{}
+			g.Expect(MarkReadyOnTimeout(tt.ctx, obj, tt.err)).To(Equal(tt.wantMarked))
+			if !tt.wantMarked {
+				g.Expect(conditions.Has(obj, meta.ReadyCondition)).To(BeFalse())
+				return
+			}
+			g.Expect(conditions.IsFalse(obj, meta.ReadyCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(meta.ReconciliationTimeoutReason))
+			g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(Equal(tt.wantMessage))
+		})
+	}
+}
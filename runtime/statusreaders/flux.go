@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusreaders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/engine"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/event"
+	kstatusreaders "github.com/fluxcd/cli-utils/pkg/kstatus/polling/statusreaders"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	"github.com/fluxcd/cli-utils/pkg/object"
+
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+)
+
+// FluxGroupSuffix is the suffix of the API groups of the Flux kinds.
+const FluxGroupSuffix = ".toolkit.fluxcd.io"
+
+type fluxStatusReader struct {
+	genericStatusReader engine.StatusReader
+}
+
+// NewFluxStatusReader returns a status reader for the Flux kinds, i.e. the
+// kinds of the API groups ending with FluxGroupSuffix, following the Flux
+// conventions for the Ready, Reconciling and Stalled conditions and the
+// spec.suspend field.
+func NewFluxStatusReader(mapper meta.RESTMapper) engine.StatusReader {
+	genericStatusReader := kstatusreaders.NewGenericStatusReader(mapper, fluxConditions)
+	return &fluxStatusReader{
+		genericStatusReader: genericStatusReader,
+	}
+}
+
+// NewCustomStatusReaders returns the status readers of this package, for
+// setting in the polling.Options of the status poller used by the
+// ssa.ResourceManager to wait for the Jobs and the Flux kinds.
+func NewCustomStatusReaders(mapper meta.RESTMapper) []engine.StatusReader {
+	return []engine.StatusReader{
+		NewCustomJobStatusReader(mapper),
+		NewFluxStatusReader(mapper),
+	}
+}
+
+func (f *fluxStatusReader) Supports(gk schema.GroupKind) bool {
+	return strings.HasSuffix(gk.Group, FluxGroupSuffix)
+}
+
+func (f *fluxStatusReader) ReadStatus(ctx context.Context, reader engine.ClusterReader, resource object.ObjMetadata) (*event.ResourceStatus, error) {
+	return f.genericStatusReader.ReadStatus(ctx, reader, resource)
+}
+
+func (f *fluxStatusReader) ReadStatusForObject(ctx context.Context, reader engine.ClusterReader, resource *unstructured.Unstructured) (*event.ResourceStatus, error) {
+	return f.genericStatusReader.ReadStatusForObject(ctx, reader, resource)
+}
+
+// fluxConditions computes the status of a Flux object. Contrary to the
+// generic kstatus computation, a suspended object is Current, and the Ready
+// condition is taken into account when the object is neither Reconciling nor
+// Stalled.
+func fluxConditions(u *unstructured.Unstructured) (*status.Result, error) {
+	obj := u.UnstructuredContent()
+
+	if suspended, found, err := unstructured.NestedBool(obj, "spec", "suspend"); err == nil && found && suspended {
+		return &status.Result{
+			Status:     status.CurrentStatus,
+			Message:    fmt.Sprintf("%s is suspended", u.GetKind()),
+			Conditions: []status.Condition{},
+		}, nil
+	}
+
+	objc, err := status.GetObjectWithConditions(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	observedGeneration, found, err := unstructured.NestedInt64(obj, "status", "observedGeneration")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return inProgress("LatestGenerationNotObserved",
+			fmt.Sprintf("%s status has not been observed", u.GetKind())), nil
+	}
+	if generation := u.GetGeneration(); observedGeneration != generation {
+		return inProgress("LatestGenerationNotObserved",
+			fmt.Sprintf("%s generation is %d, but latest observed generation is %d",
+				u.GetKind(), generation, observedGeneration)), nil
+	}
+
+	var ready *status.BasicCondition
+	for i, c := range objc.Status.Conditions {
+		switch c.Type {
+		case fluxmeta.StalledCondition:
+			if c.Status == corev1.ConditionTrue {
+				return &status.Result{
+					Status:  status.FailedStatus,
+					Message: c.Message,
+					Conditions: []status.Condition{
+						{
+							Type:    status.ConditionStalled,
+							Status:  corev1.ConditionTrue,
+							Reason:  c.Reason,
+							Message: c.Message,
+						},
+					},
+				}, nil
+			}
+		case fluxmeta.ReconcilingCondition:
+			if c.Status == corev1.ConditionTrue {
+				return inProgress(c.Reason, c.Message), nil
+			}
+		case fluxmeta.ReadyCondition:
+			ready = &objc.Status.Conditions[i]
+		}
+	}
+
+	switch {
+	case ready == nil:
+		return inProgress("ReadyConditionNotFound",
+			fmt.Sprintf("%s has no Ready condition", u.GetKind())), nil
+	case ready.Status == corev1.ConditionTrue:
+		return &status.Result{
+			Status:     status.CurrentStatus,
+			Message:    ready.Message,
+			Conditions: []status.Condition{},
+		}, nil
+	case ready.Status == corev1.ConditionFalse:
+		return &status.Result{
+			Status:  status.FailedStatus,
+			Message: ready.Message,
+			Conditions: []status.Condition{
+				{
+					Type:    status.ConditionStalled,
+					Status:  corev1.ConditionTrue,
+					Reason:  ready.Reason,
+					Message: ready.Message,
+				},
+			},
+		}, nil
+	default:
+		return inProgress(ready.Reason, ready.Message), nil
+	}
+}
+
+// inProgress returns an InProgress result with a Reconciling condition.
+func inProgress(reason, message string) *status.Result {
+	return &status.Result{
+		Status:  status.InProgressStatus,
+		Message: message,
+		Conditions: []status.Condition{
+			{
+				Type:    status.ConditionReconciling,
+				Status:  corev1.ConditionTrue,
+				Reason:  reason,
+				Message: message,
+			},
+		},
+	}
+}
+
+// ComputeFluxStatus computes the status of a Flux object.
+// It returns a status.Result indicating whether the object is InProgress, Failed, or Current.
+// if the status cannot be determined, it returns Unknown with an error message.
+func ComputeFluxStatus(u *unstructured.Unstructured) status.Result {
+	res, err := fluxConditions(u)
+	if err != nil {
+		return status.Result{
+			Status:  status.UnknownStatus,
+			Message: fmt.Sprintf("Failed to compute status: %s", err.Error()),
+		}
+	}
+	return *res
+}
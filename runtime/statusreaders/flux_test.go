@@ -0,0 +1,179 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusreaders
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
+)
+
+func newFluxObject(suspend bool, observedGeneration int64, conditions ...map[string]any) *unstructured.Unstructured {
+	items := make([]any, 0, len(conditions))
+	for _, c := range conditions {
+		items = append(items, c)
+	}
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+		"kind":       "Kustomization",
+		"metadata": map[string]any{
+			"name":       "app",
+			"namespace":  "flux-system",
+			"generation": int64(2),
+		},
+		"spec": map[string]any{
+			"suspend": suspend,
+		},
+	}}
+	if observedGeneration > 0 {
+		u.Object["status"] = map[string]any{
+			"observedGeneration": observedGeneration,
+			"conditions":         items,
+		}
+	}
+	return u
+}
+
+func fluxCondition(conditionType, status, reason, message string) map[string]any {
+	return map[string]any{
+		"type":    conditionType,
+		"status":  status,
+		"reason":  reason,
+		"message": message,
+	}
+}
+
+func Test_fluxConditions(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		obj       *unstructured.Unstructured
+		status    status.Status
+		message   string
+		condition status.ConditionType
+	}{
+		{
+			name: "ready",
+			obj: newFluxObject(false, 2,
+				fluxCondition("Ready", "True", "ReconciliationSucceeded", "Applied revision: main@sha1:1")),
+			status:  status.CurrentStatus,
+			message: "Applied revision: main@sha1:1",
+		},
+		{
+			name: "suspended",
+			obj: newFluxObject(true, 1,
+				fluxCondition("Ready", "False", "ReconciliationFailed", "kustomization path not found")),
+			status:  status.CurrentStatus,
+			message: "Kustomization is suspended",
+		},
+		{
+			name: "stalled",
+			obj: newFluxObject(false, 2,
+				fluxCondition("Ready", "False", "InvalidPath", "kustomization path not found"),
+				fluxCondition("Stalled", "True", "InvalidPath", "kustomization path not found")),
+			status:    status.FailedStatus,
+			message:   "kustomization path not found",
+			condition: status.ConditionStalled,
+		},
+		{
+			name: "reconciling",
+			obj: newFluxObject(false, 2,
+				fluxCondition("Ready", "Unknown", "Progressing", "Reconciliation in progress"),
+				fluxCondition("Reconciling", "True", "Progressing", "Running health checks")),
+			status:    status.InProgressStatus,
+			message:   "Running health checks",
+			condition: status.ConditionReconciling,
+		},
+		{
+			name: "not ready",
+			obj: newFluxObject(false, 2,
+				fluxCondition("Ready", "False", "HealthCheckFailed", "timeout waiting for: [Deployment/apps/app]")),
+			status:    status.FailedStatus,
+			message:   "timeout waiting for: [Deployment/apps/app]",
+			condition: status.ConditionStalled,
+		},
+		{
+			name: "ready unknown",
+			obj: newFluxObject(false, 2,
+				fluxCondition("Ready", "Unknown", "Progressing", "Reconciliation in progress")),
+			status:    status.InProgressStatus,
+			message:   "Reconciliation in progress",
+			condition: status.ConditionReconciling,
+		},
+		{
+			name:      "no Ready condition",
+			obj:       newFluxObject(false, 2),
+			status:    status.InProgressStatus,
+			message:   "Kustomization has no Ready condition",
+			condition: status.ConditionReconciling,
+		},
+		{
+			name: "generation not observed",
+			obj: newFluxObject(false, 1,
+				fluxCondition("Ready", "True", "ReconciliationSucceeded", "Applied revision: main@sha1:1")),
+			status:    status.InProgressStatus,
+			message:   "Kustomization generation is 2, but latest observed generation is 1",
+			condition: status.ConditionReconciling,
+		},
+		{
+			name:      "no status",
+			obj:       newFluxObject(false, 0),
+			status:    status.InProgressStatus,
+			message:   "Kustomization status has not been observed",
+			condition: status.ConditionReconciling,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := fluxConditions(tt.obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result.Status).To(Equal(tt.status))
+			g.Expect(result.Message).To(Equal(tt.message))
+			if tt.condition == "" {
+				g.Expect(result.Conditions).To(BeEmpty())
+				return
+			}
+			g.Expect(result.Conditions).To(HaveLen(1))
+			g.Expect(result.Conditions[0].Type).To(Equal(tt.condition))
+			g.Expect(result.Conditions[0].Message).To(Equal(tt.message))
+		})
+	}
+}
+
+func Test_ComputeFluxStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := newFluxObject(false, 2)
+	obj.Object["status"].(map[string]any)["conditions"] = "invalid"
+	result := ComputeFluxStatus(obj)
+	g.Expect(result.Status).To(Equal(status.UnknownStatus))
+	g.Expect(result.Message).To(ContainSubstring("Failed to compute status"))
+}
+
+func Test_fluxStatusReader_Supports(t *testing.T) {
+	g := NewWithT(t)
+
+	reader := NewFluxStatusReader(nil)
+	g.Expect(reader.Supports(schema.GroupKind{Group: "kustomize.toolkit.fluxcd.io", Kind: "Kustomization"})).To(BeTrue())
+	g.Expect(reader.Supports(schema.GroupKind{Group: "helm.toolkit.fluxcd.io", Kind: "HelmRelease"})).To(BeTrue())
+	g.Expect(reader.Supports(schema.GroupKind{Group: "apps", Kind: "Deployment"})).To(BeFalse())
+	g.Expect(reader.Supports(schema.GroupKind{Group: "toolkit.fluxcd.io.example.com", Kind: "Fake"})).To(BeFalse())
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// readCRDs reads the Custom Resource Definitions from the YAML and JSON files
+// found in the directories of the given filesystems. Documents of other kinds
+// are ignored.
+func readCRDs(sources []crdFS) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for _, src := range sources {
+		paths := src.paths
+		if len(paths) == 0 {
+			paths = []string{"."}
+		}
+		for _, dir := range paths {
+			entries, err := fs.ReadDir(src.fsys, dir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CRD directory '%s': %w", dir, err)
+			}
+			for _, entry := range entries {
+				if entry.IsDir() {
+					continue
+				}
+				switch path.Ext(entry.Name()) {
+				case ".yaml", ".yml", ".json":
+				default:
+					continue
+				}
+				filePath := path.Join(dir, entry.Name())
+				fileCRDs, err := readCRDFile(src.fsys, filePath)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read CRDs from '%s': %w", filePath, err)
+				}
+				crds = append(crds, fileCRDs...)
+			}
+		}
+	}
+	return crds, nil
+}
+
+// readCRDFile decodes the Custom Resource Definitions of a multi-document
+// YAML or JSON file.
+func readCRDFile(fsys fs.FS, name string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return crds, nil
+			}
+			return nil, err
+		}
+		if obj.Object == nil {
+			continue
+		}
+		if obj.GroupVersionKind() != apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") {
+			continue
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+			return nil, err
+		}
+		crds = append(crds, crd)
+	}
+}
@@ -26,7 +26,9 @@ package testenv
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"sync"
+	"testing"
 	"time"
 
 	"github.com/pkg/errors"
@@ -36,9 +38,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/kubernetes/scheme"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
-	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/config"
@@ -81,14 +82,28 @@ type Environment struct {
 // options holds the configuration options for the Environment.
 type options struct {
 	scheme                  *runtime.Scheme
+	addToScheme             []func(*runtime.Scheme) error
 	crdDirectoryPaths       []string
+	crdFS                   []crdFS
 	maxConcurrentReconciles int
 }
 
+// crdFS holds the directories of a filesystem containing Custom Resource
+// Definitions.
+type crdFS struct {
+	fsys  fs.FS
+	paths []string
+}
+
 // withDefaults sets the default configuration for missing values.
 func (o *options) withDefaults() {
 	if o.scheme == nil {
-		o.scheme = scheme.Scheme
+		if len(o.addToScheme) > 0 {
+			o.scheme = runtime.NewScheme()
+			o.addToScheme = append([]func(*runtime.Scheme) error{clientgoscheme.AddToScheme}, o.addToScheme...)
+		} else {
+			o.scheme = clientgoscheme.Scheme
+		}
 	}
 	if o.maxConcurrentReconciles == 0 {
 		o.maxConcurrentReconciles = 2
@@ -106,13 +121,34 @@ func WithScheme(scheme *runtime.Scheme) Option {
 	}
 }
 
+// WithAddToScheme configures functions registering types in the runtime.Scheme
+// of the Environment, e.g. the AddToScheme functions of API packages.
+// If no scheme is configured with WithScheme, a new scheme is built with the
+// Kubernetes built-in types and the types registered by the given functions.
+func WithAddToScheme(addToScheme ...func(*runtime.Scheme) error) Option {
+	return func(o *options) {
+		o.addToScheme = append(o.addToScheme, addToScheme...)
+	}
+}
+
 // WithCRDPath configures the paths the envtest.Environment should look at for Custom Resource Definitions.
+// It can be specified multiple times to load definitions from multiple modules.
 func WithCRDPath(path ...string) Option {
 	return func(o *options) {
 		o.crdDirectoryPaths = append(o.crdDirectoryPaths, path...)
 	}
 }
 
+// WithCRDFS configures the directories of the given filesystem (e.g. an
+// embed.FS) the Environment should load Custom Resource Definitions from.
+// The root of the filesystem is used if no directory is specified.
+// It can be specified multiple times to load definitions from multiple modules.
+func WithCRDFS(fsys fs.FS, path ...string) Option {
+	return func(o *options) {
+		o.crdFS = append(o.crdFS, crdFS{fsys: fsys, paths: path})
+	}
+}
+
 // WithMaxConcurrentReconciles configures the maximum number of concurrent Reconciles which can be run.
 func WithMaxConcurrentReconciles(max int) Option {
 	return func(o *options) {
@@ -149,20 +185,85 @@ func New(o ...Option) *Environment {
 	// Set a default logger if not set already.
 	log.SetLogger(klogr.New())
 
+	e, err := newEnvironment(o...)
+	if err != nil {
+		panic(err)
+	}
+	env = e.env
+	return e
+}
+
+// NewT creates a new environment spinning up a local api-server for the given
+// test, and starts its manager. The environment is stopped when the test and
+// all its subtests complete. The test fails immediately if the environment
+// cannot be started.
+//
+//	func TestReconciler(t *testing.T) {
+//	    testEnv := testenv.NewT(t,
+//	        testenv.WithAddToScheme(sourcev1.AddToScheme),
+//	        testenv.WithCRDPath("../config/crd/bases"),
+//	    )
+//	    ...
+//	}
+func NewT(t testing.TB, o ...Option) *Environment {
+	t.Helper()
+
+	// Set a default logger if not set already.
+	log.SetLogger(klogr.New())
+
+	e, err := newEnvironment(o...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancelManager = cancel
+	e.startOnce.Do(func() {
+		go func() {
+			if err := e.Manager.Start(ctx); err != nil {
+				t.Errorf("failed to start the testenv manager: %v", err)
+			}
+		}()
+	})
+	t.Cleanup(func() {
+		if err := e.Stop(); err != nil {
+			t.Errorf("failed to stop the testenv: %v", err)
+		}
+	})
+
+	if !e.Manager.GetCache().WaitForCacheSync(ctx) {
+		t.Fatal("failed to wait for the testenv manager cache to sync")
+	}
+	return e
+}
+
+// newEnvironment creates a new environment with the given options.
+func newEnvironment(o ...Option) (*Environment, error) {
 	opts := options{}
 	for _, apply := range o {
 		apply(&opts)
 	}
 	opts.withDefaults()
 
-	env = &envtest.Environment{
+	for _, addToScheme := range opts.addToScheme {
+		if err := addToScheme(opts.scheme); err != nil {
+			return nil, fmt.Errorf("failed to build the testenv scheme: %w", err)
+		}
+	}
+
+	crds, err := readCRDs(opts.crdFS)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &envtest.Environment{
 		ErrorIfCRDPathMissing: true,
 		CRDDirectoryPaths:     opts.crdDirectoryPaths,
+		CRDs:                  crds,
 	}
 
 	if _, err := env.Start(); err != nil {
-		err = kerrors.NewAggregate([]error{err, env.Stop()})
-		panic(err)
+		return nil, kerrors.NewAggregate([]error{err, env.Stop()})
 	}
 
 	mgr, err := ctrl.NewManager(env.Config, manager.Options{
@@ -175,7 +276,8 @@ func New(o ...Option) *Environment {
 		},
 	})
 	if err != nil {
-		klog.Fatalf("Failed to start testenv manager: %v", err)
+		err = fmt.Errorf("failed to start testenv manager: %w", err)
+		return nil, kerrors.NewAggregate([]error{err, env.Stop()})
 	}
 
 	return &Environment{
@@ -183,7 +285,7 @@ func New(o ...Option) *Environment {
 		Client:  mgr.GetClient(),
 		Config:  mgr.GetConfig(),
 		env:     env,
-	}
+	}, nil
 }
 
 // Start starts the test environment.
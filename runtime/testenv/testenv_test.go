@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv
+
+import (
+	"context"
+	"embed"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//go:embed testdata/crds
+var testCRDs embed.FS
+
+func TestNewT(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	testEnv := NewT(t,
+		WithAddToScheme(corev1.AddToScheme, rbacv1.AddToScheme),
+		WithCRDPath("../conditions/testdata/crds"),
+		WithCRDFS(testCRDs, "testdata/crds"),
+	)
+
+	t.Run("installs CRDs from all sources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, gk := range []schema.GroupKind{
+			{Group: "fake.toolkit.fluxcd.io", Kind: "Fake"},
+			{Group: "testenv.toolkit.fluxcd.io", Kind: "Widget"},
+		} {
+			_, err := testEnv.Client.RESTMapper().RESTMapping(gk)
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	t.Run("denies requests not allowed by the user RBAC", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ns, err := testEnv.CreateNamespace(ctx, "rbac")
+		g.Expect(err).NotTo(HaveOccurred())
+
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: ns.Name},
+		}
+		g.Expect(testEnv.Client.Create(ctx, cm)).To(Succeed())
+
+		userClient, err := testEnv.CreateUser(ctx, ns.Name, "reader", rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"get", "list"},
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Eventually(func() error {
+			return userClient.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+		}).Should(Succeed())
+
+		err = userClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "denied", Namespace: ns.Name},
+		})
+		g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+
+		err = userClient.Get(ctx, client.ObjectKey{Name: "kube-root-ca.crt", Namespace: "default"}, &corev1.ConfigMap{})
+		g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+	})
+
+	g.Expect(testEnv.Manager.GetScheme().IsGroupRegistered(rbacv1.GroupName)).To(BeTrue())
+}
+
+func TestNewT_Parallel(t *testing.T) {
+	// The environments of parallel tests keep their own handle, and leave
+	// the one of the legacy New unset.
+	envs := make(chan *Environment, 2)
+	t.Run("group", func(t *testing.T) {
+		for _, name := range []string{"a", "b"} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				envs <- NewT(t, WithAddToScheme(corev1.AddToScheme))
+			})
+		}
+	})
+	close(envs)
+
+	g := NewWithT(t)
+	a, b := <-envs, <-envs
+	g.Expect(a.env).ToNot(BeIdenticalTo(b.env))
+	g.Expect(a.Config.Host).ToNot(Equal(b.Config.Host))
+	g.Expect(env).To(BeNil())
+}
+
+func Test_readCRDs(t *testing.T) {
+	g := NewWithT(t)
+
+	fsys := fstest.MapFS{
+		"crds/multi.yaml": &fstest.MapFile{Data: []byte(`---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: first.example.com
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: legacy.example.com
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: second.example.com
+`)},
+		"crds/third.json": &fstest.MapFile{Data: []byte(`{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition","metadata":{"name":"third.example.com"}}`)},
+		"crds/README.md":  &fstest.MapFile{Data: []byte(`# CRDs`)},
+		"crds/nested/fourth.yaml": &fstest.MapFile{Data: []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: fourth.example.com
+`)},
+		"invalid/crd.yaml": &fstest.MapFile{Data: []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec: [`)},
+	}
+
+	crds, err := readCRDs([]crdFS{{fsys: fsys, paths: []string{"crds"}}})
+	g.Expect(err).NotTo(HaveOccurred())
+	var names []string
+	for _, crd := range crds {
+		names = append(names, crd.Name)
+	}
+	g.Expect(names).To(Equal([]string{"first.example.com", "second.example.com", "third.example.com"}))
+
+	crds, err = readCRDs([]crdFS{{fsys: testCRDs, paths: []string{"testdata/crds"}}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(crds).To(HaveLen(1))
+	g.Expect(crds[0].Spec.Names.Kind).To(Equal("Widget"))
+
+	_, err = readCRDs([]crdFS{{fsys: fsys, paths: []string{"missing"}}})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = readCRDs([]crdFS{{fsys: fsys, paths: []string{"invalid"}}})
+	g.Expect(err).To(HaveOccurred())
+}
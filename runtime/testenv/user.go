@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CreateUser grants the given RBAC rules to a test user in the specified
+// namespace, and returns a client impersonating the user. The rules are
+// granted with a Role and a RoleBinding named after the user.
+//
+// NOTE: The API server authorizer observes RBAC changes asynchronously,
+// requests allowed by the rules may be denied for a short time after the
+// user has been created.
+func (e *Environment) CreateUser(ctx context.Context, namespace, name string, rules ...rbacv1.PolicyRule) (client.Client, error) {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Rules: rules,
+	}
+	if err := e.Client.Create(ctx, role); err != nil {
+		return nil, fmt.Errorf("failed to create role for user '%s': %w", name, err)
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     name,
+			},
+		},
+	}
+	if err := e.Client.Create(ctx, binding); err != nil {
+		return nil, fmt.Errorf("failed to create role binding for user '%s': %w", name, err)
+	}
+
+	return e.ImpersonateClient(name)
+}
+
+// ImpersonateClient returns a client impersonating the given user and groups.
+// The client reads directly from the API server and uses the scheme of the
+// Environment.
+func (e *Environment) ImpersonateClient(name string, groups ...string) (client.Client, error) {
+	cfg := rest.CopyConfig(e.Config)
+	cfg.Impersonate = rest.ImpersonationConfig{
+		UserName: name,
+		Groups:   groups,
+	}
+	return client.New(cfg, client.Options{
+		Scheme: e.Manager.GetScheme(),
+		Mapper: e.Manager.GetRESTMapper(),
+	})
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitignore
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// maxCompiledCacheSize is the maximum number of pattern sets kept in the
+// compiled patterns cache.
+const maxCompiledCacheSize = 128
+
+var (
+	compiledCacheMu sync.Mutex
+	compiledCache   = make(map[[sha256.Size]byte]*compiledPatterns)
+)
+
+// compiledPatterns holds the compiled patterns of a matcher, which are
+// shared by the matchers of identical pattern sets.
+type compiledPatterns struct {
+	patterns []compiledPattern
+	// domains holds the distinct domains of the patterns, the domain of
+	// each pattern being checked once per match.
+	domains [][]string
+}
+
+// domainResult is the memoized result of the check of a domain against
+// the matched path.
+type domainResult uint8
+
+const (
+	domainUnchecked domainResult = iota
+	domainMatched
+	domainMismatched
+)
+
+// compilePatterns returns the compiled patterns of the given pattern set,
+// from the cache if the set was already compiled. The pattern sets holding
+// patterns not created by ParsePattern are not cached.
+func compilePatterns(ps []Pattern) *compiledPatterns {
+	key, ok := patternsHash(ps)
+	if !ok {
+		return newCompiledPatterns(ps)
+	}
+
+	compiledCacheMu.Lock()
+	defer compiledCacheMu.Unlock()
+	if c, ok := compiledCache[key]; ok {
+		return c
+	}
+	if len(compiledCache) >= maxCompiledCacheSize {
+		clear(compiledCache)
+	}
+	c := newCompiledPatterns(ps)
+	compiledCache[key] = c
+	return c
+}
+
+// patternsHash returns the hash of the given pattern set, and false if it
+// holds patterns not created by ParsePattern.
+func patternsHash(ps []Pattern) ([sha256.Size]byte, bool) {
+	h := sha256.New()
+	for _, p := range ps {
+		pp, ok := p.(*pattern)
+		if !ok {
+			return [sha256.Size]byte{}, false
+		}
+		writeHashStrings(h, pp.domain)
+		writeHashStrings(h, pp.pattern)
+		var flags byte
+		if pp.inclusion {
+			flags |= 1
+		}
+		if pp.dirOnly {
+			flags |= 2
+		}
+		if pp.isGlob {
+			flags |= 4
+		}
+		h.Write([]byte{flags})
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum, true
+}
+
+// writeHashStrings writes the length-prefixed strings to the hash.
+func writeHashStrings(h hash.Hash, ss []string) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(ss)))])
+	for _, s := range ss {
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(s)))])
+		h.Write([]byte(s))
+	}
+}
+
+func newCompiledPatterns(ps []Pattern) *compiledPatterns {
+	c := &compiledPatterns{patterns: make([]compiledPattern, len(ps))}
+	domains := make(map[string]int)
+	for i, p := range ps {
+		pp, ok := p.(*pattern)
+		if !ok {
+			c.patterns[i] = compiledPattern{other: p}
+			continue
+		}
+		key := fmt.Sprintf("%q", pp.domain)
+		domain, ok := domains[key]
+		if !ok {
+			domain = len(c.domains)
+			domains[key] = domain
+			c.domains = append(c.domains, pp.domain)
+		}
+		c.patterns[i] = compilePattern(pp, domain)
+	}
+	return c
+}
+
+// compiledPattern is a pattern created by ParsePattern, with its domain
+// deduplicated and its elements compiled. The other patterns are matched
+// as is.
+type compiledPattern struct {
+	other Pattern
+
+	domain    int
+	domainLen int
+	elements  []compiledElement
+	result    MatchResult
+	dirOnly   bool
+	isGlob    bool
+}
+
+func compilePattern(p *pattern, domain int) compiledPattern {
+	c := compiledPattern{
+		domain:    domain,
+		domainLen: len(p.domain),
+		elements:  make([]compiledElement, len(p.pattern)),
+		result:    Exclude,
+		dirOnly:   p.dirOnly,
+		isGlob:    p.isGlob,
+	}
+	if p.inclusion {
+		c.result = Include
+	}
+	for i, e := range p.pattern {
+		c.elements[i] = compileElement(e)
+	}
+	return c
+}
+
+// match behaves like pattern.Match, checking the domain of the pattern once
+// per match of all the patterns with the domains results.
+func (p *compiledPattern) match(path []string, isDir bool, domains [][]string, results []domainResult) MatchResult {
+	if p.other != nil {
+		return p.other.Match(path, isDir)
+	}
+
+	if len(path) <= p.domainLen {
+		return NoMatch
+	}
+	switch results[p.domain] {
+	case domainMismatched:
+		return NoMatch
+	case domainUnchecked:
+		results[p.domain] = domainMatched
+		for i, e := range domains[p.domain] {
+			if path[i] != e {
+				results[p.domain] = domainMismatched
+				return NoMatch
+			}
+		}
+	}
+
+	path = path[p.domainLen:]
+	if p.isGlob && !p.globMatch(path, isDir) {
+		return NoMatch
+	} else if !p.isGlob && !p.simpleNameMatch(path, isDir) {
+		return NoMatch
+	}
+	return p.result
+}
+
+func (p *compiledPattern) simpleNameMatch(path []string, isDir bool) bool {
+	for i, name := range path {
+		if match, err := p.elements[0].match(name); err != nil {
+			return false
+		} else if !match {
+			continue
+		}
+		if p.dirOnly && !isDir && i == len(path)-1 {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+func (p *compiledPattern) globMatch(path []string, isDir bool) bool {
+	matched := false
+	canTraverse := false
+	for i, element := range p.elements {
+		if element.pattern == "" {
+			canTraverse = false
+			continue
+		}
+		if element.pattern == zeroToManyDirs {
+			if i == len(p.elements)-1 {
+				break
+			}
+			canTraverse = true
+			continue
+		}
+		if element.hasZeroToManyDirs {
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if canTraverse {
+			canTraverse = false
+			for len(path) > 0 {
+				e := path[0]
+				path = path[1:]
+				if match, err := element.match(e); err != nil {
+					return false
+				} else if match {
+					matched = true
+					break
+				} else if len(path) == 0 {
+					// if nothing left then fail
+					matched = false
+				}
+			}
+		} else {
+			if match, err := element.match(path[0]); err != nil || !match {
+				return false
+			}
+			matched = true
+			path = path[1:]
+		}
+	}
+	if matched && p.dirOnly && !isDir && len(path) == 0 {
+		matched = false
+	}
+	return matched
+}
+
+// elementKind is the kind of matching of a pattern element.
+type elementKind uint8
+
+const (
+	// elementGlob elements are matched with filepath.Match.
+	elementGlob elementKind = iota
+	// elementLiteral elements hold no special characters, and are matched
+	// by equality.
+	elementLiteral
+	// elementSuffix elements are a '*' followed by a literal, and are
+	// matched by suffix.
+	elementSuffix
+)
+
+// compiledElement is an element of a pattern, i.e. the glob of a path
+// element, with a faster match than filepath.Match for the literal and
+// suffix globs.
+type compiledElement struct {
+	pattern           string
+	kind              elementKind
+	literal           string
+	hasZeroToManyDirs bool
+}
+
+func compileElement(e string) compiledElement {
+	c := compiledElement{
+		pattern:           e,
+		kind:              elementGlob,
+		hasZeroToManyDirs: strings.Contains(e, zeroToManyDirs),
+	}
+	switch {
+	case !hasGlobChars(e):
+		c.kind = elementLiteral
+		c.literal = e
+	case strings.HasPrefix(e, "*") && !hasGlobChars(e[1:]):
+		c.kind = elementSuffix
+		c.literal = e[1:]
+	}
+	return c
+}
+
+// match returns the same result as filepath.Match for the element.
+func (e *compiledElement) match(name string) (bool, error) {
+	switch e.kind {
+	case elementLiteral:
+		return name == e.literal, nil
+	case elementSuffix:
+		// The '*' matches any sequence of non-separator characters.
+		return strings.HasSuffix(name, e.literal) &&
+			!strings.ContainsRune(name[:len(name)-len(e.literal)], filepath.Separator), nil
+	default:
+		return filepath.Match(e.pattern, name)
+	}
+}
+
+// hasGlobChars returns true if the string holds characters with a special
+// meaning for filepath.Match.
+func hasGlobChars(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
@@ -19,6 +19,11 @@ limitations under the License.
 //	https://github.com/go-git/go-git/tree/v5.16.4/plumbing/format/gitignore
 package gitignore
 
+import (
+	"sort"
+	"strings"
+)
+
 // Matcher defines a global multi-pattern matcher for gitignore patterns
 type Matcher interface {
 	// Match matches patterns in the order of priorities. As soon as an inclusion or
@@ -26,24 +31,97 @@ type Matcher interface {
 	Match(path []string, isDir bool) bool
 }
 
+// BatchMatcher is a Matcher able to match a whole tree of paths at once.
+type BatchMatcher interface {
+	Matcher
+
+	// MatchAll matches the given slash-separated paths, relative to the root
+	// of the matcher, the directories having a trailing slash. A path is
+	// matched if it is matched itself or if any of its parent directories is
+	// matched, like when walking a tree and skipping the matched directories.
+	// The results are returned in the order of the given paths.
+	MatchAll(paths []string) []bool
+}
+
 // NewMatcher constructs a new global matcher. Patterns must be given in the order of
 // increasing priority. That is most generic settings files first, then the content of
 // the repo .gitignore, then content of .gitignore down the path or the repo and then
 // the content command line arguments.
-func NewMatcher(ps []Pattern) Matcher {
-	return &matcher{ps}
+//
+// The patterns are compiled once per pattern set, the matchers of identical
+// pattern sets sharing the compiled patterns.
+func NewMatcher(ps []Pattern) BatchMatcher {
+	return &matcher{compilePatterns(ps)}
 }
 
 type matcher struct {
-	patterns []Pattern
+	*compiledPatterns
 }
 
 func (m *matcher) Match(path []string, isDir bool) bool {
+	var buf [16]domainResult
+	domains := buf[:0]
+	if len(m.domains) > len(buf) {
+		domains = make([]domainResult, len(m.domains))
+	} else {
+		domains = buf[:len(m.domains)]
+	}
+
 	n := len(m.patterns)
 	for i := n - 1; i >= 0; i-- {
-		if match := m.patterns[i].Match(path, isDir); match > NoMatch {
+		if match := m.patterns[i].match(path, isDir, m.domains, domains); match > NoMatch {
 			return match == Exclude
 		}
 	}
 	return false
 }
+
+func (m *matcher) MatchAll(paths []string) []bool {
+	results := make([]bool, len(paths))
+	order := make([]int, len(paths))
+	for i := range order {
+		order[i] = i
+	}
+	// The descendants of a directory directly follow it once sorted, and
+	// are pruned as a whole when the directory is matched.
+	sort.SliceStable(order, func(i, j int) bool {
+		return paths[order[i]] < paths[order[j]]
+	})
+
+	var matchedDir string
+	// parents holds the unmatched parent directories of the previous path,
+	// from the root.
+	var parents []string
+	for _, i := range order {
+		p := paths[i]
+		if matchedDir != "" && strings.HasPrefix(p, matchedDir) {
+			results[i] = true
+			continue
+		}
+		matchedDir = ""
+
+		isDir := strings.HasSuffix(p, patternDirSep)
+		elems := strings.Split(strings.TrimSuffix(p, patternDirSep), patternDirSep)
+		for len(parents) > 0 && !strings.HasPrefix(p, parents[len(parents)-1]) {
+			parents = parents[:len(parents)-1]
+		}
+		for depth := len(parents) + 1; depth < len(elems); depth++ {
+			dir := strings.Join(elems[:depth], patternDirSep) + patternDirSep
+			if m.Match(elems[:depth], true) {
+				matchedDir = dir
+				break
+			}
+			parents = append(parents, dir)
+		}
+		if matchedDir != "" {
+			results[i] = true
+			continue
+		}
+
+		results[i] = m.Match(elems, isDir)
+		if results[i] && isDir {
+			matchedDir = p
+		}
+	}
+	return results
+}
@@ -20,6 +20,9 @@ limitations under the License.
 package gitignore
 
 import (
+	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -37,3 +40,202 @@ func TestMatcher_Match(t *testing.T) {
 	g.Expect(m.Match([]string{"head", "middle", "vulkano"}, false)).To(BeTrue())
 	g.Expect(m.Match([]string{"head", "middle", "volcano"}, false)).To(BeFalse())
 }
+
+func TestMatcher_MatchAll(t *testing.T) {
+	g := NewWithT(t)
+
+	ps := []Pattern{
+		ParsePattern("docs/", nil),
+		ParsePattern("!docs/README.md", nil),
+		ParsePattern("*.md", []string{"apps"}),
+		ParsePattern("!keep.md", []string{"apps"}),
+	}
+
+	m := NewMatcher(ps)
+	g.Expect(m.MatchAll([]string{
+		"docs/README.md",
+		"apps/keep.md",
+		"apps/a/notes.md",
+		"docs/",
+		"apps/",
+		"apps/a/deploy.yaml",
+		"README.md",
+	})).To(Equal([]bool{true, false, true, true, false, false, false}))
+	g.Expect(m.MatchAll(nil)).To(BeEmpty())
+}
+
+func TestNewMatcher_Cache(t *testing.T) {
+	g := NewWithT(t)
+
+	newPatterns := func(domain ...string) []Pattern {
+		return []Pattern{ParsePattern("*.md", domain), ParsePattern("!README.md", domain)}
+	}
+	m1 := NewMatcher(newPatterns("apps")).(*matcher)
+	m2 := NewMatcher(newPatterns("apps")).(*matcher)
+	m3 := NewMatcher(newPatterns("infra")).(*matcher)
+	g.Expect(m1.compiledPatterns).To(BeIdenticalTo(m2.compiledPatterns))
+	g.Expect(m1.compiledPatterns).ToNot(BeIdenticalTo(m3.compiledPatterns))
+	g.Expect(m3.Match([]string{"infra", "notes.md"}, false)).To(BeTrue())
+	g.Expect(m3.Match([]string{"apps", "notes.md"}, false)).To(BeFalse())
+}
+
+// referenceMatch matches the path against each of the patterns, like the
+// matcher before the compilation of the patterns.
+func referenceMatch(ps []Pattern, path []string, isDir bool) bool {
+	for i := len(ps) - 1; i >= 0; i-- {
+		if match := ps[i].Match(path, isDir); match > NoMatch {
+			return match == Exclude
+		}
+	}
+	return false
+}
+
+// referenceMatchAll matches the paths like a walk of the tree skipping
+// the matched directories.
+func referenceMatchAll(ps []Pattern, paths []string) []bool {
+	results := make([]bool, len(paths))
+	for i, p := range paths {
+		isDir := strings.HasSuffix(p, "/")
+		elems := strings.Split(strings.TrimSuffix(p, "/"), "/")
+		for depth := 1; depth < len(elems) && !results[i]; depth++ {
+			results[i] = referenceMatch(ps, elems[:depth], true)
+		}
+		if !results[i] {
+			results[i] = referenceMatch(ps, elems, isDir)
+		}
+	}
+	return results
+}
+
+// randomTree returns the paths of a random tree, the directories having
+// a trailing slash.
+func randomTree(r *rand.Rand, names []string, depth int) []string {
+	var paths []string
+	var walk func(prefix string, depth int)
+	walk = func(prefix string, depth int) {
+		for _, name := range names {
+			if r.Intn(3) == 0 {
+				continue
+			}
+			if depth > 0 && r.Intn(2) == 0 {
+				paths = append(paths, prefix+name+"/")
+				walk(prefix+name+"/", depth-1)
+			} else {
+				paths = append(paths, prefix+name)
+			}
+		}
+	}
+	walk("", depth)
+	r.Shuffle(len(paths), func(i, j int) { paths[i], paths[j] = paths[j], paths[i] })
+	return paths
+}
+
+// randomPatterns returns random patterns built from the given names.
+func randomPatterns(r *rand.Rand, names []string, n int) []Pattern {
+	globs := append([]string{"*", "**", "*.md", "?", "[ab]*", "*.y*ml", "[", "a**"}, names...)
+	var ps []Pattern
+	for range n {
+		var elems []string
+		for range 1 + r.Intn(3) {
+			elems = append(elems, globs[r.Intn(len(globs))])
+		}
+		p := strings.Join(elems, "/")
+		if r.Intn(4) == 0 {
+			p = "/" + p
+		}
+		if r.Intn(4) == 0 {
+			p += "/"
+		}
+		if r.Intn(3) == 0 {
+			p = "!" + p
+		}
+		var domain []string
+		if r.Intn(3) == 0 {
+			domain = []string{names[r.Intn(len(names))]}
+		}
+		ps = append(ps, ParsePattern(p, domain))
+	}
+	return ps
+}
+
+func TestMatcher_ReferenceEquivalence(t *testing.T) {
+	names := []string{"a", "b", "apps", "README.md", "deploy.yaml", "x.yml", "docs"}
+	r := rand.New(rand.NewSource(1))
+	for i := range 500 {
+		paths := randomTree(r, names, 3)
+		ps := randomPatterns(r, names, 1+r.Intn(8))
+		m := NewMatcher(ps)
+
+		for _, p := range paths {
+			isDir := strings.HasSuffix(p, "/")
+			elems := strings.Split(strings.TrimSuffix(p, "/"), "/")
+			if got, want := m.Match(elems, isDir), referenceMatch(ps, elems, isDir); got != want {
+				t.Fatalf("tree %d: Match(%q) = %t, want %t, patterns: %v", i, p, got, want, ps)
+			}
+		}
+		got, want := m.MatchAll(paths), referenceMatchAll(ps, paths)
+		for j := range paths {
+			if got[j] != want[j] {
+				t.Fatalf("tree %d: MatchAll(%q) = %t, want %t, patterns: %v", i, paths[j], got[j], want[j], ps)
+			}
+		}
+	}
+}
+
+// benchmarkTree returns the paths of a tree of about 100k files, and the
+// patterns of a .sourceignore file in each of its top-level directories.
+func benchmarkTree() ([]string, []Pattern) {
+	var paths []string
+	ps := []Pattern{
+		ParsePattern(".git/", nil),
+		ParsePattern("*.jpg", nil),
+		ParsePattern("*.tar.gz", nil),
+		ParsePattern("**/.sops.yaml", nil),
+		ParsePattern(".github/", nil),
+	}
+	for i := range 100 {
+		top := fmt.Sprintf("app-%d", i)
+		domain := []string{top}
+		ps = append(ps,
+			ParsePattern("docs/", domain),
+			ParsePattern("*.md", domain),
+			ParsePattern("!README.md", domain),
+		)
+		paths = append(paths, top+"/")
+		for _, dir := range []string{"docs", "base", "overlays"} {
+			paths = append(paths, top+"/"+dir+"/")
+			for j := range 333 {
+				paths = append(paths, fmt.Sprintf("%s/%s/file-%d.yaml", top, dir, j))
+			}
+		}
+	}
+	return paths, ps
+}
+
+func BenchmarkMatcher_Reference(b *testing.B) {
+	paths, ps := benchmarkTree()
+	b.ResetTimer()
+	for range b.N {
+		referenceMatchAll(ps, paths)
+	}
+}
+
+func BenchmarkMatcher_Match(b *testing.B) {
+	paths, ps := benchmarkTree()
+	m := NewMatcher(ps)
+	b.ResetTimer()
+	for range b.N {
+		for _, p := range paths {
+			m.Match(strings.Split(strings.TrimSuffix(p, "/"), "/"), strings.HasSuffix(p, "/"))
+		}
+	}
+}
+
+func BenchmarkMatcher_MatchAll(b *testing.B) {
+	paths, ps := benchmarkTree()
+	m := NewMatcher(ps)
+	b.ResetTimer()
+	for range b.N {
+		m.MatchAll(paths)
+	}
+}
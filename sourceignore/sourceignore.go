@@ -34,15 +34,15 @@ const (
 	ExcludeExtra = "**/.goreleaser.yml,**/.sops.yaml,**/.flux.yaml"
 )
 
-// NewMatcher returns a gitignore.Matcher for the given gitignore.Pattern
+// NewMatcher returns a gitignore.BatchMatcher for the given gitignore.Pattern
 // slice. It mainly exists to compliment the API.
-func NewMatcher(ps []gitignore.Pattern) gitignore.Matcher {
+func NewMatcher(ps []gitignore.Pattern) gitignore.BatchMatcher {
 	return gitignore.NewMatcher(ps)
 }
 
-// NewDefaultMatcher returns a gitignore.Matcher with the DefaultPatterns
+// NewDefaultMatcher returns a gitignore.BatchMatcher with the DefaultPatterns
 // as lowest priority patterns.
-func NewDefaultMatcher(ps []gitignore.Pattern, domain []string) gitignore.Matcher {
+func NewDefaultMatcher(ps []gitignore.Pattern, domain []string) gitignore.BatchMatcher {
 	var defaultPs []gitignore.Pattern
 	defaultPs = append(defaultPs, VCSPatterns(domain)...)
 	defaultPs = append(defaultPs, DefaultPatterns(domain)...)
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+)
+
+// ApplyPolicy defines how the objects of a class, matched by the selector,
+// are applied. The objects which do not match any policy are applied with
+// the field manager of the ResourceManager owner, forcing the conflicts and
+// correcting the drift.
+type ApplyPolicy struct {
+	// Name identifies the policy in the ChangeSetEntry of the objects it
+	// has been applied to.
+	Name string `json:"name"`
+
+	// Selector matches the objects of the class by their group, version,
+	// kind, name, namespace, labels and annotations. A nil selector matches
+	// all the objects.
+	Selector *jsondiff.Selector `json:"selector,omitempty"`
+
+	// FieldManager is the field manager of the apply requests. It defaults
+	// to the field manager of the ResourceManager owner.
+	FieldManager string `json:"fieldManager,omitempty"`
+
+	// ForceConflicts, when enabled, takes the ownership of the fields
+	// managed by other field managers. Otherwise, the objects whose apply
+	// conflicts with other field managers are not applied, so that the
+	// changes made by the other managers win, and are reported in the
+	// change set as skipped with the conflicts as message.
+	ForceConflicts bool `json:"forceConflicts,omitempty"`
+
+	// SkipDriftCorrection, when enabled, validates the in-cluster objects
+	// with a server-side dry-run without correcting their drift. The objects
+	// are created if they don't exist, but the drifted objects are not
+	// applied, and are reported in the change set as skipped.
+	SkipDriftCorrection bool `json:"skipDriftCorrection,omitempty"`
+}
+
+// compiledApplyPolicy is an ApplyPolicy with a compiled selector.
+type compiledApplyPolicy struct {
+	policy   ApplyPolicy
+	selector *jsondiff.SelectorRegex
+}
+
+// compileApplyPolicies compiles the selectors of the given policies.
+func compileApplyPolicies(policies []ApplyPolicy) ([]compiledApplyPolicy, error) {
+	compiled := make([]compiledApplyPolicy, 0, len(policies))
+	for _, p := range policies {
+		sr, err := jsondiff.NewSelectorRegex(p.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create apply policy '%s' selector: %w", p.Name, err)
+		}
+		compiled = append(compiled, compiledApplyPolicy{policy: p, selector: sr})
+	}
+	return compiled, nil
+}
+
+// defaultApplyPolicy returns the policy of the objects which do not match
+// any ApplyPolicy.
+func (m *ResourceManager) defaultApplyPolicy() ApplyPolicy {
+	return ApplyPolicy{
+		FieldManager:   m.owner.Field,
+		ForceConflicts: true,
+	}
+}
+
+// applyPolicyFor returns the first of the policies matching the given
+// object, with the field manager of the ResourceManager owner as default,
+// or the default policy if none matches.
+func (m *ResourceManager) applyPolicyFor(policies []compiledApplyPolicy, object *unstructured.Unstructured) ApplyPolicy {
+	for _, p := range policies {
+		if p.selector.MatchUnstructured(object) {
+			policy := p.policy
+			if policy.FieldManager == "" {
+				policy.FieldManager = m.owner.Field
+			}
+			return policy
+		}
+	}
+	return m.defaultApplyPolicy()
+}
+
+// patchOptions returns the options of the apply requests of the policy.
+func (p ApplyPolicy) patchOptions() []client.PatchOption {
+	opts := []client.PatchOption{client.FieldOwner(p.FieldManager)}
+	if p.ForceConflicts {
+		opts = append(opts, client.ForceOwnership)
+	}
+	return opts
+}
+
+// conflictEntry returns the skipped ChangeSetEntry of an object whose apply
+// conflicts with other field managers.
+func (m *ResourceManager) conflictEntry(object *unstructured.Unstructured, policy ApplyPolicy, err error) *ChangeSetEntry {
+	entry := m.changeSetEntry(object, SkippedAction)
+	entry.Message = fmt.Sprintf("conflicts not forced by the apply policy '%s': %s", policy.Name, err.Error())
+	return entry
+}
+
+// driftNotCorrectedEntry returns the skipped ChangeSetEntry of a drifted
+// object whose drift is not corrected.
+func (m *ResourceManager) driftNotCorrectedEntry(object *unstructured.Unstructured, policy ApplyPolicy) *ChangeSetEntry {
+	entry := m.changeSetEntry(object, SkippedAction)
+	entry.Message = fmt.Sprintf("drift not corrected by the apply policy '%s'", policy.Name)
+	return entry
+}
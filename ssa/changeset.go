@@ -20,6 +20,10 @@ package ssa
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/fluxcd/cli-utils/pkg/object"
 )
@@ -121,6 +125,51 @@ type ChangeSetEntry struct {
 
 	// Action represents the action type taken by the reconciler for this object.
 	Action Action
+
+	// Message holds additional details about the action, e.g. the reason
+	// for which the object was recreated.
+	Message string
+
+	// UID holds the UID of the in-cluster object. For applied objects, it is
+	// read from the API response, for deleted objects, it is captured before
+	// the deletion. It is empty if the object does not exist in the cluster.
+	UID types.UID
+
+	// ResourceVersion holds the resource version of the in-cluster object
+	// after the action, or before the deletion for deleted objects.
+	ResourceVersion string
+
+	// Timestamp holds the time at which the ResourceManager recorded the action.
+	Timestamp time.Time
+
+	// PreservedFields holds the JSON pointers of the fields of the kubectl
+	// last applied configuration which were preserved during the takeover
+	// of the object. See ApplyOptions.PreserveKubectlFields.
+	PreservedFields []string
+
+	// ApplyPolicy holds the name of the ApplyPolicy the object was applied
+	// with. It is empty if the object did not match any of the
+	// ApplyOptions.ApplyPolicies.
+	ApplyPolicy string
+
+	// Warnings holds the problems found with the object which did not
+	// prevent the action, e.g. the malformed paths of the
+	// IgnorePathsAnnotation.
+	Warnings []string
+}
+
+// withObjectRef sets the UID and resource version of the entry from the
+// given in-cluster object, and returns the entry.
+func (e *ChangeSetEntry) withObjectRef(o *unstructured.Unstructured) *ChangeSetEntry {
+	e.UID = o.GetUID()
+	e.ResourceVersion = o.GetResourceVersion()
+	return e
+}
+
+// withWarnings sets the warnings of the entry, and returns the entry.
+func (e *ChangeSetEntry) withWarnings(warnings []string) *ChangeSetEntry {
+	e.Warnings = warnings
+	return e
 }
 
 // String returns a string representation of the ChangeSetEntry
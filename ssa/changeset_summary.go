@@ -0,0 +1,298 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+)
+
+// SummaryGroupBy represents the criteria used to group the entries
+// of a ChangeSet summary.
+type SummaryGroupBy string
+
+const (
+	// SummaryGroupByNone lists the entries of each action without grouping.
+	SummaryGroupByNone SummaryGroupBy = ""
+	// SummaryGroupByNamespace groups the entries of each action by namespace.
+	SummaryGroupByNamespace SummaryGroupBy = "namespace"
+	// SummaryGroupByKind groups the entries of each action by kind.
+	SummaryGroupByKind SummaryGroupBy = "kind"
+)
+
+// clusterScopeGroup is the group name used for cluster-scoped objects
+// when grouping the entries by namespace.
+const clusterScopeGroup = "<cluster>"
+
+// summaryActions holds the order in which actions are written in a summary.
+var summaryActions = []Action{
+	CreatedAction,
+	ConfiguredAction,
+	DeletedAction,
+	SkippedAction,
+	UnknownAction,
+	UnchangedAction,
+}
+
+// SummaryOptions holds the options for formatting a ChangeSet summary.
+type SummaryOptions struct {
+	// MaxEntriesPerAction is the maximum number of entries listed for each
+	// action, the remaining entries are only counted. Zero lists all entries.
+	MaxEntriesPerAction int
+
+	// GroupBy groups the entries of each action by namespace or kind.
+	GroupBy SummaryGroupBy
+
+	// OmitUnchanged excludes the unchanged entries from the summary.
+	OmitUnchanged bool
+
+	// MaxLength is the maximum length in bytes of the summary. When exceeded,
+	// the summary is truncated at a line boundary. Zero means no limit.
+	MaxLength int
+}
+
+// Summary formats and returns a human-readable summary of the ChangeSet,
+// listing the entries by action in a deterministic order bounded by the
+// given options. It is suitable for event messages and CLI output.
+func (c *ChangeSet) Summary(opts SummaryOptions) string {
+	byAction := make(map[Action][]ChangeSetEntry)
+	for _, entry := range c.Entries {
+		byAction[entry.Action] = append(byAction[entry.Action], entry)
+	}
+
+	var lines []string
+	for _, action := range orderedActions(byAction) {
+		if opts.OmitUnchanged && action == UnchangedAction {
+			continue
+		}
+		entries := byAction[action]
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Subject < entries[j].Subject
+		})
+
+		lines = append(lines, fmt.Sprintf("%s: %d", action, len(entries)))
+		listed := 0
+		if opts.GroupBy == SummaryGroupByNone {
+			listed, lines = appendSummaryEntries(lines, entries, "  ", opts.MaxEntriesPerAction)
+		} else {
+			groups, names := groupEntries(entries, opts.GroupBy)
+			for _, name := range names {
+				lines = append(lines, fmt.Sprintf("  %s %s: %d", opts.GroupBy, name, len(groups[name])))
+				limit := 0
+				if opts.MaxEntriesPerAction > 0 {
+					limit = opts.MaxEntriesPerAction - listed
+					if limit <= 0 {
+						continue
+					}
+				}
+				var n int
+				n, lines = appendSummaryEntries(lines, groups[name], "    ", limit)
+				listed += n
+			}
+		}
+		if remaining := len(entries) - listed; remaining > 0 {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", remaining))
+		}
+	}
+
+	return truncateSummary(lines, opts.MaxLength)
+}
+
+// orderedActions returns the actions present in the given map, in the
+// summary order followed by any other actions sorted by name.
+func orderedActions(byAction map[Action][]ChangeSetEntry) []Action {
+	var res []Action
+	known := make(map[Action]bool, len(summaryActions))
+	for _, action := range summaryActions {
+		known[action] = true
+		if len(byAction[action]) > 0 {
+			res = append(res, action)
+		}
+	}
+
+	var other []Action
+	for action := range byAction {
+		if !known[action] {
+			other = append(other, action)
+		}
+	}
+	sort.Slice(other, func(i, j int) bool {
+		return other[i] < other[j]
+	})
+	return append(res, other...)
+}
+
+// appendSummaryEntries appends up to limit entries with the given indentation
+// to lines. It returns the number of entries appended and the new lines.
+func appendSummaryEntries(lines []string, entries []ChangeSetEntry, indent string, limit int) (int, []string) {
+	n := len(entries)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	for _, entry := range entries[:n] {
+		lines = append(lines, indent+entry.Subject)
+	}
+	return n, lines
+}
+
+// groupEntries groups the given entries by namespace or kind, returning
+// the groups and their names in sorted order.
+func groupEntries(entries []ChangeSetEntry, groupBy SummaryGroupBy) (map[string][]ChangeSetEntry, []string) {
+	groups := make(map[string][]ChangeSetEntry)
+	for _, entry := range entries {
+		var name string
+		switch groupBy {
+		case SummaryGroupByKind:
+			name = entry.ObjMetadata.GroupKind.Kind
+		default:
+			name = entry.ObjMetadata.Namespace
+			if name == "" {
+				name = clusterScopeGroup
+			}
+		}
+		groups[name] = append(groups[name], entry)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return groups, names
+}
+
+// truncateSummary joins the lines, dropping the trailing lines that do not
+// fit in maxLength together with a truncation marker.
+func truncateSummary(lines []string, maxLength int) string {
+	res := strings.Join(lines, "\n")
+	if maxLength <= 0 || len(res) <= maxLength {
+		return res
+	}
+
+	const marker = "... (truncated)"
+	var b strings.Builder
+	for _, line := range lines {
+		if b.Len()+len(line)+1+len(marker) > maxLength {
+			break
+		}
+		b.WriteString(line + "\n")
+	}
+	if b.Len()+len(marker) > maxLength {
+		return marker[:min(len(marker), maxLength)]
+	}
+	return b.String() + marker
+}
+
+// ChangeSetJSONSchemaVersion is the version of the JSON representation of
+// a ChangeSet returned by ToJSON. Version 2 added the uid, resourceVersion
+// and timestamp fields of the entries, the JSON documents without a version
+// are of version 1.
+const ChangeSetJSONSchemaVersion = 2
+
+// changeSetJSON is the machine-readable representation of a ChangeSet.
+type changeSetJSON struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	Summary       map[Action]int       `json:"summary"`
+	Entries       []changeSetEntryJSON `json:"entries"`
+}
+
+// changeSetEntryJSON is the machine-readable representation of a ChangeSetEntry.
+type changeSetEntryJSON struct {
+	Subject         string    `json:"subject"`
+	Action          Action    `json:"action"`
+	Group           string    `json:"group,omitempty"`
+	Version         string    `json:"version,omitempty"`
+	Kind            string    `json:"kind"`
+	Namespace       string    `json:"namespace,omitempty"`
+	Name            string    `json:"name"`
+	UID             types.UID `json:"uid,omitempty"`
+	ResourceVersion string    `json:"resourceVersion,omitempty"`
+	Timestamp       time.Time `json:"timestamp,omitzero"`
+}
+
+// ToJSON returns the machine-readable JSON representation of the ChangeSet,
+// containing the number of entries per action and the entries in order.
+func (c *ChangeSet) ToJSON() ([]byte, error) {
+	res := changeSetJSON{
+		SchemaVersion: ChangeSetJSONSchemaVersion,
+		Summary:       make(map[Action]int),
+		Entries:       make([]changeSetEntryJSON, 0, len(c.Entries)),
+	}
+	for _, entry := range c.Entries {
+		res.Summary[entry.Action]++
+
+		version := entry.GroupVersion
+		if i := strings.LastIndex(version, "/"); i >= 0 {
+			version = version[i+1:]
+		}
+		res.Entries = append(res.Entries, changeSetEntryJSON{
+			Subject:         entry.Subject,
+			Action:          entry.Action,
+			Group:           entry.ObjMetadata.GroupKind.Group,
+			Version:         version,
+			Kind:            entry.ObjMetadata.GroupKind.Kind,
+			Namespace:       entry.ObjMetadata.Namespace,
+			Name:            entry.ObjMetadata.Name,
+			UID:             entry.UID,
+			ResourceVersion: entry.ResourceVersion,
+			Timestamp:       entry.Timestamp,
+		})
+	}
+	return json.Marshal(res)
+}
+
+// ChangeSetFromJSON returns the ChangeSet of the given JSON representation,
+// as returned by ToJSON. The documents of all schema versions up to
+// ChangeSetJSONSchemaVersion are supported. The GroupVersion of the entries
+// holds the version of their API group, as set by the ResourceManager, and
+// the messages of the entries are not part of the JSON representation.
+func ChangeSetFromJSON(data []byte) (*ChangeSet, error) {
+	var res changeSetJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("failed to decode change set: %w", err)
+	}
+	if res.SchemaVersion > ChangeSetJSONSchemaVersion {
+		return nil, fmt.Errorf("unsupported change set schema version %d, expected at most %d",
+			res.SchemaVersion, ChangeSetJSONSchemaVersion)
+	}
+
+	cs := NewChangeSet()
+	for _, entry := range res.Entries {
+		cs.Add(ChangeSetEntry{
+			ObjMetadata: object.ObjMetadata{
+				Namespace: entry.Namespace,
+				Name:      entry.Name,
+				GroupKind: schema.GroupKind{Group: entry.Group, Kind: entry.Kind},
+			},
+			GroupVersion:    entry.Version,
+			Subject:         entry.Subject,
+			Action:          entry.Action,
+			UID:             entry.UID,
+			ResourceVersion: entry.ResourceVersion,
+			Timestamp:       entry.Timestamp,
+		})
+	}
+	return cs, nil
+}
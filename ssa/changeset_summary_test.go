@@ -0,0 +1,236 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+func newTestChangeSet() *ChangeSet {
+	cs := NewChangeSet()
+	for _, e := range []struct {
+		gv        string
+		kind      string
+		namespace string
+		name      string
+		action    Action
+	}{
+		{"v1", "Namespace", "", "apps", CreatedAction},
+		{"v1", "ServiceAccount", "apps", "web", CreatedAction},
+		{"v1", "ConfigMap", "apps", "web-config", ConfiguredAction},
+		{"apps/v1", "Deployment", "apps", "web", ConfiguredAction},
+		{"apps/v1", "Deployment", "default", "api", ConfiguredAction},
+		{"v1", "Service", "default", "api", ConfiguredAction},
+		{"v1", "Secret", "default", "old", DeletedAction},
+		{"v1", "Service", "apps", "web", UnchangedAction},
+		{"v1", "ConfigMap", "default", "settings", UnchangedAction},
+		{"rbac.authorization.k8s.io/v1", "ClusterRole", "", "viewer", UnchangedAction},
+	} {
+		gv, _ := schema.ParseGroupVersion(e.gv)
+		meta := object.ObjMetadata{
+			Namespace: e.namespace,
+			Name:      e.name,
+			GroupKind: schema.GroupKind{Group: gv.Group, Kind: e.kind},
+		}
+		cs.Add(ChangeSetEntry{
+			ObjMetadata:  meta,
+			GroupVersion: e.gv,
+			Subject:      utils.FmtObjMetadata(meta),
+			Action:       e.action,
+		})
+	}
+	return cs
+}
+
+func TestChangeSet_Summary(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   SummaryOptions
+		golden string
+	}{
+		{
+			name:   "all entries",
+			opts:   SummaryOptions{},
+			golden: "all.golden",
+		},
+		{
+			name:   "omit unchanged",
+			opts:   SummaryOptions{OmitUnchanged: true},
+			golden: "omit_unchanged.golden",
+		},
+		{
+			name:   "max entries per action",
+			opts:   SummaryOptions{MaxEntriesPerAction: 2},
+			golden: "max_entries.golden",
+		},
+		{
+			name:   "group by namespace",
+			opts:   SummaryOptions{GroupBy: SummaryGroupByNamespace, OmitUnchanged: true},
+			golden: "group_by_namespace.golden",
+		},
+		{
+			name:   "group by kind with max entries",
+			opts:   SummaryOptions{GroupBy: SummaryGroupByKind, MaxEntriesPerAction: 3},
+			golden: "group_by_kind.golden",
+		},
+		{
+			name:   "truncated to max length",
+			opts:   SummaryOptions{MaxLength: 120},
+			golden: "truncated.golden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			want, err := os.ReadFile(filepath.Join("testdata", "summary", tt.golden))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got := newTestChangeSet().Summary(tt.opts)
+			g.Expect(got).To(Equal(strings.TrimSuffix(string(want), "\n")))
+			if tt.opts.MaxLength > 0 {
+				g.Expect(len(got)).To(BeNumerically("<=", tt.opts.MaxLength))
+			}
+
+			// The output must not depend on the order of the entries.
+			reversed := NewChangeSet()
+			entries := newTestChangeSet().Entries
+			for i := len(entries) - 1; i >= 0; i-- {
+				reversed.Add(entries[i])
+			}
+			g.Expect(reversed.Summary(tt.opts)).To(Equal(got))
+		})
+	}
+}
+
+func TestChangeSet_ToJSON(t *testing.T) {
+	g := NewWithT(t)
+
+	want, err := os.ReadFile(filepath.Join("testdata", "summary", "changeset.json"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got, err := newTestChangeSet().ToJSON()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(MatchJSON(want))
+
+	empty, err := NewChangeSet().ToJSON()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(empty).To(MatchJSON(`{"schemaVersion":2,"summary":{},"entries":[]}`))
+}
+
+func TestChangeSetFromJSON(t *testing.T) {
+	g := NewWithT(t)
+
+	cs := NewChangeSet()
+	for _, e := range []ChangeSetEntry{
+		{
+			ObjMetadata: object.ObjMetadata{
+				Namespace: "apps",
+				Name:      "web",
+				GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			},
+			GroupVersion:    "v1",
+			Subject:         "Deployment/apps/web",
+			Action:          ConfiguredAction,
+			UID:             "8d5b4a3e-5f2b-4c1e-9a7d-2f3c4b5a6d7e",
+			ResourceVersion: "1234",
+			Timestamp:       time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			ObjMetadata: object.ObjMetadata{
+				Name:      "apps",
+				GroupKind: schema.GroupKind{Kind: "Namespace"},
+			},
+			GroupVersion: "v1",
+			Subject:      "Namespace/apps",
+			Action:       UnchangedAction,
+		},
+	} {
+		cs.Add(e)
+	}
+
+	data, err := cs.ToJSON()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(MatchJSON(`{
+		"schemaVersion": 2,
+		"summary": {"configured": 1, "unchanged": 1},
+		"entries": [
+			{
+				"subject": "Deployment/apps/web",
+				"action": "configured",
+				"group": "apps",
+				"version": "v1",
+				"kind": "Deployment",
+				"namespace": "apps",
+				"name": "web",
+				"uid": "8d5b4a3e-5f2b-4c1e-9a7d-2f3c4b5a6d7e",
+				"resourceVersion": "1234",
+				"timestamp": "2026-10-14T09:30:00Z"
+			},
+			{
+				"subject": "Namespace/apps",
+				"action": "unchanged",
+				"version": "v1",
+				"kind": "Namespace",
+				"name": "apps"
+			}
+		]
+	}`))
+
+	got, err := ChangeSetFromJSON(data)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(cs))
+
+	t.Run("schema version 1", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got, err := ChangeSetFromJSON([]byte(`{
+			"summary": {"deleted": 1},
+			"entries": [{"subject": "Secret/default/old", "action": "deleted", "version": "v1", "kind": "Secret", "namespace": "default", "name": "old"}]
+		}`))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Entries).To(Equal([]ChangeSetEntry{{
+			ObjMetadata: object.ObjMetadata{
+				Namespace: "default",
+				Name:      "old",
+				GroupKind: schema.GroupKind{Kind: "Secret"},
+			},
+			GroupVersion: "v1",
+			Subject:      "Secret/default/old",
+			Action:       DeletedAction,
+		}}))
+	})
+
+	t.Run("unsupported schema version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := ChangeSetFromJSON([]byte(`{"schemaVersion": 3, "summary": {}, "entries": []}`))
+		g.Expect(err).To(MatchError("unsupported change set schema version 3, expected at most 2"))
+	})
+}
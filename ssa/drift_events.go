@@ -0,0 +1,225 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// DefaultDriftEventWindow is the default minimum period between two drift
+	// events of the same object, namespace and action.
+	DefaultDriftEventWindow = 5 * time.Minute
+
+	// DefaultDriftEventMaxNames is the default maximum number of objects
+	// listed in a drift event.
+	DefaultDriftEventMaxNames = 10
+
+	// DefaultDriftEventReason is the default reason of the drift events.
+	DefaultDriftEventReason = "DriftCorrected"
+)
+
+// DriftEventOptions holds the options of a DriftEventAggregator.
+type DriftEventOptions struct {
+	// Window is the minimum period between two events of the same object,
+	// namespace and action. Defaults to DefaultDriftEventWindow.
+	Window time.Duration
+
+	// MaxNames is the maximum number of objects listed in an event, the
+	// remaining objects are only counted. Defaults to DefaultDriftEventMaxNames.
+	MaxNames int
+
+	// Reason is the reason of the events. Defaults to DefaultDriftEventReason.
+	Reason string
+
+	// EventType is the type of the events. Defaults to corev1.EventTypeNormal.
+	EventType string
+}
+
+// DriftEventAggregator records the drift corrections of the ChangeSets as
+// events grouped by namespace and action, instead of one event per corrected
+// object. At most one event is recorded per object, namespace and action
+// within the configured window, the corrections made in the meantime being
+// reported by the next event of the same object, namespace and action.
+//
+// A DriftEventAggregator is safe for concurrent use, and is meant to be shared
+// by the reconciliations of a controller.
+type DriftEventAggregator struct {
+	recorder record.EventRecorder
+	opts     DriftEventOptions
+	now      func() time.Time
+
+	mu     sync.Mutex
+	states map[driftEventKey]*driftEventState
+}
+
+// driftEventKey identifies the events of an object for a namespace and action.
+type driftEventKey struct {
+	object    string
+	namespace string
+	action    Action
+}
+
+// driftEventState holds the last time an event was recorded for a key, and
+// the corrections not reported yet.
+type driftEventState struct {
+	lastRecorded time.Time
+	pending      map[string]ChangeSetEntry
+}
+
+// NewDriftEventAggregator returns a DriftEventAggregator recording the events
+// with the given recorder.
+func NewDriftEventAggregator(recorder record.EventRecorder, opts DriftEventOptions) *DriftEventAggregator {
+	if opts.Window <= 0 {
+		opts.Window = DefaultDriftEventWindow
+	}
+	if opts.MaxNames <= 0 {
+		opts.MaxNames = DefaultDriftEventMaxNames
+	}
+	if opts.Reason == "" {
+		opts.Reason = DefaultDriftEventReason
+	}
+	if opts.EventType == "" {
+		opts.EventType = corev1.EventTypeNormal
+	}
+	return &DriftEventAggregator{
+		recorder: recorder,
+		opts:     opts,
+		now:      time.Now,
+		states:   make(map[driftEventKey]*driftEventState),
+	}
+}
+
+// Record records the drift corrections of the given ChangeSet as events
+// regarding the given object, e.g. the Kustomization which applied the
+// ChangeSet. The entries of the unchanged and skipped objects are ignored.
+func (a *DriftEventAggregator) Record(object runtime.Object, cs *ChangeSet) {
+	objectKey := driftEventObjectKey(object)
+	now := a.now()
+
+	a.mu.Lock()
+	if cs != nil {
+		for _, entry := range cs.Entries {
+			if entry.Action == UnchangedAction || entry.Action == SkippedAction {
+				continue
+			}
+			key := driftEventKey{
+				object:    objectKey,
+				namespace: entry.ObjMetadata.Namespace,
+				action:    entry.Action,
+			}
+			state, ok := a.states[key]
+			if !ok {
+				state = &driftEventState{}
+				a.states[key] = state
+			}
+			if state.pending == nil {
+				state.pending = make(map[string]ChangeSetEntry)
+			}
+			state.pending[entry.Subject] = entry
+		}
+	}
+
+	type driftEvent struct {
+		key     driftEventKey
+		entries []ChangeSetEntry
+	}
+	var events []driftEvent
+	for key, state := range a.states {
+		elapsed := state.lastRecorded.IsZero() || now.Sub(state.lastRecorded) >= a.opts.Window
+		switch {
+		case !elapsed:
+			continue
+		case len(state.pending) == 0:
+			// Forget the keys without corrections for a whole window.
+			delete(a.states, key)
+		case key.object == objectKey:
+			var entries []ChangeSetEntry
+			for _, entry := range state.pending {
+				entries = append(entries, entry)
+			}
+			events = append(events, driftEvent{key: key, entries: entries})
+			state.lastRecorded = now
+			state.pending = nil
+		}
+	}
+	a.mu.Unlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].key.namespace != events[j].key.namespace {
+			return events[i].key.namespace < events[j].key.namespace
+		}
+		return actionIndex(events[i].key.action) < actionIndex(events[j].key.action)
+	})
+	for _, e := range events {
+		a.recorder.Event(object, a.opts.EventType, a.opts.Reason,
+			driftEventMessage(e.key.namespace, e.key.action, e.entries, a.opts.MaxNames))
+	}
+}
+
+// driftEventObjectKey returns the key of the given object, i.e. its kind,
+// namespace and name.
+func driftEventObjectKey(object runtime.Object) string {
+	kind := object.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = fmt.Sprintf("%T", object)
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return kind
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, accessor.GetNamespace(), accessor.GetName())
+}
+
+// actionIndex returns the index of the action in the summary order, the
+// other actions being ordered last.
+func actionIndex(action Action) int {
+	if i := slices.Index(summaryActions, action); i >= 0 {
+		return i
+	}
+	return len(summaryActions)
+}
+
+// driftEventMessage returns the message of the event of the given entries,
+// listing at most maxNames entries sorted by subject.
+func driftEventMessage(namespace string, action Action, entries []ChangeSetEntry, maxNames int) string {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Subject < entries[j].Subject
+	})
+
+	var lines []string
+	if namespace == "" {
+		lines = append(lines, fmt.Sprintf("drift corrected, %s cluster-scoped objects: %d", action, len(entries)))
+	} else {
+		lines = append(lines, fmt.Sprintf("drift corrected, %s objects in namespace '%s': %d", action, namespace, len(entries)))
+	}
+	listed, lines := appendSummaryEntries(lines, entries, "  ", maxNames)
+	if remaining := len(entries) - listed; remaining > 0 {
+		lines = append(lines, fmt.Sprintf("  ... and %d more", remaining))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+)
+
+func correctedEntry(kind, namespace, name string, action Action) ChangeSetEntry {
+	subject := fmt.Sprintf("%s/%s", kind, name)
+	if namespace != "" {
+		subject = fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+	}
+	return ChangeSetEntry{
+		ObjMetadata: object.ObjMetadata{
+			Namespace: namespace,
+			Name:      name,
+			GroupKind: schema.GroupKind{Kind: kind},
+		},
+		Subject: subject,
+		Action:  action,
+	}
+}
+
+// recordedEvents returns the events recorded by the fake recorder.
+func recordedEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestDriftEventAggregator_Grouping(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(100)
+	aggregator := NewDriftEventAggregator(recorder, DriftEventOptions{})
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "flux-system"}}
+
+	cs := NewChangeSet()
+	cs.Add(correctedEntry("ConfigMap", "apps", "c", CreatedAction))
+	cs.Add(correctedEntry("ConfigMap", "apps", "a", CreatedAction))
+	cs.Add(correctedEntry("Deployment", "apps", "app", ConfiguredAction))
+	cs.Add(correctedEntry("Service", "apps", "app", UnchangedAction))
+	cs.Add(correctedEntry("Secret", "infra", "b", CreatedAction))
+	cs.Add(correctedEntry("Namespace", "", "apps", CreatedAction))
+	cs.Add(correctedEntry("ClusterRole", "", "app", SkippedAction))
+	aggregator.Record(owner, cs)
+
+	g.Expect(recordedEvents(recorder)).To(Equal([]string{
+		"Normal DriftCorrected drift corrected, created cluster-scoped objects: 1\n  Namespace/apps",
+		"Normal DriftCorrected drift corrected, created objects in namespace 'apps': 2\n  ConfigMap/apps/a\n  ConfigMap/apps/c",
+		"Normal DriftCorrected drift corrected, configured objects in namespace 'apps': 1\n  Deployment/apps/app",
+		"Normal DriftCorrected drift corrected, created objects in namespace 'infra': 1\n  Secret/infra/b",
+	}))
+
+	// A ChangeSet without corrections records no event.
+	cs = NewChangeSet()
+	cs.Add(correctedEntry("Service", "apps", "app", UnchangedAction))
+	aggregator.Record(owner, cs)
+	aggregator.Record(owner, nil)
+	g.Expect(recordedEvents(recorder)).To(BeEmpty())
+}
+
+func TestDriftEventAggregator_Truncation(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(100)
+	aggregator := NewDriftEventAggregator(recorder, DriftEventOptions{
+		MaxNames:  3,
+		Reason:    "DriftDetected",
+		EventType: corev1.EventTypeWarning,
+	})
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "flux-system"}}
+
+	cs := NewChangeSet()
+	for i := range 200 {
+		cs.Add(correctedEntry("ConfigMap", "apps", fmt.Sprintf("cm-%03d", i), CreatedAction))
+	}
+	aggregator.Record(owner, cs)
+
+	g.Expect(recordedEvents(recorder)).To(Equal([]string{
+		"Warning DriftDetected drift corrected, created objects in namespace 'apps': 200\n" +
+			"  ConfigMap/apps/cm-000\n  ConfigMap/apps/cm-001\n  ConfigMap/apps/cm-002\n  ... and 197 more",
+	}))
+}
+
+func TestDriftEventAggregator_Window(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(100)
+	aggregator := NewDriftEventAggregator(recorder, DriftEventOptions{Window: time.Minute})
+	now := time.Now()
+	aggregator.now = func() time.Time { return now }
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "flux-system"}}
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "flux-system"}}
+
+	recordDrift := func(obj *corev1.ConfigMap, entries ...ChangeSetEntry) []string {
+		cs := NewChangeSet()
+		cs.Append(entries)
+		aggregator.Record(obj, cs)
+		return recordedEvents(recorder)
+	}
+
+	g.Expect(recordDrift(owner, correctedEntry("ConfigMap", "apps", "a", CreatedAction))).To(HaveLen(1))
+
+	// The corrections within the window are held back, except for other
+	// namespaces, actions and objects.
+	now = now.Add(30 * time.Second)
+	g.Expect(recordDrift(owner, correctedEntry("ConfigMap", "apps", "b", CreatedAction))).To(BeEmpty())
+	g.Expect(recordDrift(owner, correctedEntry("ConfigMap", "apps", "a", CreatedAction))).To(BeEmpty())
+	g.Expect(recordDrift(owner, correctedEntry("ConfigMap", "apps", "a", ConfiguredAction))).To(HaveLen(1))
+	g.Expect(recordDrift(owner, correctedEntry("ConfigMap", "infra", "a", CreatedAction))).To(HaveLen(1))
+	g.Expect(recordDrift(other, correctedEntry("ConfigMap", "apps", "a", CreatedAction))).To(HaveLen(1))
+
+	// The held back corrections are reported once the window has passed,
+	// even without new corrections.
+	now = now.Add(31 * time.Second)
+	g.Expect(recordDrift(owner)).To(Equal([]string{
+		"Normal DriftCorrected drift corrected, created objects in namespace 'apps': 2\n  ConfigMap/apps/a\n  ConfigMap/apps/b",
+	}))
+	g.Expect(recordDrift(owner)).To(BeEmpty())
+
+	// The keys without corrections for a whole window are forgotten.
+	now = now.Add(2 * time.Minute)
+	g.Expect(recordDrift(owner)).To(BeEmpty())
+	g.Expect(aggregator.states).To(HaveLen(0))
+	g.Expect(recordDrift(owner, correctedEntry("ConfigMap", "apps", "a", CreatedAction))).To(HaveLen(1))
+}
@@ -17,9 +17,16 @@ limitations under the License.
 package errors
 
 import (
+	stderrors "errors"
+	"fmt"
 	"regexp"
+	"slices"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/pkg/ssa/utils"
 )
 
 // Match CEL immutable error variants.
@@ -28,6 +35,14 @@ var matchImmutableFieldErrors = []*regexp.Regexp{
 	regexp.MustCompile(`.*immutable\sfield.*`),
 }
 
+// Match the Kubernetes API validation messages of immutable fields, e.g.
+// 'field is immutable', 'may not change once set' for Service clusterIPs and
+// 'updates to statefulset spec for fields other than ... are forbidden'.
+var matchImmutableFieldCauses = append([]*regexp.Regexp{
+	regexp.MustCompile(`may\snot\schange\sonce\sset`),
+	regexp.MustCompile(`updates\sto\s.*\sare\sforbidden`),
+}, matchImmutableFieldErrors...)
+
 // IsImmutableError checks if the given error is an immutable error.
 func IsImmutableError(err error) bool {
 	// Detect immutability like kubectl does
@@ -46,3 +61,103 @@ func IsImmutableError(err error) bool {
 
 	return false
 }
+
+// ImmutableFieldPaths returns the paths of the immutable fields rejected by
+// the API server in the given error, and whether the error was caused by the
+// change of immutable fields. The paths are extracted from the causes of the
+// API status, they are empty if the error has no such causes, e.g. for errors
+// returned by custom admission webhooks.
+func ImmutableFieldPaths(err error) ([]string, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var fieldPaths []string
+	var status errors.APIStatus
+	if stderrors.As(err, &status) && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Field == "" || !matchesAny(matchImmutableFieldCauses, cause.Message) {
+				continue
+			}
+			if !slices.Contains(fieldPaths, cause.Field) {
+				fieldPaths = append(fieldPaths, cause.Field)
+			}
+		}
+	}
+	if len(fieldPaths) > 0 {
+		return fieldPaths, true
+	}
+
+	return nil, matchesAny(matchImmutableFieldCauses, err.Error())
+}
+
+// FmtImmutableFields returns a description of the given immutable field paths,
+// e.g. 'immutable field spec.clusterIP'.
+func FmtImmutableFields(fieldPaths []string) string {
+	switch len(fieldPaths) {
+	case 0:
+		return "immutable fields"
+	case 1:
+		return "immutable field " + fieldPaths[0]
+	default:
+		return "immutable fields " + strings.Join(fieldPaths, ", ")
+	}
+}
+
+func matchesAny(regexps []*regexp.Regexp, s string) bool {
+	for _, r := range regexps {
+		if r.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrImmutableField is an error that occurs when the server-side dry-run apply
+// of an object fails due to the change of immutable fields, and the object is
+// not recreated.
+type ErrImmutableField struct {
+	dryRunErr  *DryRunErr
+	fieldPaths []string
+}
+
+// NewImmutableFieldErr returns a new ErrImmutableField for the given dry-run
+// error and the paths of the immutable fields.
+func NewImmutableFieldErr(err error, involvedObject *unstructured.Unstructured, fieldPaths []string) *ErrImmutableField {
+	return &ErrImmutableField{
+		dryRunErr:  NewDryRunErr(err, involvedObject),
+		fieldPaths: fieldPaths,
+	}
+}
+
+// InvolvedObject returns the involved object.
+func (e *ErrImmutableField) InvolvedObject() *unstructured.Unstructured {
+	return e.dryRunErr.InvolvedObject()
+}
+
+// FieldPaths returns the paths of the immutable fields, if known.
+func (e *ErrImmutableField) FieldPaths() []string {
+	return e.fieldPaths
+}
+
+// Error returns the error message of the dry-run.
+func (e *ErrImmutableField) Error() string {
+	return e.dryRunErr.Error()
+}
+
+// Unwrap returns the underlying DryRunErr.
+func (e *ErrImmutableField) Unwrap() error {
+	return e.dryRunErr
+}
+
+// Suggestion returns a human-readable message describing the immutable field
+// change and suggesting to enable force apply to recreate the object.
+func (e *ErrImmutableField) Suggestion() string {
+	subject := "the object"
+	if obj := e.InvolvedObject(); obj != nil {
+		subject = utils.FmtUnstructured(obj)
+	}
+	return fmt.Sprintf("%s cannot be updated due to changes to %s, "+
+		"enable force apply to delete and recreate the object, or revert the changes",
+		subject, FmtImmutableFields(e.fieldPaths))
+}
@@ -17,10 +17,15 @@ limitations under the License.
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func TestIsImmutableError(t *testing.T) {
@@ -54,3 +59,117 @@ func TestIsImmutableError(t *testing.T) {
 		})
 	}
 }
+
+func TestImmutableFieldPaths(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       error
+		wantPaths []string
+		match     bool
+	}{
+		{
+			name: "Service cluster IP",
+			err: apierrors.NewInvalid(schema.GroupKind{Kind: "Service"}, "test", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "clusterIPs").Index(0), "10.0.0.2", "may not change once set"),
+				field.Invalid(field.NewPath("spec", "clusterIP"), "10.0.0.2", "field is immutable"),
+			}),
+			wantPaths: []string{"spec.clusterIPs[0]", "spec.clusterIP"},
+			match:     true,
+		},
+		{
+			name: "immutable Secret data",
+			err: apierrors.NewInvalid(schema.GroupKind{Kind: "Secret"}, "test", field.ErrorList{
+				field.Forbidden(field.NewPath("data"), "field is immutable when `immutable` is set"),
+			}),
+			wantPaths: []string{"data"},
+			match:     true,
+		},
+		{
+			name: "StatefulSet spec",
+			err: apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "StatefulSet"}, "test", field.ErrorList{
+				field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than "+
+					"'replicas', 'ordinals', 'template', 'updateStrategy', 'persistentVolumeClaimRetentionPolicy' "+
+					"and 'minReadySeconds' are forbidden"),
+			}),
+			wantPaths: []string{"spec"},
+			match:     true,
+		},
+		{
+			name: "duplicate causes",
+			err: apierrors.NewInvalid(schema.GroupKind{Group: "batch", Kind: "Job"}, "test", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "template"), "", "field is immutable"),
+				field.Invalid(field.NewPath("spec", "template"), "", "field is immutable"),
+			}),
+			wantPaths: []string{"spec.template"},
+			match:     true,
+		},
+		{
+			name:  "custom admission immutable error",
+			err:   fmt.Errorf(`admission webhook "deny-immutable-field-updates.cnrm.cloud.google.com" denied the request: the IAMPolicyMember's spec is immutable`),
+			match: true,
+		},
+		{
+			name: "invalid value",
+			err: apierrors.NewInvalid(schema.GroupKind{Kind: "Service"}, "test", field.ErrorList{
+				field.Required(field.NewPath("spec", "ports"), ""),
+			}),
+			match: false,
+		},
+		{
+			name:  "conflict",
+			err:   apierrors.NewConflict(schema.GroupResource{Resource: "services"}, "test", fmt.Errorf("the object has been modified")),
+			match: false,
+		},
+		{
+			name:  "nil error",
+			match: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			paths, match := ImmutableFieldPaths(tc.err)
+			g.Expect(match).To(Equal(tc.match))
+			g.Expect(paths).To(Equal(tc.wantPaths))
+		})
+	}
+}
+
+func TestFmtImmutableFields(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(FmtImmutableFields(nil)).To(Equal("immutable fields"))
+	g.Expect(FmtImmutableFields([]string{"spec.clusterIP"})).To(Equal("immutable field spec.clusterIP"))
+	g.Expect(FmtImmutableFields([]string{"spec.selector", "spec.template"})).
+		To(Equal("immutable fields spec.selector, spec.template"))
+}
+
+func TestErrImmutableField(t *testing.T) {
+	g := NewWithT(t)
+
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion("v1")
+	object.SetKind("Service")
+	object.SetNamespace("default")
+	object.SetName("test")
+	err := apierrors.NewInvalid(schema.GroupKind{Kind: "Service"}, "test", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "clusterIP"), "10.0.0.2", "field is immutable"),
+	})
+
+	immutableErr := NewImmutableFieldErr(err, object, []string{"spec.clusterIP"})
+	g.Expect(immutableErr.Error()).To(Equal(NewDryRunErr(err, object).Error()))
+	g.Expect(immutableErr.FieldPaths()).To(Equal([]string{"spec.clusterIP"}))
+	g.Expect(immutableErr.InvolvedObject()).To(Equal(object))
+	g.Expect(immutableErr.Suggestion()).To(Equal("Service/default/test cannot be updated due to changes to " +
+		"immutable field spec.clusterIP, enable force apply to delete and recreate the object, or revert the changes"))
+
+	var wrapped error = fmt.Errorf("apply failed: %w", immutableErr)
+	var dryRunErr *DryRunErr
+	g.Expect(stderrors.As(wrapped, &dryRunErr)).To(BeTrue())
+	g.Expect(apierrors.IsInvalid(wrapped)).To(BeTrue())
+
+	g.Expect(NewImmutableFieldErr(err, nil, nil).Suggestion()).To(Equal("the object cannot be updated due to " +
+		"changes to immutable fields, enable force apply to delete and recreate the object, or revert the changes"))
+}
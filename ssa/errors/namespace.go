@@ -0,0 +1,62 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// IsNamespaceTerminatingError checks if the given error was returned by the
+// Kubernetes API because the namespace of the object is being terminated.
+func IsNamespaceTerminatingError(err error) bool {
+	return err != nil && errors.HasStatusCause(err, corev1.NamespaceTerminatingCause)
+}
+
+// ErrNamespaceTerminating is an error that occurs when objects are not
+// applied because their namespace is being terminated. The objects can be
+// applied again once the namespace is deleted.
+type ErrNamespaceTerminating struct {
+	namespaces []string
+}
+
+// NewNamespaceTerminatingErr returns a new ErrNamespaceTerminating for the
+// given namespaces.
+func NewNamespaceTerminatingErr(namespaces ...string) *ErrNamespaceTerminating {
+	ns := slices.Clone(namespaces)
+	slices.Sort(ns)
+	return &ErrNamespaceTerminating{namespaces: slices.Compact(ns)}
+}
+
+// Namespaces returns the sorted names of the terminating namespaces.
+func (e *ErrNamespaceTerminating) Namespaces() []string {
+	return e.namespaces
+}
+
+// Error returns the error message.
+func (e *ErrNamespaceTerminating) Error() string {
+	noun := "namespace"
+	if len(e.namespaces) > 1 {
+		noun = "namespaces"
+	}
+	return fmt.Sprintf("unable to apply objects in %s '%s': being terminated",
+		noun, strings.Join(e.namespaces, "', '"))
+}
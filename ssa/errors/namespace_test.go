@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsNamespaceTerminatingError(t *testing.T) {
+	// The error returned by the NamespaceLifecycle admission plugin.
+	terminatingErr := apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "test",
+		fmt.Errorf("unable to create new content in namespace apps because it is being terminated"))
+	terminatingErr.ErrStatus.Details.Causes = []metav1.StatusCause{{
+		Type:    corev1.NamespaceTerminatingCause,
+		Message: "namespace apps is being terminated",
+		Field:   "metadata.namespace",
+	}}
+
+	testCases := []struct {
+		name  string
+		err   error
+		match bool
+	}{
+		{
+			name:  "namespace terminating",
+			err:   terminatingErr,
+			match: true,
+		},
+		{
+			name:  "wrapped namespace terminating",
+			err:   fmt.Errorf("apply failed: %w", terminatingErr),
+			match: true,
+		},
+		{
+			name: "forbidden",
+			err: apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "test",
+				fmt.Errorf("denied")),
+		},
+		{
+			name: "nil",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(IsNamespaceTerminatingError(tc.err)).To(BeIdenticalTo(tc.match))
+		})
+	}
+}
+
+func TestErrNamespaceTerminating(t *testing.T) {
+	g := NewWithT(t)
+
+	err := fmt.Errorf("apply failed: %w", NewNamespaceTerminatingErr("b", "a", "b"))
+	var nsErr *ErrNamespaceTerminating
+	g.Expect(stderrors.As(err, &nsErr)).To(BeTrue())
+	g.Expect(nsErr.Namespaces()).To(Equal([]string{"a", "b"}))
+	g.Expect(nsErr.Error()).To(Equal("unable to apply objects in namespaces 'a', 'b': being terminated"))
+
+	g.Expect(NewNamespaceTerminatingErr("a").Error()).To(Equal("unable to apply objects in namespace 'a': being terminated"))
+}
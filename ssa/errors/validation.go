@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrValidationFailed is an error that occurs when the server-side dry-run
+// validation of a set of objects fails, before any of them is applied. It
+// holds the dry-run errors of all the invalid objects of the set.
+type ErrValidationFailed struct {
+	errs []error
+}
+
+// NewValidationFailedErr returns a new ErrValidationFailed for the given
+// dry-run errors.
+func NewValidationFailedErr(errs ...error) *ErrValidationFailed {
+	return &ErrValidationFailed{errs: errs}
+}
+
+// Errors returns the dry-run errors of the invalid objects.
+func (e *ErrValidationFailed) Errors() []error {
+	return e.errs
+}
+
+// Error returns the error message.
+func (e *ErrValidationFailed) Error() string {
+	noun := "object"
+	if len(e.errs) > 1 {
+		noun = "objects"
+	}
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("validation failed for %d %s, no object was applied:\n%s",
+		len(e.errs), noun, strings.Join(msgs, "\n"))
+}
+
+// Unwrap returns the dry-run errors.
+func (e *ErrValidationFailed) Unwrap() []error {
+	return e.errs
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestErrValidationFailed(t *testing.T) {
+	g := NewWithT(t)
+
+	newObject := func(name string) *unstructured.Unstructured {
+		o := &unstructured.Unstructured{}
+		o.SetAPIVersion("v1")
+		o.SetKind("ConfigMap")
+		o.SetNamespace("default")
+		o.SetName(name)
+		return o
+	}
+	invalid := func(name string) error {
+		return NewDryRunErr(apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, name, field.ErrorList{
+			field.Invalid(field.NewPath("data", "key"), 1, "must be a string"),
+		}), newObject(name))
+	}
+
+	err := NewValidationFailedErr(invalid("first"), invalid("second"))
+	g.Expect(err.Errors()).To(HaveLen(2))
+	g.Expect(err.Error()).To(HavePrefix("validation failed for 2 objects, no object was applied:\n" +
+		"ConfigMap/default/first dry-run failed (Invalid): "))
+	g.Expect(err.Error()).To(ContainSubstring("\nConfigMap/default/second dry-run failed (Invalid): "))
+
+	var dryRunErr *DryRunErr
+	g.Expect(stderrors.As(err, &dryRunErr)).To(BeTrue())
+	g.Expect(dryRunErr.InvolvedObject().GetName()).To(Equal("first"))
+	g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	g.Expect(stderrors.Is(err, dryRunErr)).To(BeTrue())
+
+	g.Expect(NewValidationFailedErr(invalid("single")).Error()).To(HavePrefix("validation failed for 1 object, no object was applied:\n"))
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+// IgnorePathsAnnotation is the annotation listing the comma-separated JSON
+// pointers (RFC 6901) of the fields of an object which are excluded from
+// drift detection and correction, e.g. '/spec/replicas' for a Deployment
+// scaled by a HorizontalPodAutoscaler, or '/webhooks/0/clientConfig/caBundle'
+// for a webhook configuration injected by cert-manager.
+//
+// The fields are removed from both the desired and in-cluster objects before
+// they are compared, and are left out of the apply configuration so that the
+// values set by the other field managers persist. A field which is solely
+// owned by the ResourceManager, e.g. because the annotation was added after
+// the field was applied, is set to its in-cluster value instead, as leaving
+// it out would remove it from the object.
+const IgnorePathsAnnotation = "kustomize.toolkit.fluxcd.io/ignore-paths"
+
+// identityPaths are the JSON pointers which cannot be ignored, as the object
+// cannot be applied without them.
+var identityPaths = []string{
+	"/apiVersion",
+	"/kind",
+	"/metadata",
+	"/metadata/name",
+	"/metadata/namespace",
+}
+
+// ignorePaths returns the JSON pointers listed in the IgnorePathsAnnotation of
+// the object, along with a warning for each malformed one.
+func ignorePaths(object *unstructured.Unstructured) (paths []string, warnings []string) {
+	value, ok := object.GetAnnotations()[IgnorePathsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if err := validateIgnorePath(path); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s annotation: invalid path '%s': %s",
+				IgnorePathsAnnotation, path, err))
+			continue
+		}
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths, warnings
+}
+
+// validateIgnorePath returns an error if the path is not a valid JSON pointer
+// to a field which can be left out of the apply configuration.
+func validateIgnorePath(path string) error {
+	if _, err := jsonpointer.New(path); err != nil {
+		return err
+	}
+	for i := 0; i < len(path); i++ {
+		if path[i] == '~' && (i+1 == len(path) || (path[i+1] != '0' && path[i+1] != '1')) {
+			return fmt.Errorf("invalid escape sequence at offset %d", i)
+		}
+	}
+	if slices.Contains(identityPaths, path) {
+		return fmt.Errorf("the field is required to apply the object")
+	}
+	return nil
+}
+
+// ignoreWarnings returns the warnings of the IgnorePathsAnnotation of the
+// object, if any.
+func ignoreWarnings(object *unstructured.Unstructured) []string {
+	_, warnings := ignorePaths(object)
+	return warnings
+}
+
+// withoutIgnoredPaths returns a copy of the object without the fields of the
+// given paths, except for those solely owned by fieldManager in the
+// existing object, which are set to their in-cluster value.
+// The object is returned as is if there are no paths.
+func withoutIgnoredPaths(object, existingObject *unstructured.Unstructured,
+	paths []string, fieldManager string) (*unstructured.Unstructured, error) {
+	if len(paths) == 0 {
+		return object, nil
+	}
+
+	var dr driftResult
+	var parsed []parsedManagedField
+	if existingObject.GetResourceVersion() != "" {
+		parsed = parseManagedFieldsApply(existingObject)
+	}
+	for _, path := range paths {
+		if parsed != nil && isFieldSolelyOwnedByApplyManager(existingObject, path, fieldManager, parsed) {
+			existingVal, ef, _ := lookupJSONPointer(existingObject, path)
+			_, df, _ := lookupJSONPointer(object, path)
+			if ef && df {
+				dr.entries = append(dr.entries, driftEntry{path: path, action: driftAdopt, value: existingVal})
+			}
+			continue
+		}
+		dr.entries = append(dr.entries, driftEntry{path: path, action: driftStrip})
+	}
+
+	result := object.DeepCopy()
+	if err := applyDriftResult(result, dr); err != nil {
+		return nil, fmt.Errorf("%s failed to remove the fields of the %s annotation: %w",
+			utils.FmtUnstructured(object), IgnorePathsAnnotation, err)
+	}
+	return result, nil
+}
+
+// isFieldSolelyOwnedByApplyManager checks whether fieldManager is the only
+// Apply field manager owning the given JSON pointer path on the object.
+func isFieldSolelyOwnedByApplyManager(
+	obj *unstructured.Unstructured,
+	pointer string,
+	fieldManager string,
+	parsed []parsedManagedField,
+) bool {
+	segments, err := pointerToFieldsV1Segments(obj, pointer, parsed)
+	if err != nil {
+		return false
+	}
+	owned := false
+	for _, entry := range parsed {
+		if !fieldsV1Contains(entry.fields, segments) {
+			continue
+		}
+		if entry.manager != fieldManager {
+			return false
+		}
+		owned = true
+	}
+	return owned
+}
+
+// withIgnorePaths returns a copy of the rules with the given paths ignored on
+// all objects, or the rules as is if there are no paths.
+func withIgnorePaths(rules jsondiff.CompiledIgnoreRules, paths []string) jsondiff.CompiledIgnoreRules {
+	if len(paths) == 0 {
+		return rules
+	}
+	merged := maps.Clone(rules)
+	if merged == nil {
+		merged = make(jsondiff.CompiledIgnoreRules, 1)
+	}
+	// A nil selector matches all objects.
+	merged[nil] = append(slices.Clone(merged[nil]), paths...)
+	return merged
+}
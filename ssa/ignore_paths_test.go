@@ -0,0 +1,257 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/ssa/normalize"
+)
+
+func TestApply_IgnorePathsAnnotation(t *testing.T) {
+	timeout := 30 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("ignore-paths")
+	objects, err := readManifest("testdata/test15.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.SetOwnerLabels(objects, "app1", "default")
+
+	if err := normalize.UnstructuredList(objects); err != nil {
+		t.Fatal(err)
+	}
+
+	deployName, deployObject := getFirstObject(objects, "Deployment", id)
+
+	getReplicas := func(t *testing.T) int64 {
+		t.Helper()
+		existing := deployObject.DeepCopy()
+		if err := manager.client.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+			t.Fatal(err)
+		}
+		replicas, _, _ := unstructured.NestedInt64(existing.Object, "spec", "replicas")
+		return replicas
+	}
+
+	deployEntry := func(t *testing.T, changeSet *ChangeSet) ChangeSetEntry {
+		t.Helper()
+		for _, entry := range changeSet.Entries {
+			if entry.Subject == deployName {
+				return entry
+			}
+		}
+		t.Fatalf("no entry for %s", deployName)
+		return ChangeSetEntry{}
+	}
+
+	t.Run("creates objects without the ignored fields", func(t *testing.T) {
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := deployEntry(t, changeSet)
+		if diff := cmp.Diff(CreatedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if len(entry.Warnings) != 1 || !strings.Contains(entry.Warnings[0], "'spec/paused'") {
+			t.Errorf("expected a warning for the malformed path, got %v", entry.Warnings)
+		}
+
+		// The replicas are left to the defaults of the API server.
+		if diff := cmp.Diff(int64(1), getReplicas(t)); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		existing := deployObject.DeepCopy()
+		if err := manager.client.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+			t.Fatal(err)
+		}
+		for _, mf := range existing.GetManagedFields() {
+			if mf.Manager == manager.owner.Field && strings.Contains(string(mf.FieldsV1.Raw), "f:replicas") {
+				t.Errorf("expected %s not to own spec.replicas", manager.owner.Field)
+			}
+		}
+	})
+
+	t.Run("skips the fields scaled by the autoscaler", func(t *testing.T) {
+		// The HPA controller updates the replicas through the scale subresource.
+		scale := deployObject.DeepCopy()
+		patch := client.RawPatch(types.MergePatchType, []byte(`{"spec":{"replicas":5}}`))
+		if err := manager.client.SubResource("scale").Patch(ctx, scale, patch,
+			client.FieldOwner("kube-controller-manager")); err != nil {
+			t.Fatal(err)
+		}
+
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(UnchangedAction, deployEntry(t, changeSet).Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(int64(5), getReplicas(t)); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+
+		entry, _, _, err := manager.Diff(ctx, deployObject, DefaultDiffOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(UnchangedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if len(entry.Warnings) != 1 {
+			t.Errorf("expected a warning for the malformed path, got %v", entry.Warnings)
+		}
+	})
+
+	t.Run("corrects the drift of the other fields", func(t *testing.T) {
+		err := unstructured.SetNestedSlice(deployObject.Object, []any{
+			map[string]any{
+				"name":  "podinfod",
+				"image": "ghcr.io/stefanprodan/podinfo:6.1.0",
+				"resources": map[string]any{
+					"requests": map[string]any{"cpu": "100m"},
+				},
+			},
+		}, "spec", "template", "spec", "containers")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry, err := manager.Apply(ctx, deployObject, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ConfiguredAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if len(entry.Warnings) != 1 {
+			t.Errorf("expected a warning for the malformed path, got %v", entry.Warnings)
+		}
+		if diff := cmp.Diff(int64(5), getReplicas(t)); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestApply_IgnorePathsAnnotation_SoleOwner(t *testing.T) {
+	timeout := 30 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("ignore-paths-owner")
+	objects, err := readManifest("testdata/test15.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.SetOwnerLabels(objects, "app1", "default")
+
+	if err := normalize.UnstructuredList(objects); err != nil {
+		t.Fatal(err)
+	}
+
+	_, deployObject := getFirstObject(objects, "Deployment", id)
+	annotations := deployObject.GetAnnotations()
+	delete(annotations, IgnorePathsAnnotation)
+	deployObject.SetAnnotations(annotations)
+
+	if _, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The replicas applied before the annotation was added are kept.
+	annotations[IgnorePathsAnnotation] = "/spec/replicas"
+	deployObject.SetAnnotations(annotations)
+	if err := unstructured.SetNestedField(deployObject.Object, int64(3), "spec", "replicas"); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := manager.Apply(ctx, deployObject, DefaultApplyOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(ConfiguredAction, entry.Action); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+	if len(entry.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", entry.Warnings)
+	}
+
+	existing := deployObject.DeepCopy()
+	if err := manager.client.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+		t.Fatal(err)
+	}
+	replicas, _, _ := unstructured.NestedInt64(existing.Object, "spec", "replicas")
+	if diff := cmp.Diff(int64(2), replicas); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+
+	// The annotation change of the ignored field only is not a drift.
+	entry, err = manager.Apply(ctx, deployObject, DefaultApplyOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(UnchangedAction, entry.Action); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+}
+
+func Test_ignorePaths(t *testing.T) {
+	for _, tt := range []struct {
+		value        string
+		wantPaths    []string
+		wantWarnings int
+	}{
+		{value: "/spec/replicas", wantPaths: []string{"/spec/replicas"}},
+		{
+			value:     " /spec/replicas , /webhooks/0/clientConfig/caBundle,,/spec/replicas",
+			wantPaths: []string{"/spec/replicas", "/webhooks/0/clientConfig/caBundle"},
+		},
+		{value: "/metadata/annotations/example.com~1key", wantPaths: []string{"/metadata/annotations/example.com~1key"}},
+		{value: "spec/replicas", wantWarnings: 1},
+		{value: "/spec/a~2b,/kind,/metadata/name,/spec/b~", wantWarnings: 4},
+		{value: "", wantPaths: nil},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			object := &unstructured.Unstructured{}
+			object.SetAnnotations(map[string]string{IgnorePathsAnnotation: tt.value})
+
+			paths, warnings := ignorePaths(object)
+			if diff := cmp.Diff(tt.wantPaths, paths); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("expected %d warnings, got %s", tt.wantWarnings, fmt.Sprint(warnings))
+			}
+		})
+	}
+}
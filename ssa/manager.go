@@ -18,6 +18,8 @@ limitations under the License.
 package ssa
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -91,5 +93,6 @@ func (m *ResourceManager) changeSetEntry(o *unstructured.Unstructured, action Ac
 		GroupVersion: o.GroupVersionKind().Version,
 		Subject:      utils.FmtUnstructured(o),
 		Action:       action,
+		Timestamp:    time.Now().UTC(),
 	}
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -84,11 +85,48 @@ type ApplyOptions struct {
 	// apply with "field not declared in schema" for the new defaulted field.
 	MigrateAPIVersion bool `json:"migrateAPIVersion,omitempty"`
 
+	// PreserveKubectlFields, when enabled, preserves the fields of objects
+	// created with the client-side 'kubectl apply' on the first apply.
+	//
+	// When taking over an object whose managed fields show the
+	// KubectlClientSideManager, and which was never applied by the
+	// ResourceManager, the fields of the kubectl last applied configuration
+	// which are absent from the applied object are merged into it, instead
+	// of being removed from the in-cluster object. The preserved fields are
+	// reported in the ChangeSetEntry, so that users can add them to their
+	// manifests before the next apply removes them.
+	PreserveKubectlFields bool `json:"preserveKubectlFields,omitempty"`
+
 	// DriftIgnoreRules defines a list of JSON pointer ignore rules that are used to
 	// remove specific fields from objects before applying them.
 	// This is useful for ignoring fields that are managed by other controllers
 	// (e.g. VPA, HPA) and would otherwise cause drift.
 	DriftIgnoreRules []jsondiff.IgnoreRule `json:"driftIgnoreRules,omitempty"`
+
+	// Validators defines the validators run against each object before it is
+	// applied. The objects which violate a policy are not applied, and are
+	// reported in the change set as skipped with the violations as message.
+	Validators []ObjectValidator `json:"-"`
+
+	// ValidateBeforeApply, when enabled, performs a server-side dry-run of
+	// all the objects of the set before applying any of them. If any object
+	// fails the dry-run, no object is applied and an ssaerrors.ErrValidationFailed
+	// listing the errors of all the invalid objects is returned, so that they
+	// can be fixed at once instead of leaving the cluster half-updated.
+	//
+	// The custom resources of the CRDs of the set, and the objects of the
+	// namespaces of the set which do not exist yet, cannot be validated
+	// before the CRDs and namespaces are applied. They are applied without
+	// the validation, with the reason in the message of their ChangeSetEntry.
+	ValidateBeforeApply bool `json:"validateBeforeApply,omitempty"`
+
+	// ApplyPolicies defines the field manager, the conflict policy and the
+	// drift correction of classes of objects. Each object is applied with the
+	// first policy whose selector matches the desired object, or with the
+	// field manager of the ResourceManager owner, forcing the conflicts and
+	// correcting the drift, if none matches. The name of the policy is
+	// recorded in the ChangeSetEntry of the object.
+	ApplyPolicies []ApplyPolicy `json:"applyPolicies,omitempty"`
 }
 
 // ApplyCleanupOptions defines which metadata entries are to be removed before applying objects.
@@ -120,7 +158,27 @@ func DefaultApplyOptions() ApplyOptions {
 // Apply performs a server-side apply of the given object if the matching in-cluster object is different or if it doesn't exist.
 // Drift detection is performed by comparing the server-side dry-run result with the existing object.
 // When immutable field changes are detected, the object is recreated if 'force' is set to 'true'.
+// When the namespace of the object is being terminated, a skipped ChangeSetEntry is returned
+// along with an ssaerrors.ErrNamespaceTerminating error.
+// The object is applied according to the first of the ApplyOptions.ApplyPolicies matching it.
 func (m *ResourceManager) Apply(ctx context.Context, object *unstructured.Unstructured, opts ApplyOptions) (*ChangeSetEntry, error) {
+	policies, err := compileApplyPolicies(opts.ApplyPolicies)
+	if err != nil {
+		return nil, err
+	}
+	policy := m.applyPolicyFor(policies, object)
+
+	entry, err := m.applyObject(ctx, object, opts, policy)
+	if entry != nil {
+		entry.ApplyPolicy = policy.Name
+		entry.Warnings = ignoreWarnings(object)
+	}
+	return entry, err
+}
+
+// applyObject performs the server-side apply of Apply with the given policy.
+func (m *ResourceManager) applyObject(ctx context.Context, object *unstructured.Unstructured,
+	opts ApplyOptions, policy ApplyPolicy) (*ChangeSetEntry, error) {
 	existingObject := &unstructured.Unstructured{}
 	existingObject.SetGroupVersionKind(object.GroupVersionKind())
 	getError := m.client.Get(ctx, client.ObjectKeyFromObject(object), existingObject)
@@ -129,6 +187,23 @@ func (m *ResourceManager) Apply(ctx context.Context, object *unstructured.Unstru
 		return skippedEntry, nil
 	}
 
+	if violationEntry, err := m.validate(ctx, object, opts); err != nil || violationEntry != nil {
+		return violationEntry, err
+	}
+
+	object, preservedFields, err := m.preserveKubectlFields(object, existingObject, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to preserve kubectl fields: %w", utils.FmtUnstructured(existingObject), err)
+	}
+
+	// The fields of the ignore paths annotation are left out of both the
+	// dry-run and the apply configuration.
+	paths, _ := ignorePaths(object)
+	object, err = withoutIgnoredPaths(object, existingObject, paths, policy.FieldManager)
+	if err != nil {
+		return nil, err
+	}
+
 	var patched bool
 	if opts.MigrateAPIVersion && getError == nil {
 		var err error
@@ -139,16 +214,29 @@ func (m *ResourceManager) Apply(ctx context.Context, object *unstructured.Unstru
 	}
 
 	dryRunObject := object.DeepCopy()
-	if err := m.dryRunApply(ctx, dryRunObject); err != nil {
+	if err := m.dryRunApply(ctx, dryRunObject, policy); err != nil {
+		if m.isNamespaceTerminating(ctx, object, err) {
+			return m.namespaceTerminatingEntry(object), ssaerrors.NewNamespaceTerminatingErr(object.GetNamespace())
+		}
+
+		if !policy.ForceConflicts && errors.IsConflict(err) {
+			return m.conflictEntry(object, policy, err).withObjectRef(existingObject), nil
+		}
+
 		if !errors.IsNotFound(getError) && m.shouldForceApply(object, existingObject, opts, err) {
 			if err := m.client.Delete(ctx, existingObject, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
 				return nil, fmt.Errorf("%s immutable field detected, failed to delete object: %w",
 					utils.FmtUnstructured(dryRunObject), err)
 			}
-			return m.Apply(ctx, object, opts)
+			entry, applyErr := m.applyObject(ctx, object, opts, policy)
+			if applyErr != nil {
+				return nil, applyErr
+			}
+			entry.Message = replacedMessage(err)
+			return entry, nil
 		}
 
-		return nil, ssaerrors.NewDryRunErr(err, dryRunObject)
+		return nil, newDryRunErr(err, dryRunObject)
 	}
 
 	patchedCleanupMetadata, err := m.cleanupMetadata(ctx, object, existingObject, opts.Cleanup)
@@ -170,12 +258,15 @@ func (m *ResourceManager) Apply(ctx context.Context, object *unstructured.Unstru
 	// Do not apply objects that have not drifted to avoid bumping the resource version.
 	// Ignored fields are excluded from the comparison so that differences in fields
 	// managed by other controllers (e.g. VPA, HPA) do not trigger unnecessary applies.
-	drifted, err := m.hasDriftedWithIgnore(existingObject, dryRunObject, compiled)
+	drifted, err := m.hasDriftedWithIgnore(existingObject, dryRunObject, withIgnorePaths(compiled, paths))
 	if err != nil {
 		return nil, err
 	}
 	if !patched && !drifted {
-		return m.changeSetEntry(object, UnchangedAction), nil
+		return m.changeSetEntry(object, UnchangedAction).withObjectRef(existingObject), nil
+	}
+	if policy.SkipDriftCorrection && existingObject.GetResourceVersion() != "" {
+		return m.driftNotCorrectedEntry(object, policy).withObjectRef(existingObject), nil
 	}
 
 	appliedObject := object.DeepCopy()
@@ -184,34 +275,64 @@ func (m *ResourceManager) Apply(ctx context.Context, object *unstructured.Unstru
 	// another Apply manager owns it) or adopt the in-cluster value (when Flux
 	// is the sole owner) to avoid API errors and silent value corruption.
 	if compiled != nil {
-		dr := computeDriftedPaths(existingObject, dryRunObject, compiled, m.owner.Field)
+		dr := computeDriftedPaths(existingObject, dryRunObject, compiled, policy.FieldManager)
 		if err := applyDriftResult(appliedObject, dr); err != nil {
 			return nil, err
 		}
 	}
 
-	if err := m.apply(ctx, appliedObject); err != nil {
+	if err := m.apply(ctx, appliedObject, policy); err != nil {
+		if m.isNamespaceTerminating(ctx, appliedObject, err) {
+			return m.namespaceTerminatingEntry(appliedObject), ssaerrors.NewNamespaceTerminatingErr(appliedObject.GetNamespace())
+		}
 		return nil, fmt.Errorf("%s apply failed: %w", utils.FmtUnstructured(appliedObject), err)
 	}
 
 	if dryRunObject.GetResourceVersion() == "" {
-		return m.changeSetEntry(appliedObject, CreatedAction), nil
+		return m.changeSetEntry(appliedObject, CreatedAction).withObjectRef(appliedObject), nil
 	}
 
-	return m.changeSetEntry(appliedObject, ConfiguredAction), nil
+	entry := m.changeSetEntry(appliedObject, ConfiguredAction).withObjectRef(appliedObject)
+	entry.PreservedFields = preservedFields
+	return entry, nil
 }
 
 // ApplyAll performs a server-side dry-run of the given objects, and based on the diff result,
 // it applies the objects that are new or modified.
+// The objects whose namespace is being terminated are skipped, in which case the ChangeSet is
+// returned along with an ssaerrors.ErrNamespaceTerminating error listing the namespaces.
+// With ApplyOptions.ValidateBeforeApply, no object is applied unless all of them pass the
+// dry-run, otherwise an ssaerrors.ErrValidationFailed is returned.
+// Each object is applied according to the first of the ApplyOptions.ApplyPolicies matching it.
 func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.Unstructured, opts ApplyOptions) (*ChangeSet, error) {
 	sort.Sort(SortableUnstructureds(objects))
 
+	if opts.ValidateBeforeApply {
+		deferred, err := m.validateAll(ctx, objects, opts)
+		if err != nil {
+			return nil, err
+		}
+		opts.ValidateBeforeApply = false
+		changeSet, err := m.ApplyAll(ctx, objects, opts)
+		return changeSet.withDeferredValidation(deferred), err
+	}
+
 	// Results are written to the following arrays from the concurrent goroutines. We use arrays
 	// to avoid complex synchronization. toApply is sparse, slots are only popuplated when there
 	// is an object to apply
 	toApply := make([]*unstructured.Unstructured, len(objects))
 	changes := make([]ChangeSetEntry, len(objects))
 	driftResults := make([]driftResult, len(objects))
+	// terminating holds the namespaces of the objects skipped because
+	// their namespace is being terminated.
+	terminating := make([]string, len(objects))
+	// policies holds the apply policy of each object.
+	policies := make([]ApplyPolicy, len(objects))
+
+	compiledPolicies, err := compileApplyPolicies(opts.ApplyPolicies)
+	if err != nil {
+		return nil, err
+	}
 
 	// Compile ignore rules once for drift detection and conditional field stripping.
 	var compiled jsondiff.CompiledIgnoreRules
@@ -230,6 +351,8 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 
 			g.Go(func() error {
 				utils.RemoveCABundleFromCRD(object)
+				policy := m.applyPolicyFor(compiledPolicies, object)
+				policies[i] = policy
 
 				existingObject := &unstructured.Unstructured{}
 				existingObject.SetGroupVersionKind(object.GroupVersionKind())
@@ -240,6 +363,26 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 					return nil
 				}
 
+				violationEntry, err := m.validate(ctx, object, opts)
+				if err != nil {
+					return err
+				}
+				if violationEntry != nil {
+					changes[i] = *violationEntry
+					return nil
+				}
+
+				object, preservedFields, err := m.preserveKubectlFields(object, existingObject, opts)
+				if err != nil {
+					return fmt.Errorf("%s failed to preserve kubectl fields: %w", utils.FmtUnstructured(existingObject), err)
+				}
+
+				paths, _ := ignorePaths(object)
+				object, err = withoutIgnoredPaths(object, existingObject, paths, policy.FieldManager)
+				if err != nil {
+					return err
+				}
+
 				var patched bool
 				if opts.MigrateAPIVersion && getError == nil {
 					var err error
@@ -250,12 +393,25 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 				}
 
 				dryRunObject := object.DeepCopy()
-				if err := m.dryRunApply(ctx, dryRunObject); err != nil {
+				var message string
+				if err := m.dryRunApply(ctx, dryRunObject, policy); err != nil {
+					if m.isNamespaceTerminating(ctx, object, err) {
+						changes[i] = *m.namespaceTerminatingEntry(object)
+						terminating[i] = object.GetNamespace()
+						return nil
+					}
+
+					if !policy.ForceConflicts && errors.IsConflict(err) {
+						changes[i] = *m.conflictEntry(object, policy, err).withObjectRef(existingObject)
+						return nil
+					}
+
 					// We cannot have an immutable error (and therefore shouldn't force-apply) if the resource doesn't
 					// exist on the cluster. Note that resource might not exist because we wrongly identified an error
 					// as immutable and deleted it when ApplyAll was called the last time (the check for ImmutableError
 					// returns false positives)
 					if !errors.IsNotFound(getError) && m.shouldForceApply(object, existingObject, opts, err) {
+						message = replacedMessage(err)
 						if err := m.client.Delete(ctx, existingObject, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
 							return fmt.Errorf("%s immutable field detected, failed to delete object: %w",
 								utils.FmtUnstructured(dryRunObject), err)
@@ -276,11 +432,11 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 								utils.FmtUnstructured(dryRunObject), err)
 						}
 
-						err = m.dryRunApply(ctx, dryRunObject)
+						err = m.dryRunApply(ctx, dryRunObject, policy)
 					}
 
 					if err != nil {
-						return ssaerrors.NewDryRunErr(err, dryRunObject)
+						return newDryRunErr(err, dryRunObject)
 					}
 				}
 
@@ -291,23 +447,28 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 				}
 				patched = patched || patchedCleanupMetadata
 
-				drifted, err := m.hasDriftedWithIgnore(existingObject, dryRunObject, compiled)
+				drifted, err := m.hasDriftedWithIgnore(existingObject, dryRunObject, withIgnorePaths(compiled, paths))
 				if err != nil {
 					return err
 				}
-				if patched || drifted {
+				switch {
+				case (patched || drifted) && policy.SkipDriftCorrection && dryRunObject.GetResourceVersion() != "":
+					changes[i] = *m.driftNotCorrectedEntry(dryRunObject, policy).withObjectRef(existingObject)
+				case patched || drifted:
 					toApply[i] = object
 					// Compute drifted paths while existingObject and dryRunObject are available.
 					if compiled != nil && existingObject.GetResourceVersion() != "" {
-						driftResults[i] = computeDriftedPaths(existingObject, dryRunObject, compiled, m.owner.Field)
+						driftResults[i] = computeDriftedPaths(existingObject, dryRunObject, compiled, policy.FieldManager)
 					}
 					if dryRunObject.GetResourceVersion() == "" {
 						changes[i] = *m.changeSetEntry(dryRunObject, CreatedAction)
+						changes[i].Message = message
 					} else {
 						changes[i] = *m.changeSetEntry(dryRunObject, ConfiguredAction)
+						changes[i].PreservedFields = preservedFields
 					}
-				} else {
-					changes[i] = *m.changeSetEntry(dryRunObject, UnchangedAction)
+				default:
+					changes[i] = *m.changeSetEntry(dryRunObject, UnchangedAction).withObjectRef(existingObject)
 				}
 				return nil
 			})
@@ -326,15 +487,36 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 					return nil, err
 				}
 			}
-			if err := m.apply(ctx, appliedObject); err != nil {
+			if err := m.apply(ctx, appliedObject, policies[i]); err != nil {
+				if m.isNamespaceTerminating(ctx, appliedObject, err) {
+					changes[i] = *m.namespaceTerminatingEntry(appliedObject)
+					terminating[i] = appliedObject.GetNamespace()
+					continue
+				}
+				if !policies[i].ForceConflicts && errors.IsConflict(err) {
+					changes[i] = *m.conflictEntry(appliedObject, policies[i], err)
+					continue
+				}
 				return nil, fmt.Errorf("%s apply failed: %w", utils.FmtUnstructured(appliedObject), err)
 			}
+			changes[i].withObjectRef(appliedObject)
+			changes[i].Timestamp = time.Now().UTC()
 		}
 	}
 
+	for i := range changes {
+		changes[i].ApplyPolicy = policies[i].Name
+		changes[i].Warnings = ignoreWarnings(objects[i])
+	}
 	changeSet := NewChangeSet()
 	changeSet.Append(changes)
 
+	// The objects of the other namespaces are applied, the change set is
+	// returned along with the error for the skipped objects to be reported.
+	if namespaces := slices.DeleteFunc(terminating, func(ns string) bool { return ns == "" }); len(namespaces) > 0 {
+		return changeSet, ssaerrors.NewNamespaceTerminatingErr(namespaces...)
+	}
+
 	return changeSet, nil
 }
 
@@ -344,7 +526,20 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 // and custom resources, or a mix of namespace definitions with namespaced objects.
 // If an error occurs during the apply of the cluster or class definitions, the change set is
 // returned with the applied entries, up to that point, and the error is returned.
+// With ApplyOptions.ValidateBeforeApply, the objects of all the stages are validated
+// before the first stage is applied.
 func (m *ResourceManager) ApplyAllStaged(ctx context.Context, objects []*unstructured.Unstructured, opts ApplyOptions) (*ChangeSet, error) {
+	if opts.ValidateBeforeApply {
+		// Validate the objects of all the stages before applying the first one.
+		deferred, err := m.validateAll(ctx, objects, opts)
+		if err != nil {
+			return nil, err
+		}
+		opts.ValidateBeforeApply = false
+		changeSet, err := m.ApplyAllStaged(ctx, objects, opts)
+		return changeSet.withDeferredValidation(deferred), err
+	}
+
 	changeSet := NewChangeSet()
 
 	var (
@@ -377,10 +572,12 @@ func (m *ResourceManager) ApplyAllStaged(ctx context.Context, objects []*unstruc
 	// Apply CRDs, ClusterRoles, and Namespaces first and wait for them to become ready.
 	if len(defStage) > 0 {
 		cs, err := m.ApplyAll(ctx, defStage, opts)
+		if cs != nil {
+			changeSet.Append(cs.Entries)
+		}
 		if err != nil {
 			return changeSet, err
 		}
-		changeSet.Append(cs.Entries)
 
 		if err := m.WaitForSet(cs.ToObjMetadataSet(), WaitOptions{Interval: opts.WaitInterval, Timeout: opts.WaitTimeout}); err != nil {
 			return changeSet, err
@@ -390,10 +587,12 @@ func (m *ResourceManager) ApplyAllStaged(ctx context.Context, objects []*unstruc
 	// Apply Class definitions next, if any, and wait for them to become ready.
 	if len(classStage) > 0 {
 		cs, err := m.ApplyAll(ctx, classStage, opts)
+		if cs != nil {
+			changeSet.Append(cs.Entries)
+		}
 		if err != nil {
 			return changeSet, err
 		}
-		changeSet.Append(cs.Entries)
 
 		if err := m.WaitForSet(cs.ToObjMetadataSet(), WaitOptions{Interval: opts.WaitInterval, Timeout: opts.WaitTimeout}); err != nil {
 			return changeSet, err
@@ -403,37 +602,33 @@ func (m *ResourceManager) ApplyAllStaged(ctx context.Context, objects []*unstruc
 	// Apply custom staged objects next.
 	if len(customStage) > 0 {
 		cs, err := m.ApplyAll(ctx, customStage, opts)
+		if cs != nil {
+			changeSet.Append(cs.Entries)
+		}
 		if err != nil {
 			return changeSet, err
 		}
-		changeSet.Append(cs.Entries)
 	}
 
 	// Finally, apply all the other resources.
 	cs, err := m.ApplyAll(ctx, resStage, opts)
+	if cs != nil {
+		changeSet.Append(cs.Entries)
+	}
 	if err != nil {
 		return changeSet, err
 	}
-	changeSet.Append(cs.Entries)
 
 	return changeSet, nil
 }
 
-func (m *ResourceManager) dryRunApply(ctx context.Context, object *unstructured.Unstructured) error {
-	opts := []client.PatchOption{
-		client.DryRunAll,
-		client.ForceOwnership,
-		client.FieldOwner(m.owner.Field),
-	}
+func (m *ResourceManager) dryRunApply(ctx context.Context, object *unstructured.Unstructured, policy ApplyPolicy) error {
+	opts := append([]client.PatchOption{client.DryRunAll}, policy.patchOptions()...)
 	return m.client.Patch(ctx, object, client.Apply, opts...)
 }
 
-func (m *ResourceManager) apply(ctx context.Context, object *unstructured.Unstructured) error {
-	opts := []client.PatchOption{
-		client.ForceOwnership,
-		client.FieldOwner(m.owner.Field),
-	}
-	return m.client.Patch(ctx, object, client.Apply, opts...)
+func (m *ResourceManager) apply(ctx context.Context, object *unstructured.Unstructured, policy ApplyPolicy) error {
+	return m.client.Patch(ctx, object, client.Apply, policy.patchOptions()...)
 }
 
 // migrateAPIVersion rewrites every managed fields entry on existingObject
@@ -516,6 +711,22 @@ func (m *ResourceManager) cleanupMetadata(ctx context.Context,
 	return true, m.client.Patch(ctx, object, patch, client.FieldOwner(m.owner.Field))
 }
 
+// newDryRunErr returns an ErrImmutableField if the dry-run apply failed due
+// to the change of immutable fields, otherwise a DryRunErr.
+func newDryRunErr(err error, object *unstructured.Unstructured) error {
+	if fieldPaths, ok := ssaerrors.ImmutableFieldPaths(err); ok {
+		return ssaerrors.NewImmutableFieldErr(err, object, fieldPaths)
+	}
+	return ssaerrors.NewDryRunErr(err, object)
+}
+
+// replacedMessage returns the ChangeSetEntry message of an object
+// recreated due to the given dry-run apply error.
+func replacedMessage(err error) string {
+	fieldPaths, _ := ssaerrors.ImmutableFieldPaths(err)
+	return "replaced due to " + ssaerrors.FmtImmutableFields(fieldPaths)
+}
+
 // shouldForceApply determines based on the apply error and ApplyOptions if the object should be recreated.
 // An object is recreated if the apply error was due to immutable field changes and if the object
 // contains a label or annotation which matches the ApplyOptions.ForceSelector.
@@ -549,12 +760,12 @@ func (m *ResourceManager) shouldSkipApply(desiredObject *unstructured.Unstructur
 
 	if utils.AnyInMetadata(desiredObject, opts.ExclusionSelector) ||
 		utils.AnyInMetadata(existingObject, opts.ExclusionSelector) {
-		return true, m.changeSetEntry(source, SkippedAction)
+		return true, m.changeSetEntry(source, SkippedAction).withObjectRef(existingObject)
 	}
 
 	if existingObject.GetUID() != "" &&
 		utils.AnyInMetadata(desiredObject, opts.IfNotPresentSelector) {
-		return true, m.changeSetEntry(source, SkippedAction)
+		return true, m.changeSetEntry(source, SkippedAction).withObjectRef(existingObject)
 	}
 
 	return false, nil
@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ssaerrors "github.com/fluxcd/pkg/ssa/errors"
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+func newNamespacedConfigMap(namespace string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      "test",
+			"namespace": namespace,
+		},
+		"data": map[string]any{"key": "val"},
+	}}
+}
+
+func TestApply_NamespaceTerminating(t *testing.T) {
+	g := NewWithT(t)
+	timeout := 30 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("terminating")
+	activeID := generateName("active")
+	finalizer := "test.fluxcd.io/finalizer"
+
+	// The finalizer keeps the namespace in the Terminating phase after its deletion.
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: id, Finalizers: []string{finalizer}}}
+	g.Expect(manager.client.Create(ctx, ns)).To(Succeed())
+	g.Expect(manager.client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: activeID}})).To(Succeed())
+	g.Expect(manager.client.Delete(ctx, ns)).To(Succeed())
+	g.Eventually(func() corev1.NamespacePhase {
+		_ = manager.client.Get(ctx, client.ObjectKeyFromObject(ns), ns)
+		return ns.Status.Phase
+	}, timeout, time.Second).Should(Equal(corev1.NamespaceTerminating))
+
+	terminatingObject := newNamespacedConfigMap(id)
+	activeObject := newNamespacedConfigMap(activeID)
+	wantMessage := fmt.Sprintf("skipped due to namespace '%s' being terminated", id)
+
+	t.Run("Apply skips the object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		entry, err := manager.Apply(ctx, terminatingObject, DefaultApplyOptions())
+		var nsErr *ssaerrors.ErrNamespaceTerminating
+		g.Expect(errors.As(err, &nsErr)).To(BeTrue())
+		g.Expect(nsErr.Namespaces()).To(Equal([]string{id}))
+		g.Expect(entry).NotTo(BeNil())
+		g.Expect(entry.Action).To(Equal(SkippedAction))
+		g.Expect(entry.Subject).To(Equal(utils.FmtUnstructured(terminatingObject)))
+		g.Expect(entry.Message).To(Equal(wantMessage))
+	})
+
+	t.Run("ApplyAll applies the objects of the other namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := []*unstructured.Unstructured{terminatingObject, activeObject}
+		changeSet, err := manager.ApplyAll(ctx, objects, DefaultApplyOptions())
+		var nsErr *ssaerrors.ErrNamespaceTerminating
+		g.Expect(errors.As(err, &nsErr)).To(BeTrue())
+		g.Expect(nsErr.Namespaces()).To(Equal([]string{id}))
+		g.Expect(changeSet).NotTo(BeNil())
+		g.Expect(changeSet.ToMap()).To(Equal(map[string]Action{
+			utils.FmtUnstructured(terminatingObject): SkippedAction,
+			utils.FmtUnstructured(activeObject):      CreatedAction,
+		}))
+		for _, entry := range changeSet.Entries {
+			if entry.Action == SkippedAction {
+				g.Expect(entry.Message).To(Equal(wantMessage))
+			}
+		}
+	})
+
+	t.Run("ApplyAllStaged returns the skipped objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		changeSet, err := manager.ApplyAllStaged(ctx, []*unstructured.Unstructured{terminatingObject}, DefaultApplyOptions())
+		var nsErr *ssaerrors.ErrNamespaceTerminating
+		g.Expect(errors.As(err, &nsErr)).To(BeTrue())
+		g.Expect(changeSet.ToMap()).To(Equal(map[string]Action{
+			utils.FmtUnstructured(terminatingObject): SkippedAction,
+		}))
+	})
+
+	t.Run("waits for the namespace termination", func(t *testing.T) {
+		g := NewWithT(t)
+
+		waitOpts := WaitOptions{Interval: 100 * time.Millisecond, Timeout: time.Second}
+		err := manager.WaitForNamespaceTermination([]string{id}, waitOpts)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("termination timeout"))
+
+		// Finalize the namespace, as envtest runs no namespace controller.
+		g.Expect(manager.client.Get(ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+		ns.Finalizers = nil
+		g.Expect(manager.client.Update(ctx, ns)).To(Succeed())
+		ns.Spec.Finalizers = nil
+		g.Expect(manager.client.SubResource("finalize").Update(ctx, ns)).To(Succeed())
+
+		waitOpts.Timeout = timeout
+		g.Expect(manager.WaitForNamespaceTermination([]string{id}, waitOpts)).To(Succeed())
+
+		// The namespace can be recreated.
+		g.Expect(manager.client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: id}})).To(Succeed())
+		entry, err := manager.Apply(ctx, terminatingObject, DefaultApplyOptions())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(entry.Action).To(Equal(CreatedAction))
+	})
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+)
+
+func newPolicyConfigMap(namespace, name, class string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+			"labels":    map[string]any{"class": class},
+		},
+		"data": map[string]any{"key": "desired"},
+	}}
+}
+
+func TestApplyAll_ApplyPolicies(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("policy")
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: id}}
+	if err := manager.client.Create(ctx, ns); err != nil {
+		t.Fatal(err)
+	}
+
+	corrected := ApplyPolicy{
+		Name:           "corrected",
+		Selector:       &jsondiff.Selector{LabelSelector: "class=corrected"},
+		FieldManager:   "kustomize-controller",
+		ForceConflicts: true,
+	}
+	validated := ApplyPolicy{
+		Name:         "validated",
+		Selector:     &jsondiff.Selector{LabelSelector: "class=validated"},
+		FieldManager: "kustomize-validator",
+	}
+	opts := DefaultApplyOptions()
+	opts.ApplyPolicies = []ApplyPolicy{corrected, validated}
+
+	objects := func() []*unstructured.Unstructured {
+		return []*unstructured.Unstructured{
+			newPolicyConfigMap(id, "corrected", "corrected"),
+			newPolicyConfigMap(id, "validated", "validated"),
+			newPolicyConfigMap(id, "default", "other"),
+		}
+	}
+	entries := func(changeSet *ChangeSet) map[string]ChangeSetEntry {
+		res := make(map[string]ChangeSetEntry)
+		for _, entry := range changeSet.Entries {
+			res[entry.ObjMetadata.Name] = entry
+		}
+		return res
+	}
+	getObject := func(name string) *unstructured.Unstructured {
+		t.Helper()
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		if err := manager.client.Get(ctx, client.ObjectKey{Namespace: id, Name: name}, existing); err != nil {
+			t.Fatal(err)
+		}
+		return existing
+	}
+	getValue := func(name string) string {
+		t.Helper()
+		val, _, _ := unstructured.NestedString(getObject(name).Object, "data", "key")
+		return val
+	}
+
+	t.Run("applies with the field manager of the policies", func(t *testing.T) {
+		changeSet, err := manager.ApplyAll(ctx, objects(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for name, want := range map[string]struct {
+			policy  string
+			manager string
+		}{
+			"corrected": {policy: "corrected", manager: "kustomize-controller"},
+			"validated": {policy: "validated", manager: "kustomize-validator"},
+			"default":   {policy: "", manager: manager.owner.Field},
+		} {
+			entry := entries(changeSet)[name]
+			if diff := cmp.Diff(CreatedAction, entry.Action); diff != "" {
+				t.Errorf("%s: mismatch from expected value (-want +got):\n%s", name, diff)
+			}
+			if diff := cmp.Diff(want.policy, entry.ApplyPolicy); diff != "" {
+				t.Errorf("%s: mismatch from expected value (-want +got):\n%s", name, diff)
+			}
+
+			managedFields := getObject(name).GetManagedFields()
+			if len(managedFields) != 1 || managedFields[0].Manager != want.manager {
+				t.Errorf("%s: expected the object to be managed by %s, got %v", name, want.manager, managedFields)
+			}
+		}
+	})
+
+	// Simulate a user editing the objects, taking the ownership of the field.
+	for _, name := range []string{"corrected", "validated"} {
+		patch := client.RawPatch(types.MergePatchType, []byte(`{"data":{"key":"edited"}}`))
+		if err := manager.client.Patch(ctx, getObject(name), patch, client.FieldOwner("user")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("forces the conflicts of the corrected objects only", func(t *testing.T) {
+		changeSet, err := manager.ApplyAll(ctx, objects(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := entries(changeSet)["corrected"]
+		if diff := cmp.Diff(ConfiguredAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if val := getValue("corrected"); val != "desired" {
+			t.Errorf("expected the corrected object to be restored, got %q", val)
+		}
+
+		entry = entries(changeSet)["validated"]
+		if diff := cmp.Diff(SkippedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if !strings.Contains(entry.Message, "conflicts not forced by the apply policy 'validated'") {
+			t.Errorf("expected the conflicts in the message, got %q", entry.Message)
+		}
+		if diff := cmp.Diff("validated", entry.ApplyPolicy); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if val := getValue("validated"); val != "edited" {
+			t.Errorf("expected the user edit to win, got %q", val)
+		}
+
+		entry = entries(changeSet)["default"]
+		if diff := cmp.Diff(UnchangedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("does not correct the drift of the validated objects", func(t *testing.T) {
+		opts := opts
+		validated := validated
+		validated.ForceConflicts = true
+		validated.SkipDriftCorrection = true
+		opts.ApplyPolicies = []ApplyPolicy{corrected, validated}
+
+		changeSet, err := manager.ApplyAll(ctx, objects(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := entries(changeSet)["validated"]
+		if diff := cmp.Diff(SkippedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff("drift not corrected by the apply policy 'validated'", entry.Message); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if val := getValue("validated"); val != "edited" {
+			t.Errorf("expected the drift not to be corrected, got %q", val)
+		}
+	})
+}
+
+func TestApplyPolicyFor(t *testing.T) {
+	m := &ResourceManager{owner: Owner{Field: "resource-manager"}}
+	policies, err := compileApplyPolicies([]ApplyPolicy{
+		{
+			Name:     "secrets",
+			Selector: &jsondiff.Selector{Kind: "Secret"},
+		},
+		{
+			Name:         "validated",
+			Selector:     &jsondiff.Selector{AnnotationSelector: "apply=validate"},
+			FieldManager: "validator",
+		},
+		{
+			Name:           "shadowed",
+			Selector:       &jsondiff.Selector{Kind: "Secret"},
+			FieldManager:   "shadowed",
+			ForceConflicts: true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := newPolicyConfigMap("default", "secret", "")
+	secret.SetKind("Secret")
+	annotated := newPolicyConfigMap("default", "annotated", "")
+	annotated.SetAnnotations(map[string]string{"apply": "validate"})
+
+	tests := []struct {
+		name   string
+		object *unstructured.Unstructured
+		want   ApplyPolicy
+	}{
+		{
+			name:   "first matching policy with the default field manager",
+			object: secret,
+			want: ApplyPolicy{
+				Name:         "secrets",
+				Selector:     &jsondiff.Selector{Kind: "Secret"},
+				FieldManager: "resource-manager",
+			},
+		},
+		{
+			name:   "annotation selector",
+			object: annotated,
+			want: ApplyPolicy{
+				Name:         "validated",
+				Selector:     &jsondiff.Selector{AnnotationSelector: "apply=validate"},
+				FieldManager: "validator",
+			},
+		},
+		{
+			name:   "default policy",
+			object: newPolicyConfigMap("default", "other", ""),
+			want: ApplyPolicy{
+				FieldManager:   "resource-manager",
+				ForceConflicts: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, m.applyPolicyFor(policies, tt.object)); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := compileApplyPolicies([]ApplyPolicy{{Name: "invalid", Selector: &jsondiff.Selector{Kind: "("}}}); err == nil {
+		t.Error("expected an error for an invalid selector")
+	}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTakeoverConfigMap(namespace string, labels, data map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      "takeover",
+			"namespace": namespace,
+			"labels":    labels,
+		},
+		"data": data,
+	}}
+}
+
+func TestApply_PreserveKubectlFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply applyFunc
+	}{
+		{name: "Apply", apply: applyOneViaApply},
+		{name: "ApplyAll", apply: applyOneViaApplyAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := 10 * time.Second
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			id := generateName("takeover")
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: id}}
+			if err := manager.client.Create(ctx, ns); err != nil {
+				t.Fatal(err)
+			}
+
+			// Simulate an object created with the client-side 'kubectl apply'.
+			kubectlObject := newTakeoverConfigMap(id,
+				map[string]any{"app": "demo", "team": "platform"},
+				map[string]any{"key": "val", "extra": "keep"})
+			lastApplied, err := json.Marshal(kubectlObject.Object)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kubectlObject.SetAnnotations(map[string]string{corev1.LastAppliedConfigAnnotation: string(lastApplied)})
+			if err := manager.client.Create(ctx, kubectlObject, client.FieldOwner(KubectlClientSideManager)); err != nil {
+				t.Fatal(err)
+			}
+
+			opts := DefaultApplyOptions()
+			opts.PreserveKubectlFields = true
+			opts.Cleanup = ApplyCleanupOptions{
+				Annotations: []string{corev1.LastAppliedConfigAnnotation},
+				FieldManagers: []FieldManager{
+					{
+						Name:          "kubectl",
+						OperationType: metav1.ManagedFieldsOperationUpdate,
+					},
+				},
+			}
+			object := newTakeoverConfigMap(id,
+				map[string]any{"app": "demo"},
+				map[string]any{"key": "val"})
+
+			getObject := func() *unstructured.Unstructured {
+				t.Helper()
+				existing := object.DeepCopy()
+				if err := manager.client.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+					t.Fatal(err)
+				}
+				return existing
+			}
+
+			t.Run("preserves the kubectl fields on takeover", func(t *testing.T) {
+				entry, err := tt.apply(ctx, object, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(ConfiguredAction, entry.Action); diff != "" {
+					t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+				}
+				if diff := cmp.Diff([]string{"/data/extra", "/metadata/labels/team"}, entry.PreservedFields); diff != "" {
+					t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+				}
+
+				existing := getObject()
+				if val, _, _ := unstructured.NestedString(existing.Object, "data", "extra"); val != "keep" {
+					t.Errorf("expected data.extra to be preserved, got %q", val)
+				}
+				if val := existing.GetLabels()["team"]; val != "platform" {
+					t.Errorf("expected the team label to be preserved, got %q", val)
+				}
+				if _, ok := existing.GetAnnotations()[corev1.LastAppliedConfigAnnotation]; ok {
+					t.Errorf("%s annotation not removed", corev1.LastAppliedConfigAnnotation)
+				}
+				for _, mf := range existing.GetManagedFields() {
+					if mf.Manager == KubectlClientSideManager {
+						t.Errorf("%s manager not removed", KubectlClientSideManager)
+					}
+				}
+			})
+
+			t.Run("removes the kubectl fields on the next apply", func(t *testing.T) {
+				entry, err := tt.apply(ctx, object, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(ConfiguredAction, entry.Action); diff != "" {
+					t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+				}
+				if len(entry.PreservedFields) > 0 {
+					t.Errorf("expected no preserved fields, got %v", entry.PreservedFields)
+				}
+
+				existing := getObject()
+				if _, ok, _ := unstructured.NestedString(existing.Object, "data", "extra"); ok {
+					t.Errorf("expected data.extra to be removed")
+				}
+				if _, ok := existing.GetLabels()["team"]; ok {
+					t.Errorf("expected the team label to be removed")
+				}
+			})
+		})
+	}
+}
+
+func TestPreserveKubectlFields(t *testing.T) {
+	lastApplied := `{"apiVersion":"v1","kind":"ConfigMap",` +
+		`"metadata":{"name":"test","namespace":"default","labels":{"app":"demo","team":"platform"},` +
+		`"annotations":{"a/b":"c","cleanup":"true"}},` +
+		`"data":{"key":"val","extra":"keep"},"spec":{"replicas":2,"ports":[{"port":80}]}}`
+
+	newExisting := func(managers ...metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+		existing := newTakeoverConfigMap("default", nil, nil)
+		existing.SetUID("uid")
+		existing.SetAnnotations(map[string]string{corev1.LastAppliedConfigAnnotation: lastApplied})
+		existing.SetManagedFields(managers)
+		return existing
+	}
+	kubectl := metav1.ManagedFieldsEntry{Manager: KubectlClientSideManager, Operation: metav1.ManagedFieldsOperationUpdate}
+	m := &ResourceManager{owner: Owner{Field: "resource-manager"}}
+
+	tests := []struct {
+		name          string
+		existing      *unstructured.Unstructured
+		disabled      bool
+		wantPreserved []string
+	}{
+		{
+			name:     "kubectl takeover",
+			existing: newExisting(kubectl),
+			wantPreserved: []string{
+				"/metadata/annotations/a~1b",
+				"/metadata/labels/team",
+				"/spec",
+			},
+		},
+		{
+			name:     "disabled",
+			existing: newExisting(kubectl),
+			disabled: true,
+		},
+		{
+			name:     "not managed by kubectl",
+			existing: newExisting(metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}),
+		},
+		{
+			name: "already applied",
+			existing: newExisting(kubectl,
+				metav1.ManagedFieldsEntry{Manager: m.owner.Field, Operation: metav1.ManagedFieldsOperationApply}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object := newTakeoverConfigMap("default",
+				map[string]any{"app": "demo"},
+				map[string]any{"key": "val", "extra": "changed"})
+			object.SetAnnotations(map[string]string{"owner": "flux"})
+
+			opts := DefaultApplyOptions()
+			opts.PreserveKubectlFields = !tt.disabled
+			opts.Cleanup.Annotations = []string{"cleanup"}
+			merged, preserved, err := m.preserveKubectlFields(object, tt.existing, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantPreserved, preserved); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+			if len(tt.wantPreserved) == 0 {
+				if merged != object {
+					t.Errorf("expected the object to be returned as is")
+				}
+				return
+			}
+
+			// The fields set in the object take precedence.
+			if val, _, _ := unstructured.NestedString(merged.Object, "data", "extra"); val != "changed" {
+				t.Errorf("expected data.extra to be unchanged, got %q", val)
+			}
+			if replicas, _, _ := unstructured.NestedInt64(merged.Object, "spec", "replicas"); replicas != 2 {
+				t.Errorf("expected spec.replicas to be preserved, got %d", replicas)
+			}
+			if diff := cmp.Diff(map[string]string{"a/b": "c", "owner": "flux"}, merged.GetAnnotations()); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+			if _, ok := object.GetLabels()["team"]; ok {
+				t.Errorf("expected the object not to be modified")
+			}
+		})
+	}
+}
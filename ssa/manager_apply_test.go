@@ -43,6 +43,7 @@ import (
 	ssaerrors "github.com/fluxcd/pkg/ssa/errors"
 	"github.com/fluxcd/pkg/ssa/normalize"
 	"github.com/fluxcd/pkg/ssa/utils"
+	"github.com/fluxcd/pkg/ssa/validation"
 )
 
 func TestApply(t *testing.T) {
@@ -150,6 +151,117 @@ func TestApply(t *testing.T) {
 	})
 }
 
+func TestApply_ObjectRef(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("object-ref")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.SetOwnerLabels(objects, "app1", "default")
+
+	configMapName, configMap := getFirstObject(objects, "ConfigMap", id)
+
+	// assertObjectRef verifies that the entry of the config map
+	// refers to the config map in the cluster.
+	assertObjectRef := func(t *testing.T, changeSet *ChangeSet, action Action) {
+		t.Helper()
+		existing := configMap.DeepCopy()
+		if err := manager.client.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range changeSet.Entries {
+			if entry.UID == "" {
+				t.Errorf("%s has no UID", entry.Subject)
+			}
+			if entry.Timestamp.IsZero() {
+				t.Errorf("%s has no timestamp", entry.Subject)
+			}
+			if entry.Subject != configMapName {
+				continue
+			}
+			if diff := cmp.Diff(action, entry.Action); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(existing.GetUID(), entry.UID); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(existing.GetResourceVersion(), entry.ResourceVersion); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+		}
+	}
+
+	t.Run("populates created objects", func(t *testing.T) {
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertObjectRef(t, changeSet, CreatedAction)
+	})
+
+	t.Run("populates unchanged objects", func(t *testing.T) {
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertObjectRef(t, changeSet, UnchangedAction)
+	})
+
+	t.Run("populates configured objects", func(t *testing.T) {
+		if err := unstructured.SetNestedField(configMap.Object, "object-ref", "data", "key"); err != nil {
+			t.Fatal(err)
+		}
+		entry, err := manager.Apply(ctx, configMap, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertObjectRef(t, &ChangeSet{Entries: []ChangeSetEntry{*entry}}, ConfiguredAction)
+	})
+
+	t.Run("round-trips through JSON", func(t *testing.T) {
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := changeSet.ToJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ChangeSetFromJSON(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, entry := range got.Entries {
+			want := changeSet.Entries[i]
+			if entry.UID != want.UID || entry.ResourceVersion != want.ResourceVersion || !entry.Timestamp.Equal(want.Timestamp) {
+				t.Errorf("%s does not round-trip: got %v, want %v", want.Subject, entry, want)
+			}
+		}
+	})
+
+	t.Run("captures the UID of deleted objects", func(t *testing.T) {
+		existing := configMap.DeepCopy()
+		if err := manager.client.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+			t.Fatal(err)
+		}
+		entry, err := manager.Delete(ctx, configMap, DefaultDeleteOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(DeletedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(existing.GetUID(), entry.UID); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+	})
+}
+
 func TestApplyAllStaged_PartialFailure(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
@@ -532,6 +644,89 @@ func TestApply_Force(t *testing.T) {
 	})
 }
 
+func TestApply_ImmutableFieldReport(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, tt := range []struct {
+		name  string
+		apply func(context.Context, *unstructured.Unstructured, ApplyOptions) (*ChangeSetEntry, error)
+	}{
+		{name: "Apply", apply: applyOneViaApply},
+		{name: "ApplyAll", apply: applyOneViaApplyAll},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			id := generateName("immutable")
+			objects, err := readManifest("testdata/test1.yaml", id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			manager.SetOwnerLabels(objects, "app1", "default")
+			svcName, svc := getFirstObject(objects, "Service", id)
+
+			// create objects
+			if _, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions()); err != nil {
+				t.Fatal(err)
+			}
+
+			// change the cluster IP allocated by the API server
+			svcClone := svc.DeepCopy()
+			if err := manager.client.Get(ctx, client.ObjectKeyFromObject(svcClone), svcClone); err != nil {
+				t.Fatal(err)
+			}
+			clusterIP, _, _ := unstructured.NestedString(svcClone.Object, "spec", "clusterIP")
+			newClusterIP := "10.0.0.200"
+			if clusterIP == newClusterIP {
+				newClusterIP = "10.0.0.201"
+			}
+			if err := unstructured.SetNestedField(svc.Object, newClusterIP, "spec", "clusterIP"); err != nil {
+				t.Fatal(err)
+			}
+
+			t.Run("reports immutable fields", func(t *testing.T) {
+				_, err := tt.apply(ctx, svc, DefaultApplyOptions())
+				if err == nil {
+					t.Fatal("Expected error got none")
+				}
+
+				var immutableErr *ssaerrors.ErrImmutableField
+				if !errors.As(err, &immutableErr) {
+					t.Fatalf("Expected ErrImmutableField got %T: %v", err, err)
+				}
+				if len(immutableErr.FieldPaths()) == 0 {
+					t.Errorf("Expected immutable field paths got none")
+				}
+				if !strings.HasPrefix(immutableErr.Suggestion(), svcName+" cannot be updated due to changes to immutable field") {
+					t.Errorf("Unexpected suggestion: %s", immutableErr.Suggestion())
+				}
+
+				var dryRunErr *ssaerrors.DryRunErr
+				if !errors.As(err, &dryRunErr) {
+					t.Errorf("Expected DryRunErr got %T", err)
+				}
+			})
+
+			t.Run("force apply recreates the object", func(t *testing.T) {
+				opts := DefaultApplyOptions()
+				opts.Force = true
+
+				entry, err := tt.apply(ctx, svc, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(CreatedAction, entry.Action); diff != "" {
+					t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+				}
+				if !strings.HasPrefix(entry.Message, "replaced due to immutable field") {
+					t.Errorf("Unexpected message: %s", entry.Message)
+				}
+			})
+		})
+	}
+}
+
 func TestApply_SetNativeKindsDefaults(t *testing.T) {
 	timeout := 10 * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -929,6 +1124,100 @@ func TestApply_IfNotPresent_ClusterScopedNamespaceInjection(t *testing.T) {
 	})
 }
 
+// errValidator is an ObjectValidator failing to validate any object.
+type errValidator struct{}
+
+func (errValidator) Validate(context.Context, *unstructured.Unstructured) ([]string, error) {
+	return nil, errors.New("policy engine unavailable")
+}
+
+func TestApply_Validators(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("validators")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configMapName, configMap := getFirstObject(objects, "ConfigMap", id)
+
+	validator, err := validation.NewCELValidator(validation.Rule{
+		Expression: "object.kind != 'ConfigMap' || !has(object.data) || !('key' in object.data)",
+		Message:    "config maps must not contain 'key'",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultApplyOptions()
+	opts.Validators = []ObjectValidator{validator}
+
+	t.Run("skips objects with violations", func(t *testing.T) {
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, entry := range changeSet.Entries {
+			switch entry.Subject {
+			case configMapName:
+				if entry.Action != SkippedAction {
+					t.Errorf("expected %s to be skipped, got %s", configMapName, entry.Action)
+				}
+				if want := "policy violation: config maps must not contain 'key'"; entry.Message != want {
+					t.Errorf("expected message %q, got %q", want, entry.Message)
+				}
+			default:
+				if entry.Action != CreatedAction {
+					t.Errorf("expected %s to be created, got %s", entry.Subject, entry.Action)
+				}
+			}
+		}
+
+		err = manager.client.Get(ctx, client.ObjectKeyFromObject(configMap), configMap.DeepCopy())
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("expected %s to not be applied, got %v", configMapName, err)
+		}
+	})
+
+	t.Run("applies objects without violations", func(t *testing.T) {
+		valid := configMap.DeepCopy()
+		unstructured.RemoveNestedField(valid.Object, "data", "key")
+
+		entry, err := manager.Apply(ctx, valid, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Action != CreatedAction {
+			t.Errorf("expected %s to be created, got %s", configMapName, entry.Action)
+		}
+
+		entry, err = manager.Apply(ctx, configMap, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Action != SkippedAction {
+			t.Errorf("expected %s to be skipped, got %s", configMapName, entry.Action)
+		}
+	})
+
+	t.Run("fails when validation fails", func(t *testing.T) {
+		opts := DefaultApplyOptions()
+		opts.Validators = []ObjectValidator{errValidator{}}
+
+		_, err := manager.ApplyAll(ctx, objects, opts)
+		if err == nil || !strings.Contains(err.Error(), "validation failed: policy engine unavailable") {
+			t.Errorf("expected validation error, got %v", err)
+		}
+
+		_, err = manager.Apply(ctx, configMap, opts)
+		if err == nil || !strings.Contains(err.Error(), "validation failed: policy engine unavailable") {
+			t.Errorf("expected validation error, got %v", err)
+		}
+	})
+}
+
 func TestApply_Cleanup_ExactMatch(t *testing.T) {
 	timeout := 10 * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
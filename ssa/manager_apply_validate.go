@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+
+	ssaerrors "github.com/fluxcd/pkg/ssa/errors"
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+// validateAll performs a server-side dry-run of all the given objects without
+// mutating anything in the cluster, and returns an ErrValidationFailed holding
+// the dry-run errors of all the invalid objects. See ApplyOptions.ValidateBeforeApply.
+//
+// The objects which cannot be validated before the other objects of the set
+// are applied, i.e. the custom resources of the CRDs of the set and the objects
+// of the namespaces of the set which do not exist yet, are not validated. They
+// are returned with the reason, to be reported in their ChangeSetEntry.
+func (m *ResourceManager) validateAll(ctx context.Context, objects []*unstructured.Unstructured,
+	opts ApplyOptions) (map[object.ObjMetadata]string, error) {
+	definedKinds := make(map[schema.GroupKind]struct{})
+	definedNamespaces := make(map[string]struct{})
+	for _, o := range objects {
+		switch {
+		case utils.IsCRD(o):
+			group, _, _ := unstructured.NestedString(o.Object, "spec", "group")
+			kind, _, _ := unstructured.NestedString(o.Object, "spec", "names", "kind")
+			definedKinds[schema.GroupKind{Group: group, Kind: kind}] = struct{}{}
+		case utils.IsNamespace(o):
+			definedNamespaces[o.GetName()] = struct{}{}
+		}
+	}
+
+	policies, err := compileApplyPolicies(opts.ApplyPolicies)
+	if err != nil {
+		return nil, err
+	}
+
+	// Results are written to the following arrays from the concurrent goroutines.
+	errs := make([]error, len(objects))
+	deferred := make([]string, len(objects))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(m.concurrency)
+	for i, object := range objects {
+		g.Go(func() error {
+			if _, ok := definedKinds[object.GroupVersionKind().GroupKind()]; ok {
+				deferred[i] = fmt.Sprintf("dry-run validation skipped: kind '%s' is defined by a CustomResourceDefinition of the set",
+					object.GroupVersionKind().GroupKind())
+				return nil
+			}
+
+			existingObject := &unstructured.Unstructured{}
+			existingObject.SetGroupVersionKind(object.GroupVersionKind())
+			getError := m.client.Get(ctx, client.ObjectKeyFromObject(object), existingObject)
+
+			if shouldSkip, _ := m.shouldSkipApply(object, existingObject, opts); shouldSkip {
+				return nil
+			}
+
+			dryRunObject := object.DeepCopy()
+			utils.RemoveCABundleFromCRD(dryRunObject)
+			policy := m.applyPolicyFor(policies, object)
+			err := m.dryRunApply(ctx, dryRunObject, policy)
+			_, namespaceInSet := definedNamespaces[object.GetNamespace()]
+			switch {
+			case err == nil:
+			case !policy.ForceConflicts && errors.IsConflict(err):
+				// The object is skipped by the apply.
+			case m.isNamespaceTerminating(ctx, object, err):
+				// The object is skipped by the apply.
+			case errors.IsNotFound(err) && namespaceInSet:
+				deferred[i] = fmt.Sprintf("dry-run validation skipped: namespace '%s' is defined in the set",
+					object.GetNamespace())
+			case !errors.IsNotFound(getError) && m.shouldForceApply(object, existingObject, opts, err):
+				// The object is recreated by the apply.
+			default:
+				errs[i] = newDryRunErr(err, dryRunObject)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if errs = slices.DeleteFunc(errs, func(err error) bool { return err == nil }); len(errs) > 0 {
+		return nil, ssaerrors.NewValidationFailedErr(errs...)
+	}
+
+	res := make(map[object.ObjMetadata]string)
+	for i, reason := range deferred {
+		if reason == "" {
+			continue
+		}
+		res[object.UnstructuredToObjMetadata(objects[i])] = reason
+	}
+	return res, nil
+}
+
+// withDeferredValidation sets the reason of the deferred validation of the
+// objects returned by validateAll as the message of their entries, unless
+// the entries have a message already.
+func (c *ChangeSet) withDeferredValidation(deferred map[object.ObjMetadata]string) *ChangeSet {
+	if c == nil {
+		return c
+	}
+	for i := range c.Entries {
+		if reason, ok := deferred[c.Entries[i].ObjMetadata]; ok && c.Entries[i].Message == "" {
+			c.Entries[i].Message = reason
+		}
+	}
+	return c
+}
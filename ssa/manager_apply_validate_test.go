@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ssaerrors "github.com/fluxcd/pkg/ssa/errors"
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+func TestApplyAll_ValidateBeforeApply(t *testing.T) {
+	g := NewWithT(t)
+	timeout := 30 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("validate")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	g.Expect(err).NotTo(HaveOccurred())
+	manager.SetOwnerLabels(objects, "app1", "default")
+
+	_, configMap := getFirstObject(objects, "ConfigMap", id)
+	_, secret := getFirstObject(objects, "Secret", id)
+
+	// Two objects of the set are invalid, in an existing namespace to be validated.
+	invalidConfigMap := configMap.DeepCopy()
+	invalidConfigMap.SetNamespace("default")
+	g.Expect(unstructured.SetNestedField(invalidConfigMap.Object, int64(1), "data", "key")).To(Succeed())
+	invalidSecret := secret.DeepCopy()
+	invalidSecret.SetNamespace("default")
+	invalidSecret.SetName("Invalid_Name")
+	invalidSet := []*unstructured.Unstructured{invalidSecret, invalidConfigMap}
+	for _, o := range objects {
+		if o != configMap && o != secret {
+			invalidSet = append(invalidSet, o)
+		}
+	}
+
+	opts := DefaultApplyOptions()
+	opts.ValidateBeforeApply = true
+
+	t.Run("reports all the invalid objects without applying any", func(t *testing.T) {
+		g := NewWithT(t)
+
+		changeSet, err := manager.ApplyAllStaged(ctx, invalidSet, opts)
+		g.Expect(changeSet).To(BeNil())
+
+		var validationErr *ssaerrors.ErrValidationFailed
+		g.Expect(errors.As(err, &validationErr)).To(BeTrue())
+		g.Expect(validationErr.Errors()).To(HaveLen(2))
+		g.Expect(err.Error()).To(ContainSubstring("validation failed for 2 objects"))
+		g.Expect(err.Error()).To(ContainSubstring(utils.FmtUnstructured(invalidSecret)))
+		g.Expect(err.Error()).To(ContainSubstring(utils.FmtUnstructured(invalidConfigMap)))
+
+		var dryRunErr *ssaerrors.DryRunErr
+		g.Expect(errors.As(err, &dryRunErr)).To(BeTrue())
+
+		// The valid objects, including the namespace, are not applied either.
+		for _, o := range invalidSet {
+			existing := &unstructured.Unstructured{}
+			existing.SetGroupVersionKind(o.GroupVersionKind())
+			err := manager.client.Get(ctx, client.ObjectKeyFromObject(o), existing)
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), "%s was applied", utils.FmtUnstructured(o))
+		}
+	})
+
+	t.Run("applies the set once valid", func(t *testing.T) {
+		g := NewWithT(t)
+
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, opts)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(changeSet.Entries).To(HaveLen(len(objects)))
+		for _, entry := range changeSet.Entries {
+			g.Expect(entry.Action).To(Equal(CreatedAction))
+			if entry.ObjMetadata.Namespace == id {
+				// The objects of the namespace created by the set cannot be validated beforehand.
+				g.Expect(entry.Message).To(Equal("dry-run validation skipped: namespace '" + id + "' is defined in the set"))
+			}
+		}
+	})
+
+	t.Run("defers the custom resources of the CRDs of the set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crdID := generateName("validate-crd")
+		crdObjects, err := readManifest("testdata/test5.yaml", crdID)
+		g.Expect(err).NotTo(HaveOccurred())
+		manager.SetOwnerLabels(crdObjects, "app1", "default")
+		crdName, _ := getFirstObject(crdObjects, "ClusterTest", crdID)
+
+		changeSet, err := manager.ApplyAllStaged(ctx, crdObjects, opts)
+		g.Expect(err).NotTo(HaveOccurred())
+		var messages []string
+		for _, entry := range changeSet.Entries {
+			if entry.Subject == crdName {
+				messages = append(messages, entry.Message)
+			}
+		}
+		g.Expect(messages).To(ConsistOf(
+			"dry-run validation skipped: kind 'ClusterTest.testing.fluxcd.io' is defined by a CustomResourceDefinition of the set"))
+	})
+}
@@ -76,24 +76,26 @@ func (m *ResourceManager) Delete(ctx context.Context, object *unstructured.Unstr
 
 	sel, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: opts.Inclusions})
 	if err != nil {
-		return m.changeSetEntry(object, UnknownAction),
+		return m.changeSetEntry(object, UnknownAction).withObjectRef(existingObject),
 			fmt.Errorf("%s label selector failed: %w", utils.FmtUnstructured(object), err)
 	}
 
 	if !sel.Matches(labels.Set(existingObject.GetLabels())) {
-		return m.changeSetEntry(object, SkippedAction), nil
+		return m.changeSetEntry(object, SkippedAction).withObjectRef(existingObject), nil
 	}
 
 	if utils.AnyInMetadata(existingObject, opts.Exclusions) {
-		return m.changeSetEntry(object, SkippedAction), nil
+		return m.changeSetEntry(object, SkippedAction).withObjectRef(existingObject), nil
 	}
 
 	if err := m.client.Delete(ctx, existingObject, client.PropagationPolicy(opts.PropagationPolicy)); err != nil {
-		return m.changeSetEntry(object, UnknownAction),
+		return m.changeSetEntry(object, UnknownAction).withObjectRef(existingObject),
 			fmt.Errorf("%s delete failed: %w", utils.FmtUnstructured(object), err)
 	}
 
-	return m.changeSetEntry(object, DeletedAction), nil
+	// The UID is captured before the deletion for the entry to be
+	// correlated with the deleted object.
+	return m.changeSetEntry(object, DeletedAction).withObjectRef(existingObject), nil
 }
 
 // DeleteAll deletes the given set of objects (not found errors are ignored).
@@ -77,20 +77,26 @@ func (m *ResourceManager) Diff(ctx context.Context, object *unstructured.Unstruc
 		return m.changeSetEntry(existingObject, SkippedAction), nil, nil, nil
 	}
 
+	paths, warnings := ignorePaths(object)
+	object, err := withoutIgnoredPaths(object, existingObject, paths, m.owner.Field)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	dryRunObject := object.DeepCopy()
-	if err := m.dryRunApply(ctx, dryRunObject); err != nil {
+	if err := m.dryRunApply(ctx, dryRunObject, m.defaultApplyPolicy()); err != nil {
 		if m.shouldForceApply(object, existingObject, ApplyOptions{
 			Force:         opts.Force,
 			ForceSelector: opts.ForceSelector,
 		}, err) {
-			return m.changeSetEntry(object, CreatedAction), nil, nil, nil
+			return m.changeSetEntry(object, CreatedAction).withWarnings(warnings), nil, nil, nil
 		}
 
 		return nil, nil, nil, errors.NewDryRunErr(err, dryRunObject)
 	}
 
 	if dryRunObject.GetResourceVersion() == "" {
-		return m.changeSetEntry(dryRunObject, CreatedAction), nil, nil, nil
+		return m.changeSetEntry(dryRunObject, CreatedAction).withWarnings(warnings), nil, nil, nil
 	}
 
 	// Compile ignore rules once for drift detection.
@@ -102,6 +108,7 @@ func (m *ResourceManager) Diff(ctx context.Context, object *unstructured.Unstruc
 			return nil, nil, nil, err
 		}
 	}
+	compiled = withIgnorePaths(compiled, paths)
 
 	drifted, err := m.hasDriftedWithIgnore(existingObject, dryRunObject, compiled)
 	if err != nil {
@@ -109,7 +116,7 @@ func (m *ResourceManager) Diff(ctx context.Context, object *unstructured.Unstruc
 	}
 
 	if drifted {
-		cse := m.changeSetEntry(object, ConfiguredAction)
+		cse := m.changeSetEntry(object, ConfiguredAction).withWarnings(warnings)
 
 		unstructured.RemoveNestedField(dryRunObject.Object, "metadata", "managedFields")
 		unstructured.RemoveNestedField(existingObject.Object, "metadata", "managedFields")
@@ -134,7 +141,7 @@ func (m *ResourceManager) Diff(ctx context.Context, object *unstructured.Unstruc
 		return cse, existingObject, dryRunObject, nil
 	}
 
-	return m.changeSetEntry(dryRunObject, UnchangedAction), nil, nil, nil
+	return m.changeSetEntry(dryRunObject, UnchangedAction).withWarnings(warnings), nil, nil, nil
 }
 
 // hasDrifted detects changes to metadata labels, annotations and spec.
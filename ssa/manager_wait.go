@@ -57,6 +57,13 @@ type WaitOptions struct {
 	// NotFound status for these Jobs is treated as successful completion since they
 	// are deleted by the TTL controller after finishing.
 	JobsWithTTL object.ObjMetadataSet
+
+	// DisableJobHealthChecks makes the Wait function use the kstatus
+	// semantics for Jobs and CronJobs, where a Job is Current once started
+	// and a CronJob is always Current. By default, a Job is Current only
+	// after completing and Failed after failing, while a CronJob is Current
+	// when its schedule is valid and its last scheduled run succeeded.
+	DisableJobHealthChecks bool
 }
 
 // DefaultWaitOptions returns the default wait options where the poll interval is set to
@@ -113,6 +120,9 @@ func (m *ResourceManager) WaitForSetWithContext(ctx context.Context, set object.
 				if rs == nil {
 					continue
 				}
+				if !opts.DisableJobHealthChecks {
+					rs = assessJobHealth(rs)
+				}
 				lastStatus[rs.Identifier] = rs
 
 				// Treat NotFound Jobs with TTL as Current for aggregation purposes
@@ -182,6 +192,17 @@ func (m *ResourceManager) WaitForSetWithContext(ctx context.Context, set object.
 			if rs.Error != nil {
 				fmt.Fprintf(&builder, ": %s", rs.Error)
 			}
+			if last := lastStatus[id]; !opts.DisableJobHealthChecks && last.Status == status.FailedStatus {
+				switch id.GroupKind {
+				case jobGroupKind:
+					fmt.Fprintf(&builder, ": %s", last.Message)
+					if failure := m.jobPodFailure(ctx, last.Resource); failure != "" {
+						fmt.Fprintf(&builder, ": %s", failure)
+					}
+				case cronJobGroupKind:
+					fmt.Fprintf(&builder, ": %s", last.Message)
+				}
+			}
 			errs = append(errs, builder.String())
 		}
 	}
@@ -253,6 +274,20 @@ func (m *ResourceManager) WaitForTermination(objects []*unstructured.Unstructure
 	return nil
 }
 
+// WaitForNamespaceTermination waits for the given namespaces to be deleted
+// from the cluster, e.g. to apply again the objects skipped with an
+// ssaerrors.ErrNamespaceTerminating error when the namespace is recreated.
+func (m *ResourceManager) WaitForNamespaceTermination(namespaces []string, opts WaitOptions) error {
+	objects := make([]*unstructured.Unstructured, 0, len(namespaces))
+	for _, name := range namespaces {
+		namespace := &unstructured.Unstructured{}
+		namespace.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"})
+		namespace.SetName(name)
+		objects = append(objects, namespace)
+	}
+	return m.WaitForTermination(objects, opts)
+}
+
 func (m *ResourceManager) isDeleted(object *unstructured.Unstructured) wait.ConditionWithContextFunc {
 	return func(ctx context.Context) (bool, error) {
 		obj := object.DeepCopy()
@@ -0,0 +1,309 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/event"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
+)
+
+var (
+	jobGroupKind     = schema.GroupKind{Group: "batch", Kind: "Job"}
+	cronJobGroupKind = schema.GroupKind{Group: "batch", Kind: "CronJob"}
+)
+
+// jobPodsTimeout bounds the lookup of the pods of a failed Job.
+const jobPodsTimeout = 5 * time.Second
+
+// assessJobHealth returns the status of the Jobs and CronJobs computed with
+// the Flux semantics, as opposed to kstatus which considers a Job Current
+// once started and a CronJob Current as soon as it exists. The statuses
+// other than InProgress, Current and Failed, e.g. the NotFound status of
+// the Jobs deleted by the TTL controller, are returned as is.
+func assessJobHealth(rs *event.ResourceStatus) *event.ResourceStatus {
+	if rs.Resource == nil {
+		return rs
+	}
+	switch rs.Status {
+	case status.CurrentStatus, status.InProgressStatus, status.FailedStatus:
+	default:
+		return rs
+	}
+
+	var s status.Status
+	var message string
+	switch rs.Identifier.GroupKind {
+	case jobGroupKind:
+		s, message = jobStatus(rs.Resource)
+	case cronJobGroupKind:
+		s, message = cronJobStatus(rs.Resource)
+	default:
+		return rs
+	}
+
+	assessed := *rs
+	assessed.Status = s
+	assessed.Message = message
+	return &assessed
+}
+
+// jobStatus returns the status of the Job: Current when completed or
+// suspended, Failed when failed, e.g. after reaching its backoffLimit,
+// and InProgress otherwise.
+func jobStatus(u *unstructured.Unstructured) (status.Status, string) {
+	obj := u.UnstructuredContent()
+	if suspended, _, _ := unstructured.NestedBool(obj, "spec", "suspend"); suspended {
+		return status.CurrentStatus, "Job is suspended"
+	}
+
+	parallelism := status.GetIntField(obj, ".spec.parallelism", 1)
+	completions := status.GetIntField(obj, ".spec.completions", parallelism)
+	active := status.GetIntField(obj, ".status.active", 0)
+	succeeded := status.GetIntField(obj, ".status.succeeded", 0)
+	failed := status.GetIntField(obj, ".status.failed", 0)
+
+	objc, err := status.GetObjectWithConditions(obj)
+	if err != nil {
+		return status.UnknownStatus, fmt.Sprintf("failed to read the Job conditions: %s", err)
+	}
+	for _, c := range objc.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case "Complete":
+			return status.CurrentStatus, fmt.Sprintf("Job completed, succeeded: %d/%d", succeeded, completions)
+		case "Failed", "FailureTarget":
+			// The FailureTarget condition is set before the Failed one,
+			// while the remaining pods are terminated.
+			message := fmt.Sprintf("Job failed, failed: %d", failed)
+			if c.Reason != "" {
+				message += fmt.Sprintf(", reason: %s", c.Reason)
+			}
+			if c.Message != "" {
+				message += fmt.Sprintf(", message: %s", c.Message)
+			}
+			return status.FailedStatus, message
+		}
+	}
+
+	if status.GetStringField(obj, ".status.startTime", "") == "" {
+		return status.InProgressStatus, "Job not started"
+	}
+	return status.InProgressStatus, fmt.Sprintf("Job in progress, active: %d, succeeded: %d, failed: %d",
+		active, succeeded, failed)
+}
+
+// cronJobStatus returns the status of the CronJob: Failed when its schedule
+// is invalid or its last scheduled run did not succeed, InProgress while the
+// last scheduled run is active, and Current otherwise.
+func cronJobStatus(u *unstructured.Unstructured) (status.Status, string) {
+	obj := u.UnstructuredContent()
+	schedule, _, _ := unstructured.NestedString(obj, "spec", "schedule")
+	if err := validateCronSchedule(schedule); err != nil {
+		return status.FailedStatus, fmt.Sprintf("CronJob has an invalid schedule '%s': %s", schedule, err)
+	}
+	if suspended, _, _ := unstructured.NestedBool(obj, "spec", "suspend"); suspended {
+		return status.CurrentStatus, "CronJob is suspended"
+	}
+
+	lastSchedule := status.GetStringField(obj, ".status.lastScheduleTime", "")
+	if lastSchedule == "" {
+		return status.CurrentStatus, "CronJob has not run yet"
+	}
+	scheduled, err := time.Parse(time.RFC3339, lastSchedule)
+	if err != nil {
+		return status.UnknownStatus, fmt.Sprintf("invalid CronJob last schedule time '%s': %s", lastSchedule, err)
+	}
+	if lastSuccessful := status.GetStringField(obj, ".status.lastSuccessfulTime", ""); lastSuccessful != "" {
+		succeeded, err := time.Parse(time.RFC3339, lastSuccessful)
+		if err != nil {
+			return status.UnknownStatus, fmt.Sprintf("invalid CronJob last successful time '%s': %s", lastSuccessful, err)
+		}
+		if !succeeded.Before(scheduled) {
+			return status.CurrentStatus, fmt.Sprintf("CronJob run scheduled at %s succeeded", lastSchedule)
+		}
+	}
+	if active, _, _ := unstructured.NestedSlice(obj, "status", "active"); len(active) > 0 {
+		return status.InProgressStatus, fmt.Sprintf("CronJob run scheduled at %s is active", lastSchedule)
+	}
+	return status.FailedStatus, fmt.Sprintf("CronJob run scheduled at %s did not succeed", lastSchedule)
+}
+
+// jobPodFailure returns the termination of the failed containers of the
+// pods of the failed Job, if any, e.g. "pod 'job-x' container 'main'
+// terminated with exit code 1 (Error)". The lookup is best effort, the
+// errors being ignored.
+func (m *ResourceManager) jobPodFailure(ctx context.Context, job *unstructured.Unstructured) string {
+	if job == nil || job.GetUID() == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jobPodsTimeout)
+	defer cancel()
+
+	pods := &corev1.PodList{}
+	if err := m.client.List(ctx, pods, client.InNamespace(job.GetNamespace()),
+		client.MatchingLabels{"batch.kubernetes.io/controller-uid": string(job.GetUID())}); err != nil {
+		return ""
+	}
+
+	var failures []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			t := cs.State.Terminated
+			if t == nil || t.ExitCode == 0 {
+				continue
+			}
+			failure := fmt.Sprintf("pod '%s' container '%s' terminated with exit code %d", pod.Name, cs.Name, t.ExitCode)
+			if t.Reason != "" {
+				failure += fmt.Sprintf(" (%s)", t.Reason)
+			}
+			if msg := strings.TrimSpace(t.Message); msg != "" {
+				failure += ": " + msg
+			}
+			failures = append(failures, failure)
+		}
+		if len(pod.Status.ContainerStatuses) == 0 && pod.Status.Message != "" {
+			failures = append(failures, fmt.Sprintf("pod '%s' failed: %s", pod.Name, pod.Status.Message))
+		}
+	}
+	return strings.Join(failures, ", ")
+}
+
+// cronDescriptors are the predefined schedules of the CronJobs.
+var cronDescriptors = map[string]bool{
+	"@yearly":   true,
+	"@annually": true,
+	"@monthly":  true,
+	"@weekly":   true,
+	"@daily":    true,
+	"@midnight": true,
+	"@hourly":   true,
+}
+
+// cronField is a field of a standard cron schedule.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// validateCronSchedule validates the schedule of a CronJob, which is
+// either a standard cron expression of five fields or a descriptor, e.g.
+// '@hourly' or '@every 1h30m'.
+func validateCronSchedule(schedule string) error {
+	fields := strings.Fields(schedule)
+	switch {
+	case len(fields) == 0:
+		return errors.New("empty schedule")
+	case strings.HasPrefix(fields[0], "TZ=") || strings.HasPrefix(fields[0], "CRON_TZ="):
+		return errors.New("the time zone must be set with spec.timeZone")
+	case fields[0] == "@every":
+		if len(fields) != 2 {
+			return errors.New("@every requires a duration")
+		}
+		if d, err := time.ParseDuration(fields[1]); err != nil || d <= 0 {
+			return fmt.Errorf("invalid @every duration '%s'", fields[1])
+		}
+		return nil
+	case strings.HasPrefix(fields[0], "@"):
+		if len(fields) != 1 || !cronDescriptors[fields[0]] {
+			return fmt.Errorf("unknown descriptor '%s'", fields[0])
+		}
+		return nil
+	case len(fields) != len(cronFields):
+		return fmt.Errorf("expected %d fields, found %d", len(cronFields), len(fields))
+	}
+	for i, field := range cronFields {
+		if err := field.validate(fields[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate validates the comma-separated list of values, ranges and steps
+// of the field.
+func (f cronField) validate(expr string) error {
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return fmt.Errorf("invalid step '%s' in %s field", step, f.name)
+			}
+		}
+		if rangeExpr == "*" || rangeExpr == "?" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(rangeExpr, "-")
+		start, err := f.value(lo)
+		if err != nil {
+			return err
+		}
+		if isRange {
+			end, err := f.value(hi)
+			if err != nil {
+				return err
+			}
+			if end < start {
+				return fmt.Errorf("invalid range '%s' in %s field", rangeExpr, f.name)
+			}
+		}
+	}
+	return nil
+}
+
+// value returns the number or name of the field as a number within
+// its bounds.
+func (f cronField) value(s string) (int, error) {
+	if n, ok := f.names[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid value '%s' in %s field, must be within [%d-%d]", s, f.name, f.min, f.max)
+	}
+	return n, nil
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	"github.com/fluxcd/cli-utils/pkg/object"
+)
+
+func TestJobStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		spec    map[string]any
+		status  map[string]any
+		want    status.Status
+		message string
+	}{
+		{
+			name:    "not started",
+			want:    status.InProgressStatus,
+			message: "Job not started",
+		},
+		{
+			name: "active",
+			status: map[string]any{
+				"startTime": "2026-01-01T00:00:00Z",
+				"active":    int64(1),
+			},
+			want:    status.InProgressStatus,
+			message: "Job in progress, active: 1, succeeded: 0, failed: 0",
+		},
+		{
+			name: "complete",
+			spec: map[string]any{"completions": int64(2)},
+			status: map[string]any{
+				"startTime": "2026-01-01T00:00:00Z",
+				"succeeded": int64(2),
+				"conditions": []any{
+					map[string]any{"type": "SuccessCriteriaMet", "status": "True"},
+					map[string]any{"type": "Complete", "status": "True"},
+				},
+			},
+			want:    status.CurrentStatus,
+			message: "Job completed, succeeded: 2/2",
+		},
+		{
+			name: "failed",
+			status: map[string]any{
+				"startTime": "2026-01-01T00:00:00Z",
+				"failed":    int64(3),
+				"conditions": []any{
+					map[string]any{
+						"type":    "Failed",
+						"status":  "True",
+						"reason":  "BackoffLimitExceeded",
+						"message": "Job has reached the specified backoff limit",
+					},
+				},
+			},
+			want:    status.FailedStatus,
+			message: "Job failed, failed: 3, reason: BackoffLimitExceeded, message: Job has reached the specified backoff limit",
+		},
+		{
+			name: "failure target",
+			status: map[string]any{
+				"startTime": "2026-01-01T00:00:00Z",
+				"active":    int64(1),
+				"failed":    int64(1),
+				"conditions": []any{
+					map[string]any{"type": "FailureTarget", "status": "True", "reason": "DeadlineExceeded"},
+				},
+			},
+			want:    status.FailedStatus,
+			message: "Job failed, failed: 1, reason: DeadlineExceeded",
+		},
+		{
+			name: "condition not true",
+			status: map[string]any{
+				"startTime": "2026-01-01T00:00:00Z",
+				"active":    int64(1),
+				"conditions": []any{
+					map[string]any{"type": "Failed", "status": "False"},
+				},
+			},
+			want:    status.InProgressStatus,
+			message: "Job in progress, active: 1, succeeded: 0, failed: 0",
+		},
+		{
+			name:    "suspended",
+			spec:    map[string]any{"suspend": true},
+			want:    status.CurrentStatus,
+			message: "Job is suspended",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, message := jobStatus(batchObject("Job", tt.spec, tt.status))
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(message).To(Equal(tt.message))
+		})
+	}
+}
+
+func TestCronJobStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		spec    map[string]any
+		status  map[string]any
+		want    status.Status
+		message string
+	}{
+		{
+			name:    "never scheduled",
+			want:    status.CurrentStatus,
+			message: "CronJob has not run yet",
+		},
+		{
+			name: "last run succeeded",
+			status: map[string]any{
+				"lastScheduleTime":   "2026-01-01T00:00:00Z",
+				"lastSuccessfulTime": "2026-01-01T00:00:10Z",
+			},
+			want:    status.CurrentStatus,
+			message: "CronJob run scheduled at 2026-01-01T00:00:00Z succeeded",
+		},
+		{
+			name: "last run active",
+			status: map[string]any{
+				"lastScheduleTime":   "2026-01-01T01:00:00Z",
+				"lastSuccessfulTime": "2026-01-01T00:00:10Z",
+				"active":             []any{map[string]any{"name": "job"}},
+			},
+			want:    status.InProgressStatus,
+			message: "CronJob run scheduled at 2026-01-01T01:00:00Z is active",
+		},
+		{
+			name: "last run failed",
+			status: map[string]any{
+				"lastScheduleTime":   "2026-01-01T01:00:00Z",
+				"lastSuccessfulTime": "2026-01-01T00:00:10Z",
+			},
+			want:    status.FailedStatus,
+			message: "CronJob run scheduled at 2026-01-01T01:00:00Z did not succeed",
+		},
+		{
+			name: "suspended",
+			spec: map[string]any{"schedule": "@hourly", "suspend": true},
+			status: map[string]any{
+				"lastScheduleTime": "2026-01-01T01:00:00Z",
+			},
+			want:    status.CurrentStatus,
+			message: "CronJob is suspended",
+		},
+		{
+			name:    "invalid schedule",
+			spec:    map[string]any{"schedule": "0 25 * * *"},
+			want:    status.FailedStatus,
+			message: "CronJob has an invalid schedule '0 25 * * *': invalid value '25' in hour field, must be within [0-23]",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			spec := tt.spec
+			if spec == nil {
+				spec = map[string]any{"schedule": "*/5 * * * *"}
+			}
+			got, message := cronJobStatus(batchObject("CronJob", spec, tt.status))
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(message).To(Equal(tt.message))
+		})
+	}
+}
+
+func TestValidateCronSchedule(t *testing.T) {
+	for _, tt := range []struct {
+		schedule string
+		err      string
+	}{
+		{schedule: "* * * * *"},
+		{schedule: "*/15 0-6,18-23 1,15 jan-jun mon-fri"},
+		{schedule: "0 0 ? * SUN"},
+		{schedule: "@daily"},
+		{schedule: "@every 1h30m"},
+		{schedule: "", err: "empty schedule"},
+		{schedule: "* * * *", err: "expected 5 fields, found 4"},
+		{schedule: "60 * * * *", err: "invalid value '60' in minute field, must be within [0-59]"},
+		{schedule: "* * 0 * *", err: "invalid value '0' in day of month field, must be within [1-31]"},
+		{schedule: "* * * foo *", err: "invalid value 'foo' in month field, must be within [1-12]"},
+		{schedule: "*/0 * * * *", err: "invalid step '0' in minute field"},
+		{schedule: "* 10-5 * * *", err: "invalid range '10-5' in hour field"},
+		{schedule: "@often", err: "unknown descriptor '@often'"},
+		{schedule: "@every forever", err: "invalid @every duration 'forever'"},
+		{schedule: "TZ=UTC 0 0 * * *", err: "the time zone must be set with spec.timeZone"},
+	} {
+		t.Run(tt.schedule, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := validateCronSchedule(tt.schedule)
+			if tt.err != "" {
+				g.Expect(err).To(MatchError(tt.err))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestWaitForSet_JobHealth(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		conditions []any
+		counts     map[string]any
+		disabled   bool
+		err        string
+	}{
+		{
+			name: "complete Job is Current",
+			conditions: []any{
+				jobCondition("SuccessCriteriaMet", "CompletionsReached", "Reached expected number of succeeded pods"),
+				jobCondition("Complete", "CompletionsReached", "Reached expected number of succeeded pods"),
+			},
+			counts: map[string]any{"succeeded": int64(1)},
+		},
+		{
+			name: "failed Job is Failed",
+			conditions: []any{
+				jobCondition("FailureTarget", "BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+				jobCondition("Failed", "BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+			},
+			counts: map[string]any{"failed": int64(1)},
+			err:    "status: 'Failed': Job failed, failed: 1, reason: BackoffLimitExceeded, message: Job has reached the specified backoff limit",
+		},
+		{
+			name:     "active Job is Current without health checks",
+			disabled: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			job := &unstructured.Unstructured{
+				Object: map[string]any{
+					"apiVersion": "batch/v1",
+					"kind":       "Job",
+					"metadata": map[string]any{
+						"name":      generateName("job-health"),
+						"namespace": "default",
+					},
+					"spec": map[string]any{
+						"backoffLimit": int64(0),
+						"template": map[string]any{
+							"spec": map[string]any{
+								"restartPolicy": "Never",
+								"containers": []any{
+									map[string]any{
+										"name":    "test",
+										"image":   "busybox",
+										"command": []any{"false"},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			_, err := manager.ApplyAll(ctx, []*unstructured.Unstructured{job}, DefaultApplyOptions())
+			g.Expect(err).NotTo(HaveOccurred())
+			jobObjMeta := object.UnstructuredToObjMetadata(job)
+			t.Cleanup(func() {
+				_ = manager.client.Delete(ctx, job)
+			})
+
+			// A started Job is Current for kstatus but InProgress until
+			// it reaches a terminal state.
+			g.Expect(manager.client.Get(ctx, client.ObjectKeyFromObject(job), job)).To(Succeed())
+			startTime := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+			g.Expect(unstructured.SetNestedField(job.Object, startTime, "status", "startTime")).To(Succeed())
+			g.Expect(unstructured.SetNestedField(job.Object, int64(1), "status", "active")).To(Succeed())
+			g.Expect(manager.client.Status().Update(ctx, job)).To(Succeed())
+
+			if !tt.disabled {
+				err = manager.WaitForSet([]object.ObjMetadata{jobObjMeta}, WaitOptions{
+					Interval: 100 * time.Millisecond,
+					Timeout:  time.Second,
+				})
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("timeout waiting for"))
+				g.Expect(err.Error()).To(ContainSubstring("Job in progress, active: 1"))
+
+				// Move the Job to its terminal state.
+				g.Expect(manager.client.Get(ctx, client.ObjectKeyFromObject(job), job)).To(Succeed())
+				now := time.Now().UTC().Format(time.RFC3339)
+				for i := range tt.conditions {
+					c := tt.conditions[i].(map[string]any)
+					c["lastProbeTime"] = now
+					c["lastTransitionTime"] = now
+				}
+				g.Expect(unstructured.SetNestedSlice(job.Object, tt.conditions, "status", "conditions")).To(Succeed())
+				unstructured.RemoveNestedField(job.Object, "status", "active")
+				for k, v := range tt.counts {
+					g.Expect(unstructured.SetNestedField(job.Object, v, "status", k)).To(Succeed())
+				}
+				if tt.err == "" {
+					g.Expect(unstructured.SetNestedField(job.Object, now, "status", "completionTime")).To(Succeed())
+				}
+				g.Expect(manager.client.Status().Update(ctx, job)).To(Succeed())
+			}
+
+			start := time.Now()
+			err = manager.WaitForSet([]object.ObjMetadata{jobObjMeta}, WaitOptions{
+				Interval:               100 * time.Millisecond,
+				Timeout:                5 * time.Second,
+				DisableJobHealthChecks: tt.disabled,
+			})
+			if tt.err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("failed early due to stalled resources"))
+				g.Expect(err.Error()).To(ContainSubstring(tt.err))
+				g.Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+// batchObject returns an object of the batch group with the given spec and status.
+func batchObject(kind string, spec, st map[string]any) *unstructured.Unstructured {
+	obj := map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name":      "test",
+			"namespace": "default",
+		},
+	}
+	if spec != nil {
+		obj["spec"] = spec
+	}
+	if st != nil {
+		obj["status"] = st
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+// jobCondition returns a true condition of a Job.
+func jobCondition(conditionType, reason, message string) map[string]any {
+	return map[string]any{
+		"type":    conditionType,
+		"status":  "True",
+		"reason":  reason,
+		"message": message,
+	}
+}
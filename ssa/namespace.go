@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ssaerrors "github.com/fluxcd/pkg/ssa/errors"
+)
+
+// isNamespaceTerminating returns true if the apply of the given object failed
+// because its namespace is being terminated. Besides the cause returned by
+// the Kubernetes API, the phase of the namespace is checked when the apply
+// is forbidden, e.g. by an admission webhook.
+func (m *ResourceManager) isNamespaceTerminating(ctx context.Context, object *unstructured.Unstructured, err error) bool {
+	if ssaerrors.IsNamespaceTerminatingError(err) {
+		return true
+	}
+	if object.GetNamespace() == "" || !errors.IsForbidden(err) {
+		return false
+	}
+
+	namespace := &unstructured.Unstructured{}
+	namespace.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	if err := m.client.Get(ctx, client.ObjectKey{Name: object.GetNamespace()}, namespace); err != nil {
+		return false
+	}
+	phase, _, _ := unstructured.NestedString(namespace.Object, "status", "phase")
+	return phase == string(corev1.NamespaceTerminating)
+}
+
+// namespaceTerminatingEntry returns the ChangeSetEntry of an object skipped
+// because its namespace is being terminated.
+func (m *ResourceManager) namespaceTerminatingEntry(object *unstructured.Unstructured) *ChangeSetEntry {
+	entry := m.changeSetEntry(object, SkippedAction)
+	entry.Message = fmt.Sprintf("skipped due to namespace '%s' being terminated", object.GetNamespace())
+	return entry
+}
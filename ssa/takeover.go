@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// KubectlClientSideManager is the field manager of the objects
+// created or updated with the client-side 'kubectl apply'.
+const KubectlClientSideManager = "kubectl-client-side-apply"
+
+// isKubectlTakeover returns true if the in-cluster object is managed by the
+// client-side kubectl manager, holds the last applied configuration of
+// kubectl, and has never been applied by the given field manager.
+func isKubectlTakeover(existingObject *unstructured.Unstructured, fieldManager string) bool {
+	if existingObject.GetAnnotations()[corev1.LastAppliedConfigAnnotation] == "" {
+		return false
+	}
+	kubectl := false
+	for _, mf := range existingObject.GetManagedFields() {
+		if mf.Manager == fieldManager {
+			return false
+		}
+		if mf.Manager == KubectlClientSideManager && mf.Operation == metav1.ManagedFieldsOperationUpdate {
+			kubectl = true
+		}
+	}
+	return kubectl
+}
+
+// preserveKubectlFields returns a copy of the desired object with the fields
+// of the kubectl last applied configuration of the existing object which are
+// absent from the desired object, along with the JSON pointers of the
+// preserved fields. The desired object is returned as is when the apply is
+// not a takeover of an object created with the client-side kubectl apply.
+// See ApplyOptions.PreserveKubectlFields.
+func (m *ResourceManager) preserveKubectlFields(object, existingObject *unstructured.Unstructured,
+	opts ApplyOptions) (*unstructured.Unstructured, []string, error) {
+	if !opts.PreserveKubectlFields || existingObject.GetUID() == "" ||
+		!isKubectlTakeover(existingObject, m.owner.Field) {
+		return object, nil, nil
+	}
+
+	lastApplied := map[string]any{}
+	if err := json.Unmarshal([]byte(existingObject.GetAnnotations()[corev1.LastAppliedConfigAnnotation]), &lastApplied); err != nil {
+		return nil, nil, fmt.Errorf("%s annotation is invalid: %w", corev1.LastAppliedConfigAnnotation, err)
+	}
+
+	// Only the user-managed fields are preserved, the identity and the
+	// status of the object are left out, as well as the metadata entries
+	// removed by the cleanup.
+	delete(lastApplied, "apiVersion")
+	delete(lastApplied, "kind")
+	delete(lastApplied, "status")
+	if metadata, ok := lastApplied["metadata"].(map[string]any); ok {
+		for k := range metadata {
+			if k != "labels" && k != "annotations" {
+				delete(metadata, k)
+			}
+		}
+		excluded := map[string][]string{
+			"annotations": append([]string{corev1.LastAppliedConfigAnnotation}, opts.Cleanup.Annotations...),
+			"labels":      opts.Cleanup.Labels,
+		}
+		for field, keys := range excluded {
+			if entries, ok := metadata[field].(map[string]any); ok {
+				for _, k := range keys {
+					delete(entries, k)
+				}
+			}
+		}
+	}
+
+	merged := object.DeepCopy()
+	var preserved []string
+	mergeMissingFields(merged.Object, lastApplied, "", &preserved)
+	sort.Strings(preserved)
+	return merged, preserved, nil
+}
+
+// mergeMissingFields sets the fields of src which are absent from dst, and
+// records their JSON pointers. Maps are merged recursively, while the other
+// values, including lists, are only set when absent from dst.
+func mergeMissingFields(dst, src map[string]any, path string, preserved *[]string) {
+	keys := make([]string, 0, len(src))
+	for k := range src {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fieldPath := path + "/" + escapeJSONPointer(k)
+		dstValue, ok := dst[k]
+		if !ok {
+			dst[k] = src[k]
+			*preserved = append(*preserved, fieldPath)
+			continue
+		}
+		dstMap, dstOk := dstValue.(map[string]any)
+		srcMap, srcOk := src[k].(map[string]any)
+		if dstOk && srcOk {
+			mergeMissingFields(dstMap, srcMap, fieldPath, preserved)
+		}
+	}
+}
+
+// escapeJSONPointer escapes the given key as a JSON pointer segment.
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation provides object validators to enforce
+// policies on the objects applied by the ssa.ResourceManager.
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ObjectVariable is the name of the CEL variable holding the validated object.
+const ObjectVariable = "object"
+
+// Rule is a CEL validation rule.
+type Rule struct {
+	// Expression is a CEL expression which evaluates to true if the object
+	// is valid, e.g. '!has(object.spec.hostNetwork) || !object.spec.hostNetwork'.
+	// The object is available as the 'object' variable.
+	Expression string
+
+	// Message is the violation reported when the expression evaluates to false.
+	// Defaults to a message containing the expression.
+	Message string
+}
+
+// CELValidator validates objects with CEL rules. The expressions
+// are compiled once and evaluated for each object.
+type CELValidator struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	prog cel.Program
+}
+
+// NewCELValidator compiles the given rules and returns a validator
+// evaluating them. It returns an error if any expression fails to
+// compile or does not evaluate to a boolean.
+func NewCELValidator(rules ...Rule) (*CELValidator, error) {
+	env, err := cel.NewEnv(
+		cel.HomogeneousAggregateLiterals(),
+		cel.EagerlyValidateDeclarations(true),
+		cel.DefaultUTCTimeZone(true),
+		cel.CrossTypeNumericComparisons(true),
+		cel.OptionalTypes(),
+		ext.Strings(),
+		ext.Sets(),
+		ext.Encoders(),
+		cel.Variable(ObjectVariable, cel.DynType),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	v := &CELValidator{}
+	for _, rule := range rules {
+		ast, issues := env.Compile(rule.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile the CEL expression '%s': %s", rule.Expression, issues.String())
+		}
+		if t := ast.OutputType(); t != cel.BoolType && t != cel.DynType {
+			return nil, fmt.Errorf("CEL expression '%s' output type mismatch: expected %s, got %s",
+				rule.Expression, cel.BoolType, t)
+		}
+
+		prog, err := env.Program(ast,
+			cel.EvalOptions(cel.OptOptimize),
+
+			// 100 is the kubernetes default:
+			// https://github.com/kubernetes/kubernetes/blob/3f26d005571dc5903e7cebae33ada67986bc40f3/staging/src/k8s.io/apiserver/pkg/apis/cel/config.go#L33-L35
+			cel.InterruptCheckFrequency(100),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CEL program for '%s': %w", rule.Expression, err)
+		}
+
+		if rule.Message == "" {
+			rule.Message = fmt.Sprintf("failed expression '%s'", rule.Expression)
+		}
+		v.rules = append(v.rules, compiledRule{Rule: rule, prog: prog})
+	}
+	return v, nil
+}
+
+// Validate evaluates the rules against the given object and returns the
+// messages of the rules which evaluate to false. An error is returned if
+// an expression fails to evaluate or does not evaluate to a boolean.
+func (v *CELValidator) Validate(ctx context.Context, object *unstructured.Unstructured) ([]string, error) {
+	data := map[string]any{ObjectVariable: object.UnstructuredContent()}
+
+	var violations []string
+	for _, rule := range v.rules {
+		val, _, err := rule.prog.ContextEval(ctx, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate the CEL expression '%s': %w", rule.Expression, err)
+		}
+		valid, ok := val.(types.Bool)
+		if !ok {
+			return nil, fmt.Errorf("failed to evaluate CEL expression as boolean: '%s'", rule.Expression)
+		}
+		if !valid {
+			violations = append(violations, rule.Message)
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewCELValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		wantErr string
+	}{
+		{
+			name: "valid expressions",
+			rules: []Rule{
+				{Expression: "object.kind != 'Secret'"},
+				{Expression: "has(object.metadata.labels) && 'app' in object.metadata.labels"},
+			},
+		},
+		{
+			name:    "syntax error",
+			rules:   []Rule{{Expression: "object.kind =="}},
+			wantErr: "failed to compile the CEL expression 'object.kind =='",
+		},
+		{
+			name:    "undeclared variable",
+			rules:   []Rule{{Expression: "obj.kind == 'Pod'"}},
+			wantErr: "undeclared reference to 'obj'",
+		},
+		{
+			name:    "non boolean output",
+			rules:   []Rule{{Expression: "object.kind + '/v1'"}},
+			wantErr: "CEL expression 'object.kind + '/v1'' output type mismatch: expected bool, got string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			v, err := NewCELValidator(tt.rules...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(v.rules).To(HaveLen(len(tt.rules)))
+		})
+	}
+}
+
+func TestCELValidator_Validate(t *testing.T) {
+	g := NewWithT(t)
+
+	v, err := NewCELValidator(
+		Rule{
+			Expression: "object.kind != 'Pod' || !has(object.spec.hostNetwork) || !object.spec.hostNetwork",
+			Message:    "pods must not use the host network",
+		},
+		Rule{
+			Expression: "object.kind != 'Pod' || object.spec.containers.all(c, c.image.startsWith('registry.example.com/'))",
+			Message:    "images must be pulled from registry.example.com",
+		},
+		Rule{
+			Expression: "object.metadata.namespace != 'kube-system'",
+		},
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	newPod := func(namespace string, hostNetwork bool, images ...string) *unstructured.Unstructured {
+		var containers []any
+		for _, image := range images {
+			containers = append(containers, map[string]any{"name": "app", "image": image})
+		}
+		spec := map[string]any{"containers": containers}
+		if hostNetwork {
+			spec["hostNetwork"] = true
+		}
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "test", "namespace": namespace},
+			"spec":       spec,
+		}}
+	}
+
+	tests := []struct {
+		name           string
+		object         *unstructured.Unstructured
+		wantViolations []string
+	}{
+		{
+			name:   "valid pod",
+			object: newPod("default", false, "registry.example.com/app:v1"),
+		},
+		{
+			name:           "host network",
+			object:         newPod("default", true, "registry.example.com/app:v1"),
+			wantViolations: []string{"pods must not use the host network"},
+		},
+		{
+			name:   "all violations",
+			object: newPod("kube-system", true, "registry.example.com/app:v1", "docker.io/app:v1"),
+			wantViolations: []string{
+				"pods must not use the host network",
+				"images must be pulled from registry.example.com",
+				"failed expression 'object.metadata.namespace != 'kube-system''",
+			},
+		},
+		{
+			name: "other kinds",
+			object: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "test", "namespace": "default"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			violations, err := v.Validate(context.Background(), tt.object)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(violations).To(Equal(tt.wantViolations))
+		})
+	}
+}
+
+func TestCELValidator_Validate_EvaluationError(t *testing.T) {
+	g := NewWithT(t)
+
+	v, err := NewCELValidator(Rule{Expression: "object.spec.replicas > 1"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = v.Validate(context.Background(), &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+	}})
+	g.Expect(err).To(MatchError(ContainSubstring("failed to evaluate the CEL expression 'object.spec.replicas > 1'")))
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+// ObjectValidator validates the objects before they are applied,
+// e.g. to enforce policies on the objects of a tenant.
+type ObjectValidator interface {
+	// Validate returns the policy violations of the given object, or an
+	// error if the object could not be validated.
+	Validate(ctx context.Context, object *unstructured.Unstructured) ([]string, error)
+}
+
+// validate runs the validators of the apply options against the desired
+// object. If the object violates any policy, a skipped entry is returned
+// with the violations as message.
+func (m *ResourceManager) validate(ctx context.Context, object *unstructured.Unstructured,
+	opts ApplyOptions) (*ChangeSetEntry, error) {
+	var violations []string
+	for _, v := range opts.Validators {
+		vs, err := v.Validate(ctx, object)
+		if err != nil {
+			return nil, fmt.Errorf("%s validation failed: %w", utils.FmtUnstructured(object), err)
+		}
+		violations = append(violations, vs...)
+	}
+	if len(violations) == 0 {
+		return nil, nil
+	}
+
+	entry := m.changeSetEntry(object, SkippedAction)
+	entry.Message = "policy violation: " + strings.Join(violations, "; ")
+	return entry, nil
+}
@@ -16,7 +16,10 @@ limitations under the License.
 
 package tar
 
-import "os"
+import (
+	"io/fs"
+	"os"
+)
 
 // Option configures the behavior of Tar and Untar. Options are
 // silently ignored by operations they do not apply to.
@@ -37,6 +40,10 @@ type tarOpts struct {
 	// filter is called for each entry during archiving or extraction.
 	// If it returns true, the entry is excluded.
 	filter func(path string, fi os.FileInfo) bool
+
+	// fsys is the file system Tar reads the directory from instead of
+	// the local disk.
+	fsys fs.FS
 }
 
 // WithMaxUntarSize sets the limit size for archives being decompressed by Untar.
@@ -65,14 +72,26 @@ func WithSkipGzip() Option {
 
 // WithFilter sets a predicate called for each entry during archiving
 // or extraction. Entries for which fn returns true are excluded. During
-// Tar the path is the absolute filesystem path; during Untar it is the
-// slash-separated name from the tar header.
+// Tar the path is the absolute filesystem path, or the path within the
+// file system set with WithFS; during Untar it is the slash-separated
+// name from the tar header.
 func WithFilter(fn func(path string, fi os.FileInfo) bool) Option {
 	return func(t *tarOpts) {
 		t.filter = fn
 	}
 }
 
+// WithFS makes Tar archive the directory from fsys instead of the local
+// disk. The directory is then a slash-separated path within fsys, such
+// as ".", and the filter gets the paths of the entries within fsys. The
+// regular files and directories for which fsys reports no permission
+// bits are archived with the 0644 and 0755 modes respectively.
+func WithFS(fsys fs.FS) Option {
+	return func(t *tarOpts) {
+		t.fsys = fsys
+	}
+}
+
 // applyOpts applies the given Option to t.
 func (t *tarOpts) applyOpts(opts ...Option) {
 	for _, opt := range opts {
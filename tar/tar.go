@@ -23,16 +23,28 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
+const (
+	// defaultFileMode is the mode of the regular files archived from
+	// a file system that does not report permission bits.
+	defaultFileMode fs.FileMode = 0o644
+	// defaultDirMode is the mode of the directories archived from
+	// a file system that does not report permission bits.
+	defaultDirMode fs.FileMode = 0o755
+)
+
 // Tar writes a tar archive of dir to w and returns the number of bytes
 // written.
 //
 // By default, the archive is gzip-compressed; use WithSkipGzip to write
 // a plain tar stream. Use WithFilter to exclude entries by path or
-// FileInfo. The directory tree is walked recursively; symlinks and
+// FileInfo, and WithFS to archive dir from a file system other than the
+// local disk. The directory tree is walked recursively; symlinks and
 // other non-regular, non-directory entries are silently skipped.
 // Headers are sanitized to produce reproducible archives: uid, gid,
 // user and group names, and all timestamps are zeroed.
@@ -40,19 +52,11 @@ func Tar(dir string, w io.Writer, opts ...Option) (int64, error) {
 	var o tarOpts
 	o.applyOpts(opts...)
 
-	absDir, err := filepath.Abs(dir)
+	fsys, root, absDir, err := o.sourceDir(dir)
 	if err != nil {
 		return 0, err
 	}
 
-	fi, err := os.Stat(absDir)
-	if err != nil {
-		return 0, fmt.Errorf("invalid dir path %s: %w", absDir, err)
-	}
-	if !fi.IsDir() {
-		return 0, fmt.Errorf("not a directory: %s", absDir)
-	}
-
 	cw := &countWriter{w: w}
 
 	var tw *tar.Writer
@@ -67,7 +71,7 @@ func Tar(dir string, w io.Writer, opts ...Option) (int64, error) {
 	}
 
 	buf := make([]byte, bufferSize)
-	walkErr := filepath.WalkDir(absDir, func(p string, d fs.DirEntry, err error) error {
+	walkErr := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -82,20 +86,29 @@ func Tar(dir string, w io.Writer, opts ...Option) (int64, error) {
 			return err
 		}
 
-		if o.filter != nil && o.filter(p, fi) {
+		// The filter gets the absolute path of the entries on disk.
+		fp := p
+		if absDir != "" {
+			fp = filepath.Join(absDir, filepath.FromSlash(p))
+		}
+		if o.filter != nil && o.filter(fp, fi) {
 			return nil
 		}
 
-		header, err := tar.FileInfoHeader(fi, p)
+		header, err := tar.FileInfoHeader(fi, "")
 		if err != nil {
 			return err
 		}
-
-		relPath, err := filepath.Rel(absDir, p)
-		if err != nil {
-			return err
+		header.Name = relPath(root, p)
+
+		// File systems like fstest.MapFS may not report permission bits.
+		if o.fsys != nil && header.Mode&0o777 == 0 {
+			if fi.IsDir() {
+				header.Mode |= int64(defaultDirMode)
+			} else {
+				header.Mode |= int64(defaultFileMode)
+			}
 		}
-		header.Name = filepath.ToSlash(relPath)
 
 		// Sanitize environment-specific data.
 		header.Gid = 0
@@ -114,7 +127,7 @@ func Tar(dir string, w io.Writer, opts ...Option) (int64, error) {
 			return nil
 		}
 
-		f, err := os.Open(p)
+		f, err := fsys.Open(p)
 		if err != nil {
 			return err
 		}
@@ -133,6 +146,50 @@ func Tar(dir string, w io.Writer, opts ...Option) (int64, error) {
 	return cw.n, walkErr
 }
 
+// sourceDir returns the file system to walk and the slash-separated root
+// of dir within it. Without WithFS, dir is a local path: the returned
+// file system is rooted at its absolute path, which is returned too.
+func (t *tarOpts) sourceDir(dir string) (fs.FS, string, string, error) {
+	if t.fsys != nil {
+		root := path.Clean(dir)
+		fi, err := fs.Stat(t.fsys, root)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("invalid dir path %s: %w", root, err)
+		}
+		if !fi.IsDir() {
+			return nil, "", "", fmt.Errorf("not a directory: %s", root)
+		}
+		return t.fsys, root, "", nil
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	fi, err := os.Stat(absDir)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("invalid dir path %s: %w", absDir, err)
+	}
+	if !fi.IsDir() {
+		return nil, "", "", fmt.Errorf("not a directory: %s", absDir)
+	}
+	return os.DirFS(absDir), ".", absDir, nil
+}
+
+// relPath returns the slash-separated path of p relative to root, where
+// p is root itself or one of its descendants.
+func relPath(root, p string) string {
+	switch {
+	case root == ".":
+		return p
+	case p == root:
+		return "."
+	default:
+		return strings.TrimPrefix(p, root+"/")
+	}
+}
+
 // countWriter wraps an io.Writer and counts the bytes written.
 type countWriter struct {
 	w io.Writer
@@ -21,9 +21,13 @@ import (
 	"bytes"
 	"compress/gzip"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"testing"
+	"testing/fstest"
 )
 
 func TestTar(t *testing.T) {
@@ -240,6 +244,92 @@ func TestTar_notADirectory(t *testing.T) {
 	}
 }
 
+func TestTar_withFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src":               {Mode: fs.ModeDir},
+		"src/file.txt":      {Data: []byte("hello")},
+		"src/skip.log":      {Data: []byte("skip")},
+		"src/sub":           {Mode: fs.ModeDir},
+		"src/sub/exec.sh":   {Data: []byte("exec"), Mode: 0o755},
+		"outside/other.txt": {Data: []byte("other")},
+	}
+
+	var filtered []string
+	filter := func(p string, fi os.FileInfo) bool {
+		filtered = append(filtered, p)
+		return path.Ext(p) == ".log"
+	}
+
+	var buf bytes.Buffer
+	if _, err := Tar("src", &buf, WithFS(fsys), WithFilter(filter), WithSkipGzip()); err != nil {
+		t.Fatalf("Tar() error: %v", err)
+	}
+
+	wantFiltered := []string{"src", "src/file.txt", "src/skip.log", "src/sub", "src/sub/exec.sh"}
+	if !slices.Equal(filtered, wantFiltered) {
+		t.Errorf("filter paths: got %v, want %v", filtered, wantFiltered)
+	}
+
+	wantModes := map[string]int64{
+		".":           int64(defaultDirMode),
+		"file.txt":    int64(defaultFileMode),
+		"sub":         int64(defaultDirMode),
+		"sub/exec.sh": 0o755,
+	}
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar.Next: %v", err)
+		}
+		mode, ok := wantModes[hdr.Name]
+		if !ok {
+			t.Errorf("unexpected entry %q", hdr.Name)
+			continue
+		}
+		if hdr.Mode&0o777 != mode {
+			t.Errorf("entry %q: mode %o, want %o", hdr.Name, hdr.Mode&0o777, mode)
+		}
+		delete(wantModes, hdr.Name)
+	}
+	for name := range wantModes {
+		t.Errorf("entry %q not found in archive", name)
+	}
+
+	if _, err := Tar("src/file.txt", io.Discard, WithFS(fsys)); err == nil {
+		t.Error("expected error for file path")
+	}
+	if _, err := Tar("nonexistent", io.Discard, WithFS(fsys)); err == nil {
+		t.Error("expected error for nonexistent dir")
+	}
+}
+
+func TestTar_withFSDirFS(t *testing.T) {
+	srcDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(srcDir, "subdir"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "subdir", "nested.txt"), []byte("world"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Archiving a directory from the disk or through os.DirFS produces
+	// the same archive.
+	var want, got bytes.Buffer
+	if _, err := Tar(srcDir, &want); err != nil {
+		t.Fatalf("Tar() error: %v", err)
+	}
+	if _, err := Tar(".", &got, WithFS(os.DirFS(srcDir))); err != nil {
+		t.Fatalf("Tar() error: %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Error("archives of the same directory differ")
+	}
+}
+
 // readTarEntries decompresses a tar.gz and returns a map of entry name to content.
 func readTarEntries(t *testing.T, r io.Reader) map[string]string {
 	t.Helper()